@@ -0,0 +1,164 @@
+package alertingstate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/server"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/folder/folderimpl"
+	"github.com/grafana/grafana/pkg/services/ngalert"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/statearchive"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// Export writes the state of alerting of the organizations to the file in the first argument.
+func Export(c utils.CommandLine, runner server.Runner) error {
+	path := c.Args().First()
+	if path == "" {
+		return errors.New("the path of the archive is required")
+	}
+	orgIDs, err := parseOrgIDs(c.StringSlice("org-id"))
+	if err != nil {
+		return err
+	}
+
+	archive, err := newService(runner).Export(context.Background(), orgIDs)
+	if err != nil {
+		return err
+	}
+	content, err := json.Marshal(archive)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, content, 0600); err != nil {
+		return fmt.Errorf("failed to write the archive: %w", err)
+	}
+
+	instances := 0
+	for _, org := range archive.Orgs {
+		instances += len(org.Instances)
+	}
+	logger.Infof("%s Exported the alerting state of %d organizations and %d alert instances to %s\n", color.GreenString("✔"), len(archive.Orgs), instances, path)
+	return nil
+}
+
+// Import imports the state of alerting in the archive in the first argument. Grafana must not be running, as it
+// would overwrite the imported state with its own.
+func Import(c utils.CommandLine, runner server.Runner) error {
+	path := c.Args().First()
+	if path == "" {
+		return errors.New("the path of the archive is required")
+	}
+	opts := statearchive.ImportOptions{}
+	var err error
+	if opts.OrgIDs, err = parseOrgIDMap(c.StringSlice("org-id-map")); err != nil {
+		return err
+	}
+	if opts.RuleUIDs, err = parseMap(c.StringSlice("rule-uid-map")); err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read the archive: %w", err)
+	}
+	var archive apimodels.AlertStateArchive
+	if err := json.Unmarshal(content, &archive); err != nil {
+		return fmt.Errorf("failed to parse the archive: %w", err)
+	}
+
+	result, err := newService(runner).Import(context.Background(), archive, opts)
+	if err != nil {
+		return err
+	}
+	logger.Infof("%s Imported the alerting state of %d organizations, %d alert rules and %d alert instances\n", color.GreenString("✔"), result.Orgs, result.Rules, result.Instances)
+	if result.SkippedInstances > 0 {
+		logger.Warnf("Skipped %d alert instances of organizations or alert rules that do not exist\n", result.SkippedInstances)
+	}
+	return nil
+}
+
+func newService(runner server.Runner) *statearchive.Service {
+	l := log.New("cli.alerting-state")
+	dbStore := store.DBstore{
+		Cfg:            runner.Cfg.UnifiedAlerting,
+		FeatureToggles: runner.Features,
+		SQLStore:       runner.SQLStore,
+		Logger:         l,
+	}
+	instanceStore, instanceReader := ngalert.InitInstanceStore(runner.SQLStore, l, runner.Features)
+	folders := folderReader{store: folderimpl.ProvideStore(runner.SQLStore)}
+	return statearchive.NewService(dbStore, dbStore, folders, instanceReader, instanceStore, kvstore.ProvideService(runner.SQLStore), l)
+}
+
+// folderReader reads the folders from the database, as the folder service is not available in the CLI.
+type folderReader struct {
+	store folder.Store
+}
+
+func (r folderReader) GetUserVisibleNamespaces(ctx context.Context, orgID int64, _ identity.Requester) (map[string]*folder.Folder, error) {
+	folders, err := r.store.GetFolders(ctx, folder.NewGetFoldersQuery(folder.GetFoldersQuery{OrgID: orgID, WithFullpath: true}))
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]*folder.Folder, len(folders))
+	for _, f := range folders {
+		result[f.UID] = f
+	}
+	return result, nil
+}
+
+func parseOrgIDs(values []string) ([]int64, error) {
+	result := make([]int64, 0, len(values))
+	for _, v := range values {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid organization ID %q", v)
+		}
+		result = append(result, id)
+	}
+	return result, nil
+}
+
+// parseMap parses values in the format from=to.
+func parseMap(values []string) (map[string]string, error) {
+	result := make(map[string]string, len(values))
+	for _, v := range values {
+		from, to, ok := strings.Cut(v, "=")
+		if !ok || from == "" || to == "" {
+			return nil, fmt.Errorf("invalid mapping %q, expected the format from=to", v)
+		}
+		result[from] = to
+	}
+	return result, nil
+}
+
+func parseOrgIDMap(values []string) (map[int64]int64, error) {
+	m, err := parseMap(values)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[int64]int64, len(m))
+	for from, to := range m {
+		ids, err := parseOrgIDs([]string{from, to})
+		if err != nil {
+			return nil, err
+		}
+		result[ids[0]] = ids[1]
+	}
+	return result, nil
+}
@@ -0,0 +1,38 @@
+package alertingstate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMappings(t *testing.T) {
+	t.Run("should parse organization IDs", func(t *testing.T) {
+		ids, err := parseOrgIDs([]string{"1", "3"})
+		require.NoError(t, err)
+		require.Equal(t, []int64{1, 3}, ids)
+
+		_, err = parseOrgIDs([]string{"main"})
+		require.Error(t, err)
+	})
+
+	t.Run("should parse rule UID mappings", func(t *testing.T) {
+		m, err := parseMap([]string{"old-a=new-a", "old-b=new-b"})
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"old-a": "new-a", "old-b": "new-b"}, m)
+
+		for _, invalid := range []string{"old", "=new", "old="} {
+			_, err = parseMap([]string{invalid})
+			require.Error(t, err, invalid)
+		}
+	})
+
+	t.Run("should parse organization ID mappings", func(t *testing.T) {
+		m, err := parseOrgIDMap([]string{"1=2", "3=1"})
+		require.NoError(t, err)
+		require.Equal(t, map[int64]int64{1: 2, 3: 1}, m)
+
+		_, err = parseOrgIDMap([]string{"1=main"})
+		require.Error(t, err)
+	})
+}
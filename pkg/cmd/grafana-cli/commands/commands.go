@@ -6,6 +6,7 @@ import (
 
 	"github.com/urfave/cli/v2"
 
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/commands/alertingstate"
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/commands/datamigrations"
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/commands/secretsmigrations"
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
@@ -158,6 +159,38 @@ var adminCommands = []*cli.Command{
 			},
 		},
 	},
+	{
+		Name:  "alerting-state",
+		Usage: "Exports and imports the state of alerts, silences and notification log to move them to another Grafana instance",
+		Subcommands: []*cli.Command{
+			{
+				Name:   "export",
+				Usage:  "export <archive file>. Exports the state of alerting to the archive file.",
+				Action: runRunnerCommand(alertingstate.Export),
+				Flags: []cli.Flag{
+					&cli.StringSliceFlag{
+						Name:  "org-id",
+						Usage: "ID of an organization to export. Can be repeated. All organizations are exported if not set.",
+					},
+				},
+			},
+			{
+				Name:   "import",
+				Usage:  "import <archive file>. Imports the state of alerting from the archive file. Grafana must be stopped while the state is imported.",
+				Action: runRunnerCommand(alertingstate.Import),
+				Flags: []cli.Flag{
+					&cli.StringSliceFlag{
+						Name:  "org-id-map",
+						Usage: "Maps an organization in the archive to an organization of this instance, in the format <archive org ID>=<org ID>. Can be repeated.",
+					},
+					&cli.StringSliceFlag{
+						Name:  "rule-uid-map",
+						Usage: "Maps an alert rule in the archive to an alert rule of this instance, in the format <archive rule UID>=<rule UID>. Can be repeated.",
+					},
+				},
+			},
+		},
+	},
 	{
 		Name:  "secrets-migration",
 		Usage: "Runs a script that migrates secrets in your database",
@@ -0,0 +1,122 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/expr/metrics"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+// AnomalyCommand is an expression command that detects anomalies in time series locally,
+// without the Machine Learning plugin (see MLNode).
+type AnomalyCommand struct {
+	VarToDetect string
+	Options     mathexp.AnomalyOptions
+	Output      AnomalyOutput
+	refID       string
+}
+
+// NewAnomalyCommand creates a new AnomalyCommand.
+func NewAnomalyCommand(refID, varToDetect string, opts mathexp.AnomalyOptions, output AnomalyOutput) (*AnomalyCommand, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	switch output {
+	case "":
+		output = AnomalyOutputVerdict
+	case AnomalyOutputVerdict, AnomalyOutputBands:
+	default:
+		return nil, fmt.Errorf("anomaly output %q is not supported. Supported only: [%s,%s]", output, AnomalyOutputVerdict, AnomalyOutputBands)
+	}
+	return &AnomalyCommand{
+		VarToDetect: varToDetect,
+		Options:     opts,
+		Output:      output,
+		refID:       refID,
+	}, nil
+}
+
+// UnmarshalAnomalyCommand creates an AnomalyCommand from Grafana's frontend query.
+func UnmarshalAnomalyCommand(rn *rawNode) (*AnomalyCommand, error) {
+	q := AnomalyQuery{}
+	if err := json.Unmarshal(rn.QueryRaw, &q); err != nil {
+		return nil, fmt.Errorf("failed to parse the anomaly command: %w", err)
+	}
+	varToDetect, err := getReferenceVar(q.Expression, rn.RefID)
+	if err != nil {
+		return nil, err
+	}
+	return newAnomalyCommandFromQuery(rn.RefID, varToDetect, q)
+}
+
+func newAnomalyCommandFromQuery(refID, varToDetect string, q AnomalyQuery) (*AnomalyCommand, error) {
+	opts := mathexp.AnomalyOptions{
+		Algorithm:   q.Algorithm,
+		Sensitivity: mathexp.DefaultAnomalySensitivity,
+	}
+	if q.Sensitivity != nil {
+		opts.Sensitivity = *q.Sensitivity
+	}
+	var err error
+	if q.Window != "" {
+		opts.Window, err = gtime.ParseDuration(q.Window)
+		if err != nil {
+			return nil, fmt.Errorf(`failed to parse anomaly "window" duration field %q: %w`, q.Window, err)
+		}
+	}
+	if q.Season != "" {
+		opts.Season, err = gtime.ParseDuration(q.Season)
+		if err != nil {
+			return nil, fmt.Errorf(`failed to parse anomaly "season" duration field %q: %w`, q.Season, err)
+		}
+	}
+	return NewAnomalyCommand(refID, varToDetect, opts, q.Output)
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (ac *AnomalyCommand) NeedsVars() []string {
+	return []string{ac.VarToDetect}
+}
+
+// Execute runs the command and returns the results or an error if the command
+// failed to execute.
+func (ac *AnomalyCommand) Execute(ctx context.Context, _ time.Time, vars mathexp.Vars, tracer tracing.Tracer, _ *metrics.ExprMetrics) (mathexp.Results, error) {
+	_, span := tracer.Start(ctx, "SSE.ExecuteAnomaly")
+	defer span.End()
+	span.SetAttributes(attribute.String("algorithm", string(ac.Options.Algorithm)), attribute.String("output", string(ac.Output)))
+
+	newRes := mathexp.Results{}
+	for _, val := range vars[ac.VarToDetect].Values {
+		switch v := val.(type) {
+		case mathexp.Series:
+			bands, err := v.DetectAnomalies(ac.refID, ac.Options)
+			if err != nil {
+				return newRes, err
+			}
+			if ac.Output == AnomalyOutputBands {
+				newRes.Values = append(newRes.Values, bands.Lower, bands.Upper, bands.Anomaly)
+				continue
+			}
+			num := mathexp.NewNumber(ac.refID, v.GetLabels().Copy())
+			num.SetValue(bands.Verdict())
+			newRes.Values = append(newRes.Values, num)
+		case mathexp.NoData:
+			newRes.Values = append(newRes.Values, v.New())
+		default:
+			return newRes, fmt.Errorf("can only detect anomalies in type series, got type %v", val.Type())
+		}
+	}
+	return newRes, nil
+}
+
+func (ac *AnomalyCommand) Type() string {
+	return TypeAnomaly.String()
+}
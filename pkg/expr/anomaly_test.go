@@ -0,0 +1,163 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+func TestUnmarshalAnomalyCommand(t *testing.T) {
+	cases := []struct {
+		description   string
+		query         string
+		expectedError string
+		expected      *AnomalyCommand
+	}{
+		{
+			description: "defaults sensitivity and output",
+			query:       `{"expression": "$A", "type": "anomaly", "algorithm": "zscore", "window": "1h"}`,
+			expected: &AnomalyCommand{
+				VarToDetect: "A",
+				Options: mathexp.AnomalyOptions{
+					Algorithm:   mathexp.AnomalyZScore,
+					Window:      time.Hour,
+					Sensitivity: mathexp.DefaultAnomalySensitivity,
+				},
+				Output: AnomalyOutputVerdict,
+				refID:  "B",
+			},
+		},
+		{
+			description: "holt_winters with bands",
+			query:       `{"expression": "A", "type": "anomaly", "algorithm": "holt_winters", "season": "1d", "sensitivity": 2, "output": "bands"}`,
+			expected: &AnomalyCommand{
+				VarToDetect: "A",
+				Options: mathexp.AnomalyOptions{
+					Algorithm:   mathexp.AnomalyHoltWinters,
+					Season:      24 * time.Hour,
+					Sensitivity: 2,
+				},
+				Output: AnomalyOutputBands,
+				refID:  "B",
+			},
+		},
+		{
+			description:   "fails without expression",
+			query:         `{"type": "anomaly", "algorithm": "zscore", "window": "1h"}`,
+			expectedError: "no variable specified",
+		},
+		{
+			description:   "fails without window",
+			query:         `{"expression": "A", "type": "anomaly", "algorithm": "mad"}`,
+			expectedError: "requires a positive window",
+		},
+		{
+			description:   "fails with invalid season",
+			query:         `{"expression": "A", "type": "anomaly", "algorithm": "holt_winters", "season": "daily"}`,
+			expectedError: "failed to parse anomaly \"season\"",
+		},
+		{
+			description:   "fails with unknown output",
+			query:         `{"expression": "A", "type": "anomaly", "algorithm": "zscore", "window": "1h", "output": "foo"}`,
+			expectedError: "anomaly output \"foo\" is not supported",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			var qmap = make(map[string]any)
+			require.NoError(t, json.Unmarshal([]byte(tc.query), &qmap))
+
+			cmd, err := UnmarshalAnomalyCommand(&rawNode{
+				RefID:    "B",
+				Query:    qmap,
+				QueryRaw: []byte(tc.query),
+			})
+			if tc.expectedError != "" {
+				require.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, cmd)
+			require.Equal(t, []string{"A"}, cmd.NeedsVars())
+		})
+	}
+}
+
+func TestAnomalyExecute(t *testing.T) {
+	series := func(labels data.Labels, values ...float64) mathexp.Series {
+		s := mathexp.NewSeries("A", labels, len(values))
+		for i, v := range values {
+			s.SetPoint(i, time.Unix(int64(i), 0), &v)
+		}
+		return s
+	}
+	number := func(labels data.Labels, value float64) mathexp.Number {
+		n := mathexp.NewNumber("B", labels)
+		n.SetValue(&value)
+		return n
+	}
+	opts := mathexp.AnomalyOptions{Algorithm: mathexp.AnomalyZScore, Window: time.Minute, Sensitivity: 3}
+	tracer := tracing.InitializeTracerForTest()
+
+	t.Run("verdict returns a number per series", func(t *testing.T) {
+		cmd, err := NewAnomalyCommand("B", "A", opts, "")
+		require.NoError(t, err)
+
+		results, err := cmd.Execute(context.Background(), time.Now(), mathexp.Vars{
+			"A": mathexp.Results{Values: mathexp.Values{
+				series(data.Labels{"host": "a"}, 10, 11, 10, 11, 50),
+				series(data.Labels{"host": "b"}, 10, 11, 10, 11, 10),
+			}},
+		}, tracer, nil)
+		require.NoError(t, err)
+		require.Equal(t, mathexp.Values{
+			number(data.Labels{"host": "a"}, 1),
+			number(data.Labels{"host": "b"}, 0),
+		}, results.Values)
+	})
+
+	t.Run("bands returns three series per series", func(t *testing.T) {
+		cmd, err := NewAnomalyCommand("B", "A", opts, AnomalyOutputBands)
+		require.NoError(t, err)
+
+		results, err := cmd.Execute(context.Background(), time.Now(), mathexp.Vars{
+			"A": mathexp.Results{Values: mathexp.Values{
+				series(data.Labels{"host": "a"}, 10, 11, 10, 11, 50),
+			}},
+		}, tracer, nil)
+		require.NoError(t, err)
+		require.Len(t, results.Values, 3)
+		for i, band := range []string{"lower", "upper", "anomaly"} {
+			require.Equal(t, data.Labels{"host": "a", mathexp.AnomalyBandLabel: band}, results.Values[i].GetLabels())
+		}
+	})
+
+	t.Run("no data is passed through", func(t *testing.T) {
+		cmd, err := NewAnomalyCommand("B", "A", opts, "")
+		require.NoError(t, err)
+
+		results, err := cmd.Execute(context.Background(), time.Now(), mathexp.Vars{
+			"A": mathexp.Results{Values: mathexp.Values{mathexp.NewNoData()}},
+		}, tracer, nil)
+		require.NoError(t, err)
+		require.True(t, results.IsNoData())
+	})
+
+	t.Run("fails on numbers", func(t *testing.T) {
+		cmd, err := NewAnomalyCommand("B", "A", opts, "")
+		require.NoError(t, err)
+
+		_, err = cmd.Execute(context.Background(), time.Now(), mathexp.Vars{
+			"A": mathexp.Results{Values: mathexp.Values{number(nil, 1)}},
+		}, tracer, nil)
+		require.ErrorContains(t, err, "can only detect anomalies in type series")
+	})
+}
@@ -4,6 +4,8 @@ import (
 	"math"
 	"sort"
 
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
 	"github.com/grafana/grafana/pkg/expr/mathexp"
 )
 
@@ -15,8 +17,11 @@ func (cr reducer) ValidReduceFunc() bool {
 		return true
 	case "diff", "diff_abs", "percent_diff", "percent_diff_abs", "count_non_null":
 		return true
+	case "first", "stddev", "range", "delta", "rate":
+		return true
 	}
-	return false
+	_, ok := mathexp.ParsePercentile(mathexp.ReducerID(cr))
+	return ok
 }
 
 //nolint:gocyclo
@@ -144,6 +149,27 @@ func (cr reducer) Reduce(series mathexp.Series) mathexp.Number {
 		if value > 0 {
 			allNull = false
 		}
+	case "first":
+		for i := 0; i < ff.Len(); i++ {
+			f := ff.GetValue(i)
+			if !nilOrNaN(f) {
+				value = *f
+				allNull = false
+				break
+			}
+		}
+	case "stddev":
+		allNull, value = reduceNonNull(ff, mathexp.StdDev)
+	case "range":
+		allNull, value = reduceNonNull(ff, mathexp.Range)
+	case "delta":
+		allNull, value = reduceNonNull(ff, mathexp.Delta)
+	case "rate":
+		allNull, value = calculateRate(series)
+	default:
+		if p, ok := mathexp.ParsePercentile(mathexp.ReducerID(cr)); ok {
+			allNull, value = reduceNonNull(ff, mathexp.Percentile(p))
+		}
 	}
 
 	if allNull {
@@ -182,6 +208,44 @@ func calculateDiff(ff mathexp.Float64Field, allNull bool, value float64, fn func
 	return allNull, value
 }
 
+// reduceNonNull applies fn to the values of ff that are neither null nor NaN.
+func reduceNonNull(ff mathexp.Float64Field, fn mathexp.ReducerFunc) (bool, float64) {
+	values := make([]float64, 0, ff.Len())
+	for i := 0; i < ff.Len(); i++ {
+		f := ff.GetValue(i)
+		if nilOrNaN(f) {
+			continue
+		}
+		values = append(values, *f)
+	}
+	if len(values) == 0 {
+		return true, 0
+	}
+	nonNull := mathexp.Float64Field(*data.NewField("", nil, values))
+	return false, *fn(&nonNull)
+}
+
+// calculateRate returns the per-second increase between the oldest and the newest non-null points,
+// taking counter resets into account. At least two non-null points are required.
+func calculateRate(series mathexp.Series) (bool, float64) {
+	nonNull := mathexp.NewSeries("", nil, 0)
+	for i := 0; i < series.Len(); i++ {
+		t, f := series.GetPoint(i)
+		if nilOrNaN(f) {
+			continue
+		}
+		nonNull.AppendPoint(t, f)
+	}
+	if nonNull.Len() < 2 {
+		return true, 0
+	}
+	rate := mathexp.Rate(nonNull)
+	if nilOrNaN(rate) {
+		return true, 0
+	}
+	return false, *rate
+}
+
 func nilOrNaN(f *float64) bool {
 	return f == nil || math.IsNaN(*f)
 }
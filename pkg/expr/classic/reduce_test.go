@@ -115,6 +115,60 @@ func TestReducer(t *testing.T) {
 			inputSeries:    newSeries(nil, nil),
 			expectedNumber: newNumber(nil),
 		},
+		{
+			name:           "first should ignore null values",
+			reducer:        reducer("first"),
+			inputSeries:    newSeries(nil, util.Pointer(2.0), util.Pointer(3.0)),
+			expectedNumber: newNumber(util.Pointer(2.0)),
+		},
+		{
+			name:           "stddev",
+			reducer:        reducer("stddev"),
+			inputSeries:    newSeries(util.Pointer(1.0), nil, util.Pointer(2.0)),
+			expectedNumber: newNumber(util.Pointer(0.5)),
+		},
+		{
+			name:           "stddev with only nulls",
+			reducer:        reducer("stddev"),
+			inputSeries:    newSeries(nil, nil),
+			expectedNumber: newNumber(nil),
+		},
+		{
+			name:           "range",
+			reducer:        reducer("range"),
+			inputSeries:    newSeries(util.Pointer(3.0), nil, util.Pointer(-1.0), util.Pointer(2.0)),
+			expectedNumber: newNumber(util.Pointer(4.0)),
+		},
+		{
+			name:           "delta with counter reset",
+			reducer:        reducer("delta"),
+			inputSeries:    newSeries(util.Pointer(1.0), util.Pointer(5.0), nil, util.Pointer(2.0), util.Pointer(4.0)),
+			expectedNumber: newNumber(util.Pointer(8.0)),
+		},
+		{
+			name:           "rate with counter reset",
+			reducer:        reducer("rate"),
+			inputSeries:    newSeries(util.Pointer(1.0), util.Pointer(5.0), nil, util.Pointer(2.0), util.Pointer(4.0)),
+			expectedNumber: newNumber(util.Pointer(2.0)),
+		},
+		{
+			name:           "rate with a single value",
+			reducer:        reducer("rate"),
+			inputSeries:    newSeries(nil, util.Pointer(1.0)),
+			expectedNumber: newNumber(nil),
+		},
+		{
+			name:           "p50",
+			reducer:        reducer("p50"),
+			inputSeries:    newSeries(util.Pointer(4.0), nil, util.Pointer(1.0), util.Pointer(2.0)),
+			expectedNumber: newNumber(util.Pointer(2.0)),
+		},
+		{
+			name:           "p99.5 with only nulls",
+			reducer:        reducer("p99.5"),
+			inputSeries:    newSeries(nil, nil),
+			expectedNumber: newNumber(nil),
+		},
 	}
 
 	for _, tt := range tests {
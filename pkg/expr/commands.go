@@ -92,7 +92,7 @@ type ReduceCommand struct {
 
 // NewReduceCommand creates a new ReduceCMD.
 func NewReduceCommand(refID string, reducer mathexp.ReducerID, varToReduce string, mapper mathexp.ReduceMapper) (*ReduceCommand, error) {
-	_, err := mathexp.GetReduceFunc(reducer)
+	_, err := mathexp.GetSeriesReduceFunc(reducer)
 	if err != nil {
 		return nil, err
 	}
@@ -126,6 +126,21 @@ func UnmarshalReduceCommand(rn *rawNode) (*ReduceCommand, error) {
 		return nil, fmt.Errorf("expected reducer to be a string, got %T", rawReducer)
 	}
 	redFunc := mathexp.ReducerID(strings.ToLower(redString))
+	if redFunc == mathexp.ReducerPercentile {
+		rawPercentile, ok := rn.Query["percentile"]
+		if !ok {
+			return nil, errors.New("field percentile must be specified when reducer is 'percentile'")
+		}
+		percentile, ok := rawPercentile.(float64)
+		if !ok {
+			return nil, fmt.Errorf("field percentile must be a number, got %T", rawPercentile)
+		}
+		var err error
+		redFunc, err = mathexp.PercentileReducer(percentile)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	var mapper mathexp.ReduceMapper = nil
 	settings, ok := rn.Query["settings"]
@@ -218,22 +233,77 @@ type ResampleCommand struct {
 	VarToResample string
 	Downsampler   mathexp.ReducerID
 	Upsampler     mathexp.Upsampler
+	Alignment     mathexp.ResampleAlignment
+	Location      *time.Location
 	TimeRange     TimeRange
 	refID         string
 }
 
+// ResampleWindowAuto is the resample window that uses the interval of the query.
+const ResampleWindowAuto = "auto"
+
+// ResampleCommandOptions are the optional settings of a ResampleCommand.
+type ResampleCommandOptions struct {
+	// QueryInterval is used as the window when the window is ResampleWindowAuto.
+	QueryInterval time.Duration
+	Alignment     mathexp.ResampleAlignment
+	// Timezone is the IANA name of the timezone of the calendar boundaries. Defaults to UTC.
+	Timezone string
+}
+
 // NewResampleCommand creates a new ResampleCMD.
 func NewResampleCommand(refID, rawWindow, varToResample string, downsampler mathexp.ReducerID, upsampler mathexp.Upsampler, tr TimeRange) (*ResampleCommand, error) {
-	// TODO: validate reducer here, before execution
-	window, err := gtime.ParseDuration(rawWindow)
-	if err != nil {
-		return nil, fmt.Errorf(`failed to parse resample "window" duration field %q: %w`, window, err)
+	return NewResampleCommandWithOptions(refID, rawWindow, varToResample, downsampler, upsampler, tr, ResampleCommandOptions{})
+}
+
+// NewResampleCommandWithOptions creates a new ResampleCMD with the optional settings.
+func NewResampleCommandWithOptions(refID, rawWindow, varToResample string, downsampler mathexp.ReducerID, upsampler mathexp.Upsampler, tr TimeRange, opts ResampleCommandOptions) (*ResampleCommand, error) {
+	var window time.Duration
+	if rawWindow == ResampleWindowAuto {
+		if opts.QueryInterval <= 0 {
+			return nil, fmt.Errorf(`resample "window" is %q but the query has no interval`, ResampleWindowAuto)
+		}
+		window = opts.QueryInterval
+	} else {
+		var err error
+		window, err = gtime.ParseDuration(rawWindow)
+		if err != nil {
+			return nil, fmt.Errorf(`failed to parse resample "window" duration field %q: %w`, rawWindow, err)
+		}
+	}
+	if window <= 0 {
+		return nil, fmt.Errorf(`resample "window" must be positive, got %q`, rawWindow)
+	}
+	if downsampler != "" {
+		if _, err := mathexp.GetReduceFunc(downsampler); err != nil {
+			return nil, fmt.Errorf("invalid resample downsampler: %w", err)
+		}
+	}
+	var loc *time.Location
+	switch opts.Alignment {
+	case mathexp.ResampleAlignStart:
+		if opts.Timezone != "" {
+			return nil, fmt.Errorf("resample timezone requires alignment %q", mathexp.ResampleAlignCalendar)
+		}
+	case mathexp.ResampleAlignCalendar:
+		loc = time.UTC
+		if opts.Timezone != "" {
+			var err error
+			loc, err = time.LoadLocation(opts.Timezone)
+			if err != nil {
+				return nil, fmt.Errorf("invalid resample timezone %q: %w", opts.Timezone, err)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("resample alignment %q is not supported. Supported only: [%q,%s]", opts.Alignment, mathexp.ResampleAlignStart, mathexp.ResampleAlignCalendar)
 	}
 	return &ResampleCommand{
 		Window:        window,
 		VarToResample: varToResample,
 		Downsampler:   downsampler,
 		Upsampler:     upsampler,
+		Alignment:     opts.Alignment,
+		Location:      loc,
 		TimeRange:     tr,
 		refID:         refID,
 	}, nil
@@ -282,11 +352,28 @@ func UnmarshalResampleCommand(rn *rawNode) (*ResampleCommand, error) {
 		return nil, fmt.Errorf("expected resample downsampler to be a string, got type %T", upsampler)
 	}
 
-	return NewResampleCommand(rn.RefID, window,
+	opts := ResampleCommandOptions{QueryInterval: rn.Interval}
+	if rawAlignment, ok := rn.Query["alignment"]; ok {
+		alignment, ok := rawAlignment.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected resample alignment to be a string, got type %T", rawAlignment)
+		}
+		opts.Alignment = mathexp.ResampleAlignment(alignment)
+	}
+	if rawTimezone, ok := rn.Query["timezone"]; ok {
+		timezone, ok := rawTimezone.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected resample timezone to be a string, got type %T", rawTimezone)
+		}
+		opts.Timezone = timezone
+	}
+
+	return NewResampleCommandWithOptions(rn.RefID, window,
 		varToResample,
 		mathexp.ReducerID(downsampler),
 		mathexp.Upsampler(upsampler),
-		rn.TimeRange)
+		rn.TimeRange,
+		opts)
 }
 
 // NeedsVars returns the variable names (refIds) that are dependencies
@@ -308,7 +395,13 @@ func (gr *ResampleCommand) Execute(ctx context.Context, now time.Time, vars math
 		}
 		switch v := val.(type) {
 		case mathexp.Series:
-			num, err := v.Resample(gr.refID, gr.Window, gr.Downsampler, gr.Upsampler, timeRange.From, timeRange.To)
+			num, err := v.ResampleWithOptions(gr.refID, mathexp.ResampleOptions{
+				Interval:    gr.Window,
+				Downsampler: gr.Downsampler,
+				Upsampler:   gr.Upsampler,
+				Alignment:   gr.Alignment,
+				Location:    gr.Location,
+			}, timeRange.From, timeRange.To)
 			if err != nil {
 				return newRes, err
 			}
@@ -345,6 +438,10 @@ const (
 	TypeThreshold
 	// TypeSQL is the CMDType for running SQL expressions
 	TypeSQL
+	// TypeAnomaly is the CMDType for detecting anomalies in time series
+	TypeAnomaly
+	// TypeForecast is the CMDType for predicting future values of time series
+	TypeForecast
 )
 
 func (gt CommandType) String() string {
@@ -361,6 +458,10 @@ func (gt CommandType) String() string {
 		return "threshold"
 	case TypeSQL:
 		return "sql"
+	case TypeAnomaly:
+		return "anomaly"
+	case TypeForecast:
+		return "forecast"
 	default:
 		return "unknown"
 	}
@@ -381,6 +482,10 @@ func ParseCommandType(s string) (CommandType, error) {
 		return TypeThreshold, nil
 	case "sql":
 		return TypeSQL, nil
+	case "anomaly":
+		return TypeAnomaly, nil
+	case "forecast":
+		return TypeForecast, nil
 	default:
 		return TypeUnknown, fmt.Errorf("'%v' is not a recognized expression type", s)
 	}
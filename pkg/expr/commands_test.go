@@ -99,6 +99,61 @@ func Test_UnmarshalReduceCommand_Settings(t *testing.T) {
 	}
 }
 
+func Test_UnmarshalReduceCommand_Percentile(t *testing.T) {
+	var tests = []struct {
+		name            string
+		query           string
+		isError         bool
+		expectedReducer mathexp.ReducerID
+	}{
+		{
+			name:            "percentile with an argument",
+			query:           `{ "expression" : "$A", "reducer": "percentile", "percentile": 95 }`,
+			expectedReducer: "p95",
+		},
+		{
+			name:            "percentile shorthand",
+			query:           `{ "expression" : "$A", "reducer": "p99.9" }`,
+			expectedReducer: "p99.9",
+		},
+		{
+			name:    "error when percentile is missing",
+			query:   `{ "expression" : "$A", "reducer": "percentile" }`,
+			isError: true,
+		},
+		{
+			name:    "error when percentile is not a number",
+			query:   `{ "expression" : "$A", "reducer": "percentile", "percentile": "95" }`,
+			isError: true,
+		},
+		{
+			name:    "error when percentile is out of range",
+			query:   `{ "expression" : "$A", "reducer": "percentile", "percentile": 101 }`,
+			isError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var qmap = make(map[string]any)
+			require.NoError(t, json.Unmarshal([]byte(test.query), &qmap))
+
+			cmd, err := UnmarshalReduceCommand(&rawNode{
+				RefID: "A",
+				Query: qmap,
+			})
+
+			if test.isError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expectedReducer, cmd.Reducer)
+		})
+	}
+}
+
 func TestReduceExecute(t *testing.T) {
 	varToReduce := util.GenerateShortUID()
 
@@ -273,3 +328,51 @@ func TestResampleCommand_Execute(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestNewResampleCommandWithOptions(t *testing.T) {
+	tr := RelativeTimeRange{From: -10 * time.Second, To: 0}
+
+	t.Run("auto window uses the interval of the query", func(t *testing.T) {
+		cmd, err := NewResampleCommandWithOptions("B", ResampleWindowAuto, "A", mathexp.ReducerSum, mathexp.UpsamplerLinear, tr, ResampleCommandOptions{
+			QueryInterval: 15 * time.Second,
+		})
+		require.NoError(t, err)
+		require.Equal(t, 15*time.Second, cmd.Window)
+	})
+
+	t.Run("auto window fails without query interval", func(t *testing.T) {
+		_, err := NewResampleCommandWithOptions("B", ResampleWindowAuto, "A", mathexp.ReducerSum, mathexp.UpsamplerPad, tr, ResampleCommandOptions{})
+		require.ErrorContains(t, err, "the query has no interval")
+	})
+
+	t.Run("calendar alignment loads the timezone", func(t *testing.T) {
+		cmd, err := NewResampleCommandWithOptions("B", "1d", "A", mathexp.ReducerSum, mathexp.UpsamplerFillNA, tr, ResampleCommandOptions{
+			Alignment: mathexp.ResampleAlignCalendar,
+			Timezone:  "Europe/Berlin",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "Europe/Berlin", cmd.Location.String())
+
+		cmd, err = NewResampleCommandWithOptions("B", "1d", "A", mathexp.ReducerSum, mathexp.UpsamplerFillNA, tr, ResampleCommandOptions{
+			Alignment: mathexp.ResampleAlignCalendar,
+		})
+		require.NoError(t, err)
+		require.Equal(t, time.UTC, cmd.Location)
+	})
+
+	t.Run("fails with invalid options", func(t *testing.T) {
+		_, err := NewResampleCommandWithOptions("B", "1d", "A", mathexp.ReducerSum, mathexp.UpsamplerFillNA, tr, ResampleCommandOptions{
+			Alignment: mathexp.ResampleAlignCalendar,
+			Timezone:  "Mars/Olympus_Mons",
+		})
+		require.ErrorContains(t, err, "invalid resample timezone")
+
+		_, err = NewResampleCommandWithOptions("B", "1d", "A", mathexp.ReducerSum, mathexp.UpsamplerFillNA, tr, ResampleCommandOptions{
+			Timezone: "Europe/Berlin",
+		})
+		require.ErrorContains(t, err, "requires alignment")
+
+		_, err = NewResampleCommandWithOptions("B", "1d", "A", "foo", mathexp.UpsamplerFillNA, tr, ResampleCommandOptions{})
+		require.ErrorContains(t, err, "invalid resample downsampler")
+	})
+}
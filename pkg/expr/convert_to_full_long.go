@@ -1,6 +1,7 @@
 package expr
 
 import (
+	"encoding/json"
 	"fmt"
 	"sort"
 	"time"
@@ -12,6 +13,9 @@ const (
 	SQLMetricFieldName  = "__metric_name__"
 	SQLValueFieldName   = "__value__"
 	SQLDisplayFieldName = "__display_name__"
+	// SQLLabelsFieldName is the column with all labels of a row of a full long frame as a JSON object,
+	// so queries can join or group on the whole label set.
+	SQLLabelsFieldName = "__labels__"
 
 	// These are not types in the SDK or dataplane contract yet.
 	numericFullLongType    = "numeric_full_long"
@@ -398,3 +402,59 @@ func convertNumericMultiToNumericWide(frames data.Frames) data.Frames {
 	out.Meta = &data.FrameMeta{Type: data.FrameTypeNumericWide}
 	return data.Frames{out}
 }
+
+// isFullLong returns true if the frame was produced by ConvertToFullLong.
+func isFullLong(frame *data.Frame) bool {
+	if frame == nil || frame.Meta == nil {
+		return false
+	}
+	return frame.Meta.Type == numericFullLongType || frame.Meta.Type == timeseriesFullLongType
+}
+
+// addLabelsField returns a copy of a full long frame with a SQLLabelsFieldName column
+// that holds the labels of every row as a JSON object with sorted keys. The label columns
+// are the string columns other than the metric name and display name. Null labels are omitted.
+// Frames that are not full long, or already have the column, are returned as is.
+func addLabelsField(frame *data.Frame) (*data.Frame, error) {
+	if !isFullLong(frame) {
+		return frame, nil
+	}
+	labelFields := make([]*data.Field, 0, len(frame.Fields))
+	for _, f := range frame.Fields {
+		switch f.Name {
+		case SQLLabelsFieldName:
+			return frame, nil
+		case SQLMetricFieldName, SQLDisplayFieldName:
+			continue
+		}
+		if f.Type() == data.FieldTypeString || f.Type() == data.FieldTypeNullableString {
+			labelFields = append(labelFields, f)
+		}
+	}
+
+	rows := frame.Rows()
+	values := make([]string, rows)
+	for i := 0; i < rows; i++ {
+		labels := make(map[string]string, len(labelFields))
+		for _, f := range labelFields {
+			switch v := f.At(i).(type) {
+			case *string:
+				if v != nil {
+					labels[f.Name] = *v
+				}
+			case string:
+				labels[f.Name] = v
+			}
+		}
+		b, err := json.Marshal(labels) // map keys are sorted
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal labels of row %d: %w", i, err)
+		}
+		values[i] = string(b)
+	}
+
+	out := data.NewFrame(frame.Name, append(append([]*data.Field{}, frame.Fields...), data.NewField(SQLLabelsFieldName, nil, values))...)
+	out.RefID = frame.RefID
+	out.Meta = frame.Meta
+	return out, nil
+}
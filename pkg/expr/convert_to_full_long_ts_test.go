@@ -371,3 +371,39 @@ func TestConvertTimeSeriesMultiToFullLongWithDisplayName(t *testing.T) {
 		}
 	})
 }
+
+func TestAddLabelsField(t *testing.T) {
+	t.Run("adds sorted labels to full long frames", func(t *testing.T) {
+		input := data.NewFrame("",
+			data.NewField("time", nil, []time.Time{time.Unix(0, 0), time.Unix(0, 0)}),
+			data.NewField(SQLValueFieldName, nil, []*float64{fp(1.0), fp(2.0)}),
+			data.NewField(SQLMetricFieldName, nil, []string{"cpu", "cpu"}),
+			data.NewField(SQLDisplayFieldName, nil, []*string{sp("CPU A"), sp("CPU B")}),
+			data.NewField("host", nil, []*string{sp("a"), sp("b")}),
+			data.NewField("env", nil, []*string{sp("prod"), nil}),
+		)
+		input.Meta = &data.FrameMeta{Type: timeseriesFullLongType}
+
+		output, err := addLabelsField(input)
+		require.NoError(t, err)
+		require.Len(t, input.Fields, 6)
+		require.Len(t, output.Fields, 7)
+		require.Equal(t, input.Meta, output.Meta)
+
+		labels := output.Fields[6]
+		require.Equal(t, SQLLabelsFieldName, labels.Name)
+		require.Equal(t, `{"env":"prod","host":"a"}`, labels.At(0))
+		require.Equal(t, `{"host":"b"}`, labels.At(1))
+	})
+
+	t.Run("leaves other frames unchanged", func(t *testing.T) {
+		input := data.NewFrame("",
+			data.NewField("host", nil, []string{"a"}),
+			data.NewField("value", nil, []float64{1}),
+		)
+
+		output, err := addLabelsField(input)
+		require.NoError(t, err)
+		require.Same(t, input, output)
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+	"github.com/grafana/grafana/pkg/expr/sql"
 )
 
 var ErrSeriesMustBeWide = errors.New("input data must be a wide series")
@@ -123,3 +124,25 @@ func truncateExamples(examples []string, limit int) []string {
 	truncated = append(truncated, fmt.Sprintf("... and %d more", len(examples)-limit))
 	return truncated
 }
+
+var sqlLimitExceededStr = "SQL expression [{{ .Public.refId }}]: {{ .Public.limit }} exceeds limit of {{ .Public.max }}"
+
+var SQLLimitExceededError = errutil.NewBase(
+	errutil.StatusBadRequest, "sse.sqlLimitExceeded").MustTemplate(
+	sqlLimitExceededStr,
+	errutil.WithPublic(sqlLimitExceededStr))
+
+// MakeSQLLimitExceededError returns an error for a SQL expression that exceeded one of its resource limits.
+// maxValue is the configured limit, e.g. the number of rows or the timeout.
+func MakeSQLLimitExceededError(refID string, limit sql.Limit, maxValue any, err error) error {
+	data := errutil.TemplateData{
+		Public: map[string]any{
+			"refId": refID,
+			"limit": string(limit),
+			"max":   maxValue,
+		},
+		Error: err,
+	}
+
+	return SQLLimitExceededError.Build(data)
+}
@@ -0,0 +1,131 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/expr/metrics"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+// ForecastCommand is an expression command that predicts the future values of time series,
+// similar to predict_linear in Prometheus but for any data source.
+type ForecastCommand struct {
+	VarToForecast string
+	Options       mathexp.ForecastOptions
+	Output        ForecastOutput
+	refID         string
+}
+
+// NewForecastCommand creates a new ForecastCommand.
+func NewForecastCommand(refID, varToForecast string, opts mathexp.ForecastOptions, output ForecastOutput) (*ForecastCommand, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	switch output {
+	case "":
+		output = ForecastOutputValue
+	case ForecastOutputValue, ForecastOutputSeries:
+	default:
+		return nil, fmt.Errorf("forecast output %q is not supported. Supported only: [%s,%s]", output, ForecastOutputValue, ForecastOutputSeries)
+	}
+	return &ForecastCommand{
+		VarToForecast: varToForecast,
+		Options:       opts,
+		Output:        output,
+		refID:         refID,
+	}, nil
+}
+
+// UnmarshalForecastCommand creates a ForecastCommand from Grafana's frontend query.
+func UnmarshalForecastCommand(rn *rawNode) (*ForecastCommand, error) {
+	q := ForecastQuery{}
+	if err := json.Unmarshal(rn.QueryRaw, &q); err != nil {
+		return nil, fmt.Errorf("failed to parse the forecast command: %w", err)
+	}
+	varToForecast, err := getReferenceVar(q.Expression, rn.RefID)
+	if err != nil {
+		return nil, err
+	}
+	return newForecastCommandFromQuery(rn.RefID, varToForecast, q)
+}
+
+func newForecastCommandFromQuery(refID, varToForecast string, q ForecastQuery) (*ForecastCommand, error) {
+	opts := mathexp.ForecastOptions{
+		Model: q.Model,
+	}
+	if opts.Model == "" {
+		opts.Model = mathexp.ForecastLinear
+	}
+	var err error
+	if q.Horizon != "" {
+		opts.Horizon, err = gtime.ParseDuration(q.Horizon)
+		if err != nil {
+			return nil, fmt.Errorf(`failed to parse forecast "horizon" duration field %q: %w`, q.Horizon, err)
+		}
+	}
+	if q.Window != "" {
+		opts.Window, err = gtime.ParseDuration(q.Window)
+		if err != nil {
+			return nil, fmt.Errorf(`failed to parse forecast "window" duration field %q: %w`, q.Window, err)
+		}
+	}
+	if q.Season != "" {
+		opts.Season, err = gtime.ParseDuration(q.Season)
+		if err != nil {
+			return nil, fmt.Errorf(`failed to parse forecast "season" duration field %q: %w`, q.Season, err)
+		}
+	}
+	return NewForecastCommand(refID, varToForecast, opts, q.Output)
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (fc *ForecastCommand) NeedsVars() []string {
+	return []string{fc.VarToForecast}
+}
+
+// Execute runs the command and returns the results or an error if the command
+// failed to execute.
+func (fc *ForecastCommand) Execute(ctx context.Context, _ time.Time, vars mathexp.Vars, tracer tracing.Tracer, _ *metrics.ExprMetrics) (mathexp.Results, error) {
+	_, span := tracer.Start(ctx, "SSE.ExecuteForecast")
+	defer span.End()
+	span.SetAttributes(attribute.String("model", string(fc.Options.Model)), attribute.String("output", string(fc.Output)))
+
+	newRes := mathexp.Results{}
+	for _, val := range vars[fc.VarToForecast].Values {
+		switch v := val.(type) {
+		case mathexp.Series:
+			if fc.Output == ForecastOutputSeries {
+				s, err := v.ForecastSeries(fc.refID, fc.Options)
+				if err != nil {
+					return newRes, err
+				}
+				newRes.Values = append(newRes.Values, s)
+				continue
+			}
+			f, err := v.Forecast(fc.Options)
+			if err != nil {
+				return newRes, err
+			}
+			num := mathexp.NewNumber(fc.refID, v.GetLabels().Copy())
+			num.SetValue(f)
+			newRes.Values = append(newRes.Values, num)
+		case mathexp.NoData:
+			newRes.Values = append(newRes.Values, v.New())
+		default:
+			return newRes, fmt.Errorf("can only forecast type series, got type %v", val.Type())
+		}
+	}
+	return newRes, nil
+}
+
+func (fc *ForecastCommand) Type() string {
+	return TypeForecast.String()
+}
@@ -0,0 +1,184 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+func TestUnmarshalForecastCommand(t *testing.T) {
+	cases := []struct {
+		description   string
+		query         string
+		expectedError string
+		expected      *ForecastCommand
+	}{
+		{
+			description: "defaults model and output",
+			query:       `{"expression": "$A", "type": "forecast", "horizon": "4h"}`,
+			expected: &ForecastCommand{
+				VarToForecast: "A",
+				Options: mathexp.ForecastOptions{
+					Model:   mathexp.ForecastLinear,
+					Horizon: 4 * time.Hour,
+				},
+				Output: ForecastOutputValue,
+				refID:  "B",
+			},
+		},
+		{
+			description: "seasonal with series output",
+			query:       `{"expression": "A", "type": "forecast", "model": "seasonal", "horizon": "1d", "window": "1w", "season": "1d", "output": "series"}`,
+			expected: &ForecastCommand{
+				VarToForecast: "A",
+				Options: mathexp.ForecastOptions{
+					Model:   mathexp.ForecastSeasonal,
+					Horizon: 24 * time.Hour,
+					Window:  7 * 24 * time.Hour,
+					Season:  24 * time.Hour,
+				},
+				Output: ForecastOutputSeries,
+				refID:  "B",
+			},
+		},
+		{
+			description:   "fails without expression",
+			query:         `{"type": "forecast", "horizon": "4h"}`,
+			expectedError: "no variable specified",
+		},
+		{
+			description:   "fails without horizon",
+			query:         `{"expression": "A", "type": "forecast"}`,
+			expectedError: "requires a positive horizon",
+		},
+		{
+			description:   "fails with invalid window",
+			query:         `{"expression": "A", "type": "forecast", "horizon": "4h", "window": "long"}`,
+			expectedError: "failed to parse forecast \"window\"",
+		},
+		{
+			description:   "fails with unknown output",
+			query:         `{"expression": "A", "type": "forecast", "horizon": "4h", "output": "foo"}`,
+			expectedError: "forecast output \"foo\" is not supported",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			var qmap = make(map[string]any)
+			require.NoError(t, json.Unmarshal([]byte(tc.query), &qmap))
+
+			cmd, err := UnmarshalForecastCommand(&rawNode{
+				RefID:    "B",
+				Query:    qmap,
+				QueryRaw: []byte(tc.query),
+			})
+			if tc.expectedError != "" {
+				require.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, cmd)
+			require.Equal(t, []string{"A"}, cmd.NeedsVars())
+		})
+	}
+}
+
+func TestForecastExecute(t *testing.T) {
+	series := func(labels data.Labels, values ...float64) mathexp.Series {
+		s := mathexp.NewSeries("A", labels, len(values))
+		for i, v := range values {
+			s.SetPoint(i, time.Unix(int64(i)*60, 0), &v)
+		}
+		return s
+	}
+	number := func(labels data.Labels, value *float64) mathexp.Number {
+		n := mathexp.NewNumber("B", labels)
+		n.SetValue(value)
+		return n
+	}
+	value := func(v float64) *float64 {
+		return &v
+	}
+	opts := mathexp.ForecastOptions{Model: mathexp.ForecastLinear, Horizon: 10 * time.Minute}
+	tracer := tracing.InitializeTracerForTest()
+
+	t.Run("value returns a number per series", func(t *testing.T) {
+		cmd, err := NewForecastCommand("B", "A", opts, "")
+		require.NoError(t, err)
+
+		results, err := cmd.Execute(context.Background(), time.Now(), mathexp.Vars{
+			"A": mathexp.Results{Values: mathexp.Values{
+				series(data.Labels{"disk": "a"}, 10, 20, 30),
+				series(data.Labels{"disk": "b"}, 50, 50, 50),
+				series(data.Labels{"disk": "c"}, 10),
+			}},
+		}, tracer, nil)
+		require.NoError(t, err)
+		require.Len(t, results.Values, 3)
+		expected := []struct {
+			labels data.Labels
+			value  *float64
+		}{
+			{data.Labels{"disk": "a"}, value(130)},
+			{data.Labels{"disk": "b"}, value(50)},
+			{data.Labels{"disk": "c"}, nil},
+		}
+		for i, e := range expected {
+			num := results.Values[i].(mathexp.Number)
+			require.Equal(t, e.labels, num.GetLabels())
+			if e.value == nil {
+				require.Nil(t, num.GetFloat64Value())
+				continue
+			}
+			require.InDelta(t, *e.value, *num.GetFloat64Value(), 1e-9)
+		}
+	})
+
+	t.Run("series returns the forecast series", func(t *testing.T) {
+		cmd, err := NewForecastCommand("B", "A", opts, ForecastOutputSeries)
+		require.NoError(t, err)
+
+		results, err := cmd.Execute(context.Background(), time.Now(), mathexp.Vars{
+			"A": mathexp.Results{Values: mathexp.Values{
+				series(data.Labels{"disk": "a"}, 10, 20, 30),
+			}},
+		}, tracer, nil)
+		require.NoError(t, err)
+		require.Len(t, results.Values, 1)
+		s := results.Values[0].(mathexp.Series)
+		require.Equal(t, data.Labels{"disk": "a"}, s.GetLabels())
+		require.Equal(t, 10, s.Len())
+		ts, v := s.GetPoint(9)
+		require.True(t, time.Unix(12*60, 0).Equal(ts))
+		require.InDelta(t, 130, *v, 1e-9)
+	})
+
+	t.Run("no data is passed through", func(t *testing.T) {
+		cmd, err := NewForecastCommand("B", "A", opts, "")
+		require.NoError(t, err)
+
+		results, err := cmd.Execute(context.Background(), time.Now(), mathexp.Vars{
+			"A": mathexp.Results{Values: mathexp.Values{mathexp.NewNoData()}},
+		}, tracer, nil)
+		require.NoError(t, err)
+		require.True(t, results.IsNoData())
+	})
+
+	t.Run("fails on numbers", func(t *testing.T) {
+		cmd, err := NewForecastCommand("B", "A", opts, "")
+		require.NoError(t, err)
+
+		_, err = cmd.Execute(context.Background(), time.Now(), mathexp.Vars{
+			"A": mathexp.Results{Values: mathexp.Values{number(nil, value(1))}},
+		}, tracer, nil)
+		require.ErrorContains(t, err, "can only forecast type series")
+	})
+}
@@ -61,6 +61,11 @@ type DataPipeline []Node
 // execute runs all the command/datasource requests in the pipeline return a
 // map of the refId of the of each command
 func (dp *DataPipeline) execute(c context.Context, now time.Time, s *Service) (mathexp.Vars, error) {
+	return dp.executeWithTrace(c, now, s, nil)
+}
+
+// executeWithTrace is execute that records every node in trace, unless trace is nil.
+func (dp *DataPipeline) executeWithTrace(c context.Context, now time.Time, s *Service, trace *pipelineTrace) (mathexp.Vars, error) {
 	vars := make(mathexp.Vars)
 
 	groupByDSFlag := s.features.IsEnabled(c, featuremgmt.FlagSseGroupByDatasource)
@@ -75,6 +80,9 @@ func (dp *DataPipeline) execute(c context.Context, now time.Time, s *Service) (m
 		}
 
 		executeDSNodesGrouped(c, now, vars, s, dsNodes)
+		for _, node := range dsNodes {
+			trace.record(node, vars, nil, false)
+		}
 	}
 
 	for _, node := range *dp {
@@ -97,6 +105,7 @@ func (dp *DataPipeline) execute(c context.Context, now time.Time, s *Service) (m
 			}
 		}
 		if hasDepError {
+			trace.record(node, vars, nil, true)
 			continue
 		}
 
@@ -113,12 +122,15 @@ func (dp *DataPipeline) execute(c context.Context, now time.Time, s *Service) (m
 			return vars, makeUnexpectedNodeTypeError(node.RefID(), node.NodeType().String())
 		}
 
+		start := time.Now()
 		res, err := execNode.Execute(c, now, vars, s)
 		if err != nil {
 			res.Error = err
 		}
 
 		vars[node.RefID()] = res
+		duration := time.Since(start)
+		trace.record(node, vars, &duration, false)
 	}
 	return vars, nil
 }
@@ -277,6 +289,7 @@ func (s *Service) buildGraph(req *Request) (*simple.DirectedGraph, error) {
 			QueryRaw:   query.JSON,
 			RefID:      query.RefID,
 			TimeRange:  query.TimeRange,
+			Interval:   query.Interval,
 			QueryType:  query.QueryType,
 			DataSource: query.DataSource,
 			idx:        int64(i),
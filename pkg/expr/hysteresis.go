@@ -3,10 +3,13 @@ package expr
 import (
 	"context"
 	"fmt"
+	"slices"
 	"time"
 
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/grafana/grafana/pkg/expr/mathexp"
 	"github.com/grafana/grafana/pkg/expr/metrics"
@@ -27,27 +30,89 @@ type HysteresisCommand struct {
 	LoadingThresholdFunc   ThresholdCommand
 	UnloadingThresholdFunc ThresholdCommand
 	LoadedDimensions       Fingerprints
+	// Transitions delays the loading and unloading of dimensions. Streaks are the HysteresisStreak of the dimensions that
+	// were waiting to transition during the previous evaluation.
+	Transitions HysteresisTransitions
+	Streaks     HysteresisStreaks
 }
 
+// HysteresisTransitions delays the transitions of HysteresisCommand to protect from flapping.
+// A dimension is loaded only after it has been beyond the loading threshold for PendingEvaluations consecutive evaluations
+// and for PendingPeriod, and unloaded only after it has been beyond the unloading threshold for RecoveryEvaluations
+// consecutive evaluations and for RecoveryPeriod. Zero values do not delay the transition.
+type HysteresisTransitions struct {
+	PendingEvaluations  int64
+	PendingPeriod       time.Duration
+	RecoveryEvaluations int64
+	RecoveryPeriod      time.Duration
+}
+
+// IsZero returns true if no transition is delayed.
+func (t HysteresisTransitions) IsZero() bool {
+	return t == HysteresisTransitions{}
+}
+
+// Satisfied returns true if the streak is long enough to load the dimension, or to unload it if loading is false.
+func (t HysteresisTransitions) Satisfied(streak HysteresisStreak, now time.Time, loading bool) bool {
+	evaluations, period := t.RecoveryEvaluations, t.RecoveryPeriod
+	if loading {
+		evaluations, period = t.PendingEvaluations, t.PendingPeriod
+	}
+	return streak.Evaluations >= evaluations && now.Sub(streak.Since) >= period
+}
+
+// HysteresisStreak is the progress of a dimension that is beyond the threshold but has not transitioned yet.
+type HysteresisStreak struct {
+	// Evaluations is the number of consecutive evaluations, including the current one, that the dimension has been beyond the threshold.
+	Evaluations int64 `json:"evaluations"`
+	// Since is the time of the first evaluation of the streak.
+	Since time.Time `json:"since"`
+}
+
+type HysteresisStreaks map[data.Fingerprint]HysteresisStreak
+
 func (h *HysteresisCommand) NeedsVars() []string {
-	return []string{h.ReferenceVar}
+	vars := []string{h.ReferenceVar}
+	for _, v := range append(h.LoadingThresholdFunc.NeedsVars(), h.UnloadingThresholdFunc.NeedsVars()...) {
+		if !slices.Contains(vars, v) {
+			vars = append(vars, v)
+		}
+	}
+	return vars
 }
 
 func (h *HysteresisCommand) Execute(ctx context.Context, now time.Time, vars mathexp.Vars, tracer tracing.Tracer, metrics *metrics.ExprMetrics) (mathexp.Results, error) {
 	results := vars[h.ReferenceVar]
 
-	logger := logger.FromContext(ctx)
 	traceCtx, span := tracer.Start(ctx, "SSE.ExecuteHysteresis")
 	span.SetAttributes(attribute.Int("previousLoadedDimensions", len(h.LoadedDimensions)))
 	span.SetAttributes(attribute.Int("totalDimensions", len(results.Values)))
+	span.SetAttributes(attribute.Int("previousStreaks", len(h.Streaks)))
 	defer span.End()
 
 	// shortcut for NoData
 	if results.IsNoData() {
 		return mathexp.Results{Values: mathexp.Values{mathexp.NewNoData()}}, nil
 	}
+	loadingResults, unloadingResults, err := h.executeThresholds(traceCtx, now, vars, tracer, metrics)
+	if err != nil {
+		return mathexp.Results{}, err
+	}
+	if h.Transitions.IsZero() {
+		return mathexp.Results{Values: append(loadingResults.Values, unloadingResults.Values...)}, nil
+	}
+	return mathexp.Results{Values: append(h.delayTransitions(now, loadingResults.Values, true), h.delayTransitions(now, unloadingResults.Values, false)...)}, nil
+}
+
+// executeThresholds applies the loading threshold to the dimensions that are not loaded and the unloading threshold to the loaded ones.
+func (h *HysteresisCommand) executeThresholds(ctx context.Context, now time.Time, vars mathexp.Vars, tracer tracing.Tracer, metrics *metrics.ExprMetrics) (loadingResults mathexp.Results, unloadingResults mathexp.Results, err error) {
+	results := vars[h.ReferenceVar]
+	logger := logger.FromContext(ctx)
+	span := trace.SpanFromContext(ctx)
+
 	if len(h.LoadedDimensions) == 0 {
-		return h.LoadingThresholdFunc.Execute(traceCtx, now, vars, tracer, metrics)
+		loadingResults, err = h.LoadingThresholdFunc.Execute(ctx, now, vars, tracer, metrics)
+		return loadingResults, mathexp.Results{}, err
 	}
 	var loadedVals, unloadedVals mathexp.Values
 	for _, value := range results.Values {
@@ -63,10 +128,12 @@ func (h *HysteresisCommand) Execute(ctx context.Context, now time.Time, vars mat
 
 	logger.Debug("Evaluating thresholds", "unloadingThresholdDimensions", len(loadedVals), "loadingThresholdDimensions", len(unloadedVals))
 	if len(loadedVals) == 0 { // if all values are unloaded
-		return h.LoadingThresholdFunc.Execute(traceCtx, now, vars, tracer, metrics)
+		loadingResults, err = h.LoadingThresholdFunc.Execute(ctx, now, vars, tracer, metrics)
+		return loadingResults, mathexp.Results{}, err
 	}
 	if len(unloadedVals) == 0 { // if all values are loaded
-		return h.UnloadingThresholdFunc.Execute(traceCtx, now, vars, tracer, metrics)
+		unloadingResults, err = h.UnloadingThresholdFunc.Execute(ctx, now, vars, tracer, metrics)
+		return mathexp.Results{}, unloadingResults, err
 	}
 
 	defer func() {
@@ -75,17 +142,48 @@ func (h *HysteresisCommand) Execute(ctx context.Context, now time.Time, vars mat
 	}()
 
 	vars[h.ReferenceVar] = mathexp.Results{Values: unloadedVals}
-	loadingResults, err := h.LoadingThresholdFunc.Execute(traceCtx, now, vars, tracer, metrics)
+	loadingResults, err = h.LoadingThresholdFunc.Execute(ctx, now, vars, tracer, metrics)
 	if err != nil {
-		return mathexp.Results{}, fmt.Errorf("failed to execute loading threshold: %w", err)
+		return mathexp.Results{}, mathexp.Results{}, fmt.Errorf("failed to execute loading threshold: %w", err)
 	}
 	vars[h.ReferenceVar] = mathexp.Results{Values: loadedVals}
-	unloadingResults, err := h.UnloadingThresholdFunc.Execute(traceCtx, now, vars, tracer, metrics)
+	unloadingResults, err = h.UnloadingThresholdFunc.Execute(ctx, now, vars, tracer, metrics)
 	if err != nil {
-		return mathexp.Results{}, fmt.Errorf("failed to execute unloading threshold: %w", err)
+		return mathexp.Results{}, mathexp.Results{}, fmt.Errorf("failed to execute unloading threshold: %w", err)
 	}
+	return loadingResults, unloadingResults, nil
+}
 
-	return mathexp.Results{Values: append(loadingResults.Values, unloadingResults.Values...)}, nil
+// delayTransitions keeps the previous result of the dimensions that crossed the threshold until they have stayed
+// beyond it for long enough. loading is true if the values are the results of the loading threshold.
+// The values that keep the previous result carry their HysteresisStreak in the meta of the frame.
+func (h *HysteresisCommand) delayTransitions(now time.Time, values mathexp.Values, loading bool) mathexp.Values {
+	for _, value := range values {
+		num, ok := value.(mathexp.Number)
+		if !ok {
+			continue
+		}
+		v := num.GetFloat64Value()
+		// The loading threshold returns 1 to load a dimension. The unloading threshold is inverted and returns 0 to unload it.
+		if v == nil || (*v != 0) != loading {
+			continue
+		}
+		streak := h.Streaks[num.GetLabels().Fingerprint()]
+		if streak.Evaluations == 0 {
+			streak.Since = now
+		}
+		streak.Evaluations++
+		if h.Transitions.Satisfied(streak, now, loading) {
+			continue
+		}
+		previous := 1.0
+		if loading {
+			previous = 0
+		}
+		num.SetValue(&previous)
+		num.SetMeta(streak)
+	}
+	return values
 }
 
 func (h HysteresisCommand) Type() string {
@@ -93,15 +191,65 @@ func (h HysteresisCommand) Type() string {
 }
 
 func NewHysteresisCommand(refID string, referenceVar string, loadCondition ThresholdCommand, unloadCondition ThresholdCommand, l Fingerprints) (*HysteresisCommand, error) {
+	return NewHysteresisCommandWithTransitions(refID, referenceVar, loadCondition, unloadCondition, l, HysteresisTransitions{}, nil)
+}
+
+// NewHysteresisCommandWithTransitions creates a HysteresisCommand that delays the transitions of dimensions.
+// streaks are the HysteresisStreak returned by the previous evaluation.
+func NewHysteresisCommandWithTransitions(refID string, referenceVar string, loadCondition ThresholdCommand, unloadCondition ThresholdCommand, l Fingerprints, transitions HysteresisTransitions, streaks HysteresisStreaks) (*HysteresisCommand, error) {
+	if transitions.PendingEvaluations < 0 || transitions.RecoveryEvaluations < 0 {
+		return nil, fmt.Errorf("the number of evaluations of a recovery threshold must not be negative")
+	}
+	if transitions.PendingPeriod < 0 || transitions.RecoveryPeriod < 0 {
+		return nil, fmt.Errorf("the period of a recovery threshold must not be negative")
+	}
 	return &HysteresisCommand{
 		RefID:                  refID,
 		LoadingThresholdFunc:   loadCondition,
 		UnloadingThresholdFunc: unloadCondition,
 		ReferenceVar:           referenceVar,
 		LoadedDimensions:       l,
+		Transitions:            transitions,
+		Streaks:                streaks,
 	}, nil
 }
 
+// newHysteresisCommandFromCondition creates a HysteresisCommand from the condition of a threshold query that has an unload evaluator.
+func newHysteresisCommandFromCondition(refID string, referenceVar string, loadCondition ThresholdCommand, unloadCondition ThresholdCommand, condition ThresholdConditionJSON) (*HysteresisCommand, error) {
+	var d Fingerprints
+	var err error
+	if condition.LoadedDimensions != nil {
+		d, err = FingerprintsFromFrame(condition.LoadedDimensions)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse loaded dimensions: %w", err)
+		}
+	}
+	transitions := HysteresisTransitions{
+		PendingEvaluations:  condition.PendingEvaluations,
+		RecoveryEvaluations: condition.RecoveryEvaluations,
+	}
+	if condition.PendingPeriod != "" {
+		transitions.PendingPeriod, err = gtime.ParseDuration(condition.PendingPeriod)
+		if err != nil {
+			return nil, fmt.Errorf(`failed to parse "pendingPeriod" duration field %q: %w`, condition.PendingPeriod, err)
+		}
+	}
+	if condition.RecoveryPeriod != "" {
+		transitions.RecoveryPeriod, err = gtime.ParseDuration(condition.RecoveryPeriod)
+		if err != nil {
+			return nil, fmt.Errorf(`failed to parse "recoveryPeriod" duration field %q: %w`, condition.RecoveryPeriod, err)
+		}
+	}
+	var streaks HysteresisStreaks
+	if condition.Streaks != nil {
+		streaks, err = HysteresisStreaksFromFrame(condition.Streaks)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse streaks: %w", err)
+		}
+	}
+	return NewHysteresisCommandWithTransitions(refID, referenceVar, loadCondition, unloadCondition, d, transitions, streaks)
+}
+
 // FingerprintsFromFrame converts data.Frame to Fingerprints.
 // The input data frame must have a single field of uint64 type.
 // Returns error if the input data frame has invalid format
@@ -149,3 +297,64 @@ func FingerprintsToFrame(fingerprints Fingerprints) *data.Frame {
 	})
 	return frame
 }
+
+// HysteresisStreaksFromFrame converts data.Frame to HysteresisStreaks.
+// The input data frame must have the fields fingerprints (uint64), evaluations (int64) and since (time.Time).
+func HysteresisStreaksFromFrame(frame *data.Frame) (HysteresisStreaks, error) {
+	frameType, frameVersion := frame.TypeInfo("")
+	if frameType != "hysteresis_streaks" {
+		return nil, fmt.Errorf("invalid format of streaks frame: expected frame type 'hysteresis_streaks'")
+	}
+	if frameVersion.Greater(data.FrameTypeVersion{1, 0}) {
+		return nil, fmt.Errorf("invalid format of streaks frame: expected frame type 'hysteresis_streaks' of version 1.0 or lower")
+	}
+	if len(frame.Fields) != 3 {
+		return nil, fmt.Errorf("invalid format of streaks frame: expected 3 fields but got %d", len(frame.Fields))
+	}
+	fps, evaluations, since := frame.Fields[0], frame.Fields[1], frame.Fields[2]
+	if fps.Type() != data.FieldTypeUint64 || evaluations.Type() != data.FieldTypeInt64 || since.Type() != data.FieldTypeTime {
+		return nil, fmt.Errorf("invalid format of streaks frame: expected fields of types uint64, int64 and time but got %s, %s and %s", fps.Type(), evaluations.Type(), since.Type())
+	}
+	result := make(HysteresisStreaks, fps.Len())
+	for i := 0; i < fps.Len(); i++ {
+		result[data.Fingerprint(fps.At(i).(uint64))] = HysteresisStreak{
+			Evaluations: evaluations.At(i).(int64),
+			Since:       since.At(i).(time.Time),
+		}
+	}
+	return result, nil
+}
+
+// HysteresisStreaksToFrame converts HysteresisStreaks to data.Frame.
+func HysteresisStreaksToFrame(streaks HysteresisStreaks) *data.Frame {
+	fps := make([]uint64, 0, len(streaks))
+	evaluations := make([]int64, 0, len(streaks))
+	since := make([]time.Time, 0, len(streaks))
+	for fp, streak := range streaks {
+		fps = append(fps, uint64(fp))
+		evaluations = append(evaluations, streak.Evaluations)
+		since = append(since, streak.Since)
+	}
+	frame := data.NewFrame("",
+		data.NewField("fingerprints", nil, fps),
+		data.NewField("evaluations", nil, evaluations),
+		data.NewField("since", nil, since),
+	)
+	frame.SetMeta(&data.FrameMeta{
+		Type:        "hysteresis_streaks",
+		TypeVersion: data.FrameTypeVersion{1, 0},
+	})
+	return frame
+}
+
+// GetHysteresisStreak returns the HysteresisStreak of a result of HysteresisCommand,
+// or nil if the result has not been held back by HysteresisTransitions.
+func GetHysteresisStreak(frame *data.Frame) *HysteresisStreak {
+	if frame == nil || frame.Meta == nil {
+		return nil
+	}
+	if streak, ok := frame.Meta.Custom.(HysteresisStreak); ok {
+		return &streak
+	}
+	return nil
+}
@@ -119,6 +119,103 @@ func TestHysteresisExecute(t *testing.T) {
 	}
 }
 
+func TestHysteresisExecuteWithTransitions(t *testing.T) {
+	tracer := tracing.InitializeTracerForTest()
+	labels := data.Labels{"label": "value"}
+	fingerprint := labels.Fingerprint()
+	start := time.Unix(0, 0)
+
+	// evaluate runs the command once per value, one minute apart, and feeds the results back the way the alerting engine does.
+	evaluate := func(t *testing.T, transitions HysteresisTransitions, loaded bool, values ...float64) ([]float64, []*HysteresisStreak) {
+		t.Helper()
+		cmd := &HysteresisCommand{
+			RefID:        "B",
+			ReferenceVar: "A",
+			LoadingThresholdFunc: ThresholdCommand{
+				ReferenceVar:  "A",
+				RefID:         "B",
+				ThresholdFunc: ThresholdIsAbove,
+				predicate:     greaterThanPredicate{100},
+			},
+			UnloadingThresholdFunc: ThresholdCommand{
+				ReferenceVar:  "A",
+				RefID:         "B",
+				ThresholdFunc: ThresholdIsAbove,
+				predicate:     greaterThanPredicate{30},
+			},
+			Transitions: transitions,
+		}
+		if loaded {
+			cmd.LoadedDimensions = Fingerprints{fingerprint: {}}
+		}
+		var outputs []float64
+		var streaks []*HysteresisStreak
+		for i, v := range values {
+			input := mathexp.NewNumber("A", labels)
+			input.SetValue(&v)
+			result, err := cmd.Execute(context.Background(), start.Add(time.Duration(i)*time.Minute), mathexp.Vars{
+				"A": mathexp.Results{Values: mathexp.Values{input}},
+			}, tracer, nil)
+			require.NoError(t, err)
+			require.Len(t, result.Values, 1)
+			num := result.Values[0].(mathexp.Number)
+			out := *num.GetFloat64Value()
+			streak := GetHysteresisStreak(num.AsDataFrame())
+			outputs = append(outputs, out)
+			streaks = append(streaks, streak)
+
+			cmd.LoadedDimensions = Fingerprints{}
+			if out == 1 {
+				cmd.LoadedDimensions[fingerprint] = struct{}{}
+			}
+			cmd.Streaks = HysteresisStreaks{}
+			if streak != nil {
+				cmd.Streaks[fingerprint] = *streak
+			}
+		}
+		return outputs, streaks
+	}
+
+	t.Run("fires after pending evaluations", func(t *testing.T) {
+		outputs, streaks := evaluate(t, HysteresisTransitions{PendingEvaluations: 3}, false, 150, 150, 150, 150)
+		require.Equal(t, []float64{0, 0, 1, 1}, outputs)
+		require.Equal(t, []*HysteresisStreak{
+			{Evaluations: 1, Since: start},
+			{Evaluations: 2, Since: start},
+			nil,
+			nil,
+		}, streaks)
+	})
+
+	t.Run("pending evaluations restart when the series is not beyond the threshold", func(t *testing.T) {
+		outputs, _ := evaluate(t, HysteresisTransitions{PendingEvaluations: 2}, false, 150, 50, 150, 150)
+		require.Equal(t, []float64{0, 0, 0, 1}, outputs)
+	})
+
+	t.Run("fires after pending period", func(t *testing.T) {
+		outputs, _ := evaluate(t, HysteresisTransitions{PendingPeriod: 2 * time.Minute}, false, 150, 150, 150)
+		require.Equal(t, []float64{0, 0, 1}, outputs)
+	})
+
+	t.Run("requires both pending evaluations and period", func(t *testing.T) {
+		outputs, _ := evaluate(t, HysteresisTransitions{PendingEvaluations: 2, PendingPeriod: 2 * time.Minute}, false, 150, 150, 150)
+		require.Equal(t, []float64{0, 0, 1}, outputs)
+	})
+
+	t.Run("recovers after recovery evaluations", func(t *testing.T) {
+		outputs, streaks := evaluate(t, HysteresisTransitions{RecoveryEvaluations: 2}, true, 10, 50, 10, 10, 10)
+		require.Equal(t, []float64{1, 1, 1, 0, 0}, outputs)
+		require.Equal(t, &HysteresisStreak{Evaluations: 1, Since: start}, streaks[0])
+		require.Nil(t, streaks[1])
+		require.Equal(t, &HysteresisStreak{Evaluations: 1, Since: start.Add(2 * time.Minute)}, streaks[2])
+	})
+
+	t.Run("does not delay recovery if only pending is set", func(t *testing.T) {
+		outputs, _ := evaluate(t, HysteresisTransitions{PendingEvaluations: 2}, true, 10)
+		require.Equal(t, []float64{0}, outputs)
+	})
+}
+
 func TestLoadedDimensionsFromFrame(t *testing.T) {
 	correctType := &data.FrameMeta{Type: "fingerprints", TypeVersion: data.FrameTypeVersion{1, 0}}
 	testCases := []struct {
@@ -219,3 +316,31 @@ func TestFingerprintsToFrame(t *testing.T) {
 		})
 	}
 }
+
+func TestHysteresisStreaksFrame(t *testing.T) {
+	t.Run("converts to frame and back", func(t *testing.T) {
+		streaks := HysteresisStreaks{
+			1: {Evaluations: 1, Since: time.Unix(100, 0)},
+			2: {Evaluations: 5, Since: time.Unix(200, 0)},
+		}
+		actual, err := HysteresisStreaksFromFrame(HysteresisStreaksToFrame(streaks))
+		require.NoError(t, err)
+		require.Equal(t, streaks, actual)
+	})
+
+	t.Run("fails if the frame has another type", func(t *testing.T) {
+		_, err := HysteresisStreaksFromFrame(FingerprintsToFrame(Fingerprints{1: {}}))
+		require.ErrorContains(t, err, "expected frame type 'hysteresis_streaks'")
+	})
+
+	t.Run("fails if the fields have wrong types", func(t *testing.T) {
+		frame := data.NewFrame("",
+			data.NewField("fingerprints", nil, []uint64{1}),
+			data.NewField("evaluations", nil, []float64{1}),
+			data.NewField("since", nil, []time.Time{time.Unix(100, 0)}),
+		)
+		frame.SetMeta(&data.FrameMeta{Type: "hysteresis_streaks", TypeVersion: data.FrameTypeVersion{1, 0}})
+		_, err := HysteresisStreaksFromFrame(frame)
+		require.ErrorContains(t, err, "expected fields of types uint64, int64 and time")
+	})
+}
@@ -0,0 +1,288 @@
+package mathexp
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// The anomaly detection algorithm
+// +enum
+type AnomalyAlgorithm string
+
+const (
+	// Rolling mean and standard deviation
+	AnomalyZScore AnomalyAlgorithm = "zscore"
+
+	// Rolling median and median absolute deviation
+	AnomalyMAD AnomalyAlgorithm = "mad"
+
+	// Seasonal baseline using additive Holt-Winters
+	AnomalyHoltWinters AnomalyAlgorithm = "holt_winters"
+)
+
+// AnomalyBandLabel is the label added to the band series to tell them apart.
+const AnomalyBandLabel = "anomaly_band"
+
+const (
+	// minAnomalyBaseline is the minimum number of points needed to calculate a band.
+	minAnomalyBaseline = 3
+
+	// DefaultAnomalySensitivity is the default width of the band in deviations.
+	DefaultAnomalySensitivity = 3.0
+
+	// Smoothing factors for level, trend and season (and deviation) of the Holt-Winters baseline.
+	holtWintersAlpha = 0.5
+	holtWintersBeta  = 0.1
+	holtWintersGamma = 0.3
+
+	// madScale makes the median absolute deviation comparable to the standard deviation of a normal distribution.
+	madScale = 1.4826
+)
+
+// AnomalyOptions configures the anomaly detection of a series.
+type AnomalyOptions struct {
+	Algorithm AnomalyAlgorithm
+	// Window is the duration of the rolling baseline used by zscore and mad.
+	Window time.Duration
+	// Season is the duration of one season used by holt_winters, e.g. 1d for daily seasonality.
+	Season time.Duration
+	// Sensitivity is the width of the band in deviations from the baseline.
+	Sensitivity float64
+}
+
+// Validate returns an error if the options cannot be used with the selected algorithm.
+func (o AnomalyOptions) Validate() error {
+	switch o.Algorithm {
+	case AnomalyZScore, AnomalyMAD:
+		if o.Window <= 0 {
+			return fmt.Errorf("algorithm %s requires a positive window", o.Algorithm)
+		}
+	case AnomalyHoltWinters:
+		if o.Season <= 0 {
+			return fmt.Errorf("algorithm %s requires a positive season", o.Algorithm)
+		}
+	default:
+		return fmt.Errorf("anomaly algorithm %q is not supported. Supported only: [%s,%s,%s]", o.Algorithm, AnomalyZScore, AnomalyMAD, AnomalyHoltWinters)
+	}
+	if math.IsNaN(o.Sensitivity) || o.Sensitivity <= 0 {
+		return fmt.Errorf("sensitivity must be greater than 0, got %v", o.Sensitivity)
+	}
+	return nil
+}
+
+// AnomalyBands is the result of the anomaly detection of a single series.
+// All series have the same timestamps as the input series. Points that have no baseline yet,
+// or are nil or NaN in the input series, are nil.
+type AnomalyBands struct {
+	Lower Series
+	Upper Series
+	// Anomaly is 1 for points outside the band and 0 otherwise.
+	Anomaly Series
+}
+
+// Verdict returns the value of the Anomaly series at the last point of the series,
+// or nil if the series is empty.
+func (b AnomalyBands) Verdict() *float64 {
+	if b.Anomaly.Len() == 0 {
+		return nil
+	}
+	return b.Anomaly.GetValue(b.Anomaly.Len() - 1)
+}
+
+type anomalyPoint struct {
+	idx int
+	t   time.Time
+	v   float64
+}
+
+type band struct {
+	lower, upper float64
+}
+
+// DetectAnomalies calculates the expected range of every point of the series from the points before it,
+// and flags the points that fall outside of it. The series must be sorted by time in ascending order.
+func (s Series) DetectAnomalies(refID string, opts AnomalyOptions) (AnomalyBands, error) {
+	if err := opts.Validate(); err != nil {
+		return AnomalyBands{}, err
+	}
+
+	points := make([]anomalyPoint, 0, s.Len())
+	for i := 0; i < s.Len(); i++ {
+		t, v := s.GetPoint(i)
+		if v == nil || math.IsNaN(*v) {
+			continue
+		}
+		points = append(points, anomalyPoint{idx: i, t: t, v: *v})
+	}
+
+	var bands []*band
+	switch opts.Algorithm {
+	case AnomalyZScore:
+		bands = rollingBands(points, opts.Window, func(values []float64) band {
+			mean, stdDev := meanStdDev(values)
+			return band{lower: mean - opts.Sensitivity*stdDev, upper: mean + opts.Sensitivity*stdDev}
+		})
+	case AnomalyMAD:
+		bands = rollingBands(points, opts.Window, func(values []float64) band {
+			median, mad := medianAbsoluteDeviation(values)
+			return band{lower: median - opts.Sensitivity*madScale*mad, upper: median + opts.Sensitivity*madScale*mad}
+		})
+	case AnomalyHoltWinters:
+		bands = holtWintersBands(points, opts.Season, opts.Sensitivity)
+	}
+
+	newSeries := func(name string) Series {
+		labels := data.Labels{}
+		if s.GetLabels() != nil {
+			labels = s.GetLabels().Copy()
+		}
+		labels[AnomalyBandLabel] = name
+		return NewSeries(refID, labels, s.Len())
+	}
+	result := AnomalyBands{
+		Lower:   newSeries("lower"),
+		Upper:   newSeries("upper"),
+		Anomaly: newSeries("anomaly"),
+	}
+	for i := 0; i < s.Len(); i++ {
+		t := s.GetTime(i)
+		result.Lower.SetPoint(i, t, nil)
+		result.Upper.SetPoint(i, t, nil)
+		result.Anomaly.SetPoint(i, t, nil)
+	}
+	for i, p := range points {
+		b := bands[i]
+		if b == nil {
+			continue
+		}
+		lower, upper := b.lower, b.upper
+		anomaly := 0.0
+		if p.v < lower || p.v > upper {
+			anomaly = 1
+		}
+		result.Lower.SetPoint(p.idx, p.t, &lower)
+		result.Upper.SetPoint(p.idx, p.t, &upper)
+		result.Anomaly.SetPoint(p.idx, p.t, &anomaly)
+	}
+	return result, nil
+}
+
+// rollingBands calculates the band of every point from the points within the window before it.
+func rollingBands(points []anomalyPoint, window time.Duration, calc func(values []float64) band) []*band {
+	bands := make([]*band, len(points))
+	start := 0
+	for i, p := range points {
+		for start < i && p.t.Sub(points[start].t) > window {
+			start++
+		}
+		if i-start < minAnomalyBaseline {
+			continue
+		}
+		values := make([]float64, 0, i-start)
+		for _, bp := range points[start:i] {
+			values = append(values, bp.v)
+		}
+		b := calc(values)
+		bands[i] = &b
+	}
+	return bands
+}
+
+// holtWintersBands calculates the band of every point as the additive Holt-Winters forecast
+// plus or minus the smoothed seasonal deviation of the forecast error (Brutlag's method).
+// The first season is used to initialize the model and has no band.
+func holtWintersBands(points []anomalyPoint, season time.Duration, sensitivity float64) []*band {
+	bands := make([]*band, len(points))
+	step := medianStep(points)
+	if step <= 0 {
+		return bands
+	}
+	m := int(math.Round(float64(season) / float64(step)))
+	if m < 2 || len(points) <= m {
+		return bands
+	}
+
+	var level float64
+	for _, p := range points[:m] {
+		level += p.v
+	}
+	level /= float64(m)
+	trend := 0.0
+	seasonal := make([]float64, m)
+	deviation := make([]float64, m)
+	var meanDeviation float64
+	for i, p := range points[:m] {
+		seasonal[i] = p.v - level
+		meanDeviation += math.Abs(seasonal[i])
+	}
+	meanDeviation /= float64(m)
+	for i := range deviation {
+		deviation[i] = meanDeviation
+	}
+
+	for i := m; i < len(points); i++ {
+		y := points[i].v
+		si := i % m
+		forecast := level + trend + seasonal[si]
+		bands[i] = &band{
+			lower: forecast - sensitivity*deviation[si],
+			upper: forecast + sensitivity*deviation[si],
+		}
+
+		prevLevel := level
+		level = holtWintersAlpha*(y-seasonal[si]) + (1-holtWintersAlpha)*(level+trend)
+		trend = holtWintersBeta*(level-prevLevel) + (1-holtWintersBeta)*trend
+		seasonal[si] = holtWintersGamma*(y-level) + (1-holtWintersGamma)*seasonal[si]
+		deviation[si] = holtWintersGamma*math.Abs(y-forecast) + (1-holtWintersGamma)*deviation[si]
+	}
+	return bands
+}
+
+// medianStep returns the median interval between the points.
+func medianStep(points []anomalyPoint) time.Duration {
+	if len(points) < 2 {
+		return 0
+	}
+	steps := make([]time.Duration, 0, len(points)-1)
+	for i := 1; i < len(points); i++ {
+		steps = append(steps, points[i].t.Sub(points[i-1].t))
+	}
+	sort.Slice(steps, func(i, j int) bool { return steps[i] < steps[j] })
+	return steps[len(steps)/2]
+}
+
+func meanStdDev(values []float64) (float64, float64) {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+	var variance float64
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(variance / float64(len(values)))
+}
+
+func medianAbsoluteDeviation(values []float64) (float64, float64) {
+	median := medianOf(values)
+	deviations := make([]float64, len(values))
+	for i, v := range values {
+		deviations[i] = math.Abs(v - median)
+	}
+	return median, medianOf(deviations)
+}
+
+// medianOf returns the median of the values. It sorts the slice in place.
+func medianOf(values []float64) float64 {
+	sort.Float64s(values)
+	mid := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[mid-1] + values[mid]) / 2
+	}
+	return values[mid]
+}
@@ -0,0 +1,128 @@
+package mathexp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func seriesFromValues(labels data.Labels, values ...float64) Series {
+	points := make([]tp, 0, len(values))
+	for i, v := range values {
+		points = append(points, tp{time.Unix(int64(i), 0), float64Pointer(v)})
+	}
+	return makeSeries("A", labels, points...)
+}
+
+func anomalyValues(s Series) []*float64 {
+	result := make([]*float64, 0, s.Len())
+	for i := 0; i < s.Len(); i++ {
+		result = append(result, s.GetValue(i))
+	}
+	return result
+}
+
+func TestDetectAnomalies(t *testing.T) {
+	tests := []struct {
+		name      string
+		series    Series
+		opts      AnomalyOptions
+		anomalies []*float64
+	}{
+		{
+			name:   "zscore flags a spike",
+			series: seriesFromValues(nil, 10, 11, 10, 11, 10, 11, 50),
+			opts:   AnomalyOptions{Algorithm: AnomalyZScore, Window: 10 * time.Second, Sensitivity: 3},
+			anomalies: []*float64{
+				nil, nil, nil, float64Pointer(0), float64Pointer(0), float64Pointer(0), float64Pointer(1),
+			},
+		},
+		{
+			name:   "zscore uses only the points within the window",
+			series: seriesFromValues(nil, 100, 100, 100, 10, 10, 10, 10, 11),
+			opts:   AnomalyOptions{Algorithm: AnomalyZScore, Window: 3 * time.Second, Sensitivity: 3},
+			anomalies: []*float64{
+				nil, nil, nil, float64Pointer(1), float64Pointer(0), float64Pointer(0), float64Pointer(0), float64Pointer(1),
+			},
+		},
+		{
+			name:   "mad ignores earlier outliers",
+			series: seriesFromValues(nil, 10, 11, 1000, 10, 11, 10, 30),
+			opts:   AnomalyOptions{Algorithm: AnomalyMAD, Window: 10 * time.Second, Sensitivity: 3},
+			anomalies: []*float64{
+				nil, nil, nil, float64Pointer(0), float64Pointer(0), float64Pointer(0), float64Pointer(1),
+			},
+		},
+		{
+			name:   "holt_winters follows the season",
+			series: seriesFromValues(nil, 1, 5, 1, 5, 1, 5, 1, 5, 1, 5, 1, 5, 20),
+			opts:   AnomalyOptions{Algorithm: AnomalyHoltWinters, Season: 4 * time.Second, Sensitivity: 3},
+			anomalies: []*float64{
+				nil, nil, nil, nil,
+				float64Pointer(0), float64Pointer(0), float64Pointer(0), float64Pointer(0),
+				float64Pointer(0), float64Pointer(0), float64Pointer(0), float64Pointer(0),
+				float64Pointer(1),
+			},
+		},
+		{
+			name:      "holt_winters needs more than one season",
+			series:    seriesFromValues(nil, 1, 5, 1, 5),
+			opts:      AnomalyOptions{Algorithm: AnomalyHoltWinters, Season: 4 * time.Second, Sensitivity: 3},
+			anomalies: []*float64{nil, nil, nil, nil},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bands, err := tt.series.DetectAnomalies("B", tt.opts)
+			require.NoError(t, err)
+			require.Equal(t, tt.anomalies, anomalyValues(bands.Anomaly))
+			require.Equal(t, tt.anomalies[len(tt.anomalies)-1], bands.Verdict())
+			require.Equal(t, tt.series.Len(), bands.Lower.Len())
+			require.Equal(t, tt.series.Len(), bands.Upper.Len())
+		})
+	}
+
+	t.Run("bands keep the labels of the series", func(t *testing.T) {
+		s := seriesFromValues(data.Labels{"host": "a"}, 1, 2, 3, 4)
+		bands, err := s.DetectAnomalies("B", AnomalyOptions{Algorithm: AnomalyZScore, Window: time.Minute, Sensitivity: 1})
+		require.NoError(t, err)
+		require.Equal(t, data.Labels{"host": "a", AnomalyBandLabel: "lower"}, bands.Lower.GetLabels())
+		require.Equal(t, data.Labels{"host": "a", AnomalyBandLabel: "upper"}, bands.Upper.GetLabels())
+		require.Equal(t, data.Labels{"host": "a", AnomalyBandLabel: "anomaly"}, bands.Anomaly.GetLabels())
+		require.Equal(t, "B", bands.Anomaly.GetName())
+		require.InDelta(t, 2-0.816, *bands.Lower.GetValue(3), 0.001)
+		require.InDelta(t, 2+0.816, *bands.Upper.GetValue(3), 0.001)
+		require.Equal(t, data.Labels{"host": "a"}, s.GetLabels())
+	})
+
+	t.Run("nil and NaN points are skipped", func(t *testing.T) {
+		s := makeSeries("A", nil,
+			tp{time.Unix(0, 0), float64Pointer(10)},
+			tp{time.Unix(1, 0), nil},
+			tp{time.Unix(2, 0), float64Pointer(10)},
+			tp{time.Unix(3, 0), NaN},
+			tp{time.Unix(4, 0), float64Pointer(10)},
+			tp{time.Unix(5, 0), float64Pointer(10)},
+		)
+		bands, err := s.DetectAnomalies("B", AnomalyOptions{Algorithm: AnomalyZScore, Window: time.Minute, Sensitivity: 3})
+		require.NoError(t, err)
+		require.Equal(t, []*float64{nil, nil, nil, nil, nil, float64Pointer(0)}, anomalyValues(bands.Anomaly))
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		s := seriesFromValues(nil, 1, 2, 3)
+		for _, opts := range []AnomalyOptions{
+			{Algorithm: "foo", Window: time.Minute, Sensitivity: 3},
+			{Algorithm: AnomalyZScore, Sensitivity: 3},
+			{Algorithm: AnomalyMAD, Sensitivity: 3},
+			{Algorithm: AnomalyHoltWinters, Window: time.Minute, Sensitivity: 3},
+			{Algorithm: AnomalyZScore, Window: time.Minute},
+		} {
+			_, err := s.DetectAnomalies("B", opts)
+			require.Error(t, err)
+		}
+	})
+}
@@ -203,48 +203,60 @@ type Union struct {
 // operations. The labels of the Union will the taken from result with a greater
 // number of tags.
 func (e *State) union(aResults, bResults Results, biNode *parse.BinaryNode) []*Union {
-	unions := []*Union{}
-	appendUnions := func(u *Union) {
-		unions = append(unions, u)
+	if len(aResults.Values) == 0 || len(bResults.Values) == 0 {
+		return []*Union{}
 	}
 
+	unions, aMatched, bMatched := matchUnions(aResults, bResults)
+
 	aVar := biNode.Args[0].String()
 	bVar := biNode.Args[1].String()
+	check := func(v string, matchArray []bool, r *Results) {
+		for i, b := range matchArray {
+			if b {
+				continue
+			}
+			if e.Drops == nil {
+				e.Drops = make(map[string]map[string][]data.Labels)
+			}
+			if e.Drops[biNode.String()] == nil {
+				e.Drops[biNode.String()] = make(map[string][]data.Labels)
+			}
 
-	aMatched := make([]bool, len(aResults.Values))
-	bMatched := make([]bool, len(bResults.Values))
-	collectDrops := func() {
-		check := func(v string, matchArray []bool, r *Results) {
-			for i, b := range matchArray {
-				if b {
-					continue
-				}
-				if e.Drops == nil {
-					e.Drops = make(map[string]map[string][]data.Labels)
-				}
-				if e.Drops[biNode.String()] == nil {
-					e.Drops[biNode.String()] = make(map[string][]data.Labels)
-				}
-
-				if r.Values[i].Type() == parse.TypeNoData {
-					continue
-				}
-
-				e.DropCount++
-				e.Drops[biNode.String()][v] = append(e.Drops[biNode.String()][v], r.Values[i].GetLabels())
+			if r.Values[i].Type() == parse.TypeNoData {
+				continue
 			}
+
+			e.DropCount++
+			e.Drops[biNode.String()][v] = append(e.Drops[biNode.String()][v], r.Values[i].GetLabels())
 		}
-		check(aVar, aMatched, &aResults)
-		check(bVar, bMatched, &bResults)
 	}
+	check(aVar, aMatched, &aResults)
+	check(bVar, bMatched, &bResults)
+	return unions
+}
 
-	aValueLen := len(aResults.Values)
-	bValueLen := len(bResults.Values)
-	if aValueLen == 0 || bValueLen == 0 {
-		return unions
+// Unions matches the values of two results by their labels using the same rules as the binary
+// operations of math expressions. Values that do not match any value of the other result are dropped.
+func Unions(aResults, bResults Results) []*Union {
+	if len(aResults.Values) == 0 || len(bResults.Values) == 0 {
+		return []*Union{}
 	}
+	unions, _, _ := matchUnions(aResults, bResults)
+	return unions
+}
 
-	if aValueLen == 1 || bValueLen == 1 {
+// matchUnions creates the unions of two non-empty results and reports which values of each result were matched.
+func matchUnions(aResults, bResults Results) ([]*Union, []bool, []bool) {
+	unions := []*Union{}
+	appendUnions := func(u *Union) {
+		unions = append(unions, u)
+	}
+
+	aMatched := make([]bool, len(aResults.Values))
+	bMatched := make([]bool, len(bResults.Values))
+
+	if len(aResults.Values) == 1 || len(bResults.Values) == 1 {
 		aNoData := aResults.Values[0].Type() == parse.TypeNoData
 		bNoData := bResults.Values[0].Type() == parse.TypeNoData
 		if aNoData || bNoData {
@@ -253,8 +265,7 @@ func (e *State) union(aResults, bResults Results, biNode *parse.BinaryNode) []*U
 				A:      aResults.Values[0],
 				B:      bResults.Values[0],
 			})
-			collectDrops()
-			return unions
+			return unions, aMatched, bMatched
 		}
 	}
 
@@ -302,8 +313,7 @@ func (e *State) union(aResults, bResults Results, biNode *parse.BinaryNode) []*U
 		})
 	}
 
-	collectDrops()
-	return unions
+	return unions, aMatched, bMatched
 }
 
 func (e *State) walkBinary(node *parse.BinaryNode) (Results, error) {
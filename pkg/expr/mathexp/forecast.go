@@ -0,0 +1,203 @@
+package mathexp
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// The forecast model
+// +enum
+type ForecastModel string
+
+const (
+	// Least squares linear regression, like predict_linear in Prometheus
+	ForecastLinear ForecastModel = "linear"
+
+	// Additive Holt-Winters with a trend and a seasonal component
+	ForecastSeasonal ForecastModel = "seasonal"
+)
+
+// ForecastOptions configures the forecast of a series.
+type ForecastOptions struct {
+	Model ForecastModel
+	// Horizon is how far after the last point of the series to forecast.
+	Horizon time.Duration
+	// Window limits the points that the model is fitted on to the ones within the duration before the last point.
+	// Zero uses all points.
+	Window time.Duration
+	// Season is the duration of one season used by the seasonal model, e.g. 1d for daily seasonality.
+	Season time.Duration
+}
+
+// Validate returns an error if the options cannot be used with the selected model.
+func (o ForecastOptions) Validate() error {
+	switch o.Model {
+	case ForecastLinear:
+	case ForecastSeasonal:
+		if o.Season <= 0 {
+			return fmt.Errorf("model %s requires a positive season", o.Model)
+		}
+	default:
+		return fmt.Errorf("forecast model %q is not supported. Supported only: [%s,%s]", o.Model, ForecastLinear, ForecastSeasonal)
+	}
+	if o.Horizon <= 0 {
+		return fmt.Errorf("forecast requires a positive horizon")
+	}
+	if o.Window < 0 {
+		return fmt.Errorf("forecast window must not be negative")
+	}
+	return nil
+}
+
+// forecaster predicts the value of the series at a time after its last point.
+type forecaster func(t time.Time) float64
+
+// Forecast predicts the value of the series at Horizon after its last point.
+// It returns nil if the series does not have enough points to fit the model.
+// The series must be sorted by time in ascending order.
+func (s Series) Forecast(opts ForecastOptions) (*float64, error) {
+	f, points, err := s.fitForecast(opts)
+	if err != nil || f == nil {
+		return nil, err
+	}
+	v := f(points[len(points)-1].t.Add(opts.Horizon))
+	return &v, nil
+}
+
+// ForecastSeries returns the points that the model predicts after the last point of the series, up to Horizon,
+// at the median interval of the series. The series is empty if the series does not have enough points to fit the model.
+func (s Series) ForecastSeries(refID string, opts ForecastOptions) (Series, error) {
+	var labels data.Labels
+	if s.GetLabels() != nil {
+		labels = s.GetLabels().Copy()
+	}
+	f, points, err := s.fitForecast(opts)
+	if err != nil {
+		return Series{}, err
+	}
+	step := medianStep(points)
+	if f == nil || step <= 0 {
+		return NewSeries(refID, labels, 0), nil
+	}
+	last := points[len(points)-1].t
+	n := int(math.Ceil(float64(opts.Horizon) / float64(step)))
+	result := NewSeries(refID, labels, n)
+	for i := 0; i < n; i++ {
+		t := last.Add(time.Duration(i+1) * step)
+		if t.After(last.Add(opts.Horizon)) {
+			t = last.Add(opts.Horizon)
+		}
+		v := f(t)
+		result.SetPoint(i, t, &v)
+	}
+	return result, nil
+}
+
+// forecastPoints returns the points of the series that have a value and are within the window before the last point.
+func (s Series) forecastPoints(window time.Duration) []anomalyPoint {
+	points := make([]anomalyPoint, 0, s.Len())
+	for i := 0; i < s.Len(); i++ {
+		t, v := s.GetPoint(i)
+		if v == nil || math.IsNaN(*v) || math.IsInf(*v, 0) {
+			continue
+		}
+		points = append(points, anomalyPoint{idx: i, t: t, v: *v})
+	}
+	if window <= 0 || len(points) == 0 {
+		return points
+	}
+	last := points[len(points)-1].t
+	start := 0
+	for start < len(points) && last.Sub(points[start].t) > window {
+		start++
+	}
+	return points[start:]
+}
+
+// fitForecast fits the model on the series, and returns the forecaster and the points it was fitted on.
+// The forecaster is nil if there are not enough points.
+func (s Series) fitForecast(opts ForecastOptions) (forecaster, []anomalyPoint, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, nil, err
+	}
+	points := s.forecastPoints(opts.Window)
+	switch opts.Model {
+	case ForecastSeasonal:
+		return holtWintersForecast(points, opts.Season), points, nil
+	default:
+		return linearForecast(points), points, nil
+	}
+}
+
+// linearForecast fits a line on the points with the least squares method.
+// It returns nil if there are less than two points with different timestamps.
+func linearForecast(points []anomalyPoint) forecaster {
+	if len(points) < 2 {
+		return nil
+	}
+	// use the first point as the origin to keep the numbers small
+	origin := points[0].t
+	var sumX, sumY, sumXY, sumX2 float64
+	for _, p := range points {
+		x := p.t.Sub(origin).Seconds()
+		sumX += x
+		sumY += p.v
+		sumXY += x * p.v
+		sumX2 += x * x
+	}
+	n := float64(len(points))
+	denominator := n*sumX2 - sumX*sumX
+	if denominator == 0 {
+		return nil
+	}
+	slope := (n*sumXY - sumX*sumY) / denominator
+	intercept := (sumY - slope*sumX) / n
+	return func(t time.Time) float64 {
+		return intercept + slope*t.Sub(origin).Seconds()
+	}
+}
+
+// holtWintersForecast fits an additive Holt-Winters model on the points. The first season is used to initialize the model.
+// It returns nil if the points do not cover more than one season.
+func holtWintersForecast(points []anomalyPoint, season time.Duration) forecaster {
+	step := medianStep(points)
+	if step <= 0 {
+		return nil
+	}
+	m := int(math.Round(float64(season) / float64(step)))
+	if m < 2 || len(points) <= m {
+		return nil
+	}
+
+	var level float64
+	for _, p := range points[:m] {
+		level += p.v
+	}
+	level /= float64(m)
+	trend := 0.0
+	seasonal := make([]float64, m)
+	for i, p := range points[:m] {
+		seasonal[i] = p.v - level
+	}
+	for i := m; i < len(points); i++ {
+		y := points[i].v
+		si := i % m
+		prevLevel := level
+		level = holtWintersAlpha*(y-seasonal[si]) + (1-holtWintersAlpha)*(level+trend)
+		trend = holtWintersBeta*(level-prevLevel) + (1-holtWintersBeta)*trend
+		seasonal[si] = holtWintersGamma*(y-level) + (1-holtWintersGamma)*seasonal[si]
+	}
+
+	lastIdx := len(points) - 1
+	last := points[lastIdx].t
+	return func(t time.Time) float64 {
+		h := int(math.Round(float64(t.Sub(last)) / float64(step)))
+		if h < 1 {
+			h = 1
+		}
+		return level + float64(h)*trend + seasonal[(lastIdx+h)%m]
+	}
+}
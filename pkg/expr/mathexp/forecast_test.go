@@ -0,0 +1,105 @@
+package mathexp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForecast(t *testing.T) {
+	tests := []struct {
+		name     string
+		series   Series
+		opts     ForecastOptions
+		expected *float64
+	}{
+		{
+			name:     "linear extrapolates the trend",
+			series:   seriesFromValues(nil, 0, 1, 2, 3, 4),
+			opts:     ForecastOptions{Model: ForecastLinear, Horizon: 10 * time.Second},
+			expected: float64Pointer(14),
+		},
+		{
+			name:     "linear fits only the points within the window",
+			series:   seriesFromValues(nil, 100, 100, 0, 1, 2),
+			opts:     ForecastOptions{Model: ForecastLinear, Horizon: 10 * time.Second, Window: 2 * time.Second},
+			expected: float64Pointer(12),
+		},
+		{
+			name:     "linear needs two points",
+			series:   seriesFromValues(nil, 1),
+			opts:     ForecastOptions{Model: ForecastLinear, Horizon: time.Second},
+			expected: nil,
+		},
+		{
+			name:     "seasonal continues the season",
+			series:   seriesFromValues(nil, 0, 10, 0, 10, 0, 10),
+			opts:     ForecastOptions{Model: ForecastSeasonal, Horizon: 2 * time.Second, Season: 2 * time.Second},
+			expected: float64Pointer(10),
+		},
+		{
+			name:     "seasonal forecasts the next phase",
+			series:   seriesFromValues(nil, 0, 10, 0, 10, 0, 10),
+			opts:     ForecastOptions{Model: ForecastSeasonal, Horizon: time.Second, Season: 2 * time.Second},
+			expected: float64Pointer(0),
+		},
+		{
+			name:     "seasonal needs more than one season",
+			series:   seriesFromValues(nil, 0, 10),
+			opts:     ForecastOptions{Model: ForecastSeasonal, Horizon: time.Second, Season: 2 * time.Second},
+			expected: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := tt.series.Forecast(tt.opts)
+			require.NoError(t, err)
+			if tt.expected == nil {
+				require.Nil(t, actual)
+				return
+			}
+			require.NotNil(t, actual)
+			require.InDelta(t, *tt.expected, *actual, 1e-9)
+		})
+	}
+}
+
+func TestForecastSeries(t *testing.T) {
+	t.Run("returns points up to the horizon", func(t *testing.T) {
+		s := seriesFromValues(data.Labels{"host": "a"}, 0, 1, 2, 3, 4)
+		actual, err := s.ForecastSeries("B", ForecastOptions{Model: ForecastLinear, Horizon: 2500 * time.Millisecond})
+		require.NoError(t, err)
+		require.Equal(t, data.Labels{"host": "a"}, actual.GetLabels())
+		require.Equal(t, 3, actual.Len())
+		expected := []struct {
+			t time.Time
+			v float64
+		}{
+			{time.Unix(5, 0), 5},
+			{time.Unix(6, 0), 6},
+			{time.UnixMilli(6500), 6.5},
+		}
+		for i, e := range expected {
+			ts, v := actual.GetPoint(i)
+			require.True(t, e.t.Equal(ts))
+			require.InDelta(t, e.v, *v, 1e-9)
+		}
+	})
+
+	t.Run("returns empty series if there are not enough points", func(t *testing.T) {
+		s := seriesFromValues(nil, 1)
+		actual, err := s.ForecastSeries("B", ForecastOptions{Model: ForecastLinear, Horizon: time.Minute})
+		require.NoError(t, err)
+		require.Equal(t, 0, actual.Len())
+	})
+}
+
+func TestForecastOptionsValidate(t *testing.T) {
+	require.NoError(t, ForecastOptions{Model: ForecastLinear, Horizon: time.Hour}.Validate())
+	require.ErrorContains(t, ForecastOptions{Model: ForecastLinear}.Validate(), "requires a positive horizon")
+	require.ErrorContains(t, ForecastOptions{Model: ForecastSeasonal, Horizon: time.Hour}.Validate(), "requires a positive season")
+	require.ErrorContains(t, ForecastOptions{Model: ForecastLinear, Horizon: time.Hour, Window: -time.Hour}.Validate(), "must not be negative")
+	require.ErrorContains(t, ForecastOptions{Model: "arima", Horizon: time.Hour}.Validate(), "is not supported")
+}
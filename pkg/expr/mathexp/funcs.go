@@ -1,7 +1,11 @@
 package mathexp
 
 import (
+	"fmt"
 	"math"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
 
 	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
 )
@@ -68,6 +72,46 @@ var builtins = map[string]parse.Func{
 		VariantReturn: true,
 		F:             floor,
 	},
+	"exp": {
+		Args:          []parse.ReturnType{parse.TypeVariantSet},
+		VariantReturn: true,
+		F:             exp,
+	},
+	"sqrt": {
+		Args:          []parse.ReturnType{parse.TypeVariantSet},
+		VariantReturn: true,
+		F:             sqrt,
+	},
+	"pow": {
+		Args:          []parse.ReturnType{parse.TypeVariantSet, parse.TypeScalar},
+		VariantReturn: true,
+		F:             pow,
+	},
+	"clamp": {
+		Args:          []parse.ReturnType{parse.TypeVariantSet, parse.TypeScalar, parse.TypeScalar},
+		VariantReturn: true,
+		F:             clamp,
+	},
+	"shift": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet, parse.TypeString},
+		Return: parse.TypeSeriesSet,
+		F:      shift,
+	},
+	"moving_avg": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet, parse.TypeString},
+		Return: parse.TypeSeriesSet,
+		F:      movingAvg,
+	},
+	"cumsum": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet},
+		Return: parse.TypeSeriesSet,
+		F:      cumsum,
+	},
+	"derivative": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet},
+		Return: parse.TypeSeriesSet,
+		F:      derivative,
+	},
 }
 
 // abs returns the absolute value for each result in NumberSet, SeriesSet, or Scalar
@@ -307,3 +351,231 @@ func floor(e *State, varSet Results) (Results, error) {
 	}
 	return newRes, nil
 }
+
+// exp returns e raised to the power of each result in NumberSet, SeriesSet, or Scalar
+func exp(e *State, varSet Results) (Results, error) {
+	newRes := Results{}
+	for _, res := range varSet.Values {
+		newVal, err := perFloat(e, res, math.Exp)
+		if err != nil {
+			return newRes, err
+		}
+		newRes.Values = append(newRes.Values, newVal)
+	}
+	return newRes, nil
+}
+
+// sqrt returns the square root of each result in NumberSet, SeriesSet, or Scalar
+func sqrt(e *State, varSet Results) (Results, error) {
+	newRes := Results{}
+	for _, res := range varSet.Values {
+		newVal, err := perFloat(e, res, math.Sqrt)
+		if err != nil {
+			return newRes, err
+		}
+		newRes.Values = append(newRes.Values, newVal)
+	}
+	return newRes, nil
+}
+
+// pow raises each result in NumberSet, SeriesSet, or Scalar to the power of the scalar exponent
+func pow(e *State, varSet Results, exponent Results) (Results, error) {
+	newRes := Results{}
+	y, err := scalarArg("pow", exponent)
+	if err != nil {
+		return newRes, err
+	}
+	for _, res := range varSet.Values {
+		newVal, err := perFloat(e, res, func(x float64) float64 {
+			return math.Pow(x, y)
+		})
+		if err != nil {
+			return newRes, err
+		}
+		newRes.Values = append(newRes.Values, newVal)
+	}
+	return newRes, nil
+}
+
+// clamp limits each result in NumberSet, SeriesSet, or Scalar to the range between the scalars lo and hi
+func clamp(e *State, varSet Results, loArg Results, hiArg Results) (Results, error) {
+	newRes := Results{}
+	lo, err := scalarArg("clamp", loArg)
+	if err != nil {
+		return newRes, err
+	}
+	hi, err := scalarArg("clamp", hiArg)
+	if err != nil {
+		return newRes, err
+	}
+	if lo > hi {
+		return newRes, fmt.Errorf("clamp: lower bound %v is greater than upper bound %v", lo, hi)
+	}
+	for _, res := range varSet.Values {
+		newVal, err := perFloat(e, res, func(x float64) float64 {
+			if math.IsNaN(x) {
+				return x
+			}
+			return math.Max(lo, math.Min(hi, x))
+		})
+		if err != nil {
+			return newRes, err
+		}
+		newRes.Values = append(newRes.Values, newVal)
+	}
+	return newRes, nil
+}
+
+// shift moves each series in SeriesSet forward in time by the given duration, so that
+// past data can be compared with the current data, e.g. $A - shift($A, 1w).
+func shift(e *State, varSet Results, rawOffset string) (Results, error) {
+	offset, err := durationArg("shift", rawOffset)
+	if err != nil {
+		return Results{}, err
+	}
+	return perSeries(e, "shift", varSet, func(s Series) Series {
+		newSeries := NewSeries(e.RefID, s.GetLabels(), s.Len())
+		for i := 0; i < s.Len(); i++ {
+			t, f := s.GetPoint(i)
+			newSeries.SetPoint(i, t.Add(offset), f)
+		}
+		return newSeries
+	})
+}
+
+// movingAvg replaces each point of each series in SeriesSet with the average of the points
+// in the window that ends at the point's time. Null and NaN values are ignored, and the point
+// is null if there are no other values in the window.
+func movingAvg(e *State, varSet Results, rawWindow string) (Results, error) {
+	window, err := durationArg("moving_avg", rawWindow)
+	if err != nil {
+		return Results{}, err
+	}
+	if window <= 0 {
+		return Results{}, fmt.Errorf("moving_avg: window must be positive, got %v", rawWindow)
+	}
+	return perSeries(e, "moving_avg", varSet, func(s Series) Series {
+		sorted := sortedSeries(e, s)
+		newSeries := NewSeries(e.RefID, s.GetLabels(), sorted.Len())
+		var sum float64
+		var count, start int
+		for i := 0; i < sorted.Len(); i++ {
+			t, f := sorted.GetPoint(i)
+			if f != nil && !math.IsNaN(*f) {
+				sum += *f
+				count++
+			}
+			for ; !sorted.GetTime(start).After(t.Add(-window)); start++ {
+				if v := sorted.GetValue(start); v != nil && !math.IsNaN(*v) {
+					sum -= *v
+					count--
+				}
+			}
+			var avg *float64
+			if count > 0 {
+				v := sum / float64(count)
+				avg = &v
+			}
+			newSeries.SetPoint(i, t, avg)
+		}
+		return newSeries
+	})
+}
+
+// cumsum replaces each point of each series in SeriesSet with the sum of the values up to
+// and including the point. Null values stay null and do not contribute to the sum.
+func cumsum(e *State, varSet Results) (Results, error) {
+	return perSeries(e, "cumsum", varSet, func(s Series) Series {
+		sorted := sortedSeries(e, s)
+		newSeries := NewSeries(e.RefID, s.GetLabels(), sorted.Len())
+		var sum float64
+		for i := 0; i < sorted.Len(); i++ {
+			t, f := sorted.GetPoint(i)
+			if f == nil {
+				newSeries.SetPoint(i, t, nil)
+				continue
+			}
+			sum += *f
+			v := sum
+			newSeries.SetPoint(i, t, &v)
+		}
+		return newSeries
+	})
+}
+
+// derivative replaces each series in SeriesSet with the per-second rate of change between
+// consecutive points. The resulting series has one point less than the original one.
+func derivative(e *State, varSet Results) (Results, error) {
+	return perSeries(e, "derivative", varSet, func(s Series) Series {
+		sorted := sortedSeries(e, s)
+		newSeries := NewSeries(e.RefID, s.GetLabels(), 0)
+		for i := 1; i < sorted.Len(); i++ {
+			prevT, prevF := sorted.GetPoint(i - 1)
+			t, f := sorted.GetPoint(i)
+			seconds := t.Sub(prevT).Seconds()
+			if seconds == 0 {
+				continue
+			}
+			var d *float64
+			if f != nil && prevF != nil {
+				v := (*f - *prevF) / seconds
+				d = &v
+			}
+			newSeries.AppendPoint(t, d)
+		}
+		return newSeries
+	})
+}
+
+// perSeries passes each Series in varSet to seriesF. NoData is passed through, and other types
+// result in an error because the function depends on the time of the points.
+func perSeries(e *State, name string, varSet Results, seriesF func(s Series) Series) (Results, error) {
+	newRes := Results{}
+	for _, res := range varSet.Values {
+		switch v := res.(type) {
+		case Series:
+			newRes.Values = append(newRes.Values, seriesF(v))
+		case NoData:
+			newRes.Values = append(newRes.Values, NewNoData())
+		default:
+			return newRes, fmt.Errorf("%s: can only be applied to series, got type %v", name, res.Type())
+		}
+	}
+	return newRes, nil
+}
+
+// sortedSeries returns a copy of the series sorted by time from oldest to newest.
+func sortedSeries(e *State, s Series) Series {
+	sorted := NewSeries(e.RefID, s.GetLabels(), s.Len())
+	for i := 0; i < s.Len(); i++ {
+		t, f := s.GetPoint(i)
+		sorted.SetPoint(i, t, f)
+	}
+	sorted.SortByTime(false)
+	return sorted
+}
+
+// scalarArg returns the value of a function argument of type Scalar.
+func scalarArg(name string, res Results) (float64, error) {
+	if len(res.Values) != 1 {
+		return 0, fmt.Errorf("%s: expected a single scalar argument, got %d values", name, len(res.Values))
+	}
+	s, ok := res.Values[0].(Scalar)
+	if !ok {
+		return 0, fmt.Errorf("%s: expected a scalar argument, got type %v", name, res.Values[0].Type())
+	}
+	f := s.GetFloat64Value()
+	if f == nil {
+		return 0, fmt.Errorf("%s: scalar argument must not be null", name)
+	}
+	return *f, nil
+}
+
+// durationArg parses a function argument such as 5m or "1h".
+func durationArg(name string, raw string) (time.Duration, error) {
+	d, err := gtime.ParseDuration(raw)
+	if err != nil {
+		return 0, fmt.Errorf("%s: invalid duration %q: %w", name, raw, err)
+	}
+	return d, nil
+}
@@ -140,3 +140,208 @@ func TestIsNumberFunc(t *testing.T) {
 		})
 	}
 }
+
+func TestSeriesFuncs(t *testing.T) {
+	var tests = []struct {
+		name      string
+		expr      string
+		vars      Vars
+		newErrIs  require.ErrorAssertionFunc
+		execErrIs require.ErrorAssertionFunc
+		results   Results
+	}{
+		{
+			name: "shift series by a duration",
+			expr: "shift($A, 1h)",
+			vars: Vars{
+				"A": resultValuesNoErr(
+					makeSeries("", nil,
+						tp{time.Unix(0, 0), float64Pointer(1)},
+						tp{time.Unix(60, 0), float64Pointer(2)}),
+				),
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(3600, 0), float64Pointer(1)},
+					tp{time.Unix(3660, 0), float64Pointer(2)}),
+			),
+		},
+		{
+			name: "shift series by a quoted duration",
+			expr: `shift($A, "1m")`,
+			vars: Vars{
+				"A": resultValuesNoErr(
+					makeSeries("", nil, tp{time.Unix(0, 0), float64Pointer(1)}),
+				),
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", nil, tp{time.Unix(60, 0), float64Pointer(1)}),
+			),
+		},
+		{
+			name: "shift number should error",
+			expr: "shift($A, 1h)",
+			vars: Vars{
+				"A": resultValuesNoErr(makeNumber("", nil, float64Pointer(1))),
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.Error,
+		},
+		{
+			name:     "shift with invalid duration should error",
+			expr:     `shift($A, "soon")`,
+			vars:     Vars{},
+			newErrIs: require.NoError,
+			execErrIs: func(t require.TestingT, err error, _ ...any) {
+				require.ErrorContains(t, err, "invalid duration")
+			},
+		},
+		{
+			name:     "shift without duration should error",
+			expr:     "shift($A)",
+			newErrIs: require.Error,
+		},
+		{
+			name: "moving_avg ignores null values",
+			expr: "moving_avg($A, 20s)",
+			vars: Vars{
+				"A": resultValuesNoErr(
+					makeSeries("", nil,
+						tp{time.Unix(10, 0), float64Pointer(1)},
+						tp{time.Unix(20, 0), float64Pointer(3)},
+						tp{time.Unix(30, 0), nil},
+						tp{time.Unix(40, 0), float64Pointer(7)},
+						tp{time.Unix(50, 0), nil},
+						tp{time.Unix(60, 0), nil}),
+				),
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(10, 0), float64Pointer(1)},
+					tp{time.Unix(20, 0), float64Pointer(2)},
+					tp{time.Unix(30, 0), float64Pointer(3)},
+					tp{time.Unix(40, 0), float64Pointer(7)},
+					tp{time.Unix(50, 0), float64Pointer(7)},
+					tp{time.Unix(60, 0), nil}),
+			),
+		},
+		{
+			name: "cumsum sorts series by time",
+			expr: "cumsum($A)",
+			vars: Vars{
+				"A": resultValuesNoErr(
+					makeSeries("", nil,
+						tp{time.Unix(20, 0), float64Pointer(2)},
+						tp{time.Unix(10, 0), float64Pointer(1)},
+						tp{time.Unix(30, 0), nil},
+						tp{time.Unix(40, 0), float64Pointer(3)}),
+				),
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(10, 0), float64Pointer(1)},
+					tp{time.Unix(20, 0), float64Pointer(3)},
+					tp{time.Unix(30, 0), nil},
+					tp{time.Unix(40, 0), float64Pointer(6)}),
+			),
+		},
+		{
+			name: "derivative is per second",
+			expr: "derivative($A)",
+			vars: Vars{
+				"A": resultValuesNoErr(
+					makeSeries("", nil,
+						tp{time.Unix(0, 0), float64Pointer(10)},
+						tp{time.Unix(10, 0), float64Pointer(30)},
+						tp{time.Unix(20, 0), float64Pointer(20)}),
+				),
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(10, 0), float64Pointer(2)},
+					tp{time.Unix(20, 0), float64Pointer(-1)}),
+			),
+		},
+		{
+			name: "clamp series",
+			expr: "clamp($A, -1, 1)",
+			vars: Vars{
+				"A": resultValuesNoErr(
+					makeSeries("", nil,
+						tp{time.Unix(0, 0), float64Pointer(-5)},
+						tp{time.Unix(10, 0), float64Pointer(0.5)},
+						tp{time.Unix(20, 0), float64Pointer(5)}),
+				),
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(0, 0), float64Pointer(-1)},
+					tp{time.Unix(10, 0), float64Pointer(0.5)},
+					tp{time.Unix(20, 0), float64Pointer(1)}),
+			),
+		},
+		{
+			name:      "clamp with inverted bounds should error",
+			expr:      "clamp(5, 1, -1)",
+			vars:      Vars{},
+			newErrIs:  require.NoError,
+			execErrIs: require.Error,
+		},
+		{
+			name:      "pow on scalar",
+			expr:      "pow(2, 10)",
+			vars:      Vars{},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results:   resultValuesNoErr(NewScalar("", float64Pointer(1024))),
+		},
+		{
+			name: "sqrt on number",
+			expr: "sqrt($A)",
+			vars: Vars{
+				"A": resultValuesNoErr(makeNumber("", nil, float64Pointer(9))),
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results:   resultValuesNoErr(makeNumber("", nil, float64Pointer(3))),
+		},
+		{
+			name:      "exp on scalar",
+			expr:      "exp(0)",
+			vars:      Vars{},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results:   resultValuesNoErr(NewScalar("", float64Pointer(1))),
+		},
+		{
+			name:     "duration outside of a function should error",
+			expr:     "$A + 1h",
+			newErrIs: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New(tt.expr)
+			tt.newErrIs(t, err)
+			if e != nil {
+				res, err := e.Execute("", tt.vars, tracing.InitializeTracerForTest())
+				tt.execErrIs(t, err)
+				if err == nil {
+					require.Equal(t, tt.results, res)
+				}
+			}
+		})
+	}
+}
@@ -35,6 +35,9 @@ var itemName = map[itemType]string{
 	itemRightParen: ")",
 	itemString:     "string",
 	itemFunc:       "func",
+	itemVar:        "var",
+	itemPow:        "**",
+	itemDuration:   "duration",
 }
 
 func (i itemType) String() string {
@@ -99,6 +102,21 @@ var lexTests = []lexTest{
 		{itemNumber, 0, "1.2e-4"},
 		tEOF,
 	}},
+	{"durations", "5m 1h30m 7d", []item{
+		{itemDuration, 0, "5m"},
+		{itemDuration, 0, "1h30m"},
+		{itemDuration, 0, "7d"},
+		tEOF,
+	}},
+	{"function with duration", "shift($A, 1w)", []item{
+		{itemFunc, 0, "shift"},
+		{itemLeftParen, 0, "("},
+		{itemVar, 0, "$A"},
+		{itemComma, 0, ","},
+		{itemDuration, 0, "1w"},
+		{itemRightParen, 0, ")"},
+		tEOF,
+	}},
 	{"curly brace var", "${My Var}", []item{
 		{itemVar, 0, "${My Var}"},
 		tEOF,
@@ -177,7 +177,7 @@ E -> F {( "**" ) F}
 F -> v | "(" O ")" | "!" O | "-" O
 v -> number | func(..) | queryVar
 Func -> name "(" param {"," param} ")"
-param -> number | "string" | queryVar
+param -> number | "string" | duration | queryVar
 */
 
 // expr:
@@ -332,6 +332,9 @@ func (t *Tree) Func() (f *FuncNode) {
 				t.errorf("Unquoting error: %s", err)
 			}
 			f.append(newString(token.pos, token.val, s))
+		case itemDuration:
+			// Durations are passed to functions as strings, so 5m is the same as "5m".
+			f.append(newString(token.pos, token.val, token.val))
 		case itemRightParen:
 			return
 		}
@@ -4,12 +4,17 @@ import (
 	"fmt"
 	"math"
 	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 )
 
 type ReducerFunc = func(fv *Float64Field) *float64
 
+// SeriesReducerFunc is a reducer that needs the timestamps of the series in addition to its values.
+type SeriesReducerFunc = func(s Series) *float64
+
 // The reducer function
 // +enum
 type ReducerID string
@@ -22,11 +27,42 @@ const (
 	ReducerCount  ReducerID = "count"
 	ReducerLast   ReducerID = "last"
 	ReducerMedian ReducerID = "median"
+	ReducerFirst  ReducerID = "first"
+	ReducerStdDev ReducerID = "stddev"
+	ReducerRange  ReducerID = "range"
+	ReducerDiff   ReducerID = "diff"
+	ReducerDelta  ReducerID = "delta"
+	ReducerRate   ReducerID = "rate"
+
+	ReducerPercentile ReducerID = "percentile"
 )
 
-// GetSupportedReduceFuncs returns collection of supported function names
+// GetSupportedReduceFuncs returns collection of supported function names.
+// ReducerPercentile is not listed because it requires an argument, see PercentileReducer.
 func GetSupportedReduceFuncs() []ReducerID {
-	return []ReducerID{ReducerSum, ReducerMean, ReducerMin, ReducerMax, ReducerCount, ReducerLast, ReducerMedian}
+	return []ReducerID{ReducerSum, ReducerMean, ReducerMin, ReducerMax, ReducerCount, ReducerLast, ReducerMedian,
+		ReducerFirst, ReducerStdDev, ReducerRange, ReducerDiff, ReducerDelta, ReducerRate}
+}
+
+// PercentileReducer returns the ID of the reducer that calculates the given percentile, e.g. "p95" or "p99.9".
+func PercentileReducer(percentile float64) (ReducerID, error) {
+	if math.IsNaN(percentile) || percentile < 0 || percentile > 100 {
+		return "", fmt.Errorf("percentile must be between 0 and 100, got %v", percentile)
+	}
+	return ReducerID("p" + strconv.FormatFloat(percentile, 'f', -1, 64)), nil
+}
+
+// ParsePercentile returns the percentile encoded in reducer IDs such as "p95", see PercentileReducer.
+func ParsePercentile(rFunc ReducerID) (float64, bool) {
+	s, ok := strings.CutPrefix(string(rFunc), "p")
+	if !ok || s == "" {
+		return 0, false
+	}
+	p, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(p) || p < 0 || p > 100 {
+		return 0, false
+	}
+	return p, true
 }
 
 func Sum(fv *Float64Field) *float64 {
@@ -126,6 +162,119 @@ func Median(fv *Float64Field) *float64 {
 	}
 }
 
+// values returns all values of the field, or false if any of them is nil or NaN.
+func (ff *Float64Field) values() ([]float64, bool) {
+	values := make([]float64, 0, ff.Len())
+	for i := 0; i < ff.Len(); i++ {
+		v := ff.GetValue(i)
+		if v == nil || math.IsNaN(*v) {
+			return nil, false
+		}
+		values = append(values, *v)
+	}
+	return values, true
+}
+
+func First(fv *Float64Field) *float64 {
+	var f float64
+	if fv.Len() == 0 {
+		f = math.NaN()
+		return &f
+	}
+	return fv.GetValue(0)
+}
+
+// StdDev calculates the population standard deviation.
+func StdDev(fv *Float64Field) *float64 {
+	values, ok := fv.values()
+	if !ok || len(values) == 0 {
+		nan := math.NaN()
+		return &nan
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+	var variance float64
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	f := math.Sqrt(variance / float64(len(values)))
+	return &f
+}
+
+// Range calculates the difference between the maximum and the minimum.
+func Range(fv *Float64Field) *float64 {
+	minV, maxV := Min(fv), Max(fv)
+	f := *maxV - *minV
+	return &f
+}
+
+// Diff calculates the difference between the last and the first value.
+func Diff(fv *Float64Field) *float64 {
+	values, ok := fv.values()
+	if !ok || len(values) == 0 {
+		nan := math.NaN()
+		return &nan
+	}
+	f := values[len(values)-1] - values[0]
+	return &f
+}
+
+// Delta calculates the total increase of a counter. A value lower than the previous one is
+// treated as a counter reset, and the value itself is counted as the increase.
+func Delta(fv *Float64Field) *float64 {
+	values, ok := fv.values()
+	if !ok || len(values) == 0 {
+		nan := math.NaN()
+		return &nan
+	}
+	var f float64
+	for i := 1; i < len(values); i++ {
+		if values[i] >= values[i-1] {
+			f += values[i] - values[i-1]
+		} else {
+			f += values[i]
+		}
+	}
+	return &f
+}
+
+// Rate calculates the per-second increase of a counter, see Delta.
+// It returns NaN if the series has less than two points.
+func Rate(s Series) *float64 {
+	nan := math.NaN()
+	if s.Len() < 2 {
+		return &nan
+	}
+	seconds := s.GetTime(s.Len() - 1).Sub(s.GetTime(0)).Seconds()
+	if seconds <= 0 {
+		return &nan
+	}
+	ff := Float64Field(*s.Frame.Fields[seriesTypeValIdx])
+	f := *Delta(&ff) / seconds
+	return &f
+}
+
+// Percentile returns a reducer that calculates the given percentile (0-100)
+// using linear interpolation between the closest ranks.
+func Percentile(percentile float64) ReducerFunc {
+	return func(fv *Float64Field) *float64 {
+		values, ok := fv.values()
+		if !ok || len(values) == 0 {
+			nan := math.NaN()
+			return &nan
+		}
+		sort.Float64s(values)
+		rank := percentile / 100 * float64(len(values)-1)
+		lower := int(math.Floor(rank))
+		upper := int(math.Ceil(rank))
+		f := values[lower] + (values[upper]-values[lower])*(rank-float64(lower))
+		return &f
+	}
+}
+
 func GetReduceFunc(rFunc ReducerID) (ReducerFunc, error) {
 	switch rFunc {
 	case ReducerSum:
@@ -142,11 +291,43 @@ func GetReduceFunc(rFunc ReducerID) (ReducerFunc, error) {
 		return Last, nil
 	case ReducerMedian:
 		return Median, nil
+	case ReducerFirst:
+		return First, nil
+	case ReducerStdDev:
+		return StdDev, nil
+	case ReducerRange:
+		return Range, nil
+	case ReducerDiff:
+		return Diff, nil
+	case ReducerDelta:
+		return Delta, nil
+	case ReducerRate:
+		return nil, fmt.Errorf("reduction %v requires a time series", rFunc)
+	case ReducerPercentile:
+		return nil, fmt.Errorf("reduction %v requires an argument, for example p95", rFunc)
 	default:
+		if p, ok := ParsePercentile(rFunc); ok {
+			return Percentile(p), nil
+		}
 		return nil, fmt.Errorf("reduction %v not implemented", rFunc)
 	}
 }
 
+// GetSeriesReduceFunc is like GetReduceFunc but also supports reducers that depend on the timestamps of the series.
+func GetSeriesReduceFunc(rFunc ReducerID) (SeriesReducerFunc, error) {
+	if rFunc == ReducerRate {
+		return Rate, nil
+	}
+	reduceFunc, err := GetReduceFunc(rFunc)
+	if err != nil {
+		return nil, err
+	}
+	return func(s Series) *float64 {
+		ff := Float64Field(*s.Frame.Fields[seriesTypeValIdx])
+		return reduceFunc(&ff)
+	}, nil
+}
+
 // Reduce turns the Series into a Number based on the given reduction function
 // if ReduceMapper is defined it applies it to the provided series and performs reduction of the resulting series.
 // Otherwise, the reduction operation is done against the original series.
@@ -161,13 +342,11 @@ func (s Series) Reduce(refID string, rFunc ReducerID, mapper ReduceMapper) (Numb
 	if mapper != nil {
 		series = mapSeries(s, mapper)
 	}
-	fVec := series.Frame.Fields[seriesTypeValIdx]
-	floatField := Float64Field(*fVec)
-	reduceFunc, err := GetReduceFunc(rFunc)
+	reduceFunc, err := GetSeriesReduceFunc(rFunc)
 	if err != nil {
 		return number, fmt.Errorf("invalid expression '%s': %w", refID, err)
 	}
-	f = reduceFunc(&floatField)
+	f = reduceFunc(series)
 	if f != nil && mapper != nil {
 		f = mapper.MapOutput(f)
 	}
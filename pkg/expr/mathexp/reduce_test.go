@@ -28,6 +28,18 @@ var seriesEmpty = Vars{
 	),
 }
 
+var counterSeries = Vars{
+	"A": resultValuesNoErr(
+		makeSeries("temp", nil,
+			tp{time.Unix(0, 0), float64Pointer(1)},
+			tp{time.Unix(10, 0), float64Pointer(3)},
+			tp{time.Unix(20, 0), float64Pointer(6)},
+			tp{time.Unix(30, 0), float64Pointer(2)},
+			tp{time.Unix(40, 0), float64Pointer(4)},
+		),
+	),
+}
+
 func TestSeriesReduce(t *testing.T) {
 	var tests = []struct {
 		name        string
@@ -274,6 +286,157 @@ func TestSeriesReduce(t *testing.T) {
 			resultsIs:   require.Equal,
 			results:     resultValuesNoErr(makeNumber("", nil, nil)),
 		},
+		{
+			name:        "first series",
+			red:         "first",
+			varToReduce: "A",
+			vars:        aSeries,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(2))),
+		},
+		{
+			name:        "first empty series",
+			red:         "first",
+			varToReduce: "A",
+			vars:        seriesEmpty,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, NaN)),
+		},
+		{
+			name:        "stddev series",
+			red:         "stddev",
+			varToReduce: "A",
+			vars:        aSeries,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(0.5))),
+		},
+		{
+			name:        "stddev series with a nil value",
+			red:         "stddev",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, NaN)),
+		},
+		{
+			name:        "range series",
+			red:         "range",
+			varToReduce: "A",
+			vars:        counterSeries,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(5))),
+		},
+		{
+			name:        "range empty series",
+			red:         "range",
+			varToReduce: "A",
+			vars:        seriesEmpty,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, NaN)),
+		},
+		{
+			name:        "diff series",
+			red:         "diff",
+			varToReduce: "A",
+			vars:        counterSeries,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(3))),
+		},
+		{
+			name:        "delta series with a counter reset",
+			red:         "delta",
+			varToReduce: "A",
+			vars:        counterSeries,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(9))),
+		},
+		{
+			name:        "delta series with a nil value",
+			red:         "delta",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, NaN)),
+		},
+		{
+			name:        "rate series with a counter reset",
+			red:         "rate",
+			varToReduce: "A",
+			vars:        counterSeries,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(0.225))),
+		},
+		{
+			name:        "rate series with a nil value",
+			red:         "rate",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, NaN)),
+		},
+		{
+			name:        "p50 series",
+			red:         "p50",
+			varToReduce: "A",
+			vars:        counterSeries,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(3))),
+		},
+		{
+			name:        "p75 series",
+			red:         "p75",
+			varToReduce: "A",
+			vars:        counterSeries,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(4))),
+		},
+		{
+			name:        "p100 series",
+			red:         "p100",
+			varToReduce: "A",
+			vars:        counterSeries,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(6))),
+		},
+		{
+			name:        "p95 empty series",
+			red:         "p95",
+			varToReduce: "A",
+			vars:        seriesEmpty,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, NaN)),
+		},
+		{
+			name:        "percentile without an argument will error",
+			red:         "percentile",
+			varToReduce: "A",
+			vars:        aSeries,
+			errIs:       require.Error,
+			resultsIs:   require.Equal,
+		},
+		{
+			name:        "p101 reduction will error",
+			red:         "p101",
+			varToReduce: "A",
+			vars:        aSeries,
+			errIs:       require.Error,
+			resultsIs:   require.Equal,
+		},
 	}
 
 	for _, tt := range tests {
@@ -299,6 +462,22 @@ func TestSeriesReduce(t *testing.T) {
 	}
 }
 
+func TestPercentileReducer(t *testing.T) {
+	id, err := PercentileReducer(99.9)
+	require.NoError(t, err)
+	require.Equal(t, ReducerID("p99.9"), id)
+
+	p, ok := ParsePercentile(id)
+	require.True(t, ok)
+	require.Equal(t, 99.9, p)
+
+	_, err = PercentileReducer(-1)
+	require.Error(t, err)
+
+	_, ok = ParsePercentile(ReducerPercentile)
+	require.False(t, ok)
+}
+
 var seriesNonNumbers = Vars{
 	"A": resultValuesNoErr(
 		makeSeries("temp", nil,
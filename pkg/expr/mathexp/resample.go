@@ -20,35 +20,99 @@ const (
 
 	// Do not fill values (nill)
 	UpsamplerFillNA Upsampler = "fillna"
+
+	// Linear interpolation between the last seen and the next value
+	UpsamplerLinear Upsampler = "linear"
+)
+
+// The alignment of the samples
+// +enum
+type ResampleAlignment string
+
+const (
+	// Samples start at the beginning of the time range
+	ResampleAlignStart ResampleAlignment = ""
+
+	// Samples start at hour and day boundaries in the given timezone
+	ResampleAlignCalendar ResampleAlignment = "calendar"
 )
 
+// ResampleOptions configures Series.ResampleWithOptions.
+type ResampleOptions struct {
+	Interval    time.Duration
+	Downsampler ReducerID
+	Upsampler   Upsampler
+	Alignment   ResampleAlignment
+	// Location is the timezone of the calendar boundaries when Alignment is ResampleAlignCalendar. Defaults to UTC.
+	Location *time.Location
+}
+
+// resampleBucket is a sample of the resampled series and the end of the window it covers.
+type resampleBucket struct {
+	t   time.Time
+	end time.Time
+}
+
 // Resample turns the Series into a Number based on the given reduction function
 func (s Series) Resample(refID string, interval time.Duration, downsampler ReducerID, upsampler Upsampler, from, to time.Time) (Series, error) {
-	newSeriesLength := int(float64(to.Sub(from).Nanoseconds()) / float64(interval.Nanoseconds()))
-	if newSeriesLength <= 0 {
-		return s, fmt.Errorf("the series cannot be sampled further; the time range is shorter than the interval")
+	return s.ResampleWithOptions(refID, ResampleOptions{
+		Interval:    interval,
+		Downsampler: downsampler,
+		Upsampler:   upsampler,
+	}, from, to)
+}
+
+// ResampleWithOptions resamples the Series to a consistent interval.
+// By default, samples start at from and every sample holds the values after the previous sample up to its own time.
+// With ResampleAlignCalendar, samples start at calendar boundaries and every sample holds the values from its own time
+// up to the next sample, so a daily sample holds the values of that day.
+func (s Series) ResampleWithOptions(refID string, opts ResampleOptions, from, to time.Time) (Series, error) {
+	var buckets []resampleBucket
+	var err error
+	switch opts.Alignment {
+	case ResampleAlignStart:
+		buckets, err = startAlignedBuckets(opts.Interval, from, to)
+	case ResampleAlignCalendar:
+		buckets, err = calendarAlignedBuckets(opts.Interval, opts.Location, from, to)
+	default:
+		err = fmt.Errorf("alignment %v not implemented", opts.Alignment)
+	}
+	if err != nil {
+		return s, err
 	}
-	resampled := NewSeries(refID, s.GetLabels(), newSeriesLength+1)
+	// windows are (previous sample, sample] unless aligned to the calendar, then they are [sample, next sample)
+	calendar := opts.Alignment == ResampleAlignCalendar
+	inWindow := func(st time.Time, b resampleBucket) bool {
+		if calendar {
+			return st.Before(b.end)
+		}
+		return !st.After(b.end)
+	}
+
+	resampled := NewSeries(refID, s.GetLabels(), len(buckets))
 	bookmark := 0
 	var lastSeen *float64
-	idx := 0
-	t := from
-	for !t.After(to) && idx <= newSeriesLength {
+	var lastSeenTime time.Time
+	for idx, b := range buckets {
 		vals := make([]*float64, 0)
 		sIdx := bookmark
 		for sIdx != s.Len() {
 			st, v := s.GetPoint(sIdx)
-			if st.After(t) {
+			if !inWindow(st, b) {
 				break
 			}
 			bookmark++
 			sIdx++
 			lastSeen = v
+			lastSeenTime = st
+			if calendar && st.Before(b.t) { // before the first sample
+				continue
+			}
 			vals = append(vals, v)
 		}
 		var value *float64
 		if len(vals) == 0 { // upsampling
-			switch upsampler {
+			switch opts.Upsampler {
 			case UpsamplerPad:
 				if lastSeen != nil {
 					value = lastSeen
@@ -63,34 +127,104 @@ func (s Series) Resample(refID string, interval time.Duration, downsampler Reduc
 				}
 			case UpsamplerFillNA:
 				value = nil
+			case UpsamplerLinear:
+				if lastSeen != nil && sIdx != s.Len() {
+					nextTime, next := s.GetPoint(sIdx)
+					value = interpolate(lastSeenTime, *lastSeen, nextTime, next, b.t)
+				}
 			default:
-				return s, fmt.Errorf("upsampling %v not implemented", upsampler)
+				return s, fmt.Errorf("upsampling %v not implemented", opts.Upsampler)
 			}
-		} else if len(vals) == 1 {
+		} else if len(vals) == 1 && opts.Downsampler != ReducerCount {
 			value = vals[0]
 		} else { // downsampling
+			reduceFunc, err := GetReduceFunc(opts.Downsampler)
+			if err != nil {
+				return s, fmt.Errorf("downsampling %v not implemented", opts.Downsampler)
+			}
 			fVec := data.NewField("", s.GetLabels(), vals)
 			ff := Float64Field(*fVec)
-			var tmp *float64
-			switch downsampler {
-			case ReducerSum:
-				tmp = Sum(&ff)
-			case ReducerMean:
-				tmp = Avg(&ff)
-			case ReducerMin:
-				tmp = Min(&ff)
-			case ReducerMax:
-				tmp = Max(&ff)
-			case ReducerLast:
-				tmp = Last(&ff)
-			default:
-				return s, fmt.Errorf("downsampling %v not implemented", downsampler)
-			}
-			value = tmp
+			value = reduceFunc(&ff)
 		}
-		resampled.SetPoint(idx, t, value)
-		t = t.Add(interval)
-		idx++
+		resampled.SetPoint(idx, b.t, value)
 	}
 	return resampled, nil
 }
+
+// interpolate returns the value at t on the line between the two points, or nil if the next value is nil.
+func interpolate(prevTime time.Time, prev float64, nextTime time.Time, next *float64, t time.Time) *float64 {
+	if next == nil {
+		return nil
+	}
+	span := nextTime.Sub(prevTime)
+	if span <= 0 {
+		return next
+	}
+	f := prev + (*next-prev)*float64(t.Sub(prevTime))/float64(span)
+	return &f
+}
+
+func startAlignedBuckets(interval time.Duration, from, to time.Time) ([]resampleBucket, error) {
+	newSeriesLength := int(float64(to.Sub(from).Nanoseconds()) / float64(interval.Nanoseconds()))
+	if newSeriesLength <= 0 {
+		return nil, fmt.Errorf("the series cannot be sampled further; the time range is shorter than the interval")
+	}
+	buckets := make([]resampleBucket, 0, newSeriesLength+1)
+	for t := from; !t.After(to) && len(buckets) <= newSeriesLength; t = t.Add(interval) {
+		buckets = append(buckets, resampleBucket{t: t, end: t})
+	}
+	return buckets, nil
+}
+
+// calendarAlignedBuckets returns the samples at the calendar boundaries of the interval in the location that cover [from, to).
+// The interval must either divide a day, in which case the samples restart at every midnight, or be a whole number of days,
+// in which case the samples start at the midnight before from.
+func calendarAlignedBuckets(interval time.Duration, loc *time.Location, from, to time.Time) ([]resampleBucket, error) {
+	const day = 24 * time.Hour
+	if interval <= 0 || (day%interval != 0 && interval%day != 0) {
+		return nil, fmt.Errorf("the interval %v cannot be aligned to the calendar; it must divide a day or be a whole number of days", interval)
+	}
+	if !from.Before(to) {
+		return nil, fmt.Errorf("the series cannot be sampled further; the time range is empty")
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	midnight := func(t time.Time) time.Time {
+		y, m, d := t.In(loc).Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, loc)
+	}
+
+	var next func(t time.Time) time.Time
+	start := midnight(from)
+	if interval < day {
+		// boundaries are wall clock times, so they stay at the same time of the day when the clock changes
+		sinceMidnight := func(t time.Time) time.Duration {
+			h, m, sec := t.In(loc).Clock()
+			return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(sec)*time.Second
+		}
+		atWallClock := func(t time.Time, d time.Duration) time.Time {
+			y, m, dd := t.In(loc).Date()
+			return time.Date(y, m, dd, 0, 0, int(d/time.Second), 0, loc)
+		}
+		start = atWallClock(from, sinceMidnight(from).Truncate(interval))
+		next = func(t time.Time) time.Time {
+			n := atWallClock(t, sinceMidnight(t)+interval)
+			if !n.After(t) { // the wall clock time does not exist
+				n = t.Add(interval)
+			}
+			return n
+		}
+	} else {
+		days := int(interval / day)
+		next = func(t time.Time) time.Time {
+			return t.AddDate(0, 0, days)
+		}
+	}
+
+	var buckets []resampleBucket
+	for t := start; t.Before(to); t = next(t) {
+		buckets = append(buckets, resampleBucket{t: t, end: next(t)})
+	}
+	return buckets, nil
+}
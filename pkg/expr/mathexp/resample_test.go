@@ -290,3 +290,106 @@ func TestResampleSeries(t *testing.T) {
 		})
 	}
 }
+
+func TestResampleWithOptions(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	t.Run("linear upsampling interpolates between the points around the sample", func(t *testing.T) {
+		s := makeSeries("", nil,
+			tp{time.Unix(0, 0), float64Pointer(0)},
+			tp{time.Unix(4, 0), float64Pointer(8)},
+		)
+		resampled, err := s.ResampleWithOptions("", ResampleOptions{
+			Interval:    time.Second,
+			Downsampler: ReducerMean,
+			Upsampler:   UpsamplerLinear,
+		}, time.Unix(0, 0), time.Unix(5, 0))
+		require.NoError(t, err)
+		assert.Equal(t, makeSeries("", nil,
+			tp{time.Unix(0, 0), float64Pointer(0)},
+			tp{time.Unix(1, 0), float64Pointer(2)},
+			tp{time.Unix(2, 0), float64Pointer(4)},
+			tp{time.Unix(3, 0), float64Pointer(6)},
+			tp{time.Unix(4, 0), float64Pointer(8)},
+			tp{time.Unix(5, 0), nil},
+		), resampled)
+	})
+
+	t.Run("count downsampling counts single values", func(t *testing.T) {
+		s := makeSeries("", nil,
+			tp{time.Unix(1, 0), float64Pointer(5)},
+			tp{time.Unix(3, 0), float64Pointer(5)},
+			tp{time.Unix(4, 0), float64Pointer(5)},
+		)
+		resampled, err := s.ResampleWithOptions("", ResampleOptions{
+			Interval:    2 * time.Second,
+			Downsampler: ReducerCount,
+			Upsampler:   UpsamplerFillNA,
+		}, time.Unix(0, 0), time.Unix(4, 0))
+		require.NoError(t, err)
+		assert.Equal(t, makeSeries("", nil,
+			tp{time.Unix(0, 0), nil},
+			tp{time.Unix(2, 0), float64Pointer(1)},
+			tp{time.Unix(4, 0), float64Pointer(2)},
+		), resampled)
+	})
+
+	t.Run("calendar alignment sums values per day in the timezone", func(t *testing.T) {
+		day := func(d, h int) time.Time {
+			return time.Date(2024, 3, d, h, 0, 0, 0, berlin)
+		}
+		s := makeSeries("", nil,
+			tp{day(29, 23), float64Pointer(100)}, // before the first sample
+			tp{day(30, 0), float64Pointer(1)},
+			tp{day(30, 12), float64Pointer(2)},
+			tp{day(30, 23), float64Pointer(3)},
+			tp{day(31, 0), float64Pointer(4)},
+			tp{day(31, 23), float64Pointer(5)},
+		)
+		resampled, err := s.ResampleWithOptions("", ResampleOptions{
+			Interval:    24 * time.Hour,
+			Downsampler: ReducerSum,
+			Upsampler:   UpsamplerFillNA,
+			Alignment:   ResampleAlignCalendar,
+			Location:    berlin,
+		}, day(30, 6), day(31, 12))
+		require.NoError(t, err)
+		require.Equal(t, 2, resampled.Len())
+		require.True(t, day(30, 0).Equal(resampled.GetTime(0)))
+		require.Equal(t, float64Pointer(6), resampled.GetValue(0))
+		// the clock changes on March 31st, so the day is 23 hours long
+		require.True(t, day(31, 0).Equal(resampled.GetTime(1)))
+		require.Equal(t, float64Pointer(9), resampled.GetValue(1))
+	})
+
+	t.Run("calendar alignment starts at the hour", func(t *testing.T) {
+		s := makeSeries("", nil,
+			tp{time.Unix(3600, 0), float64Pointer(1)},
+			tp{time.Unix(5400, 0), float64Pointer(2)},
+			tp{time.Unix(7200, 0), float64Pointer(3)},
+		)
+		resampled, err := s.ResampleWithOptions("", ResampleOptions{
+			Interval:    time.Hour,
+			Downsampler: ReducerLast,
+			Upsampler:   UpsamplerPad,
+			Alignment:   ResampleAlignCalendar,
+		}, time.Unix(4000, 0), time.Unix(10000, 0))
+		require.NoError(t, err)
+		assert.Equal(t, makeSeries("", nil,
+			tp{time.Unix(3600, 0).In(time.UTC), float64Pointer(2)},
+			tp{time.Unix(7200, 0).In(time.UTC), float64Pointer(3)},
+		), resampled)
+	})
+
+	t.Run("calendar alignment fails when the interval does not fit into a day", func(t *testing.T) {
+		s := makeSeries("", nil, tp{time.Unix(0, 0), float64Pointer(1)})
+		_, err := s.ResampleWithOptions("", ResampleOptions{
+			Interval:    7 * time.Hour,
+			Downsampler: ReducerSum,
+			Upsampler:   UpsamplerFillNA,
+			Alignment:   ResampleAlignCalendar,
+		}, time.Unix(0, 0), time.Unix(86400, 0))
+		require.ErrorContains(t, err, "cannot be aligned to the calendar")
+	})
+}
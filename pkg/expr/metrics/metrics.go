@@ -12,6 +12,8 @@ type ExprMetrics struct {
 	SqlCommandDuration      *prometheus.HistogramVec
 	SqlCommandErrorCount    *prometheus.CounterVec
 	SqlCommandCellCount     *prometheus.HistogramVec
+	SqlCommandLimitExceeded *prometheus.CounterVec
+	SqlCommandInputRowCount *prometheus.HistogramVec
 }
 
 func newExprMetrics(subsystem string) *ExprMetrics {
@@ -59,6 +61,24 @@ func newExprMetrics(subsystem string) *ExprMetrics {
 			},
 			[]string{"status"},
 		),
+
+		SqlCommandLimitExceeded: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "grafana",
+			Subsystem: subsystem,
+			Name:      "sql_command_limit_exceeded_total",
+			Help:      "Total number of SQL command executions that exceeded a resource limit",
+		}, []string{"limit"}),
+
+		SqlCommandInputRowCount: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: "grafana",
+				Subsystem: subsystem,
+				Name:      "sql_command_input_row_count",
+				Help:      "Distribution of the total number of input rows in each SQL command execution",
+				Buckets:   prometheus.ExponentialBuckets(10, 2, 15),
+			},
+			[]string{"status"},
+		),
 	}
 }
 
@@ -76,6 +96,10 @@ func NewSSEMetrics(reg prometheus.Registerer) *ExprMetrics {
 		SqlCommandErrorCount: newExprMetrics(metricsSubSystem).SqlCommandErrorCount,
 
 		SqlCommandCellCount: newExprMetrics(metricsSubSystem).SqlCommandCellCount,
+
+		SqlCommandLimitExceeded: newExprMetrics(metricsSubSystem).SqlCommandLimitExceeded,
+
+		SqlCommandInputRowCount: newExprMetrics(metricsSubSystem).SqlCommandInputRowCount,
 	}
 
 	if reg != nil {
@@ -85,6 +109,8 @@ func NewSSEMetrics(reg prometheus.Registerer) *ExprMetrics {
 			m.SqlCommandDuration,
 			m.SqlCommandErrorCount,
 			m.SqlCommandCellCount,
+			m.SqlCommandLimitExceeded,
+			m.SqlCommandInputRowCount,
 		)
 	}
 
@@ -105,6 +131,10 @@ func NewQueryServiceExpressionsMetrics(reg prometheus.Registerer) *ExprMetrics {
 		SqlCommandErrorCount: newExprMetrics(metricsSubSystem).SqlCommandErrorCount,
 
 		SqlCommandCellCount: newExprMetrics(metricsSubSystem).SqlCommandCellCount,
+
+		SqlCommandLimitExceeded: newExprMetrics(metricsSubSystem).SqlCommandLimitExceeded,
+
+		SqlCommandInputRowCount: newExprMetrics(metricsSubSystem).SqlCommandInputRowCount,
 	}
 
 	if reg != nil {
@@ -114,6 +144,8 @@ func NewQueryServiceExpressionsMetrics(reg prometheus.Registerer) *ExprMetrics {
 			m.SqlCommandDuration,
 			m.SqlCommandErrorCount,
 			m.SqlCommandCellCount,
+			m.SqlCommandLimitExceeded,
+			m.SqlCommandInputRowCount,
 		)
 	}
 
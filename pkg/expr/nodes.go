@@ -42,6 +42,7 @@ type rawNode struct {
 	QueryRaw   []byte
 	QueryType  string
 	TimeRange  TimeRange
+	Interval   time.Duration
 	DataSource *datasources.DataSource
 	// We use this index as the id of the node graph so the order can remain during a the stable sort of the dependency graph execution order.
 	// Some data sources, such as cloud watch, have order dependencies between queries.
@@ -165,6 +166,10 @@ func buildCMDNode(rn *rawNode, toggles featuremgmt.FeatureToggles, cfg *setting.
 		node.Command, err = UnmarshalThresholdCommand(rn)
 	case TypeSQL:
 		node.Command, err = UnmarshalSQLCommand(rn, cfg)
+	case TypeAnomaly:
+		node.Command, err = UnmarshalAnomalyCommand(rn)
+	case TypeForecast:
+		node.Command, err = UnmarshalForecastCommand(rn)
 	default:
 		return nil, fmt.Errorf("expression command type '%v' in expression '%v' not implemented", commandType, rn.RefID)
 	}
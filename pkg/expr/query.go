@@ -29,6 +29,12 @@ const (
 
 	// SQL query
 	QueryTypeSQL QueryType = "sql"
+
+	// Anomaly detection
+	QueryTypeAnomaly QueryType = "anomaly"
+
+	// Forecast future values
+	QueryTypeForecast QueryType = "forecast"
 )
 
 type MathQuery struct {
@@ -43,6 +49,9 @@ type ReduceQuery struct {
 	// The reducer
 	Reducer mathexp.ReducerID `json:"reducer"`
 
+	// The percentile (0-100) to calculate, required when the reducer is percentile
+	Percentile *float64 `json:"percentile,omitempty"`
+
 	// Reducer Options
 	Settings *ReduceSettings `json:"settings,omitempty"`
 }
@@ -52,14 +61,20 @@ type ResampleQuery struct {
 	// The math expression
 	Expression string `json:"expression" jsonschema:"minLength=1,example=$A + 1,example=$A"`
 
-	// The time duration
-	Window string `json:"window" jsonschema:"minLength=1,example=1d,example=10m"`
+	// The time duration, or auto to use the interval of the query
+	Window string `json:"window" jsonschema:"minLength=1,example=1d,example=10m,example=auto"`
 
 	// The downsample function
 	Downsampler mathexp.ReducerID `json:"downsampler"`
 
 	// The upsample function
 	Upsampler mathexp.Upsampler `json:"upsampler"`
+
+	// The alignment of the samples
+	Alignment mathexp.ResampleAlignment `json:"alignment,omitempty"`
+
+	// The timezone of the calendar boundaries, for example Europe/Berlin. Defaults to UTC
+	Timezone string `json:"timezone,omitempty"`
 }
 
 type ThresholdQuery struct {
@@ -78,6 +93,51 @@ type ClassicQuery struct {
 type SQLExpression struct {
 	Expression string `json:"expression" jsonschema:"minLength=1,example=SELECT * FROM A LIMIT 1"`
 	Format     string `json:"format"`
+
+	// Return the plan and the estimated cost of the query instead of running it
+	Explain bool `json:"explain,omitempty"`
+}
+
+// QueryType = anomaly
+type AnomalyQuery struct {
+	// Reference to single query result
+	Expression string `json:"expression" jsonschema:"minLength=1,example=$A"`
+
+	// The anomaly detection algorithm
+	Algorithm mathexp.AnomalyAlgorithm `json:"algorithm"`
+
+	// The duration of the rolling baseline, required by zscore and mad
+	Window string `json:"window,omitempty" jsonschema:"example=1h,example=30m"`
+
+	// The duration of one season, required by holt_winters
+	Season string `json:"season,omitempty" jsonschema:"example=1d,example=1w"`
+
+	// The width of the band in deviations from the baseline, defaults to 3
+	Sensitivity *float64 `json:"sensitivity,omitempty"`
+
+	// The output of the expression, defaults to verdict
+	Output AnomalyOutput `json:"output,omitempty"`
+}
+
+// QueryType = forecast
+type ForecastQuery struct {
+	// Reference to single query result
+	Expression string `json:"expression" jsonschema:"minLength=1,example=$A"`
+
+	// The forecast model, defaults to linear
+	Model mathexp.ForecastModel `json:"model,omitempty"`
+
+	// How far after the last point to forecast
+	Horizon string `json:"horizon" jsonschema:"minLength=1,example=4h,example=1d"`
+
+	// Fit the model only on the points within this duration before the last point, defaults to all points
+	Window string `json:"window,omitempty" jsonschema:"example=1h,example=6h"`
+
+	// The duration of one season, required by seasonal
+	Season string `json:"season,omitempty" jsonschema:"example=1d,example=1w"`
+
+	// The output of the expression, defaults to value
+	Output ForecastOutput `json:"output,omitempty"`
 }
 
 //-------------------------------
@@ -107,6 +167,30 @@ const (
 	ReduceModeReplace ReduceMode = "replaceNN"
 )
 
+// Anomaly output
+// +enum
+type AnomalyOutput string
+
+const (
+	// A number per series that is 1 if the last point is anomalous and 0 otherwise
+	AnomalyOutputVerdict AnomalyOutput = "verdict"
+
+	// The lower and upper band series, and a series that is 1 for every anomalous point and 0 otherwise
+	AnomalyOutputBands AnomalyOutput = "bands"
+)
+
+// Forecast output
+// +enum
+type ForecastOutput string
+
+const (
+	// A number per series with the value forecast at the horizon
+	ForecastOutputValue ForecastOutput = "value"
+
+	// A series per series with the values forecast from the last point up to the horizon
+	ForecastOutputSeries ForecastOutput = "series"
+)
+
 //go:embed query.types.json
 var f embed.FS
 
@@ -27,6 +27,10 @@ func TestQueryTypeDefinitions(t *testing.T) {
 				reflect.TypeOf(ReduceModeDrop),       // pick an example value (not the root)
 				reflect.TypeOf(ThresholdIsAbove),
 				reflect.TypeOf(classic.ConditionOperatorAnd),
+				reflect.TypeOf(mathexp.AnomalyZScore),
+				reflect.TypeOf(AnomalyOutputVerdict),
+				reflect.TypeOf(mathexp.ForecastLinear),
+				reflect.TypeOf(ForecastOutputValue),
 			},
 		})
 	require.NoError(t, err)
@@ -161,6 +165,35 @@ func TestQueryTypeDefinitions(t *testing.T) {
 				},
 			},
 		},
+		schemabuilder.QueryTypeInfo{
+			Discriminators: data.NewDiscriminators("type", QueryTypeAnomaly),
+			GoType:         reflect.TypeOf(&AnomalyQuery{}),
+			Examples: []data.QueryExample{
+				{
+					Name: "Daily seasonal baseline of A",
+					SaveModel: data.AsUnstructured(AnomalyQuery{
+						Expression: "$A",
+						Algorithm:  mathexp.AnomalyHoltWinters,
+						Season:     "1d",
+					}),
+				},
+			},
+		},
+		schemabuilder.QueryTypeInfo{
+			Discriminators: data.NewDiscriminators("type", QueryTypeForecast),
+			GoType:         reflect.TypeOf(&ForecastQuery{}),
+			Examples: []data.QueryExample{
+				{
+					Name: "Value of A in 4 hours",
+					SaveModel: data.AsUnstructured(ForecastQuery{
+						Expression: "$A",
+						Model:      mathexp.ForecastLinear,
+						Horizon:    "4h",
+						Window:     "6h",
+					}),
+				},
+			},
+		},
 	)
 
 	require.NoError(t, err)
@@ -1,8 +1,10 @@
 package expr
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
 	"github.com/grafana/grafana-plugin-sdk-go/data/utils/jsoniter"
@@ -87,10 +89,18 @@ func (h *ExpressionQueryReader) ReadQuery(
 				err = fmt.Errorf("unsupported reduce mode")
 			}
 		}
+		reducer := q.Reducer
+		if err == nil && reducer == mathexp.ReducerPercentile {
+			if q.Percentile == nil {
+				err = errors.New("field percentile must be specified when reducer is 'percentile'")
+			} else {
+				reducer, err = mathexp.PercentileReducer(*q.Percentile)
+			}
+		}
 		if err == nil {
 			eq.Properties = q
 			eq.Command, err = NewReduceCommand(common.RefID,
-				q.Reducer, referenceVar, mapper)
+				reducer, referenceVar, mapper)
 		}
 
 	case QueryTypeResample:
@@ -105,7 +115,7 @@ func (h *ExpressionQueryReader) ReadQuery(
 		if err == nil {
 			tr := gtime.NewTimeRange(common.TimeRange.From, common.TimeRange.To)
 			eq.Properties = q
-			eq.Command, err = NewResampleCommand(common.RefID,
+			eq.Command, err = NewResampleCommandWithOptions(common.RefID,
 				q.Window,
 				referenceVar,
 				q.Downsampler,
@@ -114,6 +124,11 @@ func (h *ExpressionQueryReader) ReadQuery(
 					From: tr.GetFromAsTimeUTC(),
 					To:   tr.GetToAsTimeUTC(),
 				},
+				ResampleCommandOptions{
+					QueryInterval: time.Duration(common.IntervalMS * float64(time.Millisecond)),
+					Alignment:     q.Alignment,
+					Timezone:      q.Timezone,
+				},
 			)
 		}
 
@@ -134,8 +149,32 @@ func (h *ExpressionQueryReader) ReadQuery(
 		if err == nil {
 			eq.Properties = q
 			// TODO: Cascade limit from Grafana config in this (new Expression Parser) branch of the code
-			cellLimit := 0 // zero means no limit
-			eq.Command, err = NewSQLCommand(common.RefID, q.Format, q.Expression, int64(cellLimit), 0, 0)
+			// zero means no limit
+			eq.Command, err = NewSQLCommandWithOptions(common.RefID, q.Format, q.Expression, SQLCommandOptions{
+				Explain: q.Explain,
+			})
+		}
+
+	case QueryTypeAnomaly:
+		q := &AnomalyQuery{}
+		err = iter.ReadVal(q)
+		if err == nil {
+			referenceVar, err = getReferenceVar(q.Expression, common.RefID)
+		}
+		if err == nil {
+			eq.Properties = q
+			eq.Command, err = newAnomalyCommandFromQuery(common.RefID, referenceVar, *q)
+		}
+
+	case QueryTypeForecast:
+		q := &ForecastQuery{}
+		err = iter.ReadVal(q)
+		if err == nil {
+			referenceVar, err = getReferenceVar(q.Expression, common.RefID)
+		}
+		if err == nil {
+			eq.Properties = q
+			eq.Command, err = newForecastCommandFromQuery(common.RefID, referenceVar, *q)
 		}
 
 	case QueryTypeThreshold:
@@ -151,7 +190,7 @@ func (h *ExpressionQueryReader) ReadQuery(
 			}
 			firstCondition := q.Conditions[0]
 
-			threshold, err := NewThresholdCommand(common.RefID, referenceVar, firstCondition.Evaluator.Type, firstCondition.Evaluator.Params)
+			threshold, err := NewThresholdCommandWithVars(common.RefID, referenceVar, firstCondition.Evaluator.Type, firstCondition.Evaluator.Params, firstCondition.Evaluator.ParamVars)
 			if err != nil {
 				return eq, fmt.Errorf("invalid condition: %w", err)
 			}
@@ -159,19 +198,12 @@ func (h *ExpressionQueryReader) ReadQuery(
 			eq.Properties = q
 
 			if firstCondition.UnloadEvaluator != nil {
-				unloading, err := NewThresholdCommand(common.RefID, referenceVar, firstCondition.UnloadEvaluator.Type, firstCondition.UnloadEvaluator.Params)
-				unloading.Invert = true
+				unloading, err := NewThresholdCommandWithVars(common.RefID, referenceVar, firstCondition.UnloadEvaluator.Type, firstCondition.UnloadEvaluator.Params, firstCondition.UnloadEvaluator.ParamVars)
 				if err != nil {
 					return eq, fmt.Errorf("invalid unloadCondition: %w", err)
 				}
-				var d Fingerprints
-				if firstCondition.LoadedDimensions != nil {
-					d, err = FingerprintsFromFrame(firstCondition.LoadedDimensions)
-					if err != nil {
-						return eq, fmt.Errorf("failed to parse loaded dimensions: %w", err)
-					}
-				}
-				eq.Command, err = NewHysteresisCommand(common.RefID, referenceVar, *threshold, *unloading, d)
+				unloading.Invert = true
+				eq.Command, err = newHysteresisCommandFromCondition(common.RefID, referenceVar, *threshold, *unloading, firstCondition)
 				if err != nil {
 					return eq, err
 				}
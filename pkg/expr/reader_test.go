@@ -160,3 +160,67 @@ func TestReaderReduceMode(t *testing.T) {
 		})
 	}
 }
+
+func TestReaderReducePercentile(t *testing.T) {
+	read := func(t *testing.T, query string) (eq ExpressionQuery, err error) {
+		t.Helper()
+		var q data.DataQuery
+		require.NoError(t, json.Unmarshal([]byte(query), &q))
+		raw, err := json.Marshal(q)
+		require.NoError(t, err)
+		iter, err := jsoniter.ParseBytes(jsoniter.ConfigDefault, raw)
+		require.NoError(t, err)
+		return NewExpressionQueryReader(featuremgmt.WithFeatures()).ReadQuery(q, iter)
+	}
+
+	t.Run("should reduce to the percentile", func(t *testing.T) {
+		eq, err := read(t, `{"refId": "B", "type": "reduce", "expression": "A", "reducer": "percentile", "percentile": 95}`)
+		require.NoError(t, err)
+		rc, ok := eq.Command.(*ReduceCommand)
+		require.True(t, ok)
+		require.Equal(t, mathexp.ReducerID("p95"), rc.Reducer)
+	})
+
+	t.Run("should fail if the percentile is missing", func(t *testing.T) {
+		_, err := read(t, `{"refId": "B", "type": "reduce", "expression": "A", "reducer": "percentile"}`)
+		require.ErrorContains(t, err, "field percentile must be specified")
+	})
+
+	t.Run("should fail if the percentile is out of range", func(t *testing.T) {
+		_, err := read(t, `{"refId": "B", "type": "reduce", "expression": "A", "reducer": "percentile", "percentile": 101}`)
+		require.ErrorContains(t, err, "percentile must be between 0 and 100")
+	})
+}
+
+func TestReaderThresholdUnloadParamVars(t *testing.T) {
+	q := data.DataQuery{}
+	err := json.Unmarshal([]byte(`
+		{
+			"refId": "C",
+			"datasource": {
+				"type": "__expr__",
+				"uid": "__expr__"
+			},
+			"expression": "A",
+			"type": "threshold",
+			"conditions": [
+				{
+					"evaluator": { "type": "gt", "params": [10] },
+					"unloadEvaluator": { "type": "lt", "params": [5], "paramVars": ["$A"] }
+				}
+			]
+		}
+	`), &q)
+	require.NoError(t, err)
+
+	raw, err := json.Marshal(q)
+	require.NoError(t, err)
+	iter, err := jsoniter.ParseBytes(jsoniter.ConfigDefault, raw)
+	require.NoError(t, err)
+
+	reader := NewExpressionQueryReader(featuremgmt.WithFeatures())
+	require.NotPanics(t, func() {
+		_, err = reader.ReadQuery(q, iter)
+	})
+	require.ErrorContains(t, err, "invalid unloadCondition")
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
@@ -121,6 +122,30 @@ func (s *Service) ExecutePipeline(ctx context.Context, now time.Time, pipeline D
 	return res, nil
 }
 
+// ExecutePipelineWithTrace executes an expression pipeline like ExecutePipeline, and adds a table that describes
+// what every node did (timing, shapes of the inputs and outputs, label sets, and dropped series) to the results,
+// in the response with the DebugTraceRefID key.
+func (s *Service) ExecutePipelineWithTrace(ctx context.Context, now time.Time, pipeline DataPipeline) (*backend.QueryDataResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "SSE.ExecutePipelineWithTrace")
+	defer span.End()
+	res := backend.NewQueryDataResponse()
+	trace := &pipelineTrace{}
+	vars, err := pipeline.executeWithTrace(ctx, now, s, trace)
+	if err != nil {
+		return nil, err
+	}
+	for refID, val := range vars {
+		res.Responses[refID] = backend.DataResponse{
+			Frames: val.Values.AsDataFrames(refID),
+			Error:  val.Error,
+		}
+	}
+	res.Responses[DebugTraceRefID] = backend.DataResponse{
+		Frames: data.Frames{trace.Frame()},
+	}
+	return res, nil
+}
+
 // Create a datasources.DataSource struct from NodeType. Returns error if kind is TypeDatasourceNode or unknown one.
 func DataSourceModelFromNodeType(kind NodeType) (*datasources.DataSource, error) {
 	switch kind {
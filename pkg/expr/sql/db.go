@@ -60,6 +60,8 @@ type QueryOption func(*QueryOptions)
 type QueryOptions struct {
 	Timeout        time.Duration
 	MaxOutputCells int64
+	MaxOutputRows  int64
+	MaxMemoryBytes int64
 }
 
 func WithTimeout(d time.Duration) QueryOption {
@@ -74,6 +76,21 @@ func WithMaxOutputCells(n int64) QueryOption {
 	}
 }
 
+// WithMaxOutputRows makes the query fail with a LimitExceededError if it returns more than n rows.
+func WithMaxOutputRows(n int64) QueryOption {
+	return func(o *QueryOptions) {
+		o.MaxOutputRows = n
+	}
+}
+
+// WithMaxMemoryBytes makes the query fail with a LimitExceededError if the estimated memory
+// of its input and output frames is more than n bytes.
+func WithMaxMemoryBytes(n int64) QueryOption {
+	return func(o *QueryOptions) {
+		o.MaxMemoryBytes = n
+	}
+}
+
 // QueryFrames runs the sql query query against a database created from frames, and returns the frame.
 // The RefID of each frame becomes a table in the database.
 // It is expected that there is only one frame per RefID.
@@ -100,24 +117,17 @@ func (db *DB) QueryFrames(ctx context.Context, tracer tracing.Tracer, name strin
 	_, span := tracer.Start(ctx, "SSE.ExecuteGMSQuery")
 	defer span.End()
 
-	pro := NewFramesDBProvider(frames)
-	session := mysql.NewBaseSession()
-
-	// Create a new context with the session and tracer
-	mCtx := mysql.NewContext(ctx, mysql.WithSession(session), mysql.WithTracer(tracer))
-
-	// Select the database in the context
-	mCtx.SetCurrentDatabase(dbName)
-
-	// Empty dir does not disable secure_file_priv
-	//ctx.SetSessionVariable(ctx, "secure_file_priv", "")
+	var inputBytes int64
+	if QueryOptions.MaxMemoryBytes > 0 {
+		for _, f := range frames {
+			inputBytes += EstimateFrameBytes(f)
+		}
+		if inputBytes > QueryOptions.MaxMemoryBytes {
+			return nil, &LimitExceededError{Limit: LimitMemory, Max: QueryOptions.MaxMemoryBytes}
+		}
+	}
 
-	// TODO: Check if it's wise to reuse the existing provider, rather than creating a new one
-	a := analyzer.NewDefault(pro)
-
-	engine := sqle.New(a, &sqle.Config{
-		IsReadOnly: true,
-	})
+	mCtx, engine := newEngine(ctx, tracer, frames)
 
 	contextErr := func(err error) error {
 		switch {
@@ -140,7 +150,12 @@ func (db *DB) QueryFrames(ctx context.Context, tracer tracing.Tracer, name strin
 	}
 
 	// Convert the iterator into a Grafana data.Frame
-	f, err := convertToDataFrame(mCtx, iter, schema, QueryOptions.MaxOutputCells)
+	f, err := convertToDataFrame(mCtx, iter, schema, outputLimits{
+		maxCells: QueryOptions.MaxOutputCells,
+		maxRows:  QueryOptions.MaxOutputRows,
+		maxBytes: QueryOptions.MaxMemoryBytes,
+		inBytes:  inputBytes,
+	})
 	if err != nil {
 		if ctx.Err() != nil {
 			return nil, contextErr(ctx.Err())
@@ -153,3 +168,53 @@ func (db *DB) QueryFrames(ctx context.Context, tracer tracing.Tracer, name strin
 
 	return f, nil
 }
+
+// ExplainFrames returns the plan of the query against a database created from frames, without running it.
+// The plan is returned as a frame with one row per line. The name becomes the name and RefID of the returned frame.
+func (db *DB) ExplainFrames(ctx context.Context, tracer tracing.Tracer, name string, query string, frames []*data.Frame) (*data.Frame, error) {
+	if _, err := AllowQuery(query); err != nil {
+		return nil, err
+	}
+	_, span := tracer.Start(ctx, "SSE.ExplainGMSQuery")
+	defer span.End()
+
+	mCtx, engine := newEngine(ctx, tracer, frames)
+
+	// EXPLAIN only runs the analyzer, so the tables are not read.
+	schema, iter, _, err := engine.Query(mCtx, "EXPLAIN "+query)
+	if err != nil {
+		return nil, WrapGoMySQLServerError(err)
+	}
+	f, err := convertToDataFrame(mCtx, iter, schema, outputLimits{})
+	if err != nil {
+		return nil, err
+	}
+
+	f.Name = name
+	f.RefID = name
+
+	return f, nil
+}
+
+func newEngine(ctx context.Context, tracer tracing.Tracer, frames []*data.Frame) (*mysql.Context, *sqle.Engine) {
+	pro := NewFramesDBProvider(frames)
+	session := mysql.NewBaseSession()
+
+	// Create a new context with the session and tracer
+	mCtx := mysql.NewContext(ctx, mysql.WithSession(session), mysql.WithTracer(tracer))
+
+	// Select the database in the context
+	mCtx.SetCurrentDatabase(dbName)
+
+	// Empty dir does not disable secure_file_priv
+	//ctx.SetSessionVariable(ctx, "secure_file_priv", "")
+
+	// TODO: Check if it's wise to reuse the existing provider, rather than creating a new one
+	a := analyzer.NewDefault(pro)
+	a.Catalog.RegisterFunction(mCtx, grafanaFunctions...)
+
+	engine := sqle.New(a, &sqle.Config{
+		IsReadOnly: true,
+	})
+	return mCtx, engine
+}
@@ -197,6 +197,55 @@ func TestQueryFramesDateTimeSelect(t *testing.T) {
 	}
 }
 
+func TestQueryFramesTimeBucket(t *testing.T) {
+	input := data.NewFrame("",
+		data.NewField("time", nil, []time.Time{
+			time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+			time.Date(2025, 1, 2, 3, 59, 59, 0, time.UTC),
+			time.Date(2025, 1, 2, 4, 0, 0, 0, time.UTC),
+		}),
+	).SetRefID("A")
+
+	db := DB{}
+
+	t.Run("duration string", func(t *testing.T) {
+		f, err := db.QueryFrames(context.Background(), &testTracer{}, "B", `SELECT time_bucket('1h', time) AS hour FROM A`, []*data.Frame{input})
+		require.NoError(t, err)
+
+		expected := data.NewFrame("B",
+			data.NewField("hour", nil, []*time.Time{
+				p(time.Date(2025, 1, 2, 3, 0, 0, 0, time.UTC)),
+				p(time.Date(2025, 1, 2, 3, 0, 0, 0, time.UTC)),
+				p(time.Date(2025, 1, 2, 4, 0, 0, 0, time.UTC)),
+			}),
+		).SetRefID("B")
+		if diff := cmp.Diff(expected, f, data.FrameTestCompareOptions()...); diff != "" {
+			require.FailNowf(t, "Result mismatch (-want +got):%s\n", diff)
+		}
+	})
+
+	t.Run("seconds", func(t *testing.T) {
+		f, err := db.QueryFrames(context.Background(), &testTracer{}, "B", `SELECT time_bucket(900, time) AS quarter FROM A`, []*data.Frame{input})
+		require.NoError(t, err)
+
+		expected := data.NewFrame("B",
+			data.NewField("quarter", nil, []*time.Time{
+				p(time.Date(2025, 1, 2, 3, 0, 0, 0, time.UTC)),
+				p(time.Date(2025, 1, 2, 3, 45, 0, 0, time.UTC)),
+				p(time.Date(2025, 1, 2, 4, 0, 0, 0, time.UTC)),
+			}),
+		).SetRefID("B")
+		if diff := cmp.Diff(expected, f, data.FrameTestCompareOptions()...); diff != "" {
+			require.FailNowf(t, "Result mismatch (-want +got):%s\n", diff)
+		}
+	})
+
+	t.Run("invalid interval", func(t *testing.T) {
+		_, err := db.QueryFrames(context.Background(), &testTracer{}, "B", `SELECT time_bucket('often', time) AS hour FROM A`, []*data.Frame{input})
+		require.ErrorContains(t, err, "time_bucket: invalid interval")
+	})
+}
+
 func TestErrorsFromGoMySQLServerAreFlagged(t *testing.T) {
 	const GmsNotImplemented = "TRUNCATE" // not implemented in go-mysql-server as of 2025-04-11
 
@@ -310,6 +359,24 @@ func TestQueryFrames_Limits(t *testing.T) {
 			opts:        []QueryOption{WithTimeout(1 * time.Nanosecond)},
 			expectError: "did not complete within the timeout",
 		},
+		{
+			name:        "fails on max output rows",
+			query:       `SELECT 1 as x UNION ALL SELECT 2 UNION ALL SELECT 3`,
+			opts:        []QueryOption{WithMaxOutputRows(2)},
+			expectError: "output_rows exceeds limit of 2",
+		},
+		{
+			name:       "within max output rows",
+			query:      `SELECT 1 as x UNION ALL SELECT 2 UNION ALL SELECT 3`,
+			opts:       []QueryOption{WithMaxOutputRows(3)},
+			expectRows: 3,
+		},
+		{
+			name:        "fails on max memory",
+			query:       `SELECT 'a long string value' as x UNION ALL SELECT 'another long string value'`,
+			opts:        []QueryOption{WithMaxMemoryBytes(40)},
+			expectError: "memory_bytes exceeds limit of 40",
+		},
 	}
 
 	for _, tt := range tests {
@@ -362,3 +429,19 @@ func (ts *testSpan) IsRecording() bool {
 
 func (ts *testSpan) AddEvent(name string, options ...trace.EventOption) {
 }
+
+func TestExplainFrames(t *testing.T) {
+	input := data.NewFrame("",
+		data.NewField("value", nil, []int64{1, 2, 3}),
+	).SetRefID("A")
+
+	db := DB{}
+	f, err := db.ExplainFrames(context.Background(), &testTracer{}, "B", `SELECT sum(value) FROM A`, []*data.Frame{input})
+	require.NoError(t, err)
+	require.Equal(t, "B", f.RefID)
+	require.Len(t, f.Fields, 1)
+	require.Greater(t, f.Rows(), 0)
+
+	_, err = db.ExplainFrames(context.Background(), &testTracer{}, "B", `DROP TABLE A`, []*data.Frame{input})
+	require.Error(t, err)
+}
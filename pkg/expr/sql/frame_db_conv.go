@@ -15,8 +15,20 @@ import (
 	"github.com/shopspring/decimal"
 )
 
-// TODO: Should this accept a row limit and converters, like sqlutil.FrameFromRows?
-func convertToDataFrame(ctx *mysql.Context, iter mysql.RowIter, schema mysql.Schema, maxOutputCells int64) (*data.Frame, error) {
+// outputLimits are the limits of convertToDataFrame. A limit of 0 or less means no limit.
+type outputLimits struct {
+	// maxCells truncates the frame, with a notice, once it is reached.
+	maxCells int64
+	// maxRows and maxBytes fail the conversion with a LimitExceededError.
+	// maxBytes is shared with the input frames, whose size is inBytes.
+	maxRows  int64
+	maxBytes int64
+	inBytes  int64
+}
+
+// TODO: Should this accept converters, like sqlutil.FrameFromRows?
+func convertToDataFrame(ctx *mysql.Context, iter mysql.RowIter, schema mysql.Schema, limits outputLimits) (*data.Frame, error) {
+	maxOutputCells := limits.maxCells
 	f := &data.Frame{}
 
 	// Create fields based on the schema
@@ -31,6 +43,8 @@ func convertToDataFrame(ctx *mysql.Context, iter mysql.RowIter, schema mysql.Sch
 	}
 
 	cellCount := int64(0)
+	rowCount := int64(0)
+	byteCount := limits.inBytes
 
 	// Iterate through the rows and append data to fields
 	for {
@@ -63,6 +77,11 @@ func convertToDataFrame(ctx *mysql.Context, iter mysql.RowIter, schema mysql.Sch
 			}
 		}
 
+		rowCount++
+		if limits.maxRows > 0 && rowCount > limits.maxRows {
+			return nil, &LimitExceededError{Limit: LimitOutputRows, Max: limits.maxRows}
+		}
+
 		for i, val := range row {
 			// Run val through mysql.Type.Convert to normalize underlying value
 			// of the interface
@@ -80,6 +99,13 @@ func convertToDataFrame(ctx *mysql.Context, iter mysql.RowIter, schema mysql.Sch
 			}
 
 			f.Fields[i].Append(fV)
+
+			if limits.maxBytes > 0 {
+				byteCount += estimateValueBytes(fV)
+				if byteCount > limits.maxBytes {
+					return nil, &LimitExceededError{Limit: LimitMemory, Max: limits.maxBytes}
+				}
+			}
 		}
 	}
 
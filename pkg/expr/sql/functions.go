@@ -0,0 +1,126 @@
+//go:build !arm
+
+package sql
+
+import (
+	"fmt"
+	"time"
+
+	mysql "github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+)
+
+// grafanaFunctions are functions that SQL expressions support in addition to the ones built into go-mysql-server.
+// They must also be added to allowedFunction.
+var grafanaFunctions = []mysql.Function{
+	mysql.Function2{Name: "time_bucket", Fn: newTimeBucket},
+}
+
+// timeBucket is the time_bucket(interval, time) function. It returns the start of the interval that the time
+// falls into. Intervals are aligned to the Unix epoch, so time_bucket('1h', time) is the start of the hour in UTC.
+// The interval is either a duration string such as '5m' or '1d', or a number of seconds.
+type timeBucket struct {
+	interval mysql.Expression
+	time     mysql.Expression
+}
+
+func newTimeBucket(interval, t mysql.Expression) mysql.Expression {
+	return &timeBucket{interval: interval, time: t}
+}
+
+// FunctionName implements sql.FunctionExpression
+func (tb *timeBucket) FunctionName() string {
+	return "time_bucket"
+}
+
+// Description implements sql.FunctionExpression
+func (tb *timeBucket) Description() string {
+	return "returns the start of the interval that the time falls into."
+}
+
+// Resolved implements sql.Expression
+func (tb *timeBucket) Resolved() bool {
+	return tb.interval.Resolved() && tb.time.Resolved()
+}
+
+// String implements sql.Expression
+func (tb *timeBucket) String() string {
+	return fmt.Sprintf("%s(%s, %s)", tb.FunctionName(), tb.interval, tb.time)
+}
+
+// Type implements sql.Expression
+func (tb *timeBucket) Type() mysql.Type {
+	return types.Timestamp
+}
+
+// IsNullable implements sql.Expression
+func (tb *timeBucket) IsNullable() bool {
+	return true
+}
+
+// Children implements sql.Expression
+func (tb *timeBucket) Children() []mysql.Expression {
+	return []mysql.Expression{tb.interval, tb.time}
+}
+
+// WithChildren implements sql.Expression
+func (tb *timeBucket) WithChildren(children ...mysql.Expression) (mysql.Expression, error) {
+	if len(children) != 2 {
+		return nil, mysql.ErrInvalidChildrenNumber.New(tb, len(children), 2)
+	}
+	return newTimeBucket(children[0], children[1]), nil
+}
+
+// Eval implements sql.Expression
+func (tb *timeBucket) Eval(ctx *mysql.Context, row mysql.Row) (interface{}, error) {
+	rawInterval, err := tb.interval.Eval(ctx, row)
+	if err != nil || rawInterval == nil {
+		return nil, err
+	}
+	interval, err := bucketInterval(ctx, rawInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	rawTime, err := tb.time.Eval(ctx, row)
+	if err != nil || rawTime == nil {
+		return nil, err
+	}
+	converted, _, err := types.Timestamp.Convert(ctx, rawTime)
+	if err != nil {
+		return nil, fmt.Errorf("time_bucket: invalid time %v: %w", rawTime, err)
+	}
+	t, ok := converted.(time.Time)
+	if !ok {
+		return nil, fmt.Errorf("time_bucket: invalid time %v", rawTime)
+	}
+
+	ns, step := t.UnixNano(), interval.Nanoseconds()
+	offset := ns % step
+	if offset < 0 {
+		offset += step
+	}
+	return time.Unix(0, ns-offset).UTC(), nil
+}
+
+func bucketInterval(ctx *mysql.Context, v interface{}) (time.Duration, error) {
+	var interval time.Duration
+	if s, ok := v.(string); ok {
+		d, err := gtime.ParseDuration(s)
+		if err != nil {
+			return 0, fmt.Errorf("time_bucket: invalid interval %q: %w", s, err)
+		}
+		interval = d
+	} else {
+		seconds, _, err := types.Float64.Convert(ctx, v)
+		if err != nil {
+			return 0, fmt.Errorf("time_bucket: invalid interval %v: %w", v, err)
+		}
+		interval = time.Duration(seconds.(float64) * float64(time.Second))
+	}
+	if interval <= 0 {
+		return 0, fmt.Errorf("time_bucket: interval must be positive, got %v", v)
+	}
+	return interval, nil
+}
@@ -0,0 +1,155 @@
+package sql
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/dolthub/vitess/go/vt/sqlparser"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// Limit is a resource limit of a SQL expression.
+type Limit string
+
+const (
+	LimitInputCells Limit = "input_cells"
+	LimitInputRows  Limit = "input_rows"
+	LimitOutputRows Limit = "output_rows"
+	LimitMemory     Limit = "memory_bytes"
+	LimitTimeout    Limit = "timeout"
+)
+
+// LimitExceededError is returned when a query exceeds one of its limits.
+type LimitExceededError struct {
+	Limit Limit
+	Max   int64
+}
+
+// Error implements the error interface
+func (e *LimitExceededError) Error() string {
+	return fmt.Sprintf("%s exceeds limit of %d", e.Limit, e.Max)
+}
+
+// QueryCost is a rough estimate of the work a query does, calculated before it runs.
+type QueryCost struct {
+	// InputRows, InputCells and InputBytes are the totals of the tables that the query references.
+	InputRows  int64 `json:"inputRows"`
+	InputCells int64 `json:"inputCells"`
+	InputBytes int64 `json:"inputBytes"`
+	// Joins is the number of joins in the query, including comma separated tables.
+	Joins int `json:"joins"`
+	// MaxRows is an upper bound of the rows the query reads before filtering and aggregation:
+	// the product of the rows of the referenced tables if the query joins, otherwise their sum.
+	MaxRows int64 `json:"maxRows"`
+}
+
+// EstimateCost calculates the QueryCost of the query over the frames. The RefID of each frame is its table name.
+func EstimateCost(rawSQL string, frames []*data.Frame) (QueryCost, error) {
+	stmt, err := sqlparser.Parse(rawSQL)
+	if err != nil {
+		return QueryCost{}, fmt.Errorf("error parsing sql: %s", err.Error())
+	}
+
+	cost := QueryCost{}
+	err = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		switch v := node.(type) {
+		case *sqlparser.JoinTableExpr:
+			cost.Joins++
+		case *sqlparser.Select:
+			if len(v.From) > 1 {
+				cost.Joins += len(v.From) - 1
+			}
+		}
+		return true, nil
+	}, stmt)
+	if err != nil {
+		return QueryCost{}, fmt.Errorf("failed to parse SQL expression: %w", err)
+	}
+
+	tables, err := TablesList(rawSQL)
+	if err != nil {
+		return QueryCost{}, err
+	}
+	referenced := make(map[string]struct{}, len(tables))
+	for _, t := range tables {
+		referenced[t] = struct{}{}
+	}
+
+	product := int64(1)
+	for _, f := range frames {
+		if f == nil {
+			continue
+		}
+		if _, ok := referenced[f.RefID]; !ok {
+			continue
+		}
+		rows := int64(f.Rows())
+		cost.InputRows += rows
+		cost.InputCells += rows * int64(len(f.Fields))
+		cost.InputBytes += EstimateFrameBytes(f)
+		product = saturatingMul(product, rows)
+	}
+	cost.MaxRows = cost.InputRows
+	if cost.Joins > 0 {
+		cost.MaxRows = product
+	}
+	return cost, nil
+}
+
+// EstimateFrameBytes estimates the memory that the values of the frame use.
+func EstimateFrameBytes(f *data.Frame) int64 {
+	var total int64
+	for _, field := range f.Fields {
+		for i := 0; i < field.Len(); i++ {
+			total += estimateValueBytes(field.At(i))
+		}
+	}
+	return total
+}
+
+// estimateValueBytes estimates the memory of a single value of a data.Field, including the pointer of nullable values.
+func estimateValueBytes(v any) int64 {
+	switch v := v.(type) {
+	case nil:
+		return 8
+	case string:
+		return int64(len(v)) + 16
+	case *string:
+		if v == nil {
+			return 8
+		}
+		return int64(len(*v)) + 24
+	case json.RawMessage:
+		return int64(len(v)) + 24
+	case *json.RawMessage:
+		if v == nil {
+			return 8
+		}
+		return int64(len(*v)) + 32
+	case time.Time:
+		return 24
+	case *time.Time:
+		if v == nil {
+			return 8
+		}
+		return 32
+	case bool, int8, uint8:
+		return 1
+	case *bool, *int8, *uint8:
+		return 9
+	default:
+		return 8
+	}
+}
+
+func saturatingMul(a, b int64) int64 {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	if a > math.MaxInt64/b {
+		return math.MaxInt64
+	}
+	return a * b
+}
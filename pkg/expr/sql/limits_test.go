@@ -0,0 +1,70 @@
+package sql
+
+import (
+	"math"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateCost(t *testing.T) {
+	frame := func(refID string, rows int) *data.Frame {
+		return data.NewFrame("",
+			data.NewField("value", nil, make([]float64, rows)),
+			data.NewField("host", nil, make([]string, rows)),
+		).SetRefID(refID)
+	}
+	frames := []*data.Frame{frame("A", 10), frame("B", 20), frame("C", 5)}
+
+	tests := []struct {
+		name     string
+		query    string
+		frames   []*data.Frame
+		expected QueryCost
+	}{
+		{
+			name:     "single table",
+			query:    "SELECT * FROM A",
+			frames:   frames,
+			expected: QueryCost{InputRows: 10, InputCells: 20, InputBytes: 240, MaxRows: 10},
+		},
+		{
+			name:     "join",
+			query:    "SELECT * FROM A JOIN B ON A.host = B.host",
+			frames:   frames,
+			expected: QueryCost{InputRows: 30, InputCells: 60, InputBytes: 720, Joins: 1, MaxRows: 200},
+		},
+		{
+			name:     "comma join",
+			query:    "SELECT * FROM A, B, C",
+			frames:   frames,
+			expected: QueryCost{InputRows: 35, InputCells: 70, InputBytes: 840, Joins: 2, MaxRows: 1000},
+		},
+		{
+			name:     "union",
+			query:    "SELECT * FROM A UNION ALL SELECT * FROM B",
+			frames:   frames,
+			expected: QueryCost{InputRows: 30, InputCells: 60, InputBytes: 720, MaxRows: 30},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cost, err := EstimateCost(tt.query, tt.frames)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, cost)
+		})
+	}
+
+	t.Run("invalid query", func(t *testing.T) {
+		_, err := EstimateCost("SELECT * FROM", frames)
+		require.Error(t, err)
+	})
+}
+
+func TestSaturatingMul(t *testing.T) {
+	require.Equal(t, int64(6), saturatingMul(2, 3))
+	require.Equal(t, int64(0), saturatingMul(0, math.MaxInt64))
+	require.Equal(t, int64(math.MaxInt64), saturatingMul(math.MaxInt64/2, 3))
+}
@@ -60,3 +60,24 @@ func TablesList(rawSQL string) ([]string, error) {
 
 	return result, nil
 }
+
+// ColumnReferenced returns true if the sql statement references a column with the given name, ignoring case.
+func ColumnReferenced(rawSQL string, column string) (bool, error) {
+	stmt, err := sqlparser.Parse(rawSQL)
+	if err != nil {
+		return false, fmt.Errorf("error parsing sql: %s", err.Error())
+	}
+
+	found := false
+	err = sqlparser.Walk(func(node sqlparser.SQLNode) (kontinue bool, err error) {
+		if col, ok := node.(*sqlparser.ColName); ok && col.Name.EqualString(column) {
+			found = true
+			return false, nil
+		}
+		return !found, nil
+	}, stmt)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse SQL expression: %w", err)
+	}
+	return found, nil
+}
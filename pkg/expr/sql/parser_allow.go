@@ -254,6 +254,8 @@ func allowedFunction(f *sqlparser.FuncExpr) (b bool) {
 		return
 	case "timestampdiff", "timestampadd":
 		return
+	case "time_bucket": // see grafanaFunctions
+		return
 
 	// Type conversion
 	case "cast", "convert":
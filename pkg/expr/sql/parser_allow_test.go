@@ -87,6 +87,11 @@ func TestAllowQuery(t *testing.T) {
 			q:    example_window_functions,
 			err:  nil,
 		},
+		{
+			name: "time series functions",
+			q:    example_time_series_functions,
+			err:  nil,
+		},
 		{
 			name: "json table",
 			q:    "SELECT * FROM mockGitHubIssuesDSResponse, JSON_TABLE(labels, '$[*]' COLUMNS(val VARCHAR(255) PATH '$')) AS jt WHERE CAST(jt.val AS CHAR) LIKE 'type%'",
@@ -355,3 +360,16 @@ SELECT
   FIRST_VALUE(val) OVER (ORDER BY val) as first_val,
   LAST_VALUE(val) OVER (ORDER BY val ROWS BETWEEN UNBOUNDED PRECEDING AND UNBOUNDED FOLLOWING) as last_val
 FROM dummy_data;`
+
+var example_time_series_functions = `
+SELECT
+  time_bucket('1h', A.time) AS hour,
+  A.__labels__,
+  SUM(A.__value__) AS total,
+  SUM(A.__value__) - LAG(SUM(A.__value__)) OVER (PARTITION BY A.__labels__ ORDER BY time_bucket('1h', A.time)) AS change,
+  FIRST_VALUE(SUM(A.__value__)) OVER (PARTITION BY A.__labels__ ORDER BY time_bucket('1h', A.time)) AS first_total,
+  MAX(B.__value__) AS max_b
+FROM A
+JOIN B ON A.__labels__ = B.__labels__ AND A.time = B.time
+WHERE JSON_UNQUOTE(JSON_EXTRACT(A.__labels__, '$.host')) = 'a'
+GROUP BY 1, 2;`
@@ -131,3 +131,45 @@ func TestTablesList(t *testing.T) {
 		})
 	}
 }
+
+func TestColumnReferenced(t *testing.T) {
+	tests := []struct {
+		name     string
+		sql      string
+		expected bool
+	}{
+		{
+			name:     "star",
+			sql:      "SELECT * FROM A",
+			expected: false,
+		},
+		{
+			name:     "selected column",
+			sql:      "SELECT __labels__, __value__ FROM A",
+			expected: true,
+		},
+		{
+			name:     "qualified column in join",
+			sql:      "SELECT A.time FROM A JOIN B ON A.time = B.time AND A.__LABELS__ = B.__LABELS__",
+			expected: true,
+		},
+		{
+			name:     "column in window function",
+			sql:      "SELECT __value__ - LAG(__value__) OVER (PARTITION BY __labels__ ORDER BY time) FROM A",
+			expected: true,
+		},
+		{
+			name:     "string literal",
+			sql:      "SELECT '__labels__' AS name FROM A",
+			expected: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			referenced, err := ColumnReferenced(tc.sql, "__labels__")
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, referenced)
+		})
+	}
+}
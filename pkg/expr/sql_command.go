@@ -2,6 +2,7 @@ package expr
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
@@ -34,13 +35,50 @@ type SQLCommand struct {
 
 	format string
 
-	inputLimit  int64
-	outputLimit int64
-	timeout     time.Duration
+	inputLimit     int64
+	outputLimit    int64
+	timeout        time.Duration
+	inputRowLimit  int64
+	outputRowLimit int64
+	memoryLimit    int64
+
+	explain bool
+
+	// labelsColumn is true when the query references the SQLLabelsFieldName column,
+	// which is added to the input tables only then so other queries see the tables unchanged.
+	labelsColumn bool
+}
+
+// SQLCommandOptions are the optional settings of a SQLCommand.
+// A limit of 0 or less means no limit (following convention).
+type SQLCommandOptions struct {
+	// InputCellLimit is the maximum number of cells across all input tables.
+	InputCellLimit int64
+	// InputRowLimit is the maximum number of rows across all input tables.
+	InputRowLimit int64
+	// OutputCellLimit truncates the result once it has this many cells.
+	OutputCellLimit int64
+	// OutputRowLimit fails the query once it returns more rows.
+	OutputRowLimit int64
+	// MemoryLimit is the maximum estimated memory in bytes of the input and output tables.
+	MemoryLimit int64
+	Timeout     time.Duration
+
+	// Explain makes the command return the plan and the estimated cost of the query instead of running it.
+	Explain bool
 }
 
 // NewSQLCommand creates a new SQLCommand.
 func NewSQLCommand(refID, format, rawSQL string, intputLimit, outputLimit int64, timeout time.Duration) (*SQLCommand, error) {
+	return NewSQLCommandWithOptions(refID, format, rawSQL, SQLCommandOptions{
+		InputCellLimit:  intputLimit,
+		OutputCellLimit: outputLimit,
+		Timeout:         timeout,
+	})
+}
+
+// NewSQLCommandWithOptions creates a new SQLCommand with the given limits.
+func NewSQLCommandWithOptions(refID, format, rawSQL string, opts SQLCommandOptions) (*SQLCommand, error) {
 	if rawSQL == "" {
 		return nil, ErrMissingSQLQuery
 	}
@@ -63,15 +101,32 @@ func NewSQLCommand(refID, format, rawSQL string, intputLimit, outputLimit int64,
 	if tables != nil {
 		logger.Debug("REF tables", "tables", tables, "sql", rawSQL)
 	}
+	labelsColumn, err := sql.ColumnReferenced(rawSQL, SQLLabelsFieldName)
+	if err != nil {
+		return nil, ErrInvalidSQLQuery.Build(errutil.TemplateData{
+			Error: err,
+			Public: map[string]any{
+				"error": err.Error(),
+			},
+			Private: map[string]any{
+				"query": rawSQL,
+			},
+		})
+	}
 
 	return &SQLCommand{
-		query:       rawSQL,
-		varsToQuery: tables,
-		refID:       refID,
-		inputLimit:  intputLimit,
-		outputLimit: outputLimit,
-		timeout:     timeout,
-		format:      format,
+		query:          rawSQL,
+		varsToQuery:    tables,
+		refID:          refID,
+		inputLimit:     opts.InputCellLimit,
+		outputLimit:    opts.OutputCellLimit,
+		timeout:        opts.Timeout,
+		inputRowLimit:  opts.InputRowLimit,
+		outputRowLimit: opts.OutputRowLimit,
+		memoryLimit:    opts.MemoryLimit,
+		format:         format,
+		explain:        opts.Explain,
+		labelsColumn:   labelsColumn,
 	}, nil
 }
 
@@ -96,7 +151,18 @@ func UnmarshalSQLCommand(rn *rawNode, cfg *setting.Cfg) (*SQLCommand, error) {
 	formatRaw := rn.Query["format"]
 	format, _ := formatRaw.(string)
 
-	return NewSQLCommand(rn.RefID, format, expression, cfg.SQLExpressionCellLimit, cfg.SQLExpressionOutputCellLimit, cfg.SQLExpressionTimeout)
+	explainRaw := rn.Query["explain"]
+	explain, _ := explainRaw.(bool)
+
+	return NewSQLCommandWithOptions(rn.RefID, format, expression, SQLCommandOptions{
+		InputCellLimit:  cfg.SQLExpressionCellLimit,
+		InputRowLimit:   cfg.SQLExpressionInputRowLimit,
+		OutputCellLimit: cfg.SQLExpressionOutputCellLimit,
+		OutputRowLimit:  cfg.SQLExpressionOutputRowLimit,
+		MemoryLimit:     cfg.SQLExpressionMemoryLimit,
+		Timeout:         cfg.SQLExpressionTimeout,
+		Explain:         explain,
+	})
 }
 
 // NeedsVars returns the variable names (refIds) that are dependencies
@@ -111,6 +177,7 @@ func (gr *SQLCommand) Execute(ctx context.Context, now time.Time, vars mathexp.V
 	_, span := tracer.Start(ctx, "SSE.ExecuteSQL")
 	start := time.Now()
 	tc := int64(0)
+	rc := int64(0)
 
 	defer func() {
 		span.End()
@@ -122,6 +189,7 @@ func (gr *SQLCommand) Execute(ctx context.Context, now time.Time, vars mathexp.V
 		}
 		metrics.SqlCommandDuration.WithLabelValues(statusLabel).Observe(duration)
 		metrics.SqlCommandCellCount.WithLabelValues(statusLabel).Observe(float64(tc))
+		metrics.SqlCommandInputRowCount.WithLabelValues(statusLabel).Observe(float64(rc))
 	}()
 
 	allFrames := []*data.Frame{}
@@ -132,29 +200,55 @@ func (gr *SQLCommand) Execute(ctx context.Context, now time.Time, vars mathexp.V
 			continue
 		}
 		frames := results.Values.AsDataFrames(ref)
+		if gr.labelsColumn {
+			for i, frame := range frames {
+				withLabels, err := addLabelsField(frame)
+				if err != nil {
+					return mathexp.Results{}, fmt.Errorf("SQL expression: %w", err)
+				}
+				frames[i] = withLabels
+			}
+		}
 		allFrames = append(allFrames, frames...)
 	}
 
 	tc = totalCells(allFrames)
+	rc = totalRows(allFrames)
+
+	if gr.explain {
+		return gr.explainQuery(ctx, tracer, allFrames)
+	}
 
 	// limit of 0 or less means no limit (following convention)
 	if gr.inputLimit > 0 && tc > gr.inputLimit {
-		return mathexp.Results{},
-			fmt.Errorf(
-				"SQL expression: total cell count across all input tables exceeds limit of %d. Total cells: %d",
-				gr.inputLimit,
-				tc,
-			)
+		return mathexp.Results{}, gr.limitExceeded(metrics, sql.LimitInputCells, gr.inputLimit,
+			fmt.Errorf("total cell count across all input tables is %d", tc))
+	}
+	if gr.inputRowLimit > 0 && rc > gr.inputRowLimit {
+		return mathexp.Results{}, gr.limitExceeded(metrics, sql.LimitInputRows, gr.inputRowLimit,
+			fmt.Errorf("total row count across all input tables is %d", rc))
 	}
 
 	logger.Debug("Executing query", "query", gr.query, "frames", len(allFrames))
 
 	db := sql.DB{}
-	frame, err := db.QueryFrames(ctx, tracer, gr.refID, gr.query, allFrames, sql.WithMaxOutputCells(gr.outputLimit), sql.WithTimeout(gr.timeout))
+	frame, err := db.QueryFrames(ctx, tracer, gr.refID, gr.query, allFrames,
+		sql.WithMaxOutputCells(gr.outputLimit),
+		sql.WithMaxOutputRows(gr.outputRowLimit),
+		sql.WithMaxMemoryBytes(gr.memoryLimit),
+		sql.WithTimeout(gr.timeout),
+	)
 
 	rsp := mathexp.Results{}
 	if err != nil {
 		logger.Error("Failed to query frames", "error", err.Error())
+		var limitErr *sql.LimitExceededError
+		switch {
+		case errors.As(err, &limitErr):
+			err = gr.limitExceeded(metrics, limitErr.Limit, limitErr.Max, err)
+		case gr.timeout > 0 && errors.Is(err, context.DeadlineExceeded):
+			err = gr.limitExceeded(metrics, sql.LimitTimeout, gr.timeout.String(), err)
+		}
 		rsp.Error = err
 		return rsp, nil
 	}
@@ -192,6 +286,54 @@ func (gr *SQLCommand) Type() string {
 	return TypeSQL.String()
 }
 
+func (gr *SQLCommand) limitExceeded(m *metrics.ExprMetrics, limit sql.Limit, maxValue any, err error) error {
+	m.SqlCommandLimitExceeded.WithLabelValues(string(limit)).Inc()
+	return MakeSQLLimitExceededError(gr.refID, limit, maxValue, err)
+}
+
+// explainQuery returns the plan of the query and its estimated cost without running it.
+// The cost is in the custom metadata of the frame, and every limit that the input
+// already exceeds is added as a warning notice.
+func (gr *SQLCommand) explainQuery(ctx context.Context, tracer tracing.Tracer, frames []*data.Frame) (mathexp.Results, error) {
+	cost, err := sql.EstimateCost(gr.query, frames)
+	if err != nil {
+		return mathexp.Results{}, err
+	}
+
+	db := sql.DB{}
+	frame, err := db.ExplainFrames(ctx, tracer, gr.refID, gr.query, frames)
+	if err != nil {
+		return mathexp.Results{Error: err}, nil
+	}
+
+	frame.SetMeta(&data.FrameMeta{
+		ExecutedQueryString: gr.query,
+		Custom:              cost,
+	})
+	exceeded := func(limit sql.Limit, maxValue, value int64) {
+		if maxValue > 0 && value > maxValue {
+			frame.AppendNotices(data.Notice{
+				Severity: data.NoticeSeverityWarning,
+				Text:     fmt.Sprintf("%s exceeds limit of %d: %d", limit, maxValue, value),
+			})
+		}
+	}
+	exceeded(sql.LimitInputCells, gr.inputLimit, cost.InputCells)
+	exceeded(sql.LimitInputRows, gr.inputRowLimit, cost.InputRows)
+	exceeded(sql.LimitMemory, gr.memoryLimit, cost.InputBytes)
+
+	return mathexp.Results{Values: mathexp.Values{mathexp.TableData{Frame: frame}}}, nil
+}
+
+func totalRows(frames []*data.Frame) (total int64) {
+	for _, frame := range frames {
+		if frame != nil {
+			total += int64(frame.Rows())
+		}
+	}
+	return
+}
+
 func totalCells(frames []*data.Frame) (total int64) {
 	for _, frame := range frames {
 		if frame != nil {
@@ -258,14 +400,27 @@ func extractNumberSetFromSQLForAlerting(frame *data.Frame) ([]mathexp.Number, er
 				continue
 			}
 
-			val := f.At(i)
-			switch v := val.(type) {
+			var str *string
+			switch v := f.At(i).(type) {
 			case *string:
-				if v != nil {
-					labels[f.Name] = *v
-				}
+				str = v
 			case string:
-				labels[f.Name] = v
+				str = &v
+			}
+			if str == nil {
+				continue
+			}
+			if f.Name != SQLLabelsFieldName {
+				labels[f.Name] = *str
+				continue
+			}
+			// The labels column holds the whole label set as a JSON object.
+			var rowLabels map[string]string
+			if err := json.Unmarshal([]byte(*str), &rowLabels); err != nil {
+				return nil, fmt.Errorf("failed to parse %s column at row %d: %w", SQLLabelsFieldName, i, err)
+			}
+			for k, v := range rowLabels {
+				labels[k] = v
 			}
 		}
 
@@ -75,6 +75,31 @@ func TestExtractNumberSetFromSQLForAlerting(t *testing.T) {
 			"env":              "prod",
 		}, numbers[1].GetLabels())
 	})
+
+	t.Run("LabelsColumn", func(t *testing.T) {
+		input := data.NewFrame("",
+			data.NewField(SQLValueFieldName, nil, []*float64{fp(1.0), fp(2.0)}),
+			data.NewField(SQLLabelsFieldName, nil, []*string{sp(`{"env":"prod","host":"a"}`), sp(`{"host":"b"}`)}),
+			data.NewField("region", nil, []*string{sp("eu"), sp("us")}),
+		)
+
+		numbers, err := extractNumberSetFromSQLForAlerting(input)
+		require.NoError(t, err)
+		require.Len(t, numbers, 2)
+
+		require.Equal(t, data.Labels{"env": "prod", "host": "a", "region": "eu"}, numbers[0].GetLabels())
+		require.Equal(t, data.Labels{"host": "b", "region": "us"}, numbers[1].GetLabels())
+	})
+
+	t.Run("InvalidLabelsColumn", func(t *testing.T) {
+		input := data.NewFrame("",
+			data.NewField(SQLValueFieldName, nil, []*float64{fp(1.0)}),
+			data.NewField(SQLLabelsFieldName, nil, []string{"host=a"}),
+		)
+
+		_, err := extractNumberSetFromSQLForAlerting(input)
+		require.ErrorContains(t, err, "failed to parse __labels__ column at row 0")
+	})
 }
 
 func TestExtractNumberSetFromSQLForAlerting_Duplicates(t *testing.T) {
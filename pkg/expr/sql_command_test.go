@@ -11,6 +11,7 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/grafana/grafana/pkg/expr/mathexp"
 	"github.com/grafana/grafana/pkg/expr/metrics"
+	"github.com/grafana/grafana/pkg/expr/sql"
 	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel/trace"
@@ -148,6 +149,128 @@ func TestSQLCommandCellLimits(t *testing.T) {
 	}
 }
 
+func TestSQLCommandResourceLimits(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		opts      SQLCommandOptions
+		frames    []*data.Frame
+		limit     sql.Limit
+		errResult bool // the error is returned in the results rather than by Execute
+	}{
+		{
+			name:   "input rows",
+			query:  "select * from foo",
+			opts:   SQLCommandOptions{InputRowLimit: 9},
+			frames: []*data.Frame{createFrameWithRowsAndCols(10, 1)},
+			limit:  sql.LimitInputRows,
+		},
+		{
+			name:      "output rows",
+			query:     "select 1 as n union all select 2 union all select 3",
+			opts:      SQLCommandOptions{OutputRowLimit: 2},
+			limit:     sql.LimitOutputRows,
+			errResult: true,
+		},
+		{
+			name:      "memory",
+			query:     "select * from foo",
+			opts:      SQLCommandOptions{MemoryLimit: 100},
+			frames:    []*data.Frame{createFrameWithRowsAndCols(10, 1)},
+			limit:     sql.LimitMemory,
+			errResult: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := NewSQLCommandWithOptions("A", "", tt.query, tt.opts)
+			require.NoError(t, err)
+
+			vars := mathexp.Vars{}
+			for _, frame := range tt.frames {
+				vars["foo"] = mathexp.Results{Values: mathexp.Values{mathexp.TableData{Frame: frame}}}
+			}
+
+			m := metrics.NewTestMetrics()
+			res, err := cmd.Execute(context.Background(), time.Now(), vars, &testTracer{}, m)
+			if tt.errResult {
+				require.NoError(t, err)
+				err = res.Error
+			}
+			require.ErrorIs(t, err, SQLLimitExceededError)
+			require.ErrorContains(t, err, fmt.Sprintf("%s exceeds limit", tt.limit))
+			require.Equal(t, 1.0, testutil.ToFloat64(m.SqlCommandLimitExceeded.WithLabelValues(string(tt.limit))))
+		})
+	}
+}
+
+func TestSQLCommandExplain(t *testing.T) {
+	cmd, err := NewSQLCommandWithOptions("A", "", "select * from foo", SQLCommandOptions{
+		InputRowLimit: 5,
+		Explain:       true,
+	})
+	require.NoError(t, err)
+
+	frame := createFrameWithRowsAndCols(10, 1)
+	vars := mathexp.Vars{
+		"foo": mathexp.Results{Values: mathexp.Values{mathexp.TableData{Frame: frame}}},
+	}
+
+	res, err := cmd.Execute(context.Background(), time.Now(), vars, &testTracer{}, metrics.NewTestMetrics())
+	require.NoError(t, err)
+	require.NoError(t, res.Error)
+	require.Len(t, res.Values, 1)
+
+	plan := res.Values[0].AsDataFrame()
+	require.Greater(t, plan.Rows(), 0)
+	require.Equal(t, sql.QueryCost{InputRows: 10, InputCells: 10, InputBytes: 160, MaxRows: 10}, plan.Meta.Custom)
+	require.Len(t, plan.Meta.Notices, 1)
+	require.Equal(t, "input_rows exceeds limit of 5: 10", plan.Meta.Notices[0].Text)
+}
+
+func TestSQLCommandLabelsColumn(t *testing.T) {
+	newVars := func() mathexp.Vars {
+		frame := data.NewFrame("",
+			data.NewField(SQLValueFieldName, nil, []*float64{fp(1.0), fp(2.0)}),
+			data.NewField("host", nil, []*string{sp("a"), sp("b")}),
+		)
+		frame.Meta = &data.FrameMeta{Type: numericFullLongType}
+		return mathexp.Vars{
+			"A": mathexp.Results{Values: mathexp.Values{mathexp.TableData{Frame: frame}}},
+		}
+	}
+
+	t.Run("is not added when the query does not reference it", func(t *testing.T) {
+		cmd, err := NewSQLCommand("B", "", "SELECT * FROM A", 0, 0, 0)
+		require.NoError(t, err)
+
+		res, err := cmd.Execute(context.Background(), time.Now(), newVars(), &testTracer{}, metrics.NewTestMetrics())
+		require.NoError(t, err)
+		require.NoError(t, res.Error)
+
+		frame := res.Values[0].AsDataFrame()
+		require.Len(t, frame.Fields, 2)
+		require.Equal(t, SQLValueFieldName, frame.Fields[0].Name)
+		require.Equal(t, "host", frame.Fields[1].Name)
+	})
+
+	t.Run("is added when the query references it", func(t *testing.T) {
+		cmd, err := NewSQLCommand("B", "", "SELECT __labels__, __value__ FROM A ORDER BY __value__", 0, 0, 0)
+		require.NoError(t, err)
+
+		res, err := cmd.Execute(context.Background(), time.Now(), newVars(), &testTracer{}, metrics.NewTestMetrics())
+		require.NoError(t, err)
+		require.NoError(t, res.Error)
+
+		frame := res.Values[0].AsDataFrame()
+		require.Len(t, frame.Fields, 2)
+		require.Equal(t, SQLLabelsFieldName, frame.Fields[0].Name)
+		require.Equal(t, `{"host":"a"}`, frame.Fields[0].CopyAt(0))
+		require.Equal(t, `{"host":"b"}`, frame.Fields[0].CopyAt(1))
+	})
+}
+
 func TestSQLCommandMetrics(t *testing.T) {
 	// Create test metrics
 	m := metrics.NewTestMetrics()
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"slices"
 	"strings"
 	"time"
 
@@ -25,7 +27,11 @@ type ThresholdCommand struct {
 	RefID         string
 	ThresholdFunc ThresholdType
 	Invert        bool
+	// ThresholdVars holds, for each argument of the threshold function, the variable that provides its value
+	// for the series with matching labels. An empty string means that the constant argument is used.
+	ThresholdVars []string
 	predicate     predicate
+	conditions    []float64
 }
 
 // +enum
@@ -60,73 +66,114 @@ var (
 )
 
 func NewThresholdCommand(refID, referenceVar string, thresholdFunc ThresholdType, conditions []float64) (*ThresholdCommand, error) {
-	var predicate predicate
+	return NewThresholdCommandWithVars(refID, referenceVar, thresholdFunc, conditions, nil)
+}
+
+// NewThresholdCommandWithVars creates a ThresholdCommand whose arguments can be provided by other queries or expressions.
+// If thresholdVars[i] is not empty, the i-th argument is taken from the value of that variable that matches
+// the labels of each series, instead of conditions[i].
+func NewThresholdCommandWithVars(refID, referenceVar string, thresholdFunc ThresholdType, conditions []float64, thresholdVars []string) (*ThresholdCommand, error) {
+	var vars []string
+	args := slices.Clone(conditions)
+	for i, v := range thresholdVars {
+		v = strings.TrimPrefix(v, "$")
+		if v == "" {
+			continue
+		}
+		if v == referenceVar {
+			return nil, fmt.Errorf("threshold argument %d cannot reference the input variable %s", i+1, v)
+		}
+		if vars == nil {
+			vars = make([]string, len(thresholdVars))
+		}
+		vars[i] = v
+		// use a placeholder to validate the number of arguments, the value is resolved at execution time
+		for len(args) <= i {
+			args = append(args, 0)
+		}
+	}
+
+	predicate, err := newPredicate(thresholdFunc, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ThresholdCommand{
+		RefID:         refID,
+		ReferenceVar:  referenceVar,
+		ThresholdFunc: thresholdFunc,
+		ThresholdVars: vars,
+		predicate:     predicate,
+		conditions:    args,
+	}, nil
+}
+
+func newPredicate(thresholdFunc ThresholdType, conditions []float64) (predicate, error) {
 	switch thresholdFunc {
 	case ThresholdIsOutsideRange:
 		if len(conditions) < 2 {
 			return nil, fmt.Errorf("incorrect number of arguments for threshold function '%s': got %d but need 2", thresholdFunc, len(conditions))
 		}
-		predicate = outsideRangePredicate{left: conditions[0], right: conditions[1]}
+		return outsideRangePredicate{left: conditions[0], right: conditions[1]}, nil
 	case ThresholdIsWithinRange:
 		if len(conditions) < 2 {
 			return nil, fmt.Errorf("incorrect number of arguments for threshold function '%s': got %d but need 2", thresholdFunc, len(conditions))
 		}
-		predicate = withinRangePredicate{left: conditions[0], right: conditions[1]}
+		return withinRangePredicate{left: conditions[0], right: conditions[1]}, nil
 	case ThresholdIsWithinRangeIncluded:
 		if len(conditions) < 2 {
 			return nil, fmt.Errorf("incorrect number of arguments for threshold function '%s': got %d but need 2", thresholdFunc, len(conditions))
 		}
-		predicate = withinRangeIncludedPredicate{left: conditions[0], right: conditions[1]}
+		return withinRangeIncludedPredicate{left: conditions[0], right: conditions[1]}, nil
 	case ThresholdIsOutsideRangeIncluded:
 		if len(conditions) < 2 {
 			return nil, fmt.Errorf("incorrect number of arguments for threshold function '%s': got %d but need 2", thresholdFunc, len(conditions))
 		}
-		predicate = outsideRangeIncludedPredicate{left: conditions[0], right: conditions[1]}
+		return outsideRangeIncludedPredicate{left: conditions[0], right: conditions[1]}, nil
 	case ThresholdIsAbove:
 		if len(conditions) < 1 {
 			return nil, fmt.Errorf("incorrect number of arguments for threshold function '%s': got %d but need 1", thresholdFunc, len(conditions))
 		}
-		predicate = greaterThanPredicate{value: conditions[0]}
+		return greaterThanPredicate{value: conditions[0]}, nil
 	case ThresholdIsBelow:
 		if len(conditions) < 1 {
 			return nil, fmt.Errorf("incorrect number of arguments for threshold function '%s': got %d but need 1", thresholdFunc, len(conditions))
 		}
-		predicate = lessThanPredicate{value: conditions[0]}
+		return lessThanPredicate{value: conditions[0]}, nil
 	case ThresholdIsEqual:
 		if len(conditions) < 1 {
 			return nil, fmt.Errorf("incorrect number of arguments for threshold function '%s': got %d but need 1", thresholdFunc, len(conditions))
 		}
-		predicate = equalPredicate{value: conditions[0]}
+		return equalPredicate{value: conditions[0]}, nil
 	case ThresholdIsNotEqual:
 		if len(conditions) < 1 {
 			return nil, fmt.Errorf("incorrect number of arguments for threshold function '%s': got %d but need 1", thresholdFunc, len(conditions))
 		}
-		predicate = notEqualPredicate{value: conditions[0]}
+		return notEqualPredicate{value: conditions[0]}, nil
 	case ThresholdIsGreaterThanEqual:
 		if len(conditions) < 1 {
 			return nil, fmt.Errorf("incorrect number of arguments for threshold function '%s': got %d but need 1", thresholdFunc, len(conditions))
 		}
-		predicate = greaterThanEqualPredicate{value: conditions[0]}
+		return greaterThanEqualPredicate{value: conditions[0]}, nil
 	case ThresholdIsLessThanEqual:
 		if len(conditions) < 1 {
 			return nil, fmt.Errorf("incorrect number of arguments for threshold function '%s': got %d but need 1", thresholdFunc, len(conditions))
 		}
-		predicate = lessThanEqualPredicate{value: conditions[0]}
+		return lessThanEqualPredicate{value: conditions[0]}, nil
 	default:
 		return nil, fmt.Errorf("expected threshold function to be one of [%s], got %s", strings.Join(supportedThresholdFuncs, ", "), thresholdFunc)
 	}
 
-	return &ThresholdCommand{
-		RefID:         refID,
-		ReferenceVar:  referenceVar,
-		ThresholdFunc: thresholdFunc,
-		predicate:     predicate,
-	}, nil
+	return predicate, nil
 }
 
 type ConditionEvalJSON struct {
 	Params []float64     `json:"params"`
 	Type   ThresholdType `json:"type"` // e.g. "gt"
+
+	// References to queries or expressions (e.g. "$B") that provide the param at the same index for each series with matching labels.
+	// An empty reference means that the constant param is used.
+	ParamVars []string `json:"paramVars,omitempty"`
 }
 
 // UnmarshalResampleCommand creates a ResampleCMD from Grafana's frontend query.
@@ -146,24 +193,17 @@ func UnmarshalThresholdCommand(rn *rawNode) (Command, error) {
 	}
 	firstCondition := cmdConfig.Conditions[0]
 
-	threshold, err := NewThresholdCommand(rn.RefID, referenceVar, firstCondition.Evaluator.Type, firstCondition.Evaluator.Params)
+	threshold, err := NewThresholdCommandWithVars(rn.RefID, referenceVar, firstCondition.Evaluator.Type, firstCondition.Evaluator.Params, firstCondition.Evaluator.ParamVars)
 	if err != nil {
 		return nil, fmt.Errorf("invalid condition: %w", err)
 	}
 	if firstCondition.UnloadEvaluator != nil {
-		unloading, err := NewThresholdCommand(rn.RefID, referenceVar, firstCondition.UnloadEvaluator.Type, firstCondition.UnloadEvaluator.Params)
+		unloading, err := NewThresholdCommandWithVars(rn.RefID, referenceVar, firstCondition.UnloadEvaluator.Type, firstCondition.UnloadEvaluator.Params, firstCondition.UnloadEvaluator.ParamVars)
 		if err != nil {
 			return nil, fmt.Errorf("invalid unloadCondition: %w", err)
 		}
 		unloading.Invert = true
-		var d Fingerprints
-		if firstCondition.LoadedDimensions != nil {
-			d, err = FingerprintsFromFrame(firstCondition.LoadedDimensions)
-			if err != nil {
-				return nil, fmt.Errorf("failed to parse loaded dimensions: %w", err)
-			}
-		}
-		return NewHysteresisCommand(rn.RefID, referenceVar, *threshold, *unloading, d)
+		return newHysteresisCommandFromCondition(rn.RefID, referenceVar, *threshold, *unloading, firstCondition)
 	}
 	return threshold, nil
 }
@@ -171,15 +211,51 @@ func UnmarshalThresholdCommand(rn *rawNode) (Command, error) {
 // NeedsVars returns the variable names (refIds) that are dependencies
 // to execute the command and allows the command to fulfill the Command interface.
 func (tc *ThresholdCommand) NeedsVars() []string {
-	return []string{tc.ReferenceVar}
+	vars := []string{tc.ReferenceVar}
+	for _, v := range tc.ThresholdVars {
+		if v != "" && !slices.Contains(vars, v) {
+			vars = append(vars, v)
+		}
+	}
+	return vars
 }
 
 func (tc *ThresholdCommand) Execute(_ context.Context, _ time.Time, vars mathexp.Vars, _ tracing.Tracer, _ *metrics.ExprMetrics) (mathexp.Results, error) {
+	refVarResult := vars[tc.ReferenceVar]
+	newRes := mathexp.Results{Values: make(mathexp.Values, 0, len(refVarResult.Values))}
+	for _, val := range refVarResult.Values {
+		_, isNoData := val.(mathexp.NoData)
+		if len(tc.ThresholdVars) == 0 || isNoData {
+			result, err := tc.evaluate(val, val.GetLabels(), tc.predicate)
+			if err != nil {
+				return newRes, err
+			}
+			newRes.Values = append(newRes.Values, result)
+			continue
+		}
+		thresholds, err := tc.matchThresholds(val, vars)
+		if err != nil {
+			return newRes, err
+		}
+		for _, threshold := range thresholds {
+			result, err := tc.evaluate(val, threshold.labels, threshold.predicate)
+			if err != nil {
+				return newRes, err
+			}
+			newRes.Values = append(newRes.Values, result)
+		}
+	}
+	return newRes, nil
+}
+
+// evaluate applies the predicate to the value and returns the result with the given labels.
+// If the predicate is nil, which happens when a threshold has no value, the result is null.
+func (tc *ThresholdCommand) evaluate(val mathexp.Value, labels data.Labels, predicate predicate) (mathexp.Value, error) {
 	eval := func(maybeValue *float64) *float64 {
-		if maybeValue == nil {
+		if maybeValue == nil || predicate == nil {
 			return nil
 		}
-		result := tc.predicate.Eval(*maybeValue)
+		result := predicate.Eval(*maybeValue)
 		if tc.Invert {
 			result = !result
 		}
@@ -189,31 +265,90 @@ func (tc *ThresholdCommand) Execute(_ context.Context, _ time.Time, vars mathexp
 		return util.Pointer(float64(0))
 	}
 
-	refVarResult := vars[tc.ReferenceVar]
-	newRes := mathexp.Results{Values: make(mathexp.Values, 0, len(refVarResult.Values))}
-	for _, val := range refVarResult.Values {
-		switch v := val.(type) {
-		case mathexp.Series:
-			s := mathexp.NewSeries(tc.RefID, v.GetLabels(), v.Len())
-			for i := 0; i < v.Len(); i++ {
-				t, value := v.GetPoint(i)
-				s.SetPoint(i, t, eval(value))
+	switch v := val.(type) {
+	case mathexp.Series:
+		s := mathexp.NewSeries(tc.RefID, labels, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			t, value := v.GetPoint(i)
+			s.SetPoint(i, t, eval(value))
+		}
+		return s, nil
+	case mathexp.Number:
+		copyV := mathexp.NewNumber(tc.RefID, labels)
+		copyV.SetValue(eval(v.GetFloat64Value()))
+		return copyV, nil
+	case mathexp.Scalar:
+		return mathexp.NewScalar(tc.RefID, eval(v.GetFloat64Value())), nil
+	case mathexp.NoData:
+		return mathexp.NewNoData(), nil
+	default:
+		return nil, fmt.Errorf("unsupported format of the input data, got type %v", val.Type())
+	}
+}
+
+// dynamicThreshold is the threshold for a value whose arguments are provided by other variables.
+type dynamicThreshold struct {
+	labels    data.Labels
+	predicate predicate
+}
+
+// matchThresholds resolves the arguments provided by ThresholdVars for the value by matching their labels
+// the same way as binary operations in math expressions. The value gets one threshold for each combination
+// of matching arguments, or none if an argument has no matching value.
+func (tc *ThresholdCommand) matchThresholds(val mathexp.Value, vars mathexp.Vars) ([]dynamicThreshold, error) {
+	type candidate struct {
+		labels data.Labels
+		args   []float64
+		isNull bool
+	}
+	candidates := []candidate{{labels: val.GetLabels(), args: tc.conditions}}
+	for i, v := range tc.ThresholdVars {
+		if v == "" {
+			continue
+		}
+		var next []candidate
+		for _, c := range candidates {
+			placeholder := mathexp.Results{Values: mathexp.Values{mathexp.NewNumber("", c.labels)}}
+			for _, u := range mathexp.Unions(placeholder, vars[v]) {
+				var f *float64
+				switch b := u.B.(type) {
+				case mathexp.Number:
+					f = b.GetFloat64Value()
+				case mathexp.Scalar:
+					f = b.GetFloat64Value()
+				case mathexp.NoData:
+					continue
+				default:
+					return nil, fmt.Errorf("threshold argument %d must be a number, but %s is of type %v", i+1, v, u.B.Type())
+				}
+				args := slices.Clone(c.args)
+				if f != nil {
+					args[i] = *f
+				}
+				labels := u.Labels
+				if len(labels) == 0 {
+					// values without labels apply to every series, keep the labels of the series
+					labels = c.labels
+				}
+				next = append(next, candidate{labels: labels, args: args, isNull: c.isNull || f == nil || math.IsNaN(*f)})
 			}
-			newRes.Values = append(newRes.Values, s)
-		case mathexp.Number:
-			copyV := mathexp.NewNumber(tc.RefID, v.GetLabels())
-			copyV.SetValue(eval(v.GetFloat64Value()))
-			newRes.Values = append(newRes.Values, copyV)
-		case mathexp.Scalar:
-			copyV := mathexp.NewScalar(tc.RefID, eval(v.GetFloat64Value()))
-			newRes.Values = append(newRes.Values, copyV)
-		case mathexp.NoData:
-			newRes.Values = append(newRes.Values, mathexp.NewNoData())
-		default:
-			return newRes, fmt.Errorf("unsupported format of the input data, got type %v", val.Type())
 		}
+		candidates = next
 	}
-	return newRes, nil
+
+	thresholds := make([]dynamicThreshold, 0, len(candidates))
+	for _, c := range candidates {
+		threshold := dynamicThreshold{labels: c.labels}
+		if !c.isNull {
+			p, err := newPredicate(tc.ThresholdFunc, c.args)
+			if err != nil {
+				return nil, err
+			}
+			threshold.predicate = p
+		}
+		thresholds = append(thresholds, threshold)
+	}
+	return thresholds, nil
 }
 
 func (tc *ThresholdCommand) Type() string {
@@ -240,6 +375,17 @@ type ThresholdConditionJSON struct {
 	Evaluator        ConditionEvalJSON  `json:"evaluator"`
 	UnloadEvaluator  *ConditionEvalJSON `json:"unloadEvaluator,omitempty"`
 	LoadedDimensions *data.Frame        `json:"loadedDimensions,omitempty"`
+
+	// The number of consecutive evaluations that a series must be beyond the threshold before it fires. Requires unloadEvaluator.
+	PendingEvaluations int64 `json:"pendingEvaluations,omitempty"`
+	// The duration that a series must be beyond the threshold before it fires, e.g. "5m". Requires unloadEvaluator.
+	PendingPeriod string `json:"pendingPeriod,omitempty"`
+	// The number of consecutive evaluations that a firing series must be beyond the unload threshold before it recovers.
+	RecoveryEvaluations int64 `json:"recoveryEvaluations,omitempty"`
+	// The duration that a firing series must be beyond the unload threshold before it recovers, e.g. "5m".
+	RecoveryPeriod string `json:"recoveryPeriod,omitempty"`
+	// The series that are waiting to fire or recover. It is set by the alerting engine.
+	Streaks *data.Frame `json:"streaks,omitempty"`
 }
 
 // IsHysteresisExpression returns true if the raw model describes a hysteresis command:
@@ -268,6 +414,19 @@ func SetLoadedDimensionsToHysteresisCommand(query map[string]any, fingerprints F
 	return nil
 }
 
+// SetHysteresisStreaksToHysteresisCommand mutates the input map and sets field "conditions[0].streaks" with the data frame created from the provided streaks.
+func SetHysteresisStreaksToHysteresisCommand(query map[string]any, streaks HysteresisStreaks) error {
+	condition, err := getConditionForHysteresisCommand(query)
+	if err != nil {
+		return err
+	}
+	if condition == nil {
+		return errors.New("not a hysteresis command")
+	}
+	condition["streaks"] = HysteresisStreaksToFrame(streaks)
+	return nil
+}
+
 func getConditionForHysteresisCommand(query map[string]any) (map[string]any, error) {
 	t, err := GetExpressionCommandType(query)
 	if err != nil {
@@ -183,6 +183,26 @@ func TestUnmarshalThresholdCommand(t *testing.T) {
 				require.Equal(t, greaterThanPredicate{20.0}, cmd.predicate)
 			},
 		},
+		{
+			description: "unmarshal with threshold from another variable",
+			query: `{
+				"expression" : "A",
+				"type": "threshold",
+				"conditions": [{
+					"evaluator": {
+						"type": "gt",
+						"params": [],
+						"paramVars": ["$B"]
+					}
+				}]
+			}`,
+			assert: func(t *testing.T, command Command) {
+				require.IsType(t, &ThresholdCommand{}, command)
+				cmd := command.(*ThresholdCommand)
+				require.Equal(t, []string{"A", "B"}, cmd.NeedsVars())
+				require.Equal(t, []string{"B"}, cmd.ThresholdVars)
+			},
+		},
 		{
 			description: "unmarshal with missing conditions should error",
 			query: `{
@@ -262,6 +282,58 @@ func TestUnmarshalThresholdCommand(t *testing.T) {
 				require.EqualValues(t, []uint64{2, 3, 4, 5, 18446744073709551615}, actual)
 			},
 		},
+		{
+			description: "unmarshal hysteresis command with delayed transitions",
+			query: `{
+				"expression": "B",
+				"conditions": [{
+					"evaluator": { "params": [100], "type": "gt" },
+					"unloadEvaluator": { "params": [31], "type": "lt" },
+					"pendingEvaluations": 3,
+					"pendingPeriod": "5m",
+					"recoveryEvaluations": 2,
+					"streaks": {"schema":{"meta":{"type":"hysteresis_streaks","typeVersion":[1,0]},"fields":[{"name":"fingerprints","type":"number","typeInfo":{"frame":"uint64"}},{"name":"evaluations","type":"number","typeInfo":{"frame":"int64"}},{"name":"since","type":"time","typeInfo":{"frame":"time.Time"}}]},"data":{"values":[[5],[2],[1700000000000]]}}
+				}]
+			}`,
+			assert: func(t *testing.T, c Command) {
+				require.IsType(t, &HysteresisCommand{}, c)
+				cmd := c.(*HysteresisCommand)
+				require.Equal(t, HysteresisTransitions{
+					PendingEvaluations:  3,
+					PendingPeriod:       5 * time.Minute,
+					RecoveryEvaluations: 2,
+				}, cmd.Transitions)
+				require.Len(t, cmd.Streaks, 1)
+				require.Equal(t, int64(2), cmd.Streaks[5].Evaluations)
+				require.True(t, time.UnixMilli(1700000000000).Equal(cmd.Streaks[5].Since))
+			},
+		},
+		{
+			description: "unmarshal hysteresis command with invalid pending period should error",
+			query: `{
+				"expression": "B",
+				"conditions": [{
+					"evaluator": { "params": [100], "type": "gt" },
+					"unloadEvaluator": { "params": [31], "type": "lt" },
+					"pendingPeriod": "soon"
+				}]
+			}`,
+			shouldError:   true,
+			expectedError: `failed to parse "pendingPeriod" duration field`,
+		},
+		{
+			description: "unmarshal hysteresis command with negative evaluations should error",
+			query: `{
+				"expression": "B",
+				"conditions": [{
+					"evaluator": { "params": [100], "type": "gt" },
+					"unloadEvaluator": { "params": [31], "type": "lt" },
+					"recoveryEvaluations": -1
+				}]
+			}`,
+			shouldError:   true,
+			expectedError: "must not be negative",
+		},
 	}
 
 	for _, tc := range cases {
@@ -472,6 +544,40 @@ func TestSetLoadedDimensionsToHysteresisCommand(t *testing.T) {
 	})
 }
 
+func TestSetHysteresisStreaksToHysteresisCommand(t *testing.T) {
+	t.Run("error if condition does not have unloadEvaluator", func(t *testing.T) {
+		query := map[string]any{}
+		require.NoError(t, json.Unmarshal([]byte(`{ "type": "threshold", "conditions": [{ "evaluator": { "params": [5], "type": "gt"}}], "expression": "A" }`), &query))
+		require.Error(t, SetHysteresisStreaksToHysteresisCommand(query, HysteresisStreaks{1: {Evaluations: 1}}))
+	})
+
+	t.Run("when unloadEvaluator is set, mutates query with streaks", func(t *testing.T) {
+		streaks := HysteresisStreaks{
+			math.MaxUint64: {Evaluations: 1, Since: time.UnixMilli(1700000000000)},
+			2:              {Evaluations: 3, Since: time.UnixMilli(1700000060000)},
+		}
+		input := json.RawMessage(`{ "type": "threshold", "conditions": [{ "evaluator": { "params": [5], "type": "gt" }, "unloadEvaluator" : {"params": [2], "type": "lt"}, "pendingEvaluations": 3}], "expression": "A" }`)
+		query := map[string]any{}
+		require.NoError(t, json.Unmarshal(input, &query))
+		require.NoError(t, SetHysteresisStreaksToHysteresisCommand(query, streaks))
+		raw, err := json.Marshal(query)
+		require.NoError(t, err)
+
+		cmd, err := UnmarshalThresholdCommand(&rawNode{
+			RefID:    "B",
+			QueryRaw: raw,
+		})
+		require.NoError(t, err)
+
+		actual := cmd.(*HysteresisCommand).Streaks
+		require.Len(t, actual, len(streaks))
+		for fp, streak := range streaks {
+			require.Equal(t, streak.Evaluations, actual[fp].Evaluations)
+			require.True(t, streak.Since.Equal(actual[fp].Since))
+		}
+	})
+}
+
 func TestThresholdExecute(t *testing.T) {
 	input := map[string]mathexp.Value{
 		//
@@ -646,3 +752,78 @@ func TestThresholdExecute(t *testing.T) {
 		})
 	}
 }
+
+func TestThresholdExecuteWithVars(t *testing.T) {
+	hostA := data.Labels{"host": "a"}
+	hostB := data.Labels{"host": "b"}
+	hostC := data.Labels{"host": "c"}
+	input := newResults(
+		newNumber(hostA, util.Pointer(10.0)),
+		newNumber(hostB, util.Pointer(10.0)),
+		newNumber(hostC, util.Pointer(10.0)),
+	)
+
+	t.Run("should use the threshold with matching labels and drop series without a threshold", func(t *testing.T) {
+		cmd, err := NewThresholdCommandWithVars("", "A", ThresholdIsAbove, nil, []string{"$B"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"A", "B"}, cmd.NeedsVars())
+
+		result, err := cmd.Execute(context.Background(), time.Now(), mathexp.Vars{
+			"A": input,
+			"B": newResults(
+				newNumber(hostA, util.Pointer(5.0)),
+				newNumber(hostB, util.Pointer(20.0)),
+			),
+		}, tracing.InitializeTracerForTest(), nil)
+		require.NoError(t, err)
+		require.Equal(t, newResults(
+			newNumber(hostA, util.Pointer(1.0)),
+			newNumber(hostB, util.Pointer(0.0)),
+		), result)
+	})
+
+	t.Run("should keep the labels of the series if the threshold has no labels", func(t *testing.T) {
+		cmd, err := NewThresholdCommandWithVars("", "A", ThresholdIsWithinRange, []float64{0}, []string{"", "B"})
+		require.NoError(t, err)
+
+		result, err := cmd.Execute(context.Background(), time.Now(), mathexp.Vars{
+			"A": newResults(newSeriesWithLabels(hostA, util.Pointer(5.0), util.Pointer(15.0))),
+			"B": newResults(newScalar(util.Pointer(12.0))),
+		}, tracing.InitializeTracerForTest(), nil)
+		require.NoError(t, err)
+		require.Equal(t, newResults(newSeriesWithLabels(hostA, util.Pointer(1.0), util.Pointer(0.0))), result)
+	})
+
+	t.Run("should return null if the threshold is null", func(t *testing.T) {
+		cmd, err := NewThresholdCommandWithVars("", "A", ThresholdIsAbove, nil, []string{"B"})
+		require.NoError(t, err)
+
+		result, err := cmd.Execute(context.Background(), time.Now(), mathexp.Vars{
+			"A": newResults(newNumber(hostA, util.Pointer(10.0))),
+			"B": newResults(newNumber(hostA, nil)),
+		}, tracing.InitializeTracerForTest(), nil)
+		require.NoError(t, err)
+		require.Equal(t, newResults(newNumber(hostA, nil)), result)
+	})
+
+	t.Run("should fail if the threshold is a series", func(t *testing.T) {
+		cmd, err := NewThresholdCommandWithVars("", "A", ThresholdIsAbove, nil, []string{"B"})
+		require.NoError(t, err)
+
+		_, err = cmd.Execute(context.Background(), time.Now(), mathexp.Vars{
+			"A": newResults(newNumber(hostA, util.Pointer(10.0))),
+			"B": newResults(newSeriesWithLabels(hostA, util.Pointer(1.0))),
+		}, tracing.InitializeTracerForTest(), nil)
+		require.ErrorContains(t, err, "must be a number")
+	})
+
+	t.Run("should fail if the threshold references the input", func(t *testing.T) {
+		_, err := NewThresholdCommandWithVars("", "A", ThresholdIsAbove, nil, []string{"$A"})
+		require.Error(t, err)
+	})
+
+	t.Run("should fail if arguments are missing", func(t *testing.T) {
+		_, err := NewThresholdCommandWithVars("", "A", ThresholdIsWithinRange, nil, []string{"B"})
+		require.Error(t, err)
+	})
+}
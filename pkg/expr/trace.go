@@ -0,0 +1,238 @@
+package expr
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+)
+
+// DebugTraceRefID is the key of the response that holds the trace of the pipeline
+// when the request has Debug set.
+const DebugTraceRefID = "__debug_trace__"
+
+const (
+	nodeStatusOK      = "ok"
+	nodeStatusError   = "error"
+	nodeStatusSkipped = "skipped"
+)
+
+// pipelineTrace records what every node of a pipeline did, in the order the nodes were executed.
+type pipelineTrace struct {
+	nodes []nodeTrace
+}
+
+type nodeTrace struct {
+	refID    string
+	nodeType NodeType
+	// kind is the command type of expressions, or the type of the data source.
+	kind   string
+	inputs []string
+	// duration is nil if the node was not executed on its own, e.g. grouped data source queries.
+	duration *time.Duration
+	status   string
+	err      error
+	input    string
+	output   resultsSummary
+	dropped  []string
+}
+
+// resultsSummary describes the shape of the values of a node.
+type resultsSummary struct {
+	series, points, numbers, scalars, tables, noData int
+	labels                                           []data.Labels
+}
+
+func summarizeResults(res mathexp.Results) resultsSummary {
+	s := resultsSummary{}
+	for _, v := range res.Values {
+		switch v := v.(type) {
+		case mathexp.Series:
+			s.series++
+			s.points += v.Len()
+		case mathexp.Number:
+			s.numbers++
+		case mathexp.Scalar:
+			s.scalars++
+		case mathexp.TableData:
+			s.tables++
+		case mathexp.NoData:
+			s.noData++
+			continue
+		}
+		if v.GetLabels() != nil {
+			s.labels = append(s.labels, v.GetLabels())
+		}
+	}
+	return s
+}
+
+// String returns the shape, e.g. "2 series (20 points), 1 no data".
+func (s resultsSummary) String() string {
+	var parts []string
+	add := func(n int, what string) {
+		if n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, what))
+		}
+	}
+	if s.series > 0 {
+		parts = append(parts, fmt.Sprintf("%d series (%d points)", s.series, s.points))
+	}
+	add(s.numbers, "numbers")
+	add(s.scalars, "scalars")
+	add(s.tables, "tables")
+	add(s.noData, "no data")
+	if len(parts) == 0 {
+		return "empty"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// record adds the results of the node, which must already be in vars, to the trace.
+// skipped is true if the node was not executed because one of its inputs failed.
+func (t *pipelineTrace) record(node Node, vars mathexp.Vars, duration *time.Duration, skipped bool) {
+	if t == nil {
+		return
+	}
+	res := vars[node.RefID()]
+	nt := nodeTrace{
+		refID:    node.RefID(),
+		nodeType: node.NodeType(),
+		kind:     node.String(),
+		inputs:   node.NeedsVars(),
+		duration: duration,
+		status:   nodeStatusOK,
+		err:      res.Error,
+		output:   summarizeResults(res),
+	}
+	switch n := node.(type) {
+	case *CMDNode:
+		nt.kind = n.CMDType.String()
+	case *MLNode:
+		nt.kind = fmt.Sprintf("ml_%s", n.command.Type())
+	}
+	switch {
+	case skipped:
+		nt.status = nodeStatusSkipped
+	case res.Error != nil:
+		nt.status = nodeStatusError
+	}
+
+	var inputs []string
+	var inputLabels []data.Labels
+	for _, refID := range nt.inputs {
+		in := summarizeResults(vars[refID])
+		inputs = append(inputs, fmt.Sprintf("%s: %s", refID, in))
+		inputLabels = append(inputLabels, in.labels...)
+	}
+	nt.input = strings.Join(inputs, "; ")
+	nt.dropped = droppedLabels(inputLabels, nt.output.labels)
+
+	t.nodes = append(t.nodes, nt)
+}
+
+// droppedLabels returns the label sets of the inputs that are not part of any label set of the outputs.
+// For example, a reduce in drop mode or a math operation that found no matching series drops them.
+func droppedLabels(inputs, outputs []data.Labels) []string {
+	if len(outputs) == 0 && len(inputs) > 0 {
+		// The node returned no labeled values, e.g. it returned no data or a scalar.
+		return nil
+	}
+	seen := map[data.Fingerprint]struct{}{}
+	var dropped []string
+	for _, in := range inputs {
+		fp := in.Fingerprint()
+		if _, ok := seen[fp]; ok {
+			continue
+		}
+		seen[fp] = struct{}{}
+		kept := false
+		for _, out := range outputs {
+			if out.Contains(in) || in.Contains(out) {
+				kept = true
+				break
+			}
+		}
+		if !kept {
+			dropped = append(dropped, in.String())
+		}
+	}
+	sort.Strings(dropped)
+	return dropped
+}
+
+// Frame returns the trace as a table with one row per node.
+func (t *pipelineTrace) Frame() *data.Frame {
+	n := len(t.nodes)
+	refIDs := make([]string, n)
+	nodeTypes := make([]string, n)
+	kinds := make([]string, n)
+	inputs := make([]string, n)
+	durations := make([]*float64, n)
+	statuses := make([]string, n)
+	errs := make([]*string, n)
+	inputShapes := make([]string, n)
+	outputShapes := make([]string, n)
+	series := make([]int64, n)
+	noData := make([]int64, n)
+	labels := make([]json.RawMessage, n)
+	dropped := make([]json.RawMessage, n)
+
+	for i, nt := range t.nodes {
+		refIDs[i] = nt.refID
+		nodeTypes[i] = nt.nodeType.String()
+		kinds[i] = nt.kind
+		inputs[i] = strings.Join(nt.inputs, ",")
+		if nt.duration != nil {
+			ms := float64(nt.duration.Microseconds()) / 1000
+			durations[i] = &ms
+		}
+		statuses[i] = nt.status
+		if nt.err != nil {
+			msg := nt.err.Error()
+			errs[i] = &msg
+		}
+		inputShapes[i] = nt.input
+		outputShapes[i] = nt.output.String()
+		series[i] = int64(nt.output.series)
+		noData[i] = int64(nt.output.noData)
+
+		labelSets := make([]string, 0, len(nt.output.labels))
+		for _, l := range nt.output.labels {
+			labelSets = append(labelSets, l.String())
+		}
+		labels[i] = marshalStrings(labelSets)
+		if nt.dropped == nil {
+			nt.dropped = []string{}
+		}
+		dropped[i] = marshalStrings(nt.dropped)
+	}
+
+	frame := data.NewFrame("Expression pipeline trace",
+		data.NewField("refId", nil, refIDs),
+		data.NewField("nodeType", nil, nodeTypes),
+		data.NewField("kind", nil, kinds),
+		data.NewField("inputs", nil, inputs),
+		data.NewField("status", nil, statuses),
+		data.NewField("error", nil, errs),
+		data.NewField("durationMs", nil, durations),
+		data.NewField("inputShape", nil, inputShapes),
+		data.NewField("outputShape", nil, outputShapes),
+		data.NewField("series", nil, series),
+		data.NewField("noData", nil, noData),
+		data.NewField("labels", nil, labels),
+		data.NewField("droppedLabels", nil, dropped),
+	)
+	frame.RefID = DebugTraceRefID
+	return frame
+}
+
+func marshalStrings(v []string) json.RawMessage {
+	b, _ := json.Marshal(v) // a slice of strings always marshals
+	return b
+}
@@ -0,0 +1,155 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/datasources"
+)
+
+func TestExecutePipelineWithTrace(t *testing.T) {
+	dsQuery := Query{
+		RefID: "A",
+		DataSource: &datasources.DataSource{
+			OrgID: 1,
+			UID:   "test",
+			Type:  "test",
+		},
+		JSON: json.RawMessage(`{ "datasource": { "uid": "1" }, "intervalMs": 1000, "maxDataPoints": 1000 }`),
+		TimeRange: AbsoluteTimeRange{
+			From: time.Time{},
+			To:   time.Time{},
+		},
+	}
+
+	t.Run("describes every node", func(t *testing.T) {
+		resp := map[string]backend.DataResponse{
+			"A": {Frames: data.Frames{
+				data.NewFrame("test",
+					data.NewField("time", nil, []time.Time{time.Unix(1, 0), time.Unix(2, 0)}),
+					data.NewField("value", data.Labels{"host": "a"}, []*float64{fp(2), fp(3)}),
+				),
+				data.NewFrame("test",
+					data.NewField("time", nil, []time.Time{time.Unix(1, 0), time.Unix(2, 0)}),
+					data.NewField("value", data.Labels{"host": "b"}, []*float64{nil, nil}),
+				),
+			}},
+		}
+		queries := []Query{
+			dsQuery,
+			{
+				RefID:      "B",
+				DataSource: dataSourceModel(),
+				JSON:       json.RawMessage(`{ "datasource": { "uid": "__expr__", "type": "__expr__"}, "type": "reduce", "expression": "$A", "reducer": "last" }`),
+			},
+			{
+				RefID:      "C",
+				DataSource: dataSourceModel(),
+				JSON:       json.RawMessage(`{ "datasource": { "uid": "__expr__", "type": "__expr__"}, "type": "reduce", "expression": "$B", "reducer": "last", "settings": { "mode": "dropNN" } }`),
+			},
+		}
+
+		s, req := newMockQueryService(resp, queries)
+		pl, err := s.BuildPipeline(req)
+		require.NoError(t, err)
+
+		res, err := s.ExecutePipelineWithTrace(context.Background(), time.Now(), pl)
+		require.NoError(t, err)
+		require.Contains(t, res.Responses, "C")
+
+		trace := traceRows(t, res)
+		require.Len(t, trace, 3)
+
+		require.Equal(t, "A", trace[0]["refId"])
+		require.Equal(t, "Datasource", trace[0]["nodeType"])
+		require.Equal(t, "test", trace[0]["kind"])
+		require.Equal(t, "ok", trace[0]["status"])
+		require.Equal(t, "2 series (4 points)", trace[0]["outputShape"])
+
+		require.Equal(t, "B", trace[1]["refId"])
+		require.Equal(t, "reduce", trace[1]["kind"])
+		require.Equal(t, "A", trace[1]["inputs"])
+		require.Equal(t, "A: 2 series (4 points)", trace[1]["inputShape"])
+		require.Equal(t, "2 numbers", trace[1]["outputShape"])
+		require.JSONEq(t, `["host=a", "host=b"]`, string(trace[1]["labels"].(json.RawMessage)))
+		require.JSONEq(t, `[]`, string(trace[1]["droppedLabels"].(json.RawMessage)))
+		require.NotNil(t, trace[1]["durationMs"])
+
+		require.Equal(t, "C", trace[2]["refId"])
+		require.Equal(t, "B: 2 numbers", trace[2]["inputShape"])
+		require.Equal(t, "1 numbers", trace[2]["outputShape"])
+		require.JSONEq(t, `["host=a"]`, string(trace[2]["labels"].(json.RawMessage)))
+		require.JSONEq(t, `["host=b"]`, string(trace[2]["droppedLabels"].(json.RawMessage)))
+	})
+
+	t.Run("marks nodes that did not run", func(t *testing.T) {
+		resp := map[string]backend.DataResponse{
+			"A": {Error: fmt.Errorf("womp womp")},
+		}
+		queries := []Query{
+			dsQuery,
+			{
+				RefID:      "B",
+				DataSource: dataSourceModel(),
+				JSON:       json.RawMessage(`{ "datasource": { "uid": "__expr__", "type": "__expr__"}, "type": "math", "expression": "$A * 2" }`),
+			},
+		}
+
+		s, req := newMockQueryService(resp, queries)
+		pl, err := s.BuildPipeline(req)
+		require.NoError(t, err)
+
+		res, err := s.ExecutePipelineWithTrace(context.Background(), time.Now(), pl)
+		require.NoError(t, err)
+
+		trace := traceRows(t, res)
+		require.Len(t, trace, 2)
+		require.Equal(t, "error", trace[0]["status"])
+		require.Contains(t, *trace[0]["error"].(*string), "womp womp")
+		require.Equal(t, "skipped", trace[1]["status"])
+		require.Nil(t, trace[1]["durationMs"])
+	})
+}
+
+// traceRows returns the rows of the trace frame as maps of field name to value.
+func traceRows(t *testing.T, res *backend.QueryDataResponse) []map[string]any {
+	t.Helper()
+	require.Contains(t, res.Responses, DebugTraceRefID)
+	frames := res.Responses[DebugTraceRefID].Frames
+	require.Len(t, frames, 1)
+
+	rows := make([]map[string]any, frames[0].Rows())
+	for i := range rows {
+		rows[i] = map[string]any{}
+		for _, f := range frames[0].Fields {
+			v := f.At(i)
+			if p, ok := v.(*float64); ok && p == nil {
+				v = nil
+			}
+			rows[i][f.Name] = v
+		}
+	}
+	return rows
+}
+
+func TestDroppedLabels(t *testing.T) {
+	a := data.Labels{"host": "a"}
+	b := data.Labels{"host": "b"}
+
+	require.Equal(t, []string{"host=b"}, droppedLabels([]data.Labels{a, b}, []data.Labels{a}))
+	require.Empty(t, droppedLabels([]data.Labels{a, b}, []data.Labels{{"host": "a", "env": "prod"}, b}))
+	require.Empty(t, droppedLabels([]data.Labels{a, b}, nil))
+}
+
+func TestResultsSummaryString(t *testing.T) {
+	require.Equal(t, "empty", resultsSummary{}.String())
+	require.Equal(t, "2 series (20 points), 1 no data", resultsSummary{series: 2, points: 20, noData: 1}.String())
+	require.Equal(t, "3 numbers, 1 scalars", resultsSummary{numbers: 3, scalars: 1}.String())
+}
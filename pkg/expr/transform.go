@@ -93,8 +93,12 @@ func (s *Service) TransformData(ctx context.Context, now time.Time, req *Request
 		return nil, err
 	}
 
-	// Execute the pipeline
-	responses, err := s.ExecutePipeline(ctx, now, pipeline)
+	// Execute the pipeline, with a trace of every node if the request asks for it
+	execute := s.ExecutePipeline
+	if req.Debug {
+		execute = s.ExecutePipelineWithTrace
+	}
+	responses, err := execute(ctx, now, pipeline)
 	if err != nil {
 		return nil, err
 	}
@@ -173,7 +173,7 @@ func (r *LokiHistorianStore) annotationsFromStream(stream historian.Stream, ac a
 			historymodel.RuleMeta{
 				Title: entry.RuleTitle,
 			},
-			transition.State,
+			*transition,
 		)
 
 		items = append(items, &annotations.ItemDTO{
@@ -260,7 +260,7 @@ func buildTransition(entry historian.LokiEntry) (*state.StateTransition, error)
 		return nil, fmt.Errorf("parsing entry values: %w", err)
 	}
 
-	return &state.StateTransition{
+	transition := &state.StateTransition{
 		State: &state.State{
 			State:       curState,
 			StateReason: curStateReason,
@@ -269,7 +269,21 @@ func buildTransition(entry historian.LokiEntry) (*state.StateTransition, error)
 		},
 		PreviousState:       prevState,
 		PreviousStateReason: prevReason,
-	}, nil
+	}
+	if ack := entry.Acknowledgement; ack != nil {
+		record := &ngmodels.AlertInstanceAcknowledgement{
+			By:        ack.By,
+			Comment:   ack.Comment,
+			ExpiresAt: ack.ExpiresAt,
+		}
+		transition.AcknowledgementChange = &state.AcknowledgementChange{}
+		if ack.Acknowledged {
+			transition.Acknowledgement = record
+		} else {
+			transition.AcknowledgementChange.Previous = record
+		}
+	}
+	return transition, nil
 }
 
 func buildHistoryQuery(query *annotations.ItemQuery, dashboards map[string]int64, ruleUID string) ngmodels.HistoryQuery {
@@ -72,15 +72,21 @@ type API struct {
 	ContactPointService  *provisioning.ContactPointService
 	Templates            *provisioning.TemplateService
 	MuteTimings          *provisioning.MuteTimingService
+	EscalationPolicies   *provisioning.EscalationPolicyService
 	AlertRules           *provisioning.AlertRuleService
-	AlertsRouter         *sender.AlertsRouter
-	EvaluatorFactory     eval.EvaluatorFactory
-	ConditionValidator   *eval.ConditionValidator
-	FeatureManager       featuremgmt.FeatureToggles
-	Historian            Historian
-	Tracer               tracing.Tracer
-	AppUrl               *url.URL
-	UserService          user.Service
+	AlertRuleTemplates   *provisioning.AlertRuleTemplateService
+	RecurringSilences    *provisioning.RecurringSilenceService
+	// NotificationDeliveries is nil if the notification delivery log is disabled.
+	NotificationDeliveries NotificationDeliveryLog
+	StateArchive           AlertingStateArchiver
+	AlertsRouter           *sender.AlertsRouter
+	EvaluatorFactory       eval.EvaluatorFactory
+	ConditionValidator     *eval.ConditionValidator
+	FeatureManager         featuremgmt.FeatureToggles
+	Historian              Historian
+	Tracer                 tracing.Tracer
+	AppUrl                 *url.URL
+	UserService            user.Service
 
 	// Hooks can be used to replace API handlers for specific paths.
 	Hooks *Hooks
@@ -113,7 +119,12 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 				api.RuleStore,
 				ruleAuthzService,
 			),
-			receiverAuthz: accesscontrol.NewReceiverAccess[ReceiverStatus](api.AccessControl, false),
+			recurringSilences: api.RecurringSilences,
+			deliveries:        api.NotificationDeliveries,
+			receiverAuthz:     accesscontrol.NewReceiverAccess[ReceiverStatus](api.AccessControl, false),
+			ruleStore:         api.RuleStore,
+			ruleAuthz:         ruleAuthzService,
+			cfg:               &api.Cfg.UnifiedAlerting,
 		},
 	), m)
 	// Register endpoints for proxying to Prometheus-compatible backends.
@@ -139,6 +150,7 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 			amRefresher:        api.MultiOrgAlertmanager,
 			featureManager:     api.FeatureManager,
 			userService:        api.UserService,
+			acknowledger:       api.StateManager,
 		},
 	), m)
 	api.RegisterTestingApiEndpoints(NewTestingApi(
@@ -149,11 +161,14 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 			authz:           ruleAuthzService,
 			evaluator:       api.EvaluatorFactory,
 			cfg:             &api.Cfg.UnifiedAlerting,
-			backtesting:     backtesting.NewEngine(api.AppUrl, api.EvaluatorFactory, api.Tracer),
+			backtesting:     backtesting.NewEngine(api.AppUrl, api.EvaluatorFactory, api.Tracer, api.FeatureManager),
 			featureManager:  api.FeatureManager,
 			appUrl:          api.AppUrl,
 			tracer:          api.Tracer,
 			folderService:   api.RuleStore,
+			ruleStore:       api.RuleStore,
+			mam:             api.MultiOrgAlertmanager,
+			historian:       api.Historian,
 		}), m)
 	api.RegisterConfigurationApiEndpoints(NewConfiguration(
 		&ConfigSrv{
@@ -162,6 +177,7 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 			log:                  logger,
 			alertmanagerProvider: api.AlertsRouter,
 			featureManager:       api.FeatureManager,
+			stateArchive:         api.StateArchive,
 		},
 	), m)
 
@@ -171,7 +187,9 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 		contactPointService: api.ContactPointService,
 		templates:           api.Templates,
 		muteTimings:         api.MuteTimings,
+		escalationPolicies:  api.EscalationPolicies,
 		alertRules:          api.AlertRules,
+		ruleTemplates:       api.AlertRuleTemplates,
 		// XXX: Used to flag recording rules, remove when FT is removed
 		featureManager: api.FeatureManager,
 	}), m)
@@ -189,6 +207,8 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 			api.DatasourceCache,
 			api.AlertRules,
 			api.FeatureManager,
+			api.AlertingStore,
+			api.MultiOrgAlertmanager,
 		),
 	), m)
 }
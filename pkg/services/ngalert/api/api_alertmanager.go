@@ -22,6 +22,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier/legacy_storage"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 )
 
@@ -35,13 +36,20 @@ type receiversAuthz interface {
 }
 
 type AlertmanagerSrv struct {
-	log            log.Logger
-	ac             accesscontrol.AccessControl
-	mam            *notifier.MultiOrgAlertmanager
-	crypto         notifier.Crypto
-	silenceSvc     SilenceService
+	log        log.Logger
+	ac         accesscontrol.AccessControl
+	mam        *notifier.MultiOrgAlertmanager
+	crypto     notifier.Crypto
+	silenceSvc SilenceService
+	// recurringSilences manages recurring silences. They are a Grafana-only resource, so they are not proxied to other Alertmanagers.
+	recurringSilences RecurringSilenceService
+	// deliveries is nil if the notification delivery log is disabled.
+	deliveries     NotificationDeliveryLog
 	featureManager featuremgmt.FeatureToggles
 	receiverAuthz  receiversAuthz
+	ruleStore      RuleStore
+	ruleAuthz      RuleAccessControlService
+	cfg            *setting.UnifiedAlertingSettings
 }
 
 type UnknownReceiverError struct {
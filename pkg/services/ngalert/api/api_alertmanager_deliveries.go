@@ -0,0 +1,47 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+var errNotificationDeliveryLogDisabled = errors.New("the notification delivery log is not enabled")
+
+// NotificationDeliveryLog is the log of the attempts of the Grafana AM to send notifications.
+type NotificationDeliveryLog interface {
+	Query(ctx context.Context, query models.NotificationDeliveryQuery) ([]models.NotificationDelivery, error)
+}
+
+// RouteGetNotificationDeliveries is the notification delivery log GET endpoint for Grafana AM.
+func (srv AlertmanagerSrv) RouteGetNotificationDeliveries(c *contextmodel.ReqContext) response.Response {
+	if srv.deliveries == nil {
+		return ErrResp(http.StatusNotImplemented, errNotificationDeliveryLogDisabled, "")
+	}
+	query := models.NotificationDeliveryQuery{
+		OrgID:       c.GetOrgID(),
+		RuleUID:     c.Query("ruleUID"),
+		Fingerprint: c.Query("fingerprint"),
+		Receiver:    c.Query("receiver"),
+		Limit:       c.QueryInt("limit"),
+	}
+	if from := c.QueryInt64("from"); from > 0 {
+		query.From = time.Unix(from, 0)
+	}
+	if to := c.QueryInt64("to"); to > 0 {
+		query.To = time.Unix(to, 0)
+	}
+	if !query.From.IsZero() && !query.To.IsZero() && query.From.After(query.To) {
+		return ErrResp(http.StatusBadRequest, errors.New("the start of the time range must not be after its end"), "")
+	}
+	deliveries, err := srv.deliveries.Query(c.Req.Context(), query)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to query notification deliveries", err)
+	}
+	return response.JSON(http.StatusOK, NotificationDeliveriesToGettableNotificationDeliveries(deliveries))
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+type fakeNotificationDeliveryLog struct {
+	queries    []ngmodels.NotificationDeliveryQuery
+	deliveries []ngmodels.NotificationDelivery
+}
+
+func (f *fakeNotificationDeliveryLog) Query(_ context.Context, query ngmodels.NotificationDeliveryQuery) ([]ngmodels.NotificationDelivery, error) {
+	f.queries = append(f.queries, query)
+	return f.deliveries, nil
+}
+
+func TestRouteGetNotificationDeliveries(t *testing.T) {
+	reqCtx := func(query string) *contextmodel.ReqContext {
+		return &contextmodel.ReqContext{
+			Context:      &web.Context{Req: &http.Request{URL: &url.URL{RawQuery: query}}},
+			SignedInUser: &user.SignedInUser{OrgID: 1},
+		}
+	}
+
+	t.Run("should return 501 if the notification delivery log is disabled", func(t *testing.T) {
+		srv := AlertmanagerSrv{log: log.NewNopLogger()}
+
+		resp := srv.RouteGetNotificationDeliveries(reqCtx(""))
+		require.Equal(t, http.StatusNotImplemented, resp.Status())
+	})
+
+	t.Run("should query the deliveries with the filters", func(t *testing.T) {
+		at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		deliveries := &fakeNotificationDeliveryLog{deliveries: []ngmodels.NotificationDelivery{{
+			OrgID:       1,
+			Receiver:    "team-a",
+			Integration: "email",
+			Attempt:     2,
+			Status:      ngmodels.NotificationDeliveryFailed,
+			Error:       "connection refused",
+			Duration:    1500 * time.Millisecond,
+			Time:        at,
+			Alerts:      []ngmodels.NotificationDeliveryAlert{{RuleUID: "rule-1", Fingerprint: "000000000000000a", Status: "active", StartsAt: at}},
+		}}}
+		srv := AlertmanagerSrv{log: log.NewNopLogger(), deliveries: deliveries}
+
+		resp := srv.RouteGetNotificationDeliveries(reqCtx("ruleUID=rule-1&fingerprint=000000000000000a&receiver=team-a&from=1704067200&limit=10"))
+		require.Equal(t, http.StatusOK, resp.Status())
+		require.Equal(t, []ngmodels.NotificationDeliveryQuery{{
+			OrgID:       1,
+			RuleUID:     "rule-1",
+			Fingerprint: "000000000000000a",
+			Receiver:    "team-a",
+			From:        time.Unix(1704067200, 0),
+			Limit:       10,
+		}}, deliveries.queries)
+
+		var result apimodels.GettableNotificationDeliveries
+		require.NoError(t, json.Unmarshal(resp.Body(), &result))
+		require.Len(t, result, 1)
+		require.Equal(t, int64(1500), result[0].DurationMs)
+		require.Equal(t, "failed", result[0].Status)
+		require.Equal(t, "rule-1", result[0].Alerts[0].RuleUID)
+	})
+
+	t.Run("should return 400 if the time range is inverted", func(t *testing.T) {
+		srv := AlertmanagerSrv{log: log.NewNopLogger(), deliveries: &fakeNotificationDeliveryLog{}}
+
+		resp := srv.RouteGetNotificationDeliveries(reqCtx("from=1704067200&to=1704060000"))
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+	})
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// RecurringSilenceService is the service for managing recurring silences of the Grafana AM.
+type RecurringSilenceService interface {
+	GetRecurringSilences(ctx context.Context, user identity.Requester) ([]models.RecurringSilence, map[string]models.Provenance, error)
+	GetRecurringSilence(ctx context.Context, user identity.Requester, uid string) (models.RecurringSilence, models.Provenance, error)
+	CreateRecurringSilence(ctx context.Context, user identity.Requester, silence models.RecurringSilence, provenance models.Provenance) (models.RecurringSilence, error)
+	UpdateRecurringSilence(ctx context.Context, user identity.Requester, silence models.RecurringSilence, provenance models.Provenance) (models.RecurringSilence, error)
+	DeleteRecurringSilence(ctx context.Context, user identity.Requester, uid string, provenance models.Provenance) error
+}
+
+// RouteGetRecurringSilences is the recurring silence list GET endpoint for Grafana AM.
+func (srv AlertmanagerSrv) RouteGetRecurringSilences(c *contextmodel.ReqContext) response.Response {
+	silences, provenances, err := srv.recurringSilences.GetRecurringSilences(c.Req.Context(), c.SignedInUser)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to list recurring silences", err)
+	}
+	return response.JSON(http.StatusOK, RecurringSilencesToGettableRecurringSilences(silences, provenances))
+}
+
+// RouteGetRecurringSilence is the single recurring silence GET endpoint for Grafana AM.
+func (srv AlertmanagerSrv) RouteGetRecurringSilence(c *contextmodel.ReqContext, uid string) response.Response {
+	silence, provenance, err := srv.recurringSilences.GetRecurringSilence(c.Req.Context(), c.SignedInUser, uid)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get recurring silence", err)
+	}
+	return response.JSON(http.StatusOK, RecurringSilenceToGettableRecurringSilence(silence, provenance))
+}
+
+// RouteCreateRecurringSilence is the recurring silence POST endpoint for Grafana AM.
+func (srv AlertmanagerSrv) RouteCreateRecurringSilence(c *contextmodel.ReqContext, body apimodels.PostableRecurringSilence) response.Response {
+	silence := PostableRecurringSilenceToRecurringSilence(body)
+	if silence.CreatedBy == "" {
+		silence.CreatedBy = c.SignedInUser.GetLogin()
+	}
+	created, err := srv.recurringSilences.CreateRecurringSilence(c.Req.Context(), c.SignedInUser, silence, models.ProvenanceNone)
+	if err != nil {
+		return recurringSilenceErrorResponse(err, "failed to create recurring silence")
+	}
+	return response.JSON(http.StatusCreated, RecurringSilenceToGettableRecurringSilence(created, models.ProvenanceNone))
+}
+
+// RouteUpdateRecurringSilence is the recurring silence PUT endpoint for Grafana AM.
+func (srv AlertmanagerSrv) RouteUpdateRecurringSilence(c *contextmodel.ReqContext, body apimodels.PostableRecurringSilence, uid string) response.Response {
+	silence := PostableRecurringSilenceToRecurringSilence(body)
+	silence.UID = uid
+	if silence.CreatedBy == "" {
+		silence.CreatedBy = c.SignedInUser.GetLogin()
+	}
+	updated, err := srv.recurringSilences.UpdateRecurringSilence(c.Req.Context(), c.SignedInUser, silence, models.ProvenanceNone)
+	if err != nil {
+		return recurringSilenceErrorResponse(err, "failed to update recurring silence")
+	}
+	return response.JSON(http.StatusOK, RecurringSilenceToGettableRecurringSilence(updated, models.ProvenanceNone))
+}
+
+// RouteDeleteRecurringSilence is the recurring silence DELETE endpoint for Grafana AM.
+// The silences that were created from the recurring silence are expired.
+func (srv AlertmanagerSrv) RouteDeleteRecurringSilence(c *contextmodel.ReqContext, uid string) response.Response {
+	if err := srv.recurringSilences.DeleteRecurringSilence(c.Req.Context(), c.SignedInUser, uid, models.ProvenanceNone); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to delete recurring silence", err)
+	}
+	return response.JSON(http.StatusNoContent, "")
+}
+
+func recurringSilenceErrorResponse(err error, message string) response.Response {
+	if errors.Is(err, models.ErrRecurringSilenceFailedValidation) {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	if errors.Is(err, store.ErrOptimisticLock) {
+		return ErrResp(http.StatusConflict, err, "")
+	}
+	return response.ErrOrFallback(http.StatusInternalServerError, message, err)
+}
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/log"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+type fakeRecurringSilenceService struct {
+	silences    map[string]ngmodels.RecurringSilence
+	provenances map[string]ngmodels.Provenance
+}
+
+func (f *fakeRecurringSilenceService) GetRecurringSilences(_ context.Context, _ identity.Requester) ([]ngmodels.RecurringSilence, map[string]ngmodels.Provenance, error) {
+	result := make([]ngmodels.RecurringSilence, 0, len(f.silences))
+	for _, s := range f.silences {
+		result = append(result, s)
+	}
+	return result, f.provenances, nil
+}
+
+func (f *fakeRecurringSilenceService) GetRecurringSilence(_ context.Context, _ identity.Requester, uid string) (ngmodels.RecurringSilence, ngmodels.Provenance, error) {
+	s, ok := f.silences[uid]
+	if !ok {
+		return ngmodels.RecurringSilence{}, ngmodels.ProvenanceNone, ngmodels.ErrRecurringSilenceNotFound.Errorf("")
+	}
+	return s, f.provenances[uid], nil
+}
+
+func (f *fakeRecurringSilenceService) CreateRecurringSilence(_ context.Context, _ identity.Requester, silence ngmodels.RecurringSilence, _ ngmodels.Provenance) (ngmodels.RecurringSilence, error) {
+	if err := silence.Validate(); err != nil {
+		return ngmodels.RecurringSilence{}, err
+	}
+	silence.Version = 1
+	f.silences[silence.UID] = silence
+	return silence, nil
+}
+
+func (f *fakeRecurringSilenceService) UpdateRecurringSilence(_ context.Context, _ identity.Requester, silence ngmodels.RecurringSilence, _ ngmodels.Provenance) (ngmodels.RecurringSilence, error) {
+	existing, ok := f.silences[silence.UID]
+	if !ok {
+		return ngmodels.RecurringSilence{}, ngmodels.ErrRecurringSilenceNotFound.Errorf("")
+	}
+	silence.Version = existing.Version + 1
+	f.silences[silence.UID] = silence
+	return silence, nil
+}
+
+func (f *fakeRecurringSilenceService) DeleteRecurringSilence(_ context.Context, _ identity.Requester, uid string, _ ngmodels.Provenance) error {
+	delete(f.silences, uid)
+	return nil
+}
+
+func TestRecurringSilenceRoutes(t *testing.T) {
+	createSrv := func() (AlertmanagerSrv, *fakeRecurringSilenceService) {
+		svc := &fakeRecurringSilenceService{silences: map[string]ngmodels.RecurringSilence{}, provenances: map[string]ngmodels.Provenance{}}
+		return AlertmanagerSrv{log: log.NewNopLogger(), recurringSilences: svc}, svc
+	}
+	reqCtx := func() *contextmodel.ReqContext {
+		return &contextmodel.ReqContext{
+			Context:      &web.Context{Req: &http.Request{}},
+			SignedInUser: &user.SignedInUser{OrgID: 1, Login: "operator"},
+		}
+	}
+	postable := apimodels.PostableRecurringSilence{
+		UID:      "maintenance",
+		Matchers: amv2.Matchers{{Name: util.Pointer("cluster"), Value: util.Pointer("eu-1"), IsEqual: util.Pointer(true), IsRegex: util.Pointer(false)}},
+		Comment:  "Maintenance",
+		Schedule: apimodels.RecurringSilenceSchedule{Cron: "0 2 * * 0", Duration: model.Duration(2 * time.Hour), Timezone: "Europe/Berlin"},
+	}
+
+	t.Run("create should default the author to the user", func(t *testing.T) {
+		srv, svc := createSrv()
+
+		resp := srv.RouteCreateRecurringSilence(reqCtx(), postable)
+		require.Equal(t, http.StatusCreated, resp.Status())
+		var result apimodels.GettableRecurringSilence
+		require.NoError(t, json.Unmarshal(resp.Body(), &result))
+		require.Equal(t, "operator", result.CreatedBy)
+		require.Equal(t, postable.Schedule, result.Schedule)
+		require.Equal(t, 2*time.Hour, svc.silences["maintenance"].Schedule.Duration)
+	})
+
+	t.Run("create should return 400 if the recurring silence is invalid", func(t *testing.T) {
+		srv, _ := createSrv()
+		invalid := postable
+		invalid.Schedule.Duration = 0
+
+		resp := srv.RouteCreateRecurringSilence(reqCtx(), invalid)
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+	})
+
+	t.Run("get should return the provenance", func(t *testing.T) {
+		srv, svc := createSrv()
+		svc.silences["maintenance"] = PostableRecurringSilenceToRecurringSilence(postable)
+		svc.provenances["maintenance"] = ngmodels.ProvenanceFile
+
+		resp := srv.RouteGetRecurringSilence(reqCtx(), "maintenance")
+		require.Equal(t, http.StatusOK, resp.Status())
+		var result apimodels.GettableRecurringSilence
+		require.NoError(t, json.Unmarshal(resp.Body(), &result))
+		require.Equal(t, apimodels.Provenance(ngmodels.ProvenanceFile), result.Provenance)
+
+		resp = srv.RouteGetRecurringSilences(reqCtx())
+		require.Equal(t, http.StatusOK, resp.Status())
+		var list apimodels.GettableRecurringSilences
+		require.NoError(t, json.Unmarshal(resp.Body(), &list))
+		require.Len(t, list, 1)
+		require.Equal(t, apimodels.Provenance(ngmodels.ProvenanceFile), list[0].Provenance)
+	})
+
+	t.Run("get and update should return 404 if the recurring silence does not exist", func(t *testing.T) {
+		srv, _ := createSrv()
+
+		require.Equal(t, http.StatusNotFound, srv.RouteGetRecurringSilence(reqCtx(), "unknown").Status())
+		require.Equal(t, http.StatusNotFound, srv.RouteUpdateRecurringSilence(reqCtx(), postable, "unknown").Status())
+	})
+
+	t.Run("update should use the UID of the path", func(t *testing.T) {
+		srv, svc := createSrv()
+		svc.silences["maintenance"] = PostableRecurringSilenceToRecurringSilence(postable)
+		body := postable
+		body.UID = ""
+		body.Comment = "Changed"
+
+		resp := srv.RouteUpdateRecurringSilence(reqCtx(), body, "maintenance")
+		require.Equal(t, http.StatusOK, resp.Status())
+		require.Equal(t, "Changed", svc.silences["maintenance"].Comment)
+
+		resp = srv.RouteDeleteRecurringSilence(reqCtx(), "maintenance")
+		require.Equal(t, http.StatusNoContent, resp.Status())
+		require.Empty(t, svc.silences)
+	})
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	authz "github.com/grafana/grafana/pkg/services/ngalert/accesscontrol"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// RoutePostTestRouting routes an alert through the notification policy tree of the Grafana Alertmanager without sending
+// any notification. It returns the matched routes with their effective options and the silences that apply to the alert.
+func (srv AlertmanagerSrv) RoutePostTestRouting(c *contextmodel.ReqContext, body apimodels.TestRoutingConfigBodyParams) response.Response {
+	if _, errResp := srv.AlertmanagerFor(c.GetOrgID()); errResp != nil {
+		return errResp
+	}
+
+	now := time.Now()
+	if body.Time != nil {
+		now = *body.Time
+	}
+
+	lbls := make(model.LabelSet, len(body.Labels))
+	if body.RuleUID != "" {
+		ruleLabels, err := srv.getRuleNotificationLabels(c, body.RuleUID)
+		if err != nil {
+			if errors.Is(err, ngmodels.ErrAlertRuleNotFound) {
+				return ErrResp(http.StatusNotFound, err, "")
+			}
+			return response.ErrOrFallback(http.StatusInternalServerError, "failed to get alert rule", err)
+		}
+		for k, v := range ruleLabels {
+			lbls[model.LabelName(k)] = model.LabelValue(v)
+		}
+	}
+	for k, v := range body.Labels {
+		lbls[k] = v
+	}
+	if len(lbls) == 0 {
+		return ErrResp(http.StatusBadRequest, errors.New("labels or rule_uid must be provided"), "")
+	}
+	if err := lbls.Validate(); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "invalid labels")
+	}
+
+	routes, err := srv.mam.TestRouting(c.Req.Context(), c.GetOrgID(), body.Config, lbls, now)
+	if err != nil {
+		if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to test routing", err)
+	}
+
+	silences, err := srv.silenceSvc.ListSilences(c.Req.Context(), c.SignedInUser, nil)
+	if err != nil {
+		// Users who can read notification policies but not silences still get the routes.
+		if !errors.Is(err, authz.ErrAuthorizationBase) {
+			return response.ErrOrFallback(http.StatusInternalServerError, "failed to list silences", err)
+		}
+		srv.log.Debug("User is not authorized to read silences, skipping them in the routing test", "error", err)
+		silences = nil
+	}
+	matching := make([]*ngmodels.Silence, 0)
+	for _, s := range silences {
+		if s.Mutes(lbls, now) {
+			matching = append(matching, s)
+		}
+	}
+
+	return response.JSON(http.StatusOK, apimodels.TestRoutingResult{
+		Labels:   lbls,
+		Time:     now,
+		Routes:   routes,
+		Silences: SilencesToGettableGrafanaSilences(withEmptyMetadata(matching...)),
+		Silenced: len(matching) > 0,
+	})
+}
+
+// getRuleNotificationLabels returns the labels that the alerts of the rule have when they are sent to the Alertmanager,
+// without the labels of the query results.
+func (srv AlertmanagerSrv) getRuleNotificationLabels(c *contextmodel.ReqContext, ruleUID string) (map[string]string, error) {
+	ctx := c.Req.Context()
+	rule, err := srv.ruleStore.GetAlertRuleByUID(ctx, &ngmodels.GetAlertRuleByUIDQuery{
+		UID:   ruleUID,
+		OrgID: c.GetOrgID(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := srv.ruleAuthz.AuthorizeAccessInFolder(ctx, c.SignedInUser, rule); err != nil {
+		return nil, err
+	}
+	folder, err := srv.ruleStore.GetNamespaceByUID(ctx, rule.NamespaceUID, c.GetOrgID(), c.SignedInUser)
+	if err != nil {
+		return nil, err
+	}
+
+	includeFolder := !srv.cfg.ReservedLabels.IsReservedLabelDisabled(ngmodels.FolderTitleLabel)
+	lbls := make(map[string]string, len(rule.Labels))
+	for k, v := range rule.Labels {
+		lbls[k] = v
+	}
+	for k, v := range state.GetRuleExtraLabels(srv.log, rule, folder.Fullpath, includeFolder) {
+		lbls[k] = v
+	}
+	return lbls, nil
+}
@@ -10,17 +10,23 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/prometheus/alertmanager/config"
 	"github.com/prometheus/alertmanager/pkg/labels"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/accesscontrol"
 
+	alertingModels "github.com/grafana/alerting/models"
+
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/infra/log"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
@@ -554,6 +560,154 @@ func TestRoutePostTestTemplates(t *testing.T) {
 	})
 }
 
+func TestRoutePostTestRouting(t *testing.T) {
+	sut := createSut(t)
+	ruleStore := sut.ruleStore.(*ngfakes.RuleStore)
+
+	routes := func(t *testing.T, resp response.Response) apimodels.TestRoutingResult {
+		t.Helper()
+		require.Equal(t, http.StatusOK, resp.Status(), string(resp.Body()))
+		var result apimodels.TestRoutingResult
+		require.NoError(t, json.Unmarshal(resp.Body(), &result))
+		return result
+	}
+	proposed := func(t *testing.T, raw string) *apimodels.PostableUserConfig {
+		t.Helper()
+		cfg := apimodels.PostableUserConfig{}
+		require.NoError(t, json.Unmarshal([]byte(raw), &cfg))
+		return &cfg
+	}
+
+	t.Run("assert 404 when no alertmanager found", func(t *testing.T) {
+		resp := sut.RoutePostTestRouting(createRequestCtxInOrg(10), apimodels.TestRoutingConfigBodyParams{})
+		require.Equal(t, http.StatusNotFound, resp.Status())
+	})
+
+	t.Run("assert 400 without labels", func(t *testing.T) {
+		resp := sut.RoutePostTestRouting(createRequestCtxInOrg(1), apimodels.TestRoutingConfigBodyParams{})
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+	})
+
+	t.Run("routes with the current configuration", func(t *testing.T) {
+		result := routes(t, sut.RoutePostTestRouting(createRequestCtxInOrg(1), apimodels.TestRoutingConfigBodyParams{
+			Labels: model.LabelSet{"a": "b"},
+		}))
+		require.Equal(t, model.LabelSet{"a": "b"}, result.Labels)
+		require.Len(t, result.Routes, 1)
+		route := result.Routes[0]
+		require.Equal(t, "grafana-default-email", route.Receiver)
+		require.Equal(t, []apimodels.TestRoutingRouteStep{{Index: -1, Receiver: "grafana-default-email"}}, route.Path)
+		require.False(t, route.Muted)
+		require.Empty(t, result.Silences)
+	})
+
+	t.Run("routes with a proposed configuration", func(t *testing.T) {
+		result := routes(t, sut.RoutePostTestRouting(createRequestCtxInOrg(1), apimodels.TestRoutingConfigBodyParams{
+			Labels: model.LabelSet{"a": "b"},
+			Config: proposed(t, validConfigWithoutAutogen),
+		}))
+		require.Len(t, result.Routes, 1)
+		route := result.Routes[0]
+		require.Equal(t, "other email", route.Receiver)
+		require.Len(t, route.Path, 2)
+		// the first route of the root is the autogenerated route
+		require.Equal(t, apimodels.TestRoutingRouteStep{Index: 1, Receiver: "other email", Matchers: []string{`a="b"`}}, route.Path[1])
+	})
+
+	t.Run("reports the time intervals that mute the routes", func(t *testing.T) {
+		cfg := proposed(t, configWithTimeIntervals)
+		body := apimodels.TestRoutingConfigBodyParams{
+			Labels: model.LabelSet{"team": "infra", "severity": "critical"},
+			Config: cfg,
+		}
+
+		saturday := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+		body.Time = &saturday
+		result := routes(t, sut.RoutePostTestRouting(createRequestCtxInOrg(1), body))
+		require.Len(t, result.Routes, 2)
+		require.Equal(t, "other email", result.Routes[0].Receiver)
+		require.Equal(t, model.Duration(time.Minute), result.Routes[0].GroupWait)
+		require.Equal(t, []string{"alertname"}, result.Routes[0].GroupBy)
+		require.True(t, result.Routes[0].Muted)
+		require.Equal(t, []string{"weekends"}, result.Routes[0].MutedBy)
+		require.Equal(t, "some email", result.Routes[1].Receiver)
+		require.False(t, result.Routes[1].Muted)
+
+		monday := time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC)
+		body.Time = &monday
+		body.Config = proposed(t, configWithTimeIntervals)
+		result = routes(t, sut.RoutePostTestRouting(createRequestCtxInOrg(1), body))
+		require.Len(t, result.Routes, 2)
+		require.False(t, result.Routes[0].Muted)
+		require.True(t, result.Routes[1].Muted)
+		require.Equal(t, []string{"weekends"}, result.Routes[1].MutedBy)
+	})
+
+	t.Run("assert 400 when the proposed configuration has inhibition rules", func(t *testing.T) {
+		cfg := proposed(t, validConfigWithoutAutogen)
+		cfg.AlertmanagerConfig.InhibitRules = []config.InhibitRule{{}}
+		resp := sut.RoutePostTestRouting(createRequestCtxInOrg(1), apimodels.TestRoutingConfigBodyParams{
+			Labels: model.LabelSet{"a": "b"},
+			Config: cfg,
+		})
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+	})
+
+	t.Run("routes with the labels of a rule", func(t *testing.T) {
+		folder := randFolder()
+		ruleStore.Folders[1] = append(ruleStore.Folders[1], folder)
+		rule := ngmodels.RuleGen.With(
+			ngmodels.RuleGen.WithOrgID(1),
+			ngmodels.RuleGen.WithNamespaceUID(folder.UID),
+			ngmodels.RuleGen.WithLabels(map[string]string{"a": "b", "team": "infra"}),
+			ngmodels.RuleGen.WithNoNotificationSettings(),
+		).GenerateRef()
+		ruleStore.PutRule(context.Background(), rule)
+
+		t.Run("assert 403 without access to the rule", func(t *testing.T) {
+			resp := sut.RoutePostTestRouting(createRequestCtxInOrg(1), apimodels.TestRoutingConfigBodyParams{
+				RuleUID: rule.UID,
+			})
+			require.Equal(t, http.StatusForbidden, resp.Status())
+		})
+
+		rc := createRequestContextWithPerms(1, map[int64]map[string][]string{
+			1: {
+				dashboards.ActionFoldersRead:  {dashboards.ScopeFoldersAll},
+				ac.ActionAlertingRuleRead:     {dashboards.ScopeFoldersAll},
+				ac.ActionAlertingInstanceRead: nil,
+			},
+		}, nil)
+
+		t.Run("assert 404 for an unknown rule", func(t *testing.T) {
+			resp := sut.RoutePostTestRouting(rc, apimodels.TestRoutingConfigBodyParams{
+				RuleUID: "unknown",
+			})
+			require.Equal(t, http.StatusNotFound, resp.Status())
+		})
+
+		silence := ngmodels.SilenceGen(func(s *ngmodels.Silence) {
+			s.Matchers = nil
+		}, ngmodels.SilenceMuts.WithRuleUID(rule.UID), ngmodels.SilenceMuts.WithEmptyId())()
+		_, err := sut.mam.CreateSilence(context.Background(), 1, silence)
+		require.NoError(t, err)
+
+		result := routes(t, sut.RoutePostTestRouting(rc, apimodels.TestRoutingConfigBodyParams{
+			RuleUID: rule.UID,
+			Labels:  model.LabelSet{"team": "db"},
+			Config:  proposed(t, validConfigWithoutAutogen),
+		}))
+		require.Equal(t, model.LabelValue("b"), result.Labels["a"])
+		require.Equal(t, model.LabelValue("db"), result.Labels["team"])
+		require.Equal(t, model.LabelValue(rule.Title), result.Labels[model.AlertNameLabel])
+		require.Equal(t, model.LabelValue(rule.UID), result.Labels[alertingModels.RuleUIDLabel])
+		require.Len(t, result.Routes, 1)
+		require.Equal(t, "other email", result.Routes[0].Receiver)
+		require.True(t, result.Silenced)
+		require.Len(t, result.Silences, 1)
+	})
+}
+
 func createSut(t *testing.T) AlertmanagerSrv {
 	t.Helper()
 
@@ -574,6 +728,9 @@ func createSut(t *testing.T) AlertmanagerSrv {
 		log:            log,
 		featureManager: featuremgmt.WithFeatures(),
 		silenceSvc:     notifier.NewSilenceService(accesscontrol.NewSilenceService(ac, ruleStore), ruleStore, log, mam, ruleStore, ruleAuthzService),
+		ruleStore:      ruleStore,
+		ruleAuthz:      ruleAuthzService,
+		cfg:            &setting.UnifiedAlertingSettings{},
 	}
 }
 
@@ -676,6 +833,50 @@ var validConfigWithoutAutogen = `{
 }
 `
 
+var configWithTimeIntervals = `{
+	"alertmanager_config": {
+		"route": {
+			"receiver": "some email",
+			"group_by": ["alertname"],
+			"routes": [{
+				"receiver": "other email",
+				"object_matchers": [["team", "=", "infra"]],
+				"group_wait": "1m",
+				"mute_time_intervals": ["weekends"],
+				"continue": true
+			},{
+				"receiver": "some email",
+				"object_matchers": [["severity", "=", "critical"]],
+				"active_time_intervals": ["weekends"]
+			}]
+		},
+		"time_intervals": [{
+			"name": "weekends",
+			"time_intervals": [{"weekdays": ["saturday", "sunday"]}]
+		}],
+		"receivers": [{
+			"name": "some email",
+			"grafana_managed_receiver_configs": [{
+				"name": "some email",
+				"type": "email",
+				"settings": {
+					"addresses": "<some@email.com>"
+				}
+			}]
+		},{
+			"name": "other email",
+			"grafana_managed_receiver_configs": [{
+				"name": "other email",
+				"type": "email",
+				"settings": {
+					"addresses": "<other@email.com>"
+				}
+			}]
+		}]
+	}
+}
+`
+
 var validConfigWithAutogen = `{
 	"template_files": {
 		"a": "template"
@@ -26,6 +26,7 @@ type ConfigSrv struct {
 	store                store.AdminConfigurationStore
 	log                  log.Logger
 	featureManager       featuremgmt.FeatureToggles
+	stateArchive         AlertingStateArchiver
 }
 
 func (srv ConfigSrv) RouteGetAlertmanagers(c *contextmodel.ReqContext) response.Response {
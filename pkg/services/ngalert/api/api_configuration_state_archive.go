@@ -0,0 +1,49 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/statearchive"
+)
+
+// AlertingStateArchiver exports and imports the state of alerting to move it to another Grafana instance.
+type AlertingStateArchiver interface {
+	Export(ctx context.Context, orgIDs []int64) (*apimodels.AlertStateArchive, error)
+	Import(ctx context.Context, archive apimodels.AlertStateArchive, opts statearchive.ImportOptions) (apimodels.AlertStateImportResult, error)
+}
+
+// RouteExportAlertingState exports the state of alerting of the organizations in the orgId query parameter, or of all organizations.
+func (srv ConfigSrv) RouteExportAlertingState(c *contextmodel.ReqContext) response.Response {
+	var orgIDs []int64
+	for _, s := range c.QueryStrings("orgId") {
+		id, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return ErrResp(http.StatusBadRequest, fmt.Errorf("invalid organization ID %q", s), "")
+		}
+		orgIDs = append(orgIDs, id)
+	}
+
+	archive, err := srv.stateArchive.Export(c.Req.Context(), orgIDs)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to export alerting state", err)
+	}
+	return response.JSON(http.StatusOK, archive)
+}
+
+// RouteImportAlertingState imports the state of alerting exported by another Grafana instance.
+func (srv ConfigSrv) RouteImportAlertingState(c *contextmodel.ReqContext, body apimodels.PostableAlertStateImport) response.Response {
+	result, err := srv.stateArchive.Import(c.Req.Context(), body.Archive, statearchive.ImportOptions{
+		OrgIDs:   body.OrgIDs,
+		RuleUIDs: body.RuleUIDs,
+	})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to import alerting state", err)
+	}
+	return response.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/statearchive"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+type fakeAlertingStateArchiver struct {
+	exported [][]int64
+	imported []statearchive.ImportOptions
+	err      error
+}
+
+func (f *fakeAlertingStateArchiver) Export(_ context.Context, orgIDs []int64) (*apimodels.AlertStateArchive, error) {
+	f.exported = append(f.exported, orgIDs)
+	return &apimodels.AlertStateArchive{Version: apimodels.AlertStateArchiveVersion}, f.err
+}
+
+func (f *fakeAlertingStateArchiver) Import(_ context.Context, _ apimodels.AlertStateArchive, opts statearchive.ImportOptions) (apimodels.AlertStateImportResult, error) {
+	f.imported = append(f.imported, opts)
+	return apimodels.AlertStateImportResult{Orgs: 1}, f.err
+}
+
+func TestRouteExportAlertingState(t *testing.T) {
+	reqCtx := func(query string) *contextmodel.ReqContext {
+		return &contextmodel.ReqContext{
+			Context:      &web.Context{Req: &http.Request{URL: &url.URL{RawQuery: query}}},
+			SignedInUser: &user.SignedInUser{OrgID: 1, IsGrafanaAdmin: true},
+		}
+	}
+
+	t.Run("should export the organizations in the query", func(t *testing.T) {
+		archiver := &fakeAlertingStateArchiver{}
+		srv := ConfigSrv{log: log.NewNopLogger(), stateArchive: archiver}
+
+		resp := srv.RouteExportAlertingState(reqCtx("orgId=1&orgId=3"))
+		require.Equal(t, http.StatusOK, resp.Status())
+		require.Equal(t, [][]int64{{1, 3}}, archiver.exported)
+	})
+
+	t.Run("should return 400 if an organization ID is invalid", func(t *testing.T) {
+		archiver := &fakeAlertingStateArchiver{}
+		srv := ConfigSrv{log: log.NewNopLogger(), stateArchive: archiver}
+
+		resp := srv.RouteExportAlertingState(reqCtx("orgId=main"))
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+		require.Empty(t, archiver.exported)
+	})
+}
+
+func TestRouteImportAlertingState(t *testing.T) {
+	reqCtx := &contextmodel.ReqContext{
+		Context:      &web.Context{Req: &http.Request{URL: &url.URL{}}},
+		SignedInUser: &user.SignedInUser{OrgID: 1, IsGrafanaAdmin: true},
+	}
+
+	t.Run("should import the archive with the mappings", func(t *testing.T) {
+		archiver := &fakeAlertingStateArchiver{}
+		srv := ConfigSrv{log: log.NewNopLogger(), stateArchive: archiver}
+
+		resp := srv.RouteImportAlertingState(reqCtx, apimodels.PostableAlertStateImport{
+			OrgIDs:   map[int64]int64{1: 2},
+			RuleUIDs: map[string]string{"a": "b"},
+		})
+		require.Equal(t, http.StatusOK, resp.Status())
+		require.Equal(t, []statearchive.ImportOptions{{OrgIDs: map[int64]int64{1: 2}, RuleUIDs: map[string]string{"a": "b"}}}, archiver.imported)
+	})
+
+	t.Run("should return 400 if the archive is invalid", func(t *testing.T) {
+		archiver := &fakeAlertingStateArchiver{err: statearchive.ErrInvalidArchive.Errorf("unsupported archive version")}
+		srv := ConfigSrv{log: log.NewNopLogger(), stateArchive: archiver}
+
+		resp := srv.RouteImportAlertingState(reqCtx, apimodels.PostableAlertStateImport{})
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+	})
+}
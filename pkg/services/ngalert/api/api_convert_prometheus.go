@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -10,6 +11,8 @@ import (
 	"strings"
 	"time"
 
+	amConfig "github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/pkg/labels"
 	prommodel "github.com/prometheus/common/model"
 	"gopkg.in/yaml.v3"
 
@@ -24,6 +27,7 @@ import (
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/validation"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
 	"github.com/grafana/grafana/pkg/services/ngalert/prom"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
 	"github.com/grafana/grafana/pkg/setting"
@@ -47,6 +51,14 @@ const (
 	// notificationSettingsHeader is the header that specifies the notification settings to be used for the rules.
 	// The value should be a JSON-encoded AlertRuleNotificationSettings object.
 	notificationSettingsHeader = "X-Grafana-Alerting-Notification-Settings"
+
+	// configIdentifierHeader is the header that specifies the identifier of an imported Alertmanager configuration.
+	configIdentifierHeader = "X-Grafana-Alerting-Config-Identifier"
+	// mergeMatchersHeader is the header that specifies the matchers of the route under which
+	// the routing tree of an imported Alertmanager configuration is merged.
+	mergeMatchersHeader = "X-Grafana-Alerting-Merge-Matchers"
+	// dryRunHeader is the header that makes the import of an Alertmanager configuration report the changes without saving them.
+	dryRunHeader = "X-Grafana-Alerting-Dry-Run"
 )
 
 var (
@@ -58,6 +70,9 @@ var (
 	errInvalidHeaderValueMsg  = "Invalid value for header {{.Public.Header}}: {{.Public.Error}}"
 	errInvalidHeaderValueBase = errutil.ValidationFailed("alerting.invalidHeaderValue").MustTemplate(errInvalidHeaderValueMsg, errutil.WithPublic(errInvalidHeaderValueMsg))
 
+	errInvalidAlertmanagerConfigMsg  = "Invalid Alertmanager configuration: {{.Public.Error}}"
+	errInvalidAlertmanagerConfigBase = errutil.ValidationFailed("alerting.invalidAlertmanagerConfig").MustTemplate(errInvalidAlertmanagerConfigMsg, errutil.WithPublic(errInvalidAlertmanagerConfigMsg))
+
 	errRecordingRulesNotEnabled = errutil.ValidationFailed(
 		"alerting.recordingRulesNotEnabled",
 		errutil.WithPublicMessage("Cannot import recording rules: Feature not enabled."),
@@ -73,6 +88,10 @@ func errInvalidHeaderValue(header string, err error) error {
 	return errInvalidHeaderValueBase.Build(errutil.TemplateData{Public: map[string]any{"Header": header, "Error": err}})
 }
 
+func errInvalidAlertmanagerConfig(err error) error {
+	return errInvalidAlertmanagerConfigBase.Build(errutil.TemplateData{Public: map[string]any{"Error": err}, Error: err})
+}
+
 // ConvertPrometheusSrv converts Prometheus rules to Grafana rules
 // and retrieves them in a Prometheus-compatible format.
 //
@@ -81,6 +100,9 @@ func errInvalidHeaderValue(header string, err error) error {
 // where once a rule group is created, it is marked as "provisioned" (via provenance mechanism)
 // and is not editable in the UI.
 //
+// It also merges Prometheus Alertmanager configurations into the Grafana Alertmanager configuration,
+// so that the notification routing of the imported rules does not have to be migrated by hand.
+//
 // This service returns only rule groups that were initially imported from Prometheus-compatible sources.
 // Rule groups not imported from Prometheus are excluded because their original rule definitions are unavailable.
 // When a rule group is converted from Prometheus to Grafana, the original definition is preserved alongside
@@ -118,6 +140,14 @@ type ConvertPrometheusSrv struct {
 	datasourceCache  datasources.CacheService
 	alertRuleService *provisioning.AlertRuleService
 	featureToggles   featuremgmt.FeatureToggles
+	amConfigStore    AMConfigStore
+	amConfigApplier  AMConfigApplier
+}
+
+// AMConfigApplier validates the Alertmanager configuration of an organization, saves it and applies it.
+type AMConfigApplier interface {
+	ValidateAlertmanagerConfiguration(ctx context.Context, org int64, config *apimodels.PostableUserConfig) error
+	UpdateAndApplyAlertmanagerConfiguration(ctx context.Context, org int64, config apimodels.PostableUserConfig, fetchedHash string) error
 }
 
 func NewConvertPrometheusSrv(
@@ -127,6 +157,8 @@ func NewConvertPrometheusSrv(
 	datasourceCache datasources.CacheService,
 	alertRuleService *provisioning.AlertRuleService,
 	featureToggles featuremgmt.FeatureToggles,
+	amConfigStore AMConfigStore,
+	amConfigApplier AMConfigApplier,
 ) *ConvertPrometheusSrv {
 	return &ConvertPrometheusSrv{
 		cfg:              cfg,
@@ -135,6 +167,8 @@ func NewConvertPrometheusSrv(
 		datasourceCache:  datasourceCache,
 		alertRuleService: alertRuleService,
 		featureToggles:   featureToggles,
+		amConfigStore:    amConfigStore,
+		amConfigApplier:  amConfigApplier,
 	}
 }
 
@@ -436,6 +470,87 @@ func (srv *ConvertPrometheusSrv) RouteConvertPrometheusPostRuleGroups(c *context
 	return successfulResponse()
 }
 
+// RouteConvertPrometheusPostAlertmanagerConfig converts a Prometheus Alertmanager configuration
+// and merges it into the Grafana Alertmanager configuration of the organization.
+// If the dry run header is set, the changes are reported but not saved.
+func (srv *ConvertPrometheusSrv) RouteConvertPrometheusPostAlertmanagerConfig(c *contextmodel.ReqContext, promConfig apimodels.PrometheusAlertmanagerConfig) response.Response {
+	logger := srv.logger.FromContext(c.Req.Context())
+
+	identifier := strings.TrimSpace(c.Req.Header.Get(configIdentifierHeader))
+	logger = logger.New("config_identifier", identifier)
+
+	mergeMatchers, err := parseMergeMatchersHeader(c)
+	if err != nil {
+		return errorToResponse(err)
+	}
+	dryRun, err := parseBooleanHeader(c.Req.Header.Get(dryRunHeader), dryRunHeader)
+	if err != nil {
+		return errorToResponse(err)
+	}
+
+	src, err := amConfig.Load(promConfig.AlertmanagerConfig)
+	if err != nil {
+		return errorToResponse(errInvalidAlertmanagerConfig(err))
+	}
+
+	current, err := srv.amConfigStore.GetLatestAlertmanagerConfiguration(c.Req.Context(), c.GetOrgID())
+	if err != nil {
+		logger.Error("Failed to get the Alertmanager configuration", "error", err)
+		return errorToResponse(err)
+	}
+	cfg, err := notifier.Load([]byte(current.AlertmanagerConfiguration))
+	if err != nil {
+		logger.Error("Failed to load the Alertmanager configuration", "error", err)
+		return errorToResponse(err)
+	}
+	stored := make(map[*apimodels.PostableGrafanaReceiver]struct{})
+	for _, r := range cfg.AlertmanagerConfig.Receivers {
+		for _, integration := range r.GrafanaManagedReceivers {
+			stored[integration] = struct{}{}
+		}
+	}
+
+	result, err := prom.MergeAlertmanagerConfig(cfg, src, promConfig.TemplateFiles, prom.AlertmanagerImportConfig{
+		Identifier:    identifier,
+		MergeMatchers: mergeMatchers,
+	})
+	if err != nil {
+		logger.Error("Failed to merge the Alertmanager configuration", "error", err)
+		return errorToResponse(err)
+	}
+	// The secure settings of the stored configuration are encrypted. They are removed from the integrations that were
+	// not imported after the merge, so that the secure settings of the previously imported receivers are compared,
+	// and copied back from the stored configuration when the configuration is saved.
+	for _, r := range cfg.AlertmanagerConfig.Receivers {
+		for _, integration := range r.GrafanaManagedReceivers {
+			if _, ok := stored[integration]; ok {
+				integration.SecureSettings = nil
+			}
+		}
+	}
+
+	if dryRun {
+		err = srv.amConfigApplier.ValidateAlertmanagerConfiguration(c.Req.Context(), c.GetOrgID(), cfg)
+	} else {
+		// The configuration is saved only if it was not changed since it was fetched, so that concurrent imports
+		// do not overwrite each other.
+		err = srv.amConfigApplier.UpdateAndApplyAlertmanagerConfiguration(c.Req.Context(), c.GetOrgID(), *cfg, current.ConfigurationHash)
+	}
+	if err != nil {
+		logger.Error("Failed to save the Alertmanager configuration", "error", err, "dry_run", dryRun)
+		var configRejectedError notifier.AlertmanagerConfigRejectedError
+		if errors.As(err, &configRejectedError) {
+			return ErrResp(http.StatusBadRequest, configRejectedError, "")
+		}
+		return errorToResponse(err)
+	}
+	if !dryRun {
+		logger.Info("Imported Alertmanager configuration", "unsupported", len(result.Unsupported))
+	}
+
+	return response.JSON(http.StatusAccepted, alertmanagerImportResponse(result, dryRun))
+}
+
 func (srv *ConvertPrometheusSrv) getOrCreateNamespace(c *contextmodel.ReqContext, title string, logger log.Logger, workingFolderUID string) (*folder.FolderReference, response.Response) {
 	logger.Debug("Getting or creating a new folder")
 
@@ -642,3 +757,38 @@ func parseNotificationSettingsHeader(ctx *contextmodel.ReqContext) ([]models.Not
 
 	return notificationSettings, nil
 }
+
+// parseMergeMatchersHeader parses the matchers of the mergeMatchersHeader, in the same format as the matchers of silences,
+// for example `source="prometheus",cluster=~"eu-.*"`.
+func parseMergeMatchersHeader(c *contextmodel.ReqContext) (apimodels.ObjectMatchers, error) {
+	value := strings.TrimSpace(c.Req.Header.Get(mergeMatchersHeader))
+	if value == "" {
+		return nil, prom.ErrMissingMergeMatchers.Errorf("")
+	}
+	matchers, err := labels.ParseMatchers(value)
+	if err != nil {
+		return nil, errInvalidHeaderValue(mergeMatchersHeader, err)
+	}
+	return apimodels.ObjectMatchers(matchers), nil
+}
+
+func alertmanagerImportResponse(result prom.AlertmanagerImportResult, dryRun bool) apimodels.ConvertPrometheusAlertmanagerConfigResponse {
+	resourceDiff := func(d prom.ResourceDiff) apimodels.ConvertPrometheusResourceDiff {
+		return apimodels.ConvertPrometheusResourceDiff{Added: d.Added, Updated: d.Updated, Removed: d.Removed}
+	}
+	unsupported := make([]apimodels.UnsupportedAlertmanagerFeature, 0, len(result.Unsupported))
+	for _, u := range result.Unsupported {
+		unsupported = append(unsupported, apimodels.UnsupportedAlertmanagerFeature{Kind: u.Kind, Name: u.Name, Reason: u.Reason})
+	}
+	return apimodels.ConvertPrometheusAlertmanagerConfigResponse{
+		Status: "success",
+		DryRun: dryRun,
+		Diff: apimodels.ConvertPrometheusAlertmanagerConfigDiff{
+			Routes:        resourceDiff(result.Diff.Routes),
+			Receivers:     resourceDiff(result.Diff.Receivers),
+			TimeIntervals: resourceDiff(result.Diff.TimeIntervals),
+			Templates:     resourceDiff(result.Diff.Templates),
+		},
+		Unsupported: unsupported,
+	}
+}
@@ -2,12 +2,16 @@ package api
 
 import (
 	"context"
+	"crypto/md5"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/prometheus/alertmanager/pkg/labels"
 	prommodel "github.com/prometheus/common/model"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
@@ -23,6 +27,8 @@ import (
 	acfakes "github.com/grafana/grafana/pkg/services/ngalert/accesscontrol/fakes"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	"github.com/grafana/grafana/pkg/services/ngalert/prom"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
@@ -1273,11 +1279,224 @@ func TestRouteConvertPrometheusPostRuleGroups(t *testing.T) {
 	})
 }
 
+func TestRouteConvertPrometheusPostAlertmanagerConfig(t *testing.T) {
+	promConfig := apimodels.PrometheusAlertmanagerConfig{
+		AlertmanagerConfig: `
+route:
+  receiver: team-a
+  group_by: [alertname]
+  routes:
+    - receiver: team-b
+      matchers: [team="b"]
+      mute_time_intervals: [weekends]
+receivers:
+  - name: team-a
+    slack_configs:
+      - api_url: https://hooks.slack.com/services/a
+        channel: '#alerts'
+  - name: team-b
+    webhook_configs:
+      - url: https://example.com/hook
+    wechat_configs:
+      - corp_id: corp
+        api_secret: secret
+        to_user: user
+inhibit_rules:
+  - source_matchers: [severity="critical"]
+    target_matchers: [severity="warning"]
+time_intervals:
+  - name: weekends
+    time_intervals:
+      - weekdays: [saturday, sunday]
+`,
+		TemplateFiles: map[string]string{"slack.tmpl": `{{ define "slack.custom" }}custom{{ end }}`},
+	}
+
+	newRequest := func(headers map[string]string) *contextmodel.ReqContext {
+		rc := createRequestCtx()
+		for k, v := range headers {
+			rc.Req.Header.Set(k, v)
+		}
+		return rc
+	}
+
+	t.Run("without identifier should return 400", func(t *testing.T) {
+		srv, _, _, _ := createConvertPrometheusSrv(t)
+		rc := newRequest(map[string]string{mergeMatchersHeader: `source="prometheus"`})
+
+		response := srv.RouteConvertPrometheusPostAlertmanagerConfig(rc, promConfig)
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("without merge matchers should return 400", func(t *testing.T) {
+		srv, _, _, _ := createConvertPrometheusSrv(t)
+		rc := newRequest(map[string]string{configIdentifierHeader: "prometheus"})
+
+		response := srv.RouteConvertPrometheusPostAlertmanagerConfig(rc, promConfig)
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("with invalid configuration should return 400", func(t *testing.T) {
+		srv, _, _, _ := createConvertPrometheusSrv(t)
+		rc := newRequest(map[string]string{configIdentifierHeader: "prometheus", mergeMatchersHeader: `source="prometheus"`})
+
+		response := srv.RouteConvertPrometheusPostAlertmanagerConfig(rc, apimodels.PrometheusAlertmanagerConfig{AlertmanagerConfig: "route: {receiver: missing}"})
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("with dry run should report the changes without saving them", func(t *testing.T) {
+		applier := &fakeAMConfigApplier{}
+		srv, _, _, _ := createConvertPrometheusSrv(t, withAlertmanagerConfig(fakes.NewFakeAlertmanagerConfigStore(validConfig), applier))
+		rc := newRequest(map[string]string{configIdentifierHeader: "prometheus", mergeMatchersHeader: `source="prometheus"`, dryRunHeader: "true"})
+
+		response := srv.RouteConvertPrometheusPostAlertmanagerConfig(rc, promConfig)
+		require.Equal(t, http.StatusAccepted, response.Status())
+		require.Empty(t, applier.saved)
+		require.Len(t, applier.validated, 1)
+
+		var result apimodels.ConvertPrometheusAlertmanagerConfigResponse
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.True(t, result.DryRun)
+		require.Equal(t, []string{"prometheus_team-a", "prometheus_team-b"}, result.Diff.Receivers.Added)
+		require.Equal(t, []string{"prometheus_weekends"}, result.Diff.TimeIntervals.Added)
+		require.Equal(t, []string{"prometheus_slack.tmpl"}, result.Diff.Templates.Added)
+		require.Equal(t, []string{`{source="prometheus"}`}, result.Diff.Routes.Added)
+
+		kinds := map[string]int{}
+		for _, u := range result.Unsupported {
+			kinds[u.Kind]++
+		}
+		require.Equal(t, 1, kinds[prom.UnsupportedKindInhibitRule])
+		require.Equal(t, 1, kinds[prom.UnsupportedKindIntegration])
+	})
+
+	t.Run("with dry run should return 400 when the merged configuration is rejected", func(t *testing.T) {
+		applier := &fakeAMConfigApplier{err: notifier.AlertmanagerConfigRejectedError{Inner: errors.New("invalid integration")}}
+		srv, _, _, _ := createConvertPrometheusSrv(t, withAlertmanagerConfig(fakes.NewFakeAlertmanagerConfigStore(validConfig), applier))
+		rc := newRequest(map[string]string{configIdentifierHeader: "prometheus", mergeMatchersHeader: `source="prometheus"`, dryRunHeader: "true"})
+
+		response := srv.RouteConvertPrometheusPostAlertmanagerConfig(rc, promConfig)
+		require.Equal(t, http.StatusBadRequest, response.Status())
+		require.Empty(t, applier.saved)
+	})
+
+	t.Run("should save the configuration with the hash of the fetched configuration", func(t *testing.T) {
+		applier := &fakeAMConfigApplier{}
+		srv, _, _, _ := createConvertPrometheusSrv(t, withAlertmanagerConfig(fakes.NewFakeAlertmanagerConfigStore(validConfig), applier))
+		rc := newRequest(map[string]string{configIdentifierHeader: "prometheus", mergeMatchersHeader: `source="prometheus"`})
+
+		response := srv.RouteConvertPrometheusPostAlertmanagerConfig(rc, promConfig)
+		require.Equal(t, http.StatusAccepted, response.Status())
+		require.Empty(t, applier.validated)
+		require.Equal(t, []string{fmt.Sprintf("%x", md5.Sum([]byte(validConfig)))}, applier.hashes)
+	})
+
+	t.Run("should return 409 when the configuration was changed after it was fetched", func(t *testing.T) {
+		applier := &fakeAMConfigApplier{err: notifier.ErrAlertmanagerConfigConflict.Errorf("")}
+		srv, _, _, _ := createConvertPrometheusSrv(t, withAlertmanagerConfig(fakes.NewFakeAlertmanagerConfigStore(validConfig), applier))
+		rc := newRequest(map[string]string{configIdentifierHeader: "prometheus", mergeMatchersHeader: `source="prometheus"`})
+
+		response := srv.RouteConvertPrometheusPostAlertmanagerConfig(rc, promConfig)
+		require.Equal(t, http.StatusConflict, response.Status())
+	})
+
+	t.Run("should merge the configuration and save it", func(t *testing.T) {
+		applier := &fakeAMConfigApplier{}
+		srv, _, _, _ := createConvertPrometheusSrv(t, withAlertmanagerConfig(fakes.NewFakeAlertmanagerConfigStore(validConfig), applier))
+		rc := newRequest(map[string]string{configIdentifierHeader: "prometheus", mergeMatchersHeader: `source="prometheus"`})
+
+		response := srv.RouteConvertPrometheusPostAlertmanagerConfig(rc, promConfig)
+		require.Equal(t, http.StatusAccepted, response.Status())
+		require.Len(t, applier.saved, 1)
+
+		saved := applier.saved[0]
+		require.Len(t, saved.AlertmanagerConfig.Receivers, 3)
+		require.Equal(t, "grafana-default-email", saved.AlertmanagerConfig.Route.Receiver)
+		require.Len(t, saved.AlertmanagerConfig.Route.Routes, 1)
+		subtree := saved.AlertmanagerConfig.Route.Routes[0]
+		require.Equal(t, "prometheus_team-a", subtree.Receiver)
+		require.Equal(t, `{source="prometheus"}`, labels.Matchers(subtree.ObjectMatchers).String())
+		require.Equal(t, "prometheus_team-b", subtree.Routes[0].Receiver)
+		require.Equal(t, []string{"prometheus_weekends"}, subtree.Routes[0].MuteTimeIntervals)
+		require.Contains(t, saved.TemplateFiles, "prometheus_slack.tmpl")
+		require.Contains(t, saved.TemplateFiles, "a")
+	})
+
+	t.Run("should report no changes when the same configuration is imported again", func(t *testing.T) {
+		applier := &fakeAMConfigApplier{}
+		srv, _, _, _ := createConvertPrometheusSrv(t, withAlertmanagerConfig(fakes.NewFakeAlertmanagerConfigStore(validConfig), applier))
+		headers := map[string]string{configIdentifierHeader: "prometheus", mergeMatchersHeader: `source="prometheus"`}
+		response := srv.RouteConvertPrometheusPostAlertmanagerConfig(newRequest(headers), promConfig)
+		require.Equal(t, http.StatusAccepted, response.Status())
+		require.Len(t, applier.saved, 1)
+
+		// The secure settings of the stored configuration are encrypted.
+		stored := applier.saved[0]
+		for _, r := range stored.AlertmanagerConfig.Receivers {
+			for _, integration := range r.GrafanaManagedReceivers {
+				if r.Name == "grafana-default-email" {
+					integration.SecureSettings = map[string]string{"password": "encrypted"}
+				}
+				for k := range integration.SecureSettings {
+					integration.SecureSettings[k] = "encrypted"
+				}
+			}
+		}
+		raw, err := json.Marshal(stored)
+		require.NoError(t, err)
+		applier = &fakeAMConfigApplier{}
+		srv, _, _, _ = createConvertPrometheusSrv(t, withAlertmanagerConfig(fakes.NewFakeAlertmanagerConfigStore(string(raw)), applier))
+
+		response = srv.RouteConvertPrometheusPostAlertmanagerConfig(newRequest(headers), promConfig)
+		require.Equal(t, http.StatusAccepted, response.Status())
+		var result apimodels.ConvertPrometheusAlertmanagerConfigResponse
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Equal(t, apimodels.ConvertPrometheusAlertmanagerConfigDiff{}, result.Diff)
+
+		// The encrypted secure settings are copied back from the stored configuration when it is saved,
+		// and the imported secrets are saved to be encrypted.
+		require.Len(t, applier.saved, 1)
+		for _, r := range applier.saved[0].AlertmanagerConfig.Receivers {
+			for _, integration := range r.GrafanaManagedReceivers {
+				for _, v := range integration.SecureSettings {
+					require.NotEqual(t, "encrypted", v)
+				}
+				if r.Name == "grafana-default-email" {
+					require.Nil(t, integration.SecureSettings)
+				}
+			}
+		}
+	})
+}
+
+type fakeAMConfigApplier struct {
+	validated []apimodels.PostableUserConfig
+	saved     []apimodels.PostableUserConfig
+	hashes    []string
+	err       error
+}
+
+func (f *fakeAMConfigApplier) ValidateAlertmanagerConfiguration(_ context.Context, _ int64, config *apimodels.PostableUserConfig) error {
+	f.validated = append(f.validated, *config)
+	return f.err
+}
+
+func (f *fakeAMConfigApplier) UpdateAndApplyAlertmanagerConfiguration(_ context.Context, _ int64, config apimodels.PostableUserConfig, fetchedHash string) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.saved = append(f.saved, config)
+	f.hashes = append(f.hashes, fetchedHash)
+	return nil
+}
+
 type convertPrometheusSrvOptions struct {
 	provenanceStore              provisioning.ProvisioningStore
 	fakeAccessControlRuleService *acfakes.FakeRuleService
 	quotaChecker                 *provisioning.MockQuotaChecker
 	featureToggles               featuremgmt.FeatureToggles
+	amConfigStore                AMConfigStore
+	amConfigApplier              AMConfigApplier
 }
 
 type convertPrometheusSrvOptionsFunc func(*convertPrometheusSrvOptions)
@@ -1306,6 +1525,13 @@ func withFeatureToggles(toggles featuremgmt.FeatureToggles) convertPrometheusSrv
 	}
 }
 
+func withAlertmanagerConfig(store AMConfigStore, applier AMConfigApplier) convertPrometheusSrvOptionsFunc {
+	return func(opts *convertPrometheusSrvOptions) {
+		opts.amConfigStore = store
+		opts.amConfigApplier = applier
+	}
+}
+
 func createConvertPrometheusSrv(t *testing.T, opts ...convertPrometheusSrvOptionsFunc) (*ConvertPrometheusSrv, *dsfakes.FakeCacheService, *fakes.RuleStore, *foldertest.FakeService) {
 	t.Helper()
 
@@ -1318,6 +1544,8 @@ func createConvertPrometheusSrv(t *testing.T, opts ...convertPrometheusSrvOption
 		fakeAccessControlRuleService: &acfakes.FakeRuleService{},
 		quotaChecker:                 quotas,
 		featureToggles:               featuremgmt.WithFeatures(featuremgmt.FlagGrafanaManagedRecordingRulesDatasources),
+		amConfigStore:                fakes.NewFakeAlertmanagerConfigStore(validConfig),
+		amConfigApplier:              &fakeAMConfigApplier{},
 	}
 
 	for _, opt := range opts {
@@ -1358,7 +1586,7 @@ func createConvertPrometheusSrv(t *testing.T, opts ...convertPrometheusSrvOption
 		},
 	}
 
-	srv := NewConvertPrometheusSrv(cfg, log.NewNopLogger(), ruleStore, dsCache, alertRuleService, options.featureToggles)
+	srv := NewConvertPrometheusSrv(cfg, log.NewNopLogger(), ruleStore, dsCache, alertRuleService, options.featureToggles, options.amConfigStore, options.amConfigApplier)
 
 	return srv, dsCache, ruleStore, folderService
 }
@@ -33,7 +33,9 @@ type ProvisioningSrv struct {
 	contactPointService ContactPointService
 	templates           TemplateService
 	muteTimings         MuteTimingService
+	escalationPolicies  EscalationPolicyService
 	alertRules          AlertRuleService
+	ruleTemplates       AlertRuleTemplateService
 	folderSvc           folder.Service
 
 	// XXX: Used to flag recording rules, remove when FT is removed
@@ -68,6 +70,14 @@ type MuteTimingService interface {
 	DeleteMuteTiming(ctx context.Context, name string, orgID int64, provenance definitions.Provenance, version string) error
 }
 
+type EscalationPolicyService interface {
+	GetEscalationPolicies(ctx context.Context, orgID int64) ([]definitions.ProvisionedEscalationPolicy, error)
+	GetEscalationPolicy(ctx context.Context, name string, orgID int64) (definitions.ProvisionedEscalationPolicy, error)
+	CreateEscalationPolicy(ctx context.Context, p definitions.ProvisionedEscalationPolicy, orgID int64) (definitions.ProvisionedEscalationPolicy, error)
+	UpdateEscalationPolicy(ctx context.Context, name string, p definitions.ProvisionedEscalationPolicy, orgID int64) (definitions.ProvisionedEscalationPolicy, error)
+	DeleteEscalationPolicy(ctx context.Context, name string, orgID int64, provenance definitions.Provenance, version string) error
+}
+
 type AlertRuleService interface {
 	GetAlertRules(ctx context.Context, user identity.Requester) ([]*alerting_models.AlertRule, map[string]alerting_models.Provenance, error)
 	GetAlertRule(ctx context.Context, user identity.Requester, ruleUID string) (alerting_models.AlertRule, alerting_models.Provenance, error)
@@ -83,6 +93,15 @@ type AlertRuleService interface {
 	GetAlertGroupsWithFolderFullpath(ctx context.Context, u identity.Requester, opts *provisioning.FilterOptions) ([]alerting_models.AlertRuleGroupWithFolderFullpath, error)
 }
 
+type AlertRuleTemplateService interface {
+	GetTemplates(ctx context.Context, user identity.Requester) ([]alerting_models.AlertRuleTemplate, map[string]alerting_models.Provenance, error)
+	GetTemplate(ctx context.Context, user identity.Requester, uid string) (alerting_models.AlertRuleTemplate, alerting_models.Provenance, error)
+	GetTemplatesWithFolderFullpath(ctx context.Context, user identity.Requester, uids ...string) ([]alerting_models.AlertRuleTemplateWithFolderFullpath, error)
+	CreateTemplate(ctx context.Context, user identity.Requester, template alerting_models.AlertRuleTemplate, provenance alerting_models.Provenance) (alerting_models.AlertRuleTemplate, error)
+	UpdateTemplate(ctx context.Context, user identity.Requester, template alerting_models.AlertRuleTemplate, provenance alerting_models.Provenance) (alerting_models.AlertRuleTemplate, error)
+	DeleteTemplate(ctx context.Context, user identity.Requester, uid string, provenance alerting_models.Provenance) error
+}
+
 func (srv *ProvisioningSrv) RouteGetPolicyTree(c *contextmodel.ReqContext) response.Response {
 	policies, _, err := srv.policies.GetPolicyTree(c.Req.Context(), c.GetOrgID())
 	if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
@@ -318,6 +337,53 @@ func (srv *ProvisioningSrv) RouteDeleteMuteTiming(c *contextmodel.ReqContext, na
 	return response.JSON(http.StatusNoContent, nil)
 }
 
+func (srv *ProvisioningSrv) RouteGetEscalationPolicies(c *contextmodel.ReqContext) response.Response {
+	policies, err := srv.escalationPolicies.GetEscalationPolicies(c.Req.Context(), c.GetOrgID())
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get escalation policies", err)
+	}
+	return response.JSON(http.StatusOK, policies)
+}
+
+func (srv *ProvisioningSrv) RouteGetEscalationPolicy(c *contextmodel.ReqContext, name string) response.Response {
+	policy, err := srv.escalationPolicies.GetEscalationPolicy(c.Req.Context(), name, c.GetOrgID())
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get escalation policy by name", err)
+	}
+	return response.JSON(http.StatusOK, policy)
+}
+
+func (srv *ProvisioningSrv) RoutePostEscalationPolicy(c *contextmodel.ReqContext, p definitions.ProvisionedEscalationPolicy) response.Response {
+	p.Provenance = determineProvenance(c)
+	created, err := srv.escalationPolicies.CreateEscalationPolicy(c.Req.Context(), p, c.GetOrgID())
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to create escalation policy", err)
+	}
+	return response.JSON(http.StatusCreated, created)
+}
+
+func (srv *ProvisioningSrv) RoutePutEscalationPolicy(c *contextmodel.ReqContext, p definitions.ProvisionedEscalationPolicy, name string) response.Response {
+	// if body does not specify name, assume that the path contains the name
+	if p.Name == "" {
+		p.Name = name
+	}
+	p.Provenance = determineProvenance(c)
+	updated, err := srv.escalationPolicies.UpdateEscalationPolicy(c.Req.Context(), name, p, c.GetOrgID())
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to update escalation policy", err)
+	}
+	return response.JSON(http.StatusAccepted, updated)
+}
+
+func (srv *ProvisioningSrv) RouteDeleteEscalationPolicy(c *contextmodel.ReqContext, name string) response.Response {
+	version := c.Query("version")
+	err := srv.escalationPolicies.DeleteEscalationPolicy(c.Req.Context(), name, c.GetOrgID(), determineProvenance(c), version)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to delete escalation policy", err)
+	}
+	return response.JSON(http.StatusNoContent, nil)
+}
+
 func (srv *ProvisioningSrv) RouteGetAlertRules(c *contextmodel.ReqContext) response.Response {
 	rules, provenances, err := srv.alertRules.GetAlertRules(c.Req.Context(), c.SignedInUser)
 	if err != nil {
@@ -441,7 +507,12 @@ func (srv *ProvisioningSrv) RouteGetAlertRulesExport(c *contextmodel.ReqContext)
 		return response.Empty(http.StatusNotFound)
 	}
 
-	e, err := AlertingFileExportFromAlertRuleGroupWithFolderFullpath(groupsWithFullpath)
+	templates, _, err := srv.ruleTemplates.GetTemplates(c.Req.Context(), c.SignedInUser)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get alert rule templates", err)
+	}
+
+	e, err := alertingFileExportWithTemplates(groupsWithFullpath, templates)
 	if err != nil {
 		return response.ErrOrFallback(http.StatusInternalServerError, "failed to create alerting file export", err)
 	}
@@ -456,7 +527,12 @@ func (srv *ProvisioningSrv) RouteGetAlertRuleGroupExport(c *contextmodel.ReqCont
 		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get alert rule group", err)
 	}
 
-	e, err := AlertingFileExportFromAlertRuleGroupWithFolderFullpath([]alerting_models.AlertRuleGroupWithFolderFullpath{g})
+	templates, _, err := srv.ruleTemplates.GetTemplates(c.Req.Context(), c.SignedInUser)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get alert rule templates", err)
+	}
+
+	e, err := alertingFileExportWithTemplates([]alerting_models.AlertRuleGroupWithFolderFullpath{g}, templates)
 	if err != nil {
 		return response.ErrOrFallback(http.StatusInternalServerError, "failed to create alerting file export", err)
 	}
@@ -514,6 +590,136 @@ func (srv *ProvisioningSrv) RouteDeleteAlertRuleGroup(c *contextmodel.ReqContext
 	return response.JSON(http.StatusNoContent, "")
 }
 
+func (srv *ProvisioningSrv) RouteGetAlertRuleTemplates(c *contextmodel.ReqContext) response.Response {
+	templates, provenances, err := srv.ruleTemplates.GetTemplates(c.Req.Context(), c.SignedInUser)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get alert rule templates", err)
+	}
+	return response.JSON(http.StatusOK, ProvisionedAlertRuleTemplatesFromAlertRuleTemplates(templates, provenances))
+}
+
+func (srv *ProvisioningSrv) RouteGetAlertRuleTemplate(c *contextmodel.ReqContext, UID string) response.Response {
+	template, provenance, err := srv.ruleTemplates.GetTemplate(c.Req.Context(), c.SignedInUser, UID)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get alert rule template", err)
+	}
+	return response.JSON(http.StatusOK, ProvisionedAlertRuleTemplateFromAlertRuleTemplate(template, provenance))
+}
+
+// RouteGetAlertRuleTemplateExport retrieves the given alert rule template in a format compatible with file provisioning.
+func (srv *ProvisioningSrv) RouteGetAlertRuleTemplateExport(c *contextmodel.ReqContext, UID string) response.Response {
+	templates, err := srv.ruleTemplates.GetTemplatesWithFolderFullpath(c.Req.Context(), c.SignedInUser, UID)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get alert rule template", err)
+	}
+
+	e := definitions.AlertingFileExport{APIVersion: 1}
+	for _, t := range templates {
+		export, err := AlertRuleTemplateExportFromAlertRuleTemplateWithFolderFullpath(t)
+		if err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "failed to create alerting file export")
+		}
+		e.RuleTemplates = append(e.RuleTemplates, export)
+	}
+
+	return exportResponse(c, e)
+}
+
+func (srv *ProvisioningSrv) RoutePostAlertRuleTemplate(c *contextmodel.ReqContext, body definitions.ProvisionedAlertRuleTemplate) response.Response {
+	template, err := AlertRuleTemplateFromProvisionedAlertRuleTemplate(body)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+
+	provenance := determineProvenance(c)
+	created, err := srv.ruleTemplates.CreateTemplate(c.Req.Context(), c.SignedInUser, template, alerting_models.Provenance(provenance))
+	if err != nil {
+		return alertRuleTemplateErrorResponse(err)
+	}
+	return response.JSON(http.StatusCreated, ProvisionedAlertRuleTemplateFromAlertRuleTemplate(created, alerting_models.Provenance(provenance)))
+}
+
+func (srv *ProvisioningSrv) RoutePutAlertRuleTemplate(c *contextmodel.ReqContext, body definitions.ProvisionedAlertRuleTemplate, UID string) response.Response {
+	if UID == "" {
+		// If there is no UID, return 404 as the UID is part of the URL.
+		return response.Empty(http.StatusNotFound)
+	}
+	template, err := AlertRuleTemplateFromProvisionedAlertRuleTemplate(body)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	template.UID = UID
+
+	provenance := determineProvenance(c)
+	updated, err := srv.ruleTemplates.UpdateTemplate(c.Req.Context(), c.SignedInUser, template, alerting_models.Provenance(provenance))
+	if err != nil {
+		return alertRuleTemplateErrorResponse(err)
+	}
+	return response.JSON(http.StatusOK, ProvisionedAlertRuleTemplateFromAlertRuleTemplate(updated, alerting_models.Provenance(provenance)))
+}
+
+func (srv *ProvisioningSrv) RouteDeleteAlertRuleTemplate(c *contextmodel.ReqContext, UID string) response.Response {
+	provenance := determineProvenance(c)
+	err := srv.ruleTemplates.DeleteTemplate(c.Req.Context(), c.SignedInUser, UID, alerting_models.Provenance(provenance))
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "", err)
+	}
+	return response.JSON(http.StatusNoContent, "")
+}
+
+func alertRuleTemplateErrorResponse(err error) response.Response {
+	if errors.Is(err, alerting_models.ErrAlertRuleTemplateFailedValidation) || errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	if errors.Is(err, store.ErrOptimisticLock) {
+		return ErrResp(http.StatusConflict, err, "")
+	}
+	if errors.Is(err, alerting_models.ErrQuotaReached) {
+		return ErrResp(http.StatusForbidden, err, "")
+	}
+	return response.ErrOrFallback(http.StatusInternalServerError, "", err)
+}
+
+// extractTemplateRuleGroups removes the rule groups that are expanded from the templates, and returns the templates
+// of these groups instead, so that they are exported in the template form.
+func extractTemplateRuleGroups(groups []alerting_models.AlertRuleGroupWithFolderFullpath, templates []alerting_models.AlertRuleTemplate) ([]alerting_models.AlertRuleGroupWithFolderFullpath, []alerting_models.AlertRuleTemplateWithFolderFullpath) {
+	if len(templates) == 0 {
+		return groups, nil
+	}
+	byGroup := make(map[alerting_models.AlertRuleGroupKey]*alerting_models.AlertRuleTemplate, len(templates))
+	for i := range templates {
+		byGroup[templates[i].GetGroupKey()] = &templates[i]
+	}
+	result := make([]alerting_models.AlertRuleGroupWithFolderFullpath, 0, len(groups))
+	var groupTemplates []alerting_models.AlertRuleTemplateWithFolderFullpath
+	for _, group := range groups {
+		key := alerting_models.AlertRuleGroupKey{OrgID: group.OrgID, NamespaceUID: group.FolderUID, RuleGroup: group.Title}
+		if t, ok := byGroup[key]; ok {
+			groupTemplates = append(groupTemplates, alerting_models.AlertRuleTemplateWithFolderFullpath{AlertRuleTemplate: t, FolderFullpath: group.FolderFullpath})
+			continue
+		}
+		result = append(result, group)
+	}
+	return result, groupTemplates
+}
+
+// alertingFileExportWithTemplates creates the export of rule groups, where the groups expanded from templates are exported as templates.
+func alertingFileExportWithTemplates(groups []alerting_models.AlertRuleGroupWithFolderFullpath, templates []alerting_models.AlertRuleTemplate) (definitions.AlertingFileExport, error) {
+	groups, groupTemplates := extractTemplateRuleGroups(groups, templates)
+	e, err := AlertingFileExportFromAlertRuleGroupWithFolderFullpath(groups)
+	if err != nil {
+		return definitions.AlertingFileExport{}, err
+	}
+	for _, t := range groupTemplates {
+		export, err := AlertRuleTemplateExportFromAlertRuleTemplateWithFolderFullpath(t)
+		if err != nil {
+			return definitions.AlertingFileExport{}, err
+		}
+		e.RuleTemplates = append(e.RuleTemplates, export)
+	}
+	return e, nil
+}
+
 func determineProvenance(ctx *contextmodel.ReqContext) definitions.Provenance {
 	if _, disabled := ctx.Req.Header[disableProvenanceHeaderName]; disabled {
 		return definitions.Provenance(alerting_models.ProvenanceNone)
@@ -586,6 +792,9 @@ func escapeAlertingFileExport(body definitions.AlertingFileExport) definitions.A
 	for i, np := range body.Policies {
 		body.Policies[i] = escapeNotificationPolicy(np)
 	}
+	for i, t := range body.RuleTemplates {
+		body.RuleTemplates[i] = escapeRuleTemplate(t)
+	}
 	return body
 }
 
@@ -668,6 +877,33 @@ func escapeRuleGroup(group definitions.AlertRuleGroupExport) definitions.AlertRu
 	return group
 }
 
+// escapeRuleTemplate escapes the template as a rule group. The placeholders in the title, the labels and the metric
+// of the rule are escaped too, so they are not interpolated as environment variables when the file is provisioned.
+// Instance values are escaped as well.
+func escapeRuleTemplate(t definitions.AlertRuleTemplateExport) definitions.AlertRuleTemplateExport {
+	t.Folder = addEscapeCharactersToString(t.Folder)
+	t.Group = addEscapeCharactersToString(t.Group)
+	t.Rule.Title = addEscapeCharactersToString(t.Rule.Title)
+	if t.Rule.Labels != nil {
+		t.Rule.Labels = escapeMapValues(*t.Rule.Labels)
+	}
+	if t.Rule.NotificationSettings != nil {
+		notificationSettings := escapeRuleNotificationSettings(*t.Rule.NotificationSettings)
+		t.Rule.NotificationSettings = &notificationSettings
+	}
+	if t.Rule.Record != nil {
+		record := *t.Rule.Record
+		record.Metric = addEscapeCharactersToString(record.Metric)
+		t.Rule.Record = &record
+	}
+	instances := make([]definitions.AlertRuleTemplateInstanceExport, 0, len(t.Instances))
+	for _, instance := range t.Instances {
+		instances = append(instances, definitions.AlertRuleTemplateInstanceExport{UID: instance.UID, Values: *escapeMapValues(instance.Values)})
+	}
+	t.Instances = instances
+	return t
+}
+
 func escapeRuleNotificationSettings(ns definitions.AlertRuleNotificationSettingsExport) definitions.AlertRuleNotificationSettingsExport {
 	ns.Receiver = addEscapeCharactersToString(ns.Receiver)
 	for j := range ns.GroupBy {
@@ -750,6 +986,16 @@ func exportHcl(download bool, body definitions.AlertingFileExport) response.Resp
 	if err != nil {
 		return response.Error(http.StatusInternalServerError, "body hcl encode", err)
 	}
+	for _, t := range body.RuleTemplates {
+		templateBody, err := encodeRuleTemplateHcl(t)
+		if err != nil {
+			return response.Error(http.StatusInternalServerError, "body hcl encode", err)
+		}
+		if len(hclBody) > 0 {
+			hclBody = append(hclBody, '\n')
+		}
+		hclBody = append(hclBody, templateBody...)
+	}
 	resp := response.Respond(http.StatusOK, hclBody)
 	if download {
 		return resp.
@@ -758,3 +1004,83 @@ func exportHcl(download bool, body definitions.AlertingFileExport) response.Resp
 	}
 	return resp.SetHeader("Content-Type", "text/hcl")
 }
+
+// encodeRuleTemplateHcl encodes the template as a rule group with a dynamic block that generates a rule for each instance.
+// The placeholders are replaced by markers before encoding, because HCL escapes template sequences in strings,
+// and the markers are then replaced by references to the values of the current instance.
+// A placeholder that is a string in a query model becomes a number or a boolean if all values of the parameter are.
+func encodeRuleTemplateHcl(t definitions.AlertRuleTemplateExport) ([]byte, error) {
+	markers := make(map[string]string, len(t.Parameters))
+	for idx, p := range t.Parameters {
+		markers[p] = fmt.Sprintf("__grafana_rule_template_parameter_%d__", idx)
+	}
+	toMarkers := func(s string) string {
+		for p, marker := range markers {
+			s = strings.ReplaceAll(s, "${"+p+"}", marker)
+		}
+		return s
+	}
+	toMarkersMap := func(m *map[string]string) *map[string]string {
+		if m == nil {
+			return nil
+		}
+		result := make(map[string]string, len(*m))
+		for k, v := range *m {
+			result[k] = toMarkers(v)
+		}
+		return &result
+	}
+
+	rule := t.Rule
+	rule.Title = toMarkers(rule.Title)
+	rule.Labels = toMarkersMap(rule.Labels)
+	rule.Annotations = toMarkersMap(rule.Annotations)
+	if rule.Record != nil {
+		record := *rule.Record
+		record.Metric = toMarkers(record.Metric)
+		rule.Record = &record
+	}
+	rule.Data = make([]definitions.AlertQueryExport, 0, len(t.Rule.Data))
+	for _, query := range t.Rule.Data {
+		query.ModelString = toMarkers(query.ModelString)
+		rule.Data = append(rule.Data, query)
+	}
+
+	forEach := make([]map[string]string, 0, len(t.Instances))
+	for _, instance := range t.Instances {
+		forEach = append(forEach, instance.Values)
+	}
+	encoded, err := hcl.Encode(hcl.Resource{
+		Type: "grafana_rule_group",
+		Name: fmt.Sprintf("rule_group_%016x", getHash([]string{t.Group, t.FolderUID})),
+		Body: &definitions.AlertRuleTemplateExportHcl{
+			OrgID:           t.OrgID,
+			Name:            t.Group,
+			FolderUID:       t.FolderUID,
+			IntervalSeconds: t.IntervalSeconds,
+			Rule: definitions.AlertRuleTemplateDynamicBlock{
+				Name:    "rule",
+				ForEach: forEach,
+				Content: rule,
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := string(encoded)
+	for p, marker := range markers {
+		reference := fmt.Sprintf("${rule.value.%s}", p)
+		scalar := len(t.Instances) > 0
+		for _, instance := range t.Instances {
+			scalar = scalar && alerting_models.IsTemplateValueScalar(instance.Values[p])
+		}
+		if scalar {
+			// the model is a JSON string in HCL, therefore quotes of the JSON string are escaped
+			result = strings.ReplaceAll(result, `\"`+marker+`\"`, reference)
+		}
+		result = strings.ReplaceAll(result, marker, reference)
+	}
+	return []byte(result), nil
+}
@@ -259,6 +259,41 @@ func TestProvisioningApi(t *testing.T) {
 		})
 	})
 
+	t.Run("escalation policies", func(t *testing.T) {
+		t.Run("are invalid, POST returns 400", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			policy := definitions.ProvisionedEscalationPolicy{
+				EscalationPolicy: definitions.EscalationPolicy{Name: "on-call", Receiver: "a"},
+			}
+
+			response := sut.RoutePostEscalationPolicy(&rc, policy)
+
+			require.Equal(t, 400, response.Status())
+			require.Contains(t, string(response.Body()), "invalid")
+		})
+
+		t.Run("are missing", func(t *testing.T) {
+			t.Run("GET returns 404", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+
+				response := sut.RouteGetEscalationPolicy(&rc, "does not exist")
+
+				require.Equal(t, 404, response.Status())
+			})
+
+			t.Run("DELETE returns 204", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+
+				response := sut.RouteDeleteEscalationPolicy(&rc, "does not exist")
+
+				require.Equal(t, 204, response.Status())
+			})
+		})
+	})
+
 	t.Run("alert rules", func(t *testing.T) {
 		t.Run("are invalid", func(t *testing.T) {
 			t.Run("POST returns 400 on wrong body params", func(t *testing.T) {
@@ -577,6 +612,124 @@ func TestProvisioningApi(t *testing.T) {
 		})
 	})
 
+	t.Run("alert rule templates", func(t *testing.T) {
+		t.Run("POST returns 201 and expands the rule group", func(t *testing.T) {
+			env := createTestEnv(t, testConfig)
+			sut := createProvisioningSrvSutFromEnv(t, &env)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePostAlertRuleTemplate(&rc, createTestAlertRuleTemplate())
+			require.Equal(t, 201, response.Status())
+
+			response = sut.RouteGetAlertRuleGroup(&rc, "folder-uid", "templated-group")
+			require.Equal(t, 200, response.Status())
+			group := deserializeRuleGroup(t, response.Body())
+			require.Len(t, group.Rules, 2)
+			require.Equal(t, "High latency of api", group.Rules[0].Title)
+			require.Equal(t, map[string]string{"service": "api"}, group.Rules[0].Labels)
+			require.Equal(t, "High latency of web", group.Rules[1].Title)
+		})
+
+		t.Run("POST returns 400 when a value is missing", func(t *testing.T) {
+			env := createTestEnv(t, testConfig)
+			sut := createProvisioningSrvSutFromEnv(t, &env)
+			rc := createTestRequestCtx()
+			template := createTestAlertRuleTemplate()
+			template.Instances[1].Values = map[string]string{}
+
+			response := sut.RoutePostAlertRuleTemplate(&rc, template)
+
+			require.Equal(t, 400, response.Status())
+		})
+
+		t.Run("PUT returns 200 and propagates the changes", func(t *testing.T) {
+			env := createTestEnv(t, testConfig)
+			sut := createProvisioningSrvSutFromEnv(t, &env)
+			rc := createTestRequestCtx()
+			template := createTestAlertRuleTemplate()
+			require.Equal(t, 201, sut.RoutePostAlertRuleTemplate(&rc, template).Status())
+
+			template.Rule.Title = "Latency of ${service} is too high"
+			template.Instances = template.Instances[:1]
+			response := sut.RoutePutAlertRuleTemplate(&rc, template, template.UID)
+			require.Equal(t, 200, response.Status())
+
+			response = sut.RouteGetAlertRuleGroup(&rc, "folder-uid", "templated-group")
+			require.Equal(t, 200, response.Status())
+			group := deserializeRuleGroup(t, response.Body())
+			require.Len(t, group.Rules, 1)
+			require.Equal(t, "Latency of api is too high", group.Rules[0].Title)
+		})
+
+		t.Run("PUT returns 404 when the UID is not specified", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePutAlertRuleTemplate(&rc, createTestAlertRuleTemplate(), "")
+
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("are missing, GET returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RouteGetAlertRuleTemplate(&rc, "does not exist")
+
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("DELETE returns 204 and deletes the rule group", func(t *testing.T) {
+			env := createTestEnv(t, testConfig)
+			sut := createProvisioningSrvSutFromEnv(t, &env)
+			rc := createTestRequestCtx()
+			template := createTestAlertRuleTemplate()
+			require.Equal(t, 201, sut.RoutePostAlertRuleTemplate(&rc, template).Status())
+
+			response := sut.RouteDeleteAlertRuleTemplate(&rc, template.UID)
+			require.Equal(t, 204, response.Status())
+
+			response = sut.RouteGetAlertRuleGroup(&rc, "folder-uid", "templated-group")
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("rule export contains the template instead of the expanded rules", func(t *testing.T) {
+			env := createTestEnv(t, testConfig)
+			sut := createProvisioningSrvSutFromEnv(t, &env)
+			rc := createTestRequestCtx()
+			require.Equal(t, 201, sut.RoutePostAlertRuleTemplate(&rc, createTestAlertRuleTemplate()).Status())
+			rc.Req.Header.Add("Accept", "application/json")
+
+			response := sut.RouteGetAlertRuleGroupExport(&rc, "folder-uid", "templated-group")
+			require.Equal(t, 200, response.Status())
+
+			var export definitions.AlertingFileExport
+			require.NoError(t, json.Unmarshal(response.Body(), &export))
+			require.Empty(t, export.Groups)
+			require.Len(t, export.RuleTemplates, 1)
+			require.Equal(t, "template-uid", export.RuleTemplates[0].UID)
+			require.Equal(t, "High latency of $${service}", export.RuleTemplates[0].Rule.Title)
+			require.Equal(t, []string{"service"}, export.RuleTemplates[0].Parameters)
+			require.Len(t, export.RuleTemplates[0].Instances, 2)
+		})
+
+		t.Run("hcl export contains a dynamic rule block", func(t *testing.T) {
+			env := createTestEnv(t, testConfig)
+			sut := createProvisioningSrvSutFromEnv(t, &env)
+			rc := createTestRequestCtx()
+			require.Equal(t, 201, sut.RoutePostAlertRuleTemplate(&rc, createTestAlertRuleTemplate()).Status())
+			rc.Req.Form.Set("format", "hcl")
+
+			response := sut.RouteGetAlertRuleTemplateExport(&rc, "template-uid")
+			require.Equal(t, 200, response.Status())
+
+			body := string(response.Body())
+			require.Contains(t, body, `dynamic "rule" {`)
+			require.Contains(t, body, `name      = "High latency of ${rule.value.service}"`)
+			require.Contains(t, body, `service = "${rule.value.service}"`)
+		})
+	})
+
 	t.Run("alert rule groups", func(t *testing.T) {
 		t.Run("are present", func(t *testing.T) {
 			sut := createProvisioningSrvSut(t)
@@ -2032,13 +2185,16 @@ func createProvisioningSrvSutFromEnv(t *testing.T, env *testEnvironment) Provisi
 		ngalertfakes.NewFakeReceiverPermissionsService(),
 		tracer,
 	)
+	alertRules := provisioning.NewAlertRuleService(env.store, env.prov, env.folderService, env.quotas, env.xact, 60, 10, 100, env.log, &provisioning.NotificationSettingsValidatorProviderFake{}, env.rulesAuthz)
 	return ProvisioningSrv{
 		log:                 env.log,
 		policies:            newFakeNotificationPolicyService(),
 		contactPointService: provisioning.NewContactPointService(configStore, env.secrets, env.prov, env.xact, receiverSvc, env.log, env.store, ngalertfakes.NewFakeReceiverPermissionsService()),
 		templates:           provisioning.NewTemplateService(configStore, env.prov, env.xact, env.log),
 		muteTimings:         provisioning.NewMuteTimingService(configStore, env.prov, env.xact, env.log, env.store),
-		alertRules:          provisioning.NewAlertRuleService(env.store, env.prov, env.folderService, env.quotas, env.xact, 60, 10, 100, env.log, &provisioning.NotificationSettingsValidatorProviderFake{}, env.rulesAuthz),
+		escalationPolicies:  provisioning.NewEscalationPolicyService(configStore, env.prov, env.xact, env.log),
+		alertRules:          alertRules,
+		ruleTemplates:       provisioning.NewAlertRuleTemplateService(env.store, alertRules, env.prov, env.xact, env.log),
 		folderSvc:           env.folderService,
 		featureManager:      env.features,
 	}
@@ -2215,6 +2371,30 @@ func createTestAlertRuleGroup(orgID int64) definitions.AlertRuleGroup {
 	}
 }
 
+func createTestAlertRuleTemplate() definitions.ProvisionedAlertRuleTemplate {
+	rule := createTestAlertRule("High latency of ${service}", 1)
+	return definitions.ProvisionedAlertRuleTemplate{
+		UID:        "template-uid",
+		FolderUID:  "folder-uid",
+		RuleGroup:  "templated-group",
+		Interval:   60,
+		Parameters: []string{"service"},
+		Rule: definitions.AlertRuleTemplateRule{
+			Title:        rule.Title,
+			Condition:    rule.Condition,
+			Data:         rule.Data,
+			NoDataState:  rule.NoDataState,
+			ExecErrState: rule.ExecErrState,
+			For:          rule.For,
+			Labels:       map[string]string{"service": "${service}"},
+		},
+		Instances: []definitions.AlertRuleTemplateInstance{
+			{UID: "api", Values: map[string]string{"service": "api"}},
+			{UID: "web", Values: map[string]string{"service": "web"}},
+		},
+	}
+}
+
 func createTestAlertRuleWithFolderAndGroup(title string, orgID int64, folderUid string, group string) definitions.ProvisionedAlertRule {
 	rule := createTestAlertRule(title, orgID)
 	rule.FolderUID = folderUid
@@ -64,6 +64,7 @@ type RulerSrv struct {
 	amConfigStore  AMConfigStore
 	amRefresher    AMRefresher
 	featureManager featuremgmt.FeatureToggles
+	acknowledger   AlertInstanceAcknowledger
 }
 
 var (
@@ -145,12 +146,21 @@ func (srv RulerSrv) RouteDeleteAlertRules(c *contextmodel.ReqContext, namespaceU
 		}
 		rulesToDelete := make([]string, 0)
 		provisioned := false
+		var ownedByTemplateErr error
 		for groupKey, rules := range deletionCandidates {
 			if containsProvisionedAlerts(provenances, rules) {
 				logger.Debug("Alert group cannot be deleted because it is provisioned", "group", groupKey.RuleGroup)
 				provisioned = true
 				continue
 			}
+			if err := store.VerifyRuleGroupsNotOwnedByTemplates(ctx, srv.store, groupKey); err != nil {
+				if !errors.Is(err, ngmodels.ErrAlertRuleGroupOwnedByTemplate) {
+					return err
+				}
+				logger.Debug("Alert group cannot be deleted because it belongs to an alert rule template", "group", groupKey.RuleGroup)
+				ownedByTemplateErr = err
+				continue
+			}
 			uid := make([]string, 0, len(rules))
 			for _, rule := range rules {
 				uid = append(uid, rule.UID)
@@ -170,6 +180,9 @@ func (srv RulerSrv) RouteDeleteAlertRules(c *contextmodel.ReqContext, namespaceU
 		if provisioned {
 			return errProvisionedResource
 		}
+		if ownedByTemplateErr != nil {
+			return ownedByTemplateErr
+		}
 		logger.Info("No alert rules were deleted")
 		return nil
 	})
@@ -531,6 +544,14 @@ func (srv RulerSrv) performUpdateAlertRules(ctx context.Context, c *contextmodel
 			return err
 		}
 
+		if err := store.VerifyGroupDeltaNotOwnedByTemplates(tranCtx, srv.store, groupChanges); err != nil {
+			return err
+		}
+
+		if err := store.VerifyGroupDeltaDependencies(tranCtx, srv.store, groupChanges); err != nil {
+			return err
+		}
+
 		finalChanges = store.UpdateCalculatedRuleFields(groupChanges)
 		logger.Debug("Updating database with the authorized changes", "add", len(finalChanges.New), "update", len(finalChanges.New), "delete", len(finalChanges.Delete))
 
@@ -670,6 +691,7 @@ func toGettableExtendedRuleNode(r ngmodels.AlertRule, provenanceRecords map[stri
 			Metadata:                    AlertRuleMetadataFromModelMetadata(r.Metadata),
 			GUID:                        r.GUID,
 			MissingSeriesEvalsToResolve: r.MissingSeriesEvalsToResolve,
+			Dependencies:                ApiDependenciesFromModelDependencies(r.Dependencies),
 		},
 	}
 	forDuration := model.Duration(r.For)
@@ -864,7 +886,7 @@ func (srv RulerSrv) RouteUpdateNamespaceRules(c *contextmodel.ReqContext, body a
 				rulesToUpdate = append(rulesToUpdate, &r)
 			}
 			_, _, err := srv.performUpdateAlertRules(ctx, c, groupKey, rulesToUpdate, false)
-			if errors.Is(err, errProvisionedResource) {
+			if errors.Is(err, errProvisionedResource) || errors.Is(err, ngmodels.ErrAlertRuleGroupOwnedByTemplate) {
 				continue
 			}
 			if err != nil {
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	. "github.com/grafana/grafana/pkg/services/ngalert/api/compat"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// AlertInstanceAcknowledger manages the acknowledgements of firing alert instances.
+type AlertInstanceAcknowledger interface {
+	AcknowledgeState(ctx context.Context, rule *ngmodels.AlertRule, fingerprint data.Fingerprint, cmd state.AcknowledgeCommand) (*state.State, error)
+	UnacknowledgeState(ctx context.Context, rule *ngmodels.AlertRule, fingerprint data.Fingerprint) (*state.State, error)
+}
+
+// RoutePostAlertInstanceAcknowledgement acknowledges the firing alert instance of the rule identified by the fingerprint of its labels.
+func (srv RulerSrv) RoutePostAlertInstanceAcknowledgement(c *contextmodel.ReqContext, ruleUID string, fingerprint string, body apimodels.PostableAlertInstanceAcknowledgement) response.Response {
+	ctx := c.Req.Context()
+	rule, err := srv.getAuthorizedRuleByUid(ctx, c, ruleUID)
+	if err != nil {
+		if errors.Is(err, ngmodels.ErrAlertRuleNotFound) {
+			return response.Empty(http.StatusNotFound)
+		}
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get rule by UID", err)
+	}
+
+	fp, err := strconv.ParseUint(fingerprint, 16, 64)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "invalid fingerprint")
+	}
+
+	cmd := state.AcknowledgeCommand{
+		By:                    c.SignedInUser.GetLogin(),
+		Comment:               body.Comment,
+		ExpiresAt:             body.ExpiresAt,
+		Duration:              time.Duration(body.Duration),
+		SuppressNotifications: body.SuppressNotifications,
+	}
+	if body.Duration != 0 && body.ExpiresAt != nil {
+		return ErrResp(http.StatusBadRequest, errors.New("expiresAt and duration are mutually exclusive"), "")
+	}
+
+	s, err := srv.acknowledger.AcknowledgeState(ctx, &rule, data.Fingerprint(fp), cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to acknowledge alert instance", err)
+	}
+	return response.JSON(http.StatusOK, GettableAlertInstanceAcknowledgementFromAlertInstanceAcknowledgement(s.Acknowledgement))
+}
+
+// RouteDeleteAlertInstanceAcknowledgement removes the acknowledgement of the alert instance of the rule identified by the fingerprint of its labels.
+func (srv RulerSrv) RouteDeleteAlertInstanceAcknowledgement(c *contextmodel.ReqContext, ruleUID string, fingerprint string) response.Response {
+	ctx := c.Req.Context()
+	rule, err := srv.getAuthorizedRuleByUid(ctx, c, ruleUID)
+	if err != nil {
+		if errors.Is(err, ngmodels.ErrAlertRuleNotFound) {
+			return response.Empty(http.StatusNotFound)
+		}
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get rule by UID", err)
+	}
+
+	fp, err := strconv.ParseUint(fingerprint, 16, 64)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "invalid fingerprint")
+	}
+
+	if _, err := srv.acknowledger.UnacknowledgeState(ctx, &rule, data.Fingerprint(fp)); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to remove acknowledgement of alert instance", err)
+	}
+	return response.JSON(http.StatusAccepted, util.DynMap{"message": "acknowledgement removed"})
+}
@@ -0,0 +1,169 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestRoutePostAlertInstanceAcknowledgement(t *testing.T) {
+	orgID := rand.Int63()
+	folder := randFolder()
+	ruleStore := fakes.NewRuleStore(t)
+	ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], folder)
+	gen := models.RuleGen.With(models.RuleGen.WithOrgID(orgID), models.RuleGen.WithNamespaceUID(folder.UID))
+	rule := gen.GenerateRef()
+	ruleStore.PutRule(context.Background(), rule)
+
+	t.Run("acknowledges the alert instance", func(t *testing.T) {
+		ack := &fakeAcknowledger{}
+		svc := createService(ruleStore, nil)
+		svc.acknowledger = ack
+		req := createRequestContextWithPerms(orgID, createPermissionsForRules([]*models.AlertRule{rule}, orgID), nil)
+		req.SignedInUser.(*user.SignedInUser).Login = "admin"
+
+		response := svc.RoutePostAlertInstanceAcknowledgement(req, rule.UID, "abc", apimodels.PostableAlertInstanceAcknowledgement{
+			Comment:               "looking into it",
+			Duration:              model.Duration(time.Hour),
+			SuppressNotifications: true,
+		})
+
+		require.Equal(t, http.StatusOK, response.Status())
+		require.Equal(t, data.Fingerprint(0xabc), ack.fingerprint)
+		require.Equal(t, "admin", ack.cmd.By)
+		require.Equal(t, "looking into it", ack.cmd.Comment)
+		require.True(t, ack.cmd.SuppressNotifications)
+		require.Equal(t, time.Hour, ack.cmd.Duration)
+		require.Nil(t, ack.cmd.ExpiresAt)
+
+		result := apimodels.GettableAlertInstanceAcknowledgement{}
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Equal(t, "admin", result.AcknowledgedBy)
+		require.Equal(t, "looking into it", result.Comment)
+	})
+
+	t.Run("returns 400 if fingerprint is invalid", func(t *testing.T) {
+		svc := createService(ruleStore, nil)
+		svc.acknowledger = &fakeAcknowledger{}
+		req := createRequestContextWithPerms(orgID, createPermissionsForRules([]*models.AlertRule{rule}, orgID), nil)
+
+		response := svc.RoutePostAlertInstanceAcknowledgement(req, rule.UID, "not-a-fingerprint", apimodels.PostableAlertInstanceAcknowledgement{})
+
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("returns 400 if both expiry and duration are set", func(t *testing.T) {
+		svc := createService(ruleStore, nil)
+		svc.acknowledger = &fakeAcknowledger{}
+		req := createRequestContextWithPerms(orgID, createPermissionsForRules([]*models.AlertRule{rule}, orgID), nil)
+		expiresAt := time.Now().Add(time.Hour)
+
+		response := svc.RoutePostAlertInstanceAcknowledgement(req, rule.UID, "abc", apimodels.PostableAlertInstanceAcknowledgement{
+			ExpiresAt: &expiresAt,
+			Duration:  model.Duration(time.Hour),
+		})
+
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("returns 404 if rule does not exist", func(t *testing.T) {
+		svc := createService(ruleStore, nil)
+		svc.acknowledger = &fakeAcknowledger{}
+		req := createRequestContextWithPerms(orgID, createPermissionsForRules([]*models.AlertRule{rule}, orgID), nil)
+
+		response := svc.RoutePostAlertInstanceAcknowledgement(req, "foobar", "abc", apimodels.PostableAlertInstanceAcknowledgement{})
+
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+
+	t.Run("returns 404 if alert instance does not exist", func(t *testing.T) {
+		svc := createService(ruleStore, nil)
+		svc.acknowledger = &fakeAcknowledger{err: state.ErrAlertInstanceNotFound.Errorf("not found")}
+		req := createRequestContextWithPerms(orgID, createPermissionsForRules([]*models.AlertRule{rule}, orgID), nil)
+
+		response := svc.RoutePostAlertInstanceAcknowledgement(req, rule.UID, "abc", apimodels.PostableAlertInstanceAcknowledgement{})
+
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+}
+
+func TestRouteDeleteAlertInstanceAcknowledgement(t *testing.T) {
+	orgID := rand.Int63()
+	folder := randFolder()
+	ruleStore := fakes.NewRuleStore(t)
+	ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], folder)
+	gen := models.RuleGen.With(models.RuleGen.WithOrgID(orgID), models.RuleGen.WithNamespaceUID(folder.UID))
+	rule := gen.GenerateRef()
+	ruleStore.PutRule(context.Background(), rule)
+
+	t.Run("removes the acknowledgement", func(t *testing.T) {
+		ack := &fakeAcknowledger{}
+		svc := createService(ruleStore, nil)
+		svc.acknowledger = ack
+		req := createRequestContextWithPerms(orgID, createPermissionsForRules([]*models.AlertRule{rule}, orgID), nil)
+
+		response := svc.RouteDeleteAlertInstanceAcknowledgement(req, rule.UID, "abc")
+
+		require.Equal(t, http.StatusAccepted, response.Status())
+		require.True(t, ack.unacknowledged)
+		require.Equal(t, data.Fingerprint(0xabc), ack.fingerprint)
+	})
+
+	t.Run("returns 404 if not acknowledged", func(t *testing.T) {
+		svc := createService(ruleStore, nil)
+		svc.acknowledger = &fakeAcknowledger{err: state.ErrAlertInstanceNotAcknowledged.Errorf("not acknowledged")}
+		req := createRequestContextWithPerms(orgID, createPermissionsForRules([]*models.AlertRule{rule}, orgID), nil)
+
+		response := svc.RouteDeleteAlertInstanceAcknowledgement(req, rule.UID, "abc")
+
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+}
+
+type fakeAcknowledger struct {
+	fingerprint    data.Fingerprint
+	cmd            state.AcknowledgeCommand
+	unacknowledged bool
+	err            error
+}
+
+func (f *fakeAcknowledger) AcknowledgeState(_ context.Context, rule *models.AlertRule, fingerprint data.Fingerprint, cmd state.AcknowledgeCommand) (*state.State, error) {
+	f.fingerprint = fingerprint
+	f.cmd = cmd
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &state.State{
+		OrgID:        rule.OrgID,
+		AlertRuleUID: rule.UID,
+		CacheID:      fingerprint,
+		Acknowledgement: &models.AlertInstanceAcknowledgement{
+			By:        cmd.By,
+			At:        time.Now(),
+			Comment:   cmd.Comment,
+			ExpiresAt: cmd.ExpiresAt,
+		},
+	}, nil
+}
+
+func (f *fakeAcknowledger) UnacknowledgeState(_ context.Context, rule *models.AlertRule, fingerprint data.Fingerprint) (*state.State, error) {
+	f.fingerprint = fingerprint
+	f.unacknowledged = true
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &state.State{OrgID: rule.OrgID, AlertRuleUID: rule.UID, CacheID: fingerprint}, nil
+}
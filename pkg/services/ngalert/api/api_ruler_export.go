@@ -97,7 +97,17 @@ func (srv RulerSrv) ExportRules(c *contextmodel.ReqContext) response.Response {
 	// sort result so the response is always stable
 	ngmodels.SortAlertRuleGroupWithFolderTitle(groups)
 
-	e, err := AlertingFileExportFromAlertRuleGroupWithFolderFullpath(groups)
+	// rule groups expanded from templates are exported as templates unless a single rule is requested
+	var templates []ngmodels.AlertRuleTemplate
+	if uid == "" {
+		var err error
+		templates, err = srv.getRuleTemplatesOfGroups(c, groups)
+		if err != nil {
+			return errorToResponse(err)
+		}
+	}
+
+	e, err := alertingFileExportWithTemplates(groups, templates)
 	if err != nil {
 		return ErrResp(http.StatusInternalServerError, err, "failed to create alerting file export")
 	}
@@ -177,3 +187,25 @@ func (srv RulerSrv) getRulesWithFolderFullPathInFolders(c *contextmodel.ReqConte
 	}
 	return result, nil
 }
+
+// getRuleTemplatesOfGroups returns the templates of the rule groups. The groups are expected to be authorized.
+func (srv RulerSrv) getRuleTemplatesOfGroups(c *contextmodel.ReqContext, groups []ngmodels.AlertRuleGroupWithFolderFullpath) ([]ngmodels.AlertRuleTemplate, error) {
+	namespaces := make(map[string]struct{}, len(groups))
+	query := &ngmodels.ListAlertRuleTemplatesQuery{OrgID: c.GetOrgID()}
+	for _, group := range groups {
+		if _, ok := namespaces[group.FolderUID]; ok {
+			continue
+		}
+		namespaces[group.FolderUID] = struct{}{}
+		query.NamespaceUIDs = append(query.NamespaceUIDs, group.FolderUID)
+	}
+	templates, err := srv.store.ListAlertRuleTemplates(c.Req.Context(), query)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]ngmodels.AlertRuleTemplate, 0, len(templates))
+	for _, t := range templates {
+		result = append(result, *t)
+	}
+	return result, nil
+}
@@ -31,6 +31,10 @@ func (srv *HistorySrv) RouteQueryStateHistory(c *contextmodel.ReqContext) respon
 	ruleUID := c.Query("ruleUID")
 	dashUID := c.Query("dashboardUID")
 	panelID := c.QueryInt64("panelID")
+	aggregation, err := models.ParseHistoryAggregation(c.Query("aggregation"))
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
 
 	labels := make(map[string]string)
 	for k, v := range c.Req.URL.Query() {
@@ -49,10 +53,11 @@ func (srv *HistorySrv) RouteQueryStateHistory(c *contextmodel.ReqContext) respon
 		To:           time.Unix(to, 0),
 		Limit:        limit,
 		Labels:       labels,
+		Aggregation:  aggregation,
 	}
 	frame, err := srv.hist.Query(c.Req.Context(), query)
 	if err != nil {
-		return ErrResp(http.StatusInternalServerError, err, "")
+		return response.ErrOrFallback(http.StatusInternalServerError, err.Error(), err)
 	}
 	return response.JSON(http.StatusOK, frame)
 }
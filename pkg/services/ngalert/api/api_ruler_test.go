@@ -204,6 +204,28 @@ func TestRouteDeleteAlertRules(t *testing.T) {
 				deleteCommands := getRecordedCommand(ruleStore)
 				require.Empty(t, deleteCommands)
 			})
+			t.Run("return 409 if group belongs to an alert rule template", func(t *testing.T) {
+				ruleStore := initFakeRuleStore(t)
+
+				groupGen := gen.With(gen.WithNamespace(folder.ToFolderReference()), gen.WithSameGroup())
+
+				templateRules := groupGen.GenerateManyRef(1, 5)
+				ruleStore.PutRule(context.Background(), templateRules...)
+				ruleStore.Templates[orgID] = append(ruleStore.Templates[orgID], &models.AlertRuleTemplate{
+					OrgID:        orgID,
+					UID:          util.GenerateShortUID(),
+					NamespaceUID: folder.UID,
+					RuleGroup:    templateRules[0].RuleGroup,
+				})
+
+				permissions := createPermissionsForRules(templateRules, orgID)
+				requestCtx := createRequestContextWithPerms(orgID, permissions, nil)
+
+				response := createService(ruleStore, nil).RouteDeleteAlertRules(requestCtx, folder.UID, templateRules[0].RuleGroup)
+
+				require.Equalf(t, http.StatusConflict, response.Status(), "Expected 409 but got %d: %v", response.Status(), string(response.Body()))
+				require.Empty(t, getRecordedCommand(ruleStore))
+			})
 		})
 	})
 }
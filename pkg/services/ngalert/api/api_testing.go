@@ -30,7 +30,9 @@ import (
 	apivalidation "github.com/grafana/grafana/pkg/services/ngalert/api/validation"
 	"github.com/grafana/grafana/pkg/services/ngalert/backtesting"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/lint"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/setting"
@@ -41,6 +43,10 @@ type folderService interface {
 	GetNamespaceByUID(ctx context.Context, uid string, orgID int64, user identity.Requester) (*folder.Folder, error)
 }
 
+// backtestHistoryLimit is the maximum number of state history records that are compared with a backtest.
+// It is the largest page that the Loki historian returns.
+const backtestHistoryLimit = 5000
+
 type TestingApiSrv struct {
 	*AlertingProxy
 	DatasourceCache datasources.CacheService
@@ -53,6 +59,9 @@ type TestingApiSrv struct {
 	appUrl          *url.URL
 	tracer          tracing.Tracer
 	folderService   folderService
+	ruleStore       RuleStore
+	mam             *notifier.MultiOrgAlertmanager
+	historian       Historian
 }
 
 // RouteTestGrafanaRuleConfig returns a list of potential alerts for a given rule configuration. This is intended to be
@@ -240,6 +249,16 @@ func (srv TestingApiSrv) BacktestAlertRule(c *contextmodel.ReqContext, cmd apimo
 	if forInterval < 0 {
 		return ErrResp(400, nil, "Bad For interval")
 	}
+	keepFiringFor := time.Duration(cmd.KeepFiringFor)
+	if keepFiringFor < 0 {
+		return ErrResp(400, nil, "Bad KeepFiringFor interval")
+	}
+	if cmd.MissingSeriesEvalsToResolve != nil && *cmd.MissingSeriesEvalsToResolve <= 0 {
+		return ErrResp(400, nil, "MissingSeriesEvalsToResolve must be greater than 0")
+	}
+	if cmd.CompareWithHistory && cmd.RuleUID == "" {
+		return ErrResp(400, nil, "RuleUID is required to compare with the state history")
+	}
 
 	intervalSeconds, err := apivalidation.ValidateInterval(time.Duration(cmd.Interval), srv.cfg.BaseInterval)
 	if err != nil {
@@ -263,18 +282,71 @@ func (srv TestingApiSrv) BacktestAlertRule(c *contextmodel.ReqContext, cmd apimo
 		// ExecErrState:   "",
 		Title: cmd.Title,
 		// prefix backtesting- is to distinguish between executions of regular rule and backtesting in logs (like expression engine, evaluator, state manager etc)
-		UID:             "backtesting-" + util.GenerateShortUID(),
-		OrgID:           c.GetOrgID(),
-		Condition:       cmd.Condition,
-		Data:            queries,
-		IntervalSeconds: intervalSeconds,
-		NoDataState:     noDataState,
-		For:             forInterval,
-		Annotations:     cmd.Annotations,
-		Labels:          cmd.Labels,
-	}
-
-	result, err := srv.backtesting.Test(c.Req.Context(), c.SignedInUser, rule, cmd.From, cmd.To)
+		UID:                         "backtesting-" + util.GenerateShortUID(),
+		OrgID:                       c.GetOrgID(),
+		Condition:                   cmd.Condition,
+		Data:                        queries,
+		IntervalSeconds:             intervalSeconds,
+		NoDataState:                 noDataState,
+		For:                         forInterval,
+		Annotations:                 cmd.Annotations,
+		Labels:                      cmd.Labels,
+		KeepFiringFor:               keepFiringFor,
+		MissingSeriesEvalsToResolve: cmd.MissingSeriesEvalsToResolve,
+	}
+
+	var opts backtesting.Options
+	if cmd.RuleUID != "" {
+		existing, err := srv.ruleStore.GetAlertRuleByUID(c.Req.Context(), &ngmodels.GetAlertRuleByUIDQuery{
+			UID:   cmd.RuleUID,
+			OrgID: c.GetOrgID(),
+		})
+		if err != nil {
+			if errors.Is(err, ngmodels.ErrAlertRuleNotFound) {
+				return ErrResp(http.StatusNotFound, err, "")
+			}
+			return ErrResp(http.StatusInternalServerError, err, "Failed to get alert rule")
+		}
+		if err := srv.authz.AuthorizeAccessInFolder(c.Req.Context(), c.SignedInUser, existing); err != nil {
+			return errorToResponse(err)
+		}
+		namespace, err := srv.folderService.GetNamespaceByUID(c.Req.Context(), existing.NamespaceUID, c.GetOrgID(), c.SignedInUser)
+		if err != nil {
+			return errorToResponse(err)
+		}
+		rule.NamespaceUID = existing.NamespaceUID
+		rule.NotificationSettings = existing.NotificationSettings
+		includeFolder := !srv.cfg.ReservedLabels.IsReservedLabelDisabled(ngmodels.FolderTitleLabel)
+		opts.ExtraLabels = state.GetRuleExtraLabels(srv.log, rule, namespace.Fullpath, includeFolder)
+	}
+
+	if cmd.SimulateNotifications {
+		tree, err := srv.mam.GetRoutingTree(c.Req.Context(), c.GetOrgID(), nil)
+		if err != nil {
+			if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+				return ErrResp(http.StatusNotFound, err, "")
+			}
+			return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get the notification policy tree", err)
+		}
+		opts.Router = tree
+	}
+
+	if cmd.CompareWithHistory {
+		history, err := srv.historian.Query(c.Req.Context(), ngmodels.HistoryQuery{
+			RuleUID:      cmd.RuleUID,
+			OrgID:        c.GetOrgID(),
+			From:         cmd.From,
+			To:           cmd.To,
+			Limit:        backtestHistoryLimit,
+			SignedInUser: c.SignedInUser,
+		})
+		if err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "Failed to query the state history")
+		}
+		opts.History = history
+	}
+
+	result, err := srv.backtesting.Test(c.Req.Context(), c.SignedInUser, rule, cmd.From, cmd.To, opts)
 	if err != nil {
 		if errors.Is(err, backtesting.ErrInvalidInputData) {
 			return ErrResp(400, err, "Failed to evaluate")
@@ -288,3 +360,27 @@ func (srv TestingApiSrv) BacktestAlertRule(c *contextmodel.ReqContext, cmd apimo
 	}
 	return response.JSON(http.StatusOK, body)
 }
+
+// RouteLintGrafanaRuleGroup runs the lint checks against the rules of a rule group and returns the findings.
+// The rules are validated the same way as when the group is saved but the rule group is not saved.
+func (srv TestingApiSrv) RouteLintGrafanaRuleGroup(c *contextmodel.ReqContext, body apimodels.PostableRuleGroupConfig) response.Response {
+	rules, err := apivalidation.ValidateRuleGroup(&body, c.GetOrgID(), "", apivalidation.RuleLimitsFromConfig(srv.cfg, srv.featureManager))
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+
+	findings := make([]apimodels.RuleLintFinding, 0)
+	for _, r := range rules {
+		for _, f := range lint.LintRule(&r.AlertRule) {
+			findings = append(findings, apimodels.RuleLintFinding{
+				RuleUID:   f.RuleUID,
+				RuleTitle: f.RuleTitle,
+				RefID:     f.RefID,
+				Check:     f.Check,
+				Severity:  string(f.Severity),
+				Message:   f.Message,
+			})
+		}
+	}
+	return response.JSON(http.StatusOK, apimodels.RuleLintResponse{Findings: findings})
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
@@ -26,6 +27,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval/eval_mocks"
+	"github.com/grafana/grafana/pkg/services/ngalert/lint"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	fakes2 "github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
 	"github.com/grafana/grafana/pkg/services/user"
@@ -392,6 +394,47 @@ func TestRouteEvalQueries(t *testing.T) {
 	})
 }
 
+func TestRouteLintGrafanaRuleGroup(t *testing.T) {
+	rc := &contextmodel.ReqContext{
+		Context: &web.Context{
+			Req: &http.Request{},
+		},
+		SignedInUser: &user.SignedInUser{
+			OrgID: 1,
+		},
+	}
+	srv := createTestingApiSrv(t, nil, nil, nil, featuremgmt.WithFeatures(), nil)
+
+	t.Run("should return the findings of the rules", func(t *testing.T) {
+		rule := validRule()
+		forDuration := model.Duration(srv.cfg.BaseInterval)
+		rule.For = &forDuration
+		rule.GrafanaManagedAlert.Data[0].Model = json.RawMessage(`{"maxDataPoints": 100}`)
+		group := definitions.PostableRuleGroupConfig{
+			Name:     "group",
+			Interval: model.Duration(10 * srv.cfg.BaseInterval),
+			Rules:    []definitions.PostableExtendedRuleNode{rule},
+		}
+
+		response := srv.RouteLintGrafanaRuleGroup(rc, group)
+		require.Equal(t, http.StatusOK, response.Status())
+
+		var result definitions.RuleLintResponse
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Len(t, result.Findings, 1)
+		require.Equal(t, rule.GrafanaManagedAlert.UID, result.Findings[0].RuleUID)
+		require.Equal(t, lint.CheckForShorterThanInterval, result.Findings[0].Check)
+		require.Equal(t, string(lint.SeverityWarning), result.Findings[0].Severity)
+	})
+
+	t.Run("should return BadRequest if the rule group is invalid", func(t *testing.T) {
+		response := srv.RouteLintGrafanaRuleGroup(rc, definitions.PostableRuleGroupConfig{
+			Rules: []definitions.PostableExtendedRuleNode{validRule()},
+		})
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+}
+
 func createTestingApiSrv(t *testing.T, ds *fakes.FakeCacheService, ac *acMock.Mock, evaluator eval.EvaluatorFactory, featureManager featuremgmt.FeatureToggles, ruleStore RuleStore) *TestingApiSrv {
 	if ac == nil {
 		ac = acMock.New()
@@ -67,6 +67,14 @@ func (api *API) authorize(method, path string) web.Handler {
 		)
 	case http.MethodDelete + "/api/ruler/grafana/api/v1/trash/rule/guid/{RuleGUID}":
 		return middleware.ReqOrgAdmin
+	// access to the rule is checked by the handler
+	case http.MethodPost + "/api/ruler/grafana/api/v1/rule/{RuleUID}/instances/{Fingerprint}/ack",
+		http.MethodDelete + "/api/ruler/grafana/api/v1/rule/{RuleUID}/instances/{Fingerprint}/ack":
+		eval = ac.EvalAll(
+			ac.EvalPermission(ac.ActionAlertingRuleRead),
+			ac.EvalPermission(dashboards.ActionFoldersRead),
+			ac.EvalPermission(ac.ActionAlertingInstanceUpdate),
+		)
 
 	// Grafana rule state history paths
 	case http.MethodGet + "/api/v1/rules/history":
@@ -87,6 +95,8 @@ func (api *API) authorize(method, path string) web.Handler {
 	case http.MethodPost + "/api/v1/eval":
 		// additional authorization is done in the request handler
 		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
+	case http.MethodPost + "/api/v1/rule/lint":
+		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
 
 	// Lotex Paths
 	case http.MethodDelete + "/api/ruler/{DatasourceUID}/api/v1/rules/{Namespace}":
@@ -147,6 +157,9 @@ func (api *API) authorize(method, path string) web.Handler {
 			ac.EvalPermission(ac.ActionAlertingProvisioningSetStatus),
 		)
 
+	case http.MethodPost + "/api/convert/api/v1/alerts":
+		eval = ac.EvalPermission(ac.ActionAlertingNotificationsWrite)
+
 	// Alert Instances and Silences
 
 	// Silences for Grafana paths.
@@ -186,6 +199,48 @@ func (api *API) authorize(method, path string) web.Handler {
 			),
 		)
 
+	// Recurring silences. They are authorized like the silences that they create,
+	// further authorization is done in the request handler.
+	case http.MethodGet + "/api/alertmanager/grafana/api/v1/recurring-silences",
+		http.MethodGet + "/api/alertmanager/grafana/api/v1/recurring-silences/{UID}":
+		eval = ac.EvalAny(
+			ac.EvalPermission(ac.ActionAlertingInstanceRead),
+			ac.EvalPermission(ac.ActionAlertingSilencesRead),
+		)
+	case http.MethodPost + "/api/alertmanager/grafana/api/v1/recurring-silences":
+		eval = ac.EvalAll(
+			ac.EvalAny(
+				ac.EvalPermission(ac.ActionAlertingInstanceRead),
+				ac.EvalPermission(ac.ActionAlertingSilencesRead),
+			),
+			ac.EvalAny(
+				ac.EvalPermission(ac.ActionAlertingInstanceCreate),
+				ac.EvalPermission(ac.ActionAlertingSilencesCreate),
+			),
+		)
+	case http.MethodPut + "/api/alertmanager/grafana/api/v1/recurring-silences/{UID}",
+		http.MethodDelete + "/api/alertmanager/grafana/api/v1/recurring-silences/{UID}":
+		eval = ac.EvalAll(
+			ac.EvalAny(
+				ac.EvalPermission(ac.ActionAlertingInstanceRead),
+				ac.EvalPermission(ac.ActionAlertingSilencesRead),
+			),
+			ac.EvalAny(
+				ac.EvalPermission(ac.ActionAlertingInstanceUpdate),
+				ac.EvalPermission(ac.ActionAlertingSilencesWrite),
+			),
+		)
+
+	// Notification delivery log. It contains the alerts of the notifications, so it requires access to both.
+	case http.MethodGet + "/api/alertmanager/grafana/api/v1/deliveries":
+		eval = ac.EvalAll(
+			ac.EvalPermission(ac.ActionAlertingInstanceRead),
+			ac.EvalAny(
+				ac.EvalPermission(ac.ActionAlertingNotificationsRead),
+				ac.EvalPermission(ac.ActionAlertingReceiversRead),
+			),
+		)
+
 	// Alert Instances. Grafana Paths
 	case http.MethodGet + "/api/alertmanager/grafana/api/v2/alerts/groups":
 		eval = ac.EvalPermission(ac.ActionAlertingInstanceRead)
@@ -242,6 +297,11 @@ func (api *API) authorize(method, path string) web.Handler {
 			ac.EvalPermission(ac.ActionAlertingNotificationsWrite),
 			ac.EvalPermission(ac.ActionAlertingReceiversTest),
 		)
+	case http.MethodPost + "/api/alertmanager/grafana/config/api/v1/routing/test":
+		eval = ac.EvalAny(
+			ac.EvalPermission(ac.ActionAlertingNotificationsRead),
+			ac.EvalPermission(ac.ActionAlertingRoutesRead),
+		)
 	case http.MethodPost + "/api/alertmanager/grafana/config/api/v1/templates/test":
 		eval = ac.EvalAny(
 			ac.EvalPermission(ac.ActionAlertingNotificationsWrite),
@@ -275,6 +335,11 @@ func (api *API) authorize(method, path string) web.Handler {
 		http.MethodGet + "/api/v1/ngalert/alertmanagers":
 		return middleware.ReqOrgAdmin
 
+	// Alerting state export and import Paths
+	case http.MethodGet + "/api/v1/ngalert/admin/state/export",
+		http.MethodPost + "/api/v1/ngalert/admin/state/import":
+		return middleware.ReqGrafanaAdmin
+
 	// Grafana-only Provisioning Export Paths for everything except contact points.
 	case http.MethodGet + "/api/v1/provisioning/policies/export",
 		http.MethodGet + "/api/v1/provisioning/mute-timings/export",
@@ -300,7 +365,8 @@ func (api *API) authorize(method, path string) web.Handler {
 		eval = ac.EvalAny(perms...)
 
 	case http.MethodGet + "/api/v1/provisioning/alert-rules",
-		http.MethodGet + "/api/v1/provisioning/alert-rules/export":
+		http.MethodGet + "/api/v1/provisioning/alert-rules/export",
+		http.MethodGet + "/api/v1/provisioning/alert-rule-templates":
 		eval = ac.EvalAny(
 			ac.EvalPermission(ac.ActionAlertingProvisioningRead),
 			ac.EvalPermission(ac.ActionAlertingRulesProvisioningRead),
@@ -311,7 +377,9 @@ func (api *API) authorize(method, path string) web.Handler {
 			),
 		)
 	case http.MethodGet + "/api/v1/provisioning/alert-rules/{UID}",
-		http.MethodGet + "/api/v1/provisioning/alert-rules/{UID}/export":
+		http.MethodGet + "/api/v1/provisioning/alert-rules/{UID}/export",
+		http.MethodGet + "/api/v1/provisioning/alert-rule-templates/{UID}",
+		http.MethodGet + "/api/v1/provisioning/alert-rule-templates/{UID}/export":
 		eval = ac.EvalAny(
 			ac.EvalPermission(ac.ActionAlertingProvisioningRead),
 			ac.EvalPermission(ac.ActionAlertingRulesProvisioningRead),
@@ -340,7 +408,9 @@ func (api *API) authorize(method, path string) web.Handler {
 		http.MethodGet + "/api/v1/provisioning/templates",
 		http.MethodGet + "/api/v1/provisioning/templates/{name}",
 		http.MethodGet + "/api/v1/provisioning/mute-timings",
-		http.MethodGet + "/api/v1/provisioning/mute-timings/{name}":
+		http.MethodGet + "/api/v1/provisioning/mute-timings/{name}",
+		http.MethodGet + "/api/v1/provisioning/escalation-policies",
+		http.MethodGet + "/api/v1/provisioning/escalation-policies/{name}":
 		eval = ac.EvalAny(
 			ac.EvalPermission(ac.ActionAlertingProvisioningRead),
 			ac.EvalPermission(ac.ActionAlertingNotificationsProvisioningRead), // organization scope
@@ -349,6 +419,23 @@ func (api *API) authorize(method, path string) web.Handler {
 		)
 
 	// Grafana-only Provisioning Write Paths
+	case http.MethodPost + "/api/v1/provisioning/alert-rule-templates",
+		http.MethodPut + "/api/v1/provisioning/alert-rule-templates/{UID}",
+		http.MethodDelete + "/api/v1/provisioning/alert-rule-templates/{UID}":
+		eval = ac.EvalAny(
+			ac.EvalPermission(ac.ActionAlertingProvisioningWrite),
+			ac.EvalPermission(ac.ActionAlertingRulesProvisioningWrite),
+			ac.EvalAll(
+				ac.EvalPermission(ac.ActionAlertingRuleRead),
+				ac.EvalPermission(dashboards.ActionFoldersRead),
+				ac.EvalPermission(ac.ActionAlertingProvisioningSetStatus),
+				ac.EvalAny( // the exact permissions are checked by the handler when the rule group of the template is replaced
+					ac.EvalPermission(ac.ActionAlertingRuleUpdate),
+					ac.EvalPermission(ac.ActionAlertingRuleCreate),
+					ac.EvalPermission(ac.ActionAlertingRuleDelete),
+				),
+			),
+		)
 	case http.MethodPost + "/api/v1/provisioning/alert-rules":
 		eval = ac.EvalAny(
 			ac.EvalPermission(ac.ActionAlertingProvisioningWrite),
@@ -414,7 +501,10 @@ func (api *API) authorize(method, path string) web.Handler {
 		http.MethodDelete + "/api/v1/provisioning/templates/{name}",
 		http.MethodPost + "/api/v1/provisioning/mute-timings",
 		http.MethodPut + "/api/v1/provisioning/mute-timings/{name}",
-		http.MethodDelete + "/api/v1/provisioning/mute-timings/{name}":
+		http.MethodDelete + "/api/v1/provisioning/mute-timings/{name}",
+		http.MethodPost + "/api/v1/provisioning/escalation-policies",
+		http.MethodPut + "/api/v1/provisioning/escalation-policies/{name}",
+		http.MethodDelete + "/api/v1/provisioning/escalation-policies/{name}":
 		eval = ac.EvalAny(
 			ac.EvalPermission(ac.ActionAlertingProvisioningWrite),              // organization scope,
 			ac.EvalPermission(ac.ActionAlertingNotificationsProvisioningWrite), // organization scope
@@ -36,6 +36,7 @@ func AlertRuleFromProvisionedAlertRule(a definitions.ProvisionedAlertRule) (mode
 		NotificationSettings:        NotificationSettingsFromAlertRuleNotificationSettings(a.NotificationSettings),
 		Record:                      ModelRecordFromApiRecord(a.Record),
 		MissingSeriesEvalsToResolve: a.MissingSeriesEvalsToResolve,
+		Dependencies:                ModelDependenciesFromApiDependencies(a.Dependencies),
 	}
 
 	if rule.Type() == models.RuleTypeRecording {
@@ -68,6 +69,7 @@ func ProvisionedAlertRuleFromAlertRule(rule models.AlertRule, provenance models.
 		NotificationSettings:        AlertRuleNotificationSettingsFromNotificationSettings(rule.NotificationSettings),
 		Record:                      ApiRecordFromModelRecord(rule.Record),
 		MissingSeriesEvalsToResolve: rule.MissingSeriesEvalsToResolve,
+		Dependencies:                ApiDependenciesFromModelDependencies(rule.Dependencies),
 	}
 }
 
@@ -179,6 +181,112 @@ func AlertRuleGroupExportFromAlertRuleGroupWithFolderFullpath(d models.AlertRule
 	}, nil
 }
 
+// AlertRuleTemplateFromProvisionedAlertRuleTemplate converts definitions.ProvisionedAlertRuleTemplate to models.AlertRuleTemplate
+func AlertRuleTemplateFromProvisionedAlertRuleTemplate(t definitions.ProvisionedAlertRuleTemplate) (models.AlertRuleTemplate, error) {
+	rule, err := AlertRuleFromProvisionedAlertRule(definitions.ProvisionedAlertRule{
+		Title:                       t.Rule.Title,
+		Condition:                   t.Rule.Condition,
+		Data:                        t.Rule.Data,
+		NoDataState:                 t.Rule.NoDataState,
+		ExecErrState:                t.Rule.ExecErrState,
+		For:                         t.Rule.For,
+		KeepFiringFor:               t.Rule.KeepFiringFor,
+		Annotations:                 t.Rule.Annotations,
+		Labels:                      t.Rule.Labels,
+		IsPaused:                    t.Rule.IsPaused,
+		NotificationSettings:        t.Rule.NotificationSettings,
+		Record:                      t.Rule.Record,
+		MissingSeriesEvalsToResolve: t.Rule.MissingSeriesEvalsToResolve,
+	})
+	if err != nil {
+		return models.AlertRuleTemplate{}, err
+	}
+	instances := make([]models.AlertRuleTemplateInstance, 0, len(t.Instances))
+	for _, instance := range t.Instances {
+		instances = append(instances, models.AlertRuleTemplateInstance{UID: instance.UID, Values: instance.Values})
+	}
+	return models.AlertRuleTemplate{
+		UID:             t.UID,
+		NamespaceUID:    t.FolderUID,
+		RuleGroup:       t.RuleGroup,
+		IntervalSeconds: t.Interval,
+		Parameters:      t.Parameters,
+		Rule:            rule,
+		Instances:       instances,
+		Version:         t.Version,
+	}, nil
+}
+
+// ProvisionedAlertRuleTemplateFromAlertRuleTemplate converts models.AlertRuleTemplate to definitions.ProvisionedAlertRuleTemplate and sets provided provenance status
+func ProvisionedAlertRuleTemplateFromAlertRuleTemplate(t models.AlertRuleTemplate, provenance models.Provenance) definitions.ProvisionedAlertRuleTemplate {
+	rule := ProvisionedAlertRuleFromAlertRule(t.Rule, provenance)
+	instances := make([]definitions.AlertRuleTemplateInstance, 0, len(t.Instances))
+	for _, instance := range t.Instances {
+		instances = append(instances, definitions.AlertRuleTemplateInstance{UID: instance.UID, Values: instance.Values})
+	}
+	return definitions.ProvisionedAlertRuleTemplate{
+		UID:        t.UID,
+		FolderUID:  t.NamespaceUID,
+		RuleGroup:  t.RuleGroup,
+		Interval:   t.IntervalSeconds,
+		Parameters: t.Parameters,
+		Rule: definitions.AlertRuleTemplateRule{
+			Title:                       rule.Title,
+			Condition:                   rule.Condition,
+			Data:                        rule.Data,
+			NoDataState:                 rule.NoDataState,
+			ExecErrState:                rule.ExecErrState,
+			For:                         rule.For,
+			KeepFiringFor:               rule.KeepFiringFor,
+			Annotations:                 rule.Annotations,
+			Labels:                      rule.Labels,
+			IsPaused:                    rule.IsPaused,
+			NotificationSettings:        rule.NotificationSettings,
+			Record:                      rule.Record,
+			MissingSeriesEvalsToResolve: rule.MissingSeriesEvalsToResolve,
+		},
+		Instances:  instances,
+		Version:    t.Version,
+		Updated:    t.Updated,
+		Provenance: definitions.Provenance(provenance),
+	}
+}
+
+// ProvisionedAlertRuleTemplatesFromAlertRuleTemplates converts a collection of models.AlertRuleTemplate to definitions.ProvisionedAlertRuleTemplates
+func ProvisionedAlertRuleTemplatesFromAlertRuleTemplates(templates []models.AlertRuleTemplate, provenances map[string]models.Provenance) definitions.ProvisionedAlertRuleTemplates {
+	result := make([]definitions.ProvisionedAlertRuleTemplate, 0, len(templates))
+	for _, t := range templates {
+		result = append(result, ProvisionedAlertRuleTemplateFromAlertRuleTemplate(t, provenances[t.UID]))
+	}
+	return result
+}
+
+// AlertRuleTemplateExportFromAlertRuleTemplateWithFolderFullpath creates a definitions.AlertRuleTemplateExport DTO from models.AlertRuleTemplate.
+func AlertRuleTemplateExportFromAlertRuleTemplateWithFolderFullpath(t models.AlertRuleTemplateWithFolderFullpath) (definitions.AlertRuleTemplateExport, error) {
+	rule, err := AlertRuleExportFromAlertRule(t.Rule)
+	if err != nil {
+		return definitions.AlertRuleTemplateExport{}, err
+	}
+	// the rule of a template has no identity, its instances become rules
+	rule.UID = ""
+	instances := make([]definitions.AlertRuleTemplateInstanceExport, 0, len(t.Instances))
+	for _, instance := range t.Instances {
+		instances = append(instances, definitions.AlertRuleTemplateInstanceExport{UID: instance.UID, Values: instance.Values})
+	}
+	return definitions.AlertRuleTemplateExport{
+		OrgID:           t.OrgID,
+		UID:             t.UID,
+		Folder:          t.FolderFullpath,
+		FolderUID:       t.NamespaceUID,
+		Group:           t.RuleGroup,
+		Interval:        model.Duration(time.Duration(t.IntervalSeconds) * time.Second),
+		IntervalSeconds: t.IntervalSeconds,
+		Parameters:      t.Parameters,
+		Rule:            rule,
+		Instances:       instances,
+	}, nil
+}
+
 // AlertRuleExportFromAlertRule creates a definitions.AlertRuleExport DTO from models.AlertRule.
 func AlertRuleExportFromAlertRule(rule models.AlertRule) (definitions.AlertRuleExport, error) {
 	data := make([]definitions.AlertQueryExport, 0, len(rule.Data))
@@ -236,6 +344,12 @@ func AlertRuleExportFromAlertRule(rule models.AlertRule) (definitions.AlertRuleE
 	if rule.MissingSeriesEvalsToResolve != nil && *rule.MissingSeriesEvalsToResolve != -1 {
 		result.MissingSeriesEvalsToResolve = rule.MissingSeriesEvalsToResolve
 	}
+	for _, d := range rule.Dependencies {
+		result.Dependencies = append(result.Dependencies, definitions.AlertRuleDependencyExport{
+			RuleUID:  d.RuleUID,
+			Matchers: d.Matchers,
+		})
+	}
 
 	return result, nil
 }
@@ -550,6 +664,34 @@ func ApiRecordFromModelRecord(r *models.Record) *definitions.Record {
 	}
 }
 
+func ModelDependenciesFromApiDependencies(deps []definitions.AlertRuleDependency) []models.AlertRuleDependency {
+	if len(deps) == 0 {
+		return nil
+	}
+	result := make([]models.AlertRuleDependency, 0, len(deps))
+	for _, d := range deps {
+		result = append(result, models.AlertRuleDependency{
+			RuleUID:  d.RuleUID,
+			Matchers: d.Matchers,
+		})
+	}
+	return result
+}
+
+func ApiDependenciesFromModelDependencies(deps []models.AlertRuleDependency) []definitions.AlertRuleDependency {
+	if len(deps) == 0 {
+		return nil
+	}
+	result := make([]definitions.AlertRuleDependency, 0, len(deps))
+	for _, d := range deps {
+		result = append(result, definitions.AlertRuleDependency{
+			RuleUID:  d.RuleUID,
+			Matchers: d.Matchers,
+		})
+	}
+	return result
+}
+
 func GettableGrafanaReceiverFromReceiver(r *models.Integration, provenance models.Provenance) (definitions.GettableGrafanaReceiver, error) {
 	out := definitions.GettableGrafanaReceiver{
 		UID:                   r.UID,
@@ -602,3 +744,17 @@ func GettableApiReceiversFromReceivers(recvs []*models.Receiver) ([]*definitions
 	}
 	return out, nil
 }
+
+// GettableAlertInstanceAcknowledgementFromAlertInstanceAcknowledgement converts models.AlertInstanceAcknowledgement to definitions.GettableAlertInstanceAcknowledgement
+func GettableAlertInstanceAcknowledgementFromAlertInstanceAcknowledgement(ack *models.AlertInstanceAcknowledgement) *definitions.GettableAlertInstanceAcknowledgement {
+	if ack == nil {
+		return nil
+	}
+	return &definitions.GettableAlertInstanceAcknowledgement{
+		AcknowledgedBy: ack.By,
+		AcknowledgedAt: ack.At,
+		Comment:        ack.Comment,
+		ExpiresAt:      ack.ExpiresAt,
+		SilenceID:      ack.SilenceID,
+	}
+}
@@ -0,0 +1,36 @@
+package api
+
+import (
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// Notification delivery-specific compat functions to convert between API and model types.
+
+func NotificationDeliveriesToGettableNotificationDeliveries(deliveries []models.NotificationDelivery) definitions.GettableNotificationDeliveries {
+	res := make(definitions.GettableNotificationDeliveries, 0, len(deliveries))
+	for _, d := range deliveries {
+		alerts := make([]definitions.GettableNotificationDeliveryAlert, 0, len(d.Alerts))
+		for _, a := range d.Alerts {
+			alerts = append(alerts, definitions.GettableNotificationDeliveryAlert{
+				RuleUID:     a.RuleUID,
+				Fingerprint: a.Fingerprint,
+				Status:      a.Status,
+				StartsAt:    a.StartsAt,
+			})
+		}
+		res = append(res, definitions.GettableNotificationDelivery{
+			Receiver:         d.Receiver,
+			Integration:      d.Integration,
+			IntegrationIndex: d.IntegrationIndex,
+			GroupKey:         d.GroupKey,
+			Attempt:          d.Attempt,
+			Status:           string(d.Status),
+			Error:            d.Error,
+			DurationMs:       d.Duration.Milliseconds(),
+			Time:             d.Time,
+			Alerts:           alerts,
+		})
+	}
+	return res
+}
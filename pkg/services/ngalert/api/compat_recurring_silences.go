@@ -0,0 +1,58 @@
+package api
+
+import (
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// Recurring silence-specific compat functions to convert between API and model types.
+
+func RecurringSilenceToGettableRecurringSilence(s models.RecurringSilence, provenance models.Provenance) definitions.GettableRecurringSilence {
+	return definitions.GettableRecurringSilence{
+		PostableRecurringSilence: definitions.PostableRecurringSilence{
+			UID:       s.UID,
+			Matchers:  s.Matchers,
+			Comment:   s.Comment,
+			CreatedBy: s.CreatedBy,
+			Schedule: definitions.RecurringSilenceSchedule{
+				Cron:          s.Schedule.Cron,
+				Duration:      model.Duration(s.Schedule.Duration),
+				TimeIntervals: s.Schedule.TimeIntervals,
+				Timezone:      s.Schedule.Timezone,
+			},
+		},
+		Version:    s.Version,
+		Provenance: definitions.Provenance(provenance),
+	}
+}
+
+func RecurringSilencesToGettableRecurringSilences(silences []models.RecurringSilence, provenances map[string]models.Provenance) definitions.GettableRecurringSilences {
+	res := make(definitions.GettableRecurringSilences, 0, len(silences))
+	for _, s := range silences {
+		provenance := models.ProvenanceNone
+		if p, ok := provenances[s.ResourceID()]; ok {
+			provenance = p
+		}
+		res = append(res, RecurringSilenceToGettableRecurringSilence(s, provenance))
+	}
+	return res
+}
+
+func PostableRecurringSilenceToRecurringSilence(s definitions.PostableRecurringSilence) models.RecurringSilence {
+	return models.RecurringSilence{
+		UID:       s.UID,
+		Matchers:  s.Matchers,
+		Comment:   s.Comment,
+		CreatedBy: s.CreatedBy,
+		Schedule: models.RecurringSilenceSchedule{
+			Cron:          s.Schedule.Cron,
+			Duration:      time.Duration(s.Schedule.Duration),
+			TimeIntervals: s.Schedule.TimeIntervals,
+			Timezone:      s.Schedule.Timezone,
+		},
+	}
+}
@@ -36,3 +36,11 @@ func (f *ConfigurationApiHandler) handleRouteDeleteNGalertConfig(c *contextmodel
 func (f *ConfigurationApiHandler) handleRouteGetStatus(c *contextmodel.ReqContext) response.Response {
 	return f.grafana.RouteGetAlertingStatus(c)
 }
+
+func (f *ConfigurationApiHandler) handleRouteExportAlertingState(c *contextmodel.ReqContext) response.Response {
+	return f.grafana.RouteExportAlertingState(c)
+}
+
+func (f *ConfigurationApiHandler) handleRouteImportAlertingState(c *contextmodel.ReqContext, body apimodels.PostableAlertStateImport) response.Response {
+	return f.grafana.RouteImportAlertingState(c, body)
+}
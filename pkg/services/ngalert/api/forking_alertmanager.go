@@ -183,6 +183,34 @@ func (f *AlertmanagerApiHandler) handleRoutePostTestGrafanaReceivers(ctx *contex
 	return f.GrafanaSvc.RoutePostTestReceivers(ctx, conf)
 }
 
+func (f *AlertmanagerApiHandler) handleRoutePostTestGrafanaRouting(ctx *contextmodel.ReqContext, conf apimodels.TestRoutingConfigBodyParams) response.Response {
+	return f.GrafanaSvc.RoutePostTestRouting(ctx, conf)
+}
+
 func (f *AlertmanagerApiHandler) handleRoutePostTestGrafanaTemplates(ctx *contextmodel.ReqContext, conf apimodels.TestTemplatesConfigBodyParams) response.Response {
 	return f.GrafanaSvc.RoutePostTestTemplates(ctx, conf)
 }
+
+func (f *AlertmanagerApiHandler) handleRouteGetGrafanaRecurringSilences(ctx *contextmodel.ReqContext) response.Response {
+	return f.GrafanaSvc.RouteGetRecurringSilences(ctx)
+}
+
+func (f *AlertmanagerApiHandler) handleRouteGetGrafanaRecurringSilence(ctx *contextmodel.ReqContext, uid string) response.Response {
+	return f.GrafanaSvc.RouteGetRecurringSilence(ctx, uid)
+}
+
+func (f *AlertmanagerApiHandler) handleRouteCreateGrafanaRecurringSilence(ctx *contextmodel.ReqContext, body apimodels.PostableRecurringSilence) response.Response {
+	return f.GrafanaSvc.RouteCreateRecurringSilence(ctx, body)
+}
+
+func (f *AlertmanagerApiHandler) handleRouteUpdateGrafanaRecurringSilence(ctx *contextmodel.ReqContext, body apimodels.PostableRecurringSilence, uid string) response.Response {
+	return f.GrafanaSvc.RouteUpdateRecurringSilence(ctx, body, uid)
+}
+
+func (f *AlertmanagerApiHandler) handleRouteDeleteGrafanaRecurringSilence(ctx *contextmodel.ReqContext, uid string) response.Response {
+	return f.GrafanaSvc.RouteDeleteRecurringSilence(ctx, uid)
+}
+
+func (f *AlertmanagerApiHandler) handleRouteGetGrafanaNotificationDeliveries(ctx *contextmodel.ReqContext) response.Response {
+	return f.GrafanaSvc.RouteGetNotificationDeliveries(ctx)
+}
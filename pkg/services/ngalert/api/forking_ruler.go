@@ -133,6 +133,14 @@ func (f *RulerApiHandler) handleRouteDeleteRuleFromTrashByGUID(ctx *contextmodel
 	return f.GrafanaRuler.RouteDeleteAlertRuleFromTrashByGUID(ctx, ruleGUID)
 }
 
+func (f *RulerApiHandler) handleRoutePostAlertInstanceAcknowledgement(ctx *contextmodel.ReqContext, body apimodels.PostableAlertInstanceAcknowledgement, ruleUID, fingerprint string) response.Response {
+	return f.GrafanaRuler.RoutePostAlertInstanceAcknowledgement(ctx, ruleUID, fingerprint, body)
+}
+
+func (f *RulerApiHandler) handleRouteDeleteAlertInstanceAcknowledgement(ctx *contextmodel.ReqContext, ruleUID, fingerprint string) response.Response {
+	return f.GrafanaRuler.RouteDeleteAlertInstanceAcknowledgement(ctx, ruleUID, fingerprint)
+}
+
 func (f *RulerApiHandler) handleRouteUpdateNamespaceRules(ctx *contextmodel.ReqContext, body apimodels.UpdateNamespaceRulesRequest, namespace string) response.Response {
 	return f.GrafanaRuler.RouteUpdateNamespaceRules(ctx, body, namespace)
 }
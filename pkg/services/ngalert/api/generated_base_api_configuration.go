@@ -21,15 +21,20 @@ import (
 
 type ConfigurationApi interface {
 	RouteDeleteNGalertConfig(*contextmodel.ReqContext) response.Response
+	RouteExportAlertingState(*contextmodel.ReqContext) response.Response
 	RouteGetAlertmanagers(*contextmodel.ReqContext) response.Response
 	RouteGetNGalertConfig(*contextmodel.ReqContext) response.Response
 	RouteGetStatus(*contextmodel.ReqContext) response.Response
+	RouteImportAlertingState(*contextmodel.ReqContext) response.Response
 	RoutePostNGalertConfig(*contextmodel.ReqContext) response.Response
 }
 
 func (f *ConfigurationApiHandler) RouteDeleteNGalertConfig(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteDeleteNGalertConfig(ctx)
 }
+func (f *ConfigurationApiHandler) RouteExportAlertingState(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteExportAlertingState(ctx)
+}
 func (f *ConfigurationApiHandler) RouteGetAlertmanagers(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetAlertmanagers(ctx)
 }
@@ -39,6 +44,14 @@ func (f *ConfigurationApiHandler) RouteGetNGalertConfig(ctx *contextmodel.ReqCon
 func (f *ConfigurationApiHandler) RouteGetStatus(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetStatus(ctx)
 }
+func (f *ConfigurationApiHandler) RouteImportAlertingState(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.PostableAlertStateImport{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRouteImportAlertingState(ctx, conf)
+}
 func (f *ConfigurationApiHandler) RoutePostNGalertConfig(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Request Body
 	conf := apimodels.PostableNGalertConfig{}
@@ -62,6 +75,18 @@ func (api *API) RegisterConfigurationApiEndpoints(srv ConfigurationApi, m *metri
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/ngalert/admin/state/export"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/ngalert/admin/state/export"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/ngalert/admin/state/export",
+				api.Hooks.Wrap(srv.RouteExportAlertingState),
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/v1/ngalert/alertmanagers"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -98,6 +123,18 @@ func (api *API) RegisterConfigurationApiEndpoints(srv ConfigurationApi, m *metri
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/v1/ngalert/admin/state/import"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/ngalert/admin/state/import"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/ngalert/admin/state/import",
+				api.Hooks.Wrap(srv.RouteImportAlertingState),
+				m,
+			),
+		)
 		group.Post(
 			toMacaronPath("/api/v1/ngalert/admin_config"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -22,7 +22,9 @@ import (
 type ProvisioningApi interface {
 	RouteDeleteAlertRule(*contextmodel.ReqContext) response.Response
 	RouteDeleteAlertRuleGroup(*contextmodel.ReqContext) response.Response
+	RouteDeleteAlertRuleTemplate(*contextmodel.ReqContext) response.Response
 	RouteDeleteContactpoints(*contextmodel.ReqContext) response.Response
+	RouteDeleteEscalationPolicy(*contextmodel.ReqContext) response.Response
 	RouteDeleteMuteTiming(*contextmodel.ReqContext) response.Response
 	RouteDeleteTemplate(*contextmodel.ReqContext) response.Response
 	RouteExportMuteTiming(*contextmodel.ReqContext) response.Response
@@ -31,10 +33,15 @@ type ProvisioningApi interface {
 	RouteGetAlertRuleExport(*contextmodel.ReqContext) response.Response
 	RouteGetAlertRuleGroup(*contextmodel.ReqContext) response.Response
 	RouteGetAlertRuleGroupExport(*contextmodel.ReqContext) response.Response
+	RouteGetAlertRuleTemplate(*contextmodel.ReqContext) response.Response
+	RouteGetAlertRuleTemplateExport(*contextmodel.ReqContext) response.Response
+	RouteGetAlertRuleTemplates(*contextmodel.ReqContext) response.Response
 	RouteGetAlertRules(*contextmodel.ReqContext) response.Response
 	RouteGetAlertRulesExport(*contextmodel.ReqContext) response.Response
 	RouteGetContactpoints(*contextmodel.ReqContext) response.Response
 	RouteGetContactpointsExport(*contextmodel.ReqContext) response.Response
+	RouteGetEscalationPolicies(*contextmodel.ReqContext) response.Response
+	RouteGetEscalationPolicy(*contextmodel.ReqContext) response.Response
 	RouteGetMuteTiming(*contextmodel.ReqContext) response.Response
 	RouteGetMuteTimings(*contextmodel.ReqContext) response.Response
 	RouteGetPolicyTree(*contextmodel.ReqContext) response.Response
@@ -42,11 +49,15 @@ type ProvisioningApi interface {
 	RouteGetTemplate(*contextmodel.ReqContext) response.Response
 	RouteGetTemplates(*contextmodel.ReqContext) response.Response
 	RoutePostAlertRule(*contextmodel.ReqContext) response.Response
+	RoutePostAlertRuleTemplate(*contextmodel.ReqContext) response.Response
 	RoutePostContactpoints(*contextmodel.ReqContext) response.Response
+	RoutePostEscalationPolicy(*contextmodel.ReqContext) response.Response
 	RoutePostMuteTiming(*contextmodel.ReqContext) response.Response
 	RoutePutAlertRule(*contextmodel.ReqContext) response.Response
 	RoutePutAlertRuleGroup(*contextmodel.ReqContext) response.Response
+	RoutePutAlertRuleTemplate(*contextmodel.ReqContext) response.Response
 	RoutePutContactpoint(*contextmodel.ReqContext) response.Response
+	RoutePutEscalationPolicy(*contextmodel.ReqContext) response.Response
 	RoutePutMuteTiming(*contextmodel.ReqContext) response.Response
 	RoutePutPolicyTree(*contextmodel.ReqContext) response.Response
 	RoutePutTemplate(*contextmodel.ReqContext) response.Response
@@ -64,11 +75,21 @@ func (f *ProvisioningApiHandler) RouteDeleteAlertRuleGroup(ctx *contextmodel.Req
 	groupParam := web.Params(ctx.Req)[":Group"]
 	return f.handleRouteDeleteAlertRuleGroup(ctx, folderUIDParam, groupParam)
 }
+func (f *ProvisioningApiHandler) RouteDeleteAlertRuleTemplate(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.handleRouteDeleteAlertRuleTemplate(ctx, uIDParam)
+}
 func (f *ProvisioningApiHandler) RouteDeleteContactpoints(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	uIDParam := web.Params(ctx.Req)[":UID"]
 	return f.handleRouteDeleteContactpoints(ctx, uIDParam)
 }
+func (f *ProvisioningApiHandler) RouteDeleteEscalationPolicy(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	nameParam := web.Params(ctx.Req)[":name"]
+	return f.handleRouteDeleteEscalationPolicy(ctx, nameParam)
+}
 func (f *ProvisioningApiHandler) RouteDeleteMuteTiming(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	nameParam := web.Params(ctx.Req)[":name"]
@@ -109,6 +130,19 @@ func (f *ProvisioningApiHandler) RouteGetAlertRuleGroupExport(ctx *contextmodel.
 	groupParam := web.Params(ctx.Req)[":Group"]
 	return f.handleRouteGetAlertRuleGroupExport(ctx, folderUIDParam, groupParam)
 }
+func (f *ProvisioningApiHandler) RouteGetAlertRuleTemplate(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.handleRouteGetAlertRuleTemplate(ctx, uIDParam)
+}
+func (f *ProvisioningApiHandler) RouteGetAlertRuleTemplateExport(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.handleRouteGetAlertRuleTemplateExport(ctx, uIDParam)
+}
+func (f *ProvisioningApiHandler) RouteGetAlertRuleTemplates(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetAlertRuleTemplates(ctx)
+}
 func (f *ProvisioningApiHandler) RouteGetAlertRules(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetAlertRules(ctx)
 }
@@ -121,6 +155,14 @@ func (f *ProvisioningApiHandler) RouteGetContactpoints(ctx *contextmodel.ReqCont
 func (f *ProvisioningApiHandler) RouteGetContactpointsExport(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetContactpointsExport(ctx)
 }
+func (f *ProvisioningApiHandler) RouteGetEscalationPolicies(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetEscalationPolicies(ctx)
+}
+func (f *ProvisioningApiHandler) RouteGetEscalationPolicy(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	nameParam := web.Params(ctx.Req)[":name"]
+	return f.handleRouteGetEscalationPolicy(ctx, nameParam)
+}
 func (f *ProvisioningApiHandler) RouteGetMuteTiming(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	nameParam := web.Params(ctx.Req)[":name"]
@@ -151,6 +193,14 @@ func (f *ProvisioningApiHandler) RoutePostAlertRule(ctx *contextmodel.ReqContext
 	}
 	return f.handleRoutePostAlertRule(ctx, conf)
 }
+func (f *ProvisioningApiHandler) RoutePostAlertRuleTemplate(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.ProvisionedAlertRuleTemplate{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostAlertRuleTemplate(ctx, conf)
+}
 func (f *ProvisioningApiHandler) RoutePostContactpoints(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Request Body
 	conf := apimodels.EmbeddedContactPoint{}
@@ -159,6 +209,14 @@ func (f *ProvisioningApiHandler) RoutePostContactpoints(ctx *contextmodel.ReqCon
 	}
 	return f.handleRoutePostContactpoints(ctx, conf)
 }
+func (f *ProvisioningApiHandler) RoutePostEscalationPolicy(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.ProvisionedEscalationPolicy{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostEscalationPolicy(ctx, conf)
+}
 func (f *ProvisioningApiHandler) RoutePostMuteTiming(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Request Body
 	conf := apimodels.MuteTimeInterval{}
@@ -188,6 +246,16 @@ func (f *ProvisioningApiHandler) RoutePutAlertRuleGroup(ctx *contextmodel.ReqCon
 	}
 	return f.handleRoutePutAlertRuleGroup(ctx, conf, folderUIDParam, groupParam)
 }
+func (f *ProvisioningApiHandler) RoutePutAlertRuleTemplate(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	// Parse Request Body
+	conf := apimodels.ProvisionedAlertRuleTemplate{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePutAlertRuleTemplate(ctx, conf, uIDParam)
+}
 func (f *ProvisioningApiHandler) RoutePutContactpoint(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	uIDParam := web.Params(ctx.Req)[":UID"]
@@ -198,6 +266,16 @@ func (f *ProvisioningApiHandler) RoutePutContactpoint(ctx *contextmodel.ReqConte
 	}
 	return f.handleRoutePutContactpoint(ctx, conf, uIDParam)
 }
+func (f *ProvisioningApiHandler) RoutePutEscalationPolicy(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	nameParam := web.Params(ctx.Req)[":name"]
+	// Parse Request Body
+	conf := apimodels.ProvisionedEscalationPolicy{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePutEscalationPolicy(ctx, conf, nameParam)
+}
 func (f *ProvisioningApiHandler) RoutePutMuteTiming(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	nameParam := web.Params(ctx.Req)[":name"]
@@ -256,6 +334,18 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Delete(
+			toMacaronPath("/api/v1/provisioning/alert-rule-templates/{UID}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodDelete, "/api/v1/provisioning/alert-rule-templates/{UID}"),
+			metrics.Instrument(
+				http.MethodDelete,
+				"/api/v1/provisioning/alert-rule-templates/{UID}",
+				api.Hooks.Wrap(srv.RouteDeleteAlertRuleTemplate),
+				m,
+			),
+		)
 		group.Delete(
 			toMacaronPath("/api/v1/provisioning/contact-points/{UID}"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -268,6 +358,18 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Delete(
+			toMacaronPath("/api/v1/provisioning/escalation-policies/{name}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodDelete, "/api/v1/provisioning/escalation-policies/{name}"),
+			metrics.Instrument(
+				http.MethodDelete,
+				"/api/v1/provisioning/escalation-policies/{name}",
+				api.Hooks.Wrap(srv.RouteDeleteEscalationPolicy),
+				m,
+			),
+		)
 		group.Delete(
 			toMacaronPath("/api/v1/provisioning/mute-timings/{name}"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -364,6 +466,42 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/alert-rule-templates/{UID}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/alert-rule-templates/{UID}"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/alert-rule-templates/{UID}",
+				api.Hooks.Wrap(srv.RouteGetAlertRuleTemplate),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/alert-rule-templates/{UID}/export"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/alert-rule-templates/{UID}/export"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/alert-rule-templates/{UID}/export",
+				api.Hooks.Wrap(srv.RouteGetAlertRuleTemplateExport),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/alert-rule-templates"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/alert-rule-templates"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/alert-rule-templates",
+				api.Hooks.Wrap(srv.RouteGetAlertRuleTemplates),
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/v1/provisioning/alert-rules"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -412,6 +550,30 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/escalation-policies"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/escalation-policies"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/escalation-policies",
+				api.Hooks.Wrap(srv.RouteGetEscalationPolicies),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/escalation-policies/{name}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/escalation-policies/{name}"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/escalation-policies/{name}",
+				api.Hooks.Wrap(srv.RouteGetEscalationPolicy),
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/v1/provisioning/mute-timings/{name}"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -496,6 +658,18 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/alert-rule-templates"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/alert-rule-templates"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/alert-rule-templates",
+				api.Hooks.Wrap(srv.RoutePostAlertRuleTemplate),
+				m,
+			),
+		)
 		group.Post(
 			toMacaronPath("/api/v1/provisioning/contact-points"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -508,6 +682,18 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/escalation-policies"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/escalation-policies"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/escalation-policies",
+				api.Hooks.Wrap(srv.RoutePostEscalationPolicy),
+				m,
+			),
+		)
 		group.Post(
 			toMacaronPath("/api/v1/provisioning/mute-timings"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -544,6 +730,18 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Put(
+			toMacaronPath("/api/v1/provisioning/alert-rule-templates/{UID}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPut, "/api/v1/provisioning/alert-rule-templates/{UID}"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/v1/provisioning/alert-rule-templates/{UID}",
+				api.Hooks.Wrap(srv.RoutePutAlertRuleTemplate),
+				m,
+			),
+		)
 		group.Put(
 			toMacaronPath("/api/v1/provisioning/contact-points/{UID}"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -556,6 +754,18 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Put(
+			toMacaronPath("/api/v1/provisioning/escalation-policies/{name}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPut, "/api/v1/provisioning/escalation-policies/{name}"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/v1/provisioning/escalation-policies/{name}",
+				api.Hooks.Wrap(srv.RoutePutEscalationPolicy),
+				m,
+			),
+		)
 		group.Put(
 			toMacaronPath("/api/v1/provisioning/mute-timings/{name}"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
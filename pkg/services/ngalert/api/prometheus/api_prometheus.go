@@ -21,6 +21,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/log"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/folder"
+	apicompat "github.com/grafana/grafana/pkg/services/ngalert/api/compat"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
@@ -120,7 +121,7 @@ func PrepareAlertStatuses(manager state.AlertInstanceManager, opts AlertStatuses
 		startsAt := alertState.StartsAt
 		valString := ""
 
-		if alertState.State == eval.Alerting || alertState.State == eval.Pending || alertState.State == eval.Recovering {
+		if alertState.State == eval.Alerting || alertState.State == eval.Pending || alertState.State == eval.Recovering || alertState.State == eval.Suppressed {
 			valString = FormatValues(alertState)
 		}
 
@@ -130,15 +131,25 @@ func PrepareAlertStatuses(manager state.AlertInstanceManager, opts AlertStatuses
 
 			// TODO: or should we make this two fields? Using one field lets the
 			// frontend use the same logic for parsing text on annotations and this.
-			State:    state.FormatStateAndReason(alertState.State, alertState.StateReason),
-			ActiveAt: &startsAt,
-			Value:    valString,
+			State:           state.FormatStateAndReason(alertState.State, alertState.StateReason),
+			ActiveAt:        &startsAt,
+			Value:           valString,
+			Fingerprint:     formatFingerprint(alertState),
+			Acknowledgement: apicompat.GettableAlertInstanceAcknowledgementFromAlertInstanceAcknowledgement(alertState.Acknowledgement),
 		})
 	}
 
 	return alertResponse
 }
 
+// formatFingerprint returns the fingerprint of the labels of the state, or an empty string if the state has no labels fingerprint.
+func formatFingerprint(alertState *state.State) string {
+	if alertState.CacheID == 0 {
+		return ""
+	}
+	return alertState.CacheID.String()
+}
+
 func FormatValues(alertState *state.State) string {
 	var fv string
 	values := alertState.GetLastEvaluationValuesForCondition()
@@ -207,6 +218,8 @@ func getStatesFromQuery(v url.Values) ([]eval.State, error) {
 			states = append(states, eval.Error)
 		case "recovering":
 			states = append(states, eval.Recovering)
+		case "suppressed":
+			states = append(states, eval.Suppressed)
 		default:
 			return states, fmt.Errorf("unknown state '%s'", s)
 		}
@@ -296,7 +309,7 @@ func RuleAlertStateMutatorGenerator(manager state.AlertInstanceManager) RuleAler
 		for _, alertState := range states {
 			activeAt := alertState.StartsAt
 			valString := ""
-			if alertState.State == eval.Alerting || alertState.State == eval.Pending || alertState.State == eval.Recovering {
+			if alertState.State == eval.Alerting || alertState.State == eval.Pending || alertState.State == eval.Recovering || alertState.State == eval.Suppressed {
 				valString = FormatValues(alertState)
 			}
 			stateKey := strings.ToLower(alertState.State.String())
@@ -311,13 +324,15 @@ func RuleAlertStateMutatorGenerator(manager state.AlertInstanceManager) RuleAler
 
 				// TODO: or should we make this two fields? Using one field lets the
 				// frontend use the same logic for parsing text on annotations and this.
-				State:    state.FormatStateAndReason(alertState.State, alertState.StateReason),
-				ActiveAt: &activeAt,
-				Value:    valString,
+				State:           state.FormatStateAndReason(alertState.State, alertState.StateReason),
+				ActiveAt:        &activeAt,
+				Value:           valString,
+				Fingerprint:     formatFingerprint(alertState),
+				Acknowledgement: apicompat.GettableAlertInstanceAcknowledgementFromAlertInstanceAcknowledgement(alertState.Acknowledgement),
 			}
 
 			// Set the state of the rule based on the state of its alerts.
-			// Only update the rule state with 'pending' or 'recovering' if the current state is 'inactive'.
+			// Only update the rule state with 'pending', 'recovering' or 'suppressed' if the current state is 'inactive'.
 			// This prevents overwriting a higher-severity 'firing' state in the case of a rule with multiple alerts.
 			switch alertState.State {
 			case eval.Normal:
@@ -329,6 +344,10 @@ func RuleAlertStateMutatorGenerator(manager state.AlertInstanceManager) RuleAler
 				if toMutate.State == "inactive" {
 					toMutate.State = "recovering"
 				}
+			case eval.Suppressed:
+				if toMutate.State == "inactive" {
+					toMutate.State = "suppressed"
+				}
 			case eval.Alerting:
 				if toMutate.ActiveAt == nil || toMutate.ActiveAt.After(activeAt) {
 					toMutate.ActiveAt = &activeAt
@@ -599,6 +618,8 @@ func filterRules(ruleGroup *apimodels.RuleGroup, withStatesFast map[eval.State]s
 			state = util.Pointer(eval.Pending)
 		case "recovering":
 			state = util.Pointer(eval.Recovering)
+		case "suppressed":
+			state = util.Pointer(eval.Suppressed)
 		}
 		if state != nil {
 			if _, ok := withStatesFast[*state]; ok {
@@ -655,6 +676,7 @@ func toRuleGroup(log log.Logger, groupKey ngmodels.AlertRuleGroupKey, folderFull
 		if len(rule.NotificationSettings) > 0 {
 			alertingRule.NotificationSettings = (*apimodels.AlertRuleNotificationSettings)(&rule.NotificationSettings[0])
 		}
+		alertingRule.Dependencies = apicompat.ApiDependenciesFromModelDependencies(rule.Dependencies)
 
 		// mutate rule for alert states
 		totals, totalsFiltered := ruleAlertStateMutator(rule, &alertingRule, stateFilterSet, matchers, labelOptions)
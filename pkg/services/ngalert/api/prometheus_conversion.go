@@ -121,6 +121,44 @@ func (f *ConvertPrometheusApiHandler) handleRouteConvertPrometheusPostRuleGroups
 	return f.svc.RouteConvertPrometheusPostRuleGroups(ctx, promNamespaces)
 }
 
+func (f *ConvertPrometheusApiHandler) handleRouteConvertPrometheusPostAlertmanagerConfig(ctx *contextmodel.ReqContext) response.Response {
+	body, err := io.ReadAll(ctx.Req.Body)
+	if err != nil {
+		return errorToResponse(err)
+	}
+	defer func() { _ = ctx.Req.Body.Close() }()
+
+	var m string
+
+	// Parse content-type only if it's not empty,
+	// otherwise we'll assume it's yaml
+	contentType := ctx.Req.Header.Get("content-type")
+	if contentType != "" {
+		m, _, err = mime.ParseMediaType(contentType)
+		if err != nil {
+			return errorToResponse(err)
+		}
+	}
+
+	var promConfig apimodels.PrometheusAlertmanagerConfig
+
+	switch m {
+	case "application/yaml", "":
+		// mimirtool does not send content-type, so if it's empty, we assume it's yaml
+		if err := yaml.Unmarshal(body, &promConfig); err != nil {
+			return errorToResponse(err)
+		}
+	case "application/json":
+		if err := json.Unmarshal(body, &promConfig); err != nil {
+			return errorToResponse(err)
+		}
+	default:
+		return errorToResponse(errorUnsupportedMediaType.Errorf("unsupported media type: %s, only application/yaml and application/json are supported", m))
+	}
+
+	return f.svc.RouteConvertPrometheusPostAlertmanagerConfig(ctx, promConfig)
+}
+
 // cortextool
 func (f *ConvertPrometheusApiHandler) handleRouteConvertPrometheusCortexGetRules(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteConvertPrometheusGetRules(ctx)
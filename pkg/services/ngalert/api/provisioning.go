@@ -64,6 +64,26 @@ func (f *ProvisioningApiHandler) handleRouteDeleteTemplate(ctx *contextmodel.Req
 	return f.svc.RouteDeleteTemplate(ctx, name)
 }
 
+func (f *ProvisioningApiHandler) handleRouteGetEscalationPolicies(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetEscalationPolicies(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetEscalationPolicy(ctx *contextmodel.ReqContext, name string) response.Response {
+	return f.svc.RouteGetEscalationPolicy(ctx, name)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostEscalationPolicy(ctx *contextmodel.ReqContext, p apimodels.ProvisionedEscalationPolicy) response.Response {
+	return f.svc.RoutePostEscalationPolicy(ctx, p)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePutEscalationPolicy(ctx *contextmodel.ReqContext, p apimodels.ProvisionedEscalationPolicy, name string) response.Response {
+	return f.svc.RoutePutEscalationPolicy(ctx, p, name)
+}
+
+func (f *ProvisioningApiHandler) handleRouteDeleteEscalationPolicy(ctx *contextmodel.ReqContext, name string) response.Response {
+	return f.svc.RouteDeleteEscalationPolicy(ctx, name)
+}
+
 func (f *ProvisioningApiHandler) handleRouteGetMuteTiming(ctx *contextmodel.ReqContext, name string) response.Response {
 	return f.svc.RouteGetMuteTiming(ctx, name)
 }
@@ -139,3 +159,27 @@ func (f *ProvisioningApiHandler) handleRouteExportMuteTimings(ctx *contextmodel.
 func (f *ProvisioningApiHandler) handleRouteDeleteAlertRuleGroup(ctx *contextmodel.ReqContext, folderUID, group string) response.Response {
 	return f.svc.RouteDeleteAlertRuleGroup(ctx, folderUID, group)
 }
+
+func (f *ProvisioningApiHandler) handleRouteGetAlertRuleTemplates(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetAlertRuleTemplates(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetAlertRuleTemplate(ctx *contextmodel.ReqContext, UID string) response.Response {
+	return f.svc.RouteGetAlertRuleTemplate(ctx, UID)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetAlertRuleTemplateExport(ctx *contextmodel.ReqContext, UID string) response.Response {
+	return f.svc.RouteGetAlertRuleTemplateExport(ctx, UID)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostAlertRuleTemplate(ctx *contextmodel.ReqContext, t apimodels.ProvisionedAlertRuleTemplate) response.Response {
+	return f.svc.RoutePostAlertRuleTemplate(ctx, t)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePutAlertRuleTemplate(ctx *contextmodel.ReqContext, t apimodels.ProvisionedAlertRuleTemplate, UID string) response.Response {
+	return f.svc.RoutePutAlertRuleTemplate(ctx, t, UID)
+}
+
+func (f *ProvisioningApiHandler) handleRouteDeleteAlertRuleTemplate(ctx *contextmodel.ReqContext, UID string) response.Response {
+	return f.svc.RouteDeleteAlertRuleTemplate(ctx, UID)
+}
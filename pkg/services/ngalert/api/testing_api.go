@@ -32,3 +32,7 @@ func (f *TestingApiHandler) handleRouteEvalQueries(c *contextmodel.ReqContext, b
 func (f *TestingApiHandler) handleBacktestConfig(ctx *contextmodel.ReqContext, conf apimodels.BacktestConfig) response.Response {
 	return f.svc.BacktestAlertRule(ctx, conf)
 }
+
+func (f *TestingApiHandler) handleRouteLintGrafanaRuleGroup(ctx *contextmodel.ReqContext, conf apimodels.PostableRuleGroupConfig) response.Response {
+	return f.svc.RouteLintGrafanaRuleGroup(ctx, conf)
+}
@@ -0,0 +1,110 @@
+package definitions
+
+import (
+	"time"
+)
+
+// swagger:route GET /v1/ngalert/admin/state/export configuration RouteExportAlertingState
+//
+// Exports the state of the alert instances, the silences and the notification log of the organizations to a portable archive that can be imported into another Grafana instance. Only Grafana server administrators can export the state.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: AlertStateArchive
+//       403: ForbiddenError
+//       500: Failure
+
+// swagger:route POST /v1/ngalert/admin/state/import configuration RouteImportAlertingState
+//
+// Imports an archive exported by another Grafana instance. The state of the alert instances of the rules in the archive, and the silences and notification log of the organizations in the archive, replace the current ones. Only Grafana server administrators can import the state.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: AlertStateImportResult
+//       400: ValidationError
+//       403: ForbiddenError
+//       500: Failure
+
+// swagger:parameters RouteExportAlertingState
+type ExportAlertingStateParams struct {
+	// IDs of the organizations to export. If empty, all organizations are exported.
+	// in:query
+	// required:false
+	OrgID []int64 `json:"orgId"`
+}
+
+// swagger:parameters RouteImportAlertingState
+type ImportAlertingStateParams struct {
+	// in:body
+	Body PostableAlertStateImport
+}
+
+// AlertStateArchiveVersion is the version of the format of AlertStateArchive.
+const AlertStateArchiveVersion = 1
+
+// AlertStateArchive is the state of alerting of one or more organizations that can be moved to another Grafana instance.
+// swagger:model
+type AlertStateArchive struct {
+	// Version of the format of the archive.
+	Version int `json:"version"`
+	// ExportedAt is the time when the archive was created.
+	ExportedAt time.Time              `json:"exportedAt"`
+	Orgs       []AlertStateArchiveOrg `json:"orgs"`
+}
+
+// swagger:model
+type AlertStateArchiveOrg struct {
+	OrgID     int64                       `json:"orgId"`
+	Instances []AlertStateArchiveInstance `json:"instances"`
+	// Silences is the state of the silences of the Grafana Alertmanager of the organization, in its binary format.
+	Silences []byte `json:"silences,omitempty"`
+	// NotificationLog is the notification log of the Grafana Alertmanager of the organization, in its binary format.
+	NotificationLog []byte `json:"notificationLog,omitempty"`
+}
+
+// swagger:model
+type AlertStateArchiveInstance struct {
+	RuleUID           string                                `json:"ruleUid"`
+	Labels            map[string]string                     `json:"labels"`
+	State             string                                `json:"state"`
+	StateReason       string                                `json:"stateReason,omitempty"`
+	StartsAt          time.Time                             `json:"startsAt"`
+	EndsAt            time.Time                             `json:"endsAt"`
+	LastEvaluatedAt   time.Time                             `json:"lastEvaluatedAt"`
+	LastSentAt        *time.Time                            `json:"lastSentAt,omitempty"`
+	FiredAt           *time.Time                            `json:"firedAt,omitempty"`
+	ResolvedAt        *time.Time                            `json:"resolvedAt,omitempty"`
+	ResultFingerprint string                                `json:"resultFingerprint,omitempty"`
+	Acknowledgement   *GettableAlertInstanceAcknowledgement `json:"acknowledgement,omitempty"`
+}
+
+// swagger:model
+type PostableAlertStateImport struct {
+	Archive AlertStateArchive `json:"archive"`
+	// OrgIDs maps the IDs of the organizations in the archive to the IDs of the organizations in this instance.
+	// Organizations that are not in the map are imported into the organization with the same ID.
+	OrgIDs map[int64]int64 `json:"orgIds,omitempty"`
+	// RuleUIDs maps the UIDs of the alert rules in the archive to the UIDs of the alert rules in this instance.
+	// Alert rules that are not in the map are matched by their UID.
+	RuleUIDs map[string]string `json:"ruleUids,omitempty"`
+}
+
+// swagger:model
+type AlertStateImportResult struct {
+	// Orgs is the number of organizations that were imported.
+	Orgs int `json:"orgs"`
+	// Rules is the number of alert rules whose state was imported.
+	Rules int `json:"rules"`
+	// Instances is the number of alert instances that were imported.
+	Instances int `json:"instances"`
+	// SkippedInstances is the number of alert instances that were not imported because their organization or
+	// alert rule does not exist in this instance.
+	SkippedInstances int `json:"skippedInstances"`
+}
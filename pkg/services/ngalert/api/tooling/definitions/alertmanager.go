@@ -182,6 +182,19 @@ import (
 //       403: PermissionDenied
 //       409: AlertManagerNotReady
 
+// swagger:route POST /alertmanager/grafana/config/api/v1/routing/test alertmanager RoutePostTestGrafanaRouting
+//
+// Test how an alert is routed by the notification policy tree of the Grafana Alertmanager.
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//
+//       200: TestRoutingResult
+//       400: ValidationError
+//       403: PermissionDenied
+//       404: NotFound
+
 // swagger:route GET /alertmanager/grafana/api/v2/silences alertmanager RouteGetGrafanaSilences
 //
 // get silences
@@ -391,6 +404,78 @@ const (
 	AlertScope  TemplateScope = ".Alert"
 )
 
+// swagger:parameters RoutePostTestGrafanaRouting
+type TestRoutingConfigParams struct {
+	// in:body
+	Body TestRoutingConfigBodyParams
+}
+
+type TestRoutingConfigBodyParams struct {
+	// Labels of the alert to route.
+	Labels model.LabelSet `json:"labels,omitempty"`
+
+	// UID of an alert rule. If set, the alert has the labels of the rule, including the labels that are added
+	// at the time of the notification, and Labels override them.
+	RuleUID string `json:"rule_uid,omitempty"`
+
+	// Time at which the time intervals and silences are checked. Defaults to the current time.
+	Time *time.Time `json:"time,omitempty"`
+
+	// Configuration to test instead of the current configuration of the Alertmanager.
+	Config *PostableUserConfig `json:"config,omitempty"`
+}
+
+// swagger:model
+type TestRoutingResult struct {
+	// Labels of the alert that was routed.
+	Labels model.LabelSet `json:"labels"`
+
+	// Time at which the time intervals and silences were checked.
+	Time time.Time `json:"time"`
+
+	// Routes that the alert matches, in the order in which they are notified.
+	Routes []TestRoutingRoute `json:"routes"`
+
+	// Silences that would silence the alert at the time.
+	// The Grafana Alertmanager does not support inhibition rules, so silences are the only way an alert is suppressed.
+	Silences GettableGrafanaSilences `json:"silences"`
+
+	// Silenced is true if at least one silence applies to the alert.
+	Silenced bool `json:"silenced"`
+}
+
+type TestRoutingRoute struct {
+	// Path of the matched route from the root of the notification policy tree. The last element is the matched route.
+	Path []TestRoutingRouteStep `json:"path"`
+
+	// Effective options of the matched route, which include the options inherited from its parents.
+	Receiver       string         `json:"receiver"`
+	GroupBy        []string       `json:"group_by"`
+	GroupWait      model.Duration `json:"group_wait"`
+	GroupInterval  model.Duration `json:"group_interval"`
+	RepeatInterval model.Duration `json:"repeat_interval"`
+
+	MuteTimeIntervals   []string `json:"mute_time_intervals,omitempty"`
+	ActiveTimeIntervals []string `json:"active_time_intervals,omitempty"`
+
+	// Muted is true if the notifications of the route are muted at the time.
+	Muted bool `json:"muted"`
+
+	// MutedBy are the mute time intervals that contain the time or, if the time is outside all active time intervals, the active time intervals.
+	MutedBy []string `json:"muted_by,omitempty"`
+
+	// EscalationPolicy is the escalation policy of the receiver of the route, if any.
+	EscalationPolicy *EscalationPolicy `json:"escalation_policy,omitempty"`
+}
+
+type TestRoutingRouteStep struct {
+	// Index of the route in the routes of its parent. It is -1 for the root route.
+	Index    int      `json:"index"`
+	Receiver string   `json:"receiver"`
+	Matchers []string `json:"matchers,omitempty"`
+	Continue bool     `json:"continue"`
+}
+
 // swagger:parameters RouteCreateSilence RouteCreateGrafanaSilence
 type CreateSilenceParams struct {
 	// in:body
@@ -641,7 +726,11 @@ type DatasourceUIDReference struct {
 type PostableUserConfig struct {
 	TemplateFiles      map[string]string         `yaml:"template_files" json:"template_files"`
 	AlertmanagerConfig PostableApiAlertingConfig `yaml:"alertmanager_config" json:"alertmanager_config"`
-	amSimple           map[string]interface{}    `yaml:"-" json:"-"`
+	// Escalation policies of the contact points. They are supported only by the Grafana Alertmanager.
+	EscalationPolicies []EscalationPolicy `yaml:"escalation_policies,omitempty" json:"escalation_policies,omitempty"`
+	// Prometheus Alertmanager configurations imported into the Grafana Alertmanager configuration.
+	ImportedConfigs []ImportedAlertmanagerConfig `yaml:"imported_configs,omitempty" json:"imported_configs,omitempty"`
+	amSimple        map[string]interface{}       `yaml:"-" json:"-"`
 }
 
 func (c *PostableUserConfig) UnmarshalJSON(b []byte) error {
@@ -741,9 +830,11 @@ func (c *PostableUserConfig) UnmarshalYAML(value *yaml.Node) error {
 
 // swagger:model
 type GettableUserConfig struct {
-	TemplateFiles           map[string]string         `yaml:"template_files" json:"template_files"`
-	TemplateFileProvenances map[string]Provenance     `yaml:"template_file_provenances,omitempty" json:"template_file_provenances,omitempty"`
-	AlertmanagerConfig      GettableApiAlertingConfig `yaml:"alertmanager_config" json:"alertmanager_config"`
+	TemplateFiles           map[string]string            `yaml:"template_files" json:"template_files"`
+	TemplateFileProvenances map[string]Provenance        `yaml:"template_file_provenances,omitempty" json:"template_file_provenances,omitempty"`
+	AlertmanagerConfig      GettableApiAlertingConfig    `yaml:"alertmanager_config" json:"alertmanager_config"`
+	EscalationPolicies      []EscalationPolicy           `yaml:"escalation_policies,omitempty" json:"escalation_policies,omitempty"`
+	ImportedConfigs         []ImportedAlertmanagerConfig `yaml:"imported_configs,omitempty" json:"imported_configs,omitempty"`
 
 	// amSimple stores a map[string]interface of the decoded alertmanager config.
 	// This enables circumventing the underlying alertmanager secret type
@@ -778,13 +869,17 @@ func (c *GettableUserConfig) UnmarshalYAML(value *yaml.Node) error {
 
 func (c *GettableUserConfig) MarshalJSON() ([]byte, error) {
 	type plain struct {
-		TemplateFiles      map[string]string      `yaml:"template_files" json:"template_files"`
-		AlertmanagerConfig map[string]interface{} `yaml:"alertmanager_config" json:"alertmanager_config"`
+		TemplateFiles      map[string]string            `yaml:"template_files" json:"template_files"`
+		AlertmanagerConfig map[string]interface{}       `yaml:"alertmanager_config" json:"alertmanager_config"`
+		EscalationPolicies []EscalationPolicy           `yaml:"escalation_policies,omitempty" json:"escalation_policies,omitempty"`
+		ImportedConfigs    []ImportedAlertmanagerConfig `yaml:"imported_configs,omitempty" json:"imported_configs,omitempty"`
 	}
 
 	tmp := plain{
 		TemplateFiles:      c.TemplateFiles,
 		AlertmanagerConfig: c.amSimple,
+		EscalationPolicies: c.EscalationPolicies,
+		ImportedConfigs:    c.ImportedConfigs,
 	}
 
 	return json.Marshal(tmp)
@@ -0,0 +1,79 @@
+package definitions
+
+import (
+	"time"
+)
+
+// swagger:route GET /alertmanager/grafana/api/v1/deliveries alertmanager RouteGetGrafanaNotificationDeliveries
+//
+// get the attempts of the integrations of the Grafana Alertmanager to send notifications, the most recent first
+//
+//     Responses:
+//       200: GettableNotificationDeliveries
+//       400: ValidationError
+//       403: PermissionDenied
+//       501: PublicError
+
+// swagger:parameters RouteGetGrafanaNotificationDeliveries
+type NotificationDeliveriesParams struct {
+	// Only the deliveries of alert groups with an alert of this rule are returned.
+	// in:query
+	// required:false
+	RuleUID string `json:"ruleUID"`
+	// Only the deliveries of alert groups with this alert are returned. It is the fingerprint of the alert instance
+	// in the state history, so the deliveries after a state transition can be found with it and the time of the transition.
+	// in:query
+	// required:false
+	Fingerprint string `json:"fingerprint"`
+	// in:query
+	// required:false
+	Receiver string `json:"receiver"`
+	// Start of the time range, in seconds since the epoch. Defaults to 6 hours before the end of the range.
+	// in:query
+	// required:false
+	From int64 `json:"from"`
+	// End of the time range, in seconds since the epoch. Defaults to now.
+	// in:query
+	// required:false
+	To int64 `json:"to"`
+	// Maximum number of deliveries. Defaults to 1000.
+	// in:query
+	// required:false
+	Limit int `json:"limit"`
+}
+
+// swagger:model
+type GettableNotificationDeliveries []GettableNotificationDelivery
+
+// GettableNotificationDelivery is an attempt of an integration to send the notification of an alert group.
+// swagger:model
+type GettableNotificationDelivery struct {
+	Receiver string `json:"receiver"`
+	// Type of the integration.
+	// example: email
+	Integration string `json:"integration"`
+	// Position of the integration in the receiver.
+	IntegrationIndex int `json:"integrationIndex"`
+	// Key of the alert group in the Alertmanager, made of the route and the labels of the alert group.
+	// example: {}:{alertname="HighLatency"}
+	GroupKey string `json:"groupKey"`
+	// Number of the attempt of the integration to send the notification, starting at 1. The notification is retried until it succeeds.
+	Attempt int `json:"attempt"`
+	// enum: success,failed
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	// Duration of the attempt in milliseconds.
+	DurationMs int64     `json:"durationMs"`
+	Time       time.Time `json:"time"`
+	// Alerts of the notification.
+	Alerts []GettableNotificationDeliveryAlert `json:"alerts"`
+}
+
+// swagger:model
+type GettableNotificationDeliveryAlert struct {
+	RuleUID string `json:"ruleUID,omitempty"`
+	// Fingerprint of the alert instance in the state history.
+	Fingerprint string    `json:"fingerprint"`
+	Status      string    `json:"status"`
+	StartsAt    time.Time `json:"startsAt"`
+}
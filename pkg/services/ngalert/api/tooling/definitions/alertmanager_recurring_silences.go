@@ -0,0 +1,116 @@
+package definitions
+
+import (
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/prometheus/common/model"
+)
+
+// swagger:route GET /alertmanager/grafana/api/v1/recurring-silences alertmanager RouteGetGrafanaRecurringSilences
+//
+// get recurring silences
+//
+//     Responses:
+//       200: GettableRecurringSilences
+//       403: PermissionDenied
+
+// swagger:route POST /alertmanager/grafana/api/v1/recurring-silences alertmanager RouteCreateGrafanaRecurringSilence
+//
+// create recurring silence
+//
+//     Responses:
+//       201: GettableRecurringSilence
+//       400: ValidationError
+//       403: PermissionDenied
+//       409: PublicError
+
+// swagger:route GET /alertmanager/grafana/api/v1/recurring-silences/{UID} alertmanager RouteGetGrafanaRecurringSilence
+//
+// get recurring silence
+//
+//     Responses:
+//       200: GettableRecurringSilence
+//       403: PermissionDenied
+//       404: NotFound
+
+// swagger:route PUT /alertmanager/grafana/api/v1/recurring-silences/{UID} alertmanager RouteUpdateGrafanaRecurringSilence
+//
+// update recurring silence
+//
+//     Responses:
+//       200: GettableRecurringSilence
+//       400: ValidationError
+//       403: PermissionDenied
+//       404: NotFound
+//       409: PublicError
+
+// swagger:route DELETE /alertmanager/grafana/api/v1/recurring-silences/{UID} alertmanager RouteDeleteGrafanaRecurringSilence
+//
+// delete recurring silence
+//
+//     Responses:
+//       204: description: The recurring silence was deleted successfully.
+//       403: PermissionDenied
+//       404: NotFound
+//       409: PublicError
+
+// swagger:parameters RouteGetGrafanaRecurringSilence RouteUpdateGrafanaRecurringSilence RouteDeleteGrafanaRecurringSilence
+type RecurringSilenceUIDReference struct {
+	// Recurring silence UID
+	// in:path
+	UID string
+}
+
+// swagger:parameters RouteCreateGrafanaRecurringSilence RouteUpdateGrafanaRecurringSilence
+type RecurringSilencePayload struct {
+	// in:body
+	Body PostableRecurringSilence
+}
+
+// swagger:model
+type GettableRecurringSilences []GettableRecurringSilence
+
+// PostableRecurringSilence is a silence that is in effect in the windows of a schedule.
+// The silences of the windows are created ahead of time by Grafana.
+// swagger:model
+type PostableRecurringSilence struct {
+	// required: false
+	// minLength: 1
+	// maxLength: 40
+	// pattern: ^[a-zA-Z0-9-_]+$
+	UID string `json:"uid"`
+	// required: true
+	Matchers amv2.Matchers `json:"matchers"`
+	// required: true
+	// example: Weekly maintenance of cluster eu-1
+	Comment string `json:"comment"`
+	// Author of the silences. Defaults to the login of the user.
+	// required: false
+	CreatedBy string `json:"createdBy"`
+	// required: true
+	Schedule RecurringSilenceSchedule `json:"schedule"`
+}
+
+// swagger:model
+type GettableRecurringSilence struct {
+	PostableRecurringSilence `json:",inline"`
+	Version                  int64      `json:"version"`
+	Provenance               Provenance `json:"provenance,omitempty"`
+}
+
+// RecurringSilenceSchedule defines the windows of a recurring silence, either with a cron expression and a duration,
+// or with time intervals.
+// swagger:model
+type RecurringSilenceSchedule struct {
+	// Cron expression of the start of the windows, in the standard five-field format.
+	// example: 0 2 * * 0
+	Cron string `json:"cron,omitempty" yaml:"cron,omitempty"`
+	// Duration of the windows that start at the times of the cron expression. At most one week.
+	// example: 2h
+	Duration model.Duration `json:"duration,omitempty" yaml:"duration,omitempty"`
+	// Time intervals in which the silence is in effect. Cannot be used with a cron expression.
+	TimeIntervals []timeinterval.TimeInterval `json:"time_intervals,omitempty" yaml:"time_intervals,omitempty"`
+	// Timezone of the schedule. Defaults to UTC.
+	// example: Europe/Berlin
+	Timezone string `json:"timezone,omitempty" yaml:"timezone,omitempty"`
+}
@@ -55,3 +55,62 @@ func (mt *MuteTimeInterval) Validate() error {
 	}
 	return nil
 }
+
+// Validate checks that the escalation policy has a name, a receiver, and steps with increasing delays.
+func (p *EscalationPolicy) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("escalation policy must have a name")
+	}
+	if p.Receiver == "" {
+		return fmt.Errorf("escalation policy must have a receiver")
+	}
+	if len(p.Steps) == 0 {
+		return fmt.Errorf("escalation policy must have at least one step")
+	}
+	for i, step := range p.Steps {
+		if step.Receiver == "" {
+			return fmt.Errorf("step %d must have a receiver", i+1)
+		}
+		if step.After <= 0 {
+			return fmt.Errorf("step %d must have a positive delay", i+1)
+		}
+		if i > 0 && step.After <= p.Steps[i-1].After {
+			return fmt.Errorf("delay of step %d must be greater than the delay of step %d", i+1, i)
+		}
+	}
+	return nil
+}
+
+// ValidateEscalationPolicies checks that the escalation policies are valid, that their names and receivers are unique,
+// and that all the receivers they refer to exist in the configuration.
+func (c *PostableUserConfig) ValidateEscalationPolicies() error {
+	receivers := make(map[string]struct{}, len(c.AlertmanagerConfig.Receivers))
+	for _, r := range c.AlertmanagerConfig.Receivers {
+		receivers[r.Name] = struct{}{}
+	}
+	names := make(map[string]struct{}, len(c.EscalationPolicies))
+	escalated := make(map[string]string, len(c.EscalationPolicies))
+	for i := range c.EscalationPolicies {
+		p := &c.EscalationPolicies[i]
+		if err := p.Validate(); err != nil {
+			return fmt.Errorf("invalid escalation policy %q: %w", p.Name, err)
+		}
+		if _, ok := names[p.Name]; ok {
+			return fmt.Errorf("escalation policy %q is defined more than once", p.Name)
+		}
+		names[p.Name] = struct{}{}
+		if other, ok := escalated[p.Receiver]; ok {
+			return fmt.Errorf("receiver %q is escalated by both %q and %q escalation policies", p.Receiver, other, p.Name)
+		}
+		escalated[p.Receiver] = p.Name
+		if _, ok := receivers[p.Receiver]; !ok {
+			return fmt.Errorf("escalation policy %q refers to receiver %q that does not exist", p.Name, p.Receiver)
+		}
+		for _, step := range p.Steps {
+			if _, ok := receivers[step.Receiver]; !ok {
+				return fmt.Errorf("escalation policy %q refers to receiver %q that does not exist", p.Name, step.Receiver)
+			}
+		}
+	}
+	return nil
+}
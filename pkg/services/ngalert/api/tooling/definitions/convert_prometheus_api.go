@@ -200,6 +200,29 @@ import (
 //       202: ConvertPrometheusResponse
 //       403: ForbiddenError
 
+// swagger:route POST /convert/api/v1/alerts convert_prometheus RouteConvertPrometheusPostAlertmanagerConfig
+//
+// Converts a Prometheus Alertmanager configuration and merges it into the Grafana Alertmanager configuration.
+// The imported routing tree is added under a route with the merge matchers, and the names of the imported receivers,
+// time intervals and templates are prefixed with the configuration identifier.
+// Objects imported previously with the same identifier are replaced.
+// Integrations and settings that are not supported by the Grafana Alertmanager are skipped and reported in the response.
+//
+//     Consumes:
+//     - application/json
+//     - application/yaml
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       202: ConvertPrometheusAlertmanagerConfigResponse
+//       400: ValidationError
+//       403: ForbiddenError
+//
+//     Extensions:
+//       x-raw-request: true
+
 // swagger:parameters RouteConvertPrometheusPostRuleGroup RouteConvertPrometheusCortexPostRuleGroup
 type RouteConvertPrometheusPostRuleGroupParams struct {
 	// in: path
@@ -267,3 +290,75 @@ type ConvertPrometheusResponse struct {
 	ErrorType string `json:"errorType"`
 	Error     string `json:"error"`
 }
+
+// swagger:parameters RouteConvertPrometheusPostAlertmanagerConfig
+type RouteConvertPrometheusPostAlertmanagerConfigParams struct {
+	// Identifier of the imported configuration. It must be 1 to 40 letters, digits, dashes or underscores.
+	// in: header
+	ConfigIdentifier string `json:"x-grafana-alerting-config-identifier"`
+	// Matchers of the route under which the imported routing tree is merged, for example `source="prometheus"`.
+	// in: header
+	MergeMatchers string `json:"x-grafana-alerting-merge-matchers"`
+	// If true, the configuration is converted and the changes are reported, but it is not saved.
+	// in: header
+	DryRun bool `json:"x-grafana-alerting-dry-run"`
+	// in:body
+	Body PrometheusAlertmanagerConfig
+}
+
+// PrometheusAlertmanagerConfig is a Prometheus Alertmanager configuration in the format used by mimirtool.
+// swagger:model
+type PrometheusAlertmanagerConfig struct {
+	// The Alertmanager configuration file, in YAML.
+	AlertmanagerConfig string `yaml:"alertmanager_config" json:"alertmanager_config"`
+	// The contents of the template files by name.
+	TemplateFiles map[string]string `yaml:"template_files" json:"template_files"`
+}
+
+// swagger:model
+type ConvertPrometheusAlertmanagerConfigResponse struct {
+	Status string `json:"status"`
+	// True if the configuration was not saved.
+	DryRun bool `json:"dryRun"`
+	// The changes of the Grafana Alertmanager configuration.
+	Diff ConvertPrometheusAlertmanagerConfigDiff `json:"diff"`
+	// The parts of the configuration that were not imported.
+	Unsupported []UnsupportedAlertmanagerFeature `json:"unsupported"`
+}
+
+type ConvertPrometheusAlertmanagerConfigDiff struct {
+	Routes        ConvertPrometheusResourceDiff `json:"routes"`
+	Receivers     ConvertPrometheusResourceDiff `json:"receivers"`
+	TimeIntervals ConvertPrometheusResourceDiff `json:"timeIntervals"`
+	Templates     ConvertPrometheusResourceDiff `json:"templates"`
+}
+
+type ConvertPrometheusResourceDiff struct {
+	Added   []string `json:"added,omitempty"`
+	Updated []string `json:"updated,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+type UnsupportedAlertmanagerFeature struct {
+	// The kind of the feature: integration, inhibit_rule or setting.
+	Kind string `json:"kind"`
+	// The name of the receiver or of the inhibition rule.
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// ImportedAlertmanagerConfig records the objects that an import of a Prometheus Alertmanager configuration added to the
+// Grafana Alertmanager configuration. Only these objects are replaced by the next import with the same identifier.
+// swagger:model
+type ImportedAlertmanagerConfig struct {
+	// The identifier of the imported configuration.
+	Identifier string `yaml:"identifier" json:"identifier"`
+	// The matchers of the route under which the imported routing tree is merged.
+	MergeMatchers ObjectMatchers `yaml:"merge_matchers" json:"merge_matchers"`
+	// The names of the imported receivers.
+	Receivers []string `yaml:"receivers,omitempty" json:"receivers,omitempty"`
+	// The names of the imported time intervals.
+	TimeIntervals []string `yaml:"time_intervals,omitempty" json:"time_intervals,omitempty"`
+	// The names of the imported templates.
+	Templates []string `yaml:"templates,omitempty" json:"templates,omitempty"`
+}
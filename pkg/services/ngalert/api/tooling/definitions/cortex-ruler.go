@@ -576,6 +576,20 @@ type Record struct {
 	TargetDatasourceUID string `json:"target_datasource_uid,omitempty" yaml:"target_datasource_uid,omitempty"`
 }
 
+// swagger:model
+type AlertRuleDependency struct {
+	// UID of the alert rule this rule depends on. While an instance of that rule selected by the matchers is firing,
+	// the instances of this rule that would be pending or firing are suppressed.
+	// required: true
+	// example: cdbc9a5a-6d48-4c2a-b1a4-8a3b0a6d1f04
+	RuleUID string `json:"rule_uid" yaml:"rule_uid"`
+	// Matchers that select the instances of the parent rule, in the format of the matchers of silences. If empty,
+	// all instances of the parent rule are selected.
+	// required: false
+	// example: ["datacenter=\"eu-west-1\""]
+	Matchers []string `json:"matchers,omitempty" yaml:"matchers,omitempty"`
+}
+
 // swagger:model
 type PostableGrafanaRule struct {
 	Title                string                         `json:"title" yaml:"title"`
@@ -593,7 +607,8 @@ type PostableGrafanaRule struct {
 	// If set to 0, the value is reset to the default.
 	// required: false
 	// example: 3
-	MissingSeriesEvalsToResolve *int `json:"missing_series_evals_to_resolve,omitempty" yaml:"missing_series_evals_to_resolve,omitempty"`
+	MissingSeriesEvalsToResolve *int                  `json:"missing_series_evals_to_resolve,omitempty" yaml:"missing_series_evals_to_resolve,omitempty"`
+	Dependencies                []AlertRuleDependency `json:"dependencies,omitempty" yaml:"dependencies,omitempty"`
 }
 
 // swagger:model
@@ -617,6 +632,7 @@ type GettableGrafanaRule struct {
 	Metadata                    *AlertRuleMetadata             `json:"metadata,omitempty" yaml:"metadata,omitempty"`
 	GUID                        string                         `json:"guid" yaml:"guid"`
 	MissingSeriesEvalsToResolve *int                           `json:"missing_series_evals_to_resolve,omitempty" yaml:"missing_series_evals_to_resolve,omitempty"`
+	Dependencies                []AlertRuleDependency          `json:"dependencies,omitempty" yaml:"dependencies,omitempty"`
 }
 
 // UserInfo represents user-related information, including a unique identifier and a name.
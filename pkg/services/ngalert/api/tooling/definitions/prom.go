@@ -185,6 +185,7 @@ type Rule struct {
 	EvaluationTime       float64                        `json:"evaluationTime"`
 	IsPaused             bool                           `json:"isPaused"`
 	NotificationSettings *AlertRuleNotificationSettings `json:"notificationSettings,omitempty"`
+	Dependencies         []AlertRuleDependency          `json:"dependencies,omitempty"`
 }
 
 // Alert has info for an alert.
@@ -199,6 +200,10 @@ type Alert struct {
 	ActiveAt *time.Time `json:"activeAt"`
 	// required: true
 	Value string `json:"value"`
+	// Fingerprint of the labels of the alert instance. It identifies the instance in the acknowledgement API.
+	Fingerprint string `json:"fingerprint,omitempty"`
+	// Acknowledgement of the alert instance, if it is acknowledged.
+	Acknowledgement *GettableAlertInstanceAcknowledgement `json:"acknowledgement,omitempty"`
 }
 
 type StateByImportance int
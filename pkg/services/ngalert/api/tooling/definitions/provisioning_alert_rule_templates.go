@@ -0,0 +1,210 @@
+package definitions
+
+import (
+	"time"
+
+	"github.com/prometheus/common/model"
+)
+
+// swagger:route GET /v1/provisioning/alert-rule-templates provisioning stable RouteGetAlertRuleTemplates
+//
+// Get all the alert rule templates.
+//
+//     Responses:
+//       200: ProvisionedAlertRuleTemplates
+
+// swagger:route GET /v1/provisioning/alert-rule-templates/{UID} provisioning stable RouteGetAlertRuleTemplate
+//
+// Get a specific alert rule template by UID.
+//
+//     Responses:
+//       200: ProvisionedAlertRuleTemplate
+//       404: description: Not found.
+
+// swagger:route GET /v1/provisioning/alert-rule-templates/{UID}/export provisioning stable RouteGetAlertRuleTemplateExport
+//
+// Export an alert rule template in provisioning file format.
+//
+//     Produces:
+//     - application/json
+//     - application/yaml
+//     - application/terraform+hcl
+//     - text/yaml
+//     - text/hcl
+//
+//     Responses:
+//       200: AlertingFileExport
+//       404: description: Not found.
+
+// swagger:route POST /v1/provisioning/alert-rule-templates provisioning stable RoutePostAlertRuleTemplate
+//
+// Create a new alert rule template and the rule group expanded from it.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       201: ProvisionedAlertRuleTemplate
+//       400: ValidationError
+//       409: PublicError
+
+// swagger:route PUT /v1/provisioning/alert-rule-templates/{UID} provisioning stable RoutePutAlertRuleTemplate
+//
+// Update an existing alert rule template and the rules expanded from it.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: ProvisionedAlertRuleTemplate
+//       400: ValidationError
+//       409: PublicError
+
+// swagger:route DELETE /v1/provisioning/alert-rule-templates/{UID} provisioning stable RouteDeleteAlertRuleTemplate
+//
+// Delete a specific alert rule template and the rules expanded from it.
+//
+//     Responses:
+//       204: description: The alert rule template was deleted successfully.
+
+// swagger:parameters RouteGetAlertRuleTemplate RoutePutAlertRuleTemplate RouteDeleteAlertRuleTemplate RouteGetAlertRuleTemplateExport
+type AlertRuleTemplateUIDReference struct {
+	// Alert rule template UID
+	// in:path
+	UID string
+}
+
+// swagger:parameters RouteGetAlertRuleTemplateExport
+type AlertRuleTemplateExportParameters struct {
+	ExportQueryParams
+}
+
+// swagger:parameters RoutePostAlertRuleTemplate RoutePutAlertRuleTemplate
+type AlertRuleTemplatePayload struct {
+	// in:body
+	Body ProvisionedAlertRuleTemplate
+}
+
+// swagger:parameters RoutePostAlertRuleTemplate RoutePutAlertRuleTemplate RouteDeleteAlertRuleTemplate
+type AlertRuleTemplateHeaders struct {
+	// in:header
+	XDisableProvenance string `json:"X-Disable-Provenance"`
+}
+
+// swagger:model
+type ProvisionedAlertRuleTemplates []ProvisionedAlertRuleTemplate
+
+// ProvisionedAlertRuleTemplate is a parameterized alert rule that is expanded into one rule per instance.
+// The expanded rules form the rule group of the template.
+// swagger:model
+type ProvisionedAlertRuleTemplate struct {
+	// required: false
+	// minLength: 1
+	// maxLength: 40
+	// pattern: ^[a-zA-Z0-9-_]+$
+	UID string `json:"uid"`
+	// required: true
+	// example: project_x
+	FolderUID string `json:"folderUID"`
+	// required: true
+	// minLength: 1
+	// maxLength: 190
+	// example: latency
+	RuleGroup string `json:"ruleGroup"`
+	// Evaluation interval of the rule group in seconds.
+	// example: 60
+	Interval int64 `json:"interval"`
+	// Names of the parameters that can be used in the rule as ${name}.
+	// example: ["service", "threshold"]
+	Parameters []string `json:"parameters"`
+	// required: true
+	Rule AlertRuleTemplateRule `json:"rule"`
+	// required: true
+	Instances []AlertRuleTemplateInstance `json:"instances"`
+	// readonly: true
+	Version int64 `json:"version,omitempty"`
+	// readonly: true
+	Updated time.Time `json:"updated,omitempty"`
+	// readonly: true
+	Provenance Provenance `json:"provenance,omitempty"`
+}
+
+// AlertRuleTemplateRule is the rule of a template. Placeholders are replaced in the title, the query models,
+// the labels, the annotations and the metric of the recording rule.
+type AlertRuleTemplateRule struct {
+	// required: true
+	// example: High latency of ${service}
+	Title string `json:"title"`
+	// required: true
+	// example: A
+	Condition string `json:"condition"`
+	// required: true
+	Data []AlertQuery `json:"data"`
+	// required: true
+	NoDataState NoDataState `json:"noDataState"`
+	// required: true
+	ExecErrState ExecutionErrorState `json:"execErrState"`
+	// required: true
+	// swagger:strfmt duration
+	For model.Duration `json:"for"`
+	// required: false
+	// swagger:strfmt duration
+	KeepFiringFor model.Duration `json:"keep_firing_for"`
+	// example: {"summary": "Latency of ${service} is above ${threshold}s"}
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// example: {"service": "${service}"}
+	Labels map[string]string `json:"labels,omitempty"`
+	// example: false
+	IsPaused             bool                           `json:"isPaused"`
+	NotificationSettings *AlertRuleNotificationSettings `json:"notification_settings"`
+	Record               *Record                        `json:"record"`
+	// example: 2
+	MissingSeriesEvalsToResolve *int `json:"missingSeriesEvalsToResolve,omitempty"`
+}
+
+// AlertRuleTemplateInstance is a set of values of the parameters of a template. It becomes the rule with the same UID.
+type AlertRuleTemplateInstance struct {
+	// required: false
+	// pattern: ^[a-zA-Z0-9-_]+$
+	UID string `json:"uid,omitempty"`
+	// required: true
+	// example: {"service": "api", "threshold": "0.5"}
+	Values map[string]string `json:"values"`
+}
+
+// AlertRuleTemplateExport is the provisioned file export of models.AlertRuleTemplate.
+type AlertRuleTemplateExport struct {
+	OrgID           int64                             `json:"orgId" yaml:"orgId"`
+	UID             string                            `json:"uid" yaml:"uid"`
+	Folder          string                            `json:"folder" yaml:"folder"`
+	FolderUID       string                            `json:"-" yaml:"-"`
+	Group           string                            `json:"group" yaml:"group"`
+	Interval        model.Duration                    `json:"interval" yaml:"interval"`
+	IntervalSeconds int64                             `json:"-" yaml:"-"`
+	Parameters      []string                          `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	Rule            AlertRuleExport                   `json:"rule" yaml:"rule"`
+	Instances       []AlertRuleTemplateInstanceExport `json:"instances" yaml:"instances"`
+}
+
+// AlertRuleTemplateInstanceExport is the provisioned file export of models.AlertRuleTemplateInstance.
+type AlertRuleTemplateInstanceExport struct {
+	UID    string            `json:"uid" yaml:"uid"`
+	Values map[string]string `json:"values" yaml:"values"`
+}
+
+// AlertRuleTemplateExportHcl is the HCL export of AlertRuleTemplateExport. It is a rule group
+// with a dynamic block that generates a rule for each instance of the template.
+type AlertRuleTemplateExportHcl struct {
+	OrgID           int64                         `hcl:"org_id"`
+	Name            string                        `hcl:"name"`
+	FolderUID       string                        `hcl:"folder_uid"`
+	IntervalSeconds int64                         `hcl:"interval_seconds"`
+	Rule            AlertRuleTemplateDynamicBlock `hcl:"dynamic,block"`
+}
+
+// AlertRuleTemplateDynamicBlock is the dynamic "rule" block of AlertRuleTemplateExportHcl.
+type AlertRuleTemplateDynamicBlock struct {
+	Name    string              `hcl:"name,label"`
+	ForEach []map[string]string `hcl:"for_each"`
+	Content AlertRuleExport     `hcl:"content,block"`
+}
@@ -175,6 +175,8 @@ type ProvisionedAlertRule struct {
 	Record *Record `json:"record"`
 	// example: 2
 	MissingSeriesEvalsToResolve *int `json:"missingSeriesEvalsToResolve,omitempty"`
+	// example: [{"rule_uid":"cdbc9a5a-6d48-4c2a-b1a4-8a3b0a6d1f04","matchers":["datacenter=\"eu-west-1\""]}]
+	Dependencies []AlertRuleDependency `json:"dependencies,omitempty"`
 }
 
 // swagger:route GET /v1/provisioning/folder/{FolderUID}/rule-groups/{Group} provisioning stable RouteGetAlertRuleGroup
@@ -285,6 +287,7 @@ type AlertRuleExport struct {
 	NotificationSettings        *AlertRuleNotificationSettingsExport `json:"notification_settings,omitempty" yaml:"notification_settings,omitempty" hcl:"notification_settings,block"`
 	Record                      *AlertRuleRecordExport               `json:"record,omitempty" yaml:"record,omitempty" hcl:"record,block"`
 	MissingSeriesEvalsToResolve *int                                 `json:"missing_series_evals_to_resolve,omitempty" yaml:"missing_series_evals_to_resolve,omitempty" hcl:"missing_series_evals_to_resolve"`
+	Dependencies                []AlertRuleDependencyExport          `json:"dependencies,omitempty" yaml:"dependencies,omitempty" hcl:"dependency,block"`
 }
 
 // AlertQueryExport is the provisioned export of models.AlertQuery.
@@ -315,6 +318,12 @@ type AlertRuleNotificationSettingsExport struct {
 	ActiveTimeIntervals []string `yaml:"active_time_intervals,omitempty" json:"active_time_intervals,omitempty" hcl:"active_timings"` // TF -> `active_timings`
 }
 
+// AlertRuleDependencyExport is the provisioned export of models.AlertRuleDependency.
+type AlertRuleDependencyExport struct {
+	RuleUID  string   `json:"rule_uid" yaml:"rule_uid" hcl:"rule_uid"`
+	Matchers []string `json:"matchers,omitempty" yaml:"matchers,omitempty" hcl:"matchers"`
+}
+
 // Record is the provisioned export of models.Record.
 type AlertRuleRecordExport struct {
 	Metric              string  `json:"metric" yaml:"metric" hcl:"metric"`
@@ -0,0 +1,126 @@
+package definitions
+
+import (
+	"github.com/prometheus/common/model"
+)
+
+// swagger:route GET /v1/provisioning/escalation-policies provisioning stable RouteGetEscalationPolicies
+//
+// Get all the escalation policies.
+//
+//     Responses:
+//       200: EscalationPolicies
+
+// swagger:route GET /v1/provisioning/escalation-policies/{name} provisioning stable RouteGetEscalationPolicy
+//
+// Get an escalation policy.
+//
+//     Responses:
+//       200: ProvisionedEscalationPolicy
+//       404: description: Not found.
+
+// swagger:route POST /v1/provisioning/escalation-policies provisioning stable RoutePostEscalationPolicy
+//
+// Create a new escalation policy.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       201: ProvisionedEscalationPolicy
+//       400: ValidationError
+
+// swagger:route PUT /v1/provisioning/escalation-policies/{name} provisioning stable RoutePutEscalationPolicy
+//
+// Replace an existing escalation policy.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: ProvisionedEscalationPolicy
+//       400: ValidationError
+//       409: PublicError
+
+// swagger:route DELETE /v1/provisioning/escalation-policies/{name} provisioning stable RouteDeleteEscalationPolicy
+//
+// Delete an escalation policy.
+//
+//     Responses:
+//       204: description: The escalation policy was deleted successfully.
+//       409: PublicError
+
+// swagger:parameters RouteGetEscalationPolicy RoutePutEscalationPolicy
+type RouteGetEscalationPolicyParam struct {
+	// Escalation policy name
+	// in:path
+	Name string `json:"name"`
+}
+
+// swagger:parameters RouteDeleteEscalationPolicy
+type RouteDeleteEscalationPolicyParam struct {
+	// Escalation policy name
+	// in:path
+	Name string `json:"name"`
+
+	// Version of escalation policy to use for optimistic concurrency. Leave empty to disable validation
+	// in:query
+	Version string `json:"version"`
+}
+
+// swagger:parameters RoutePostEscalationPolicy RoutePutEscalationPolicy
+type EscalationPolicyPayload struct {
+	// in:body
+	Body ProvisionedEscalationPolicy
+}
+
+// swagger:parameters RoutePostEscalationPolicy RoutePutEscalationPolicy RouteDeleteEscalationPolicy
+type EscalationPolicyHeaders struct {
+	// in:header
+	XDisableProvenance string `json:"X-Disable-Provenance"`
+}
+
+// swagger:model
+type EscalationPolicies []ProvisionedEscalationPolicy
+
+// EscalationPolicy escalates the alerts that are notified to a contact point. If an alert is still firing and
+// not acknowledged after the delay of a step, the contact point of the step is notified, then the one of the next step.
+// swagger:model
+type EscalationPolicy struct {
+	// required: true
+	// example: on-call
+	Name string `json:"name" yaml:"name"`
+	// The contact point whose alerts are escalated. A contact point can be escalated by one policy only.
+	// required: true
+	// example: team-a
+	Receiver string `json:"receiver" yaml:"receiver"`
+	// The steps of the escalation, in the order of their delays.
+	// required: true
+	Steps []EscalationStep `json:"steps" yaml:"steps"`
+}
+
+// EscalationStep notifies a contact point when an alert has been firing for a delay.
+type EscalationStep struct {
+	// How long the alert must be firing, since it started, before the contact point is notified.
+	// required: true
+	// example: 15m
+	After model.Duration `json:"after" yaml:"after"`
+	// required: true
+	// example: team-a-lead
+	Receiver string `json:"receiver" yaml:"receiver"`
+}
+
+// swagger:model
+type ProvisionedEscalationPolicy struct {
+	EscalationPolicy `json:",inline" yaml:",inline"`
+	Version          string     `json:"version,omitempty"`
+	Provenance       Provenance `json:"provenance,omitempty"`
+}
+
+func (p *ProvisionedEscalationPolicy) ResourceType() string {
+	return "escalationPolicy"
+}
+
+func (p *ProvisionedEscalationPolicy) ResourceID() string {
+	return p.Name
+}
@@ -0,0 +1,74 @@
+package definitions
+
+import (
+	"time"
+
+	"github.com/prometheus/common/model"
+)
+
+// swagger:route POST /ruler/grafana/api/v1/rule/{RuleUID}/instances/{Fingerprint}/ack ruler RoutePostAlertInstanceAcknowledgement
+//
+// Acknowledge a firing alert instance.
+//
+// The acknowledgement is removed when the alert instance resolves or when it expires.
+// If notifications are suppressed, a silence that matches the alert instance is created until the acknowledgement expires.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: GettableAlertInstanceAcknowledgement
+//       400: ValidationError
+//       403: ForbiddenError
+//       404: NotFound
+
+// swagger:route DELETE /ruler/grafana/api/v1/rule/{RuleUID}/instances/{Fingerprint}/ack ruler RouteDeleteAlertInstanceAcknowledgement
+//
+// Remove the acknowledgement of an alert instance.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       202: Ack
+//       403: ForbiddenError
+//       404: NotFound
+
+// swagger:parameters RoutePostAlertInstanceAcknowledgement RouteDeleteAlertInstanceAcknowledgement
+type AlertInstanceAcknowledgementParams struct {
+	// in: path
+	RuleUID string
+	// Fingerprint of the labels of the alert instance, as reported by the Prometheus-compatible rules API.
+	// in: path
+	Fingerprint string
+}
+
+// swagger:parameters RoutePostAlertInstanceAcknowledgement
+type PostableAlertInstanceAcknowledgementParams struct {
+	// in: body
+	Body PostableAlertInstanceAcknowledgement
+}
+
+// swagger:model
+type PostableAlertInstanceAcknowledgement struct {
+	Comment string `json:"comment,omitempty"`
+	// The time when the acknowledgement expires. Mutually exclusive with duration.
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	// For how long the acknowledgement lasts. Mutually exclusive with expiresAt.
+	Duration model.Duration `json:"duration,omitempty"`
+	// Suppress the notifications of the alert instance until the acknowledgement expires. Requires an expiry.
+	SuppressNotifications bool `json:"suppressNotifications,omitempty"`
+}
+
+// swagger:model
+type GettableAlertInstanceAcknowledgement struct {
+	AcknowledgedBy string     `json:"acknowledgedBy"`
+	AcknowledgedAt time.Time  `json:"acknowledgedAt"`
+	Comment        string     `json:"comment,omitempty"`
+	ExpiresAt      *time.Time `json:"expiresAt,omitempty"`
+	// The ID of the silence that suppresses the notifications of the alert instance.
+	SilenceID string `json:"silenceId,omitempty"`
+}
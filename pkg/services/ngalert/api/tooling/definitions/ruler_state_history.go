@@ -15,6 +15,7 @@ import "github.com/grafana/grafana-plugin-sdk-go/data"
 //
 //     Responses:
 //       200: StateHistory
+//       400: ValidationError
 //       404: NotFound
 //       403: ForbiddenError
 //       500: Failure
@@ -50,4 +51,9 @@ type StateHistoryParams struct {
 	DashboardUID string
 	// Filter by dashboard's panel ID. Requires Dashboard UID to be specified.
 	PanelID int64
+	// Return an aggregation of the history instead of the history records. Only supported by the sql state history backend.
+	// in:query
+	// required: false
+	// enum: firing_duration,flapping
+	Aggregation string `json:"aggregation"`
 }
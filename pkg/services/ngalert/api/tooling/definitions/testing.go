@@ -68,6 +68,20 @@ import (
 //     Responses:
 //       200: BacktestResult
 
+// swagger:route Post /v1/rule/lint testing RouteLintGrafanaRuleGroup
+//
+// Find common mistakes in the rules of a Grafana rule group
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: RuleLintResponse
+//       400: ValidationError
+
 // swagger:parameters RouteTestReceiverConfig
 type TestReceiverRequest struct {
 	// in:body
@@ -230,7 +244,101 @@ type BacktestConfig struct {
 	Annotations map[string]string `json:"annotations,omitempty"`
 
 	NoDataState NoDataState `json:"no_data_state"`
+
+	// KeepFiringFor is the time an alert keeps firing after the condition is no longer met.
+	KeepFiringFor model.Duration `json:"keep_firing_for,omitempty"`
+	// MissingSeriesEvalsToResolve is the number of consecutive evaluations without a series
+	// after which the alert of the series is resolved.
+	MissingSeriesEvalsToResolve *int `json:"missing_series_evals_to_resolve,omitempty"`
+
+	// RuleUID is the UID of an existing alert rule that is being changed.
+	// If set, the alerts have the labels that Grafana adds to the alerts of the rule, such as the folder title,
+	// and the rule's notification settings.
+	RuleUID string `json:"rule_uid,omitempty"`
+	// SimulateNotifications routes the alerts through the current notification policy tree of the Grafana Alertmanager
+	// and reports the notifications that would have been sent.
+	SimulateNotifications bool `json:"simulate_notifications,omitempty"`
+	// CompareWithHistory compares the states of the backtest with the state history of the rule set by RuleUID.
+	CompareWithHistory bool `json:"compare_with_history,omitempty"`
 }
 
 // swagger:model
 type BacktestResult data.Frame
+
+// BacktestResultMeta is the custom metadata of the frame of a backtest.
+// swagger:model
+type BacktestResultMeta struct {
+	Notifications *BacktestNotifications     `json:"notifications,omitempty"`
+	History       *BacktestHistoryComparison `json:"history,omitempty"`
+}
+
+// BacktestNotifications are the notifications that the Grafana Alertmanager would have sent during the backtest.
+// swagger:model
+type BacktestNotifications struct {
+	// Total is the number of notifications.
+	Total int `json:"total"`
+	// ByReceiver is the number of notifications per contact point.
+	ByReceiver map[string]int `json:"by_receiver"`
+	// Muted is the number of notifications that were not sent because of mute timings or active time intervals.
+	Muted         int                    `json:"muted"`
+	Notifications []BacktestNotification `json:"notifications"`
+}
+
+// swagger:model
+type BacktestNotification struct {
+	Time        time.Time      `json:"time"`
+	Receiver    string         `json:"receiver"`
+	GroupLabels model.LabelSet `json:"group_labels"`
+	// Firing is the number of firing alerts in the notification.
+	Firing int `json:"firing"`
+	// Resolved is the number of resolved alerts in the notification.
+	Resolved int `json:"resolved"`
+}
+
+// BacktestHistoryComparison compares the states of the backtest with the state history of the rule.
+// swagger:model
+type BacktestHistoryComparison struct {
+	// Evaluations is the number of evaluations of all series that were compared.
+	Evaluations int `json:"evaluations"`
+	// Mismatches is the number of evaluations in which the state differs from the state history.
+	Mismatches int                               `json:"mismatches"`
+	Series     []BacktestHistorySeriesComparison `json:"series"`
+}
+
+// swagger:model
+type BacktestHistorySeriesComparison struct {
+	Labels        map[string]string `json:"labels"`
+	Evaluations   int               `json:"evaluations"`
+	Mismatches    int               `json:"mismatches"`
+	FirstMismatch *time.Time        `json:"first_mismatch,omitempty"`
+	// MissingInHistory is true if the state history has no record of the series. Its states are not compared.
+	MissingInHistory bool `json:"missing_in_history,omitempty"`
+	// MissingInBacktest is true if the series is in the state history but not in the backtest.
+	MissingInBacktest bool `json:"missing_in_backtest,omitempty"`
+}
+
+// swagger:parameters RouteLintGrafanaRuleGroup
+type RuleLintRequest struct {
+	// in:body
+	Body PostableRuleGroupConfig
+}
+
+// swagger:model
+type RuleLintResponse struct {
+	Findings []RuleLintFinding `json:"findings"`
+}
+
+// RuleLintFinding is a likely mistake in a rule. Rules with findings are valid and can be saved.
+// swagger:model
+type RuleLintFinding struct {
+	RuleUID   string `json:"ruleUid,omitempty"`
+	RuleTitle string `json:"ruleTitle"`
+	// RefID is the query or expression that the finding is about, if any.
+	RefID string `json:"refId,omitempty"`
+	// Check is the name of the check that produced the finding.
+	// example: for-shorter-than-interval
+	Check string `json:"check"`
+	// enum: info,warning,error
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
@@ -167,6 +167,13 @@ func validateAlertingRuleFields(in *apimodels.PostableExtendedRuleNode, newRule
 		return ngmodels.AlertRule{}, err
 	}
 
+	newRule.Dependencies = ModelDependenciesFromApiDependencies(in.GrafanaManagedAlert.Dependencies)
+	for _, d := range newRule.Dependencies {
+		if err := d.Validate(); err != nil {
+			return ngmodels.AlertRule{}, fmt.Errorf("%w: invalid dependency: %s", ngmodels.ErrAlertRuleFailedValidation, err.Error())
+		}
+	}
+
 	return newRule, nil
 }
 
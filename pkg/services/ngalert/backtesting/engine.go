@@ -15,6 +15,8 @@ import (
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/schedule"
@@ -42,11 +44,27 @@ type stateManager interface {
 type Engine struct {
 	evalFactory        eval.EvaluatorFactory
 	createStateManager func() stateManager
+	appUrl             *url.URL
+	featureToggles     featuremgmt.FeatureToggles
 }
 
-func NewEngine(appUrl *url.URL, evalFactory eval.EvaluatorFactory, tracer tracing.Tracer) *Engine {
+// Options enable the optional parts of a backtest.
+type Options struct {
+	// ExtraLabels are added to the labels of every alert, the same as the scheduler does for the labels of the rule's
+	// folder and notification settings.
+	ExtraLabels data.Labels
+	// Router routes the alerts to simulate the notifications of the Alertmanager. If nil, notifications are not simulated.
+	Router Router
+	// History is the state history of the rule in the interval of the backtest, in the format of the Loki historian.
+	// If not nil, the states of the backtest are compared with it.
+	History *data.Frame
+}
+
+func NewEngine(appUrl *url.URL, evalFactory eval.EvaluatorFactory, tracer tracing.Tracer, featureToggles featuremgmt.FeatureToggles) *Engine {
 	return &Engine{
-		evalFactory: evalFactory,
+		evalFactory:    evalFactory,
+		appUrl:         appUrl,
+		featureToggles: featureToggles,
 		createStateManager: func() stateManager {
 			cfg := state.ManagerCfg{
 				Metrics:       nil,
@@ -63,7 +81,10 @@ func NewEngine(appUrl *url.URL, evalFactory eval.EvaluatorFactory, tracer tracin
 	}
 }
 
-func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models.AlertRule, from, to time.Time) (*data.Frame, error) {
+// Test evaluates the rule at every evaluation interval between from and to, and returns a frame with the state of each
+// series at each evaluation. The states are calculated the same way as in the scheduler, including the pending period,
+// keep firing for and missing series settings of the rule.
+func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models.AlertRule, from, to time.Time, opts Options) (*data.Frame, error) {
 	ruleCtx := models.WithRuleKey(ctx, rule.GetKey())
 	logger := logger.FromContext(ctx)
 
@@ -77,6 +98,16 @@ func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models
 
 	stateManager := e.createStateManager()
 
+	var history map[string]*historySeries
+	filter := historyLabelsFilter(rule, opts.ExtraLabels)
+	if opts.History != nil {
+		var err error
+		history, err = parseHistory(opts.History, filter)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	evaluator, err := backtestingEvaluatorFactory(ruleCtx, e.evalFactory, user, rule.GetEvalCondition().WithSource("backtesting"), &schedule.AlertingResultsFromRuleState{
 		Manager: stateManager,
 		Rule:    rule,
@@ -91,13 +122,27 @@ func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models
 
 	tsField := data.NewField("Time", nil, make([]time.Time, length))
 	valueFields := make(map[data.Fingerprint]*data.Field)
+	// stateNames are the names of the states of each series, used to compare them with the state history.
+	stateNames := make(map[data.Fingerprint][]string)
+
+	var simulator *notificationSimulator
+	if opts.Router != nil {
+		simulator = newNotificationSimulator(opts.Router, e.appUrl, e.featureToggles)
+	}
 
 	err = evaluator.Eval(ruleCtx, from, time.Duration(rule.IntervalSeconds)*time.Second, length, func(idx int, currentTime time.Time, results eval.Results) error {
 		if idx >= length {
 			logger.Info("Unexpected evaluation. Skipping", "from", from, "to", to, "interval", rule.IntervalSeconds, "evaluationTime", currentTime, "evaluationIndex", idx, "expectedEvaluations", length)
 			return nil
 		}
-		states := stateManager.ProcessEvalResults(ruleCtx, currentTime, rule, results, nil, nil)
+		var send state.Sender
+		if simulator != nil {
+			send = simulator.sender(currentTime)
+		}
+		states := stateManager.ProcessEvalResults(ruleCtx, currentTime, rule, results, opts.ExtraLabels, send)
+		if simulator != nil && simulator.err != nil {
+			return fmt.Errorf("failed to simulate notifications: %w", simulator.err)
+		}
 		tsField.Set(idx, currentTime)
 		for _, s := range states {
 			field, ok := valueFields[s.CacheID]
@@ -105,6 +150,14 @@ func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models
 				field = data.NewField("", s.Labels, make([]*string, length))
 				valueFields[s.CacheID] = field
 			}
+			if history != nil {
+				names, ok := stateNames[s.CacheID]
+				if !ok {
+					names = make([]string, length)
+					stateNames[s.CacheID] = names
+				}
+				names[idx] = s.State.State.String()
+			}
 			if s.State.State != eval.NoData { // set nil if NoData
 				value := s.State.State.String()
 				if s.StateReason != "" {
@@ -126,6 +179,25 @@ func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models
 	if err != nil {
 		return nil, err
 	}
+
+	var meta definitions.BacktestResultMeta
+	if simulator != nil {
+		if err := simulator.flush(to); err != nil {
+			return nil, fmt.Errorf("failed to simulate notifications: %w", err)
+		}
+		meta.Notifications = simulator.result()
+	}
+	if history != nil {
+		series := make([]backtestSeries, 0, len(stateNames))
+		for fp, names := range stateNames {
+			series = append(series, backtestSeries{labels: valueFields[fp].Labels, states: names})
+		}
+		meta.History = compareWithHistory(tsField, series, history, filter)
+	}
+	if meta.Notifications != nil || meta.History != nil {
+		result.SetMeta(&data.FrameMeta{Custom: meta})
+	}
+
 	logger.Info("Rule testing finished successfully", "duration", time.Since(start))
 	return result, nil
 }
@@ -9,11 +9,14 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval/eval_mocks"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
@@ -189,6 +192,7 @@ func TestEvaluatorTest(t *testing.T) {
 		createStateManager: func() stateManager {
 			return manager
 		},
+		featureToggles: featuremgmt.WithFeatures(),
 	}
 	gen := models.RuleGen
 	rule := gen.With(gen.WithInterval(time.Second)).GenerateRef()
@@ -217,7 +221,7 @@ func TestEvaluatorTest(t *testing.T) {
 			return states
 		}
 
-		frame, err := engine.Test(context.Background(), nil, rule, from, to)
+		frame, err := engine.Test(context.Background(), nil, rule, from, to, Options{})
 
 		require.NoError(t, err)
 		require.Len(t, frame.Fields, len(states)+1) // +1 - timestamp
@@ -287,12 +291,12 @@ func TestEvaluatorTest(t *testing.T) {
 			return states
 		}
 
-		frame, err := engine.Test(context.Background(), nil, rule, from, to)
+		frame, err := engine.Test(context.Background(), nil, rule, from, to, Options{})
 		require.NoError(t, err)
 		expectedLen := frame.Rows()
 		for i := 0; i < 100; i++ {
 			jitter := time.Duration(rand.Int63n(ruleInterval.Milliseconds())) * time.Millisecond
-			frame, err = engine.Test(context.Background(), nil, rule, from, to.Add(jitter))
+			frame, err = engine.Test(context.Background(), nil, rule, from, to.Add(jitter), Options{})
 			require.NoError(t, err)
 			require.Equalf(t, expectedLen, frame.Rows(), "jitter %v caused result to be different that base-line", jitter)
 		}
@@ -323,7 +327,7 @@ func TestEvaluatorTest(t *testing.T) {
 			return stateByTime[now]
 		}
 
-		frame, err := engine.Test(context.Background(), nil, rule, from, to)
+		frame, err := engine.Test(context.Background(), nil, rule, from, to, Options{})
 		require.NoError(t, err)
 
 		var field3 *data.Field
@@ -345,6 +349,43 @@ func TestEvaluatorTest(t *testing.T) {
 		}
 	})
 
+	t.Run("should report notifications and comparison with history in frame meta", func(t *testing.T) {
+		from := time.Unix(0, 0)
+		to := from.Add(5 * ruleInterval)
+		labels := data.Labels{"instance": "1"}
+		manager.stateCallback = func(now time.Time) []state.StateTransition {
+			return []state.StateTransition{{
+				State: &state.State{
+					CacheID:            labels.Fingerprint(),
+					Labels:             labels,
+					State:              eval.Alerting,
+					LastEvaluationTime: now,
+					EndsAt:             now.Add(4 * ruleInterval),
+				},
+			}}
+		}
+		router := &fakeRouter{routes: []apimodels.TestRoutingRoute{{
+			Path:           []apimodels.TestRoutingRouteStep{{Index: -1, Receiver: "default"}},
+			Receiver:       "default",
+			GroupInterval:  model.Duration(time.Hour),
+			RepeatInterval: model.Duration(time.Hour),
+		}}}
+		history := lokiHistoryFrame(t, historyLine{at: from.Add(ruleInterval), previous: "Normal", current: "Alerting", labels: labels})
+
+		frame, err := engine.Test(context.Background(), nil, rule, from, to, Options{Router: router, History: history})
+		require.NoError(t, err)
+		require.NotNil(t, frame.Meta)
+		meta, ok := frame.Meta.Custom.(apimodels.BacktestResultMeta)
+		require.True(t, ok)
+
+		require.Equal(t, 1, meta.Notifications.Total)
+		require.Equal(t, from, meta.Notifications.Notifications[0].Time)
+
+		require.Equal(t, 5, meta.History.Evaluations)
+		require.Equal(t, 1, meta.History.Mismatches)
+		require.Equal(t, from, *meta.History.Series[0].FirstMismatch)
+	})
+
 	t.Run("should fail", func(t *testing.T) {
 		manager.stateCallback = func(now time.Time) []state.StateTransition {
 			return nil
@@ -354,17 +395,17 @@ func TestEvaluatorTest(t *testing.T) {
 			from := time.Now()
 			t.Run("when from=to", func(t *testing.T) {
 				to := from
-				_, err := engine.Test(context.Background(), nil, rule, from, to)
+				_, err := engine.Test(context.Background(), nil, rule, from, to, Options{})
 				require.ErrorIs(t, err, ErrInvalidInputData)
 			})
 			t.Run("when from > to", func(t *testing.T) {
 				to := from.Add(-ruleInterval)
-				_, err := engine.Test(context.Background(), nil, rule, from, to)
+				_, err := engine.Test(context.Background(), nil, rule, from, to, Options{})
 				require.ErrorIs(t, err, ErrInvalidInputData)
 			})
 			t.Run("when to-from < interval", func(t *testing.T) {
 				to := from.Add(ruleInterval).Add(-time.Millisecond)
-				_, err := engine.Test(context.Background(), nil, rule, from, to)
+				_, err := engine.Test(context.Background(), nil, rule, from, to, Options{})
 				require.ErrorIs(t, err, ErrInvalidInputData)
 			})
 		})
@@ -376,7 +417,7 @@ func TestEvaluatorTest(t *testing.T) {
 			}
 			from := time.Now()
 			to := from.Add(ruleInterval)
-			_, err := engine.Test(context.Background(), nil, rule, from, to)
+			_, err := engine.Test(context.Background(), nil, rule, from, to, Options{})
 			require.ErrorIs(t, err, expectedError)
 		})
 	})
@@ -386,8 +427,12 @@ type fakeStateManager struct {
 	stateCallback func(now time.Time) []state.StateTransition
 }
 
-func (f *fakeStateManager) ProcessEvalResults(_ context.Context, evaluatedAt time.Time, _ *models.AlertRule, _ eval.Results, _ data.Labels, _ state.Sender) state.StateTransitions {
-	return f.stateCallback(evaluatedAt)
+func (f *fakeStateManager) ProcessEvalResults(ctx context.Context, evaluatedAt time.Time, _ *models.AlertRule, _ eval.Results, _ data.Labels, send state.Sender) state.StateTransitions {
+	states := f.stateCallback(evaluatedAt)
+	if send != nil {
+		send(ctx, states)
+	}
+	return states
 }
 
 func (f *fakeStateManager) GetStatesForRuleUID(orgID int64, alertRuleUID string) []*state.State {
@@ -0,0 +1,180 @@
+package backtesting
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	prometheusModel "github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// historyEntry is the part of a line of the Loki state history that is needed to compare states.
+type historyEntry struct {
+	Previous       string            `json:"previous"`
+	Current        string            `json:"current"`
+	InstanceLabels map[string]string `json:"labels"`
+}
+
+type historyRecord struct {
+	at       time.Time
+	previous string
+	current  string
+}
+
+// historySeries is the state history of a series, ordered by time.
+type historySeries struct {
+	labels  data.Labels
+	records []historyRecord
+}
+
+// stateAt returns the name of the state of the series at the given time.
+// Before the first record, it is the state that the first record transitioned from.
+func (h *historySeries) stateAt(t time.Time) string {
+	i := sort.Search(len(h.records), func(i int) bool {
+		return h.records[i].at.After(t)
+	})
+	if i == 0 {
+		return h.records[0].previous
+	}
+	return h.records[i-1].current
+}
+
+// backtestSeries is a series of the backtest with the names of its states at each evaluation.
+// The name is empty if the series was not evaluated.
+type backtestSeries struct {
+	labels data.Labels
+	states []string
+}
+
+// labelsFilter returns the labels by which a series of the backtest is matched with a series of the state history.
+type labelsFilter func(data.Labels) data.Labels
+
+// historyLabelsFilter removes the labels that do not come from the query: the private labels, the labels of the rule
+// and the extra labels. This way the series are matched even if the labels of the rule changed.
+func historyLabelsFilter(rule *models.AlertRule, extraLabels data.Labels) labelsFilter {
+	return func(lbls data.Labels) data.Labels {
+		result := make(data.Labels, len(lbls))
+		for k, v := range lbls {
+			if strings.HasPrefix(k, "__") && strings.HasSuffix(k, "__") {
+				continue
+			}
+			if _, ok := rule.Labels[k]; ok {
+				continue
+			}
+			if _, ok := extraLabels[k]; ok {
+				continue
+			}
+			if k == prometheusModel.AlertNameLabel || k == models.FolderTitleLabel {
+				continue
+			}
+			result[k] = v
+		}
+		return result
+	}
+}
+
+// parseHistory groups the records of the state history by series.
+func parseHistory(frame *data.Frame, filter labelsFilter) (map[string]*historySeries, error) {
+	timeField, _ := frame.FieldByName("time")
+	lineField, _ := frame.FieldByName("line")
+	if timeField == nil || lineField == nil {
+		return nil, fmt.Errorf("%w: comparing with the state history requires the Loki state history backend", ErrInvalidInputData)
+	}
+
+	result := make(map[string]*historySeries)
+	for i := 0; i < frame.Rows(); i++ {
+		at, ok := timeField.At(i).(time.Time)
+		if !ok {
+			return nil, fmt.Errorf("unexpected type of time in the state history: %T", timeField.At(i))
+		}
+		line, ok := lineField.At(i).(json.RawMessage)
+		if !ok {
+			return nil, fmt.Errorf("unexpected type of line in the state history: %T", lineField.At(i))
+		}
+		var entry historyEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return nil, fmt.Errorf("failed to parse a line of the state history: %w", err)
+		}
+
+		lbls := filter(entry.InstanceLabels)
+		key := lbls.String()
+		series, ok := result[key]
+		if !ok {
+			series = &historySeries{labels: lbls}
+			result[key] = series
+		}
+		series.records = append(series.records, historyRecord{
+			at:       at,
+			previous: stateName(entry.Previous),
+			current:  stateName(entry.Current),
+		})
+	}
+	for _, series := range result {
+		sort.SliceStable(series.records, func(i, j int) bool {
+			return series.records[i].at.Before(series.records[j].at)
+		})
+	}
+	return result, nil
+}
+
+// stateName returns the name of a state without its reason, for example "Normal" for "Normal (MissingSeries)".
+func stateName(s string) string {
+	name, _, _ := strings.Cut(s, " (")
+	return name
+}
+
+// compareWithHistory compares the states of the series of the backtest with the states of the series of the history
+// at each evaluation.
+func compareWithHistory(timestamps *data.Field, series []backtestSeries, history map[string]*historySeries, filter labelsFilter) *definitions.BacktestHistoryComparison {
+	result := &definitions.BacktestHistoryComparison{
+		Series: make([]definitions.BacktestHistorySeriesComparison, 0, len(series)),
+	}
+	compared := make(map[string]struct{}, len(history))
+	for _, s := range series {
+		lbls := filter(s.labels)
+		key := lbls.String()
+		cmp := definitions.BacktestHistorySeriesComparison{Labels: lbls}
+		h, ok := history[key]
+		if !ok {
+			cmp.MissingInHistory = true
+			result.Series = append(result.Series, cmp)
+			continue
+		}
+		compared[key] = struct{}{}
+		for idx, name := range s.states {
+			if name == "" {
+				continue
+			}
+			at := timestamps.At(idx).(time.Time)
+			cmp.Evaluations++
+			if h.stateAt(at) != name {
+				cmp.Mismatches++
+				if cmp.FirstMismatch == nil {
+					cmp.FirstMismatch = &at
+				}
+			}
+		}
+		result.Evaluations += cmp.Evaluations
+		result.Mismatches += cmp.Mismatches
+		result.Series = append(result.Series, cmp)
+	}
+	for key, h := range history {
+		if _, ok := compared[key]; ok {
+			continue
+		}
+		result.Series = append(result.Series, definitions.BacktestHistorySeriesComparison{
+			Labels:            h.labels,
+			MissingInBacktest: true,
+		})
+	}
+	sort.Slice(result.Series, func(i, j int) bool {
+		return data.Labels(result.Series[i].Labels).String() < data.Labels(result.Series[j].Labels).String()
+	})
+	return result
+}
@@ -0,0 +1,106 @@
+package backtesting
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type historyLine struct {
+	at       time.Time
+	previous string
+	current  string
+	labels   map[string]string
+}
+
+func lokiHistoryFrame(t *testing.T, lines ...historyLine) *data.Frame {
+	t.Helper()
+	times := make([]time.Time, 0, len(lines))
+	raw := make([]json.RawMessage, 0, len(lines))
+	for _, l := range lines {
+		b, err := json.Marshal(historyEntry{Previous: l.previous, Current: l.current, InstanceLabels: l.labels})
+		require.NoError(t, err)
+		times = append(times, l.at)
+		raw = append(raw, b)
+	}
+	return data.NewFrame("states",
+		data.NewField("time", nil, times),
+		data.NewField("line", nil, raw),
+	)
+}
+
+func TestParseHistory(t *testing.T) {
+	rule := &models.AlertRule{Labels: map[string]string{"team": "a"}}
+	filter := historyLabelsFilter(rule, data.Labels{"extra": "1"})
+	start := time.Unix(0, 0)
+
+	t.Run("groups records by the labels of the query", func(t *testing.T) {
+		lbls := map[string]string{"alertname": "test", "grafana_folder": "f", "team": "a", "extra": "1", "instance": "1"}
+		frame := lokiHistoryFrame(t,
+			historyLine{at: start.Add(2 * time.Minute), previous: "Pending", current: "Alerting", labels: lbls},
+			historyLine{at: start.Add(time.Minute), previous: "Normal", current: "Pending", labels: lbls},
+			historyLine{at: start.Add(time.Minute), previous: "Normal", current: "Alerting (Error)", labels: map[string]string{"instance": "2"}},
+		)
+		history, err := parseHistory(frame, filter)
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+
+		series := history[data.Labels{"instance": "1"}.String()]
+		require.NotNil(t, series)
+		require.Equal(t, "Normal", series.stateAt(start))
+		require.Equal(t, "Pending", series.stateAt(start.Add(time.Minute)))
+		require.Equal(t, "Pending", series.stateAt(start.Add(90*time.Second)))
+		require.Equal(t, "Alerting", series.stateAt(start.Add(time.Hour)))
+
+		series = history[data.Labels{"instance": "2"}.String()]
+		require.NotNil(t, series)
+		require.Equal(t, "Alerting", series.stateAt(start.Add(time.Hour)))
+	})
+
+	t.Run("fails if the history is not in the format of Loki", func(t *testing.T) {
+		frame := data.NewFrame("states",
+			data.NewField("time", nil, []time.Time{}),
+			data.NewField("text", nil, []string{}),
+		)
+		_, err := parseHistory(frame, filter)
+		require.ErrorIs(t, err, ErrInvalidInputData)
+	})
+}
+
+func TestCompareWithHistory(t *testing.T) {
+	rule := &models.AlertRule{}
+	filter := historyLabelsFilter(rule, nil)
+	start := time.Unix(0, 0)
+	timestamps := data.NewField("Time", nil, []time.Time{start, start.Add(time.Minute), start.Add(2 * time.Minute)})
+
+	history, err := parseHistory(lokiHistoryFrame(t,
+		historyLine{at: start.Add(time.Minute), previous: "Normal", current: "Alerting", labels: map[string]string{"instance": "1"}},
+		historyLine{at: start, previous: "Normal", current: "Pending", labels: map[string]string{"instance": "3"}},
+	), filter)
+	require.NoError(t, err)
+
+	result := compareWithHistory(timestamps, []backtestSeries{
+		{labels: data.Labels{"instance": "1"}, states: []string{"Normal", "Normal", "Alerting"}},
+		{labels: data.Labels{"instance": "2"}, states: []string{"Normal", "Normal", "Normal"}},
+	}, history, filter)
+
+	require.Equal(t, 3, result.Evaluations)
+	require.Equal(t, 1, result.Mismatches)
+	require.Len(t, result.Series, 3)
+
+	require.Equal(t, map[string]string{"instance": "1"}, result.Series[0].Labels)
+	require.Equal(t, 1, result.Series[0].Mismatches)
+	require.Equal(t, start.Add(time.Minute), *result.Series[0].FirstMismatch)
+
+	require.Equal(t, map[string]string{"instance": "2"}, result.Series[1].Labels)
+	require.True(t, result.Series[1].MissingInHistory)
+	require.Zero(t, result.Series[1].Evaluations)
+
+	require.Equal(t, map[string]string{"instance": "3"}, result.Series[2].Labels)
+	require.True(t, result.Series[2].MissingInBacktest)
+}
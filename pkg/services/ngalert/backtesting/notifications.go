@@ -0,0 +1,284 @@
+package backtesting
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+// Router finds the notification policies that an alert matches.
+type Router interface {
+	// Match returns the routes that the alert with the given labels matches, and whether they are muted at the given time.
+	Match(lbls model.LabelSet, now time.Time) ([]definitions.TestRoutingRoute, error)
+}
+
+// simulatedAlert is an alert as it is received by the Alertmanager.
+type simulatedAlert struct {
+	labels      model.LabelSet
+	fingerprint model.Fingerprint
+	endsAt      time.Time
+}
+
+func newSimulatedAlert(labels map[string]string, endsAt time.Time) *simulatedAlert {
+	lbls := make(model.LabelSet, len(labels))
+	for k, v := range labels {
+		lbls[model.LabelName(k)] = model.LabelValue(v)
+	}
+	return &simulatedAlert{
+		labels:      lbls,
+		fingerprint: lbls.Fingerprint(),
+		endsAt:      endsAt,
+	}
+}
+
+func (a *simulatedAlert) resolved(now time.Time) bool {
+	return !a.endsAt.After(now)
+}
+
+// aggregationGroup is a group of alerts of a route that are notified together.
+type aggregationGroup struct {
+	key   string
+	route definitions.TestRoutingRoute
+	// labels are the labels of the first alert of the group. They are used to check if the route is muted.
+	labels      model.LabelSet
+	groupLabels model.LabelSet
+	alerts      map[model.Fingerprint]*simulatedAlert
+	nextFlush   time.Time
+}
+
+// notificationLogEntry is the last notification sent for an aggregation group.
+type notificationLogEntry struct {
+	timestamp time.Time
+	firing    map[model.Fingerprint]struct{}
+	resolved  map[model.Fingerprint]struct{}
+}
+
+// notificationSimulator replays the alerts of a backtest through the dispatcher of the Alertmanager.
+// It follows the grouping and timing options of the routes the same way the Alertmanager does:
+// the first notification of a group is sent after group_wait, and the group is flushed every group_interval.
+// A flush sends a notification if there are new firing or resolved alerts, or if repeat_interval passed since
+// the previous notification. Notifications of muted routes are counted but not sent.
+type notificationSimulator struct {
+	router         Router
+	appURL         *url.URL
+	featureToggles featuremgmt.FeatureToggles
+
+	groups map[string]*aggregationGroup
+	nflog  map[string]*notificationLogEntry
+	sent   []definitions.BacktestNotification
+	muted  int
+	// err is the first error that happened while the alerts were sent by the state manager.
+	err error
+}
+
+func newNotificationSimulator(router Router, appURL *url.URL, featureToggles featuremgmt.FeatureToggles) *notificationSimulator {
+	return &notificationSimulator{
+		router:         router,
+		appURL:         appURL,
+		featureToggles: featureToggles,
+		groups:         make(map[string]*aggregationGroup),
+		nflog:          make(map[string]*notificationLogEntry),
+	}
+}
+
+// sender returns a state.Sender that sends the alerts of the evaluation at the given time to the simulator.
+func (s *notificationSimulator) sender(now time.Time) state.Sender {
+	return func(_ context.Context, transitions state.StateTransitions) {
+		if s.err != nil {
+			return
+		}
+		alerts := make([]*simulatedAlert, 0, len(transitions))
+		for _, t := range transitions {
+			alert := state.StateToPostableAlert(t, s.appURL, s.featureToggles)
+			alerts = append(alerts, newSimulatedAlert(alert.Labels, time.Time(alert.EndsAt)))
+		}
+		s.err = s.receive(now, alerts)
+	}
+}
+
+// receive flushes the groups that are due and adds the alerts to their aggregation groups.
+func (s *notificationSimulator) receive(now time.Time, alerts []*simulatedAlert) error {
+	if err := s.flush(now); err != nil {
+		return err
+	}
+	for _, alert := range alerts {
+		routes, err := s.router.Match(alert.labels, now)
+		if err != nil {
+			return err
+		}
+		for _, route := range routes {
+			groupLabels := getGroupLabels(alert.labels, route.GroupBy)
+			key := routeKey(route) + ":" + groupLabels.String()
+			group, ok := s.groups[key]
+			if !ok {
+				group = &aggregationGroup{
+					key:         key,
+					route:       route,
+					labels:      alert.labels,
+					groupLabels: groupLabels,
+					alerts:      make(map[model.Fingerprint]*simulatedAlert),
+					nextFlush:   now.Add(time.Duration(route.GroupWait)),
+				}
+				s.groups[key] = group
+			}
+			group.alerts[alert.fingerprint] = alert
+		}
+	}
+	return nil
+}
+
+// flush flushes the groups that are due until the given time, in chronological order.
+func (s *notificationSimulator) flush(until time.Time) error {
+	for {
+		var next *aggregationGroup
+		for _, g := range s.groups {
+			if g.nextFlush.After(until) {
+				continue
+			}
+			if next == nil || g.nextFlush.Before(next.nextFlush) || (g.nextFlush.Equal(next.nextFlush) && g.key < next.key) {
+				next = g
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		if err := s.flushGroup(next); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *notificationSimulator) flushGroup(g *aggregationGroup) error {
+	now := g.nextFlush
+	g.nextFlush = now.Add(time.Duration(g.route.GroupInterval))
+
+	firing := make(map[model.Fingerprint]struct{})
+	resolved := make(map[model.Fingerprint]struct{})
+	for fp, alert := range g.alerts {
+		if alert.resolved(now) {
+			resolved[fp] = struct{}{}
+		} else {
+			firing[fp] = struct{}{}
+		}
+	}
+	// The Alertmanager deletes the resolved alerts after a flush, and the group once it is empty.
+	defer func() {
+		for fp := range resolved {
+			delete(g.alerts, fp)
+		}
+		if len(g.alerts) == 0 {
+			delete(s.groups, g.key)
+		}
+	}()
+
+	entry := s.nflog[g.key]
+	if !needsUpdate(entry, firing, resolved, now, time.Duration(g.route.RepeatInterval)) {
+		return nil
+	}
+
+	muted, err := s.isMuted(g, now)
+	if err != nil {
+		return err
+	}
+	if muted {
+		s.muted++
+		return nil
+	}
+
+	s.nflog[g.key] = &notificationLogEntry{timestamp: now, firing: firing, resolved: resolved}
+	s.sent = append(s.sent, definitions.BacktestNotification{
+		Time:        now,
+		Receiver:    g.route.Receiver,
+		GroupLabels: g.groupLabels,
+		Firing:      len(firing),
+		Resolved:    len(resolved),
+	})
+	return nil
+}
+
+// isMuted returns true if the route of the group is muted by its mute timings or active time intervals at the time.
+func (s *notificationSimulator) isMuted(g *aggregationGroup, now time.Time) (bool, error) {
+	routes, err := s.router.Match(g.labels, now)
+	if err != nil {
+		return false, err
+	}
+	key := routeKey(g.route)
+	for _, route := range routes {
+		if routeKey(route) == key {
+			return route.Muted, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *notificationSimulator) result() *definitions.BacktestNotifications {
+	result := &definitions.BacktestNotifications{
+		Total:         len(s.sent),
+		ByReceiver:    make(map[string]int),
+		Muted:         s.muted,
+		Notifications: make([]definitions.BacktestNotification, 0, len(s.sent)),
+	}
+	for _, n := range s.sent {
+		result.ByReceiver[n.Receiver]++
+		result.Notifications = append(result.Notifications, n)
+	}
+	return result
+}
+
+// needsUpdate returns true if a notification must be sent for the group.
+// It follows the deduplication of notifications of the Alertmanager, with resolved notifications enabled.
+func needsUpdate(entry *notificationLogEntry, firing, resolved map[model.Fingerprint]struct{}, now time.Time, repeatInterval time.Duration) bool {
+	if entry == nil {
+		return len(firing) > 0
+	}
+	if !isSubset(firing, entry.firing) {
+		return true
+	}
+	if len(firing) == 0 {
+		// All alerts of the group resolved since the previous notification.
+		return len(entry.firing) > 0
+	}
+	if !isSubset(resolved, entry.resolved) {
+		return true
+	}
+	return entry.timestamp.Before(now.Add(-repeatInterval))
+}
+
+func isSubset(set, of map[model.Fingerprint]struct{}) bool {
+	for fp := range set {
+		if _, ok := of[fp]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func getGroupLabels(lbls model.LabelSet, groupBy []string) model.LabelSet {
+	if len(groupBy) == 1 && groupBy[0] == "..." {
+		return lbls.Clone()
+	}
+	result := make(model.LabelSet, len(groupBy))
+	for _, name := range groupBy {
+		if v, ok := lbls[model.LabelName(name)]; ok {
+			result[model.LabelName(name)] = v
+		}
+	}
+	return result
+}
+
+// routeKey identifies a route by the indices of the routes in the path from the root.
+func routeKey(route definitions.TestRoutingRoute) string {
+	indices := make([]string, 0, len(route.Path))
+	for _, step := range route.Path {
+		indices = append(indices, strconv.Itoa(step.Index))
+	}
+	return strings.Join(indices, "/")
+}
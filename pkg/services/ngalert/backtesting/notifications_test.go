@@ -0,0 +1,136 @@
+package backtesting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+type fakeRouter struct {
+	routes []definitions.TestRoutingRoute
+	// mutedUntil mutes all routes before the time.
+	mutedUntil time.Time
+}
+
+func (f *fakeRouter) Match(_ model.LabelSet, now time.Time) ([]definitions.TestRoutingRoute, error) {
+	result := make([]definitions.TestRoutingRoute, 0, len(f.routes))
+	for _, r := range f.routes {
+		r.Muted = now.Before(f.mutedUntil)
+		result = append(result, r)
+	}
+	return result, nil
+}
+
+func TestNotificationSimulator(t *testing.T) {
+	route := definitions.TestRoutingRoute{
+		Path:           []definitions.TestRoutingRouteStep{{Index: -1, Receiver: "default"}},
+		Receiver:       "default",
+		GroupBy:        []string{"alertname"},
+		GroupWait:      model.Duration(30 * time.Second),
+		GroupInterval:  model.Duration(5 * time.Minute),
+		RepeatInterval: model.Duration(time.Hour),
+	}
+	start := time.Unix(0, 0)
+	alert := func(name, instance string, endsAt time.Time) *simulatedAlert {
+		return newSimulatedAlert(map[string]string{"alertname": name, "instance": instance}, endsAt)
+	}
+	// receiveEvery sends the alerts every minute between from and to, the same as a rule with an interval of 1m.
+	receiveEvery := func(t *testing.T, s *notificationSimulator, from, to time.Time, alerts func(now time.Time) []*simulatedAlert) {
+		for now := from; now.Before(to); now = now.Add(time.Minute) {
+			require.NoError(t, s.receive(now, alerts(now)))
+		}
+	}
+
+	t.Run("sends the first notification after group wait and repeats it", func(t *testing.T) {
+		s := newNotificationSimulator(&fakeRouter{routes: []definitions.TestRoutingRoute{route}}, nil, nil)
+		end := start.Add(2 * time.Hour)
+		receiveEvery(t, s, start, end, func(now time.Time) []*simulatedAlert {
+			return []*simulatedAlert{alert("test", "1", now.Add(4*time.Minute))}
+		})
+		require.NoError(t, s.flush(end))
+
+		result := s.result()
+		require.Equal(t, 2, result.Total)
+		require.Equal(t, map[string]int{"default": 2}, result.ByReceiver)
+		require.Equal(t, start.Add(30*time.Second), result.Notifications[0].Time)
+		require.Equal(t, model.LabelSet{"alertname": "test"}, result.Notifications[0].GroupLabels)
+		// the group is flushed every group interval, the first flush after the repeat interval sends again
+		require.Equal(t, start.Add(30*time.Second+65*time.Minute), result.Notifications[1].Time)
+	})
+
+	t.Run("groups alerts and notifies new and resolved alerts at the next group interval", func(t *testing.T) {
+		s := newNotificationSimulator(&fakeRouter{routes: []definitions.TestRoutingRoute{route}}, nil, nil)
+		resolvedAt := start.Add(10 * time.Minute)
+		end := start.Add(30 * time.Minute)
+		receiveEvery(t, s, start, end, func(now time.Time) []*simulatedAlert {
+			alerts := []*simulatedAlert{alert("test", "1", now.Add(4*time.Minute))}
+			if now.Before(resolvedAt) {
+				alerts = append(alerts, alert("test", "2", now.Add(4*time.Minute)))
+			} else if now.Equal(resolvedAt) {
+				alerts = append(alerts, alert("test", "2", now))
+			}
+			return alerts
+		})
+		require.NoError(t, s.flush(end))
+
+		result := s.result()
+		require.Equal(t, 2, result.Total)
+		require.Equal(t, 2, result.Notifications[0].Firing)
+		require.Equal(t, 0, result.Notifications[0].Resolved)
+		require.Equal(t, start.Add(30*time.Second+10*time.Minute), result.Notifications[1].Time)
+		require.Equal(t, 1, result.Notifications[1].Firing)
+		require.Equal(t, 1, result.Notifications[1].Resolved)
+	})
+
+	t.Run("creates a group per value of the group by labels and per route", func(t *testing.T) {
+		other := route
+		other.Receiver = "other"
+		other.Path = []definitions.TestRoutingRouteStep{{Index: -1}, {Index: 0, Receiver: "other"}}
+		s := newNotificationSimulator(&fakeRouter{routes: []definitions.TestRoutingRoute{route, other}}, nil, nil)
+		require.NoError(t, s.receive(start, []*simulatedAlert{
+			alert("a", "1", start.Add(time.Hour)),
+			alert("b", "1", start.Add(time.Hour)),
+		}))
+		require.NoError(t, s.flush(start.Add(time.Minute)))
+
+		result := s.result()
+		require.Equal(t, 4, result.Total)
+		require.Equal(t, map[string]int{"default": 2, "other": 2}, result.ByReceiver)
+	})
+
+	t.Run("does not send notifications of muted routes", func(t *testing.T) {
+		s := newNotificationSimulator(&fakeRouter{routes: []definitions.TestRoutingRoute{route}, mutedUntil: start.Add(20 * time.Minute)}, nil, nil)
+		end := start.Add(30 * time.Minute)
+		receiveEvery(t, s, start, end, func(now time.Time) []*simulatedAlert {
+			return []*simulatedAlert{alert("test", "1", now.Add(4*time.Minute))}
+		})
+		require.NoError(t, s.flush(end))
+
+		result := s.result()
+		require.Equal(t, 1, result.Total)
+		// the flushes at 0:30, 5:30, 10:30 and 15:30 are muted
+		require.Equal(t, 4, result.Muted)
+		require.Equal(t, start.Add(20*time.Minute+30*time.Second), result.Notifications[0].Time)
+	})
+
+	t.Run("resolves alerts that are no longer sent when they expire", func(t *testing.T) {
+		s := newNotificationSimulator(&fakeRouter{routes: []definitions.TestRoutingRoute{route}}, nil, nil)
+		require.NoError(t, s.receive(start, []*simulatedAlert{alert("test", "1", start.Add(4*time.Minute))}))
+		require.NoError(t, s.flush(start.Add(time.Hour)))
+
+		result := s.result()
+		require.Equal(t, 2, result.Total)
+		require.Equal(t, 1, result.Notifications[1].Resolved)
+		require.Empty(t, s.groups)
+	})
+}
+
+func TestRouteKey(t *testing.T) {
+	require.Equal(t, "-1/2/0", routeKey(definitions.TestRoutingRoute{
+		Path: []definitions.TestRoutingRouteStep{{Index: -1}, {Index: 2}, {Index: 0}},
+	}))
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/expr"
 )
 
 // AlertingResultsReader provides fingerprints of results that are in alerting state.
@@ -14,6 +15,12 @@ type AlertingResultsReader interface {
 	Read() map[data.Fingerprint]struct{}
 }
 
+// HysteresisStreaksReader is an optional extension of AlertingResultsReader that provides the streaks of the results
+// that were beyond a recovery threshold but had not transitioned yet, as returned by the previous evaluation.
+type HysteresisStreaksReader interface {
+	ReadHysteresisStreaks() expr.HysteresisStreaks
+}
+
 // EvaluationContext represents the context in which a condition is evaluated.
 type EvaluationContext struct {
 	Ctx                   context.Context
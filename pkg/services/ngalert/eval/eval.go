@@ -54,6 +54,9 @@ type conditionEvaluator struct {
 	condition         models.Condition
 	evalTimeout       time.Duration
 	evalResultLimit   int
+	// keepFrames is true if the frames of all queries and expressions are kept in the results, so they can be
+	// drawn in the images of notifications.
+	keepFrames bool
 }
 
 func (r *conditionEvaluator) EvaluateRaw(ctx context.Context, now time.Time) (resp *backend.QueryDataResponse, err error) {
@@ -99,12 +102,20 @@ func (r *conditionEvaluator) Evaluate(ctx context.Context, now time.Time) (Resul
 	if err != nil {
 		return nil, err
 	}
-	return EvaluateAlert(response, r.condition, now), nil
+	execResults := queryDataResponseToExecutionResults(r.condition, response)
+	results := evaluateExecutionResult(execResults, now)
+	if r.keepFrames {
+		for i := range results {
+			results[i].Results = execResults.Results
+		}
+	}
+	return results, nil
 }
 
 type evaluatorImpl struct {
 	evaluationTimeout     time.Duration
 	evaluationResultLimit int
+	keepFrames            bool
 	dataSourceCache       datasources.CacheService
 	expressionService     expressionBuilder
 }
@@ -117,6 +128,7 @@ func NewEvaluatorFactory(
 	return &evaluatorImpl{
 		evaluationTimeout:     cfg.EvaluationTimeout,
 		evaluationResultLimit: cfg.EvaluationResultLimit,
+		keepFrames:            cfg.Screenshots.RenderCharts,
 		dataSourceCache:       datasourceCache,
 		expressionService:     expressionService,
 	}
@@ -260,6 +272,10 @@ type Result struct {
 	// as EvalMatches (from "classic condition"), and in the future from operations
 	// like SSE "math".
 	EvaluationString string
+
+	// HysteresisStreak is set if the condition is a recovery threshold that delays transitions
+	// and the instance is beyond the threshold but has not transitioned yet.
+	HysteresisStreak *expr.HysteresisStreak
 }
 
 func NewResultFromError(err error, evaluatedAt time.Time, duration time.Duration) Result {
@@ -300,6 +316,11 @@ const (
 	// that evaluated to false (Normal) but has not yet met
 	// the KeepFiringFor duration defined in AlertRule.
 	Recovering
+
+	// Suppressed is the state of an alert instance that would be
+	// Pending, Alerting, NoData, Error or Recovering while one of
+	// the dependencies of the AlertRule is firing.
+	Suppressed
 )
 
 func (s State) IsValid() bool {
@@ -307,7 +328,7 @@ func (s State) IsValid() bool {
 }
 
 func (s State) String() string {
-	return [...]string{"Normal", "Alerting", "Pending", "NoData", "Error", "Recovering"}[s]
+	return [...]string{"Normal", "Alerting", "Pending", "NoData", "Error", "Recovering", "Suppressed"}[s]
 }
 
 func ParseStateString(repr string) (State, error) {
@@ -324,6 +345,8 @@ func ParseStateString(repr string) (State, error) {
 		return Error, nil
 	case "recovering":
 		return Recovering, nil
+	case "suppressed":
+		return Suppressed, nil
 	default:
 		return -1, fmt.Errorf("invalid state: %s", repr)
 	}
@@ -402,6 +425,14 @@ func getExprRequest(ctx EvaluationContext, condition models.Condition, dsCacheSe
 					if err != nil {
 						return nil, fmt.Errorf("failed to amend hysteresis command '%s': %w", q.RefID, err)
 					}
+					if streaksReader, ok := reader.(HysteresisStreaksReader); ok {
+						if streaks := streaksReader.ReadHysteresisStreaks(); len(streaks) > 0 {
+							err = q.PatchHysteresisStreaks(streaks)
+							if err != nil {
+								return nil, fmt.Errorf("failed to amend hysteresis command '%s': %w", q.RefID, err)
+							}
+						}
+					}
 				}
 			}
 		}
@@ -784,6 +815,7 @@ func buildResult(f *data.Frame, val *float64, ts time.Time) Result {
 		EvaluationDuration: time.Since(ts),
 		EvaluationString:   extractEvalString(f),
 		Values:             extractValues(f),
+		HysteresisStreak:   expr.GetHysteresisStreak(f),
 	}
 	switch {
 	case val == nil:
@@ -866,6 +898,7 @@ func (e *evaluatorImpl) create(condition models.Condition, req *expr.Request) (C
 				condition:         condition,
 				evalTimeout:       e.evaluationTimeout,
 				evalResultLimit:   e.evaluationResultLimit,
+				keepFrames:        e.keepFrames,
 			}, nil
 		}
 		conditions = append(conditions, node.RefID())
@@ -671,6 +671,33 @@ func TestCreate_HysteresisCommand(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:   "populate with streaks",
+			error:  false,
+			reader: FakeLoadedMetricsReader{fingerprints: map[data.Fingerprint]struct{}{1: {}}, streaks: expr.HysteresisStreaks{2: {Evaluations: 2, Since: time.UnixMilli(1700000000000)}}},
+			condition: func(services services) models.Condition {
+				dsQuery := models.GenerateAlertQuery()
+				ds := &datasources.DataSource{
+					UID:  dsQuery.DatasourceUID,
+					Type: util.GenerateShortUID(),
+				}
+				services.cache.DataSources = append(services.cache.DataSources, ds)
+				services.pluginsStore.PluginList = append(services.pluginsStore.PluginList, pluginstore.Plugin{
+					JSONData: plugins.JSONData{
+						ID:      ds.Type,
+						Backend: true,
+					},
+				})
+
+				return models.Condition{
+					Condition: "B",
+					Data: []models.AlertQuery{
+						dsQuery,
+						models.CreateHysteresisExpression(t, "B", dsQuery.RefID, 4, 1),
+					},
+				}
+			},
+		},
 		{
 			name:   "do nothing if reader is not specified",
 			error:  false,
@@ -728,6 +755,15 @@ func TestCreate_HysteresisCommand(t *testing.T) {
 			} else {
 				require.EqualValues(t, testCase.reader.Read(), cmds[0].LoadedDimensions)
 			}
+			if r, ok := testCase.reader.(FakeLoadedMetricsReader); ok && len(r.streaks) > 0 {
+				require.Len(t, cmds[0].Streaks, len(r.streaks))
+				for fp, streak := range r.streaks {
+					require.Equal(t, streak.Evaluations, cmds[0].Streaks[fp].Evaluations)
+					require.True(t, streak.Since.Equal(cmds[0].Streaks[fp].Since))
+				}
+			} else {
+				require.Empty(t, cmds[0].Streaks)
+			}
 		})
 	}
 }
@@ -1247,6 +1283,36 @@ func TestEvaluate(t *testing.T) {
 	}
 }
 
+func TestEvaluateKeepFrames(t *testing.T) {
+	frames := data.Frames{data.NewFrame("",
+		data.NewField("", data.Labels{"foo": "bar"}, []*float64{util.Pointer(1.0)}),
+	)}
+	resp := backend.QueryDataResponse{Responses: backend.Responses{"A": {Frames: frames}}}
+	cond := models.Condition{Condition: "A", Data: []models.AlertQuery{{RefID: "A", DatasourceUID: expr.DatasourceUID}}}
+
+	for _, keepFrames := range []bool{false, true} {
+		t.Run(fmt.Sprintf("keepFrames=%t", keepFrames), func(t *testing.T) {
+			ev := conditionEvaluator{
+				expressionService: &fakeExpressionService{
+					hook: func(ctx context.Context, now time.Time, pipeline expr.DataPipeline) (*backend.QueryDataResponse, error) {
+						return &resp, nil
+					},
+				},
+				condition:  cond,
+				keepFrames: keepFrames,
+			}
+			results, err := ev.Evaluate(context.Background(), time.Now())
+			require.NoError(t, err)
+			require.Len(t, results, 1)
+			if keepFrames {
+				require.Equal(t, map[string]data.Frames{"A": frames}, results[0].Results)
+			} else {
+				require.Nil(t, results[0].Results)
+			}
+		})
+	}
+}
+
 func TestEvaluateRaw(t *testing.T) {
 	t.Run("should timeout if request takes too long", func(t *testing.T) {
 		unexpectedResponse := &backend.QueryDataResponse{}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
+	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 )
 
@@ -88,8 +89,13 @@ func WithValues(values map[string]NumberValueCapture) ResultMutator {
 
 type FakeLoadedMetricsReader struct {
 	fingerprints map[data.Fingerprint]struct{}
+	streaks      expr.HysteresisStreaks
 }
 
 func (f FakeLoadedMetricsReader) Read() map[data.Fingerprint]struct{} {
 	return f.fingerprints
 }
+
+func (f FakeLoadedMetricsReader) ReadHysteresisStreaks() expr.HysteresisStreaks {
+	return f.streaks
+}
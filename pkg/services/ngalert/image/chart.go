@@ -0,0 +1,88 @@
+package image
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/image/chart"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// ChartImageService draws charts of the results of evaluations of alert rules and saves
+// them in the store. Unlike screenshots, charts do not need the image renderer and can be
+// drawn for alert rules that are not associated with a dashboard panel.
+type ChartImageService struct {
+	imagesDir string
+	logger    log.Logger
+	store     store.ImageStore
+	uploads   *UploadingService
+}
+
+// NewChartImageService returns a new ChartImageService that writes the charts in imagesDir.
+func NewChartImageService(imagesDir string, logger log.Logger, store store.ImageStore, uploads *UploadingService) *ChartImageService {
+	return &ChartImageService{
+		imagesDir: imagesDir,
+		logger:    logger,
+		store:     store,
+		uploads:   uploads,
+	}
+}
+
+// NewImageFromResults returns a chart of the series returned by the queries of the alert rule, its
+// thresholds and the firing window. It returns nil if the results do not contain any time series.
+func (s *ChartImageService) NewImageFromResults(ctx context.Context, r *models.AlertRule, results eval.Results, firing models.TimeWindow) (*models.Image, error) {
+	logger := s.logger.FromContext(ctx).New("rule_uid", r.UID)
+
+	if err := os.MkdirAll(s.imagesDir, 0750); err != nil {
+		return nil, fmt.Errorf("failed to create images directory: %w", err)
+	}
+	path, err := filepath.Abs(filepath.Join(s.imagesDir, util.GenerateShortUID()+".png"))
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create image file: %w", err)
+	}
+	c := chart.FromResults(r, results)
+	c.Firing = firing
+	err = chart.Render(f, c, chart.DefaultWidth, chart.DefaultHeight)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		if removeErr := os.Remove(path); removeErr != nil {
+			logger.Warn("Failed to remove image file", "path", path, "error", removeErr)
+		}
+		if errors.Is(err, chart.ErrNoSeries) {
+			logger.Debug("Cannot draw chart for alert rule as the results have no time series")
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to draw chart: %w", err)
+	}
+	logger.Debug("Drew chart", "path", path)
+	image := models.Image{Path: path}
+
+	// Uploading images is optional
+	if s.uploads != nil {
+		if image, err = s.uploads.Upload(ctx, image); err != nil {
+			logger.Warn("Failed to upload image", "error", err)
+		} else {
+			logger.Debug("Uploaded image", "url", image.URL)
+		}
+	}
+
+	if err := s.store.SaveImage(ctx, &image); err != nil {
+		return nil, fmt.Errorf("failed to save image: %w", err)
+	}
+	logger.Debug("Saved image", "token", image.Token)
+
+	return &image, nil
+}
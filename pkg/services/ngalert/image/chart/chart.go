@@ -0,0 +1,266 @@
+// Package chart draws charts of the results of evaluations of alert rules as PNG images. It does not need the image
+// renderer, so images can be attached to the notifications of any alert rule, including alert rules that are not
+// associated with a dashboard panel.
+package chart
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const (
+	DefaultWidth  = 800
+	DefaultHeight = 400
+
+	marginLeft   = 64
+	marginRight  = 16
+	marginTop    = 16
+	marginBottom = 32
+	// gridLines is the number of intervals between the grid lines of each axis.
+	gridLines = 4
+)
+
+// ErrNoSeries is returned when there is no series with points to draw.
+var ErrNoSeries = errors.New("no series to draw")
+
+var (
+	backgroundColor = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	gridColor       = color.RGBA{R: 225, G: 228, B: 232, A: 255}
+	textColor       = color.RGBA{R: 70, G: 76, B: 84, A: 255}
+	thresholdColor  = color.RGBA{R: 224, G: 47, B: 68, A: 255}
+	firingColor     = color.NRGBA{R: 242, G: 73, B: 92, A: 40}
+	// seriesColors are the colors of the series, in the order of the classic palette of Grafana.
+	seriesColors = []color.RGBA{
+		{R: 115, G: 191, B: 105, A: 255},
+		{R: 242, G: 204, B: 12, A: 255},
+		{R: 138, G: 184, B: 255, A: 255},
+		{R: 255, G: 120, B: 10, A: 255},
+		{R: 184, G: 119, B: 217, A: 255},
+		{R: 87, G: 148, B: 242, A: 255},
+		{R: 250, G: 222, B: 42, A: 255},
+		{R: 150, G: 217, B: 141, A: 255},
+	}
+)
+
+// Point is a value of a series at a time.
+type Point struct {
+	Time  time.Time
+	Value float64
+}
+
+// Series is a time series returned by a query of an alert rule.
+type Series struct {
+	Name   string
+	Points []Point
+}
+
+// Chart is the content of a chart of an alert rule.
+type Chart struct {
+	Series []Series
+	// Thresholds are the values of the thresholds of the alert rule, drawn as dashed horizontal lines.
+	Thresholds []float64
+	// Firing is the window in which the alert rule was firing, drawn as a shaded area. It is not drawn if its start
+	// is zero, and it is clipped to the time range of the series.
+	Firing models.TimeWindow
+}
+
+// Render draws the chart as a PNG image of the given size. The time range of the chart is the time range of the
+// points of the series, and its value range covers the points and the thresholds.
+func Render(w io.Writer, c Chart, width, height int) error {
+	from, to, ok := c.timeRange()
+	if !ok {
+		return ErrNoSeries
+	}
+	if width <= marginLeft+marginRight || height <= marginTop+marginBottom {
+		return fmt.Errorf("image size %dx%d is too small", width, height)
+	}
+	minValue, maxValue := c.valueRange()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: backgroundColor}, image.Point{}, draw.Src)
+	plot := image.Rect(marginLeft, marginTop, width-marginRight, height-marginBottom)
+
+	x := func(t time.Time) int {
+		return plot.Min.X + int(math.Round(float64(plot.Dx()-1)*float64(t.Sub(from))/float64(to.Sub(from))))
+	}
+	y := func(v float64) int {
+		return plot.Max.Y - 1 - int(math.Round(float64(plot.Dy()-1)*(v-minValue)/(maxValue-minValue)))
+	}
+
+	if !c.Firing.Start.IsZero() && c.Firing.Start.Before(to) && (c.Firing.End.IsZero() || c.Firing.End.After(from)) {
+		start, end := c.Firing.Start, c.Firing.End
+		if start.Before(from) {
+			start = from
+		}
+		if end.IsZero() || end.After(to) {
+			end = to
+		}
+		area := image.Rect(x(start), plot.Min.Y, x(end)+1, plot.Max.Y)
+		draw.Draw(img, area, &image.Uniform{C: firingColor}, image.Point{}, draw.Over)
+	}
+
+	for i := 0; i <= gridLines; i++ {
+		v := minValue + (maxValue-minValue)*float64(i)/gridLines
+		gy := y(v)
+		drawHorizontalLine(img, plot.Min.X, plot.Max.X, gy, gridColor, 1)
+		label := formatValue(v)
+		drawText(img, plot.Min.X-6-textWidth(label), gy-glyphHeight*glyphScale/2, label, textColor)
+
+		t := from.Add(time.Duration(float64(to.Sub(from)) * float64(i) / gridLines))
+		gx := x(t)
+		drawVerticalLine(img, gx, plot.Min.Y, plot.Max.Y, gridColor)
+		label = formatTime(t, to.Sub(from))
+		lx := min(max(gx-textWidth(label)/2, 0), width-textWidth(label))
+		drawText(img, lx, plot.Max.Y+8, label, textColor)
+	}
+
+	for i, s := range c.Series {
+		col := seriesColors[i%len(seriesColors)]
+		var prev *image.Point
+		for _, p := range s.Points {
+			if math.IsNaN(p.Value) || math.IsInf(p.Value, 0) {
+				prev = nil
+				continue
+			}
+			cur := image.Point{X: x(p.Time), Y: y(p.Value)}
+			if prev != nil {
+				drawLine(img, *prev, cur, col)
+			} else {
+				drawLine(img, cur, cur, col)
+			}
+			prev = &cur
+		}
+	}
+
+	for _, t := range c.Thresholds {
+		drawHorizontalLine(img, plot.Min.X, plot.Max.X, y(t), thresholdColor, 6)
+	}
+
+	return png.Encode(w, img)
+}
+
+// timeRange returns the time range of the points of the series. It returns false if there are no points.
+func (c Chart) timeRange() (time.Time, time.Time, bool) {
+	var from, to time.Time
+	for _, s := range c.Series {
+		for _, p := range s.Points {
+			if from.IsZero() || p.Time.Before(from) {
+				from = p.Time
+			}
+			if to.IsZero() || p.Time.After(to) {
+				to = p.Time
+			}
+		}
+	}
+	if from.IsZero() {
+		return from, to, false
+	}
+	if !to.After(from) {
+		from, to = from.Add(-time.Minute), to.Add(time.Minute)
+	}
+	return from, to, true
+}
+
+// valueRange returns the range of the values of the points and the thresholds, with a margin.
+func (c Chart) valueRange() (float64, float64) {
+	minValue, maxValue := math.Inf(1), math.Inf(-1)
+	update := func(v float64) {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return
+		}
+		minValue, maxValue = math.Min(minValue, v), math.Max(maxValue, v)
+	}
+	for _, s := range c.Series {
+		for _, p := range s.Points {
+			update(p.Value)
+		}
+	}
+	for _, t := range c.Thresholds {
+		update(t)
+	}
+	if math.IsInf(minValue, 1) {
+		return 0, 1
+	}
+	if minValue == maxValue {
+		return minValue - 1, maxValue + 1
+	}
+	margin := (maxValue - minValue) * 0.05
+	return minValue - margin, maxValue + margin
+}
+
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', 4, 64)
+}
+
+func formatTime(t time.Time, span time.Duration) string {
+	if span > 24*time.Hour {
+		return t.UTC().Format("01-02 15:04")
+	}
+	return t.UTC().Format("15:04")
+}
+
+// drawLine draws a line of two pixels of width with the Bresenham algorithm.
+func drawLine(img *image.RGBA, from, to image.Point, c color.RGBA) {
+	dx, dy := abs(to.X-from.X), -abs(to.Y-from.Y)
+	sx, sy := 1, 1
+	if from.X > to.X {
+		sx = -1
+	}
+	if from.Y > to.Y {
+		sy = -1
+	}
+	e := dx + dy
+	p := from
+	for {
+		img.SetRGBA(p.X, p.Y, c)
+		img.SetRGBA(p.X, p.Y+1, c)
+		if p == to {
+			return
+		}
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			p.X += sx
+		}
+		if e2 <= dx {
+			e += dx
+			p.Y += sy
+		}
+	}
+}
+
+// drawHorizontalLine draws a horizontal line. If dash is greater than 1, the line is dashed with segments of that length.
+func drawHorizontalLine(img *image.RGBA, x0, x1, y int, c color.RGBA, dash int) {
+	for x := x0; x < x1; x++ {
+		if dash > 1 && (x-x0)/dash%2 == 1 {
+			continue
+		}
+		img.SetRGBA(x, y, c)
+		if dash > 1 {
+			img.SetRGBA(x, y+1, c)
+		}
+	}
+}
+
+func drawVerticalLine(img *image.RGBA, x, y0, y1 int, c color.RGBA) {
+	for y := y0; y < y1; y++ {
+		img.SetRGBA(x, y, c)
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
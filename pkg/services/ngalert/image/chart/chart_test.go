@@ -0,0 +1,94 @@
+package chart
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestRender(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	series := Series{Name: "A"}
+	for i := 0; i < 10; i++ {
+		series.Points = append(series.Points, Point{Time: start.Add(time.Duration(i) * time.Minute), Value: float64(i * 10)})
+	}
+
+	t.Run("should draw the series, thresholds and firing window", func(t *testing.T) {
+		c := Chart{
+			Series:     []Series{series},
+			Thresholds: []float64{50},
+			Firing:     models.TimeWindow{Start: start.Add(5 * time.Minute)},
+		}
+		var buf bytes.Buffer
+		require.NoError(t, Render(&buf, c, DefaultWidth, DefaultHeight))
+
+		img, err := png.Decode(&buf)
+		require.NoError(t, err)
+		require.Equal(t, image.Rect(0, 0, DefaultWidth, DefaultHeight), img.Bounds())
+
+		// The firing window is shaded from its start to the end of the chart.
+		inFiring := img.At(DefaultWidth-marginRight-4, marginTop+4)
+		beforeFiring := img.At(marginLeft+4, marginTop+4)
+		require.NotEqual(t, beforeFiring, inFiring)
+	})
+
+	t.Run("should return an error if there are no points", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.ErrorIs(t, Render(&buf, Chart{Series: []Series{{Name: "A"}}}, DefaultWidth, DefaultHeight), ErrNoSeries)
+	})
+
+	t.Run("should draw a single point", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, Render(&buf, Chart{Series: []Series{{Name: "A", Points: series.Points[:1]}}}, DefaultWidth, DefaultHeight))
+	})
+
+	t.Run("should return an error if the image is too small", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.Error(t, Render(&buf, Chart{Series: []Series{series}}, 10, 10))
+	})
+}
+
+func TestFromResults(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	rule := &models.AlertRule{
+		Condition: "C",
+		Data: []models.AlertQuery{
+			{RefID: "A", DatasourceUID: "prometheus", Model: json.RawMessage(`{"expr":"up"}`)},
+			{RefID: "B", DatasourceUID: "__expr__", Model: json.RawMessage(`{"type":"reduce","expression":"A","reducer":"last"}`)},
+			{RefID: "C", DatasourceUID: "__expr__", Model: json.RawMessage(`{"type":"threshold","expression":"B","conditions":[{"evaluator":{"type":"gt","params":[0.5]}}]}`)},
+		},
+	}
+	frame := data.NewFrame("",
+		data.NewField("time", nil, []time.Time{start.Add(time.Minute), start}),
+		data.NewField("value", data.Labels{"instance": "a"}, []*float64{nil, util.Pointer(1.0)}),
+	)
+	results := eval.Results{
+		{Instance: data.Labels{"instance": "a"}, State: eval.Alerting, Results: map[string]data.Frames{
+			"A": {frame},
+			"B": {data.NewFrame("", data.NewField("", data.Labels{"instance": "a"}, []float64{1}))},
+		}},
+	}
+
+	c := FromResults(rule, results)
+	require.Equal(t, []float64{0.5}, c.Thresholds)
+	require.Equal(t, []Series{{
+		Name:   `A {instance=a}`,
+		Points: []Point{{Time: start, Value: 1}},
+	}}, c.Series)
+
+	t.Run("should return no series if the results have no frames", func(t *testing.T) {
+		c := FromResults(rule, eval.Results{{State: eval.NoData}})
+		require.Empty(t, c.Series)
+		require.Equal(t, []float64{0.5}, c.Thresholds)
+	})
+}
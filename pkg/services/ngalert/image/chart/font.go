@@ -0,0 +1,64 @@
+package chart
+
+import (
+	"image"
+	"image/color"
+)
+
+const (
+	glyphWidth  = 3
+	glyphHeight = 5
+	glyphScale  = 2
+	// glyphSpacing is the space between two glyphs, in pixels of the glyphs.
+	glyphSpacing = 1
+)
+
+// glyphs is a bitmap font with the characters of the labels of the axes. Each glyph is a row of three bits per line,
+// the most significant bit being the leftmost pixel.
+var glyphs = map[rune][glyphHeight]uint8{
+	'0': {0b111, 0b101, 0b101, 0b101, 0b111},
+	'1': {0b010, 0b110, 0b010, 0b010, 0b111},
+	'2': {0b111, 0b001, 0b111, 0b100, 0b111},
+	'3': {0b111, 0b001, 0b111, 0b001, 0b111},
+	'4': {0b101, 0b101, 0b111, 0b001, 0b001},
+	'5': {0b111, 0b100, 0b111, 0b001, 0b111},
+	'6': {0b111, 0b100, 0b111, 0b101, 0b111},
+	'7': {0b111, 0b001, 0b010, 0b010, 0b010},
+	'8': {0b111, 0b101, 0b111, 0b101, 0b111},
+	'9': {0b111, 0b101, 0b111, 0b001, 0b111},
+	'.': {0b000, 0b000, 0b000, 0b000, 0b010},
+	'-': {0b000, 0b000, 0b111, 0b000, 0b000},
+	'+': {0b000, 0b010, 0b111, 0b010, 0b000},
+	':': {0b000, 0b010, 0b000, 0b010, 0b000},
+	'e': {0b000, 0b111, 0b111, 0b100, 0b111},
+	' ': {},
+}
+
+// textWidth returns the width of the text in pixels.
+func textWidth(s string) int {
+	n := len([]rune(s))
+	if n == 0 {
+		return 0
+	}
+	return (n*(glyphWidth+glyphSpacing) - glyphSpacing) * glyphScale
+}
+
+// drawText draws the text with its top left corner at the given position. Characters without a glyph are drawn as spaces.
+func drawText(img *image.RGBA, x, y int, s string, c color.RGBA) {
+	for _, r := range s {
+		glyph := glyphs[r]
+		for row, bits := range glyph {
+			for col := 0; col < glyphWidth; col++ {
+				if bits&(1<<(glyphWidth-1-col)) == 0 {
+					continue
+				}
+				for dy := 0; dy < glyphScale; dy++ {
+					for dx := 0; dx < glyphScale; dx++ {
+						img.SetRGBA(x+col*glyphScale+dx, y+row*glyphScale+dy, c)
+					}
+				}
+			}
+		}
+		x += (glyphWidth + glyphSpacing) * glyphScale
+	}
+}
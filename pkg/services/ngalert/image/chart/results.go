@@ -0,0 +1,128 @@
+package chart
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// FromResults returns the chart of the results of an evaluation of the alert rule. The series are the time series
+// returned by the queries of the alert rule, and the thresholds are the parameters of its threshold and classic
+// condition expressions. The results of expressions are not drawn because they are usually reduced to a single value.
+func FromResults(rule *models.AlertRule, results eval.Results) Chart {
+	var frames map[string]data.Frames
+	for _, result := range results {
+		if result.Results != nil {
+			frames = result.Results
+			break
+		}
+	}
+
+	c := Chart{}
+	for _, q := range rule.Data {
+		isExpr, err := q.IsExpression()
+		if err != nil {
+			continue
+		}
+		if isExpr {
+			c.Thresholds = append(c.Thresholds, thresholds(q)...)
+			continue
+		}
+		for _, frame := range frames[q.RefID] {
+			c.Series = append(c.Series, frameSeries(q.RefID, frame)...)
+		}
+	}
+	return c
+}
+
+// thresholds returns the parameters of the evaluators of a threshold or classic condition expression.
+func thresholds(q models.AlertQuery) []float64 {
+	var model map[string]any
+	if err := json.Unmarshal(q.Model, &model); err != nil {
+		return nil
+	}
+	t, err := expr.GetExpressionCommandType(model)
+	if err != nil || (t != expr.TypeThreshold && t != expr.TypeClassicConditions) {
+		return nil
+	}
+
+	var conditions struct {
+		Conditions []struct {
+			Evaluator struct {
+				Params []float64 `json:"params"`
+			} `json:"evaluator"`
+		} `json:"conditions"`
+	}
+	if err := json.Unmarshal(q.Model, &conditions); err != nil {
+		return nil
+	}
+	var result []float64
+	for _, c := range conditions.Conditions {
+		result = append(result, c.Evaluator.Params...)
+	}
+	return result
+}
+
+// frameSeries returns a series for each numeric field of a time series frame. Frames without a time field are ignored.
+func frameSeries(refID string, frame *data.Frame) []Series {
+	if frame == nil {
+		return nil
+	}
+	if frame.TimeSeriesSchema().Type == data.TimeSeriesTypeLong {
+		wide, err := data.LongToWide(frame, nil)
+		if err != nil {
+			return nil
+		}
+		frame = wide
+	}
+	timeIndices := frame.TypeIndices(data.FieldTypeTime, data.FieldTypeNullableTime)
+	if len(timeIndices) == 0 {
+		return nil
+	}
+	timeField := frame.Fields[timeIndices[0]]
+
+	var result []Series
+	for _, field := range frame.Fields {
+		if !field.Type().Numeric() {
+			continue
+		}
+		s := Series{Name: seriesName(refID, frame, field)}
+		for i := 0; i < field.Len(); i++ {
+			tv, ok := timeField.ConcreteAt(i)
+			if !ok {
+				continue
+			}
+			if _, ok := field.ConcreteAt(i); !ok {
+				continue
+			}
+			v, err := field.FloatAt(i)
+			if err != nil {
+				continue
+			}
+			s.Points = append(s.Points, Point{Time: tv.(time.Time), Value: v})
+		}
+		if len(s.Points) == 0 {
+			continue
+		}
+		sort.Slice(s.Points, func(i, j int) bool { return s.Points[i].Time.Before(s.Points[j].Time) })
+		result = append(result, s)
+	}
+	return result
+}
+
+func seriesName(refID string, frame *data.Frame, field *data.Field) string {
+	switch {
+	case len(field.Labels) > 0:
+		return refID + " " + field.Labels.String()
+	case frame.Name != "":
+		return refID + " " + frame.Name
+	default:
+		return refID + " " + field.Name
+	}
+}
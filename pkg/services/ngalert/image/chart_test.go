@@ -0,0 +1,116 @@
+package image
+
+import (
+	"context"
+	"encoding/json"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/screenshot"
+)
+
+func TestChartImageService(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	rule := &models.AlertRule{
+		OrgID: 1,
+		UID:   "foo",
+		Data: []models.AlertQuery{
+			{RefID: "A", DatasourceUID: "prometheus", Model: json.RawMessage(`{"expr":"up"}`)},
+			{RefID: "B", DatasourceUID: "__expr__", Model: json.RawMessage(`{"type":"threshold","expression":"A","conditions":[{"evaluator":{"type":"gt","params":[1]}}]}`)},
+		},
+	}
+	results := eval.Results{{State: eval.Alerting, Results: map[string]data.Frames{
+		"A": {data.NewFrame("",
+			data.NewField("time", nil, []time.Time{start, start.Add(time.Minute)}),
+			data.NewField("value", nil, []float64{0, 2}),
+		)},
+	}}}
+	firing := models.TimeWindow{Start: start.Add(time.Minute)}
+	ctx := context.Background()
+
+	t.Run("chart is drawn and saved to database", func(t *testing.T) {
+		dir := t.TempDir()
+		s := NewChartImageService(dir, log.NewNopLogger(), store.NewFakeImageStore(t), nil)
+
+		image, err := s.NewImageFromResults(ctx, rule, results, firing)
+		require.NoError(t, err)
+		require.NotNil(t, image)
+		assert.NotEmpty(t, image.Token)
+		assert.Equal(t, dir, filepath.Dir(image.Path))
+
+		f, err := os.Open(image.Path)
+		require.NoError(t, err)
+		defer func() { _ = f.Close() }()
+		_, err = png.Decode(f)
+		require.NoError(t, err)
+	})
+
+	t.Run("firing window is shaded", func(t *testing.T) {
+		s := NewChartImageService(t.TempDir(), log.NewNopLogger(), store.NewFakeImageStore(t), nil)
+		results := eval.Results{{State: eval.Alerting, Results: map[string]data.Frames{
+			"A": {data.NewFrame("",
+				data.NewField("time", nil, []time.Time{start, start.Add(time.Minute), start.Add(2 * time.Minute)}),
+				data.NewField("value", nil, []float64{0, 0, 2}),
+			)},
+		}}}
+
+		image, err := s.NewImageFromResults(ctx, rule, results, firing)
+		require.NoError(t, err)
+		require.NotNil(t, image)
+
+		f, err := os.Open(image.Path)
+		require.NoError(t, err)
+		defer func() { _ = f.Close() }()
+		img, err := png.Decode(f)
+		require.NoError(t, err)
+
+		// The alert rule fires from the second point, in the middle of the chart, to the end of the chart.
+		beforeFiring := img.At(150, 20)
+		inFiring := img.At(500, 20)
+		assert.NotEqual(t, beforeFiring, inFiring)
+	})
+
+	t.Run("no image is returned if there are no time series", func(t *testing.T) {
+		dir := t.TempDir()
+		s := NewChartImageService(dir, log.NewNopLogger(), store.NewFakeImageStore(t), nil)
+
+		image, err := s.NewImageFromResults(ctx, rule, eval.Results{{State: eval.NoData}}, firing)
+		require.NoError(t, err)
+		assert.Nil(t, image)
+
+		files, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Empty(t, files)
+	})
+
+	t.Run("chart is drawn if the alert rule is not associated with a dashboard", func(t *testing.T) {
+		images := store.NewFakeImageStore(t)
+		charts := NewChartImageService(t.TempDir(), log.NewNopLogger(), images, nil)
+		s := NewScreenshotImageService(&NoOpCacheService{}, &screenshot.NoOpRateLimiter{}, log.NewNopLogger(),
+			&screenshot.ScreenshotUnavailableService{}, 0, images, nil, charts)
+
+		image, err := s.(*ScreenshotImageService).NewImageFromResults(ctx, rule, results, firing)
+		require.NoError(t, err)
+		require.NotNil(t, image)
+	})
+
+	t.Run("no chart is drawn if charts are disabled", func(t *testing.T) {
+		images := store.NewFakeImageStore(t)
+		s := NewScreenshotImageService(&NoOpCacheService{}, &screenshot.NoOpRateLimiter{}, log.NewNopLogger(),
+			&screenshot.ScreenshotUnavailableService{}, 0, images, nil, nil)
+
+		_, err := s.(*ScreenshotImageService).NewImageFromResults(ctx, rule, results, firing)
+		require.ErrorIs(t, err, models.ErrNoDashboard)
+	})
+}
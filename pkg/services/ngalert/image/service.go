@@ -13,6 +13,7 @@ import (
 	"github.com/grafana/grafana/pkg/components/imguploader"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/rendering"
@@ -45,9 +46,11 @@ type ImageService interface {
 // ScreenshotImageService takes screenshots of the alert rule and saves the
 // image in the store. The image contains a unique token that can be passed
 // as an annotation or label to the Alertmanager. This service cannot take
-// screenshots of alert rules that are not associated with a dashboard panel.
+// screenshots of alert rules that are not associated with a dashboard panel,
+// but it can draw charts of their results instead if charts is not nil.
 type ScreenshotImageService struct {
 	cache             CacheService
+	charts            *ChartImageService
 	limiter           screenshot.RateLimiter
 	logger            log.Logger
 	screenshots       screenshot.ScreenshotService
@@ -65,9 +68,11 @@ func NewScreenshotImageService(
 	screenshots screenshot.ScreenshotService,
 	screenshotTimeout time.Duration,
 	store store.ImageStore,
-	uploads *UploadingService) ImageService {
+	uploads *UploadingService,
+	charts *ChartImageService) ImageService {
 	return &ScreenshotImageService{
 		cache:             cache,
+		charts:            charts,
 		limiter:           limiter,
 		logger:            logger,
 		screenshots:       screenshots,
@@ -87,27 +92,51 @@ func NewScreenshotImageServiceFromCfg(cfg *setting.Cfg, db *store.DBstore, ds da
 		screenshots       screenshot.ScreenshotService = &screenshot.ScreenshotUnavailableService{}
 		screenshotTimeout time.Duration                = 0
 		uploads           *UploadingService            = nil
+		charts            *ChartImageService           = nil
+		logger                                         = log.New("ngalert.image")
 	)
 
+	// Image uploading is an optional feature
+	if (cfg.UnifiedAlerting.Screenshots.Capture || cfg.UnifiedAlerting.Screenshots.RenderCharts) &&
+		cfg.UnifiedAlerting.Screenshots.UploadExternalImageStorage {
+		m, err := imguploader.NewImageUploader(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize uploading screenshot service: %w", err)
+		}
+		uploads = NewUploadingService(m, r)
+	}
+
 	// If screenshots are enabled
 	if cfg.UnifiedAlerting.Screenshots.Capture {
 		cache = NewInmemCacheService(screenshotCacheTTL, r)
 		limiter = screenshot.NewTokenRateLimiter(cfg.UnifiedAlerting.Screenshots.MaxConcurrentScreenshots)
 		screenshots = screenshot.NewHeadlessScreenshotService(cfg, ds, rs, r)
 		screenshotTimeout = cfg.UnifiedAlerting.Screenshots.CaptureTimeout
+	}
 
-		// Image uploading is an optional feature
-		if cfg.UnifiedAlerting.Screenshots.UploadExternalImageStorage {
-			m, err := imguploader.NewImageUploader(cfg)
-			if err != nil {
-				return nil, fmt.Errorf("failed to initialize uploading screenshot service: %w", err)
-			}
-			uploads = NewUploadingService(m, r)
-		}
+	// If charts are enabled
+	if cfg.UnifiedAlerting.Screenshots.RenderCharts {
+		charts = NewChartImageService(cfg.ImagesDir, logger, db, uploads)
 	}
 
-	return NewScreenshotImageService(cache, limiter, log.New("ngalert.image"),
-		screenshots, screenshotTimeout, db, uploads), nil
+	return NewScreenshotImageService(cache, limiter, logger,
+		screenshots, screenshotTimeout, db, uploads, charts), nil
+}
+
+// NewImageFromResults returns a screenshot of the alert rule if screenshots are enabled and the alert
+// rule is associated with a dashboard panel. Otherwise, it returns a chart of the results of the
+// evaluation if charts are enabled.
+func (s *ScreenshotImageService) NewImageFromResults(ctx context.Context, r *models.AlertRule, results eval.Results, firing models.TimeWindow) (*models.Image, error) {
+	image, err := s.NewImage(ctx, r)
+	if s.charts == nil || err == nil {
+		return image, err
+	}
+	if errors.Is(err, screenshot.ErrScreenshotsUnavailable) ||
+		errors.Is(err, models.ErrNoDashboard) ||
+		errors.Is(err, models.ErrNoPanel) {
+		return s.charts.NewImageFromResults(ctx, r, results, firing)
+	}
+	return nil, err
 }
 
 // NewImage returns a screenshot of the alert rule or an error.
@@ -0,0 +1,292 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	alertingModels "github.com/grafana/alerting/models"
+	prommodel "github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// checkEmptyRangeReduce finds reduce expressions that are applied to a query with an empty time range.
+// Such a query never returns samples, so the reduced value is always empty.
+func checkEmptyRangeReduce(r *rule) []Finding {
+	var result []Finding
+	for _, n := range r.expressions(expr.TypeReduce) {
+		in, ok := r.nodes[refID(n.input())]
+		if !ok || in.isExpression || in.isInstant() {
+			continue
+		}
+		tr := in.query.RelativeTimeRange
+		if tr.From > tr.To {
+			continue
+		}
+		result = append(result, Finding{
+			RefID:    n.query.RefID,
+			Check:    CheckEmptyRangeReduce,
+			Severity: SeverityError,
+			Message: fmt.Sprintf("Expression %s reduces query %s, but the time range of the query is empty (from %s to %s ago). Set a time range that covers at least one sample.",
+				n.query.RefID, in.query.RefID, time.Duration(tr.From), time.Duration(tr.To)),
+		})
+	}
+	return result
+}
+
+// checkThresholds finds threshold expressions that are applied to something other than a reduced value
+// and thresholds that do not contribute to the condition of the rule.
+func checkThresholds(r *rule) []Finding {
+	if !r.isAlerting() {
+		return nil
+	}
+	var result []Finding
+	for _, n := range r.expressions(expr.TypeThreshold) {
+		input := refID(n.input())
+		in, ok := r.nodes[input]
+		switch {
+		case !ok:
+			result = append(result, Finding{
+				RefID:    n.query.RefID,
+				Check:    CheckThresholdInput,
+				Severity: SeverityError,
+				Message:  fmt.Sprintf("Threshold %s is applied to %s, which is not a query or an expression of the rule.", n.query.RefID, input),
+			})
+		case !in.isExpression && in.isRange():
+			result = append(result, Finding{
+				RefID:    n.query.RefID,
+				Check:    CheckThresholdInput,
+				Severity: SeverityWarning,
+				Message:  fmt.Sprintf("Threshold %s is applied directly to range query %s. Reduce the query to a single value first, or make it an instant query.", n.query.RefID, input),
+			})
+		}
+
+		if n.query.RefID != r.Condition && !r.isReferenced(n.query.RefID) {
+			result = append(result, Finding{
+				RefID:    n.query.RefID,
+				Check:    CheckUnusedThreshold,
+				Severity: SeverityWarning,
+				Message:  fmt.Sprintf("Threshold %s is not the condition of the rule and no other expression uses it, so it has no effect. The condition is %s.", n.query.RefID, r.Condition),
+			})
+		}
+	}
+	return result
+}
+
+// checkForShorterThanInterval finds rules whose pending period is shorter than the evaluation interval.
+// The pending period is checked only when the rule is evaluated, so such a rule fires on the second evaluation anyway.
+func checkForShorterThanInterval(r *rule) []Finding {
+	if !r.isAlerting() || r.For <= 0 {
+		return nil
+	}
+	interval := time.Duration(r.IntervalSeconds) * time.Second
+	if r.For >= interval {
+		return nil
+	}
+	return []Finding{{
+		Check:    CheckForShorterThanInterval,
+		Severity: SeverityWarning,
+		Message: fmt.Sprintf("The pending period %s is shorter than the evaluation interval %s. The rule fires on the evaluation after the one that made it pending, %s later.",
+			r.For, interval, interval),
+	}}
+}
+
+// annotationLabelRe matches references to labels in templates, such as $labels.instance, .Labels.instance and index $labels "instance".
+var annotationLabelRe = regexp.MustCompile(`(?:\$labels|\.Labels)\.([a-zA-Z_][a-zA-Z0-9_]*)|index\s+(?:\$labels|\.Labels)\s+"([^"]+)"`)
+
+// labelsAddedToAlerts are labels that every alert instance has, regardless of the query.
+var labelsAddedToAlerts = []string{
+	prommodel.AlertNameLabel,
+	alertingModels.FolderTitleLabel,
+	alertingModels.RuleUIDLabel,
+	alertingModels.NamespaceUIDLabel,
+}
+
+// checkAnnotationLabels finds annotations that reference labels that the alert instances do not have.
+// The labels of the instances are known only if every query of the rule is a PromQL query that aggregates
+// its result by a list of labels. In any other case the check is skipped.
+func checkAnnotationLabels(r *rule) []Finding {
+	if !r.isAlerting() || len(r.Annotations) == 0 {
+		return nil
+	}
+	known := map[string]struct{}{}
+	for _, n := range r.queries() {
+		q, err := n.query.GetQuery()
+		if err != nil {
+			return nil
+		}
+		e, err := parser.ParseExpr(q)
+		if err != nil {
+			return nil
+		}
+		lbls, ok := outputLabels(e)
+		if !ok {
+			return nil
+		}
+		for _, l := range lbls {
+			known[l] = struct{}{}
+		}
+	}
+	for l := range r.Labels {
+		known[l] = struct{}{}
+	}
+	for _, l := range labelsAddedToAlerts {
+		known[l] = struct{}{}
+	}
+
+	keys := make([]string, 0, len(r.Annotations))
+	for k := range r.Annotations {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	var result []Finding
+	for _, k := range keys {
+		var missing []string
+		for _, m := range annotationLabelRe.FindAllStringSubmatch(r.Annotations[k], -1) {
+			name := m[1]
+			if name == "" {
+				name = m[2]
+			}
+			if _, ok := known[name]; !ok && !slices.Contains(missing, name) {
+				missing = append(missing, name)
+			}
+		}
+		if len(missing) == 0 {
+			continue
+		}
+		result = append(result, Finding{
+			Check:    CheckAnnotationLabels,
+			Severity: SeverityWarning,
+			Message: fmt.Sprintf("Annotation %q references the labels %s, which the queries of the rule never return. The references are rendered as empty strings.",
+				k, strings.Join(missing, ", ")),
+		})
+	}
+	return result
+}
+
+// outputLabels returns the labels of the series that a PromQL expression returns.
+// It returns false if the labels cannot be determined without running the query.
+func outputLabels(e parser.Expr) ([]string, bool) {
+	switch e := e.(type) {
+	case *parser.ParenExpr:
+		return outputLabels(e.Expr)
+	case *parser.AggregateExpr:
+		// Aggregations with a parameter, such as topk and count_values, keep or add labels.
+		if e.Without || e.Param != nil {
+			return nil, false
+		}
+		return e.Grouping, true
+	case *parser.BinaryExpr:
+		if e.LHS.Type() != parser.ValueTypeVector {
+			return outputLabels(e.RHS)
+		}
+		if e.RHS.Type() != parser.ValueTypeVector {
+			return outputLabels(e.LHS)
+		}
+		if e.VectorMatching != nil && e.VectorMatching.Card != parser.CardOneToOne {
+			return nil, false
+		}
+		return outputLabels(e.LHS)
+	}
+	return nil, false
+}
+
+// checkNoDataState finds rules that filter series with a comparison in the query but report NoData when there are no series.
+// Such a query returns no data whenever the alert condition is not met, so the rule goes into the NoData state instead of Normal.
+func checkNoDataState(r *rule) []Finding {
+	if !r.isAlerting() || r.NoDataState != models.NoData {
+		return nil
+	}
+	var result []Finding
+	for _, n := range r.queries() {
+		q, err := n.query.GetQuery()
+		if err != nil {
+			continue
+		}
+		e, err := parser.ParseExpr(q)
+		if err != nil || !hasFilteringComparison(e) {
+			continue
+		}
+		result = append(result, Finding{
+			RefID:    n.query.RefID,
+			Check:    CheckNoDataState,
+			Severity: SeverityWarning,
+			Message: fmt.Sprintf("Query %s filters series with a comparison and returns no data when no series match, but the rule reports %s when there is no data. Set the no data state to %s if no data means that everything is fine.",
+				n.query.RefID, models.NoData, models.OK),
+		})
+	}
+	return result
+}
+
+// hasFilteringComparison returns true if the expression contains a comparison without the bool modifier,
+// which drops the series that do not satisfy it.
+func hasFilteringComparison(e parser.Expr) bool {
+	found := false
+	parser.Inspect(e, func(node parser.Node, _ []parser.Node) error {
+		if b, ok := node.(*parser.BinaryExpr); ok && b.Op.IsComparisonOperator() && !b.ReturnBool {
+			found = true
+		}
+		return nil
+	})
+	return found
+}
+
+// checkMaxDataPoints finds data source queries that do not set maxDataPoints.
+// The default value is used for them, which might be more or fewer points than the query needs.
+func checkMaxDataPoints(r *rule) []Finding {
+	var result []Finding
+	for _, n := range r.queries() {
+		if v, ok := n.model["maxDataPoints"].(float64); ok && v > 0 {
+			continue
+		}
+		result = append(result, Finding{
+			RefID:    n.query.RefID,
+			Check:    CheckMaxDataPoints,
+			Severity: SeverityInfo,
+			Message:  fmt.Sprintf("Query %s does not set maxDataPoints, so the default value is used.", n.query.RefID),
+		})
+	}
+	return result
+}
+
+// mathVarRe matches references to queries and expressions in math expressions, such as $A and ${A}.
+var mathVarRe = regexp.MustCompile(`\$\{([^}]+)\}|\$([a-zA-Z0-9_]+)`)
+
+// isReferenced returns true if an expression of the rule uses the query or the expression with the given RefID.
+func (r *rule) isReferenced(id string) bool {
+	for _, other := range r.order {
+		n := r.nodes[other]
+		if !n.isExpression || other == id {
+			continue
+		}
+		switch n.cmdType {
+		case expr.TypeMath:
+			for _, m := range mathVarRe.FindAllStringSubmatch(n.input(), -1) {
+				if m[1] == id || m[2] == id {
+					return true
+				}
+			}
+		case expr.TypeClassicConditions:
+			// Classic conditions reference queries in their parameters.
+			if strings.Contains(string(n.query.Model), fmt.Sprintf("%q", id)) {
+				return true
+			}
+		default:
+			if refID(n.input()) == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// refID returns the RefID of a reference to a query or an expression, which may be prefixed with $.
+func refID(s string) string {
+	return strings.TrimPrefix(s, "$")
+}
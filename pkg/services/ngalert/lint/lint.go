@@ -0,0 +1,167 @@
+// Package lint contains checks that find common mistakes in Grafana-managed alert rules.
+// Unlike validation, linting does not reject rules: a rule with findings can be saved and evaluated,
+// but it is likely that it does not behave the way its author expects.
+package lint
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// Severity is the severity of a Finding.
+type Severity string
+
+const (
+	// SeverityInfo is the severity of findings that point at a setting that is likely implicit.
+	SeverityInfo Severity = "info"
+	// SeverityWarning is the severity of findings that are likely a mistake.
+	SeverityWarning Severity = "warning"
+	// SeverityError is the severity of findings that prevent the rule from working.
+	SeverityError Severity = "error"
+)
+
+// Names of the checks.
+const (
+	CheckEmptyRangeReduce       = "empty-range-reduce"
+	CheckThresholdInput         = "threshold-input"
+	CheckUnusedThreshold        = "unused-threshold"
+	CheckForShorterThanInterval = "for-shorter-than-interval"
+	CheckAnnotationLabels       = "annotation-labels"
+	CheckNoDataState            = "no-data-state"
+	CheckMaxDataPoints          = "max-data-points"
+)
+
+// Finding is a problem found in an alert rule.
+type Finding struct {
+	RuleUID   string
+	RuleTitle string
+	// RefID is the query or expression that the finding is about, if any.
+	RefID    string
+	Check    string
+	Severity Severity
+	Message  string
+}
+
+// check finds problems in a single rule.
+type check func(r *rule) []Finding
+
+var checks = []check{
+	checkEmptyRangeReduce,
+	checkThresholds,
+	checkForShorterThanInterval,
+	checkAnnotationLabels,
+	checkNoDataState,
+	checkMaxDataPoints,
+}
+
+// LintRule runs all checks against the rule and returns the findings ordered by severity, most severe first.
+func LintRule(alertRule *models.AlertRule) []Finding {
+	r := newRule(alertRule)
+	var result []Finding
+	for _, c := range checks {
+		for _, f := range c(r) {
+			f.RuleUID = alertRule.UID
+			f.RuleTitle = alertRule.Title
+			result = append(result, f)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return severityOrder(result[i].Severity) < severityOrder(result[j].Severity)
+	})
+	return result
+}
+
+func severityOrder(s Severity) int {
+	switch s {
+	case SeverityError:
+		return 0
+	case SeverityWarning:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// node is a query or an expression of a rule with its model decoded.
+type node struct {
+	query        models.AlertQuery
+	isExpression bool
+	cmdType      expr.CommandType
+	model        map[string]any
+}
+
+// input returns the RefID of the node that a reduce or threshold expression is applied to.
+func (n *node) input() string {
+	s, _ := n.model["expression"].(string)
+	return s
+}
+
+// isInstant returns true if the node is a data source query that requests only the latest sample.
+func (n *node) isInstant() bool {
+	instant, _ := n.model["instant"].(bool)
+	isRange, _ := n.model["range"].(bool)
+	return instant && !isRange
+}
+
+// isRange returns true if the node is a data source query that requests all samples of its time range.
+func (n *node) isRange() bool {
+	isRange, _ := n.model["range"].(bool)
+	return isRange
+}
+
+type rule struct {
+	*models.AlertRule
+	nodes map[string]*node
+	// order is the RefIDs of the nodes in the order in which they are defined in the rule.
+	order []string
+}
+
+func newRule(alertRule *models.AlertRule) *rule {
+	r := &rule{
+		AlertRule: alertRule,
+		nodes:     make(map[string]*node, len(alertRule.Data)),
+	}
+	for _, q := range alertRule.Data {
+		n := &node{query: q}
+		n.isExpression, _ = q.IsExpression()
+		// Rules with invalid models are rejected by validation, so they are only skipped here.
+		_ = json.Unmarshal(q.Model, &n.model)
+		if n.isExpression {
+			t, _ := n.model["type"].(string)
+			n.cmdType, _ = expr.ParseCommandType(t)
+		}
+		r.nodes[q.RefID] = n
+		r.order = append(r.order, q.RefID)
+	}
+	return r
+}
+
+// isAlerting returns true if the rule is an alerting rule and not a recording rule.
+func (r *rule) isAlerting() bool {
+	return r.Record == nil
+}
+
+// queries returns the data source queries of the rule.
+func (r *rule) queries() []*node {
+	var result []*node
+	for _, refID := range r.order {
+		if n := r.nodes[refID]; !n.isExpression {
+			result = append(result, n)
+		}
+	}
+	return result
+}
+
+// expressions returns the expressions of the rule with the given type.
+func (r *rule) expressions(t expr.CommandType) []*node {
+	var result []*node
+	for _, refID := range r.order {
+		if n := r.nodes[refID]; n.isExpression && n.cmdType == t {
+			result = append(result, n)
+		}
+	}
+	return result
+}
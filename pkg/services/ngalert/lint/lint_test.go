@@ -0,0 +1,169 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func thresholdExpression(refID, inputRefID string) models.AlertQuery {
+	return models.AlertQuery{
+		RefID:         refID,
+		QueryType:     expr.DatasourceType,
+		DatasourceUID: expr.DatasourceUID,
+		Model: json.RawMessage(fmt.Sprintf(`{
+			"refId": %q,
+			"type": "threshold",
+			"expression": %q,
+			"conditions": [{"evaluator": {"params": [1], "type": "gt"}}]
+		}`, refID, inputRefID)),
+	}
+}
+
+func rangeQuery(refID, q string) models.AlertQuery {
+	query := models.CreatePrometheusQuery(refID, q, 1000, 43200, false, "prom")
+	query.RelativeTimeRange = models.RelativeTimeRange{From: models.Duration(10 * time.Minute)}
+	return query
+}
+
+// validRule returns a rule without findings.
+func validRule() *models.AlertRule {
+	return &models.AlertRule{
+		UID:             "uid",
+		Title:           "High error rate",
+		Condition:       "C",
+		IntervalSeconds: 60,
+		For:             5 * time.Minute,
+		NoDataState:     models.OK,
+		Data: []models.AlertQuery{
+			rangeQuery("A", "sum by (instance) (rate(errors_total[5m]))"),
+			models.CreateReduceExpression("B", "A", "last"),
+			thresholdExpression("C", "B"),
+		},
+		Annotations: map[string]string{"summary": "{{ $labels.instance }} fails"},
+		Labels:      map[string]string{"team": "a"},
+	}
+}
+
+func checksOf(findings []Finding) []string {
+	result := make([]string, 0, len(findings))
+	for _, f := range findings {
+		result = append(result, f.Check)
+	}
+	return result
+}
+
+func TestLintRule(t *testing.T) {
+	t.Run("should return no findings for a valid rule", func(t *testing.T) {
+		require.Empty(t, LintRule(validRule()))
+	})
+
+	t.Run("should find a reduce over an empty range", func(t *testing.T) {
+		r := validRule()
+		r.Data[0].RelativeTimeRange = models.RelativeTimeRange{}
+
+		findings := LintRule(r)
+		require.Len(t, findings, 1)
+		require.Equal(t, Finding{
+			RuleUID:   "uid",
+			RuleTitle: "High error rate",
+			RefID:     "B",
+			Check:     CheckEmptyRangeReduce,
+			Severity:  SeverityError,
+			Message:   "Expression B reduces query A, but the time range of the query is empty (from 0s to 0s ago). Set a time range that covers at least one sample.",
+		}, findings[0])
+	})
+
+	t.Run("should find thresholds on the wrong refID", func(t *testing.T) {
+		r := validRule()
+		r.Condition = "B"
+		r.Data[2] = thresholdExpression("C", "A")
+		r.Data = append(r.Data, thresholdExpression("D", "X"))
+
+		findings := LintRule(r)
+		require.Equal(t, []string{CheckThresholdInput, CheckThresholdInput, CheckUnusedThreshold, CheckUnusedThreshold}, checksOf(findings))
+		require.Equal(t, SeverityError, findings[0].Severity)
+		require.Equal(t, "D", findings[0].RefID)
+		require.Equal(t, "C", findings[1].RefID)
+	})
+
+	t.Run("should not report thresholds that are used by other expressions", func(t *testing.T) {
+		r := validRule()
+		r.Condition = "D"
+		r.Data = append(r.Data, models.AlertQuery{
+			RefID:         "D",
+			DatasourceUID: expr.DatasourceUID,
+			Model:         json.RawMessage(`{"refId": "D", "type": "math", "expression": "${C} && $B > 0"}`),
+		})
+		require.Empty(t, LintRule(r))
+	})
+
+	t.Run("should find For shorter than the interval", func(t *testing.T) {
+		r := validRule()
+		r.For = 30 * time.Second
+		require.Equal(t, []string{CheckForShorterThanInterval}, checksOf(LintRule(r)))
+
+		r.For = 0
+		require.Empty(t, LintRule(r))
+	})
+
+	t.Run("should find annotations that reference labels the query does not return", func(t *testing.T) {
+		r := validRule()
+		r.Annotations = map[string]string{
+			"summary":     `{{ $labels.instance }} on {{ $labels.job }} of {{ $labels.team }}`,
+			"description": `{{ index $labels "pod" }} {{ .Labels.alertname }}`,
+		}
+
+		findings := LintRule(r)
+		require.Equal(t, []string{CheckAnnotationLabels, CheckAnnotationLabels}, checksOf(findings))
+		require.Contains(t, findings[0].Message, `"description" references the labels pod,`)
+		require.Contains(t, findings[1].Message, `"summary" references the labels job,`)
+	})
+
+	t.Run("should skip the annotation check if the labels of the query are unknown", func(t *testing.T) {
+		r := validRule()
+		r.Data[0] = rangeQuery("A", "rate(errors_total[5m])")
+		r.Annotations = map[string]string{"summary": "{{ $labels.job }}"}
+		require.Empty(t, LintRule(r))
+	})
+
+	t.Run("should find filtering queries with the NoData state", func(t *testing.T) {
+		r := validRule()
+		r.NoDataState = models.NoData
+		require.Empty(t, LintRule(r))
+
+		r.Data[0] = rangeQuery("A", "sum by (instance) (rate(errors_total[5m])) > 0.1")
+		findings := LintRule(r)
+		require.Equal(t, []string{CheckNoDataState}, checksOf(findings))
+		require.Equal(t, "A", findings[0].RefID)
+
+		r.Data[0] = rangeQuery("A", "sum by (instance) (rate(errors_total[5m])) > bool 0.1")
+		require.Empty(t, LintRule(r))
+	})
+
+	t.Run("should find queries without maxDataPoints", func(t *testing.T) {
+		r := validRule()
+		r.Data[0].Model = json.RawMessage(`{"refId": "A", "expr": "sum by (instance) (errors_total)", "range": true}`)
+
+		findings := LintRule(r)
+		require.Equal(t, []string{CheckMaxDataPoints}, checksOf(findings))
+		require.Equal(t, SeverityInfo, findings[0].Severity)
+	})
+
+	t.Run("should only run query checks for recording rules", func(t *testing.T) {
+		r := validRule()
+		r.Record = &models.Record{Metric: "errors", From: "A"}
+		r.For = time.Second
+		r.NoDataState = models.NoData
+		r.Data = []models.AlertQuery{rangeQuery("A", "errors_total > 1")}
+		r.Data[0].Model = json.RawMessage(`{"refId": "A", "expr": "errors_total > 1"}`)
+
+		require.Equal(t, []string{CheckMaxDataPoints}, checksOf(LintRule(r)))
+	})
+}
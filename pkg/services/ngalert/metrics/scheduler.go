@@ -33,6 +33,9 @@ type Scheduler struct {
 	EvaluationMissed                    *prometheus.CounterVec
 	SimplifiedEditorRules               *prometheus.GaugeVec
 	PrometheusImportedRules             *prometheus.GaugeVec
+	ShardOwnedAlertRules                prometheus.Gauge
+	ShardMembers                        prometheus.Gauge
+	ShardRuleReassignments              *prometheus.CounterVec
 }
 
 func NewSchedulerMetrics(r prometheus.Registerer) *Scheduler {
@@ -202,5 +205,30 @@ func NewSchedulerMetrics(r prometheus.Registerer) *Scheduler {
 			},
 			[]string{"org"},
 		),
+		ShardOwnedAlertRules: promauto.With(r).NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: Namespace,
+				Subsystem: Subsystem,
+				Name:      "schedule_shard_alert_rules",
+				Help:      "The number of alert rules assigned to this replica when the evaluation is sharded across replicas.",
+			},
+		),
+		ShardMembers: promauto.With(r).NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: Namespace,
+				Subsystem: Subsystem,
+				Name:      "schedule_shard_members",
+				Help:      "The number of replicas that share the evaluation of alert rules.",
+			},
+		),
+		ShardRuleReassignments: promauto.With(r).NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: Namespace,
+				Subsystem: Subsystem,
+				Name:      "schedule_shard_rule_reassignments_total",
+				Help:      "The total number of alert rules that this replica acquired or released because the replicas changed.",
+			},
+			[]string{"direction"},
+		),
 	}
 }
@@ -146,6 +146,17 @@ func (aq *AlertQuery) PatchHysteresisExpression(loadedMetrics map[data.Fingerpri
 	return expr.SetLoadedDimensionsToHysteresisCommand(aq.modelProps, loadedMetrics)
 }
 
+// PatchHysteresisStreaks updates the AlertQuery to include the streaks of the previous evaluation into hysteresis
+func (aq *AlertQuery) PatchHysteresisStreaks(streaks expr.HysteresisStreaks) error {
+	if aq.modelProps == nil {
+		err := aq.setModelProps()
+		if err != nil {
+			return err
+		}
+	}
+	return expr.SetHysteresisStreaksToHysteresisCommand(aq.modelProps, streaks)
+}
+
 // setMaxDatapoints sets the model maxDataPoints if it's missing or invalid
 func (aq *AlertQuery) setMaxDatapoints() error {
 	if aq.modelProps == nil {
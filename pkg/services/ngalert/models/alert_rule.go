@@ -138,6 +138,13 @@ const (
 	// StateReasonAnnotation is the name of the annotation that explains the difference between evaluation state and alert state (i.e. changing state when NoData or Error).
 	StateReasonAnnotation = GrafanaReservedLabelPrefix + "state_reason"
 
+	// AcknowledgedByAnnotation is the name of the annotation that contains the login of the user who acknowledged the alert.
+	AcknowledgedByAnnotation = GrafanaReservedLabelPrefix + "acknowledged_by"
+	// AcknowledgementCommentAnnotation is the name of the annotation that contains the comment of the acknowledgement.
+	AcknowledgementCommentAnnotation = GrafanaReservedLabelPrefix + "acknowledgement_comment"
+	// AcknowledgedUntilAnnotation is the name of the annotation that contains the expiry of the acknowledgement in RFC3339 format.
+	AcknowledgedUntilAnnotation = GrafanaReservedLabelPrefix + "acknowledged_until"
+
 	// MigratedLabelPrefix is a label prefix for all labels created during legacy migration.
 	MigratedLabelPrefix = "__legacy_"
 	// MigratedUseLegacyChannelsLabel is created during legacy migration to route to separate nested policies for migrated channels.
@@ -164,6 +171,11 @@ const (
 	// AutogeneratedRouteSettingsHashLabel a label name that contains the hash of the notification settings that will be used to send notifications for the alert.
 	// This should uniquely identify the notification settings (group_by, group_wait, group_interval, repeat_interval, mute_time_intervals) for the alert.
 	AutogeneratedRouteSettingsHashLabel = "__grafana_route_settings_hash__"
+
+	// EscalationPolicyLabel a label name that contains the name of the escalation policy of the alerts that are sent to the receivers of its steps.
+	EscalationPolicyLabel = "__grafana_escalation_policy__"
+	// EscalationStepLabel a label name that contains the number of the step of the escalation policy the alert is sent to, starting at 1.
+	EscalationStepLabel = "__grafana_escalation_step__"
 )
 
 const (
@@ -197,6 +209,8 @@ var (
 		AutogeneratedRouteLabel:             {},
 		AutogeneratedRouteReceiverNameLabel: {},
 		AutogeneratedRouteSettingsHashLabel: {},
+		EscalationPolicyLabel:               {},
+		EscalationStepLabel:                 {},
 	}
 )
 
@@ -304,6 +318,8 @@ type AlertRule struct {
 	// If nil, alerts resolve after 2 missing evaluation intervals
 	// (i.e., resolution occurs during the second evaluation where data is absent).
 	MissingSeriesEvalsToResolve *int
+	// Dependencies are the alert rules that suppress the instances of this rule while they are firing.
+	Dependencies []AlertRuleDependency
 }
 
 type AlertRuleMetadata struct {
@@ -689,6 +705,10 @@ func validateAlertRuleFields(rule *AlertRule) error {
 		return errors.New("field `missing_series_evals_to_resolve` must be greater than 0")
 	}
 
+	if err := validateDependencies(rule); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -798,8 +818,9 @@ func (alertRule *AlertRule) Copy() *AlertRule {
 
 	if alertRule.Record != nil {
 		result.Record = &Record{
-			From:   alertRule.Record.From,
-			Metric: alertRule.Record.Metric,
+			From:                alertRule.Record.From,
+			Metric:              alertRule.Record.Metric,
+			TargetDatasourceUID: alertRule.Record.TargetDatasourceUID,
 		}
 	}
 
@@ -812,6 +833,8 @@ func (alertRule *AlertRule) Copy() *AlertRule {
 		result.NotificationSettings = append(result.NotificationSettings, CopyNotificationSettings(s))
 	}
 
+	result.Dependencies = CopyAlertRuleDependencies(alertRule.Dependencies)
+
 	return &result
 }
 
@@ -823,6 +846,7 @@ func ClearRecordingRuleIgnoredFields(rule *AlertRule) {
 	rule.KeepFiringFor = 0
 	rule.NotificationSettings = nil
 	rule.MissingSeriesEvalsToResolve = nil
+	rule.Dependencies = nil
 }
 
 // GetAlertRuleByUIDQuery is the query for retrieving/deleting an alert rule by UID and organisation ID.
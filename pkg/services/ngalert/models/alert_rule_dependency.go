@@ -0,0 +1,79 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+)
+
+// AlertRuleDependency is a dependency of an alert rule on another alert rule of the same organization, the parent.
+// While an instance of the parent that matches the matchers is firing, including in the NoData and Error states that
+// are sent to the Alertmanager as firing alerts, the instances of the dependent rule that would be pending, firing,
+// or in the NoData or Error states are Suppressed instead, and no notifications are sent for them.
+type AlertRuleDependency struct {
+	// RuleUID is the UID of the parent rule.
+	RuleUID string `json:"rule_uid"`
+	// Matchers select the instances of the parent rule by their labels, in the format of the matchers of silences,
+	// for example `datacenter="eu-west-1"`. If empty, all the instances of the parent rule are selected.
+	Matchers []string `json:"matchers,omitempty"`
+}
+
+// Validate returns an error if the dependency has no rule UID or one of its matchers is invalid.
+func (d AlertRuleDependency) Validate() error {
+	if d.RuleUID == "" {
+		return errors.New("rule UID is required")
+	}
+	if _, err := d.LabelMatchers(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// LabelMatchers returns the parsed matchers of the dependency.
+func (d AlertRuleDependency) LabelMatchers() (labels.Matchers, error) {
+	result := make(labels.Matchers, 0, len(d.Matchers))
+	for _, s := range d.Matchers {
+		m, err := labels.ParseMatcher(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid matcher %q: %w", s, err)
+		}
+		result = append(result, m)
+	}
+	return result, nil
+}
+
+// CopyAlertRuleDependencies returns a deep copy of the dependencies.
+func CopyAlertRuleDependencies(deps []AlertRuleDependency) []AlertRuleDependency {
+	if deps == nil {
+		return nil
+	}
+	result := make([]AlertRuleDependency, 0, len(deps))
+	for _, d := range deps {
+		c := AlertRuleDependency{RuleUID: d.RuleUID}
+		if d.Matchers != nil {
+			c.Matchers = append([]string{}, d.Matchers...)
+		}
+		result = append(result, c)
+	}
+	return result
+}
+
+// validateDependencies returns an error if a dependency is invalid, the rule depends on itself, or it depends more
+// than once on the same rule.
+func validateDependencies(rule *AlertRule) error {
+	seen := make(map[string]struct{}, len(rule.Dependencies))
+	for _, d := range rule.Dependencies {
+		if err := d.Validate(); err != nil {
+			return fmt.Errorf("invalid dependency: %w", err)
+		}
+		if d.RuleUID == rule.UID {
+			return errors.New("alert rule cannot depend on itself")
+		}
+		if _, ok := seen[d.RuleUID]; ok {
+			return fmt.Errorf("alert rule depends more than once on rule %s", d.RuleUID)
+		}
+		seen[d.RuleUID] = struct{}{}
+	}
+	return nil
+}
@@ -0,0 +1,258 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+var (
+	errAlertRuleTemplateConflictMsg  = "conflicting alert rule template found [uid: '{{ .Public.UID }}', namespace_uid: '{{ .Public.NamespaceUID }}', rule_group: '{{ .Public.RuleGroup }}']: {{ .Public.Error }}"
+	ErrAlertRuleTemplateConflictBase = errutil.Conflict("alerting.alert-rule-template.conflict").
+						MustTemplate(errAlertRuleTemplateConflictMsg, errutil.WithPublic(errAlertRuleTemplateConflictMsg))
+	ErrAlertRuleTemplateNotFound = errutil.NotFound("alerting.alert-rule-template.notFound", errutil.WithPublicMessage("alert rule template not found"))
+	// ErrAlertRuleTemplateFailedValidation is an error for an invalid alert rule template.
+	ErrAlertRuleTemplateFailedValidation = errors.New("invalid alert rule template")
+	// ErrAlertRuleGroupOwnedByTemplate is an error for a change to the rules of a template made outside of the template.
+	ErrAlertRuleGroupOwnedByTemplate = errors.New("the rule group belongs to the alert rule template, change the template instead")
+)
+
+func ErrAlertRuleTemplateConflict(template AlertRuleTemplate, underlying error) error {
+	return ErrAlertRuleTemplateConflictBase.Build(errutil.TemplateData{Public: map[string]any{"UID": template.UID, "NamespaceUID": template.NamespaceUID, "RuleGroup": template.RuleGroup, "Error": underlying.Error()}, Error: underlying})
+}
+
+// templateParameterRegexp matches a placeholder ${name} in the fields of an alert rule template.
+var templateParameterRegexp = regexp.MustCompile(`\$\{([a-zA-Z_][a-zA-Z0-9_]*)\}`)
+
+var templateParameterNameRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// AlertRuleTemplate is a parameterized alert rule that is expanded into one alert rule per instance.
+// The rules expanded from the template form the rule group of the template, and they are
+// evaluated as any other rule. Changes to the template are propagated to all rules of the group.
+type AlertRuleTemplate struct {
+	ID              int64
+	OrgID           int64
+	UID             string
+	NamespaceUID    string
+	RuleGroup       string
+	IntervalSeconds int64
+	// Parameters are the names of the placeholders that can be used in the rule as ${name}.
+	Parameters []string
+	// Rule is the rule with placeholders. Placeholders are replaced in the title, the query models,
+	// the labels, the annotations and the metric of the recording rule.
+	Rule      AlertRule
+	Instances []AlertRuleTemplateInstance
+	Version   int64
+	Updated   time.Time
+	UpdatedBy *UserUID
+}
+
+// AlertRuleTemplateInstance is a set of values of the parameters of a template. It becomes the alert rule with the same UID.
+type AlertRuleTemplateInstance struct {
+	UID    string
+	Values map[string]string
+}
+
+// AlertRuleTemplateWithFolderFullpath extends AlertRuleTemplate with the full path of its folder.
+type AlertRuleTemplateWithFolderFullpath struct {
+	*AlertRuleTemplate
+	FolderFullpath string
+}
+
+// ListAlertRuleTemplatesQuery is the query for listing alert rule templates of an organization.
+type ListAlertRuleTemplatesQuery struct {
+	OrgID         int64
+	NamespaceUIDs []string
+	RuleGroups    []string
+}
+
+func (t *AlertRuleTemplate) ResourceType() string {
+	return "alertRuleTemplate"
+}
+
+func (t *AlertRuleTemplate) ResourceID() string {
+	return t.UID
+}
+
+func (t *AlertRuleTemplate) GetGroupKey() AlertRuleGroupKey {
+	return AlertRuleGroupKey{OrgID: t.OrgID, NamespaceUID: t.NamespaceUID, RuleGroup: t.RuleGroup}
+}
+
+// Validate checks that the parameters and instances of the template are consistent.
+// The expanded rules are validated by the alert rule service.
+func (t *AlertRuleTemplate) Validate() error {
+	if t.NamespaceUID == "" {
+		return fmt.Errorf("%w: folderUID must be set", ErrAlertRuleTemplateFailedValidation)
+	}
+	if t.RuleGroup == "" {
+		return fmt.Errorf("%w: rule group must be set", ErrAlertRuleTemplateFailedValidation)
+	}
+	params := make(map[string]struct{}, len(t.Parameters))
+	for _, p := range t.Parameters {
+		if !templateParameterNameRegexp.MatchString(p) {
+			return fmt.Errorf("%w: invalid parameter name '%s', it must match %s", ErrAlertRuleTemplateFailedValidation, p, templateParameterNameRegexp)
+		}
+		if _, ok := params[p]; ok {
+			return fmt.Errorf("%w: parameter '%s' is declared more than once", ErrAlertRuleTemplateFailedValidation, p)
+		}
+		params[p] = struct{}{}
+	}
+
+	uids := make(map[string]struct{}, len(t.Instances))
+	for idx, instance := range t.Instances {
+		if instance.UID != "" {
+			if err := util.ValidateUID(instance.UID); err != nil {
+				return fmt.Errorf("%w: instance %d has invalid UID: %w", ErrAlertRuleTemplateFailedValidation, idx, err)
+			}
+			if _, ok := uids[instance.UID]; ok {
+				return fmt.Errorf("%w: UID '%s' is used by more than one instance", ErrAlertRuleTemplateFailedValidation, instance.UID)
+			}
+			uids[instance.UID] = struct{}{}
+		}
+		for p := range params {
+			if _, ok := instance.Values[p]; !ok {
+				return fmt.Errorf("%w: instance %d has no value for parameter '%s'", ErrAlertRuleTemplateFailedValidation, idx, p)
+			}
+		}
+		for p := range instance.Values {
+			if _, ok := params[p]; !ok {
+				return fmt.Errorf("%w: instance %d has a value for undeclared parameter '%s'", ErrAlertRuleTemplateFailedValidation, idx, p)
+			}
+		}
+	}
+
+	titles := make(map[string]int, len(t.Instances))
+	for idx, instance := range t.Instances {
+		title := expandTemplateString(t.Rule.Title, instance.Values)
+		if other, ok := titles[title]; ok {
+			return fmt.Errorf("%w: instances %d and %d expand to the same title '%s', use a parameter in the title", ErrAlertRuleTemplateFailedValidation, other, idx, title)
+		}
+		titles[title] = idx
+	}
+	return nil
+}
+
+// Expand returns the rules of the template, one per instance in the order of instances.
+func (t *AlertRuleTemplate) Expand() ([]AlertRule, error) {
+	result := make([]AlertRule, 0, len(t.Instances))
+	for idx, instance := range t.Instances {
+		rule, err := t.expandInstance(instance)
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to expand instance %d: %w", ErrAlertRuleTemplateFailedValidation, idx, err)
+		}
+		rule.RuleGroupIndex = idx + 1
+		result = append(result, rule)
+	}
+	return result, nil
+}
+
+func (t *AlertRuleTemplate) expandInstance(instance AlertRuleTemplateInstance) (AlertRule, error) {
+	rule := *t.Rule.Copy()
+	rule.ID = 0
+	rule.GUID = ""
+	rule.Version = 0
+	rule.UID = instance.UID
+	rule.OrgID = t.OrgID
+	rule.NamespaceUID = t.NamespaceUID
+	rule.RuleGroup = t.RuleGroup
+	rule.IntervalSeconds = t.IntervalSeconds
+
+	rule.Title = expandTemplateString(rule.Title, instance.Values)
+	for k, v := range rule.Labels {
+		rule.Labels[k] = expandTemplateString(v, instance.Values)
+	}
+	for k, v := range rule.Annotations {
+		rule.Annotations[k] = expandTemplateString(v, instance.Values)
+	}
+	if rule.Record != nil {
+		rule.Record.Metric = expandTemplateString(rule.Record.Metric, instance.Values)
+	}
+	for i := range rule.Data {
+		m, err := expandTemplateModel(rule.Data[i].Model, instance.Values)
+		if err != nil {
+			return AlertRule{}, fmt.Errorf("query %s: %w", rule.Data[i].RefID, err)
+		}
+		rule.Data[i].Model = m
+	}
+	return rule, nil
+}
+
+// expandTemplateString replaces the placeholders of the parameters that have a value. Other placeholders are left as is.
+func expandTemplateString(s string, values map[string]string) string {
+	if !strings.Contains(s, "${") {
+		return s
+	}
+	return templateParameterRegexp.ReplaceAllStringFunc(s, func(match string) string {
+		name := match[2 : len(match)-1]
+		if v, ok := values[name]; ok {
+			return v
+		}
+		return match
+	})
+}
+
+// expandTemplateModel replaces the placeholders in the strings of the query model.
+// A string that is exactly a placeholder is replaced by a number or a boolean if the value is one,
+// so that a parameter can be used as a threshold of an expression.
+func expandTemplateModel(model json.RawMessage, values map[string]string) (json.RawMessage, error) {
+	if !bytes.Contains(model, []byte("${")) {
+		return model, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(model))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("failed to parse model: %w", err)
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(expandTemplateValue(v, values)); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+func expandTemplateValue(v any, values map[string]string) any {
+	switch val := v.(type) {
+	case string:
+		if m := templateParameterRegexp.FindStringSubmatch(val); m != nil && m[0] == val {
+			if raw, ok := values[m[1]]; ok && IsTemplateValueScalar(raw) {
+				return json.RawMessage(raw)
+			}
+		}
+		return expandTemplateString(val, values)
+	case map[string]any:
+		for k, item := range val {
+			val[k] = expandTemplateValue(item, values)
+		}
+		return val
+	case []any:
+		for i, item := range val {
+			val[i] = expandTemplateValue(item, values)
+		}
+		return val
+	default:
+		return v
+	}
+}
+
+// IsTemplateValueScalar returns true if the value replaces a placeholder in a query model by a number or a boolean.
+func IsTemplateValueScalar(value string) bool {
+	var v any
+	if err := json.Unmarshal([]byte(value), &v); err != nil {
+		return false
+	}
+	switch v.(type) {
+	case float64, bool:
+		return true
+	}
+	return false
+}
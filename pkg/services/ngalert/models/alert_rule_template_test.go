@@ -0,0 +1,111 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertRuleTemplateValidate(t *testing.T) {
+	valid := func() AlertRuleTemplate {
+		return AlertRuleTemplate{
+			OrgID:        1,
+			UID:          "template",
+			NamespaceUID: "folder",
+			RuleGroup:    "group",
+			Parameters:   []string{"service", "threshold"},
+			Rule:         AlertRule{Title: "High latency of ${service}"},
+			Instances: []AlertRuleTemplateInstance{
+				{UID: "api", Values: map[string]string{"service": "api", "threshold": "0.5"}},
+				{UID: "web", Values: map[string]string{"service": "web", "threshold": "1"}},
+			},
+		}
+	}
+	require.NoError(t, func() error { tmpl := valid(); return tmpl.Validate() }())
+
+	testCases := []struct {
+		name   string
+		mutate func(*AlertRuleTemplate)
+	}{
+		{name: "empty folder", mutate: func(t *AlertRuleTemplate) { t.NamespaceUID = "" }},
+		{name: "empty group", mutate: func(t *AlertRuleTemplate) { t.RuleGroup = "" }},
+		{name: "invalid parameter name", mutate: func(t *AlertRuleTemplate) { t.Parameters = append(t.Parameters, "1st") }},
+		{name: "duplicated parameter", mutate: func(t *AlertRuleTemplate) { t.Parameters = append(t.Parameters, "service") }},
+		{name: "invalid instance UID", mutate: func(t *AlertRuleTemplate) { t.Instances[0].UID = "not/valid" }},
+		{name: "duplicated instance UID", mutate: func(t *AlertRuleTemplate) { t.Instances[1].UID = "api" }},
+		{name: "missing value", mutate: func(t *AlertRuleTemplate) { delete(t.Instances[0].Values, "threshold") }},
+		{name: "undeclared parameter", mutate: func(t *AlertRuleTemplate) { t.Instances[0].Values["region"] = "eu" }},
+		{name: "same title", mutate: func(t *AlertRuleTemplate) { t.Rule.Title = "High latency" }},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpl := valid()
+			tc.mutate(&tmpl)
+			require.ErrorIs(t, tmpl.Validate(), ErrAlertRuleTemplateFailedValidation)
+		})
+	}
+}
+
+func TestAlertRuleTemplateExpand(t *testing.T) {
+	tmpl := AlertRuleTemplate{
+		OrgID:           1,
+		UID:             "template",
+		NamespaceUID:    "folder",
+		RuleGroup:       "group",
+		IntervalSeconds: 60,
+		Parameters:      []string{"service", "threshold"},
+		Rule: AlertRule{
+			UID:         "ignored",
+			Title:       "High latency of ${service}",
+			Condition:   "B",
+			Labels:      map[string]string{"service": "${service}", "team": "sre"},
+			Annotations: map[string]string{"summary": "{{ $labels.instance }} of ${service} is above ${threshold}s, see ${unknown}"},
+			Data: []AlertQuery{
+				{RefID: "A", Model: json.RawMessage(`{"expr":"latency{service=\"${service}\"} > 0","refId":"A"}`)},
+				{RefID: "B", Model: json.RawMessage(`{"expression":"$A > ${threshold}","threshold":"${threshold}","name":"${service}","type":"math"}`)},
+			},
+		},
+		Instances: []AlertRuleTemplateInstance{
+			{UID: "api", Values: map[string]string{"service": "api", "threshold": "0.5"}},
+			{UID: "web", Values: map[string]string{"service": "web", "threshold": "1"}},
+		},
+	}
+
+	rules, err := tmpl.Expand()
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+
+	rule := rules[0]
+	require.Equal(t, "api", rule.UID)
+	require.Equal(t, int64(1), rule.OrgID)
+	require.Equal(t, "folder", rule.NamespaceUID)
+	require.Equal(t, "group", rule.RuleGroup)
+	require.Equal(t, 1, rule.RuleGroupIndex)
+	require.Equal(t, int64(60), rule.IntervalSeconds)
+	require.Equal(t, "High latency of api", rule.Title)
+	require.Equal(t, map[string]string{"service": "api", "team": "sre"}, rule.Labels)
+	require.Equal(t, "{{ $labels.instance }} of api is above 0.5s, see ${unknown}", rule.Annotations["summary"])
+	require.JSONEq(t, `{"expr":"latency{service=\"api\"} > 0","refId":"A"}`, string(rule.Data[0].Model))
+	require.JSONEq(t, `{"expression":"$A > 0.5","threshold":0.5,"name":"api","type":"math"}`, string(rule.Data[1].Model))
+
+	require.Equal(t, "web", rules[1].UID)
+	require.Equal(t, 2, rules[1].RuleGroupIndex)
+	require.Equal(t, "High latency of web", rules[1].Title)
+
+	t.Run("does not modify the template", func(t *testing.T) {
+		require.Equal(t, "${service}", tmpl.Rule.Labels["service"])
+		require.Contains(t, string(tmpl.Rule.Data[1].Model), `"${threshold}"`)
+	})
+
+	t.Run("keeps placeholders with values that are not numbers as strings", func(t *testing.T) {
+		tmpl := AlertRuleTemplate{
+			Parameters: []string{"value"},
+			Rule:       AlertRule{Data: []AlertQuery{{RefID: "A", Model: json.RawMessage(`{"value":"${value}"}`)}}},
+			Instances:  []AlertRuleTemplateInstance{{Values: map[string]string{"value": "<none>"}}},
+		}
+		rules, err := tmpl.Expand()
+		require.NoError(t, err)
+		require.Equal(t, `{"value":"<none>"}`, string(rules[0].Data[0].Model))
+	})
+}
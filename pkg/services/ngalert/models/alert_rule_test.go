@@ -1028,6 +1028,14 @@ func TestAlertRuleCopy(t *testing.T) {
 		copied := rule.Copy()
 		require.NotSame(t, rule.Metadata.PrometheusStyleRule, copied.Metadata.PrometheusStyleRule)
 	})
+
+	t.Run("should create a copy of the dependencies", func(t *testing.T) {
+		rule := RuleGen.With(RuleGen.WithDependencies(AlertRuleDependency{RuleUID: "parent", Matchers: []string{`dc="eu-1"`}})).GenerateRef()
+		copied := rule.Copy()
+		require.Equal(t, rule.Dependencies, copied.Dependencies)
+		copied.Dependencies[0].Matchers[0] = `dc="eu-2"`
+		require.Equal(t, `dc="eu-1"`, rule.Dependencies[0].Matchers[0])
+	})
 }
 
 // This test makes sure the default generator
@@ -1205,6 +1213,57 @@ func TestValidateAlertRule(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("Dependencies", func(t *testing.T) {
+		testCases := []struct {
+			name                  string
+			dependencies          []AlertRuleDependency
+			expectedErrorContains string
+		}{
+			{
+				name:         "should accept dependencies with and without matchers",
+				dependencies: []AlertRuleDependency{{RuleUID: "parent-1"}, {RuleUID: "parent-2", Matchers: []string{`dc="eu-1"`, "env=~prod.*"}}},
+			},
+			{
+				name:                  "should reject dependency without rule UID",
+				dependencies:          []AlertRuleDependency{{Matchers: []string{`dc="eu-1"`}}},
+				expectedErrorContains: "rule UID is required",
+			},
+			{
+				name:                  "should reject invalid matcher",
+				dependencies:          []AlertRuleDependency{{RuleUID: "parent-1", Matchers: []string{"dc"}}},
+				expectedErrorContains: `invalid matcher "dc"`,
+			},
+			{
+				name:                  "should reject dependency on itself",
+				dependencies:          []AlertRuleDependency{{RuleUID: "self"}},
+				expectedErrorContains: "alert rule cannot depend on itself",
+			},
+			{
+				name:                  "should reject several dependencies on the same rule",
+				dependencies:          []AlertRuleDependency{{RuleUID: "parent-1"}, {RuleUID: "parent-1", Matchers: []string{`dc="eu-1"`}}},
+				expectedErrorContains: "alert rule depends more than once on rule parent-1",
+			},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				rule := RuleGen.With(
+					RuleMuts.WithUID("self"),
+					RuleMuts.WithIntervalSeconds(10),
+					RuleMuts.WithDependencies(tc.dependencies...),
+				).Generate()
+
+				err := rule.ValidateAlertRule(setting.UnifiedAlertingSettings{BaseInterval: 10 * time.Second})
+				if tc.expectedErrorContains != "" {
+					require.Error(t, err)
+					require.ErrorIs(t, err, ErrAlertRuleFailedValidation)
+					require.Contains(t, err.Error(), tc.expectedErrorContains)
+				} else {
+					require.NoError(t, err)
+				}
+			})
+		}
+	})
 }
 
 func TestAlertRule_PrometheusRuleDefinition(t *testing.T) {
@@ -1,11 +1,38 @@
 package models
 
 import (
+	"fmt"
 	"time"
 
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
 )
 
+// ErrHistoryAggregationNotSupported is returned when an aggregation of state history is requested
+// from a backend that cannot aggregate the history.
+var ErrHistoryAggregationNotSupported = errutil.BadRequest("alerting.state-history.aggregationNotSupported", errutil.WithPublicMessage("the configured state history backend does not support aggregations"))
+
+// HistoryAggregation is the kind of aggregation of the state history.
+type HistoryAggregation string
+
+const (
+	// HistoryAggregationFiringDuration aggregates the time that the alert instances of each rule spent firing, per day.
+	HistoryAggregationFiringDuration HistoryAggregation = "firing_duration"
+	// HistoryAggregationFlapping aggregates the number of times that the alert instances of each rule started or stopped firing,
+	// ordered from the most flapping rule.
+	HistoryAggregationFlapping HistoryAggregation = "flapping"
+)
+
+// ParseHistoryAggregation parses the aggregation of a state history query. An empty string means no aggregation.
+func ParseHistoryAggregation(s string) (HistoryAggregation, error) {
+	switch a := HistoryAggregation(s); a {
+	case "", HistoryAggregationFiringDuration, HistoryAggregationFlapping:
+		return a, nil
+	default:
+		return "", fmt.Errorf("unknown aggregation %q, must be one of %q or %q", s, HistoryAggregationFiringDuration, HistoryAggregationFlapping)
+	}
+}
+
 // HistoryQuery represents a query for alert state history.
 type HistoryQuery struct {
 	RuleUID      string
@@ -17,4 +44,6 @@ type HistoryQuery struct {
 	To           time.Time
 	Limit        int
 	SignedInUser identity.Requester
+	// Aggregation is the aggregation of the history to return instead of the history records. Empty if the records are queried.
+	Aggregation HistoryAggregation
 }
@@ -18,6 +18,72 @@ type AlertInstance struct {
 	FiredAt           *time.Time
 	ResolvedAt        *time.Time
 	ResultFingerprint string
+	// AcknowledgedBy is the login of the user who acknowledged the instance. It is empty if the instance is not acknowledged.
+	AcknowledgedBy           string
+	AcknowledgedAt           *time.Time
+	AcknowledgementComment   string
+	AcknowledgementExpiresAt *time.Time
+	AcknowledgementSilenceID string
+}
+
+// GetAcknowledgement returns the acknowledgement of the instance, or nil if the instance is not acknowledged.
+func (i AlertInstance) GetAcknowledgement() *AlertInstanceAcknowledgement {
+	if i.AcknowledgedBy == "" || i.AcknowledgedAt == nil {
+		return nil
+	}
+	return &AlertInstanceAcknowledgement{
+		By:        i.AcknowledgedBy,
+		At:        *i.AcknowledgedAt,
+		Comment:   i.AcknowledgementComment,
+		ExpiresAt: i.AcknowledgementExpiresAt,
+		SilenceID: i.AcknowledgementSilenceID,
+	}
+}
+
+// SetAcknowledgement sets the fields of the acknowledgement of the instance. A nil acknowledgement clears them.
+func (i *AlertInstance) SetAcknowledgement(ack *AlertInstanceAcknowledgement) {
+	if ack == nil {
+		i.AcknowledgedBy = ""
+		i.AcknowledgedAt = nil
+		i.AcknowledgementComment = ""
+		i.AcknowledgementExpiresAt = nil
+		i.AcknowledgementSilenceID = ""
+		return
+	}
+	at := ack.At
+	i.AcknowledgedBy = ack.By
+	i.AcknowledgedAt = &at
+	i.AcknowledgementComment = ack.Comment
+	i.AcknowledgementExpiresAt = ack.ExpiresAt
+	i.AcknowledgementSilenceID = ack.SilenceID
+}
+
+// AlertInstanceAcknowledgement records that a user has taken ownership of a firing alert instance.
+// The acknowledgement is removed when the instance resolves or the acknowledgement expires.
+type AlertInstanceAcknowledgement struct {
+	// By is the login of the user who acknowledged the instance.
+	By      string
+	At      time.Time
+	Comment string
+	// ExpiresAt is the time when the acknowledgement expires. If nil, the acknowledgement lasts until the instance resolves.
+	ExpiresAt *time.Time
+	// SilenceID is the ID of the silence that suppresses the notifications of the instance until the acknowledgement
+	// expires. It is empty if notifications are not suppressed.
+	SilenceID string
+}
+
+// IsExpired returns true if the acknowledgement has expired at the given time.
+func (a *AlertInstanceAcknowledgement) IsExpired(now time.Time) bool {
+	return a.ExpiresAt != nil && !now.Before(*a.ExpiresAt)
+}
+
+// Equals returns true if both acknowledgements are nil or have the same fields.
+func (a *AlertInstanceAcknowledgement) Equals(b *AlertInstanceAcknowledgement) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	expiresEqual := (a.ExpiresAt == nil && b.ExpiresAt == nil) || (a.ExpiresAt != nil && b.ExpiresAt != nil && a.ExpiresAt.Equal(*b.ExpiresAt))
+	return a.By == b.By && a.At.Equal(b.At) && a.Comment == b.Comment && expiresEqual && a.SilenceID == b.SilenceID
 }
 
 type AlertInstanceKey struct {
@@ -42,6 +108,8 @@ const (
 	InstanceStateError InstanceStateType = "Error"
 	// InstanceStateRecovering is for a recovering alert.
 	InstanceStateRecovering InstanceStateType = "Recovering"
+	// InstanceStateSuppressed is for an alert that is suppressed by a firing dependency of its rule.
+	InstanceStateSuppressed InstanceStateType = "Suppressed"
 )
 
 // IsValid checks that the value of InstanceStateType is a valid
@@ -52,7 +120,8 @@ func (i InstanceStateType) IsValid() bool {
 		i == InstanceStateNoData ||
 		i == InstanceStatePending ||
 		i == InstanceStateError ||
-		i == InstanceStateRecovering
+		i == InstanceStateRecovering ||
+		i == InstanceStateSuppressed
 }
 
 // ListAlertInstancesQuery is the query list alert Instances.
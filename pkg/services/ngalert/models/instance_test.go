@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -107,3 +108,42 @@ func TestValidateAlertInstance(t *testing.T) {
 		})
 	}
 }
+
+func TestAlertInstanceAcknowledgement(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	expires := now.Add(time.Hour)
+
+	t.Run("instance without acknowledgement returns nil", func(t *testing.T) {
+		instance := AlertInstanceGen()
+		instance.SetAcknowledgement(nil)
+		require.Nil(t, instance.GetAcknowledgement())
+	})
+
+	t.Run("acknowledgement is stored in fields of the instance", func(t *testing.T) {
+		ack := &AlertInstanceAcknowledgement{
+			By:        "admin",
+			At:        now,
+			Comment:   "looking into it",
+			ExpiresAt: &expires,
+			SilenceID: "silence",
+		}
+		instance := AlertInstanceGen()
+		instance.SetAcknowledgement(ack)
+		require.Equal(t, "admin", instance.AcknowledgedBy)
+		require.Equal(t, &expires, instance.AcknowledgementExpiresAt)
+		require.True(t, ack.Equals(instance.GetAcknowledgement()))
+
+		instance.SetAcknowledgement(nil)
+		require.Nil(t, instance.GetAcknowledgement())
+		require.Empty(t, instance.AcknowledgementSilenceID)
+	})
+
+	t.Run("acknowledgement expires at its expiry", func(t *testing.T) {
+		ack := &AlertInstanceAcknowledgement{By: "admin", At: now, ExpiresAt: &expires}
+		require.False(t, ack.IsExpired(expires.Add(-time.Second)))
+		require.True(t, ack.IsExpired(expires))
+
+		ack.ExpiresAt = nil
+		require.False(t, ack.IsExpired(expires.Add(time.Hour)))
+	})
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+)
+
+// NotificationDeliveryStatus is the outcome of an attempt to send a notification.
+type NotificationDeliveryStatus string
+
+const (
+	NotificationDeliverySuccess NotificationDeliveryStatus = "success"
+	NotificationDeliveryFailed  NotificationDeliveryStatus = "failed"
+)
+
+// NotificationDelivery is an attempt of an integration of a receiver of the Grafana Alertmanager to send the
+// notification of an alert group.
+type NotificationDelivery struct {
+	ID    int64
+	OrgID int64
+	// Receiver is the name of the receiver of the integration.
+	Receiver string
+	// Integration is the type of the integration, for example "email" or "slack".
+	Integration string
+	// IntegrationIndex is the position of the integration in the receiver, to tell integrations of the same type apart.
+	IntegrationIndex int
+	// GroupKey is the key of the alert group in the Alertmanager.
+	GroupKey string
+	// Attempt is the number of the attempt of the integration to send the notification, starting at 1. The notify stage
+	// retries a notification until it succeeds.
+	Attempt  int
+	Status   NotificationDeliveryStatus
+	Error    string
+	Duration time.Duration
+	Time     time.Time
+	// Alerts are the alerts of the notification.
+	Alerts []NotificationDeliveryAlert
+}
+
+// NotificationDeliveryAlert is an alert of the alert group of a notification delivery.
+type NotificationDeliveryAlert struct {
+	// RuleUID is the UID of the alert rule of the alert. It is empty for alerts that are not created by alert rules.
+	RuleUID string
+	// Fingerprint is the fingerprint of the labels of the alert, without private labels, as recorded by the state history.
+	Fingerprint string
+	Status      string
+	StartsAt    time.Time
+}
+
+// NotificationDeliveryQuery is the query for listing the notification deliveries of an organization.
+type NotificationDeliveryQuery struct {
+	OrgID int64
+	// RuleUID limits the deliveries to the ones of alert groups with an alert of the rule.
+	RuleUID string
+	// Fingerprint limits the deliveries to the ones of alert groups with the alert, to find the notifications that
+	// followed a state transition in the state history.
+	Fingerprint string
+	Receiver    string
+	From        time.Time
+	To          time.Time
+	Limit       int
+}
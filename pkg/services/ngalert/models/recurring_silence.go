@@ -0,0 +1,268 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/robfig/cron/v3"
+
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+var (
+	ErrRecurringSilenceNotFound = errutil.NotFound("alerting.recurring-silence.notFound", errutil.WithPublicMessage("recurring silence not found"))
+	ErrRecurringSilenceExists   = errutil.Conflict("alerting.recurring-silence.exists", errutil.WithPublicMessage("recurring silence with this UID already exists"))
+	// ErrRecurringSilenceFailedValidation is an error for an invalid recurring silence.
+	ErrRecurringSilenceFailedValidation = errors.New("invalid recurring silence")
+)
+
+// recurringSilenceMaxWindow is the maximum duration of the windows of a cron schedule. Windows of time intervals
+// that are still open at the end of the materialized time are followed for at most this long.
+const recurringSilenceMaxWindow = 7 * 24 * time.Hour
+
+// RecurringSilence is a silence that is in effect in the windows of a schedule. The windows are materialized
+// ahead of time as regular Alertmanager silences with the matchers and the comment of the recurring silence.
+type RecurringSilence struct {
+	ID        int64
+	OrgID     int64
+	UID       string
+	Matchers  amv2.Matchers
+	Comment   string
+	CreatedBy string
+	Schedule  RecurringSilenceSchedule
+	Version   int64
+	Updated   time.Time
+	UpdatedBy *UserUID
+}
+
+// RecurringSilenceSchedule defines the windows of a recurring silence either as a cron expression with a duration
+// or as time intervals in the format of mute timings. Exactly one of them must be set.
+type RecurringSilenceSchedule struct {
+	// Cron is a cron expression in the standard five-field format. A window starts at every activation.
+	Cron string
+	// Duration is the length of the windows that start at the activations of Cron.
+	Duration time.Duration
+	// TimeIntervals are the time intervals in which the silence is in effect.
+	TimeIntervals []timeinterval.TimeInterval
+	// Timezone is the name of the IANA time zone of the schedule. If empty, UTC is used.
+	// Time intervals with their own location are evaluated in that location.
+	Timezone string
+}
+
+// TimeWindow is a window of time in which a recurring silence is in effect. The end is exclusive.
+type TimeWindow struct {
+	Start time.Time
+	End   time.Time
+}
+
+// ListRecurringSilencesQuery is the query for listing recurring silences. If OrgID is 0, the recurring silences of all organizations are returned.
+type ListRecurringSilencesQuery struct {
+	OrgID int64
+}
+
+func (s *RecurringSilence) ResourceType() string {
+	return "recurringSilence"
+}
+
+func (s *RecurringSilence) ResourceID() string {
+	return s.UID
+}
+
+// Validate checks the matchers and the schedule of the recurring silence.
+func (s *RecurringSilence) Validate() error {
+	if len(s.Matchers) == 0 {
+		return fmt.Errorf("%w: at least one matcher is required", ErrRecurringSilenceFailedValidation)
+	}
+	matchesEmpty := true
+	for idx, m := range s.Matchers {
+		if m == nil || m.Name == nil || m.Value == nil {
+			return fmt.Errorf("%w: matcher %d must have a name and a value", ErrRecurringSilenceFailedValidation, idx)
+		}
+		matcher, err := labels.NewMatcher(matchType(m), *m.Name, *m.Value)
+		if err != nil {
+			return fmt.Errorf("%w: matcher %d is invalid: %w", ErrRecurringSilenceFailedValidation, idx, err)
+		}
+		if !matcher.Matches("") {
+			matchesEmpty = false
+		}
+	}
+	if matchesEmpty {
+		return fmt.Errorf("%w: at least one matcher must not match the empty string", ErrRecurringSilenceFailedValidation)
+	}
+	if s.Comment == "" {
+		return fmt.Errorf("%w: comment must be set", ErrRecurringSilenceFailedValidation)
+	}
+	return s.Schedule.Validate()
+}
+
+// Silence returns the Alertmanager silence of the recurring silence for the window.
+func (s *RecurringSilence) Silence(w TimeWindow) Silence {
+	comment := s.Comment
+	createdBy := s.CreatedBy
+	return Silence{
+		Silence: amv2.Silence{
+			Comment:   &comment,
+			CreatedBy: &createdBy,
+			StartsAt:  util.Pointer(strfmt.DateTime(w.Start)),
+			EndsAt:    util.Pointer(strfmt.DateTime(w.End)),
+			Matchers:  s.Matchers,
+		},
+	}
+}
+
+// Validate checks that exactly one of the cron expression and the time intervals is set and that they can be evaluated.
+func (s RecurringSilenceSchedule) Validate() error {
+	if _, err := s.location(); err != nil {
+		return fmt.Errorf("%w: invalid timezone '%s': %w", ErrRecurringSilenceFailedValidation, s.Timezone, err)
+	}
+	switch {
+	case s.Cron != "" && len(s.TimeIntervals) > 0:
+		return fmt.Errorf("%w: either a cron expression or time intervals can be set, not both", ErrRecurringSilenceFailedValidation)
+	case s.Cron != "":
+		if _, err := cron.ParseStandard(s.Cron); err != nil {
+			return fmt.Errorf("%w: invalid cron expression '%s': %w", ErrRecurringSilenceFailedValidation, s.Cron, err)
+		}
+		if s.Duration <= 0 || s.Duration > recurringSilenceMaxWindow {
+			return fmt.Errorf("%w: duration must be positive and at most %s", ErrRecurringSilenceFailedValidation, recurringSilenceMaxWindow)
+		}
+	case len(s.TimeIntervals) > 0:
+		if s.Duration != 0 {
+			return fmt.Errorf("%w: duration can be set only together with a cron expression", ErrRecurringSilenceFailedValidation)
+		}
+		for idx, ti := range s.TimeIntervals {
+			// An empty time interval matches at any time, which would make the silence permanent.
+			if len(ti.Times) == 0 && len(ti.Weekdays) == 0 && len(ti.DaysOfMonth) == 0 && len(ti.Months) == 0 && len(ti.Years) == 0 {
+				return fmt.Errorf("%w: time interval %d is empty", ErrRecurringSilenceFailedValidation, idx)
+			}
+		}
+	default:
+		return fmt.Errorf("%w: either a cron expression or time intervals must be set", ErrRecurringSilenceFailedValidation)
+	}
+	return nil
+}
+
+// Windows returns the windows of the schedule that end after from and start before to, ordered by their start.
+// Windows that start before from are returned as if they started at from, and overlapping windows are merged.
+func (s RecurringSilenceSchedule) Windows(from, to time.Time) ([]TimeWindow, error) {
+	loc, err := s.location()
+	if err != nil {
+		return nil, err
+	}
+	var windows []TimeWindow
+	if s.Cron != "" {
+		sched, err := cron.ParseStandard(s.Cron)
+		if err != nil {
+			return nil, err
+		}
+		// The schedule is evaluated in the location of the time passed to Next.
+		for start := sched.Next(from.Add(-s.Duration).In(loc)); !start.IsZero() && start.Before(to); start = sched.Next(start) {
+			windows = append(windows, TimeWindow{Start: start, End: start.Add(s.Duration)})
+		}
+	} else {
+		windows = s.intervalWindows(from, to, loc)
+	}
+
+	result := make([]TimeWindow, 0, len(windows))
+	for _, w := range windows {
+		if !w.End.After(from) {
+			continue
+		}
+		if w.Start.Before(from) {
+			w.Start = from
+		}
+		if last := len(result) - 1; last >= 0 && !w.Start.After(result[last].End) {
+			if w.End.After(result[last].End) {
+				result[last].End = w.End
+			}
+			continue
+		}
+		result = append(result, w)
+	}
+	return result, nil
+}
+
+// intervalWindows scans the time between from and to minute by minute, which is the resolution of time intervals.
+// A window that is still open at to is followed until it ends, but for no longer than recurringSilenceMaxWindow after to.
+func (s RecurringSilenceSchedule) intervalWindows(from, to time.Time, loc *time.Location) []TimeWindow {
+	var result []TimeWindow
+	var current *TimeWindow
+	limit := to.Add(recurringSilenceMaxWindow)
+	for t := from.Truncate(time.Minute); t.Before(limit); t = t.Add(time.Minute) {
+		if current == nil && !t.Before(to) {
+			break
+		}
+		if s.containsTime(t.In(loc)) {
+			if current == nil {
+				current = &TimeWindow{Start: t}
+			}
+			continue
+		}
+		if current != nil {
+			current.End = t
+			result = append(result, *current)
+			current = nil
+		}
+	}
+	if current != nil {
+		current.End = limit
+		result = append(result, *current)
+	}
+	return result
+}
+
+func (s RecurringSilenceSchedule) containsTime(t time.Time) bool {
+	for _, ti := range s.TimeIntervals {
+		if ti.ContainsTime(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s RecurringSilenceSchedule) location() (*time.Location, error) {
+	if s.Timezone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(s.Timezone)
+}
+
+// RecurringSilenceState is the state of the materialization of a recurring silence.
+// It is used to make sure that every window is materialized only once, even if several instances of Grafana materialize the same recurring silence.
+type RecurringSilenceState struct {
+	OrgID int64
+	// UID is the UID of the recurring silence.
+	UID string
+	// RecurringSilenceVersion is the version of the recurring silence that the silences were created from.
+	// The silences are replaced when the recurring silence changes.
+	RecurringSilenceVersion int64
+	// MaterializedUntil is the time until which the windows of the recurring silence are materialized.
+	MaterializedUntil time.Time
+	// Silences are the silences that were created for the recurring silence and have not ended yet.
+	Silences []MaterializedSilence
+	Version  int64
+}
+
+// MaterializedSilence is a silence created for a window of a recurring silence.
+type MaterializedSilence struct {
+	ID     string
+	EndsAt time.Time
+}
+
+func matchType(m *amv2.Matcher) labels.MatchType {
+	isRegex := m.IsRegex != nil && *m.IsRegex
+	switch {
+	case isRegex && isEqualOrDefault(m):
+		return labels.MatchRegexp
+	case isRegex:
+		return labels.MatchNotRegexp
+	case !isEqualOrDefault(m):
+		return labels.MatchNotEqual
+	}
+	return labels.MatchEqual
+}
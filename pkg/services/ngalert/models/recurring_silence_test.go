@@ -0,0 +1,134 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// sundayMaintenance is every Sunday from 02:00 to 04:00.
+var sundayMaintenance = timeinterval.TimeInterval{
+	Times:    []timeinterval.TimeRange{{StartMinute: 2 * 60, EndMinute: 4 * 60}},
+	Weekdays: []timeinterval.WeekdayRange{{InclusiveRange: timeinterval.InclusiveRange{Begin: 0, End: 0}}},
+}
+
+func utc(value string) time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		panic(err)
+	}
+	return t.UTC()
+}
+
+func windowsInUTC(t *testing.T, s RecurringSilenceSchedule, from, to time.Time) []TimeWindow {
+	t.Helper()
+	windows, err := s.Windows(from, to)
+	require.NoError(t, err)
+	for i := range windows {
+		windows[i].Start = windows[i].Start.UTC()
+		windows[i].End = windows[i].End.UTC()
+	}
+	return windows
+}
+
+func TestRecurringSilenceValidate(t *testing.T) {
+	valid := func() RecurringSilence {
+		return RecurringSilence{
+			Matchers: amv2.Matchers{{Name: util.Pointer("cluster"), Value: util.Pointer("eu-1"), IsEqual: util.Pointer(true), IsRegex: util.Pointer(false)}},
+			Comment:  "Maintenance",
+			Schedule: RecurringSilenceSchedule{Cron: "0 2 * * 0", Duration: 2 * time.Hour, Timezone: "Europe/Berlin"},
+		}
+	}
+	s := valid()
+	require.NoError(t, s.Validate())
+
+	testCases := []struct {
+		name   string
+		mutate func(s *RecurringSilence)
+	}{
+		{name: "no matchers", mutate: func(s *RecurringSilence) { s.Matchers = nil }},
+		{name: "matcher matches everything", mutate: func(s *RecurringSilence) {
+			s.Matchers = amv2.Matchers{{Name: util.Pointer("cluster"), Value: util.Pointer(".*"), IsRegex: util.Pointer(true)}}
+		}},
+		{name: "invalid regex", mutate: func(s *RecurringSilence) {
+			s.Matchers = amv2.Matchers{{Name: util.Pointer("cluster"), Value: util.Pointer("(eu"), IsRegex: util.Pointer(true)}}
+		}},
+		{name: "no comment", mutate: func(s *RecurringSilence) { s.Comment = "" }},
+		{name: "no schedule", mutate: func(s *RecurringSilence) { s.Schedule = RecurringSilenceSchedule{} }},
+		{name: "cron and time intervals", mutate: func(s *RecurringSilence) {
+			s.Schedule.TimeIntervals = []timeinterval.TimeInterval{sundayMaintenance}
+		}},
+		{name: "invalid cron", mutate: func(s *RecurringSilence) { s.Schedule.Cron = "0 2 * *" }},
+		{name: "cron without duration", mutate: func(s *RecurringSilence) { s.Schedule.Duration = 0 }},
+		{name: "duration too long", mutate: func(s *RecurringSilence) { s.Schedule.Duration = 8 * 24 * time.Hour }},
+		{name: "invalid timezone", mutate: func(s *RecurringSilence) { s.Schedule.Timezone = "Europe/Nowhere" }},
+		{name: "duration with time intervals", mutate: func(s *RecurringSilence) {
+			s.Schedule.Cron = ""
+			s.Schedule.TimeIntervals = []timeinterval.TimeInterval{sundayMaintenance}
+		}},
+		{name: "empty time interval", mutate: func(s *RecurringSilence) {
+			s.Schedule = RecurringSilenceSchedule{TimeIntervals: []timeinterval.TimeInterval{{}}}
+		}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := valid()
+			tc.mutate(&s)
+			require.ErrorIs(t, s.Validate(), ErrRecurringSilenceFailedValidation)
+		})
+	}
+}
+
+func TestRecurringSilenceScheduleWindows(t *testing.T) {
+	t.Run("cron schedule is evaluated in the timezone", func(t *testing.T) {
+		s := RecurringSilenceSchedule{Cron: "0 2 * * 0", Duration: 2 * time.Hour, Timezone: "Europe/Berlin"}
+		// 02:00 in Berlin is 00:00 UTC in summer.
+		require.Equal(t, []TimeWindow{
+			{Start: utc("2024-06-02T00:00:00Z"), End: utc("2024-06-02T02:00:00Z")},
+			{Start: utc("2024-06-09T00:00:00Z"), End: utc("2024-06-09T02:00:00Z")},
+		}, windowsInUTC(t, s, utc("2024-06-01T00:00:00Z"), utc("2024-06-10T00:00:00Z")))
+	})
+
+	t.Run("window in progress starts at from", func(t *testing.T) {
+		s := RecurringSilenceSchedule{Cron: "0 2 * * 0", Duration: 2 * time.Hour}
+		require.Equal(t, []TimeWindow{
+			{Start: utc("2024-06-02T03:00:00Z"), End: utc("2024-06-02T04:00:00Z")},
+		}, windowsInUTC(t, s, utc("2024-06-02T03:00:00Z"), utc("2024-06-03T00:00:00Z")))
+
+		require.Empty(t, windowsInUTC(t, s, utc("2024-06-02T04:00:00Z"), utc("2024-06-03T00:00:00Z")))
+	})
+
+	t.Run("window that starts before to is returned completely", func(t *testing.T) {
+		s := RecurringSilenceSchedule{Cron: "0 2 * * 0", Duration: 2 * time.Hour}
+		require.Equal(t, []TimeWindow{
+			{Start: utc("2024-06-02T02:00:00Z"), End: utc("2024-06-02T04:00:00Z")},
+		}, windowsInUTC(t, s, utc("2024-06-02T00:00:00Z"), utc("2024-06-02T03:00:00Z")))
+	})
+
+	t.Run("overlapping windows are merged", func(t *testing.T) {
+		s := RecurringSilenceSchedule{Cron: "0 * * * *", Duration: 90 * time.Minute}
+		require.Equal(t, []TimeWindow{
+			{Start: utc("2024-06-02T00:00:00Z"), End: utc("2024-06-02T03:30:00Z")},
+		}, windowsInUTC(t, s, utc("2024-06-02T00:00:00Z"), utc("2024-06-02T03:00:00Z")))
+	})
+
+	t.Run("time intervals are evaluated in the timezone", func(t *testing.T) {
+		s := RecurringSilenceSchedule{TimeIntervals: []timeinterval.TimeInterval{sundayMaintenance}, Timezone: "Europe/Berlin"}
+		require.Equal(t, []TimeWindow{
+			{Start: utc("2024-06-02T00:00:00Z"), End: utc("2024-06-02T02:00:00Z")},
+			{Start: utc("2024-06-09T00:00:00Z"), End: utc("2024-06-09T02:00:00Z")},
+		}, windowsInUTC(t, s, utc("2024-06-01T00:00:00Z"), utc("2024-06-10T00:00:00Z")))
+	})
+
+	t.Run("time interval window that is open at to is followed until it ends", func(t *testing.T) {
+		s := RecurringSilenceSchedule{TimeIntervals: []timeinterval.TimeInterval{sundayMaintenance}}
+		require.Equal(t, []TimeWindow{
+			{Start: utc("2024-06-02T02:30:00Z"), End: utc("2024-06-02T04:00:00Z")},
+		}, windowsInUTC(t, s, utc("2024-06-02T02:30:00Z"), utc("2024-06-02T03:00:00Z")))
+	})
+}
@@ -1,7 +1,11 @@
 package models
 
 import (
+	"time"
+
 	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/common/model"
 	"golang.org/x/exp/maps"
 
 	alertingModels "github.com/grafana/alerting/models"
@@ -18,6 +22,28 @@ func (s Silence) GetRuleUID() *string {
 	return getRuleUIDLabelValue(s.Silence)
 }
 
+// Mutes returns true if the silence is in effect at the given time and all its matchers match the labels.
+func (s Silence) Mutes(lbls model.LabelSet, at time.Time) bool {
+	if s.StartsAt == nil || s.EndsAt == nil || at.Before(time.Time(*s.StartsAt)) || !at.Before(time.Time(*s.EndsAt)) {
+		return false
+	}
+	for _, m := range s.Matchers {
+		if m == nil || m.Name == nil || m.Value == nil {
+			return false
+		}
+		matcher, err := labels.NewMatcher(matchType(m), *m.Name, *m.Value)
+		if err != nil || !matcher.Matches(string(lbls[model.LabelName(*m.Name)])) {
+			return false
+		}
+	}
+	return true
+}
+
+func isEqualOrDefault(m *amv2.Matcher) bool {
+	// If IsEqual is nil, it is considered to be true.
+	return m.IsEqual == nil || *m.IsEqual
+}
+
 // getRuleUIDLabelValue returns the value of the RuleUIDLabel matcher in the given silence, if it exists.
 func getRuleUIDLabelValue(silence notify.Silence) *string {
 	for _, m := range silence.Matchers {
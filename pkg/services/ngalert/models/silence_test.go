@@ -2,8 +2,10 @@ package models
 
 import (
 	"testing"
+	"time"
 
 	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/grafana/alerting/models"
@@ -49,6 +51,57 @@ func TestSilenceGetRuleUID(t *testing.T) {
 	}
 }
 
+func TestSilenceMutes(t *testing.T) {
+	withoutMatchers := func(s *Silence) {
+		s.Matchers = nil
+	}
+	lbls := model.LabelSet{"team": "infra", "severity": "critical"}
+	now := time.Now()
+
+	testCases := []struct {
+		name     string
+		silence  Silence
+		at       time.Time
+		expected bool
+	}{
+		{
+			name:     "all matchers match",
+			silence:  SilenceGen(withoutMatchers, SilenceMuts.WithMatcher("team", "infra", labels.MatchEqual), SilenceMuts.WithMatcher("severity", "warn.*", labels.MatchNotRegexp))(),
+			at:       now,
+			expected: true,
+		},
+		{
+			name:     "matcher of a missing label matches empty value",
+			silence:  SilenceGen(withoutMatchers, SilenceMuts.WithMatcher("cluster", "prod", labels.MatchNotEqual))(),
+			at:       now,
+			expected: true,
+		},
+		{
+			name:     "one matcher does not match",
+			silence:  SilenceGen(withoutMatchers, SilenceMuts.WithMatcher("team", "infra", labels.MatchEqual), SilenceMuts.WithMatcher("severity", "crit.*", labels.MatchNotRegexp))(),
+			at:       now,
+			expected: false,
+		},
+		{
+			name:     "silence is expired",
+			silence:  SilenceGen(withoutMatchers, SilenceMuts.WithMatcher("team", "infra", labels.MatchEqual), SilenceMuts.Expired())(),
+			at:       now,
+			expected: false,
+		},
+		{
+			name:     "silence has not started yet",
+			silence:  SilenceGen(withoutMatchers, SilenceMuts.WithMatcher("team", "infra", labels.MatchEqual))(),
+			at:       now.Add(-time.Hour),
+			expected: false,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.silence.Mutes(lbls, tt.at))
+		})
+	}
+}
+
 func TestSilencePermissionSet(t *testing.T) {
 	t.Run("Clone", func(t *testing.T) {
 		perms := SilencePermissionSet{
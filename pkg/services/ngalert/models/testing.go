@@ -103,6 +103,15 @@ func (g *AlertRuleGenerator) Generate() AlertRule {
 		updatedBy = util.Pointer(UserUID(util.GenerateShortUID()))
 	}
 
+	var dependencies []AlertRuleDependency
+	if rand.Int63()%2 == 0 {
+		dependency := AlertRuleDependency{RuleUID: util.GenerateShortUID()}
+		if rand.Int63()%2 == 0 {
+			dependency.Matchers = []string{fmt.Sprintf("lbl=%q", util.GenerateShortUID())}
+		}
+		dependencies = append(dependencies, dependency)
+	}
+
 	rule := AlertRule{
 		ID:                          0,
 		GUID:                        uuid.NewString(),
@@ -127,6 +136,7 @@ func (g *AlertRuleGenerator) Generate() AlertRule {
 		Annotations:                 annotations,
 		Labels:                      labels,
 		NotificationSettings:        ns,
+		Dependencies:                dependencies,
 		Metadata:                    GenerateMetadata(),
 		MissingSeriesEvalsToResolve: util.Pointer(2),
 	}
@@ -523,6 +533,18 @@ func (a *AlertRuleMutators) WithMissingSeriesEvalsToResolve(timesOfInterval int)
 	}
 }
 
+func (a *AlertRuleMutators) WithDependencies(deps ...AlertRuleDependency) AlertRuleMutator {
+	return func(rule *AlertRule) {
+		rule.Dependencies = deps
+	}
+}
+
+func (a *AlertRuleMutators) WithNoDependencies() AlertRuleMutator {
+	return func(rule *AlertRule) {
+		rule.Dependencies = nil
+	}
+}
+
 func (a *AlertRuleMutators) WithNotificationSettingsGen(ns func() NotificationSettings) AlertRuleMutator {
 	return func(rule *AlertRule) {
 		rule.NotificationSettings = []NotificationSettings{ns()}
@@ -870,6 +892,7 @@ func AlertInstanceGen(mutators ...AlertInstanceMutator) *AlertInstance {
 			InstanceStateNoData,
 			InstanceStateError,
 			InstanceStateRecovering,
+			InstanceStateSuppressed,
 		}
 		return s[rand.Intn(len(s))]
 	}
@@ -1380,6 +1403,7 @@ func ConvertToRecordingRule(rule *AlertRule) {
 	rule.For = 0
 	rule.NotificationSettings = nil
 	rule.MissingSeriesEvalsToResolve = nil
+	rule.Dependencies = nil
 }
 
 func nameToUid(name string) string { // Avoid legacy_storage.NameToUid import cycle.
@@ -42,6 +42,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/sender"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/ngalert/state/historian"
+	"github.com/grafana/grafana/pkg/services/ngalert/statearchive"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/ngalert/writer"
 	"github.com/grafana/grafana/pkg/services/notifications"
@@ -143,6 +144,10 @@ type AlertNG struct {
 	RecordingWriter     schedule.RecordingWriter
 	schedule            schedule.ScheduleService
 	stateManager        *state.Manager
+	stateSeries         *state.SeriesExporter
+	recurringSilences   *notifier.RecurringSilenceMaterializer
+	deliveryLog         *notifier.NotificationDeliveryLog
+	historian           Historian
 	folderService       folder.Service
 	dashboardService    dashboards.DashboardService
 	Api                 *api.API
@@ -263,6 +268,16 @@ func (ng *AlertNG) init() error {
 		overrides = append(overrides, override)
 	}
 
+	if ng.Cfg.UnifiedAlerting.NotificationDeliveryLog.Enabled {
+		ng.deliveryLog = notifier.NewNotificationDeliveryLog(notifier.NotificationDeliveryLogCfg{
+			FlushInterval: ng.Cfg.UnifiedAlerting.NotificationDeliveryLog.FlushInterval,
+			Retention:     ng.Cfg.UnifiedAlerting.NotificationDeliveryLog.Retention,
+			Clock:         clock.New(),
+			Log:           log.New("ngalert.notification-delivery-log"),
+		}, ng.store.SQLStore)
+		overrides = append(overrides, notifier.WithNotificationDeliveryLog(ng.deliveryLog))
+	}
+
 	decryptFn := ng.SecretsService.GetDecryptedValue
 	multiOrgMetrics := ng.Metrics.GetMultiOrgAlertmanagerMetrics()
 	moa, err := notifier.NewMultiOrgAlertmanager(
@@ -341,13 +356,23 @@ func (ng *AlertNG) init() error {
 		RecordingWriter:      ng.RecordingWriter,
 		FeatureToggles:       ng.FeatureToggles,
 	}
+	if ng.Cfg.UnifiedAlerting.HAShardRuleEvaluation {
+		membership := ng.MultiOrgAlertmanager.ClusterMembership()
+		if membership == nil {
+			ng.Log.Warn("Sharding of rule evaluation is enabled but high availability is not configured. All rules will be evaluated by this instance")
+		} else {
+			schedCfg.Membership = membership
+			schedCfg.ShardingHandOffDelay = ng.Cfg.UnifiedAlerting.HAShardHandOffDelay
+		}
+	}
 
-	history, err := configureHistorianBackend(initCtx, ng.Cfg.UnifiedAlerting.StateHistory, ng.annotationsRepo, ng.dashboardService, ng.store, ng.Metrics.GetHistorianMetrics(), ng.Log, ng.tracer, ac.NewRuleService(ng.accesscontrol))
+	history, err := configureHistorianBackend(initCtx, ng.Cfg.UnifiedAlerting.StateHistory, ng.annotationsRepo, ng.dashboardService, ng.store.SQLStore, ng.store, ng.Metrics.GetHistorianMetrics(), ng.Log, ng.tracer, ac.NewRuleService(ng.accesscontrol))
 	if err != nil {
 		return err
 	}
+	ng.historian = history
 
-	ng.InstanceStore, ng.StartupInstanceReader = initInstanceStore(ng.store.SQLStore, ng.Log, ng.FeatureToggles)
+	ng.InstanceStore, ng.StartupInstanceReader = InitInstanceStore(ng.store.SQLStore, ng.Log, ng.FeatureToggles)
 
 	stateManagerCfg := state.ManagerCfg{
 		Metrics:                    ng.Metrics.GetStateMetrics(),
@@ -357,6 +382,8 @@ func (ng *AlertNG) init() error {
 		Images:                     ng.ImageService,
 		Clock:                      clk,
 		Historian:                  history,
+		Silencer:                   moa,
+		ClusterPeer:                moa.ClusterPeer(),
 		MaxStateSaveConcurrency:    ng.Cfg.UnifiedAlerting.MaxStateSaveConcurrency,
 		StatePeriodicSaveBatchSize: ng.Cfg.UnifiedAlerting.StatePeriodicSaveBatchSize,
 		RulesPerRuleGroupLimit:     ng.Cfg.UnifiedAlerting.RulesPerRuleGroupLimit,
@@ -376,6 +403,27 @@ func (ng *AlertNG) init() error {
 	ng.stateManager = stateManager
 	ng.schedule = scheduler
 
+	if ng.Cfg.UnifiedAlerting.StateSeries.Enabled {
+		if ng.Cfg.UnifiedAlerting.RecordingRules.Enabled {
+			ng.stateSeries = state.NewSeriesExporter(state.SeriesExporterCfg{
+				Interval:      ng.Cfg.UnifiedAlerting.StateSeries.Interval,
+				DatasourceUID: ng.Cfg.UnifiedAlerting.StateSeries.DatasourceUID,
+				Writer:        ng.RecordingWriter,
+				Clock:         clk,
+				Log:           log.New("ngalert.state.series"),
+			}, stateManager)
+		} else {
+			ng.Log.Warn("Alert state series are enabled but recording rules are not. Alert state series will not be written")
+		}
+	}
+
+	ng.recurringSilences = notifier.NewRecurringSilenceMaterializer(notifier.RecurringSilenceMaterializerCfg{
+		Interval:  ng.Cfg.UnifiedAlerting.RecurringSilences.SyncInterval,
+		Lookahead: ng.Cfg.UnifiedAlerting.RecurringSilences.Lookahead,
+		Clock:     clk,
+		Log:       log.New("ngalert.recurring-silences"),
+	}, ng.store, ng.MultiOrgAlertmanager)
+
 	configStore := legacy_storage.NewAlertmanagerConfigStore(ng.store)
 	receiverService := notifier.NewReceiverService(
 		ac.NewReceiverAccess[*models.Receiver](ng.accesscontrol, false),
@@ -405,11 +453,14 @@ func (ng *AlertNG) init() error {
 	contactPointService := provisioning.NewContactPointService(configStore, ng.SecretsService, ng.store, ng.store, provisioningReceiverService, ng.Log, ng.store, ng.ResourcePermissions)
 	templateService := provisioning.NewTemplateService(configStore, ng.store, ng.store, ng.Log)
 	muteTimingService := provisioning.NewMuteTimingService(configStore, ng.store, ng.store, ng.Log, ng.store)
+	escalationPolicyService := provisioning.NewEscalationPolicyService(configStore, ng.store, ng.store, ng.Log)
 	alertRuleService := provisioning.NewAlertRuleService(ng.store, ng.store, ng.folderService, ng.QuotaService, ng.store,
 		int64(ng.Cfg.UnifiedAlerting.DefaultRuleEvaluationInterval.Seconds()),
 		int64(ng.Cfg.UnifiedAlerting.BaseInterval.Seconds()),
 		ng.Cfg.UnifiedAlerting.RulesPerRuleGroupLimit, ng.Log, notifier.NewNotificationSettingsValidationService(ng.store),
 		ac.NewRuleService(ng.accesscontrol))
+	alertRuleTemplateService := provisioning.NewAlertRuleTemplateService(ng.store, alertRuleService, ng.store, ng.store, ng.Log)
+	recurringSilenceService := provisioning.NewRecurringSilenceService(ng.store, ac.NewSilenceService(ng.accesscontrol, ng.store), ng.store, ng.store, ng.Log)
 
 	ng.Api = &api.API{
 		Cfg:                  ng.Cfg,
@@ -432,7 +483,10 @@ func (ng *AlertNG) init() error {
 		ContactPointService:  contactPointService,
 		Templates:            templateService,
 		MuteTimings:          muteTimingService,
+		EscalationPolicies:   escalationPolicyService,
 		AlertRules:           alertRuleService,
+		AlertRuleTemplates:   alertRuleTemplateService,
+		RecurringSilences:    recurringSilenceService,
 		AlertsRouter:         alertsRouter,
 		EvaluatorFactory:     evalFactory,
 		ConditionValidator:   conditionValidator,
@@ -443,6 +497,13 @@ func (ng *AlertNG) init() error {
 		Tracer:               ng.tracer,
 		UserService:          ng.userService,
 	}
+	if ng.deliveryLog != nil {
+		ng.Api.NotificationDeliveries = ng.deliveryLog
+	}
+	ng.Api.StateArchive = statearchive.NewService(ng.store, ng.store, ng.store, ng.stateManager, ng.InstanceStore, ng.KVStore, log.New("ngalert.state-archive"),
+		statearchive.WithStateLoader(ng.stateManager),
+		statearchive.WithAlertmanagers(ng.MultiOrgAlertmanager),
+	)
 	ng.Api.RegisterAPIEndpoints(ng.Metrics.GetAPIMetrics())
 
 	if err := RegisterQuotas(ng.Cfg, ng.QuotaService, ng.store); err != nil {
@@ -460,10 +521,10 @@ func (ng *AlertNG) init() error {
 	return DeclareFixedRoles(ng.AccesscontrolService, ng.FeatureToggles)
 }
 
-// initInstanceStore initializes the instance store based on the feature toggles.
+// InitInstanceStore initializes the instance store based on the feature toggles.
 // It returns two vales: the instance store that should be used for writing alert instances,
 // and an alert instance reader that can be used to read alert instances on startup.
-func initInstanceStore(sqlStore db.DB, logger log.Logger, featureToggles featuremgmt.FeatureToggles) (state.InstanceStore, state.InstanceReader) {
+func InitInstanceStore(sqlStore db.DB, logger log.Logger, featureToggles featuremgmt.FeatureToggles) (state.InstanceStore, state.InstanceReader) {
 	var instanceStore state.InstanceStore
 
 	// We init both stores here, but only one will be used based on the feature toggles.
@@ -504,6 +565,11 @@ func initStatePersister(uaCfg setting.UnifiedAlertingSettings, cfg state.Manager
 	if featureToggles.IsEnabledGlobally(featuremgmt.FlagAlertingSaveStateCompressed) {
 		logger.Info("Using rule state persister")
 		statePersister = state.NewSyncRuleStatePersisiter(logger, cfg)
+	} else if featureToggles.IsEnabledGlobally(featuremgmt.FlagAlertingSaveStatePeriodic) && uaCfg.HAShardRuleEvaluation {
+		// The periodic state persister replaces the state of all rules with the state in the cache of this instance,
+		// which contains only the rules that are evaluated by this instance when the evaluation is sharded.
+		logger.Warn("Periodic state persister cannot be used when rule evaluation is sharded. Using sync state persister")
+		statePersister = state.NewSyncStatePersisiter(logger, cfg)
 	} else if featureToggles.IsEnabledGlobally(featuremgmt.FlagAlertingSaveStatePeriodic) {
 		logger.Info("Using periodic state persister")
 		ticker := clock.New().Ticker(uaCfg.StatePeriodicSaveInterval)
@@ -543,6 +609,14 @@ func (ng *AlertNG) Run(ctx context.Context) error {
 	children.Go(func() error {
 		return ng.AlertsRouter.Run(subCtx)
 	})
+	children.Go(func() error {
+		return ng.recurringSilences.Run(subCtx)
+	})
+	if ng.deliveryLog != nil {
+		children.Go(func() error {
+			return ng.deliveryLog.Run(subCtx)
+		})
+	}
 
 	if ng.Cfg.UnifiedAlerting.ExecuteAlerts {
 		// Only Warm() the state manager if we are actually executing alerts.
@@ -562,6 +636,16 @@ func (ng *AlertNG) Run(ctx context.Context) error {
 		children.Go(func() error {
 			return ng.stateManager.Run(subCtx)
 		})
+		if ng.stateSeries != nil {
+			children.Go(func() error {
+				return ng.stateSeries.Run(subCtx)
+			})
+		}
+	}
+	if h, ok := ng.historian.(historianRunner); ok {
+		children.Go(func() error {
+			return h.Run(subCtx)
+		})
 	}
 	return children.Wait()
 }
@@ -586,7 +670,12 @@ type Historian interface {
 	state.Historian
 }
 
-func configureHistorianBackend(ctx context.Context, cfg setting.UnifiedAlertingStateHistorySettings, ar annotations.Repository, ds dashboards.DashboardService, rs historian.RuleStore, met *metrics.Historian, l log.Logger, tracer tracing.Tracer, ac historian.AccessControl) (Historian, error) {
+// historianRunner is implemented by the state history backends that need to run in the background.
+type historianRunner interface {
+	Run(ctx context.Context) error
+}
+
+func configureHistorianBackend(ctx context.Context, cfg setting.UnifiedAlertingStateHistorySettings, ar annotations.Repository, ds dashboards.DashboardService, sqlStore db.DB, rs historian.RuleStore, met *metrics.Historian, l log.Logger, tracer tracing.Tracer, ac historian.AccessControl) (Historian, error) {
 	if !cfg.Enabled {
 		met.Info.WithLabelValues("noop").Set(0)
 		return historian.NewNopHistorian(), nil
@@ -601,7 +690,7 @@ func configureHistorianBackend(ctx context.Context, cfg setting.UnifiedAlertingS
 	if backend == historian.BackendTypeMultiple {
 		primaryCfg := cfg
 		primaryCfg.Backend = cfg.MultiPrimary
-		primary, err := configureHistorianBackend(ctx, primaryCfg, ar, ds, rs, met, l, tracer, ac)
+		primary, err := configureHistorianBackend(ctx, primaryCfg, ar, ds, sqlStore, rs, met, l, tracer, ac)
 		if err != nil {
 			return nil, fmt.Errorf("multi-backend target \"%s\" was misconfigured: %w", cfg.MultiPrimary, err)
 		}
@@ -610,7 +699,7 @@ func configureHistorianBackend(ctx context.Context, cfg setting.UnifiedAlertingS
 		for _, b := range cfg.MultiSecondaries {
 			secCfg := cfg
 			secCfg.Backend = b
-			sec, err := configureHistorianBackend(ctx, secCfg, ar, ds, rs, met, l, tracer, ac)
+			sec, err := configureHistorianBackend(ctx, secCfg, ar, ds, sqlStore, rs, met, l, tracer, ac)
 			if err != nil {
 				return nil, fmt.Errorf("multi-backend target \"%s\" was miconfigured: %w", b, err)
 			}
@@ -641,6 +730,10 @@ func configureHistorianBackend(ctx context.Context, cfg setting.UnifiedAlertingS
 		}
 		return backend, nil
 	}
+	if backend == historian.BackendTypeSQL {
+		sqlBackendLogger := log.New("ngalert.state.historian", "backend", "sql")
+		return historian.NewSQLBackend(sqlBackendLogger, historian.NewSQLConfig(cfg), sqlStore, met, rs, ac), nil
+	}
 
 	return nil, fmt.Errorf("unrecognized state history backend: %s", backend)
 }
@@ -5,12 +5,16 @@ import (
 	"crypto/md5"
 	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
+	alertingCluster "github.com/grafana/alerting/cluster"
 	alertingNotify "github.com/grafana/alerting/notify"
 	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/client_golang/prometheus"
 
 	amv2 "github.com/prometheus/alertmanager/api/v2/models"
 
@@ -53,6 +57,25 @@ type alertmanager struct {
 	Store                AlertingStore
 	stateStore           stateStore
 	DefaultConfiguration string
+
+	escalator *escalator
+	// deliveryRecorder is nil if the notification delivery log is disabled.
+	deliveryRecorder *deliveryRecorder
+	peer             *nflogPeer
+}
+
+// nflogPeer is the cluster peer of an Alertmanager. It keeps the notification log that the Alertmanager adds to the
+// cluster, because the Alertmanager does not expose it otherwise and its current state is needed to export it.
+type nflogPeer struct {
+	alertingNotify.ClusterPeer
+	nflog alertingCluster.State
+}
+
+func (p *nflogPeer) AddState(key string, state alertingCluster.State, reg prometheus.Registerer) alertingCluster.ClusterChannel {
+	if strings.HasPrefix(key, "notificationlog:") {
+		p.nflog = state
+	}
+	return p.ClusterPeer.AddState(key, state, reg)
 }
 
 // maintenanceOptions represent the options for components that need maintenance on a frequency within the Alertmanager.
@@ -84,7 +107,7 @@ func (m maintenanceOptions) MaintenanceFunc(state alertingNotify.State) (int64,
 
 func NewAlertmanager(ctx context.Context, orgID int64, cfg *setting.Cfg, store AlertingStore, stateStore stateStore,
 	peer alertingNotify.ClusterPeer, decryptFn alertingNotify.GetDecryptedValueFn, ns notifications.Service,
-	m *metrics.Alertmanager, featureToggles featuremgmt.FeatureToggles,
+	m *metrics.Alertmanager, featureToggles featuremgmt.FeatureToggles, deliveryLog *NotificationDeliveryLog,
 ) (*alertmanager, error) {
 	nflog, err := stateStore.GetNotificationLog(ctx)
 	if err != nil {
@@ -115,6 +138,7 @@ func NewAlertmanager(ctx context.Context, orgID int64, cfg *setting.Cfg, store A
 		},
 	}
 	l := log.New("ngalert.notifier")
+	statePeer := &nflogPeer{ClusterPeer: peer}
 
 	opts := alertingNotify.GrafanaAlertmanagerOpts{
 		ExternalURL:        cfg.AppURL,
@@ -132,11 +156,19 @@ func NewAlertmanager(ctx context.Context, orgID int64, cfg *setting.Cfg, store A
 		Version:       setting.BuildVersion,
 		TenantKey:     "orgID",
 		TenantID:      orgID,
-		Peer:          peer,
+		Peer:          statePeer,
 		Logger:        l,
 		Metrics:       alertingNotify.NewGrafanaAlertmanagerMetrics(m.Registerer, l),
 	}
 
+	var recorder *deliveryRecorder
+	if deliveryLog != nil {
+		recorder = newDeliveryRecorder(l.New("component", "alertmanager", opts.TenantKey, opts.TenantID, "subcomponent", "delivery-recorder"),
+			orgID, deliveryLog.flushInterval, deliveryLog.Save)
+		// TODO: wrap the notifiers of the integrations with recorder.wrap once the Grafana Alertmanager accepts a hook
+		// that passes the receiver and the index of each integration it builds.
+	}
+
 	gam, err := alertingNotify.NewGrafanaAlertmanager(opts)
 	if err != nil {
 		return nil, err
@@ -149,6 +181,15 @@ func NewAlertmanager(ctx context.Context, orgID int64, cfg *setting.Cfg, store A
 		Store:                store,
 		stateStore:           stateStore,
 		logger:               l.New("component", "alertmanager", opts.TenantKey, opts.TenantID), // similar to what the base does
+		deliveryRecorder:     recorder,
+		peer:                 statePeer,
+	}
+	am.escalator = newEscalator(am.logger.New("subcomponent", "escalator"), peer, func() (alertingNotify.GettableAlerts, error) {
+		return am.Base.GetAlerts(true, false, false, nil, "")
+	}, am.PutAlerts)
+	go am.escalator.run()
+	if am.deliveryRecorder != nil {
+		go am.deliveryRecorder.run()
 	}
 
 	return am, nil
@@ -162,7 +203,11 @@ func (am *alertmanager) Ready() bool {
 }
 
 func (am *alertmanager) StopAndWait() {
+	am.escalator.stop()
 	am.Base.StopAndWait()
+	if am.deliveryRecorder != nil {
+		am.deliveryRecorder.stop()
+	}
 }
 
 // SaveAndApplyDefaultConfig saves the default configuration to the database and applies it to the Alertmanager.
@@ -350,6 +395,7 @@ func (am *alertmanager) applyConfig(ctx context.Context, cfg *apimodels.Postable
 	if err != nil {
 		return false, err
 	}
+	am.escalator.applyConfig(cfg)
 
 	am.updateConfigMetrics(cfg, len(rawConfig))
 	return true, nil
@@ -375,6 +421,15 @@ func (am *alertmanager) SilenceState(_ context.Context) (alertingNotify.SilenceS
 	return am.Base.SilenceState()
 }
 
+// NotificationLogState returns the current internal state of the notification log in the binary format that
+// is saved to the kvstore.
+func (am *alertmanager) NotificationLogState(_ context.Context) ([]byte, error) {
+	if am.peer.nflog == nil {
+		return nil, errors.New("the Alertmanager has no notification log")
+	}
+	return am.peer.nflog.MarshalBinary()
+}
+
 // AlertValidationError is the error capturing the validation errors
 // faced on the alerts.
 type AlertValidationError struct {
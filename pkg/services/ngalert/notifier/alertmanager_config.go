@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/go-openapi/strfmt"
+	alertingNotify "github.com/grafana/alerting/notify"
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	"github.com/grafana/grafana/pkg/apimachinery/errutil"
@@ -30,6 +31,8 @@ var (
 		errutil.WithPublic(
 			"time interval [Name: {{ .Public.Interval }}] is used by rule",
 		))
+	// ErrAlertmanagerConfigConflict is returned when the configuration is updated with the hash of a configuration that is no longer the latest.
+	ErrAlertmanagerConfigConflict = errutil.Conflict("alerting.notifications.alertmanager.configConflict", errutil.WithPublicMessage("The Alertmanager configuration was changed after it was fetched. Fetch it again and retry."))
 )
 
 type UnknownReceiverError struct {
@@ -222,6 +225,8 @@ func (moa *MultiOrgAlertmanager) gettableUserConfigFromAMConfigString(ctx contex
 		AlertmanagerConfig: definitions.GettableApiAlertingConfig{
 			Config: cfg.AlertmanagerConfig.Config,
 		},
+		EscalationPolicies: cfg.EscalationPolicies,
+		ImportedConfigs:    cfg.ImportedConfigs,
 	}
 
 	// First we encrypt the secure settings.
@@ -275,11 +280,8 @@ func (moa *MultiOrgAlertmanager) gettableUserConfigFromAMConfigString(ctx contex
 }
 
 func (moa *MultiOrgAlertmanager) SaveAndApplyAlertmanagerConfiguration(ctx context.Context, org int64, config definitions.PostableUserConfig) error {
-	// We cannot add this validation to PostableUserConfig as that struct is used for both
-	// Grafana Alertmanager (where inhibition rules are not supported) and External Alertmanagers
-	// (including Mimir) where inhibition rules are supported.
-	if len(config.AlertmanagerConfig.InhibitRules) > 0 {
-		return errors.New("inhibition rules are not supported")
+	if err := validateUserConfig(&config); err != nil {
+		return err
 	}
 
 	// Get the last known working configuration
@@ -310,15 +312,7 @@ func (moa *MultiOrgAlertmanager) SaveAndApplyAlertmanagerConfiguration(ctx conte
 
 	if err := am.SaveAndApplyConfig(ctx, &config); err != nil {
 		moa.logger.Error("Unable to save and apply alertmanager configuration", "error", err)
-		errReceiverDoesNotExist := ErrorReceiverDoesNotExist{}
-		if errors.As(err, &errReceiverDoesNotExist) {
-			return ErrAlertmanagerReceiverInUse.Build(errutil.TemplateData{Public: map[string]interface{}{"Receiver": errReceiverDoesNotExist.Reference}, Error: err})
-		}
-		errTimeIntervalDoesNotExist := ErrorTimeIntervalDoesNotExist{}
-		if errors.As(err, &errTimeIntervalDoesNotExist) {
-			return ErrAlertmanagerTimeIntervalInUse.Build(errutil.TemplateData{Public: map[string]interface{}{"Interval": errTimeIntervalDoesNotExist.Reference}, Error: err})
-		}
-		return AlertmanagerConfigRejectedError{err}
+		return rejectedConfigError(err)
 	}
 
 	// Attempt to cleanup permissions for receivers that are no longer defined and add defaults for new receivers.
@@ -339,6 +333,112 @@ func (moa *MultiOrgAlertmanager) SaveAndApplyAlertmanagerConfiguration(ctx conte
 	return nil
 }
 
+// ValidateAlertmanagerConfiguration validates the configuration the same way SaveAndApplyAlertmanagerConfiguration
+// does, without saving or applying it. The secure settings of the configuration are encrypted, the ones that are not
+// set are copied from the latest configuration of the organization, and the missing UIDs of its integrations are assigned.
+func (moa *MultiOrgAlertmanager) ValidateAlertmanagerConfiguration(ctx context.Context, org int64, config *definitions.PostableUserConfig) error {
+	if err := validateUserConfig(config); err != nil {
+		return err
+	}
+	if err := moa.Crypto.ProcessSecureSettings(ctx, org, config.AlertmanagerConfig.Receivers); err != nil {
+		return fmt.Errorf("failed to post process Alertmanager configuration: %w", err)
+	}
+	if err := assignReceiverConfigsUIDs(config.AlertmanagerConfig.Receivers); err != nil {
+		return fmt.Errorf("failed to assign missing uids: %w", err)
+	}
+
+	// Applying the configuration fails if the notification settings of a rule refer to a receiver or a time interval
+	// that does not exist.
+	err := AddAutogenConfig(ctx, moa.logger, moa.configStore, org, &config.AlertmanagerConfig, false)
+	RemoveAutogenConfigIfExists(config.AlertmanagerConfig.Route)
+	if err != nil {
+		return rejectedConfigError(err)
+	}
+	for _, r := range config.AlertmanagerConfig.Receivers {
+		if _, err := alertingNotify.BuildReceiverConfiguration(ctx, PostableApiReceiverToApiReceiver(r), alertingNotify.DecodeSecretsFromBase64, moa.decryptFn); err != nil {
+			return AlertmanagerConfigRejectedError{err}
+		}
+	}
+	return nil
+}
+
+// UpdateAndApplyAlertmanagerConfiguration validates the configuration the same way as ValidateAlertmanagerConfiguration,
+// saves it if the latest configuration of the organization still has the fetched hash, and applies it.
+// It returns ErrAlertmanagerConfigConflict if the latest configuration was changed after it was fetched.
+func (moa *MultiOrgAlertmanager) UpdateAndApplyAlertmanagerConfiguration(ctx context.Context, org int64, config definitions.PostableUserConfig, fetchedHash string) error {
+	previousConfig, cleanPermissionsErr := moa.configStore.GetLatestAlertmanagerConfiguration(ctx, org)
+
+	if err := moa.ValidateAlertmanagerConfiguration(ctx, org, &config); err != nil {
+		return err
+	}
+	rawConfig, err := json.Marshal(&config)
+	if err != nil {
+		return fmt.Errorf("failed to serialize to the Alertmanager configuration: %w", err)
+	}
+	cmd := models.SaveAlertmanagerConfigurationCmd{
+		AlertmanagerConfiguration: string(rawConfig),
+		ConfigurationVersion:      fmt.Sprintf("v%d", models.AlertConfigurationVersion),
+		FetchedConfigurationHash:  fetchedHash,
+		OrgID:                     org,
+	}
+	if err := moa.configStore.UpdateAlertmanagerConfiguration(ctx, &cmd); err != nil {
+		if errors.Is(err, store.ErrVersionLockedObjectNotFound) {
+			return ErrAlertmanagerConfigConflict.Errorf("the latest Alertmanager configuration does not have the hash %s: %w", fetchedHash, err)
+		}
+		return fmt.Errorf("failed to save the Alertmanager configuration: %w", err)
+	}
+
+	saved, err := moa.configStore.GetLatestAlertmanagerConfiguration(ctx, org)
+	if err != nil {
+		return fmt.Errorf("failed to get latest configuration: %w", err)
+	}
+	if err := moa.ApplyConfig(ctx, org, saved); err != nil {
+		return err
+	}
+
+	// Attempt to cleanup permissions for receivers that are no longer defined and add defaults for new receivers.
+	// Failure should not prevent the configuration from being applied.
+	if cleanPermissionsErr == nil {
+		newReceiverNames := make(sets.Set[string], len(config.AlertmanagerConfig.Receivers))
+		for _, r := range config.AlertmanagerConfig.Receivers {
+			newReceiverNames.Insert(r.Name)
+		}
+		cleanPermissionsErr = moa.cleanPermissions(ctx, org, previousConfig, newReceiverNames)
+	}
+	if cleanPermissionsErr != nil {
+		moa.logger.Error("Failed to clean permissions for receivers", "error", cleanPermissionsErr)
+	}
+
+	return nil
+}
+
+// validateUserConfig validates the parts of the configuration that the Grafana Alertmanager does not support.
+func validateUserConfig(config *definitions.PostableUserConfig) error {
+	// We cannot add this validation to PostableUserConfig as that struct is used for both
+	// Grafana Alertmanager (where inhibition rules are not supported) and External Alertmanagers
+	// (including Mimir) where inhibition rules are supported.
+	if len(config.AlertmanagerConfig.InhibitRules) > 0 {
+		return errors.New("inhibition rules are not supported")
+	}
+	if err := config.ValidateEscalationPolicies(); err != nil {
+		return AlertmanagerConfigRejectedError{err}
+	}
+	return nil
+}
+
+// rejectedConfigError returns the error of a configuration that the Alertmanager could not apply.
+func rejectedConfigError(err error) error {
+	errReceiverDoesNotExist := ErrorReceiverDoesNotExist{}
+	if errors.As(err, &errReceiverDoesNotExist) {
+		return ErrAlertmanagerReceiverInUse.Build(errutil.TemplateData{Public: map[string]interface{}{"Receiver": errReceiverDoesNotExist.Reference}, Error: err})
+	}
+	errTimeIntervalDoesNotExist := ErrorTimeIntervalDoesNotExist{}
+	if errors.As(err, &errTimeIntervalDoesNotExist) {
+		return ErrAlertmanagerTimeIntervalInUse.Build(errutil.TemplateData{Public: map[string]interface{}{"Interval": errTimeIntervalDoesNotExist.Reference}, Error: err})
+	}
+	return AlertmanagerConfigRejectedError{err}
+}
+
 // assignReceiverConfigsUIDs assigns missing UUIDs to receiver configs.
 func assignReceiverConfigsUIDs(c []*definitions.PostableApiReceiver) error {
 	seenUIDs := make(map[string]struct{})
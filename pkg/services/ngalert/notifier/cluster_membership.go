@@ -0,0 +1,51 @@
+package notifier
+
+import (
+	alertingCluster "github.com/grafana/alerting/cluster"
+	alertingNotify "github.com/grafana/alerting/notify"
+)
+
+// ClusterMembership provides the members of the cluster of Grafana replicas that is used for high availability.
+type ClusterMembership interface {
+	// Self returns the name of this replica.
+	Self() string
+	// Members returns the names of the healthy replicas, including this one.
+	Members() []string
+}
+
+// memberlistMembership provides the members of a gossip cluster.
+type memberlistMembership struct {
+	peer *alertingCluster.Peer
+}
+
+func (m memberlistMembership) Self() string {
+	return m.peer.Name()
+}
+
+func (m memberlistMembership) Members() []string {
+	nodes := m.peer.Peers()
+	members := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		members = append(members, n.Name)
+	}
+	return members
+}
+
+// ClusterMembership returns the members of the cluster of Grafana replicas,
+// or nil if high availability is not configured.
+func (moa *MultiOrgAlertmanager) ClusterMembership() ClusterMembership {
+	switch p := moa.peer.(type) {
+	case *redisPeer:
+		return p
+	case *alertingCluster.Peer:
+		return memberlistMembership{peer: p}
+	default:
+		return nil
+	}
+}
+
+// ClusterPeer returns the peer that shares state with the other replicas of Grafana.
+// It does nothing if high availability is not configured.
+func (moa *MultiOrgAlertmanager) ClusterPeer() alertingNotify.ClusterPeer {
+	return moa.peer
+}
@@ -0,0 +1,290 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/benbjohnson/clock"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+const (
+	// deliveryCleanupInterval is how often the deliveries that are older than the retention are deleted.
+	deliveryCleanupInterval = time.Hour
+	// deliveryBatchSize is the maximum number of records that are deleted or loaded at once. It is below the limit of
+	// query parameters of SQLite.
+	deliveryBatchSize = 900
+	// deliveryDefaultQueryLimit is the maximum number of deliveries returned by a query that does not define a limit.
+	deliveryDefaultQueryLimit = 1000
+	// deliveryDefaultQueryRange is the time range of a query that does not define the start of the range.
+	deliveryDefaultQueryRange = 6 * time.Hour
+)
+
+type NotificationDeliveryLogCfg struct {
+	// FlushInterval is how often the recorded deliveries are written.
+	FlushInterval time.Duration
+	// Retention is how long the deliveries are kept. 0 keeps them forever.
+	Retention time.Duration
+	Clock     clock.Clock
+	Log       log.Logger
+}
+
+// notificationDeliveryEntry represents a record in the alert_notification_delivery table.
+type notificationDeliveryEntry struct {
+	ID               int64 `xorm:"pk autoincr 'id'"`
+	OrgID            int64 `xorm:"org_id"`
+	Receiver         string
+	Integration      string
+	IntegrationIndex int
+	GroupKey         string
+	Attempt          int
+	Status           string
+	Error            string
+	DurationMs       int64 `xorm:"duration_ms"`
+	// Epoch is the time of the attempt, in milliseconds.
+	Epoch int64
+}
+
+func (e notificationDeliveryEntry) TableName() string {
+	return "alert_notification_delivery"
+}
+
+// notificationDeliveryAlertEntry represents a record in the alert_notification_delivery_alert table.
+type notificationDeliveryAlertEntry struct {
+	ID          int64  `xorm:"pk autoincr 'id'"`
+	DeliveryID  int64  `xorm:"delivery_id"`
+	OrgID       int64  `xorm:"org_id"`
+	RuleUID     string `xorm:"rule_uid"`
+	Fingerprint string
+	Status      string
+	StartsAt    int64
+	// Epoch is the time of the attempt of the delivery, in milliseconds. It is denormalized for the retention.
+	Epoch int64
+}
+
+func (e notificationDeliveryAlertEntry) TableName() string {
+	return "alert_notification_delivery_alert"
+}
+
+// NotificationDeliveryLog stores the attempts of the integrations of the Grafana Alertmanagers to send notifications
+// in the Grafana database, and deletes them after the retention.
+type NotificationDeliveryLog struct {
+	db            db.DB
+	flushInterval time.Duration
+	retention     time.Duration
+	clock         clock.Clock
+	log           log.Logger
+}
+
+func NewNotificationDeliveryLog(cfg NotificationDeliveryLogCfg, store db.DB) *NotificationDeliveryLog {
+	return &NotificationDeliveryLog{
+		db:            store,
+		flushInterval: cfg.FlushInterval,
+		retention:     cfg.Retention,
+		clock:         cfg.Clock,
+		log:           cfg.Log,
+	}
+}
+
+// Run deletes the deliveries that are older than the retention until the context is cancelled.
+func (l *NotificationDeliveryLog) Run(ctx context.Context) error {
+	l.log.Info("Starting notification delivery log", "flushInterval", l.flushInterval, "retention", l.retention)
+	if l.retention <= 0 {
+		<-ctx.Done()
+		return nil
+	}
+	ticker := l.clock.Ticker(deliveryCleanupInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			l.log.Info("Stopped notification delivery log")
+			return nil
+		case <-ticker.C:
+			deleted, err := l.deleteExpired(ctx)
+			if err != nil {
+				l.log.Error("Failed to delete expired notification deliveries", "deleted", deleted, "error", err)
+				continue
+			}
+			l.log.Debug("Deleted expired notification deliveries", "deleted", deleted)
+		}
+	}
+}
+
+// Save writes the deliveries with their alerts.
+func (l *NotificationDeliveryLog) Save(ctx context.Context, deliveries []models.NotificationDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return l.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		var alerts []notificationDeliveryAlertEntry
+		for _, d := range deliveries {
+			entry := notificationDeliveryEntry{
+				OrgID:            d.OrgID,
+				Receiver:         d.Receiver,
+				Integration:      d.Integration,
+				IntegrationIndex: d.IntegrationIndex,
+				GroupKey:         d.GroupKey,
+				Attempt:          d.Attempt,
+				Status:           string(d.Status),
+				Error:            d.Error,
+				DurationMs:       d.Duration.Milliseconds(),
+				Epoch:            d.Time.UnixMilli(),
+			}
+			if _, err := sess.Insert(&entry); err != nil {
+				return fmt.Errorf("failed to insert notification delivery: %w", err)
+			}
+			for _, a := range d.Alerts {
+				alerts = append(alerts, notificationDeliveryAlertEntry{
+					DeliveryID:  entry.ID,
+					OrgID:       d.OrgID,
+					RuleUID:     a.RuleUID,
+					Fingerprint: a.Fingerprint,
+					Status:      a.Status,
+					StartsAt:    a.StartsAt.UnixMilli(),
+					Epoch:       entry.Epoch,
+				})
+			}
+		}
+		if len(alerts) == 0 {
+			return nil
+		}
+		if _, err := sess.BulkInsert(notificationDeliveryAlertEntry{}.TableName(), alerts, sqlstore.NativeSettingsForDialect(l.db.GetDialect())); err != nil {
+			return fmt.Errorf("failed to insert alerts of notification deliveries: %w", err)
+		}
+		return nil
+	})
+}
+
+// Query returns the deliveries that match the query, the most recent first.
+func (l *NotificationDeliveryLog) Query(ctx context.Context, query models.NotificationDeliveryQuery) ([]models.NotificationDelivery, error) {
+	now := l.clock.Now()
+	if query.To.IsZero() || query.To.After(now) {
+		query.To = now
+	}
+	if query.From.IsZero() {
+		query.From = query.To.Add(-deliveryDefaultQueryRange)
+	}
+	if query.Limit <= 0 {
+		query.Limit = deliveryDefaultQueryLimit
+	}
+	from, to := query.From.UnixMilli(), query.To.UnixMilli()
+
+	var result []models.NotificationDelivery
+	err := l.db.WithDbSession(ctx, func(sess *db.Session) error {
+		q := sess.Table(notificationDeliveryEntry{}.TableName()).
+			Where("org_id = ?", query.OrgID).
+			And("epoch >= ?", from).
+			And("epoch <= ?", to)
+		if query.Receiver != "" {
+			q = q.And("receiver = ?", query.Receiver)
+		}
+		if query.RuleUID != "" || query.Fingerprint != "" {
+			conditions := []string{"org_id = ?", "epoch >= ?", "epoch <= ?"}
+			args := []any{query.OrgID, from, to}
+			if query.RuleUID != "" {
+				conditions = append(conditions, "rule_uid = ?")
+				args = append(args, query.RuleUID)
+			}
+			if query.Fingerprint != "" {
+				conditions = append(conditions, "fingerprint = ?")
+				args = append(args, query.Fingerprint)
+			}
+			q = q.And(fmt.Sprintf("id IN (SELECT delivery_id FROM %s WHERE %s)", notificationDeliveryAlertEntry{}.TableName(), strings.Join(conditions, " AND ")), args...)
+		}
+		var entries []notificationDeliveryEntry
+		if err := q.Desc("epoch", "id").Limit(query.Limit).Find(&entries); err != nil {
+			return err
+		}
+
+		alerts := make(map[int64][]models.NotificationDeliveryAlert, len(entries))
+		for start := 0; start < len(entries); start += deliveryBatchSize {
+			end := min(start+deliveryBatchSize, len(entries))
+			ids := make([]int64, 0, end-start)
+			for _, e := range entries[start:end] {
+				ids = append(ids, e.ID)
+			}
+			var alertEntries []notificationDeliveryAlertEntry
+			if err := sess.In("delivery_id", ids).Asc("id").Find(&alertEntries); err != nil {
+				return err
+			}
+			for _, a := range alertEntries {
+				alerts[a.DeliveryID] = append(alerts[a.DeliveryID], models.NotificationDeliveryAlert{
+					RuleUID:     a.RuleUID,
+					Fingerprint: a.Fingerprint,
+					Status:      a.Status,
+					StartsAt:    time.UnixMilli(a.StartsAt).UTC(),
+				})
+			}
+		}
+
+		result = make([]models.NotificationDelivery, 0, len(entries))
+		for _, e := range entries {
+			result = append(result, models.NotificationDelivery{
+				ID:               e.ID,
+				OrgID:            e.OrgID,
+				Receiver:         e.Receiver,
+				Integration:      e.Integration,
+				IntegrationIndex: e.IntegrationIndex,
+				GroupKey:         e.GroupKey,
+				Attempt:          e.Attempt,
+				Status:           models.NotificationDeliveryStatus(e.Status),
+				Error:            e.Error,
+				Duration:         time.Duration(e.DurationMs) * time.Millisecond,
+				Time:             time.UnixMilli(e.Epoch).UTC(),
+				Alerts:           alerts[e.ID],
+			})
+		}
+		return nil
+	})
+	return result, err
+}
+
+// deleteExpired deletes the deliveries and their alerts that are older than the retention, in batches.
+// It returns the number of deleted deliveries.
+func (l *NotificationDeliveryLog) deleteExpired(ctx context.Context) (int64, error) {
+	cutoff := l.clock.Now().Add(-l.retention).UnixMilli()
+	var total int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+		// Load the IDs first, because deleting with a limit is not supported by all databases.
+		var ids []int64
+		err := l.db.WithDbSession(ctx, func(sess *db.Session) error {
+			return sess.SQL(
+				fmt.Sprintf("SELECT id FROM %s WHERE epoch < ? ORDER BY id %s", notificationDeliveryEntry{}.TableName(), l.db.GetDialect().Limit(deliveryBatchSize)),
+				cutoff,
+			).Find(&ids)
+		})
+		if err != nil {
+			return total, err
+		}
+		if len(ids) == 0 {
+			return total, nil
+		}
+
+		var deleted int64
+		err = l.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+			if _, err := sess.In("delivery_id", ids).Delete(&notificationDeliveryAlertEntry{}); err != nil {
+				return err
+			}
+			var err error
+			deleted, err = sess.In("id", ids).Delete(&notificationDeliveryEntry{})
+			return err
+		})
+		if err != nil {
+			return total, err
+		}
+		total += deleted
+		if len(ids) < deliveryBatchSize {
+			return total, nil
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package notifier
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestIntegrationNotificationDeliveryLog(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	sqlStore := db.InitTestDB(t)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clk := clock.NewMock()
+	clk.Set(start.Add(time.Hour))
+	deliveryLog := NewNotificationDeliveryLog(NotificationDeliveryLogCfg{
+		FlushInterval: time.Second,
+		Retention:     24 * time.Hour,
+		Clock:         clk,
+		Log:           log.NewNopLogger(),
+	}, sqlStore)
+	ctx := context.Background()
+
+	delivery := func(receiver string, at time.Time, alerts ...models.NotificationDeliveryAlert) models.NotificationDelivery {
+		return models.NotificationDelivery{
+			OrgID:       1,
+			Receiver:    receiver,
+			Integration: "email",
+			GroupKey:    `{alertname="HighLatency"}`,
+			Attempt:     1,
+			Status:      models.NotificationDeliverySuccess,
+			Duration:    250 * time.Millisecond,
+			Time:        at,
+			Alerts:      alerts,
+		}
+	}
+	alertA := models.NotificationDeliveryAlert{RuleUID: "rule-1", Fingerprint: "000000000000000a", Status: "active", StartsAt: start}
+	alertB := models.NotificationDeliveryAlert{RuleUID: "rule-2", Fingerprint: "000000000000000b", Status: "active", StartsAt: start}
+	require.NoError(t, deliveryLog.Save(ctx, []models.NotificationDelivery{
+		delivery("team-a", start.Add(-48*time.Hour), alertA),
+		delivery("team-a", start.Add(10*time.Minute), alertA, alertB),
+		delivery("team-b", start.Add(20*time.Minute), alertB),
+	}))
+
+	t.Run("should return the most recent deliveries first", func(t *testing.T) {
+		result, err := deliveryLog.Query(ctx, models.NotificationDeliveryQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+		require.Equal(t, "team-b", result[0].Receiver)
+		require.Equal(t, start.Add(20*time.Minute), result[0].Time)
+		require.Equal(t, 250*time.Millisecond, result[0].Duration)
+		require.Equal(t, []models.NotificationDeliveryAlert{alertB}, result[0].Alerts)
+		require.Equal(t, []models.NotificationDeliveryAlert{alertA, alertB}, result[1].Alerts)
+	})
+
+	t.Run("should filter by receiver, rule and fingerprint", func(t *testing.T) {
+		result, err := deliveryLog.Query(ctx, models.NotificationDeliveryQuery{OrgID: 1, Receiver: "team-a"})
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		require.Equal(t, start.Add(10*time.Minute), result[0].Time)
+
+		result, err = deliveryLog.Query(ctx, models.NotificationDeliveryQuery{OrgID: 1, RuleUID: "rule-2"})
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+
+		result, err = deliveryLog.Query(ctx, models.NotificationDeliveryQuery{OrgID: 1, RuleUID: "rule-1", From: start.Add(-72 * time.Hour)})
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+
+		result, err = deliveryLog.Query(ctx, models.NotificationDeliveryQuery{OrgID: 1, Fingerprint: alertA.Fingerprint, From: start.Add(15 * time.Minute)})
+		require.NoError(t, err)
+		require.Empty(t, result)
+	})
+
+	t.Run("should not return deliveries of other organizations", func(t *testing.T) {
+		result, err := deliveryLog.Query(ctx, models.NotificationDeliveryQuery{OrgID: 2})
+		require.NoError(t, err)
+		require.Empty(t, result)
+	})
+
+	t.Run("should delete the deliveries older than the retention", func(t *testing.T) {
+		deleted, err := deliveryLog.deleteExpired(ctx)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), deleted)
+
+		result, err := deliveryLog.Query(ctx, models.NotificationDeliveryQuery{OrgID: 1, From: start.Add(-72 * time.Hour)})
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+	})
+}
@@ -0,0 +1,215 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	alertingModels "github.com/grafana/alerting/models"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const (
+	// deliverySaveTimeout is the timeout of writing the deliveries recorded at an interval.
+	deliverySaveTimeout = 30 * time.Second
+	// deliveryAttemptsRetention is how long the attempts to send a notification are counted after the notification
+	// was flushed by the alert group. It is longer than the attempts of the notify stage can last.
+	deliveryAttemptsRetention = time.Hour
+)
+
+// integrationRef identifies an integration of a receiver by its position in the receiver.
+type integrationRef struct {
+	receiver string
+	index    int
+}
+
+// deliveryAttemptKey identifies a notification of an alert group sent by an integration. The notify stage retries a
+// notification until it succeeds, and each call of the integration is an attempt of the same notification.
+type deliveryAttemptKey struct {
+	integrationRef
+	groupKey string
+	// flushedAt is the time the alert group flushed the notification.
+	flushedAt time.Time
+}
+
+// deliveryRecorder records every call of the integrations of an Alertmanager to send a notification, with the alert
+// group, the attempt of the notification, the error and the duration of the call. The notifier of each integration is
+// wrapped with the receiver and the position of the integration in it, and the recorded deliveries are written at an
+// interval so that the notify stage does not wait for the database.
+type deliveryRecorder struct {
+	logger   log.Logger
+	orgID    int64
+	interval time.Duration
+	save     func(ctx context.Context, deliveries []ngmodels.NotificationDelivery) error
+
+	mtx        sync.Mutex
+	attempts   map[deliveryAttemptKey]int
+	deliveries []ngmodels.NotificationDelivery
+
+	stopc chan struct{}
+	done  chan struct{}
+}
+
+func newDeliveryRecorder(
+	logger log.Logger,
+	orgID int64,
+	interval time.Duration,
+	save func(ctx context.Context, deliveries []ngmodels.NotificationDelivery) error,
+) *deliveryRecorder {
+	return &deliveryRecorder{
+		logger:   logger,
+		orgID:    orgID,
+		interval: interval,
+		save:     save,
+		attempts: map[deliveryAttemptKey]int{},
+		stopc:    make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+func (r *deliveryRecorder) run() {
+	defer close(r.done)
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.stopc:
+			r.flush(time.Now())
+			return
+		case now := <-ticker.C:
+			r.flush(now)
+		}
+	}
+}
+
+func (r *deliveryRecorder) stop() {
+	close(r.stopc)
+	<-r.done
+}
+
+// flush writes the deliveries recorded since the previous call, and forgets the attempts of old notifications.
+func (r *deliveryRecorder) flush(now time.Time) {
+	r.mtx.Lock()
+	deliveries := r.deliveries
+	r.deliveries = nil
+	for key := range r.attempts {
+		if now.Sub(key.flushedAt) > deliveryAttemptsRetention {
+			delete(r.attempts, key)
+		}
+	}
+	r.mtx.Unlock()
+
+	if len(deliveries) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), deliverySaveTimeout)
+	defer cancel()
+	if err := r.save(ctx, deliveries); err != nil {
+		r.logger.Error("Failed to save notification deliveries", "deliveries", len(deliveries), "error", err)
+	}
+}
+
+// wrap returns the notifier of the integration at the given index of a receiver, which records its calls.
+func (r *deliveryRecorder) wrap(receiver string, index int, integration string, notifier notify.Notifier) notify.Notifier {
+	return &recordedNotifier{
+		recorder:    r,
+		integration: integration,
+		ref:         integrationRef{receiver: receiver, index: index},
+		notifier:    notifier,
+	}
+}
+
+// record adds the call of an integration to the deliveries that are written at the next interval.
+func (r *deliveryRecorder) record(ctx context.Context, integration string, ref integrationRef, alerts []*types.Alert, notifyErr error, at time.Time, duration time.Duration) {
+	groupKey, _ := notify.GroupKey(ctx)
+	flushedAt, ok := notify.Now(ctx)
+	if !ok {
+		flushedAt = at
+	}
+
+	delivery := ngmodels.NotificationDelivery{
+		OrgID:            r.orgID,
+		Receiver:         ref.receiver,
+		Integration:      integration,
+		IntegrationIndex: ref.index,
+		GroupKey:         groupKey,
+		Status:           ngmodels.NotificationDeliverySuccess,
+		Duration:         duration,
+		Time:             at,
+		Alerts:           deliveryAlerts(alerts, flushedAt),
+	}
+	if notifyErr != nil {
+		delivery.Status = ngmodels.NotificationDeliveryFailed
+		delivery.Error = notifyErr.Error()
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	key := deliveryAttemptKey{integrationRef: ref, groupKey: groupKey, flushedAt: flushedAt}
+	r.attempts[key]++
+	delivery.Attempt = r.attempts[key]
+	r.deliveries = append(r.deliveries, delivery)
+}
+
+// recordedNotifier is the notifier of an integration that records its calls in the delivery recorder.
+type recordedNotifier struct {
+	recorder    *deliveryRecorder
+	integration string
+	ref         integrationRef
+	notifier    notify.Notifier
+}
+
+func (n *recordedNotifier) Notify(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+	start := time.Now()
+	retry, err := n.notifier.Notify(ctx, alerts...)
+	n.recorder.record(ctx, n.integration, n.ref, alerts, err, start, time.Since(start))
+	return retry, err
+}
+
+func (n *recordedNotifier) SendResolved() bool {
+	if rs, ok := n.notifier.(notify.ResolvedSender); ok {
+		return rs.SendResolved()
+	}
+	return true
+}
+
+// deliveryAlerts returns the alerts of a notification, with their status at the time the notification was flushed.
+func deliveryAlerts(alerts []*types.Alert, flushedAt time.Time) []ngmodels.NotificationDeliveryAlert {
+	result := make([]ngmodels.NotificationDeliveryAlert, 0, len(alerts))
+	for _, alert := range alerts {
+		labels := make(map[string]string, len(alert.Labels))
+		for k, v := range alert.Labels {
+			labels[string(k)] = string(v)
+		}
+		status := model.AlertFiring
+		if alert.ResolvedAt(flushedAt) {
+			status = model.AlertResolved
+		}
+		result = append(result, ngmodels.NotificationDeliveryAlert{
+			RuleUID:     labels[alertingModels.RuleUIDLabel],
+			Fingerprint: deliveryAlertFingerprint(labels),
+			Status:      string(status),
+			StartsAt:    alert.StartsAt,
+		})
+	}
+	return result
+}
+
+// deliveryAlertFingerprint returns the fingerprint of the labels of an alert without the private labels, which is the
+// fingerprint of the alert instance in the state history.
+func deliveryAlertFingerprint(labels map[string]string) string {
+	public := make(map[string]string, len(labels))
+	for k, v := range labels {
+		if !strings.HasPrefix(k, "__") && !strings.HasSuffix(k, "__") {
+			public[k] = v
+		}
+	}
+	return fmt.Sprintf("%016x", model.LabelsToSignature(public))
+}
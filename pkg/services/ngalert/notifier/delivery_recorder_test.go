@@ -0,0 +1,106 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	alertingModels "github.com/grafana/alerting/models"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type fakeNotifier struct {
+	errs []error
+}
+
+func (n *fakeNotifier) Notify(_ context.Context, _ ...*types.Alert) (bool, error) {
+	var err error
+	if len(n.errs) > 0 {
+		err, n.errs = n.errs[0], n.errs[1:]
+	}
+	return err != nil, err
+}
+
+func TestDeliveryRecorder(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	alert := &types.Alert{Alert: model.Alert{
+		Labels: model.LabelSet{
+			"alertname":                      "HighLatency",
+			"cluster":                        "eu-1",
+			alertingModels.RuleUIDLabel:      "rule-1",
+			alertingModels.NamespaceUIDLabel: "folder-1",
+		},
+		StartsAt: start,
+	}}
+	notifyCtx := func(groupKey string, flushedAt time.Time) context.Context {
+		ctx := notify.WithReceiverName(context.Background(), "team-a")
+		ctx = notify.WithGroupKey(ctx, groupKey)
+		return notify.WithNow(ctx, flushedAt)
+	}
+
+	var saved []ngmodels.NotificationDelivery
+	r := newDeliveryRecorder(log.NewNopLogger(), 1, time.Second, func(_ context.Context, deliveries []ngmodels.NotificationDelivery) error {
+		saved = append(saved, deliveries...)
+		return nil
+	})
+	email := r.wrap("team-a", 1, "email", &fakeNotifier{errs: []error{errors.New("connection refused")}})
+
+	t.Run("records every attempt of a notification", func(t *testing.T) {
+		ctx := notifyCtx(`{}:{alertname="HighLatency"}`, start)
+		retry, err := email.Notify(ctx, alert)
+		require.True(t, retry)
+		require.EqualError(t, err, "connection refused")
+		_, err = email.Notify(ctx, alert)
+		require.NoError(t, err)
+
+		r.flush(start.Add(time.Minute))
+		require.Len(t, saved, 2)
+		require.Equal(t, ngmodels.NotificationDelivery{
+			OrgID:            1,
+			Receiver:         "team-a",
+			Integration:      "email",
+			IntegrationIndex: 1,
+			GroupKey:         `{}:{alertname="HighLatency"}`,
+			Attempt:          1,
+			Status:           ngmodels.NotificationDeliveryFailed,
+			Error:            "connection refused",
+			Duration:         saved[0].Duration,
+			Time:             saved[0].Time,
+			Alerts: []ngmodels.NotificationDeliveryAlert{{
+				RuleUID:     "rule-1",
+				Fingerprint: deliveryAlertFingerprint(map[string]string{"alertname": "HighLatency", "cluster": "eu-1"}),
+				Status:      string(model.AlertFiring),
+				StartsAt:    start,
+			}},
+		}, saved[0])
+		require.Equal(t, 2, saved[1].Attempt)
+		require.Equal(t, ngmodels.NotificationDeliverySuccess, saved[1].Status)
+		require.Empty(t, saved[1].Error)
+	})
+
+	t.Run("counts the attempts of each notification", func(t *testing.T) {
+		saved = nil
+		_, err := email.Notify(notifyCtx(`{}:{alertname="HighLatency"}`, start.Add(5*time.Minute)), alert)
+		require.NoError(t, err)
+		_, err = email.Notify(notifyCtx(`{}:{alertname="Other"}`, start.Add(5*time.Minute)), alert)
+		require.NoError(t, err)
+
+		r.flush(start.Add(6 * time.Minute))
+		require.Len(t, saved, 2)
+		require.Equal(t, 1, saved[0].Attempt)
+		require.Equal(t, 1, saved[1].Attempt)
+		require.Equal(t, `{}:{alertname="Other"}`, saved[1].GroupKey)
+	})
+
+	t.Run("forgets the attempts of old notifications", func(t *testing.T) {
+		r.flush(start.Add(2 * deliveryAttemptsRetention))
+		require.Empty(t, r.attempts)
+	})
+}
@@ -0,0 +1,265 @@
+package notifier
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	alertingNotify "github.com/grafana/alerting/notify"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// escalationInterval is how often the firing alerts are checked for escalation.
+const escalationInterval = 30 * time.Second
+
+// escalationKey identifies an alert, from the time it started firing, notified to the receiver of an escalation policy.
+type escalationKey struct {
+	fingerprint string
+	startsAt    time.Time
+	policy      string
+}
+
+// escalatedAlert is an alert that was sent to the receivers of the steps of an escalation policy.
+type escalatedAlert struct {
+	// alert is the last seen state of the escalated alert.
+	alert *amv2.GettableAlert
+	// steps are the steps that were sent, and the receivers they were sent to.
+	steps []escalationStep
+}
+
+type escalationStep struct {
+	step     int
+	receiver string
+}
+
+// escalator sends the alerts routed to the escalated receivers to the receivers of the steps of the escalation
+// policies when the alerts are still firing and not acknowledged after the delays of the steps.
+//
+// The alerts are sent through the notification pipeline as copies of the escalated alerts that are routed to the
+// receivers of the steps by the autogenerated routes. Therefore, the escalations are grouped, deduplicated and retried
+// like any other notification, and the receivers of the steps are notified when the escalated alerts resolve.
+// Only the first peer of the cluster sends escalations. The steps that were sent are kept in memory only.
+type escalator struct {
+	logger log.Logger
+	peer   alertingNotify.ClusterPeer
+	alerts func() (alertingNotify.GettableAlerts, error)
+	put    func(ctx context.Context, alerts apimodels.PostableAlerts) error
+
+	mtx sync.Mutex
+	// policies are the escalation policies by the name of the receiver they escalate.
+	policies  map[string]apimodels.EscalationPolicy
+	receivers map[string]struct{}
+	// escalated are the alerts that were sent to the receivers of at least one step.
+	escalated map[escalationKey]*escalatedAlert
+
+	stopc chan struct{}
+	done  chan struct{}
+}
+
+func newEscalator(
+	logger log.Logger,
+	peer alertingNotify.ClusterPeer,
+	alerts func() (alertingNotify.GettableAlerts, error),
+	put func(ctx context.Context, alerts apimodels.PostableAlerts) error,
+) *escalator {
+	return &escalator{
+		logger:    logger,
+		peer:      peer,
+		alerts:    alerts,
+		put:       put,
+		policies:  map[string]apimodels.EscalationPolicy{},
+		receivers: map[string]struct{}{},
+		escalated: map[escalationKey]*escalatedAlert{},
+		stopc:     make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+}
+
+// applyConfig replaces the escalation policies and the receivers of their steps with the ones of the configuration.
+func (e *escalator) applyConfig(cfg *apimodels.PostableUserConfig) {
+	policies := make(map[string]apimodels.EscalationPolicy, len(cfg.EscalationPolicies))
+	for _, p := range cfg.EscalationPolicies {
+		policies[p.Receiver] = p
+	}
+	receivers := make(map[string]struct{}, len(cfg.AlertmanagerConfig.Receivers))
+	for _, r := range cfg.AlertmanagerConfig.Receivers {
+		receivers[r.Name] = struct{}{}
+	}
+
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	e.policies = policies
+	e.receivers = receivers
+}
+
+func (e *escalator) run() {
+	defer close(e.done)
+	ticker := time.NewTicker(escalationInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-e.stopc:
+			return
+		case now := <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), escalationInterval)
+			e.escalate(ctx, now)
+			cancel()
+		}
+	}
+}
+
+func (e *escalator) stop() {
+	close(e.stopc)
+	<-e.done
+}
+
+// escalate sends the firing alerts to the receivers of the steps that are due, keeps sending them to the receivers of
+// the steps that were already sent, and resolves them for all these receivers once the alerts resolve.
+// If several steps of a policy are due, only the receiver of the last one is notified.
+// The steps are recorded as sent only if the alerts were accepted by the notification pipeline, otherwise they are
+// sent again at the next interval.
+func (e *escalator) escalate(ctx context.Context, now time.Time) {
+	if e.peer.Position() != 0 {
+		return
+	}
+
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	alerts, err := e.alerts()
+	if err != nil {
+		e.logger.Error("Failed to get alerts to escalate", "error", err)
+		return
+	}
+
+	var postable apimodels.PostableAlerts
+	var sent []log.Logger
+	next := make(map[escalationKey]*escalatedAlert, len(e.escalated))
+	for _, alert := range alerts {
+		if alert.Fingerprint == nil || alert.StartsAt == nil {
+			continue
+		}
+		// Skip the copies sent by the escalator.
+		if _, ok := alert.Labels[ngmodels.EscalationPolicyLabel]; ok {
+			continue
+		}
+		acknowledged := alert.Annotations[ngmodels.AcknowledgedByAnnotation] != ""
+		for _, r := range alert.Receivers {
+			if r == nil || r.Name == nil {
+				continue
+			}
+			policy, ok := e.policies[*r.Name]
+			if !ok {
+				continue
+			}
+			key := escalationKey{fingerprint: *alert.Fingerprint, startsAt: time.Time(*alert.StartsAt), policy: policy.Name}
+			escalated := &escalatedAlert{alert: alert}
+			if current, ok := e.escalated[key]; ok {
+				escalated.steps = slices.Clone(current.steps)
+			}
+			if due := dueSteps(policy, now.Sub(key.startsAt)); !acknowledged && due > escalated.lastStep() {
+				step := policy.Steps[due-1]
+				logger := e.logger.New("fingerprint", key.fingerprint, "policy", policy.Name, "step", due, "receiver", step.Receiver)
+				if _, ok := e.receivers[step.Receiver]; ok {
+					escalated.steps = append(escalated.steps, escalationStep{step: due, receiver: step.Receiver})
+					sent = append(sent, logger)
+				} else {
+					logger.Warn("Receiver of escalation step does not exist")
+				}
+			}
+			if len(escalated.steps) == 0 {
+				continue
+			}
+			next[key] = escalated
+			// Send the alert to the receivers of all the steps that were sent so far to keep them firing.
+			postable.PostableAlerts = append(postable.PostableAlerts, escalated.postableAlerts(policy.Name, nil)...)
+		}
+	}
+
+	// Resolve the alerts that are resolved or not routed to an escalated receiver anymore.
+	resolvedAt := strfmt.DateTime(now)
+	for key, escalated := range e.escalated {
+		if _, ok := next[key]; ok {
+			continue
+		}
+		postable.PostableAlerts = append(postable.PostableAlerts, escalated.postableAlerts(key.policy, &resolvedAt)...)
+	}
+
+	if len(postable.PostableAlerts) > 0 {
+		if err := e.put(ctx, postable); err != nil {
+			e.logger.Error("Failed to send escalated alerts", "error", err)
+			return
+		}
+	}
+	for _, logger := range sent {
+		logger.Info("Alert was escalated")
+	}
+	e.escalated = next
+}
+
+// lastStep returns the last step that was sent, or 0 if none was sent.
+func (a *escalatedAlert) lastStep() int {
+	if len(a.steps) == 0 {
+		return 0
+	}
+	return a.steps[len(a.steps)-1].step
+}
+
+// postableAlerts returns the copies of the alert that are routed to the receivers of the steps that were sent.
+// The copies end at endsAt if it is set, and when the alert ends otherwise.
+func (a *escalatedAlert) postableAlerts(policy string, endsAt *strfmt.DateTime) []amv2.PostableAlert {
+	result := make([]amv2.PostableAlert, 0, len(a.steps))
+	for _, s := range a.steps {
+		labels := make(amv2.LabelSet, len(a.alert.Labels)+4)
+		for k, v := range a.alert.Labels {
+			labels[k] = v
+		}
+		// Route the copy to the receiver of the step with the default settings of the autogenerated route.
+		delete(labels, ngmodels.AutogeneratedRouteSettingsHashLabel)
+		labels[ngmodels.AutogeneratedRouteLabel] = "true"
+		labels[ngmodels.AutogeneratedRouteReceiverNameLabel] = s.receiver
+		labels[ngmodels.EscalationPolicyLabel] = policy
+		labels[ngmodels.EscalationStepLabel] = strconv.Itoa(s.step)
+
+		annotations := make(amv2.LabelSet, len(a.alert.Annotations))
+		for k, v := range a.alert.Annotations {
+			annotations[k] = v
+		}
+
+		alert := amv2.PostableAlert{
+			Annotations: annotations,
+			StartsAt:    *a.alert.StartsAt,
+			Alert: amv2.Alert{
+				GeneratorURL: a.alert.GeneratorURL,
+				Labels:       labels,
+			},
+		}
+		switch {
+		case endsAt != nil:
+			alert.EndsAt = *endsAt
+		case a.alert.EndsAt != nil:
+			alert.EndsAt = *a.alert.EndsAt
+		}
+		result = append(result, alert)
+	}
+	return result
+}
+
+// dueSteps returns the number of steps of the policy whose delay has elapsed after the alert has been firing for the duration.
+func dueSteps(policy apimodels.EscalationPolicy, firingFor time.Duration) int {
+	due := 0
+	for _, step := range policy.Steps {
+		if time.Duration(step.After) > firingFor {
+			break
+		}
+		due++
+	}
+	return due
+}
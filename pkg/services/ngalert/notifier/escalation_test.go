@@ -0,0 +1,240 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	alertingNotify "github.com/grafana/alerting/notify"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestDueSteps(t *testing.T) {
+	policy := apimodels.EscalationPolicy{
+		Name:     "on-call",
+		Receiver: "team-a",
+		Steps: []apimodels.EscalationStep{
+			{After: model.Duration(15 * time.Minute), Receiver: "team-a-lead"},
+			{After: model.Duration(time.Hour), Receiver: "team-a-manager"},
+		},
+	}
+
+	require.Equal(t, 0, dueSteps(policy, 14*time.Minute))
+	require.Equal(t, 1, dueSteps(policy, 15*time.Minute))
+	require.Equal(t, 1, dueSteps(policy, 59*time.Minute))
+	require.Equal(t, 2, dueSteps(policy, 2*time.Hour))
+}
+
+func TestEscalatorDueEscalations(t *testing.T) {
+	startsAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cfg := &apimodels.PostableUserConfig{
+		AlertmanagerConfig: apimodels.PostableApiAlertingConfig{
+			Receivers: []*apimodels.PostableApiReceiver{
+				{Receiver: config.Receiver{Name: "team-a"}},
+				{Receiver: config.Receiver{Name: "team-a-lead"}},
+				{Receiver: config.Receiver{Name: "team-a-manager"}},
+			},
+		},
+		EscalationPolicies: []apimodels.EscalationPolicy{
+			{
+				Name:     "on-call",
+				Receiver: "team-a",
+				Steps: []apimodels.EscalationStep{
+					{After: model.Duration(15 * time.Minute), Receiver: "team-a-lead"},
+					{After: model.Duration(time.Hour), Receiver: "team-a-manager"},
+				},
+			},
+		},
+	}
+	newAlert := func(fingerprint, receiver string, annotations amv2.LabelSet) *amv2.GettableAlert {
+		s := strfmt.DateTime(startsAt)
+		e := strfmt.DateTime(startsAt.Add(3 * time.Hour))
+		return &amv2.GettableAlert{
+			Alert: amv2.Alert{
+				Labels: amv2.LabelSet{
+					"alertname":                                  "test",
+					ngmodels.AutogeneratedRouteLabel:             "true",
+					ngmodels.AutogeneratedRouteReceiverNameLabel: receiver,
+					ngmodels.AutogeneratedRouteSettingsHashLabel: "hash",
+				},
+			},
+			Annotations: annotations,
+			Fingerprint: &fingerprint,
+			StartsAt:    &s,
+			EndsAt:      &e,
+			Receivers:   []*amv2.Receiver{{Name: &receiver}},
+		}
+	}
+	// sentSteps returns the receivers and the steps of the alerts that were sent, and whether they are resolved at the time.
+	sentSteps := func(alerts apimodels.PostableAlerts, at time.Time) []string {
+		var result []string
+		for _, a := range alerts.PostableAlerts {
+			step := a.Labels[ngmodels.AutogeneratedRouteReceiverNameLabel] + "/" + a.Labels[ngmodels.EscalationStepLabel]
+			if !time.Time(a.EndsAt).After(at) {
+				step += "/resolved"
+			}
+			result = append(result, step)
+		}
+		return result
+	}
+	newEscalatorWithAlerts := func(alerts *alertingNotify.GettableAlerts, sent *[]apimodels.PostableAlerts, err *error) *escalator {
+		e := newEscalator(log.NewNopLogger(), &NilPeer{},
+			func() (alertingNotify.GettableAlerts, error) { return *alerts, nil },
+			func(_ context.Context, alerts apimodels.PostableAlerts) error {
+				if *err != nil {
+					return *err
+				}
+				*sent = append(*sent, alerts)
+				return nil
+			})
+		e.applyConfig(cfg)
+		return e
+	}
+
+	t.Run("sends alerts routed to the escalated receiver to the receivers of the due steps", func(t *testing.T) {
+		alerts := alertingNotify.GettableAlerts{newAlert("a", "team-a", nil), newAlert("b", "other", nil)}
+		var sent []apimodels.PostableAlerts
+		var err error
+		e := newEscalatorWithAlerts(&alerts, &sent, &err)
+
+		e.escalate(context.Background(), startsAt.Add(10*time.Minute))
+		require.Empty(t, sent)
+
+		now := startsAt.Add(20 * time.Minute)
+		e.escalate(context.Background(), now)
+		require.Len(t, sent, 1)
+		require.Equal(t, []string{"team-a-lead/1"}, sentSteps(sent[0], now))
+
+		now = startsAt.Add(30 * time.Minute)
+		e.escalate(context.Background(), now)
+		require.Equal(t, []string{"team-a-lead/1"}, sentSteps(sent[1], now))
+
+		now = startsAt.Add(2 * time.Hour)
+		e.escalate(context.Background(), now)
+		require.Equal(t, []string{"team-a-lead/1", "team-a-manager/2"}, sentSteps(sent[2], now))
+	})
+
+	t.Run("routes the copies to the receiver of the step with the labels and annotations of the alert", func(t *testing.T) {
+		alerts := alertingNotify.GettableAlerts{newAlert("a", "team-a", amv2.LabelSet{"summary": "test"})}
+		var sent []apimodels.PostableAlerts
+		var err error
+		e := newEscalatorWithAlerts(&alerts, &sent, &err)
+
+		e.escalate(context.Background(), startsAt.Add(20*time.Minute))
+		require.Len(t, sent, 1)
+		require.Len(t, sent[0].PostableAlerts, 1)
+		alert := sent[0].PostableAlerts[0]
+		require.Equal(t, amv2.LabelSet{
+			"alertname":                                  "test",
+			ngmodels.AutogeneratedRouteLabel:             "true",
+			ngmodels.AutogeneratedRouteReceiverNameLabel: "team-a-lead",
+			ngmodels.EscalationPolicyLabel:               "on-call",
+			ngmodels.EscalationStepLabel:                 "1",
+		}, alert.Labels)
+		require.Equal(t, amv2.LabelSet{"summary": "test"}, alert.Annotations)
+		require.Equal(t, *alerts[0].StartsAt, alert.StartsAt)
+		require.Equal(t, *alerts[0].EndsAt, alert.EndsAt)
+	})
+
+	t.Run("sends only the last step when several steps are due", func(t *testing.T) {
+		alerts := alertingNotify.GettableAlerts{newAlert("a", "team-a", nil)}
+		var sent []apimodels.PostableAlerts
+		var err error
+		e := newEscalatorWithAlerts(&alerts, &sent, &err)
+
+		now := startsAt.Add(2 * time.Hour)
+		e.escalate(context.Background(), now)
+		require.Len(t, sent, 1)
+		require.Equal(t, []string{"team-a-manager/2"}, sentSteps(sent[0], now))
+	})
+
+	t.Run("does not escalate acknowledged alerts", func(t *testing.T) {
+		alerts := alertingNotify.GettableAlerts{newAlert("a", "team-a", amv2.LabelSet{ngmodels.AcknowledgedByAnnotation: "admin"})}
+		var sent []apimodels.PostableAlerts
+		var err error
+		e := newEscalatorWithAlerts(&alerts, &sent, &err)
+
+		e.escalate(context.Background(), startsAt.Add(2*time.Hour))
+		require.Empty(t, sent)
+	})
+
+	t.Run("does not escalate the copies of escalated alerts", func(t *testing.T) {
+		copied := newAlert("copy", "team-a", nil)
+		copied.Labels[ngmodels.EscalationPolicyLabel] = "on-call"
+		alerts := alertingNotify.GettableAlerts{copied}
+		var sent []apimodels.PostableAlerts
+		var err error
+		e := newEscalatorWithAlerts(&alerts, &sent, &err)
+
+		e.escalate(context.Background(), startsAt.Add(2*time.Hour))
+		require.Empty(t, sent)
+	})
+
+	t.Run("resolves the copies when the alert is not firing anymore", func(t *testing.T) {
+		alerts := alertingNotify.GettableAlerts{newAlert("a", "team-a", nil)}
+		var sent []apimodels.PostableAlerts
+		var err error
+		e := newEscalatorWithAlerts(&alerts, &sent, &err)
+
+		e.escalate(context.Background(), startsAt.Add(20*time.Minute))
+		require.Len(t, e.escalated, 1)
+
+		alerts = nil
+		now := startsAt.Add(30 * time.Minute)
+		e.escalate(context.Background(), now)
+		require.Len(t, sent, 2)
+		require.Equal(t, []string{"team-a-lead/1/resolved"}, sentSteps(sent[1], now))
+		require.Empty(t, e.escalated)
+
+		e.escalate(context.Background(), startsAt.Add(40*time.Minute))
+		require.Len(t, sent, 2)
+	})
+
+	t.Run("does not record the step if the alerts could not be sent", func(t *testing.T) {
+		alerts := alertingNotify.GettableAlerts{newAlert("a", "team-a", nil)}
+		var sent []apimodels.PostableAlerts
+		err := errors.New("failed")
+		e := newEscalatorWithAlerts(&alerts, &sent, &err)
+
+		e.escalate(context.Background(), startsAt.Add(20*time.Minute))
+		require.Empty(t, sent)
+		require.Empty(t, e.escalated)
+
+		err = nil
+		now := startsAt.Add(21 * time.Minute)
+		e.escalate(context.Background(), now)
+		require.Len(t, sent, 1)
+		require.Equal(t, []string{"team-a-lead/1"}, sentSteps(sent[0], now))
+	})
+
+	t.Run("does not record the step if its receiver does not exist", func(t *testing.T) {
+		alerts := alertingNotify.GettableAlerts{newAlert("a", "team-a", nil)}
+		var sent []apimodels.PostableAlerts
+		var err error
+		e := newEscalatorWithAlerts(&alerts, &sent, &err)
+		e.applyConfig(&apimodels.PostableUserConfig{
+			AlertmanagerConfig: apimodels.PostableApiAlertingConfig{
+				Receivers: []*apimodels.PostableApiReceiver{{Receiver: config.Receiver{Name: "team-a"}}},
+			},
+			EscalationPolicies: cfg.EscalationPolicies,
+		})
+
+		e.escalate(context.Background(), startsAt.Add(20*time.Minute))
+		require.Empty(t, sent)
+		require.Empty(t, e.escalated)
+
+		e.applyConfig(cfg)
+		now := startsAt.Add(21 * time.Minute)
+		e.escalate(context.Background(), now)
+		require.Equal(t, []string{"team-a-lead/1"}, sentSteps(sent[0], now))
+	})
+}
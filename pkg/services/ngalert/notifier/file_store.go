@@ -74,6 +74,12 @@ func (fileStore *FileStore) SaveNotificationLog(ctx context.Context, st alerting
 	return fileStore.persist(ctx, NotificationLogFilename, st)
 }
 
+// SaveContent replaces the content for the given Alertmanager kvstore key, for example with the content of the
+// same file in another Grafana instance. It must not be called while the Alertmanager of the organization is running.
+func (fileStore *FileStore) SaveContent(ctx context.Context, filename string, content []byte) error {
+	return fileStore.kv.Set(ctx, filename, encode(content))
+}
+
 // persist takes care of persisting the binary representation of internal state to the database as a base64 encoded string.
 func (fileStore *FileStore) persist(ctx context.Context, filename string, st alertingNotify.State) (int64, error) {
 	var size int64
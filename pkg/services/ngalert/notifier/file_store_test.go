@@ -106,3 +106,24 @@ func TestFileStore_NotificationLog(t *testing.T) {
 		t.Errorf("Unexpected Diff: %v", cmp.Diff(newState, decoded))
 	}
 }
+
+func TestFileStore_SaveContent(t *testing.T) {
+	store := fakes.NewFakeKVStore(t)
+	ctx := context.Background()
+	fs := NewFileStore(1, store)
+
+	now := time.Now()
+	state := silenceState{"1": createSilence("1", now, now.Add(time.Hour))}
+	content, err := state.MarshalBinary()
+	require.NoError(t, err)
+	require.NoError(t, fs.SaveContent(ctx, SilencesFilename, content))
+
+	silences, err := fs.GetSilences(ctx)
+	require.NoError(t, err)
+	require.Equal(t, string(content), silences)
+
+	// The content of other organizations is not changed.
+	other, err := NewFileStore(2, store).GetSilences(ctx)
+	require.NoError(t, err)
+	require.Empty(t, other)
+}
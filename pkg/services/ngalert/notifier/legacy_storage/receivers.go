@@ -75,6 +75,21 @@ func (rev *ConfigRevision) ReceiverNameUsedByRoutes(name string) bool {
 	return isReceiverInUse(name, []*definitions.Route{rev.Config.AlertmanagerConfig.Route})
 }
 
+// ReceiverNameUsedByEscalationPolicies checks if a receiver name is escalated or notified by any escalation policy.
+func (rev *ConfigRevision) ReceiverNameUsedByEscalationPolicies(name string) bool {
+	for _, p := range rev.Config.EscalationPolicies {
+		if p.Receiver == name {
+			return true
+		}
+		for _, step := range p.Steps {
+			if step.Receiver == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // ReceiverUseByName returns a map of receiver names to the number of times they are used in routes.
 func (rev *ConfigRevision) ReceiverUseByName() map[string]int {
 	m := make(map[string]int)
@@ -106,6 +121,29 @@ func (rev *ConfigRevision) RenameReceiverInRoutes(oldName, newName string) int {
 	return RenameReceiverInRoute(oldName, newName, rev.Config.AlertmanagerConfig.Route)
 }
 
+// RenameReceiverInEscalationPolicies renames all references to a receiver in escalation policies. Returns number of policies that were updated
+func (rev *ConfigRevision) RenameReceiverInEscalationPolicies(oldName, newName string) int {
+	updated := 0
+	for i := range rev.Config.EscalationPolicies {
+		p := &rev.Config.EscalationPolicies[i]
+		changed := false
+		if p.Receiver == oldName {
+			p.Receiver = newName
+			changed = true
+		}
+		for j := range p.Steps {
+			if p.Steps[j].Receiver == oldName {
+				p.Steps[j].Receiver = newName
+				changed = true
+			}
+		}
+		if changed {
+			updated++
+		}
+	}
+	return updated
+}
+
 // ValidateReceiver checks if the given receiver conflicts in name or integration UID with existing receivers.
 // We only check the receiver being modified to prevent existing issues from other receivers being reported.
 func (rev *ConfigRevision) ValidateReceiver(p *definitions.PostableApiReceiver) error {
@@ -83,6 +83,9 @@ type MultiOrgAlertmanager struct {
 
 	alertmanagersMtx sync.RWMutex
 	alertmanagers    map[int64]Alertmanager
+	// replacingState are the organizations whose Alertmanager is stopped while its state is replaced.
+	// Their Alertmanagers are not created by the sync until the state is replaced.
+	replacingState map[int64]struct{}
 
 	settings       *setting.Cfg
 	featureManager featuremgmt.FeatureToggles
@@ -103,6 +106,9 @@ type MultiOrgAlertmanager struct {
 	ns      notifications.Service
 
 	receiverResourcePermissions ac.ReceiverPermissionsService
+
+	// deliveryLog is nil if the notification delivery log is disabled.
+	deliveryLog *NotificationDeliveryLog
 }
 
 type OrgAlertmanagerFactory func(ctx context.Context, orgID int64) (Alertmanager, error)
@@ -115,6 +121,14 @@ func WithAlertmanagerOverride(f func(OrgAlertmanagerFactory) OrgAlertmanagerFact
 	}
 }
 
+// WithNotificationDeliveryLog records the attempts of the integrations of the Alertmanagers to send notifications
+// in the notification delivery log.
+func WithNotificationDeliveryLog(deliveryLog *NotificationDeliveryLog) Option {
+	return func(moa *MultiOrgAlertmanager) {
+		moa.deliveryLog = deliveryLog
+	}
+}
+
 func NewMultiOrgAlertmanager(
 	cfg *setting.Cfg,
 	configStore AlertingStore,
@@ -138,6 +152,7 @@ func NewMultiOrgAlertmanager(
 		settings:                    cfg,
 		featureManager:              featureManager,
 		alertmanagers:               map[int64]Alertmanager{},
+		replacingState:              map[int64]struct{}{},
 		configStore:                 configStore,
 		orgStore:                    orgStore,
 		kvStore:                     kvStore,
@@ -160,7 +175,7 @@ func NewMultiOrgAlertmanager(
 	moa.factory = func(ctx context.Context, orgID int64) (Alertmanager, error) {
 		m := metrics.NewAlertmanagerMetrics(moa.metrics.GetOrCreateOrgRegistry(orgID), l)
 		stateStore := NewFileStore(orgID, kvStore)
-		return NewAlertmanager(ctx, orgID, moa.settings, moa.configStore, stateStore, moa.peer, moa.decryptFn, moa.ns, m, featureManager)
+		return NewAlertmanager(ctx, orgID, moa.settings, moa.configStore, stateStore, moa.peer, moa.decryptFn, moa.ns, m, featureManager, moa.deliveryLog)
 	}
 
 	for _, opt := range opts {
@@ -269,6 +284,92 @@ func (moa *MultiOrgAlertmanager) LoadAndSyncAlertmanagersForOrgs(ctx context.Con
 	return nil
 }
 
+// ReplaceAlertmanagerState stops the Alertmanager of the organization, calls replace to replace its state in the
+// kvstore, and starts it again so that it loads the new state. The Alertmanager is stopped first because it saves
+// its state to the kvstore when it stops, which would overwrite the new state. The sync does not create the
+// Alertmanager of the organization until the state is replaced, so that it does not load the previous state.
+func (moa *MultiOrgAlertmanager) ReplaceAlertmanagerState(ctx context.Context, orgID int64, replace func(ctx context.Context) error) error {
+	moa.alertmanagersMtx.Lock()
+	if _, replacing := moa.replacingState[orgID]; replacing {
+		moa.alertmanagersMtx.Unlock()
+		return fmt.Errorf("the state of the Alertmanager of organization %d is already being replaced", orgID)
+	}
+	moa.replacingState[orgID] = struct{}{}
+	am, found := moa.alertmanagers[orgID]
+	if found {
+		delete(moa.alertmanagers, orgID)
+		moa.metrics.RemoveOrgRegistry(orgID)
+	}
+	moa.alertmanagersMtx.Unlock()
+
+	if found {
+		moa.logger.Info("Stopping Alertmanager to replace its state", "org", orgID)
+		am.StopAndWait()
+	}
+
+	err := replace(ctx)
+
+	moa.alertmanagersMtx.Lock()
+	delete(moa.replacingState, orgID)
+	moa.alertmanagersMtx.Unlock()
+	// Start the Alertmanager again even if its state could not be replaced.
+	if syncErr := moa.LoadAndSyncAlertmanagersForOrgs(ctx); syncErr != nil {
+		return errors.Join(err, fmt.Errorf("failed to start the Alertmanager: %w", syncErr))
+	}
+	return err
+}
+
+// AlertmanagerState returns the current silences and notification log of the Alertmanager of the organization in the
+// binary format that is saved to the kvstore. The state is read from the running Alertmanager because the kvstore
+// is only updated by its maintenance. If the organization has no running Alertmanager, or it does not expose its
+// notification log, the state is read from the kvstore.
+func (moa *MultiOrgAlertmanager) AlertmanagerState(ctx context.Context, orgID int64) ([]byte, []byte, error) {
+	fileStore := NewFileStore(orgID, moa.kvStore)
+	orgAM, err := moa.AlertmanagerFor(orgID)
+	if err != nil {
+		if !errors.Is(err, ErrNoAlertmanagerForOrg) && !errors.Is(err, ErrAlertmanagerNotReady) {
+			return nil, nil, err
+		}
+		return stateFromFileStore(ctx, fileStore)
+	}
+
+	silenceState, err := orgAM.SilenceState(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get the silences: %w", err)
+	}
+	silences, err := silenceState.MarshalBinary()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal the silences: %w", err)
+	}
+
+	var nflog []byte
+	if am, ok := orgAM.(*alertmanager); ok {
+		nflog, err = am.NotificationLogState(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get the notification log: %w", err)
+		}
+	} else {
+		content, err := fileStore.GetNotificationLog(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		nflog = []byte(content)
+	}
+	return silences, nflog, nil
+}
+
+func stateFromFileStore(ctx context.Context, fileStore *FileStore) ([]byte, []byte, error) {
+	silences, err := fileStore.GetSilences(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	nflog, err := fileStore.GetNotificationLog(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return []byte(silences), []byte(nflog), nil
+}
+
 // getLatestConfigs retrieves the latest Alertmanager configuration for every organization. It returns a map where the key is the ID of each organization and the value is the configuration.
 func (moa *MultiOrgAlertmanager) getLatestConfigs(ctx context.Context) (map[int64]*models.AlertConfiguration, error) {
 	configs, err := moa.configStore.GetAllLatestAlertmanagerConfiguration(ctx)
@@ -299,6 +400,10 @@ func (moa *MultiOrgAlertmanager) SyncAlertmanagersForOrgs(ctx context.Context, o
 			continue
 		}
 		orgsFound[orgID] = struct{}{}
+		if _, replacing := moa.replacingState[orgID]; replacing {
+			moa.logger.Debug("Skipping syncing Alertmanager for org whose state is being replaced", "org", orgID)
+			continue
+		}
 
 		alertmanager, found := moa.alertmanagers[orgID]
 
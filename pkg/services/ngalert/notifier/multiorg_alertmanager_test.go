@@ -196,6 +196,35 @@ func TestMultiOrgAlertmanager_SyncAlertmanagersForOrgsWithFailures(t *testing.T)
 	}
 }
 
+func TestMultiOrgAlertmanager_ReplaceAlertmanagerState(t *testing.T) {
+	mam := setupMam(t, nil)
+	ctx := context.Background()
+	require.NoError(t, mam.LoadAndSyncAlertmanagersForOrgs(ctx))
+	previous, err := mam.AlertmanagerFor(1)
+	require.NoError(t, err)
+
+	replaced := false
+	err = mam.ReplaceAlertmanagerState(ctx, 1, func(ctx context.Context) error {
+		// The sync of the Run loop must not create the Alertmanager from the previous state.
+		require.NoError(t, mam.LoadAndSyncAlertmanagersForOrgs(ctx))
+		_, err := mam.AlertmanagerFor(1)
+		require.ErrorIs(t, err, ErrNoAlertmanagerForOrg)
+		_, err = mam.AlertmanagerFor(2)
+		require.NoError(t, err)
+
+		require.Error(t, mam.ReplaceAlertmanagerState(ctx, 1, func(ctx context.Context) error { return nil }))
+		replaced = true
+		return nil
+	})
+	require.NoError(t, err)
+	require.True(t, replaced)
+
+	current, err := mam.AlertmanagerFor(1)
+	require.NoError(t, err)
+	require.NotSame(t, previous, current)
+	require.Empty(t, mam.replacingState)
+}
+
 func TestMultiOrgAlertmanager_AlertmanagerFor(t *testing.T) {
 	mam := setupMam(t, nil)
 	ctx := context.Background()
@@ -286,6 +315,59 @@ func TestMultiOrgAlertmanager_ActivateHistoricalConfiguration(t *testing.T) {
 	require.JSONEq(t, defaultConfig, cfgs[2].AlertmanagerConfiguration)
 }
 
+func TestMultiOrgAlertmanager_UpdateAndApplyAlertmanagerConfiguration(t *testing.T) {
+	mam := setupMam(t, nil)
+	ctx := context.Background()
+	require.NoError(t, mam.LoadAndSyncAlertmanagersForOrgs(ctx))
+
+	newConfig := `{"template_files":null,"alertmanager_config":{"route":{"receiver":"grafana-default-email","group_by":["grafana_folder","alertname"]},"receivers":[{"name":"grafana-default-email","grafana_managed_receiver_configs":[{"uid":"","name":"some other name","type":"email","disableResolveMessage":false,"settings":{"addresses":"\u003cexample@email.com\u003e"}}]}]}}`
+	invalidConfig := `{"template_files":null,"alertmanager_config":{"route":{"receiver":"grafana-default-email"},"receivers":[{"name":"grafana-default-email","grafana_managed_receiver_configs":[{"uid":"","name":"some other name","type":"email","disableResolveMessage":false,"settings":{}}]}]}}`
+
+	t.Run("validation should not save the configuration", func(t *testing.T) {
+		postable, err := Load([]byte(newConfig))
+		require.NoError(t, err)
+		require.NoError(t, mam.ValidateAlertmanagerConfiguration(ctx, 2, postable))
+
+		cfgs, err := mam.getLatestConfigs(ctx)
+		require.NoError(t, err)
+		require.Equal(t, defaultConfig, cfgs[2].AlertmanagerConfiguration)
+	})
+
+	t.Run("validation should reject an invalid integration", func(t *testing.T) {
+		postable, err := Load([]byte(invalidConfig))
+		require.NoError(t, err)
+		err = mam.ValidateAlertmanagerConfiguration(ctx, 2, postable)
+		require.ErrorAs(t, err, &AlertmanagerConfigRejectedError{})
+	})
+
+	t.Run("update should fail if the configuration was changed after it was fetched", func(t *testing.T) {
+		fetched, err := mam.configStore.GetLatestAlertmanagerConfiguration(ctx, 3)
+		require.NoError(t, err)
+		postable, err := Load([]byte(newConfig))
+		require.NoError(t, err)
+		require.NoError(t, mam.UpdateAndApplyAlertmanagerConfiguration(ctx, 3, *postable, fetched.ConfigurationHash))
+
+		postable, err = Load([]byte(newConfig))
+		require.NoError(t, err)
+		postable.TemplateFiles = map[string]string{"a": `{{ define "a" }}a{{ end }}`}
+		err = mam.UpdateAndApplyAlertmanagerConfiguration(ctx, 3, *postable, fetched.ConfigurationHash)
+		require.ErrorIs(t, err, ErrAlertmanagerConfigConflict)
+	})
+
+	t.Run("update should not save an invalid configuration", func(t *testing.T) {
+		fetched, err := mam.configStore.GetLatestAlertmanagerConfiguration(ctx, 1)
+		require.NoError(t, err)
+		postable, err := Load([]byte(invalidConfig))
+		require.NoError(t, err)
+		err = mam.UpdateAndApplyAlertmanagerConfiguration(ctx, 1, *postable, fetched.ConfigurationHash)
+		require.ErrorAs(t, err, &AlertmanagerConfigRejectedError{})
+
+		cfgs, err := mam.getLatestConfigs(ctx)
+		require.NoError(t, err)
+		require.Equal(t, fetched.ConfigurationHash, cfgs[1].ConfigurationHash)
+	})
+}
+
 func TestMultiOrgAlertmanager_Silences(t *testing.T) {
 	mam := setupMam(t, nil)
 	ctx := context.Background()
@@ -360,14 +360,15 @@ func (rs *ReceiverService) DeleteReceiver(ctx context.Context, uid string, calle
 	}
 
 	usedByRoutes := revision.ReceiverNameUsedByRoutes(existing.Name)
+	usedByEscalationPolicies := revision.ReceiverNameUsedByEscalationPolicies(existing.Name)
 	usedByRules, err := rs.UsedByRules(ctx, orgID, existing.Name)
 	if err != nil {
 		return err
 	}
 
-	if usedByRoutes || len(usedByRules) > 0 {
-		logger.Warn("Cannot delete receiver because it is used", "used_by_routes", usedByRoutes, "used_by_rules", len(usedByRules))
-		return makeReceiverInUseErr(usedByRoutes, usedByRules)
+	if usedByRoutes || usedByEscalationPolicies || len(usedByRules) > 0 {
+		logger.Warn("Cannot delete receiver because it is used", "used_by_routes", usedByRoutes, "used_by_escalation_policies", usedByEscalationPolicies, "used_by_rules", len(usedByRules))
+		return makeReceiverInUseErr(usedByRoutes, usedByEscalationPolicies, usedByRules)
 	}
 
 	revision.DeleteReceiver(uid)
@@ -540,6 +541,7 @@ func (rs *ReceiverService) UpdateReceiver(ctx context.Context, r *models.Receive
 			if err != nil {
 				return err
 			}
+			revision.RenameReceiverInEscalationPolicies(existing.Name, r.Name)
 			// Update receiver permissions
 			permissionsUpdated, err := rs.resourcePermissions.CopyPermissions(ctx, orgID, user, legacy_storage.NameToUid(existing.Name), legacy_storage.NameToUid(r.Name))
 			if err != nil {
@@ -713,7 +715,7 @@ func limitOffset[T any](items []T, offset, limit int) []T {
 	return items[offset : offset+limit]
 }
 
-func makeReceiverInUseErr(usedByRoutes bool, rules []models.AlertRuleKey) error {
+func makeReceiverInUseErr(usedByRoutes bool, usedByEscalationPolicies bool, rules []models.AlertRuleKey) error {
 	uids := make([]string, 0, len(rules))
 	for _, key := range rules {
 		uids = append(uids, key.UID)
@@ -729,6 +731,10 @@ func makeReceiverInUseErr(usedByRoutes bool, rules []models.AlertRuleKey) error
 		usedBy = append(usedBy, "one or more routes")
 		data["UsedByRoutes"] = true
 	}
+	if usedByEscalationPolicies {
+		usedBy = append(usedBy, "one or more escalation policies")
+		data["UsedByEscalationPolicies"] = true
+	}
 	if len(usedBy) > 0 {
 		data["UsedBy"] = strings.Join(usedBy, ", ")
 	}
@@ -247,7 +247,7 @@ func TestReceiverService_Delete(t *testing.T) {
 			user:        writer,
 			deleteUID:   legacy_storage.NameToUid("grafana-default-email"),
 			version:     "cd95627c75892a39", // Correct version for grafana-default-email.
-			expectedErr: makeReceiverInUseErr(true, nil),
+			expectedErr: makeReceiverInUseErr(true, false, nil),
 		},
 		{
 			name:      "delete receiver used by rule fails",
@@ -259,7 +259,7 @@ func TestReceiverService_Delete(t *testing.T) {
 					models.NotificationSettingsGen(models.NSMuts.WithReceiver(baseReceiver.Name))(),
 				},
 			},
-			expectedErr: makeReceiverInUseErr(false, []models.AlertRuleKey{{OrgID: 1, UID: "rule1"}}),
+			expectedErr: makeReceiverInUseErr(false, false, []models.AlertRuleKey{{OrgID: 1, UID: "rule1"}}),
 		},
 		{
 			name:             "delete provisioning provenance fails when caller is ProvenanceNone",
@@ -0,0 +1,196 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/benbjohnson/clock"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// RecurringSilenceStore is the store of recurring silences and the state of their materialization.
+type RecurringSilenceStore interface {
+	ListRecurringSilences(ctx context.Context, query *models.ListRecurringSilencesQuery) ([]*models.RecurringSilence, error)
+	ListRecurringSilenceStates(ctx context.Context) ([]*models.RecurringSilenceState, error)
+	SaveRecurringSilenceState(ctx context.Context, state models.RecurringSilenceState) (models.RecurringSilenceState, error)
+	DeleteRecurringSilenceState(ctx context.Context, state models.RecurringSilenceState) error
+}
+
+type RecurringSilenceMaterializerCfg struct {
+	// Interval is how often the recurring silences are materialized.
+	Interval time.Duration
+	// Lookahead is how far ahead of time the silences are created.
+	Lookahead time.Duration
+	Clock     clock.Clock
+	Log       log.Logger
+}
+
+// RecurringSilenceMaterializer creates the Alertmanager silences of the windows of recurring silences ahead of time.
+// The state of the materialization is stored in the database with optimistic locking, so that when several instances
+// of Grafana run the materializer, only one of them creates the silences of a window. The silences created by the
+// other instances are expired right away.
+type RecurringSilenceMaterializer struct {
+	interval  time.Duration
+	lookahead time.Duration
+	store     RecurringSilenceStore
+	silences  SilenceStore
+	clock     clock.Clock
+	log       log.Logger
+}
+
+func NewRecurringSilenceMaterializer(cfg RecurringSilenceMaterializerCfg, store RecurringSilenceStore, silences SilenceStore) *RecurringSilenceMaterializer {
+	return &RecurringSilenceMaterializer{
+		interval:  cfg.Interval,
+		lookahead: cfg.Lookahead,
+		store:     store,
+		silences:  silences,
+		clock:     cfg.Clock,
+		log:       cfg.Log,
+	}
+}
+
+// Run materializes the recurring silences at every interval until the context is cancelled.
+func (m *RecurringSilenceMaterializer) Run(ctx context.Context) error {
+	m.log.Info("Starting materialization of recurring silences", "interval", m.interval, "lookahead", m.lookahead)
+	ticker := m.clock.Ticker(m.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			m.log.Info("Stopping materialization of recurring silences")
+			return nil
+		case now := <-ticker.C:
+			if err := m.Sync(ctx, now); err != nil {
+				m.log.Error("Failed to materialize recurring silences", "error", err)
+			}
+		}
+	}
+}
+
+// Sync reconciles the silences with the recurring silences of all organizations. It creates the silences of the windows
+// that start before now plus the lookahead, replaces the silences of recurring silences that were changed and
+// expires the silences of recurring silences that were deleted.
+func (m *RecurringSilenceMaterializer) Sync(ctx context.Context, now time.Time) error {
+	silences, err := m.store.ListRecurringSilences(ctx, &models.ListRecurringSilencesQuery{})
+	if err != nil {
+		return fmt.Errorf("failed to list recurring silences: %w", err)
+	}
+	states, err := m.store.ListRecurringSilenceStates(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list states of recurring silences: %w", err)
+	}
+	type key struct {
+		orgID int64
+		uid   string
+	}
+	byKey := make(map[key]*models.RecurringSilenceState, len(states))
+	for _, s := range states {
+		byKey[key{s.OrgID, s.UID}] = s
+	}
+
+	var errs []error
+	for _, s := range silences {
+		k := key{s.OrgID, s.UID}
+		state := byKey[k]
+		delete(byKey, k)
+		if state == nil {
+			state = &models.RecurringSilenceState{OrgID: s.OrgID, UID: s.UID}
+		}
+		if err := m.materialize(ctx, s, *state, now); err != nil {
+			errs = append(errs, fmt.Errorf("recurring silence %s in org %d: %w", s.UID, s.OrgID, err))
+		}
+	}
+	// The remaining states belong to recurring silences that were deleted.
+	for _, state := range byKey {
+		if err := m.store.DeleteRecurringSilenceState(ctx, *state); err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete state of recurring silence %s in org %d: %w", state.UID, state.OrgID, err))
+			continue
+		}
+		m.expire(ctx, state.OrgID, state.Silences, now)
+	}
+	return errors.Join(errs...)
+}
+
+func (m *RecurringSilenceMaterializer) materialize(ctx context.Context, s *models.RecurringSilence, state models.RecurringSilenceState, now time.Time) error {
+	changed := false
+	var stale []models.MaterializedSilence
+	if state.Version > 0 && state.RecurringSilenceVersion != s.Version {
+		// The recurring silence was changed, so its windows are materialized again from now on.
+		stale = state.Silences
+		state.Silences = nil
+		state.MaterializedUntil = time.Time{}
+		changed = true
+	}
+	state.RecurringSilenceVersion = s.Version
+
+	var active []models.MaterializedSilence
+	for _, sil := range state.Silences {
+		if sil.EndsAt.After(now) {
+			active = append(active, sil)
+		} else {
+			changed = true
+		}
+	}
+	state.Silences = active
+
+	from := state.MaterializedUntil
+	if from.Before(now) {
+		from = now
+	}
+	to := now.Add(m.lookahead)
+	var created []models.MaterializedSilence
+	if from.Before(to) {
+		windows, err := s.Schedule.Windows(from, to)
+		if err != nil {
+			return fmt.Errorf("failed to compute windows: %w", err)
+		}
+		for _, w := range windows {
+			id, err := m.silences.CreateSilence(ctx, s.OrgID, s.Silence(w))
+			if err != nil {
+				m.expire(ctx, s.OrgID, created, now)
+				return fmt.Errorf("failed to create silence: %w", err)
+			}
+			created = append(created, models.MaterializedSilence{ID: id, EndsAt: w.End})
+			if w.End.After(to) {
+				to = w.End
+			}
+		}
+		// Nothing needs to be saved if there are no windows: the same time is scanned again at the next sync.
+		if len(created) > 0 {
+			state.MaterializedUntil = to
+			state.Silences = append(state.Silences, created...)
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	if _, err := m.store.SaveRecurringSilenceState(ctx, state); err != nil {
+		// Another instance materialized the recurring silence in the meantime, or the state could not be saved.
+		// The silences created by this instance are not tracked, so they are expired.
+		m.expire(ctx, s.OrgID, created, now)
+		return fmt.Errorf("failed to save state: %w", err)
+	}
+	if len(created) > 0 {
+		m.log.Debug("Created silences of recurring silence", "org", s.OrgID, "uid", s.UID, "count", len(created), "materializedUntil", state.MaterializedUntil)
+	}
+	m.expire(ctx, s.OrgID, stale, now)
+	return nil
+}
+
+// expire expires the silences that have not ended yet.
+func (m *RecurringSilenceMaterializer) expire(ctx context.Context, orgID int64, silences []models.MaterializedSilence, now time.Time) {
+	for _, sil := range silences {
+		if !sil.EndsAt.After(now) {
+			continue
+		}
+		if err := m.silences.DeleteSilence(ctx, orgID, sil.ID); err != nil {
+			m.log.Warn("Failed to expire silence of recurring silence", "org", orgID, "silence", sil.ID, "error", err)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	ngfakes "github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+var errVersionConflict = errors.New("version conflict")
+
+type fakeRecurringSilenceStore struct {
+	silences []*models.RecurringSilence
+	states   map[string]models.RecurringSilenceState
+	// conflict makes saving a state fail as if another instance saved it first.
+	conflict bool
+}
+
+func (f *fakeRecurringSilenceStore) ListRecurringSilences(_ context.Context, _ *models.ListRecurringSilencesQuery) ([]*models.RecurringSilence, error) {
+	return f.silences, nil
+}
+
+func (f *fakeRecurringSilenceStore) ListRecurringSilenceStates(_ context.Context) ([]*models.RecurringSilenceState, error) {
+	result := make([]*models.RecurringSilenceState, 0, len(f.states))
+	for _, s := range f.states {
+		result = append(result, &s)
+	}
+	return result, nil
+}
+
+func (f *fakeRecurringSilenceStore) SaveRecurringSilenceState(_ context.Context, state models.RecurringSilenceState) (models.RecurringSilenceState, error) {
+	if f.conflict || state.Version != f.states[state.UID].Version {
+		return models.RecurringSilenceState{}, errVersionConflict
+	}
+	state.Version++
+	f.states[state.UID] = state
+	return state, nil
+}
+
+func (f *fakeRecurringSilenceStore) DeleteRecurringSilenceState(_ context.Context, state models.RecurringSilenceState) error {
+	if state.Version != f.states[state.UID].Version {
+		return errVersionConflict
+	}
+	delete(f.states, state.UID)
+	return nil
+}
+
+func TestRecurringSilenceMaterializer(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	daily := &models.RecurringSilence{
+		OrgID:    1,
+		UID:      "daily",
+		Matchers: amv2.Matchers{{Name: util.Pointer("cluster"), Value: util.Pointer("eu-1"), IsEqual: util.Pointer(true), IsRegex: util.Pointer(false)}},
+		Comment:  "Maintenance",
+		Schedule: models.RecurringSilenceSchedule{Cron: "0 2 * * *", Duration: 2 * time.Hour},
+		Version:  1,
+	}
+
+	newMaterializer := func() (*RecurringSilenceMaterializer, *fakeRecurringSilenceStore, *ngfakes.FakeSilenceStore) {
+		store := &fakeRecurringSilenceStore{silences: []*models.RecurringSilence{daily}, states: map[string]models.RecurringSilenceState{}}
+		silences := &ngfakes.FakeSilenceStore{Silences: map[string]*models.Silence{}}
+		m := NewRecurringSilenceMaterializer(RecurringSilenceMaterializerCfg{
+			Interval:  time.Minute,
+			Lookahead: 24 * time.Hour,
+			Clock:     clock.NewMock(),
+			Log:       log.NewNopLogger(),
+		}, store, silences)
+		return m, store, silences
+	}
+
+	windowsOf := func(silences *ngfakes.FakeSilenceStore) []models.TimeWindow {
+		result := make([]models.TimeWindow, 0, len(silences.Silences))
+		for _, s := range silences.Silences {
+			result = append(result, models.TimeWindow{Start: time.Time(*s.StartsAt).UTC(), End: time.Time(*s.EndsAt).UTC()})
+		}
+		return result
+	}
+
+	t.Run("should create the silences of the windows ahead of time", func(t *testing.T) {
+		m, store, silences := newMaterializer()
+
+		require.NoError(t, m.Sync(ctx, start))
+		require.Equal(t, []models.TimeWindow{
+			{Start: start.Add(2 * time.Hour), End: start.Add(4 * time.Hour)},
+		}, windowsOf(silences))
+		state := store.states["daily"]
+		require.Equal(t, start.Add(24*time.Hour), state.MaterializedUntil)
+		require.Len(t, state.Silences, 1)
+		for _, s := range silences.Silences {
+			require.Equal(t, "Maintenance", *s.Comment)
+			require.Equal(t, daily.Matchers, s.Matchers)
+		}
+
+		// The window of the next day is not materialized twice.
+		require.NoError(t, m.Sync(ctx, start.Add(time.Minute)))
+		require.Len(t, silences.Silences, 1)
+		require.Equal(t, int64(1), store.states["daily"].Version)
+
+		require.NoError(t, m.Sync(ctx, start.Add(2*time.Hour+time.Minute)))
+		require.Len(t, silences.Silences, 2)
+		require.NoError(t, m.Sync(ctx, start.Add(2*time.Hour+2*time.Minute)))
+		require.Len(t, silences.Silences, 2)
+		require.Len(t, store.states["daily"].Silences, 2)
+
+		// Silences that ended are not tracked anymore.
+		require.NoError(t, m.Sync(ctx, start.Add(5*time.Hour)))
+		require.Len(t, store.states["daily"].Silences, 1)
+	})
+
+	t.Run("should replace the silences when the recurring silence changes", func(t *testing.T) {
+		m, store, silences := newMaterializer()
+		require.NoError(t, m.Sync(ctx, start))
+
+		changed := *daily
+		changed.Schedule.Duration = time.Hour
+		changed.Version = 2
+		store.silences = []*models.RecurringSilence{&changed}
+
+		// The window in progress is replaced from now on.
+		now := start.Add(2*time.Hour + 30*time.Minute)
+		require.NoError(t, m.Sync(ctx, now))
+		require.ElementsMatch(t, []models.TimeWindow{
+			{Start: now, End: start.Add(3 * time.Hour)},
+			{Start: start.Add(26 * time.Hour), End: start.Add(27 * time.Hour)},
+		}, windowsOf(silences))
+		require.Equal(t, int64(2), store.states["daily"].RecurringSilenceVersion)
+		require.Len(t, store.states["daily"].Silences, 2)
+	})
+
+	t.Run("should expire the silences when the recurring silence is deleted", func(t *testing.T) {
+		m, store, silences := newMaterializer()
+		require.NoError(t, m.Sync(ctx, start))
+		require.Len(t, silences.Silences, 1)
+
+		store.silences = nil
+		require.NoError(t, m.Sync(ctx, start.Add(time.Minute)))
+		require.Empty(t, silences.Silences)
+		require.Empty(t, store.states)
+	})
+
+	t.Run("should expire the created silences if the state cannot be saved", func(t *testing.T) {
+		m, store, silences := newMaterializer()
+		store.conflict = true
+
+		require.ErrorIs(t, m.Sync(ctx, start), errVersionConflict)
+		require.Empty(t, silences.Silences)
+		require.Empty(t, store.states)
+	})
+}
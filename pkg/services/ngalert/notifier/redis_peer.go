@@ -372,7 +372,7 @@ func (p *redisPeer) filterUnhealthyMembers(members []string, values []any) []str
 
 func (p *redisPeer) Position() int {
 	for i, peer := range p.Members() {
-		if peer == p.withPrefix(p.name) {
+		if peer == p.Self() {
 			p.logger.Debug("Cluster position found", "name", p.name, "position", i)
 			return i
 		}
@@ -403,6 +403,11 @@ func (p *redisPeer) GetHealthScore() int {
 	return 0
 }
 
+// Self returns the name of this peer as it appears in Members.
+func (p *redisPeer) Self() string {
+	return p.withPrefix(p.name)
+}
+
 // Members returns a list of active cluster Members.
 func (p *redisPeer) Members() []string {
 	p.membersMtx.Lock()
@@ -0,0 +1,206 @@
+package notifier
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+// ErrRoutingTestInvalidConfig is returned when alerts cannot be routed with the tested configuration.
+var ErrRoutingTestInvalidConfig = errutil.BadRequest("alerting.notifications.routingTest.invalidConfig")
+
+// TestRouting routes an alert with the given labels through the notification policy tree of the organization
+// and returns the routes that the alert matches at the given time.
+// If cfg is not nil, it is used instead of the current configuration, as if it was saved.
+func (moa *MultiOrgAlertmanager) TestRouting(ctx context.Context, orgID int64, cfg *definitions.PostableUserConfig, lbls model.LabelSet, now time.Time) ([]definitions.TestRoutingRoute, error) {
+	tree, err := moa.GetRoutingTree(ctx, orgID, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return tree.Match(lbls, now)
+}
+
+// GetRoutingTree returns the notification policy tree of the organization, including the autogenerated routes.
+// If cfg is not nil, it is used instead of the current configuration, as if it was saved.
+func (moa *MultiOrgAlertmanager) GetRoutingTree(ctx context.Context, orgID int64, cfg *definitions.PostableUserConfig) (*RoutingTree, error) {
+	var amConfig definitions.Config
+	var policies []definitions.EscalationPolicy
+	if cfg == nil {
+		current, err := moa.GetAlertmanagerConfiguration(ctx, orgID, true)
+		if err != nil {
+			return nil, err
+		}
+		amConfig = current.AlertmanagerConfig.Config
+		policies = current.EscalationPolicies
+	} else {
+		// Same as in SaveAndApplyAlertmanagerConfiguration, the Grafana Alertmanager does not support inhibition rules.
+		if len(cfg.AlertmanagerConfig.InhibitRules) > 0 {
+			return nil, WithPublicError(ErrRoutingTestInvalidConfig.Errorf("inhibition rules are not supported"))
+		}
+		if err := AddAutogenConfig(ctx, moa.logger, moa.configStore, orgID, &cfg.AlertmanagerConfig, true); err != nil {
+			return nil, err
+		}
+		amConfig = cfg.AlertmanagerConfig.Config
+		policies = cfg.EscalationPolicies
+	}
+	return NewRoutingTree(amConfig, policies)
+}
+
+// RoutingTree is the notification policy tree of an Alertmanager configuration with the time intervals
+// and the escalation policies it refers to.
+type RoutingTree struct {
+	root      *dispatch.Route
+	intervals map[string][]timeinterval.TimeInterval
+	// policies are the escalation policies by the name of the receiver they escalate.
+	policies map[string]definitions.EscalationPolicy
+}
+
+// NewRoutingTree creates the routing tree of the configuration and its escalation policies.
+func NewRoutingTree(cfg definitions.Config, policies []definitions.EscalationPolicy) (*RoutingTree, error) {
+	if cfg.Route == nil {
+		return nil, WithPublicError(ErrRoutingTestInvalidConfig.Errorf("no route provided in config"))
+	}
+	intervals := make(map[string][]timeinterval.TimeInterval, len(cfg.MuteTimeIntervals)+len(cfg.TimeIntervals))
+	for _, ti := range cfg.MuteTimeIntervals {
+		intervals[ti.Name] = ti.TimeIntervals
+	}
+	for _, ti := range cfg.TimeIntervals {
+		intervals[ti.Name] = ti.TimeIntervals
+	}
+	escalations := make(map[string]definitions.EscalationPolicy, len(policies))
+	for _, p := range policies {
+		escalations[p.Receiver] = p
+	}
+	return &RoutingTree{
+		root:      dispatch.NewRoute(cfg.Route.AsAMRoute(), nil),
+		intervals: intervals,
+		policies:  escalations,
+	}, nil
+}
+
+// Match returns the routes that an alert with the given labels matches, and whether they are muted at the given time.
+func (t *RoutingTree) Match(lbls model.LabelSet, now time.Time) ([]definitions.TestRoutingRoute, error) {
+	paths := matchRoutes(t.root, lbls, []routeStep{{route: t.root, index: -1}})
+	result := make([]definitions.TestRoutingRoute, 0, len(paths))
+	for _, path := range paths {
+		r, err := newTestRoutingRoute(path, t.intervals, now)
+		if err != nil {
+			return nil, err
+		}
+		if p, ok := t.policies[r.Receiver]; ok {
+			r.EscalationPolicy = &p
+		}
+		result = append(result, r)
+	}
+	return result, nil
+}
+
+// routeStep is a route of the routing tree and its index in the routes of its parent.
+type routeStep struct {
+	route *dispatch.Route
+	index int
+}
+
+// matchRoutes returns the paths to the routes that match the labels. It follows the same rules as dispatch.Route.Match,
+// but keeps the path from the root of the tree to each matched route.
+func matchRoutes(route *dispatch.Route, lbls model.LabelSet, path []routeStep) [][]routeStep {
+	if !route.Matchers.Matches(lbls) {
+		return nil
+	}
+
+	var all [][]routeStep
+	for i, child := range route.Routes {
+		matches := matchRoutes(child, lbls, append(slices.Clip(path), routeStep{route: child, index: i}))
+		all = append(all, matches...)
+		if len(matches) > 0 && !child.Continue {
+			break
+		}
+	}
+
+	// If no child route matches, the current route is the match.
+	if len(all) == 0 {
+		all = append(all, path)
+	}
+	return all
+}
+
+func newTestRoutingRoute(path []routeStep, intervals map[string][]timeinterval.TimeInterval, now time.Time) (definitions.TestRoutingRoute, error) {
+	opts := path[len(path)-1].route.RouteOpts
+	result := definitions.TestRoutingRoute{
+		Path:                make([]definitions.TestRoutingRouteStep, 0, len(path)),
+		Receiver:            opts.Receiver,
+		GroupBy:             make([]string, 0, len(opts.GroupBy)),
+		GroupWait:           model.Duration(opts.GroupWait),
+		GroupInterval:       model.Duration(opts.GroupInterval),
+		RepeatInterval:      model.Duration(opts.RepeatInterval),
+		MuteTimeIntervals:   opts.MuteTimeIntervals,
+		ActiveTimeIntervals: opts.ActiveTimeIntervals,
+	}
+	for _, step := range path {
+		s := definitions.TestRoutingRouteStep{
+			Index:    step.index,
+			Receiver: step.route.RouteOpts.Receiver,
+			Continue: step.route.Continue,
+		}
+		for _, m := range step.route.Matchers {
+			s.Matchers = append(s.Matchers, m.String())
+		}
+		result.Path = append(result.Path, s)
+	}
+
+	if opts.GroupByAll {
+		result.GroupBy = append(result.GroupBy, "...")
+	} else {
+		for l := range opts.GroupBy {
+			result.GroupBy = append(result.GroupBy, string(l))
+		}
+		slices.Sort(result.GroupBy)
+	}
+
+	// The route is muted if the time is in any of its mute time intervals or outside all of its active time intervals,
+	// the same as in the notification pipeline of the Alertmanager.
+	for _, name := range opts.MuteTimeIntervals {
+		contains, err := intervalsContainTime(intervals, name, now)
+		if err != nil {
+			return definitions.TestRoutingRoute{}, err
+		}
+		if contains {
+			result.MutedBy = append(result.MutedBy, name)
+		}
+	}
+	if len(opts.ActiveTimeIntervals) > 0 {
+		active := false
+		for _, name := range opts.ActiveTimeIntervals {
+			contains, err := intervalsContainTime(intervals, name, now)
+			if err != nil {
+				return definitions.TestRoutingRoute{}, err
+			}
+			active = active || contains
+		}
+		if !active {
+			result.MutedBy = append(result.MutedBy, opts.ActiveTimeIntervals...)
+		}
+	}
+	result.Muted = len(result.MutedBy) > 0
+	return result, nil
+}
+
+func intervalsContainTime(intervals map[string][]timeinterval.TimeInterval, name string, now time.Time) (bool, error) {
+	tis, ok := intervals[name]
+	if !ok {
+		return false, WithPublicError(ErrRoutingTestInvalidConfig.Errorf("time interval %q does not exist", name))
+	}
+	for _, ti := range tis {
+		if ti.ContainsTime(now.UTC()) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -152,7 +152,7 @@ func (f *fakeConfigStore) UpdateAlertmanagerConfiguration(_ context.Context, cmd
 		return nil
 	}
 
-	return errors.New("config not found or hash not valid")
+	return store.ErrVersionLockedObjectNotFound
 }
 
 func (f *fakeConfigStore) MarkConfigurationAsApplied(_ context.Context, cmd *models.MarkConfigurationAsAppliedCmd) error {
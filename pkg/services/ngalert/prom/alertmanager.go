@@ -0,0 +1,712 @@
+package prom
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	commoncfg "github.com/prometheus/common/config"
+
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels_config"
+)
+
+var (
+	ErrInvalidAlertmanagerConfigIdentifier = errutil.ValidationFailed(
+		"alerting.invalidAlertmanagerConfigIdentifier",
+		errutil.WithPublicMessage("The identifier of the imported Alertmanager configuration must be 1 to 40 letters, digits, dashes or underscores."),
+	)
+	ErrMissingMergeMatchers = errutil.ValidationFailed(
+		"alerting.missingMergeMatchers",
+		errutil.WithPublicMessage("At least one merge matcher is required to import an Alertmanager configuration."),
+	)
+	ErrAlertmanagerImportConflict = errutil.Conflict(
+		"alerting.alertmanagerImportConflict",
+		errutil.WithPublicMessage("The imported Alertmanager configuration has objects with the same names as objects that were not created by a previous import with the same identifier."),
+	)
+
+	identifierRegexp = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,40}$`)
+)
+
+// Kinds of the features of a Prometheus Alertmanager configuration that cannot be imported.
+const (
+	UnsupportedKindIntegration = "integration"
+	UnsupportedKindInhibitRule = "inhibit_rule"
+	UnsupportedKindSetting     = "setting"
+)
+
+// AlertmanagerImportConfig defines how a Prometheus Alertmanager configuration is merged
+// into the Grafana Alertmanager configuration.
+type AlertmanagerImportConfig struct {
+	// Identifier identifies the imported configuration. The names of the imported receivers, time intervals
+	// and templates are prefixed with it, and the objects created by the previous import with the same identifier
+	// are replaced on every import.
+	Identifier string
+	// MergeMatchers are the matchers of the route under which the imported routing tree is merged.
+	// Only the alerts that match them are routed by the imported routing tree.
+	MergeMatchers definitions.ObjectMatchers
+}
+
+// UnsupportedFeature is a part of a Prometheus Alertmanager configuration that was not imported.
+type UnsupportedFeature struct {
+	Kind   string
+	Name   string
+	Reason string
+}
+
+// ResourceDiff contains the names of the resources that an import added, updated or removed.
+type ResourceDiff struct {
+	Added   []string
+	Updated []string
+	Removed []string
+}
+
+// AlertmanagerConfigDiff describes the changes an import made to the Grafana Alertmanager configuration.
+type AlertmanagerConfigDiff struct {
+	Routes        ResourceDiff
+	Receivers     ResourceDiff
+	TimeIntervals ResourceDiff
+	Templates     ResourceDiff
+}
+
+// AlertmanagerImportResult is the result of merging a Prometheus Alertmanager configuration.
+type AlertmanagerImportResult struct {
+	Diff        AlertmanagerConfigDiff
+	Unsupported []UnsupportedFeature
+}
+
+// MergeAlertmanagerConfig converts the Prometheus Alertmanager configuration and merges it into dst.
+//
+// The imported routing tree is added as the first child of the root route, with the merge matchers,
+// so that it takes precedence over the other policies for the alerts it matches. Receivers, time intervals
+// and templates are added with names prefixed by the identifier. The objects created by an import are recorded
+// in the imported configurations of dst, and the ones created by the previous import with the same identifier
+// are replaced. The import fails if other objects have the names of the imported ones.
+//
+// Integrations and settings that have no Grafana equivalent are skipped and reported in the result.
+// Secrets of the imported integrations are set in plain text to the secure settings and must be
+// encrypted by the caller before the configuration is persisted.
+func MergeAlertmanagerConfig(dst *definitions.PostableUserConfig, src *config.Config, templates map[string]string, cfg AlertmanagerImportConfig) (AlertmanagerImportResult, error) {
+	if !identifierRegexp.MatchString(cfg.Identifier) {
+		return AlertmanagerImportResult{}, ErrInvalidAlertmanagerConfigIdentifier.Errorf("invalid identifier %q", cfg.Identifier)
+	}
+	if len(cfg.MergeMatchers) == 0 {
+		return AlertmanagerImportResult{}, ErrMissingMergeMatchers.Errorf("")
+	}
+	if dst.AlertmanagerConfig.Route == nil {
+		return AlertmanagerImportResult{}, fmt.Errorf("the Grafana Alertmanager configuration has no root route")
+	}
+	if src.Route == nil {
+		return AlertmanagerImportResult{}, fmt.Errorf("the imported Alertmanager configuration has no root route")
+	}
+
+	c := &amConverter{prefix: cfg.Identifier + "_"}
+	var diff AlertmanagerConfigDiff
+	previousIdx := slices.IndexFunc(dst.ImportedConfigs, func(i definitions.ImportedAlertmanagerConfig) bool {
+		return i.Identifier == cfg.Identifier
+	})
+	var previous definitions.ImportedAlertmanagerConfig
+	if previousIdx >= 0 {
+		previous = dst.ImportedConfigs[previousIdx]
+	}
+	previousReceivers := toSet(previous.Receivers)
+	previousIntervals := toSet(previous.TimeIntervals)
+	previousTemplates := toSet(previous.Templates)
+
+	// Routes
+	oldRoutes := map[string]string{}
+	root := dst.AlertmanagerConfig.Route
+	children := make([]*definitions.Route, 0, len(root.Routes)+1)
+	for _, r := range root.Routes {
+		// The imported routing tree is the route with the merge matchers of the previous import and an imported receiver.
+		if _, ok := previousReceivers[r.Receiver]; ok && len(oldRoutes) == 0 && matchersString(r.ObjectMatchers) == matchersString(previous.MergeMatchers) {
+			oldRoutes[matchersString(r.ObjectMatchers)] = fingerprint(r)
+			continue
+		}
+		children = append(children, r)
+	}
+	subtree := c.convertRoute(src.Route)
+	subtree.ObjectMatchers = cfg.MergeMatchers
+	// The imported root route has no matchers and must not let the alerts through to the other policies.
+	subtree.Continue = false
+	root.Routes = append([]*definitions.Route{subtree}, children...)
+	diff.Routes = diffResources(oldRoutes, map[string]string{matchersString(subtree.ObjectMatchers): fingerprint(subtree)})
+
+	// Receivers
+	oldReceivers := map[string]*definitions.PostableApiReceiver{}
+	receivers := make([]*definitions.PostableApiReceiver, 0, len(dst.AlertmanagerConfig.Receivers)+len(src.Receivers))
+	kept := map[string]struct{}{}
+	for _, r := range dst.AlertmanagerConfig.Receivers {
+		if _, ok := previousReceivers[r.Name]; ok {
+			oldReceivers[r.Name] = r
+			continue
+		}
+		kept[r.Name] = struct{}{}
+		receivers = append(receivers, r)
+	}
+	newReceivers := make(map[string]string, len(src.Receivers))
+	for _, r := range src.Receivers {
+		recv, err := c.convertReceiver(r)
+		if err != nil {
+			return AlertmanagerImportResult{}, err
+		}
+		if _, ok := kept[recv.Name]; ok {
+			return AlertmanagerImportResult{}, ErrAlertmanagerImportConflict.Errorf("receiver %q already exists", recv.Name)
+		}
+		// Keep the UIDs of the integrations created by the previous import, so that they are updated instead of recreated.
+		if old, ok := oldReceivers[recv.Name]; ok {
+			for i, integration := range recv.GrafanaManagedReceivers {
+				if i < len(old.GrafanaManagedReceivers) && old.GrafanaManagedReceivers[i].Type == integration.Type {
+					integration.UID = old.GrafanaManagedReceivers[i].UID
+				}
+			}
+		}
+		receivers = append(receivers, recv)
+		newReceivers[recv.Name] = receiverFingerprint(recv)
+	}
+	dst.AlertmanagerConfig.Receivers = receivers
+	oldReceiverFingerprints := make(map[string]string, len(oldReceivers))
+	for name, r := range oldReceivers {
+		oldReceiverFingerprints[name] = receiverFingerprint(r)
+	}
+	diff.Receivers = diffResources(oldReceiverFingerprints, newReceivers)
+
+	// Time intervals
+	oldIntervals := map[string]string{}
+	newIntervals := map[string]string{}
+	kept = map[string]struct{}{}
+	muteTimeIntervals := make([]config.MuteTimeInterval, 0, len(dst.AlertmanagerConfig.MuteTimeIntervals)+len(src.MuteTimeIntervals))
+	for _, mt := range dst.AlertmanagerConfig.MuteTimeIntervals {
+		if _, ok := previousIntervals[mt.Name]; ok {
+			oldIntervals[mt.Name] = fingerprint(mt)
+			continue
+		}
+		kept[mt.Name] = struct{}{}
+		muteTimeIntervals = append(muteTimeIntervals, mt)
+	}
+	timeIntervals := make([]config.TimeInterval, 0, len(dst.AlertmanagerConfig.TimeIntervals)+len(src.TimeIntervals))
+	for _, ti := range dst.AlertmanagerConfig.TimeIntervals {
+		if _, ok := previousIntervals[ti.Name]; ok {
+			oldIntervals[ti.Name] = fingerprint(ti)
+			continue
+		}
+		kept[ti.Name] = struct{}{}
+		timeIntervals = append(timeIntervals, ti)
+	}
+	for _, mt := range src.MuteTimeIntervals {
+		mt.Name = c.name(mt.Name)
+		if _, ok := kept[mt.Name]; ok {
+			return AlertmanagerImportResult{}, ErrAlertmanagerImportConflict.Errorf("time interval %q already exists", mt.Name)
+		}
+		muteTimeIntervals = append(muteTimeIntervals, mt)
+		newIntervals[mt.Name] = fingerprint(mt)
+	}
+	for _, ti := range src.TimeIntervals {
+		ti.Name = c.name(ti.Name)
+		if _, ok := kept[ti.Name]; ok {
+			return AlertmanagerImportResult{}, ErrAlertmanagerImportConflict.Errorf("time interval %q already exists", ti.Name)
+		}
+		timeIntervals = append(timeIntervals, ti)
+		newIntervals[ti.Name] = fingerprint(ti)
+	}
+	dst.AlertmanagerConfig.MuteTimeIntervals = muteTimeIntervals
+	dst.AlertmanagerConfig.TimeIntervals = timeIntervals
+	diff.TimeIntervals = diffResources(oldIntervals, newIntervals)
+
+	// Templates
+	oldTemplates := map[string]string{}
+	for name, content := range dst.TemplateFiles {
+		if _, ok := previousTemplates[name]; ok {
+			oldTemplates[name] = content
+		}
+	}
+	newTemplates := make(map[string]string, len(templates))
+	for name, content := range templates {
+		if _, ok := dst.TemplateFiles[c.name(name)]; ok {
+			if _, ok := previousTemplates[c.name(name)]; !ok {
+				return AlertmanagerImportResult{}, ErrAlertmanagerImportConflict.Errorf("template %q already exists", c.name(name))
+			}
+		}
+		newTemplates[c.name(name)] = content
+	}
+	for name := range oldTemplates {
+		delete(dst.TemplateFiles, name)
+	}
+	for name, content := range newTemplates {
+		if dst.TemplateFiles == nil {
+			dst.TemplateFiles = make(map[string]string, len(newTemplates))
+		}
+		dst.TemplateFiles[name] = content
+	}
+	diff.Templates = diffResources(oldTemplates, newTemplates)
+
+	for i := range src.InhibitRules {
+		c.unsupported(UnsupportedKindInhibitRule, fmt.Sprintf("inhibit_rules[%d]", i), "Inhibition rules are not supported by the Grafana Alertmanager.")
+	}
+
+	imported := definitions.ImportedAlertmanagerConfig{
+		Identifier:    cfg.Identifier,
+		MergeMatchers: cfg.MergeMatchers,
+		Receivers:     slices.Sorted(maps.Keys(newReceivers)),
+		TimeIntervals: slices.Sorted(maps.Keys(newIntervals)),
+		Templates:     slices.Sorted(maps.Keys(newTemplates)),
+	}
+	if previousIdx >= 0 {
+		dst.ImportedConfigs[previousIdx] = imported
+	} else {
+		dst.ImportedConfigs = append(dst.ImportedConfigs, imported)
+	}
+
+	return AlertmanagerImportResult{Diff: diff, Unsupported: c.unsupportedFeatures}, nil
+}
+
+func toSet(names []string) map[string]struct{} {
+	result := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		result[name] = struct{}{}
+	}
+	return result
+}
+
+type amConverter struct {
+	prefix              string
+	unsupportedFeatures []UnsupportedFeature
+}
+
+func (c *amConverter) name(name string) string {
+	return c.prefix + name
+}
+
+func (c *amConverter) unsupported(kind, name, reason string) {
+	c.unsupportedFeatures = append(c.unsupportedFeatures, UnsupportedFeature{Kind: kind, Name: name, Reason: reason})
+}
+
+func (c *amConverter) names(names []string) []string {
+	if names == nil {
+		return nil
+	}
+	result := make([]string, 0, len(names))
+	for _, n := range names {
+		result = append(result, c.name(n))
+	}
+	return result
+}
+
+func (c *amConverter) convertRoute(r *config.Route) *definitions.Route {
+	route := &definitions.Route{
+		GroupByStr:          r.GroupByStr,
+		GroupBy:             r.GroupBy,
+		GroupByAll:          r.GroupByAll,
+		Match:               r.Match,
+		MatchRE:             r.MatchRE,
+		Matchers:            r.Matchers,
+		MuteTimeIntervals:   c.names(r.MuteTimeIntervals),
+		ActiveTimeIntervals: c.names(r.ActiveTimeIntervals),
+		Continue:            r.Continue,
+		GroupWait:           r.GroupWait,
+		GroupInterval:       r.GroupInterval,
+		RepeatInterval:      r.RepeatInterval,
+	}
+	if r.Receiver != "" {
+		route.Receiver = c.name(r.Receiver)
+	}
+	for _, child := range r.Routes {
+		route.Routes = append(route.Routes, c.convertRoute(child))
+	}
+	return route
+}
+
+// supportedIntegrations maps the configuration keys of the Prometheus integrations that can be converted
+// to the types of the Grafana integrations.
+var supportedIntegrations = map[string]string{
+	"email_configs":     "email",
+	"slack_configs":     "slack",
+	"webhook_configs":   "webhook",
+	"pagerduty_configs": "pagerduty",
+	"opsgenie_configs":  "opsgenie",
+	"telegram_configs":  "telegram",
+	"discord_configs":   "discord",
+	"msteams_configs":   "teams",
+	"pushover_configs":  "pushover",
+	"victorops_configs": "victorops",
+	"webex_configs":     "webex",
+}
+
+func (c *amConverter) convertReceiver(r config.Receiver) (*definitions.PostableApiReceiver, error) {
+	name := c.name(r.Name)
+	result := &definitions.PostableApiReceiver{}
+	result.Name = name
+
+	// Report the integrations that cannot be converted, whichever integrations the Prometheus Alertmanager supports.
+	v := reflect.ValueOf(r)
+	for i := 0; i < v.NumField(); i++ {
+		key, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("yaml"), ",")
+		if !strings.HasSuffix(key, "_configs") || v.Field(i).Kind() != reflect.Slice || v.Field(i).Len() == 0 {
+			continue
+		}
+		if _, ok := supportedIntegrations[key]; !ok {
+			c.unsupported(UnsupportedKindIntegration, name, fmt.Sprintf("%s is not supported by the Grafana Alertmanager.", key))
+		}
+	}
+
+	add := func(integrationType string, sendResolved bool, settings map[string]any) error {
+		integration, err := newIntegration(name, integrationType, sendResolved, settings)
+		if err != nil {
+			return err
+		}
+		result.GrafanaManagedReceivers = append(result.GrafanaManagedReceivers, integration)
+		return nil
+	}
+
+	for _, e := range r.EmailConfigs {
+		settings := map[string]any{
+			"addresses":   e.To,
+			"singleEmail": true,
+		}
+		if subject, ok := e.Headers["Subject"]; ok {
+			settings["subject"] = subject
+		}
+		c.unsupported(UnsupportedKindSetting, name, "The SMTP settings and the body of email_configs are not imported. Emails are sent with the SMTP server and the email template of Grafana.")
+		if err := add("email", e.SendResolved(), settings); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, s := range r.SlackConfigs {
+		if s.APIURL == nil {
+			c.unsupported(UnsupportedKindIntegration, name, "slack_configs without api_url are not supported.")
+			continue
+		}
+		c.checkHTTPConfig(name, "slack_configs", s.HTTPConfig)
+		settings := map[string]any{
+			"url":        secretURL(s.APIURL),
+			"recipient":  s.Channel,
+			"username":   s.Username,
+			"icon_emoji": s.IconEmoji,
+			"icon_url":   s.IconURL,
+			"title":      s.Title,
+			"text":       s.Text,
+		}
+		if s.Color != "" {
+			settings["color"] = s.Color
+		}
+		if err := add("slack", s.SendResolved(), settings); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, w := range r.WebhookConfigs {
+		if w.URL == nil {
+			c.unsupported(UnsupportedKindIntegration, name, "webhook_configs without url are not supported.")
+			continue
+		}
+		settings := map[string]any{
+			"url":        secretURL(w.URL),
+			"httpMethod": "POST",
+			"maxAlerts":  w.MaxAlerts,
+		}
+		var httpConfig *commoncfg.HTTPClientConfig
+		if w.HTTPConfig != nil {
+			cpy := *w.HTTPConfig
+			httpConfig = &cpy
+			if auth := httpConfig.BasicAuth; auth != nil {
+				settings["username"] = auth.Username
+				settings["password"] = string(auth.Password)
+				httpConfig.BasicAuth = nil
+			}
+			if auth := httpConfig.Authorization; auth != nil {
+				settings["authorization_scheme"] = auth.Type
+				settings["authorization_credentials"] = string(auth.Credentials)
+				httpConfig.Authorization = nil
+			}
+		}
+		c.checkHTTPConfig(name, "webhook_configs", httpConfig)
+		if err := add("webhook", w.SendResolved(), settings); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, p := range r.PagerdutyConfigs {
+		if p.RoutingKey == "" {
+			c.unsupported(UnsupportedKindIntegration, name, "pagerduty_configs without routing_key are not supported. Grafana uses the PagerDuty Events API v2.")
+			continue
+		}
+		c.checkHTTPConfig(name, "pagerduty_configs", p.HTTPConfig)
+		settings := map[string]any{
+			"integrationKey": string(p.RoutingKey),
+			"severity":       p.Severity,
+			"class":          p.Class,
+			"component":      p.Component,
+			"group":          p.Group,
+			"summary":        p.Description,
+			"source":         p.Source,
+			"client":         p.Client,
+			"client_url":     p.ClientURL,
+			"url":            plainURL(p.URL),
+		}
+		if len(p.Details) > 0 {
+			settings["details"] = p.Details
+		}
+		if err := add("pagerduty", p.SendResolved(), settings); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, o := range r.OpsGenieConfigs {
+		if o.APIKey == "" {
+			c.unsupported(UnsupportedKindIntegration, name, "opsgenie_configs without api_key are not supported.")
+			continue
+		}
+		c.checkHTTPConfig(name, "opsgenie_configs", o.HTTPConfig)
+		settings := map[string]any{
+			"apiKey":      string(o.APIKey),
+			"message":     o.Message,
+			"description": o.Description,
+			"autoClose":   true,
+		}
+		if o.APIURL != nil && o.APIURL.URL != nil {
+			settings["apiUrl"] = o.APIURL.JoinPath("v2/alerts").String()
+		}
+		responders := make([]map[string]string, 0, len(o.Responders))
+		for _, r := range o.Responders {
+			responders = append(responders, map[string]string{"type": r.Type, "id": r.ID, "name": r.Name, "username": r.Username})
+		}
+		if len(responders) > 0 {
+			settings["responders"] = responders
+		}
+		if err := add("opsgenie", o.SendResolved(), settings); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, t := range r.TelegramConfigs {
+		if t.BotToken == "" {
+			c.unsupported(UnsupportedKindIntegration, name, "telegram_configs without bot_token are not supported.")
+			continue
+		}
+		c.checkHTTPConfig(name, "telegram_configs", t.HTTPConfig)
+		settings := map[string]any{
+			"bottoken":             string(t.BotToken),
+			"chatid":               strconv.FormatInt(t.ChatID, 10),
+			"message":              t.Message,
+			"parse_mode":           t.ParseMode,
+			"disable_notification": t.DisableNotifications,
+		}
+		if err := add("telegram", t.SendResolved(), settings); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, d := range r.DiscordConfigs {
+		if d.WebhookURL == nil {
+			c.unsupported(UnsupportedKindIntegration, name, "discord_configs without webhook_url are not supported.")
+			continue
+		}
+		c.checkHTTPConfig(name, "discord_configs", d.HTTPConfig)
+		settings := map[string]any{
+			"url":     secretURL(d.WebhookURL),
+			"title":   d.Title,
+			"message": d.Message,
+		}
+		if err := add("discord", d.SendResolved(), settings); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, m := range r.MSTeamsConfigs {
+		if m.WebhookURL == nil {
+			c.unsupported(UnsupportedKindIntegration, name, "msteams_configs without webhook_url are not supported.")
+			continue
+		}
+		c.checkHTTPConfig(name, "msteams_configs", m.HTTPConfig)
+		settings := map[string]any{
+			"url":     secretURL(m.WebhookURL),
+			"title":   m.Title,
+			"message": m.Text,
+		}
+		if err := add("teams", m.SendResolved(), settings); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, p := range r.PushoverConfigs {
+		if p.UserKey == "" || p.Token == "" {
+			c.unsupported(UnsupportedKindIntegration, name, "pushover_configs without user_key and token are not supported.")
+			continue
+		}
+		c.checkHTTPConfig(name, "pushover_configs", p.HTTPConfig)
+		settings := map[string]any{
+			"userKey":  string(p.UserKey),
+			"apiToken": string(p.Token),
+			"title":    p.Title,
+			"message":  p.Message,
+			"device":   p.Device,
+			"sound":    p.Sound,
+			"retry":    int64(time.Duration(p.Retry).Seconds()),
+			"expire":   int64(time.Duration(p.Expire).Seconds()),
+		}
+		if err := add("pushover", p.SendResolved(), settings); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, o := range r.VictorOpsConfigs {
+		if o.APIKey == "" || o.APIURL == nil || o.APIURL.URL == nil {
+			c.unsupported(UnsupportedKindIntegration, name, "victorops_configs without api_key and api_url are not supported.")
+			continue
+		}
+		c.checkHTTPConfig(name, "victorops_configs", o.HTTPConfig)
+		settings := map[string]any{
+			"url":         o.APIURL.JoinPath(string(o.APIKey), o.RoutingKey).String(),
+			"messageType": o.MessageType,
+			"title":       o.EntityDisplayName,
+			"description": o.StateMessage,
+		}
+		if err := add("victorops", o.SendResolved(), settings); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, w := range r.WebexConfigs {
+		if w.HTTPConfig == nil || w.HTTPConfig.Authorization == nil {
+			c.unsupported(UnsupportedKindIntegration, name, "webex_configs without http_config.authorization are not supported.")
+			continue
+		}
+		settings := map[string]any{
+			"api_url":   plainURL(w.APIURL),
+			"room_id":   w.RoomID,
+			"message":   w.Message,
+			"bot_token": string(w.HTTPConfig.Authorization.Credentials),
+		}
+		cpy := *w.HTTPConfig
+		cpy.Authorization = nil
+		c.checkHTTPConfig(name, "webex_configs", &cpy)
+		if err := add("webex", w.SendResolved(), settings); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// checkHTTPConfig reports the HTTP client settings of an integration, which cannot be converted.
+func (c *amConverter) checkHTTPConfig(name, key string, httpConfig *commoncfg.HTTPClientConfig) {
+	if httpConfig == nil {
+		return
+	}
+	// Every integration inherits the global HTTP client settings, which default to DefaultHTTPClientConfig.
+	if reflect.DeepEqual(*httpConfig, commoncfg.DefaultHTTPClientConfig) {
+		return
+	}
+	c.unsupported(UnsupportedKindSetting, name, fmt.Sprintf("The http_config of %s is not imported.", key))
+}
+
+func newIntegration(receiverName, integrationType string, sendResolved bool, settings map[string]any) (*definitions.PostableGrafanaReceiver, error) {
+	secretKeys, err := channels_config.GetSecretKeysForContactPointType(integrationType)
+	if err != nil {
+		return nil, err
+	}
+	secureSettings := map[string]string{}
+	for _, key := range secretKeys {
+		v, ok := settings[key]
+		if !ok {
+			continue
+		}
+		delete(settings, key)
+		if s, ok := v.(string); ok && s != "" {
+			secureSettings[key] = s
+		}
+	}
+	// Do not set empty settings, so that the defaults of Grafana are used.
+	for key, v := range settings {
+		if s, ok := v.(string); ok && s == "" {
+			delete(settings, key)
+		}
+	}
+	raw, err := json.Marshal(settings)
+	if err != nil {
+		return nil, err
+	}
+	return &definitions.PostableGrafanaReceiver{
+		Name:                  receiverName,
+		Type:                  integrationType,
+		DisableResolveMessage: !sendResolved,
+		Settings:              raw,
+		SecureSettings:        secureSettings,
+	}, nil
+}
+
+func secretURL(u *config.SecretURL) string {
+	if u == nil || u.URL == nil {
+		return ""
+	}
+	return u.URL.String()
+}
+
+func plainURL(u *config.URL) string {
+	if u == nil || u.URL == nil {
+		return ""
+	}
+	return u.URL.String()
+}
+
+func matchersString(matchers definitions.ObjectMatchers) string {
+	return labels.Matchers(matchers).String()
+}
+
+func fingerprint(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// receiverFingerprint returns a fingerprint of the receiver that does not depend on the values of secrets,
+// which are encrypted in the stored configuration, and on the UIDs of the integrations.
+func receiverFingerprint(r *definitions.PostableApiReceiver) string {
+	type integration struct {
+		Type                  string
+		DisableResolveMessage bool
+		Settings              json.RawMessage
+		SecureKeys            []string
+	}
+	integrations := make([]integration, 0, len(r.GrafanaManagedReceivers))
+	for _, i := range r.GrafanaManagedReceivers {
+		integrations = append(integrations, integration{
+			Type:                  i.Type,
+			DisableResolveMessage: i.DisableResolveMessage,
+			Settings:              json.RawMessage(i.Settings),
+			SecureKeys:            slices.Sorted(maps.Keys(i.SecureSettings)),
+		})
+	}
+	return fingerprint(integrations)
+}
+
+func diffResources(before, after map[string]string) ResourceDiff {
+	var diff ResourceDiff
+	for _, name := range slices.Sorted(maps.Keys(after)) {
+		old, ok := before[name]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, name)
+		case old != after[name]:
+			diff.Updated = append(diff.Updated, name)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(before)) {
+		if _, ok := after[name]; !ok {
+			diff.Removed = append(diff.Removed, name)
+		}
+	}
+	return diff
+}
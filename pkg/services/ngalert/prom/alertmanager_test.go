@@ -0,0 +1,224 @@
+package prom
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+const testPrometheusAlertmanagerConfig = `
+route:
+  receiver: default
+  routes:
+    - receiver: pager
+      matchers: [severity="critical"]
+      active_time_intervals: [business-hours]
+receivers:
+  - name: default
+    slack_configs:
+      - api_url: https://hooks.slack.com/services/secret
+        channel: '#alerts'
+        send_resolved: true
+  - name: pager
+    pagerduty_configs:
+      - routing_key: routing-key
+        severity: critical
+    sns_configs:
+      - topic_arn: arn:aws:sns:us-east-1:123456789012:topic
+        sigv4:
+          region: us-east-1
+time_intervals:
+  - name: business-hours
+    time_intervals:
+      - weekdays: ['monday:friday']
+inhibit_rules:
+  - source_matchers: [severity="critical"]
+    target_matchers: [severity="warning"]
+`
+
+func newGrafanaConfig() *definitions.PostableUserConfig {
+	cfg := &definitions.PostableUserConfig{
+		TemplateFiles: map[string]string{"grafana": "{{ define \"grafana\" }}{{ end }}"},
+	}
+	cfg.AlertmanagerConfig.Route = &definitions.Route{
+		Receiver: "grafana-default-email",
+		Routes:   []*definitions.Route{{Receiver: "grafana-default-email"}},
+	}
+	recv := &definitions.PostableApiReceiver{
+		PostableGrafanaReceivers: definitions.PostableGrafanaReceivers{
+			GrafanaManagedReceivers: []*definitions.PostableGrafanaReceiver{{UID: "email-uid", Name: "grafana-default-email", Type: "email"}},
+		},
+	}
+	recv.Name = "grafana-default-email"
+	cfg.AlertmanagerConfig.Receivers = []*definitions.PostableApiReceiver{recv}
+	return cfg
+}
+
+func mergeMatchers(t *testing.T) definitions.ObjectMatchers {
+	t.Helper()
+	m, err := labels.NewMatcher(labels.MatchEqual, "source", "prometheus")
+	require.NoError(t, err)
+	return definitions.ObjectMatchers{m}
+}
+
+func TestMergeAlertmanagerConfig(t *testing.T) {
+	src, err := config.Load(testPrometheusAlertmanagerConfig)
+	require.NoError(t, err)
+	importCfg := AlertmanagerImportConfig{Identifier: "prom", MergeMatchers: mergeMatchers(t)}
+	templates := map[string]string{"custom.tmpl": "{{ define \"custom\" }}{{ end }}"}
+
+	t.Run("should merge the imported configuration", func(t *testing.T) {
+		dst := newGrafanaConfig()
+
+		result, err := MergeAlertmanagerConfig(dst, src, templates, importCfg)
+		require.NoError(t, err)
+
+		route := dst.AlertmanagerConfig.Route
+		require.Equal(t, "grafana-default-email", route.Receiver)
+		require.Len(t, route.Routes, 2)
+		subtree := route.Routes[0]
+		require.Equal(t, "prom_default", subtree.Receiver)
+		require.Equal(t, importCfg.MergeMatchers, subtree.ObjectMatchers)
+		require.False(t, subtree.Continue)
+		require.Len(t, subtree.Routes, 1)
+		require.Equal(t, "prom_pager", subtree.Routes[0].Receiver)
+		require.Equal(t, []string{"prom_business-hours"}, subtree.Routes[0].ActiveTimeIntervals)
+		require.Equal(t, "grafana-default-email", route.Routes[1].Receiver)
+
+		require.Len(t, dst.AlertmanagerConfig.Receivers, 3)
+		slack := dst.AlertmanagerConfig.Receivers[1]
+		require.Equal(t, "prom_default", slack.Name)
+		require.Len(t, slack.GrafanaManagedReceivers, 1)
+		require.Equal(t, "slack", slack.GrafanaManagedReceivers[0].Type)
+		require.False(t, slack.GrafanaManagedReceivers[0].DisableResolveMessage)
+		require.Equal(t, "https://hooks.slack.com/services/secret", slack.GrafanaManagedReceivers[0].SecureSettings["url"])
+		var settings map[string]any
+		require.NoError(t, json.Unmarshal(slack.GrafanaManagedReceivers[0].Settings, &settings))
+		require.Equal(t, "#alerts", settings["recipient"])
+		require.NotContains(t, settings, "url")
+
+		pager := dst.AlertmanagerConfig.Receivers[2]
+		require.Len(t, pager.GrafanaManagedReceivers, 1)
+		require.Equal(t, "pagerduty", pager.GrafanaManagedReceivers[0].Type)
+		require.False(t, pager.GrafanaManagedReceivers[0].DisableResolveMessage)
+		require.Equal(t, "routing-key", pager.GrafanaManagedReceivers[0].SecureSettings["integrationKey"])
+
+		require.Len(t, dst.AlertmanagerConfig.TimeIntervals, 1)
+		require.Equal(t, "prom_business-hours", dst.AlertmanagerConfig.TimeIntervals[0].Name)
+		require.Contains(t, dst.TemplateFiles, "prom_custom.tmpl")
+		require.Contains(t, dst.TemplateFiles, "grafana")
+
+		require.Equal(t, AlertmanagerConfigDiff{
+			Routes:        ResourceDiff{Added: []string{`{source="prometheus"}`}},
+			Receivers:     ResourceDiff{Added: []string{"prom_default", "prom_pager"}},
+			TimeIntervals: ResourceDiff{Added: []string{"prom_business-hours"}},
+			Templates:     ResourceDiff{Added: []string{"prom_custom.tmpl"}},
+		}, result.Diff)
+		require.Equal(t, []definitions.ImportedAlertmanagerConfig{{
+			Identifier:    "prom",
+			MergeMatchers: importCfg.MergeMatchers,
+			Receivers:     []string{"prom_default", "prom_pager"},
+			TimeIntervals: []string{"prom_business-hours"},
+			Templates:     []string{"prom_custom.tmpl"},
+		}}, dst.ImportedConfigs)
+
+		require.ElementsMatch(t, []UnsupportedFeature{
+			{Kind: UnsupportedKindIntegration, Name: "prom_pager", Reason: "sns_configs is not supported by the Grafana Alertmanager."},
+			{Kind: UnsupportedKindInhibitRule, Name: "inhibit_rules[0]", Reason: "Inhibition rules are not supported by the Grafana Alertmanager."},
+		}, result.Unsupported)
+	})
+
+	t.Run("should replace the objects of the previous import", func(t *testing.T) {
+		dst := newGrafanaConfig()
+		_, err := MergeAlertmanagerConfig(dst, src, templates, importCfg)
+		require.NoError(t, err)
+		dst.AlertmanagerConfig.Receivers[1].GrafanaManagedReceivers[0].UID = "slack-uid"
+
+		updated, err := config.Load(`
+route:
+  receiver: default
+receivers:
+  - name: default
+    slack_configs:
+      - api_url: https://hooks.slack.com/services/secret
+        channel: '#other'
+`)
+		require.NoError(t, err)
+
+		result, err := MergeAlertmanagerConfig(dst, updated, nil, importCfg)
+		require.NoError(t, err)
+
+		require.Len(t, dst.AlertmanagerConfig.Route.Routes, 2)
+		require.Empty(t, dst.AlertmanagerConfig.Route.Routes[0].Routes)
+		require.Len(t, dst.AlertmanagerConfig.Receivers, 2)
+		require.Equal(t, "grafana-default-email", dst.AlertmanagerConfig.Receivers[0].Name)
+		require.Equal(t, "slack-uid", dst.AlertmanagerConfig.Receivers[1].GrafanaManagedReceivers[0].UID)
+		require.Empty(t, dst.AlertmanagerConfig.TimeIntervals)
+		require.NotContains(t, dst.TemplateFiles, "prom_custom.tmpl")
+
+		require.Equal(t, AlertmanagerConfigDiff{
+			Routes:        ResourceDiff{Updated: []string{`{source="prometheus"}`}},
+			Receivers:     ResourceDiff{Updated: []string{"prom_default"}, Removed: []string{"prom_pager"}},
+			TimeIntervals: ResourceDiff{Removed: []string{"prom_business-hours"}},
+			Templates:     ResourceDiff{Removed: []string{"prom_custom.tmpl"}},
+		}, result.Diff)
+	})
+
+	t.Run("should keep the objects that were not created by the previous import", func(t *testing.T) {
+		dst := newGrafanaConfig()
+		manual := &definitions.PostableApiReceiver{}
+		manual.Name = "prom_manual"
+		dst.AlertmanagerConfig.Receivers = append(dst.AlertmanagerConfig.Receivers, manual)
+		dst.AlertmanagerConfig.Route.Routes = append(dst.AlertmanagerConfig.Route.Routes, &definitions.Route{Receiver: "prom_manual"})
+		dst.AlertmanagerConfig.TimeIntervals = []config.TimeInterval{{Name: "prom_manual"}}
+		dst.TemplateFiles["prom_manual.tmpl"] = "manual"
+
+		_, err := MergeAlertmanagerConfig(dst, src, templates, importCfg)
+		require.NoError(t, err)
+		_, err = MergeAlertmanagerConfig(dst, src, templates, importCfg)
+		require.NoError(t, err)
+
+		require.Len(t, dst.AlertmanagerConfig.Route.Routes, 3)
+		require.Equal(t, "prom_manual", dst.AlertmanagerConfig.Route.Routes[2].Receiver)
+		require.Len(t, dst.AlertmanagerConfig.Receivers, 4)
+		require.Equal(t, "prom_manual", dst.AlertmanagerConfig.Receivers[1].Name)
+		require.Len(t, dst.AlertmanagerConfig.TimeIntervals, 2)
+		require.Equal(t, "prom_manual", dst.AlertmanagerConfig.TimeIntervals[0].Name)
+		require.Equal(t, "manual", dst.TemplateFiles["prom_manual.tmpl"])
+		require.Len(t, dst.ImportedConfigs, 1)
+	})
+
+	t.Run("should fail if an object that was not imported has the name of an imported object", func(t *testing.T) {
+		dst := newGrafanaConfig()
+		manual := &definitions.PostableApiReceiver{}
+		manual.Name = "prom_default"
+		dst.AlertmanagerConfig.Receivers = append(dst.AlertmanagerConfig.Receivers, manual)
+		_, err := MergeAlertmanagerConfig(dst, src, templates, importCfg)
+		require.ErrorIs(t, err, ErrAlertmanagerImportConflict)
+
+		dst = newGrafanaConfig()
+		dst.AlertmanagerConfig.TimeIntervals = []config.TimeInterval{{Name: "prom_business-hours"}}
+		_, err = MergeAlertmanagerConfig(dst, src, templates, importCfg)
+		require.ErrorIs(t, err, ErrAlertmanagerImportConflict)
+
+		dst = newGrafanaConfig()
+		dst.TemplateFiles["prom_custom.tmpl"] = "manual"
+		_, err = MergeAlertmanagerConfig(dst, src, templates, importCfg)
+		require.ErrorIs(t, err, ErrAlertmanagerImportConflict)
+	})
+
+	t.Run("should fail if the identifier is invalid", func(t *testing.T) {
+		_, err := MergeAlertmanagerConfig(newGrafanaConfig(), src, nil, AlertmanagerImportConfig{Identifier: "in valid", MergeMatchers: mergeMatchers(t)})
+		require.ErrorIs(t, err, ErrInvalidAlertmanagerConfigIdentifier)
+	})
+
+	t.Run("should fail without merge matchers", func(t *testing.T) {
+		_, err := MergeAlertmanagerConfig(newGrafanaConfig(), src, nil, AlertmanagerImportConfig{Identifier: "prom"})
+		require.ErrorIs(t, err, ErrMissingMergeMatchers)
+	})
+}
@@ -0,0 +1,284 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/ngalert/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning/validation"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// AlertRuleTemplateService manages alert rule templates. A template owns its rule group: every change to the
+// template replaces the rules of the group with the rules expanded from the template.
+type AlertRuleTemplateService struct {
+	store           AlertRuleTemplateStore
+	rules           *AlertRuleService
+	provenanceStore ProvisioningStore
+	xact            TransactionManager
+	log             log.Logger
+}
+
+func NewAlertRuleTemplateService(store AlertRuleTemplateStore, rules *AlertRuleService, provenanceStore ProvisioningStore, xact TransactionManager, log log.Logger) *AlertRuleTemplateService {
+	return &AlertRuleTemplateService{
+		store:           store,
+		rules:           rules,
+		provenanceStore: provenanceStore,
+		xact:            xact,
+		log:             log,
+	}
+}
+
+// GetTemplates returns the templates that the user can read, and their provenances.
+func (service *AlertRuleTemplateService) GetTemplates(ctx context.Context, user identity.Requester) ([]models.AlertRuleTemplate, map[string]models.Provenance, error) {
+	templates, err := service.store.ListAlertRuleTemplates(ctx, &models.ListAlertRuleTemplatesQuery{OrgID: user.GetOrgID()})
+	if err != nil {
+		return nil, nil, err
+	}
+	provenances, err := service.provenanceStore.GetProvenances(ctx, user.GetOrgID(), (&models.AlertRuleTemplate{}).ResourceType())
+	if err != nil {
+		return nil, nil, err
+	}
+	result := make([]models.AlertRuleTemplate, 0, len(templates))
+	for _, t := range templates {
+		if err := service.authorizeRead(ctx, user, t); err != nil {
+			if errors.Is(err, accesscontrol.ErrAuthorizationBase) {
+				delete(provenances, t.ResourceID())
+				continue
+			}
+			return nil, nil, err
+		}
+		result = append(result, *t)
+	}
+	return result, provenances, nil
+}
+
+// GetTemplate returns the template with the given UID and its provenance.
+func (service *AlertRuleTemplateService) GetTemplate(ctx context.Context, user identity.Requester, uid string) (models.AlertRuleTemplate, models.Provenance, error) {
+	t, err := service.getTemplateAuthorized(ctx, user, uid)
+	if err != nil {
+		return models.AlertRuleTemplate{}, models.ProvenanceNone, err
+	}
+	provenance, err := service.provenanceStore.GetProvenance(ctx, &t, user.GetOrgID())
+	if err != nil {
+		return models.AlertRuleTemplate{}, models.ProvenanceNone, err
+	}
+	return t, provenance, nil
+}
+
+// GetTemplatesWithFolderFullpath returns the templates that the user can read with the full path of their folders.
+// If UIDs are specified, only these templates are returned.
+func (service *AlertRuleTemplateService) GetTemplatesWithFolderFullpath(ctx context.Context, user identity.Requester, uids ...string) ([]models.AlertRuleTemplateWithFolderFullpath, error) {
+	var templates []models.AlertRuleTemplate
+	if len(uids) > 0 {
+		for _, uid := range uids {
+			t, err := service.getTemplateAuthorized(ctx, user, uid)
+			if err != nil {
+				return nil, err
+			}
+			templates = append(templates, t)
+		}
+	} else {
+		var err error
+		templates, _, err = service.GetTemplates(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := make([]models.AlertRuleTemplateWithFolderFullpath, 0, len(templates))
+	fullpaths := make(map[string]string)
+	for i := range templates {
+		t := templates[i]
+		fullpath, ok := fullpaths[t.NamespaceUID]
+		if !ok {
+			f, err := service.rules.folderService.Get(ctx, &folder.GetFolderQuery{
+				OrgID:        user.GetOrgID(),
+				UID:          &t.NamespaceUID,
+				WithFullpath: true,
+				SignedInUser: user,
+			})
+			if err != nil {
+				return nil, err
+			}
+			fullpath = f.Fullpath
+			fullpaths[t.NamespaceUID] = fullpath
+		}
+		result = append(result, models.AlertRuleTemplateWithFolderFullpath{AlertRuleTemplate: &t, FolderFullpath: fullpath})
+	}
+	return result, nil
+}
+
+// CreateTemplate creates a template and the rules of its rule group. The rule group must not exist.
+func (service *AlertRuleTemplateService) CreateTemplate(ctx context.Context, user identity.Requester, template models.AlertRuleTemplate, provenance models.Provenance) (models.AlertRuleTemplate, error) {
+	template.OrgID = user.GetOrgID()
+	if template.UID == "" {
+		template.UID = util.GenerateShortUID()
+	} else if err := util.ValidateUID(template.UID); err != nil {
+		return models.AlertRuleTemplate{}, fmt.Errorf("%w: cannot create template with UID '%s': %w", models.ErrAlertRuleTemplateFailedValidation, template.UID, err)
+	}
+	if template.IntervalSeconds == 0 {
+		template.IntervalSeconds = service.rules.defaultIntervalSeconds
+	}
+	group, err := service.expand(&template)
+	if err != nil {
+		return models.AlertRuleTemplate{}, err
+	}
+	if err := service.rules.ensureNamespace(ctx, user, template.OrgID, template.NamespaceUID); err != nil {
+		return models.AlertRuleTemplate{}, err
+	}
+
+	var created models.AlertRuleTemplate
+	err = service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		existing, err := service.rules.ruleStore.ListAlertRules(ctx, &models.ListAlertRulesQuery{
+			OrgID:         template.OrgID,
+			NamespaceUIDs: []string{template.NamespaceUID},
+			RuleGroups:    []string{template.RuleGroup},
+		})
+		if err != nil {
+			return err
+		}
+		if len(existing) > 0 {
+			return models.ErrAlertRuleTemplateConflict(template, errors.New("the rule group already exists, a template must own its rule group"))
+		}
+		created, err = service.store.InsertAlertRuleTemplate(ctx, userUidOrFallback(user), template)
+		if err != nil {
+			return err
+		}
+		if err := service.rules.replaceRuleGroup(ctx, user, group, provenance, true); err != nil {
+			return err
+		}
+		return service.provenanceStore.SetProvenance(ctx, &created, template.OrgID, provenance)
+	})
+	if err != nil {
+		return models.AlertRuleTemplate{}, err
+	}
+	return created, nil
+}
+
+// UpdateTemplate updates a template and propagates the changes to the rules of its rule group.
+// Rules of instances that are removed from the template are deleted.
+func (service *AlertRuleTemplateService) UpdateTemplate(ctx context.Context, user identity.Requester, template models.AlertRuleTemplate, provenance models.Provenance) (models.AlertRuleTemplate, error) {
+	template.OrgID = user.GetOrgID()
+	existing, err := service.store.GetAlertRuleTemplate(ctx, template.OrgID, template.UID)
+	if err != nil {
+		return models.AlertRuleTemplate{}, err
+	}
+	if existing.NamespaceUID != template.NamespaceUID || existing.RuleGroup != template.RuleGroup {
+		return models.AlertRuleTemplate{}, fmt.Errorf("%w: the folder and the rule group of a template cannot be changed", models.ErrAlertRuleTemplateFailedValidation)
+	}
+	if err := service.checkProvenance(ctx, existing, provenance); err != nil {
+		return models.AlertRuleTemplate{}, err
+	}
+	template.ID = existing.ID
+	template.Version = existing.Version
+	if template.IntervalSeconds == 0 {
+		template.IntervalSeconds = existing.IntervalSeconds
+	}
+	group, err := service.expand(&template)
+	if err != nil {
+		return models.AlertRuleTemplate{}, err
+	}
+
+	var updated models.AlertRuleTemplate
+	err = service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		updated, err = service.store.UpdateAlertRuleTemplate(ctx, userUidOrFallback(user), template)
+		if err != nil {
+			return err
+		}
+		if err := service.rules.replaceRuleGroup(ctx, user, group, provenance, true); err != nil {
+			return err
+		}
+		return service.provenanceStore.SetProvenance(ctx, &updated, template.OrgID, provenance)
+	})
+	if err != nil {
+		return models.AlertRuleTemplate{}, err
+	}
+	return updated, nil
+}
+
+// DeleteTemplate deletes a template together with the rules of its rule group.
+func (service *AlertRuleTemplateService) DeleteTemplate(ctx context.Context, user identity.Requester, uid string, provenance models.Provenance) error {
+	existing, err := service.store.GetAlertRuleTemplate(ctx, user.GetOrgID(), uid)
+	if err != nil {
+		return err
+	}
+	if err := service.checkProvenance(ctx, existing, provenance); err != nil {
+		return err
+	}
+	return service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := service.rules.deleteRuleGroups(ctx, user, provenance, &FilterOptions{
+			NamespaceUIDs: []string{existing.NamespaceUID},
+			RuleGroups:    []string{existing.RuleGroup},
+		}, true); err != nil {
+			return err
+		}
+		if err := service.store.DeleteAlertRuleTemplate(ctx, user.GetOrgID(), uid); err != nil {
+			return err
+		}
+		return service.provenanceStore.DeleteProvenance(ctx, existing, user.GetOrgID())
+	})
+}
+
+// expand assigns UIDs to the new instances of the template, validates it and returns the rule group of the template.
+func (service *AlertRuleTemplateService) expand(template *models.AlertRuleTemplate) (models.AlertRuleGroup, error) {
+	for i := range template.Instances {
+		if template.Instances[i].UID == "" {
+			template.Instances[i].UID = util.GenerateShortUID()
+		}
+	}
+	if err := template.Validate(); err != nil {
+		return models.AlertRuleGroup{}, err
+	}
+	rules, err := template.Expand()
+	if err != nil {
+		return models.AlertRuleGroup{}, err
+	}
+	return models.AlertRuleGroup{
+		Title:     template.RuleGroup,
+		FolderUID: template.NamespaceUID,
+		Interval:  template.IntervalSeconds,
+		Rules:     rules,
+	}, nil
+}
+
+func (service *AlertRuleTemplateService) checkProvenance(ctx context.Context, template *models.AlertRuleTemplate, provenance models.Provenance) error {
+	storedProvenance, err := service.provenanceStore.GetProvenance(ctx, template, template.OrgID)
+	if err != nil {
+		return err
+	}
+	return validation.ValidateProvenanceRelaxed(storedProvenance, provenance)
+}
+
+func (service *AlertRuleTemplateService) getTemplateAuthorized(ctx context.Context, user identity.Requester, uid string) (models.AlertRuleTemplate, error) {
+	t, err := service.store.GetAlertRuleTemplate(ctx, user.GetOrgID(), uid)
+	if err != nil {
+		return models.AlertRuleTemplate{}, err
+	}
+	if err := service.authorizeRead(ctx, user, t); err != nil {
+		return models.AlertRuleTemplate{}, err
+	}
+	return *t, nil
+}
+
+// authorizeRead checks that the user can read the rules of the template. All rules of a template are in the same
+// folder and query the same data sources, so the rule of the template is authorized instead of the expanded rules.
+func (service *AlertRuleTemplateService) authorizeRead(ctx context.Context, user identity.Requester, template *models.AlertRuleTemplate) error {
+	can, err := service.rules.authz.CanReadAllRules(ctx, user)
+	if err != nil {
+		return err
+	}
+	if can {
+		return nil
+	}
+	rule := template.Rule.Copy()
+	rule.OrgID = template.OrgID
+	rule.NamespaceUID = template.NamespaceUID
+	rule.RuleGroup = template.RuleGroup
+	return service.rules.authz.AuthorizeRuleGroupRead(ctx, user, models.RulesGroup{rule})
+}
@@ -0,0 +1,244 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+type fakeAlertRuleTemplateStore struct {
+	templates map[string]*models.AlertRuleTemplate
+}
+
+func newFakeAlertRuleTemplateStore() *fakeAlertRuleTemplateStore {
+	return &fakeAlertRuleTemplateStore{templates: make(map[string]*models.AlertRuleTemplate)}
+}
+
+func (f *fakeAlertRuleTemplateStore) ListAlertRuleTemplates(_ context.Context, query *models.ListAlertRuleTemplatesQuery) ([]*models.AlertRuleTemplate, error) {
+	result := make([]*models.AlertRuleTemplate, 0, len(f.templates))
+	for _, t := range f.templates {
+		if t.OrgID == query.OrgID {
+			result = append(result, t)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeAlertRuleTemplateStore) GetAlertRuleTemplate(_ context.Context, orgID int64, uid string) (*models.AlertRuleTemplate, error) {
+	t, ok := f.templates[uid]
+	if !ok || t.OrgID != orgID {
+		return nil, models.ErrAlertRuleTemplateNotFound.Errorf("")
+	}
+	return t, nil
+}
+
+func (f *fakeAlertRuleTemplateStore) InsertAlertRuleTemplate(_ context.Context, _ *models.UserUID, template models.AlertRuleTemplate) (models.AlertRuleTemplate, error) {
+	template.Version = 1
+	f.templates[template.UID] = &template
+	return template, nil
+}
+
+func (f *fakeAlertRuleTemplateStore) UpdateAlertRuleTemplate(_ context.Context, _ *models.UserUID, template models.AlertRuleTemplate) (models.AlertRuleTemplate, error) {
+	template.Version++
+	f.templates[template.UID] = &template
+	return template, nil
+}
+
+func (f *fakeAlertRuleTemplateStore) DeleteAlertRuleTemplate(_ context.Context, _ int64, uid string) error {
+	delete(f.templates, uid)
+	return nil
+}
+
+func initTemplateService(t *testing.T) (*AlertRuleTemplateService, *fakeAlertRuleTemplateStore, *fakes.RuleStore, *fakes.FakeProvisioningStore, *fakeRuleAccessControlService) {
+	t.Helper()
+	rules, ruleStore, provenanceStore, ac := initService(t)
+	templateStore := newFakeAlertRuleTemplateStore()
+	return NewAlertRuleTemplateService(templateStore, rules, provenanceStore, newNopTransactionManager(), log.NewNopLogger()), templateStore, ruleStore, provenanceStore, ac
+}
+
+func testAlertRuleTemplate(orgID int64) models.AlertRuleTemplate {
+	rule := createTestRule("High latency of ${service}", "", orgID, "")
+	rule.Labels = map[string]string{"service": "${service}"}
+	rule.Data[0].Model = json.RawMessage(`{"expr":"latency{service=\"${service}\"}"}`)
+	return models.AlertRuleTemplate{
+		UID:          "template",
+		NamespaceUID: "folder",
+		RuleGroup:    "group",
+		Parameters:   []string{"service"},
+		Rule:         rule,
+		Instances: []models.AlertRuleTemplateInstance{
+			{UID: "api", Values: map[string]string{"service": "api"}},
+			{Values: map[string]string{"service": "web"}},
+		},
+	}
+}
+
+func TestAlertRuleTemplateService(t *testing.T) {
+	orgID := int64(1)
+	u := &user.SignedInUser{OrgID: orgID}
+
+	t.Run("create should expand the template into its rule group", func(t *testing.T) {
+		service, templateStore, ruleStore, provenanceStore, _ := initTemplateService(t)
+
+		created, err := service.CreateTemplate(context.Background(), u, testAlertRuleTemplate(orgID), models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.Equal(t, orgID, created.OrgID)
+		require.Equal(t, int64(60), created.IntervalSeconds)
+		require.Equal(t, "api", created.Instances[0].UID)
+		require.NotEmpty(t, created.Instances[1].UID)
+		require.Contains(t, templateStore.templates, "template")
+
+		inserted := ruleStore.GetRecordedCommands(func(cmd any) (any, bool) {
+			a, ok := cmd.([]models.AlertRule)
+			return a, ok
+		})
+		require.Len(t, inserted, 1)
+		rules := inserted[0].([]models.AlertRule)
+		require.Len(t, rules, 2)
+		require.Equal(t, "api", rules[0].UID)
+		require.Equal(t, "High latency of api", rules[0].Title)
+		require.Equal(t, "folder", rules[0].NamespaceUID)
+		require.Equal(t, "group", rules[0].RuleGroup)
+		require.Equal(t, created.Instances[1].UID, rules[1].UID)
+		require.Equal(t, "High latency of web", rules[1].Title)
+		require.JSONEq(t, `{"expr":"latency{service=\"web\"}"}`, string(rules[1].Data[0].Model))
+
+		provenance, err := provenanceStore.GetProvenance(context.Background(), &created, orgID)
+		require.NoError(t, err)
+		require.Equal(t, models.ProvenanceAPI, provenance)
+		provenance, err = provenanceStore.GetProvenance(context.Background(), &rules[0], orgID)
+		require.NoError(t, err)
+		require.Equal(t, models.ProvenanceAPI, provenance)
+	})
+
+	t.Run("create should fail if the rule group exists", func(t *testing.T) {
+		service, _, ruleStore, _, _ := initTemplateService(t)
+		rule := createTestRule("existing", "group", orgID, "folder")
+		ruleStore.PutRule(context.Background(), &rule)
+
+		_, err := service.CreateTemplate(context.Background(), u, testAlertRuleTemplate(orgID), models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrAlertRuleTemplateConflictBase)
+	})
+
+	t.Run("create should fail if the template is invalid", func(t *testing.T) {
+		service, templateStore, _, _, _ := initTemplateService(t)
+		template := testAlertRuleTemplate(orgID)
+		template.Instances[1].Values = map[string]string{}
+
+		_, err := service.CreateTemplate(context.Background(), u, template, models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrAlertRuleTemplateFailedValidation)
+		require.Empty(t, templateStore.templates)
+	})
+
+	t.Run("update should replace the rules of the group", func(t *testing.T) {
+		service, _, ruleStore, _, _ := initTemplateService(t)
+		created, err := service.CreateTemplate(context.Background(), u, testAlertRuleTemplate(orgID), models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		template := created
+		template.Rule.Title = "Latency of ${service} is too high"
+		template.Instances = template.Instances[:1]
+		updated, err := service.UpdateTemplate(context.Background(), u, template, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.Equal(t, created.Version+1, updated.Version)
+
+		updates := ruleStore.GetRecordedCommands(func(cmd any) (any, bool) {
+			a, ok := cmd.([]models.UpdateRule)
+			return a, ok
+		})
+		require.Len(t, updates, 1)
+		require.Equal(t, "Latency of api is too high", updates[0].([]models.UpdateRule)[0].New.Title)
+
+		deleted := getDeletedRules(t, ruleStore)
+		require.Len(t, deleted, 1)
+		require.Equal(t, []string{created.Instances[1].UID}, deleted[0].uids)
+	})
+
+	t.Run("update should not move the template to another group", func(t *testing.T) {
+		service, _, _, _, _ := initTemplateService(t)
+		created, err := service.CreateTemplate(context.Background(), u, testAlertRuleTemplate(orgID), models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		created.RuleGroup = "other"
+		_, err = service.UpdateTemplate(context.Background(), u, created, models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrAlertRuleTemplateFailedValidation)
+	})
+
+	t.Run("update should fail if provenance is changed to none", func(t *testing.T) {
+		service, _, _, _, _ := initTemplateService(t)
+		created, err := service.CreateTemplate(context.Background(), u, testAlertRuleTemplate(orgID), models.ProvenanceFile)
+		require.NoError(t, err)
+
+		_, err = service.UpdateTemplate(context.Background(), u, created, models.ProvenanceNone)
+		require.Error(t, err)
+	})
+
+	t.Run("delete should delete the template and its rules", func(t *testing.T) {
+		service, templateStore, ruleStore, provenanceStore, _ := initTemplateService(t)
+		created, err := service.CreateTemplate(context.Background(), u, testAlertRuleTemplate(orgID), models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		require.NoError(t, service.DeleteTemplate(context.Background(), u, created.UID, models.ProvenanceAPI))
+		require.Empty(t, templateStore.templates)
+		require.Empty(t, ruleStore.Rules[orgID])
+		provenance, err := provenanceStore.GetProvenance(context.Background(), &created, orgID)
+		require.NoError(t, err)
+		require.Equal(t, models.ProvenanceNone, provenance)
+
+		err = service.DeleteTemplate(context.Background(), u, created.UID, models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrAlertRuleTemplateNotFound)
+	})
+
+	t.Run("get should filter templates the user cannot read", func(t *testing.T) {
+		service, templateStore, _, _, ac := initTemplateService(t)
+		for _, uid := range []string{"allowed", "denied"} {
+			template := testAlertRuleTemplate(orgID)
+			template.UID = uid
+			template.OrgID = orgID
+			templateStore.templates[uid] = &template
+		}
+		ac.CanReadAllRulesFunc = func(ctx context.Context, user identity.Requester) (bool, error) {
+			return false, nil
+		}
+		ac.AuthorizeAccessToRuleGroupFunc = func(ctx context.Context, user identity.Requester, rules models.RulesGroup) error {
+			return accesscontrol.NewAuthorizationErrorGeneric("read")
+		}
+
+		templates, _, err := service.GetTemplates(context.Background(), u)
+		require.NoError(t, err)
+		require.Empty(t, templates)
+
+		_, _, err = service.GetTemplate(context.Background(), u, "allowed")
+		require.ErrorIs(t, err, accesscontrol.ErrAuthorizationBase)
+
+		ac.AuthorizeAccessToRuleGroupFunc = func(ctx context.Context, user identity.Requester, rules models.RulesGroup) error {
+			if rules[0].NamespaceUID != "folder" {
+				return errors.New("unexpected namespace")
+			}
+			return nil
+		}
+		templates, _, err = service.GetTemplates(context.Background(), u)
+		require.NoError(t, err)
+		require.Len(t, templates, 2)
+	})
+
+	t.Run("create should generate template UID", func(t *testing.T) {
+		service, _, _, _, _ := initTemplateService(t)
+		template := testAlertRuleTemplate(orgID)
+		template.UID = ""
+		created, err := service.CreateTemplate(context.Background(), u, template, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.NoError(t, util.ValidateUID(created.UID))
+	})
+}
@@ -194,6 +194,12 @@ func (service *AlertRuleService) CreateAlertRule(ctx context.Context, user ident
 	if err := service.ensureNamespace(ctx, user, rule.OrgID, rule.NamespaceUID); err != nil {
 		return models.AlertRule{}, err
 	}
+	if err := store.VerifyRuleGroupsNotOwnedByTemplates(ctx, service.ruleStore, rule.GetGroupKey()); err != nil {
+		return models.AlertRule{}, err
+	}
+	if err := store.VerifyRuleDependencies(ctx, service.ruleStore, &rule, nil); err != nil {
+		return models.AlertRule{}, err
+	}
 	// check if user can bypass fine-grained rule authorization checks. If it cannot, verfiy that the user can add rules to the group
 	canWriteAllRules, err := service.authz.CanWriteAllRules(ctx, user)
 	if err != nil {
@@ -339,6 +345,14 @@ func (service *AlertRuleService) UpdateRuleGroup(ctx context.Context, user ident
 	if err := models.ValidateRuleGroupInterval(intervalSeconds, service.baseIntervalSeconds); err != nil {
 		return err
 	}
+	groupKey := models.AlertRuleGroupKey{
+		OrgID:        user.GetOrgID(),
+		NamespaceUID: namespaceUID,
+		RuleGroup:    ruleGroup,
+	}
+	if err := store.VerifyRuleGroupsNotOwnedByTemplates(ctx, service.ruleStore, groupKey); err != nil {
+		return err
+	}
 	return service.xact.InTransaction(ctx, func(ctx context.Context) error {
 		query := &models.ListAlertRulesQuery{
 			OrgID:         user.GetOrgID(),
@@ -369,11 +383,6 @@ func (service *AlertRuleService) UpdateRuleGroup(ctx context.Context, user ident
 		}
 		// If it cannot, check that the user is authorized to perform all the changes caused by this request
 		if !can {
-			groupKey := models.AlertRuleGroupKey{
-				OrgID:        user.GetOrgID(),
-				NamespaceUID: namespaceUID,
-				RuleGroup:    ruleGroup,
-			}
 			ruleDeltas := make([]store.RuleDelta, 0, len(ruleList))
 			for _, upd := range updateRules {
 				updNew := upd.New
@@ -399,6 +408,12 @@ func (service *AlertRuleService) UpdateRuleGroup(ctx context.Context, user ident
 }
 
 func (service *AlertRuleService) ReplaceRuleGroup(ctx context.Context, user identity.Requester, group models.AlertRuleGroup, provenance models.Provenance) error {
+	return service.replaceRuleGroup(ctx, user, group, provenance, false)
+}
+
+// replaceRuleGroup replaces the rules of the group. The groups that belong to alert rule templates
+// can be changed only by the template service, which sets byTemplate.
+func (service *AlertRuleService) replaceRuleGroup(ctx context.Context, user identity.Requester, group models.AlertRuleGroup, provenance models.Provenance, byTemplate bool) error {
 	if err := models.ValidateRuleGroupInterval(group.Interval, service.baseIntervalSeconds); err != nil {
 		return err
 	}
@@ -422,6 +437,16 @@ func (service *AlertRuleService) ReplaceRuleGroup(ctx context.Context, user iden
 		return nil
 	}
 
+	if !byTemplate {
+		if err := store.VerifyGroupDeltaNotOwnedByTemplates(ctx, service.ruleStore, delta); err != nil {
+			return err
+		}
+	}
+
+	if err := store.VerifyGroupDeltaDependencies(ctx, service.ruleStore, delta); err != nil {
+		return err
+	}
+
 	// check if the current user has permissions to all rules and can bypass the regular authorization validation.
 	can, err := service.authz.CanWriteAllRules(ctx, user)
 	if err != nil {
@@ -473,6 +498,12 @@ func (service *AlertRuleService) DeleteRuleGroup(ctx context.Context, user ident
 
 // DeleteRuleGroups deletes alert rule groups by the specified filter options.
 func (service *AlertRuleService) DeleteRuleGroups(ctx context.Context, user identity.Requester, provenance models.Provenance, filterOpts *FilterOptions) error {
+	return service.deleteRuleGroups(ctx, user, provenance, filterOpts, false)
+}
+
+// deleteRuleGroups deletes alert rule groups by the specified filter options. The groups that belong to
+// alert rule templates can be deleted only by the template service, which sets byTemplate.
+func (service *AlertRuleService) deleteRuleGroups(ctx context.Context, user identity.Requester, provenance models.Provenance, filterOpts *FilterOptions, byTemplate bool) error {
 	q := models.ListAlertRulesQuery{}
 	q = filterOpts.apply(q)
 	q.OrgID = user.GetOrgID()
@@ -482,6 +513,16 @@ func (service *AlertRuleService) DeleteRuleGroups(ctx context.Context, user iden
 		return err
 	}
 
+	if !byTemplate {
+		keys := make([]models.AlertRuleGroupKey, 0, len(deltas))
+		for _, delta := range deltas {
+			keys = append(keys, delta.GroupKey)
+		}
+		if err := store.VerifyRuleGroupsNotOwnedByTemplates(ctx, service.ruleStore, keys...); err != nil {
+			return err
+		}
+	}
+
 	// Perform all deletions in a transaction
 	return service.xact.InTransaction(ctx, func(ctx context.Context) error {
 		for _, delta := range deltas {
@@ -674,6 +715,12 @@ func (service *AlertRuleService) UpdateAlertRule(ctx context.Context, user ident
 			return models.AlertRule{}, fmt.Errorf("cannot find rule in the delta")
 		}
 	}
+	if err := store.VerifyRuleGroupsNotOwnedByTemplates(ctx, service.ruleStore, storedRule.GetGroupKey(), rule.GetGroupKey()); err != nil {
+		return models.AlertRule{}, err
+	}
+	if err := store.VerifyRuleDependencies(ctx, service.ruleStore, &rule, storedRule); err != nil {
+		return models.AlertRule{}, err
+	}
 	storedProvenance, err := service.provenanceStore.GetProvenance(ctx, storedRule, storedRule.OrgID)
 	if err != nil {
 		return models.AlertRule{}, err
@@ -745,6 +792,15 @@ func (service *AlertRuleService) DeleteAlertRule(ctx context.Context, user ident
 		})
 	}
 
+	stored, err := service.ruleStore.GetAlertRuleByUID(ctx, &models.GetAlertRuleByUIDQuery{OrgID: rule.OrgID, UID: rule.UID})
+	if err == nil {
+		if err := store.VerifyRuleGroupsNotOwnedByTemplates(ctx, service.ruleStore, stored.GetGroupKey()); err != nil {
+			return err
+		}
+	} else if !errors.Is(err, models.ErrAlertRuleNotFound) {
+		return err
+	}
+
 	can, err := service.authz.CanWriteAllRules(ctx, user)
 	if err != nil {
 		return err
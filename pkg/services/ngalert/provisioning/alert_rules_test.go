@@ -759,7 +759,7 @@ func TestCreateAlertRule(t *testing.T) {
 	u := &user.SignedInUser{OrgID: orgID, UserUID: util.GenerateShortUID()}
 	groupKey := models.GenerateGroupKey(orgID)
 	groupIntervalSeconds := int64(30)
-	gen := models.RuleGen
+	gen := models.RuleGen.With(models.RuleGen.WithNoDependencies())
 	rules := gen.With(gen.WithGroupKey(groupKey), gen.WithIntervalSeconds(groupIntervalSeconds)).GenerateManyRef(3)
 	groupProvenance := models.ProvenanceAPI
 
@@ -1037,7 +1037,7 @@ func TestUpdateAlertRule(t *testing.T) {
 	u := &user.SignedInUser{OrgID: orgID}
 	groupKey := models.GenerateGroupKey(orgID)
 	groupIntervalSeconds := int64(30)
-	gen := models.RuleGen
+	gen := models.RuleGen.With(models.RuleGen.WithNoDependencies())
 	rules := gen.With(gen.WithGroupKey(groupKey), gen.WithIntervalSeconds(groupIntervalSeconds)).GenerateManyRef(3)
 	groupProvenance := models.ProvenanceAPI
 
@@ -1164,7 +1164,7 @@ func TestDeleteAlertRule(t *testing.T) {
 	u := &user.SignedInUser{OrgID: orgID}
 	groupKey := models.GenerateGroupKey(orgID)
 	groupIntervalSeconds := int64(30)
-	gen := models.RuleGen
+	gen := models.RuleGen.With(models.RuleGen.WithNoDependencies())
 	rules := gen.With(gen.WithGroupKey(groupKey), gen.WithIntervalSeconds(groupIntervalSeconds)).GenerateManyRef(3)
 	groupProvenance := models.ProvenanceAPI
 
@@ -1256,7 +1256,7 @@ func TestGetAlertRule(t *testing.T) {
 	orgID := rand.Int63()
 	u := &user.SignedInUser{OrgID: orgID}
 	groupKey := models.GenerateGroupKey(orgID)
-	gen := models.RuleGen
+	gen := models.RuleGen.With(models.RuleGen.WithNoDependencies())
 	rules := gen.With(gen.WithGroupKey(groupKey)).GenerateManyRef(3)
 	rule := rules[0]
 	expectedProvenance := models.ProvenanceAPI
@@ -1320,7 +1320,7 @@ func TestGetRuleGroup(t *testing.T) {
 	u := &user.SignedInUser{OrgID: orgID}
 	groupKey := models.GenerateGroupKey(orgID)
 	intervalSeconds := int64(30)
-	gen := models.RuleGen
+	gen := models.RuleGen.With(models.RuleGen.WithNoDependencies())
 	rules := gen.With(gen.WithGroupKey(groupKey), gen.WithIntervalSeconds(intervalSeconds)).GenerateManyRef(3)
 	derefRules := make([]models.AlertRule, 0, len(rules))
 	for _, rule := range rules {
@@ -1429,7 +1429,7 @@ func TestGetAlertRules(t *testing.T) {
 	u := &user.SignedInUser{OrgID: orgID}
 	groupKey1 := models.GenerateGroupKey(orgID)
 	groupKey2 := models.GenerateGroupKey(orgID)
-	gen := models.RuleGen
+	gen := models.RuleGen.With(models.RuleGen.WithNoDependencies())
 	rules1 := gen.With(gen.WithGroupKey(groupKey1), gen.WithUniqueGroupIndex()).GenerateManyRef(3)
 	models.RulesGroup(rules1).SortByGroupIndex()
 	rules2 := gen.With(gen.WithGroupKey(groupKey2), gen.WithUniqueGroupIndex()).GenerateManyRef(4)
@@ -1529,7 +1529,7 @@ func TestReplaceGroup(t *testing.T) {
 	u := &user.SignedInUser{OrgID: orgID}
 	groupKey := models.GenerateGroupKey(orgID)
 	groupIntervalSeconds := int64(30)
-	gen := models.RuleGen
+	gen := models.RuleGen.With(models.RuleGen.WithNoDependencies())
 	rules := gen.With(gen.WithGroupKey(groupKey), gen.WithIntervalSeconds(groupIntervalSeconds)).GenerateManyRef(3)
 	groupProvenance := models.ProvenanceAPI
 
@@ -1677,7 +1677,7 @@ func TestDeleteRuleGroup(t *testing.T) {
 	u := &user.SignedInUser{OrgID: orgID}
 	groupKey := models.GenerateGroupKey(orgID)
 	groupIntervalSeconds := int64(30)
-	gen := models.RuleGen
+	gen := models.RuleGen.With(models.RuleGen.WithNoDependencies())
 	rules := gen.With(gen.WithGroupKey(groupKey), gen.WithIntervalSeconds(groupIntervalSeconds)).GenerateManyRef(3)
 	groupProvenance := models.ProvenanceAPI
 
@@ -1787,7 +1787,7 @@ func TestDeleteRuleGroups(t *testing.T) {
 		RuleGroup:    "group1",
 	}
 
-	gen := models.RuleGen
+	gen := models.RuleGen.With(models.RuleGen.WithNoDependencies())
 	// Create rules for each group
 	rules1 := gen.With(gen.WithGroupKey(groupKey1)).GenerateManyRef(2)
 	rules2 := gen.With(gen.WithGroupKey(groupKey2)).GenerateManyRef(3)
@@ -1983,6 +1983,126 @@ func TestDeleteRuleGroups(t *testing.T) {
 	})
 }
 
+func TestRuleGroupOwnedByTemplate(t *testing.T) {
+	orgID := rand.Int63()
+	u := &user.SignedInUser{OrgID: orgID, UserUID: util.GenerateShortUID()}
+	groupKey := models.GenerateGroupKey(orgID)
+	gen := models.RuleGen.With(models.RuleGen.WithNoDependencies())
+	rules := gen.With(gen.WithGroupKey(groupKey), gen.WithIntervalSeconds(60)).GenerateManyRef(2)
+	template := &models.AlertRuleTemplate{
+		OrgID:        orgID,
+		UID:          util.GenerateShortUID(),
+		NamespaceUID: groupKey.NamespaceUID,
+		RuleGroup:    groupKey.RuleGroup,
+	}
+
+	initServiceWithData := func(t *testing.T) (*AlertRuleService, *fakes.RuleStore) {
+		service, ruleStore, _, ac := initService(t)
+		ruleStore.Rules = map[int64][]*models.AlertRule{
+			orgID: rules,
+		}
+		ruleStore.Templates = map[int64][]*models.AlertRuleTemplate{
+			orgID: {template},
+		}
+		ac.CanWriteAllRulesFunc = func(ctx context.Context, user identity.Requester) (bool, error) {
+			return true, nil
+		}
+		return service, ruleStore
+	}
+
+	requireOwnedByTemplate := func(t *testing.T, ruleStore *fakes.RuleStore, err error) {
+		t.Helper()
+		require.ErrorIs(t, err, models.ErrAlertRuleTemplateConflictBase)
+		require.ErrorIs(t, err, models.ErrAlertRuleGroupOwnedByTemplate)
+		require.Empty(t, getDeleteQueries(ruleStore))
+		require.Empty(t, ruleStore.GetRecordedCommands(func(cmd any) (any, bool) {
+			switch c := cmd.(type) {
+			case []models.AlertRule:
+				return c, true
+			case []models.UpdateRule:
+				return c, true
+			}
+			return nil, false
+		}))
+	}
+
+	t.Run("should reject a new rule in the group", func(t *testing.T) {
+		service, ruleStore := initServiceWithData(t)
+		rule := gen.With(gen.WithGroupKey(groupKey)).Generate()
+		_, err := service.CreateAlertRule(context.Background(), u, rule, models.ProvenanceNone)
+		requireOwnedByTemplate(t, ruleStore, err)
+	})
+
+	t.Run("should reject an update of a rule of the group", func(t *testing.T) {
+		service, ruleStore := initServiceWithData(t)
+		rule := models.CopyRule(rules[0])
+		rule.Title = "changed"
+		_, err := service.UpdateAlertRule(context.Background(), u, *rule, models.ProvenanceNone)
+		requireOwnedByTemplate(t, ruleStore, err)
+	})
+
+	t.Run("should reject a move of a rule to the group", func(t *testing.T) {
+		service, ruleStore := initServiceWithData(t)
+		other := gen.With(gen.WithOrgID(orgID)).GenerateRef()
+		ruleStore.Rules[orgID] = append(ruleStore.Rules[orgID], other)
+		rule := models.CopyRule(other)
+		rule.NamespaceUID = groupKey.NamespaceUID
+		rule.RuleGroup = groupKey.RuleGroup
+		_, err := service.UpdateAlertRule(context.Background(), u, *rule, models.ProvenanceNone)
+		requireOwnedByTemplate(t, ruleStore, err)
+	})
+
+	t.Run("should reject a change of the interval of the group", func(t *testing.T) {
+		service, ruleStore := initServiceWithData(t)
+		err := service.UpdateRuleGroup(context.Background(), u, groupKey.NamespaceUID, groupKey.RuleGroup, 120)
+		requireOwnedByTemplate(t, ruleStore, err)
+	})
+
+	t.Run("should reject a replacement of the group", func(t *testing.T) {
+		service, ruleStore := initServiceWithData(t)
+		group := models.AlertRuleGroup{
+			Title:     groupKey.RuleGroup,
+			FolderUID: groupKey.NamespaceUID,
+			Interval:  60,
+			Rules:     []models.AlertRule{*models.CopyRule(rules[0])},
+		}
+		err := service.ReplaceRuleGroup(context.Background(), u, group, models.ProvenanceNone)
+		requireOwnedByTemplate(t, ruleStore, err)
+	})
+
+	t.Run("should reject a deletion of a rule of the group", func(t *testing.T) {
+		service, ruleStore := initServiceWithData(t)
+		err := service.DeleteAlertRule(context.Background(), u, rules[0].UID, models.ProvenanceNone)
+		requireOwnedByTemplate(t, ruleStore, err)
+	})
+
+	t.Run("should reject a deletion of the group", func(t *testing.T) {
+		service, ruleStore := initServiceWithData(t)
+		err := service.DeleteRuleGroup(context.Background(), u, groupKey.NamespaceUID, groupKey.RuleGroup, models.ProvenanceNone)
+		requireOwnedByTemplate(t, ruleStore, err)
+	})
+
+	t.Run("should let the template service change the group", func(t *testing.T) {
+		service, ruleStore := initServiceWithData(t)
+		group := models.AlertRuleGroup{
+			Title:     groupKey.RuleGroup,
+			FolderUID: groupKey.NamespaceUID,
+			Interval:  60,
+			Rules:     []models.AlertRule{*models.CopyRule(rules[0])},
+		}
+		err := service.replaceRuleGroup(context.Background(), u, group, models.ProvenanceNone, true)
+		require.NoError(t, err)
+		require.Len(t, getDeleteQueries(ruleStore), 1)
+
+		err = service.deleteRuleGroups(context.Background(), u, models.ProvenanceNone, &FilterOptions{
+			NamespaceUIDs: []string{groupKey.NamespaceUID},
+			RuleGroups:    []string{groupKey.RuleGroup},
+		}, true)
+		require.NoError(t, err)
+		require.Len(t, getDeleteQueries(ruleStore), 2)
+	})
+}
+
 func TestProvisiongWithFullpath(t *testing.T) {
 	tracer := tracing.InitializeTracerForTest()
 	inProcBus := bus.ProvideBus(tracer)
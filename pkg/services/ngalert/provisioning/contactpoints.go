@@ -324,6 +324,7 @@ func (ecp *ContactPointService) UpdateContactPoint(ctx context.Context, orgID in
 				if err := ecp.receiverService.RenameReceiverInDependentResources(ctx, orgID, revision.Config.AlertmanagerConfig.Route, oldReceiverName, mergedReceiver.Name, provenance); err != nil {
 					return err
 				}
+				revision.RenameReceiverInEscalationPolicies(oldReceiverName, mergedReceiver.Name)
 				if err := ecp.resourcePermissions.DeleteResourcePermissions(ctx, orgID, legacy_storage.NameToUid(oldReceiverName)); err != nil {
 					return err
 				}
@@ -369,6 +370,9 @@ func (ecp *ContactPointService) DeleteContactPoint(ctx context.Context, orgID in
 	if fullRemoval && revision.ReceiverNameUsedByRoutes(name) {
 		return ErrContactPointReferenced.Errorf("")
 	}
+	if fullRemoval && revision.ReceiverNameUsedByEscalationPolicies(name) {
+		return ErrContactPointEscalated.Errorf("")
+	}
 
 	return ecp.xact.InTransaction(ctx, func(ctx context.Context) error {
 		if fullRemoval {
@@ -22,11 +22,19 @@ var (
 		errutil.WithPublic(`Time interval cannot be renamed because it is used by provisioned {{ if .Public.UsedByRules }}alert rules{{ end }}{{ if .Public.UsedByRoutes }}{{ if .Public.UsedByRules }} and {{ end }}notification policies{{ end }}. You must update those resources first using the original provision method.`),
 	)
 
+	ErrEscalationPolicyNotFound = errutil.NotFound("alerting.notifications.escalation-policies.notFound")
+	ErrEscalationPolicyExists   = errutil.BadRequest("alerting.notifications.escalation-policies.nameExists", errutil.WithPublicMessage("Escalation policy with this name already exists. Use a different name or update existing one."))
+	ErrEscalationPolicyInvalid  = errutil.BadRequest("alerting.notifications.escalation-policies.invalidFormat").MustTemplate(
+		"Invalid format of the submitted escalation policy",
+		errutil.WithPublic("Invalid escalation policy: {{ .Public.Error }}. Correct the payload and try again."),
+	)
+
 	ErrTemplateNotFound = errutil.NotFound("alerting.notifications.templates.notFound")
 	ErrTemplateInvalid  = errutil.BadRequest("alerting.notifications.templates.invalidFormat").MustTemplate("Invalid format of the submitted template", errutil.WithPublic("Template is in invalid format. Correct the payload and try again."))
 	ErrTemplateExists   = errutil.BadRequest("alerting.notifications.templates.nameExists", errutil.WithPublicMessage("Template file with this name already exists. Use a different name or update existing one."))
 
 	ErrContactPointReferenced = errutil.Conflict("alerting.notifications.contact-points.referenced", errutil.WithPublicMessage("Contact point is currently referenced by a notification policy."))
+	ErrContactPointEscalated  = errutil.Conflict("alerting.notifications.contact-points.escalated", errutil.WithPublicMessage("Contact point is currently referenced by an escalation policy."))
 	ErrContactPointUsedInRule = errutil.Conflict("alerting.notifications.contact-points.used-by-rule", errutil.WithPublicMessage("Contact point is currently used in the notification settings of one or many alert rules."))
 	contactPointUidExists     = "Receiver configuration with UID '{{ .Public.UID }}' already exists in contact point '{{ .Public.Name }}'. Please use unique identifiers for receivers across all contact points."
 	ErrContactPointUidExists  = errutil.Conflict("alerting.notifications.contact-points.uidInUse").MustTemplate(
@@ -117,3 +125,13 @@ func MakeErrContactPointUidExists(uid, name string) error {
 		},
 	})
 }
+
+// MakeErrEscalationPolicyInvalid creates an error with the ErrEscalationPolicyInvalid template
+func MakeErrEscalationPolicyInvalid(err error) error {
+	return ErrEscalationPolicyInvalid.Build(errutil.TemplateData{
+		Public: map[string]any{
+			"Error": err.Error(),
+		},
+		Error: err,
+	})
+}
@@ -0,0 +1,263 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/legacy_storage"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning/validation"
+)
+
+type EscalationPolicyService struct {
+	configStore     alertmanagerConfigStore
+	provenanceStore ProvisioningStore
+	xact            TransactionManager
+	log             log.Logger
+	validator       validation.ProvenanceStatusTransitionValidator
+}
+
+func NewEscalationPolicyService(config alertmanagerConfigStore, prov ProvisioningStore, xact TransactionManager, log log.Logger) *EscalationPolicyService {
+	return &EscalationPolicyService{
+		configStore:     config,
+		provenanceStore: prov,
+		xact:            xact,
+		log:             log,
+		validator:       validation.ValidateProvenanceRelaxed,
+	}
+}
+
+// GetEscalationPolicies returns a slice of all escalation policies within the specified org.
+func (svc *EscalationPolicyService) GetEscalationPolicies(ctx context.Context, orgID int64) ([]definitions.ProvisionedEscalationPolicy, error) {
+	rev, err := svc.configStore.Get(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rev.Config.EscalationPolicies) == 0 {
+		return []definitions.ProvisionedEscalationPolicy{}, nil
+	}
+
+	provenances, err := svc.provenanceStore.GetProvenances(ctx, orgID, (&definitions.ProvisionedEscalationPolicy{}).ResourceType())
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]definitions.ProvisionedEscalationPolicy, 0, len(rev.Config.EscalationPolicies))
+	for _, p := range rev.Config.EscalationPolicies {
+		def := definitions.ProvisionedEscalationPolicy{
+			EscalationPolicy: p,
+			Version:          calculateEscalationPolicyFingerprint(p),
+		}
+		if prov, ok := provenances[def.ResourceID()]; ok {
+			def.Provenance = definitions.Provenance(prov)
+		}
+		result = append(result, def)
+	}
+	slices.SortFunc(result, func(a, b definitions.ProvisionedEscalationPolicy) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return result, nil
+}
+
+// GetEscalationPolicy returns an escalation policy by name.
+func (svc *EscalationPolicyService) GetEscalationPolicy(ctx context.Context, name string, orgID int64) (definitions.ProvisionedEscalationPolicy, error) {
+	rev, err := svc.configStore.Get(ctx, orgID)
+	if err != nil {
+		return definitions.ProvisionedEscalationPolicy{}, err
+	}
+
+	idx := getEscalationPolicyIndex(rev, name)
+	if idx == -1 {
+		return definitions.ProvisionedEscalationPolicy{}, ErrEscalationPolicyNotFound.Errorf("")
+	}
+
+	p := rev.Config.EscalationPolicies[idx]
+	result := definitions.ProvisionedEscalationPolicy{
+		EscalationPolicy: p,
+		Version:          calculateEscalationPolicyFingerprint(p),
+	}
+	prov, err := svc.provenanceStore.GetProvenance(ctx, &result, orgID)
+	if err != nil {
+		return definitions.ProvisionedEscalationPolicy{}, err
+	}
+	result.Provenance = definitions.Provenance(prov)
+	return result, nil
+}
+
+// CreateEscalationPolicy adds a new escalation policy within the specified org. The created escalation policy is returned.
+func (svc *EscalationPolicyService) CreateEscalationPolicy(ctx context.Context, p definitions.ProvisionedEscalationPolicy, orgID int64) (definitions.ProvisionedEscalationPolicy, error) {
+	if err := p.Validate(); err != nil {
+		return definitions.ProvisionedEscalationPolicy{}, MakeErrEscalationPolicyInvalid(err)
+	}
+
+	revision, err := svc.configStore.Get(ctx, orgID)
+	if err != nil {
+		return definitions.ProvisionedEscalationPolicy{}, err
+	}
+
+	if getEscalationPolicyIndex(revision, p.Name) != -1 {
+		return definitions.ProvisionedEscalationPolicy{}, ErrEscalationPolicyExists.Errorf("")
+	}
+	revision.Config.EscalationPolicies = append(revision.Config.EscalationPolicies, p.EscalationPolicy)
+	if err := revision.Config.ValidateEscalationPolicies(); err != nil {
+		return definitions.ProvisionedEscalationPolicy{}, MakeErrEscalationPolicyInvalid(err)
+	}
+
+	err = svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := svc.configStore.Save(ctx, revision, orgID); err != nil {
+			return err
+		}
+		return svc.provenanceStore.SetProvenance(ctx, &p, orgID, models.Provenance(p.Provenance))
+	})
+	if err != nil {
+		return definitions.ProvisionedEscalationPolicy{}, err
+	}
+	return definitions.ProvisionedEscalationPolicy{
+		EscalationPolicy: p.EscalationPolicy,
+		Version:          calculateEscalationPolicyFingerprint(p.EscalationPolicy),
+		Provenance:       p.Provenance,
+	}, nil
+}
+
+// UpdateEscalationPolicy replaces the escalation policy with the given name within the specified org. The policy can be renamed.
+// The replaced escalation policy is returned. If the escalation policy does not exist, ErrEscalationPolicyNotFound is returned.
+func (svc *EscalationPolicyService) UpdateEscalationPolicy(ctx context.Context, name string, p definitions.ProvisionedEscalationPolicy, orgID int64) (definitions.ProvisionedEscalationPolicy, error) {
+	if err := p.Validate(); err != nil {
+		return definitions.ProvisionedEscalationPolicy{}, MakeErrEscalationPolicyInvalid(err)
+	}
+
+	revision, err := svc.configStore.Get(ctx, orgID)
+	if err != nil {
+		return definitions.ProvisionedEscalationPolicy{}, err
+	}
+
+	idx := getEscalationPolicyIndex(revision, name)
+	if idx == -1 {
+		return definitions.ProvisionedEscalationPolicy{}, ErrEscalationPolicyNotFound.Errorf("")
+	}
+	old := definitions.ProvisionedEscalationPolicy{EscalationPolicy: revision.Config.EscalationPolicies[idx]}
+	if old.Name != p.Name && getEscalationPolicyIndex(revision, p.Name) != -1 {
+		return definitions.ProvisionedEscalationPolicy{}, ErrEscalationPolicyExists.Errorf("")
+	}
+
+	if err := svc.checkOptimisticConcurrency(old.EscalationPolicy, models.Provenance(p.Provenance), p.Version, "update"); err != nil {
+		return definitions.ProvisionedEscalationPolicy{}, err
+	}
+
+	// check that provenance is not changed in an invalid way
+	storedProvenance, err := svc.provenanceStore.GetProvenance(ctx, &old, orgID)
+	if err != nil {
+		return definitions.ProvisionedEscalationPolicy{}, err
+	}
+	if err := svc.validator(storedProvenance, models.Provenance(p.Provenance)); err != nil {
+		return definitions.ProvisionedEscalationPolicy{}, err
+	}
+
+	revision.Config.EscalationPolicies[idx] = p.EscalationPolicy
+	if err := revision.Config.ValidateEscalationPolicies(); err != nil {
+		return definitions.ProvisionedEscalationPolicy{}, MakeErrEscalationPolicyInvalid(err)
+	}
+
+	err = svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := svc.configStore.Save(ctx, revision, orgID); err != nil {
+			return err
+		}
+		if old.Name != p.Name {
+			if err := svc.provenanceStore.DeleteProvenance(ctx, &old, orgID); err != nil {
+				return err
+			}
+		}
+		return svc.provenanceStore.SetProvenance(ctx, &p, orgID, models.Provenance(p.Provenance))
+	})
+	if err != nil {
+		return definitions.ProvisionedEscalationPolicy{}, err
+	}
+	return definitions.ProvisionedEscalationPolicy{
+		EscalationPolicy: p.EscalationPolicy,
+		Version:          calculateEscalationPolicyFingerprint(p.EscalationPolicy),
+		Provenance:       p.Provenance,
+	}, nil
+}
+
+// DeleteEscalationPolicy deletes the escalation policy with the given name in the given org. If the escalation policy does not exist, no error is returned.
+func (svc *EscalationPolicyService) DeleteEscalationPolicy(ctx context.Context, name string, orgID int64, provenance definitions.Provenance, version string) error {
+	revision, err := svc.configStore.Get(ctx, orgID)
+	if err != nil {
+		return err
+	}
+
+	idx := getEscalationPolicyIndex(revision, name)
+	if idx == -1 {
+		svc.log.FromContext(ctx).Debug("Escalation policy was not found. Skip deleting", "name", name)
+		return nil
+	}
+
+	target := definitions.ProvisionedEscalationPolicy{EscalationPolicy: revision.Config.EscalationPolicies[idx], Provenance: provenance}
+	// check that provenance is not changed in an invalid way
+	storedProvenance, err := svc.provenanceStore.GetProvenance(ctx, &target, orgID)
+	if err != nil {
+		return err
+	}
+	if err := svc.validator(storedProvenance, models.Provenance(provenance)); err != nil {
+		return err
+	}
+
+	if err := svc.checkOptimisticConcurrency(target.EscalationPolicy, models.Provenance(provenance), version, "delete"); err != nil {
+		return err
+	}
+	revision.Config.EscalationPolicies = slices.Delete(revision.Config.EscalationPolicies, idx, idx+1)
+
+	return svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := svc.configStore.Save(ctx, revision, orgID); err != nil {
+			return err
+		}
+		return svc.provenanceStore.DeleteProvenance(ctx, &target, orgID)
+	})
+}
+
+func (svc *EscalationPolicyService) checkOptimisticConcurrency(current definitions.EscalationPolicy, provenance models.Provenance, desiredVersion string, action string) error {
+	if desiredVersion == "" {
+		if provenance != models.ProvenanceFile {
+			// if version is not specified and it's not a file provisioning, emit a log message to reflect that optimistic concurrency is disabled for this request
+			svc.log.Debug("ignoring optimistic concurrency check because version was not provided", "escalationPolicy", current.Name, "operation", action)
+		}
+		return nil
+	}
+	currentVersion := calculateEscalationPolicyFingerprint(current)
+	if currentVersion != desiredVersion {
+		return ErrVersionConflict.Errorf("provided version %s of escalation policy %s does not match current version %s", desiredVersion, current.Name, currentVersion)
+	}
+	return nil
+}
+
+func getEscalationPolicyIndex(rev *legacy_storage.ConfigRevision, name string) int {
+	return slices.IndexFunc(rev.Config.EscalationPolicies, func(p definitions.EscalationPolicy) bool {
+		return p.Name == name
+	})
+}
+
+func calculateEscalationPolicyFingerprint(p definitions.EscalationPolicy) string {
+	sum := fnv.New64()
+	writeString := func(s string) {
+		_, _ = sum.Write([]byte(s))
+		// add a byte sequence that cannot happen in UTF-8 strings.
+		_, _ = sum.Write([]byte{255})
+	}
+	tmp := make([]byte, 8)
+
+	writeString(p.Name)
+	writeString(p.Receiver)
+	for _, step := range p.Steps {
+		binary.LittleEndian.PutUint64(tmp, uint64(step.After))
+		_, _ = sum.Write(tmp)
+		writeString(step.Receiver)
+	}
+	return fmt.Sprintf("%016x", sum.Sum64())
+}
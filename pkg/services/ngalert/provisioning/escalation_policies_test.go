@@ -0,0 +1,248 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/legacy_storage"
+)
+
+func TestGetEscalationPolicies(t *testing.T) {
+	orgID := int64(1)
+	revision := &legacy_storage.ConfigRevision{Config: escalationPolicyTestConfig()}
+
+	t.Run("service returns policies sorted by name with provenance and version", func(t *testing.T) {
+		sut, store, prov := createEscalationPolicySvcSut()
+		store.GetFn = func(ctx context.Context, orgID int64) (*legacy_storage.ConfigRevision, error) {
+			return revision, nil
+		}
+		prov.EXPECT().GetProvenances(mock.Anything, mock.Anything, mock.Anything).Return(map[string]models.Provenance{
+			"on-call": models.ProvenanceAPI,
+		}, nil)
+
+		result, err := sut.GetEscalationPolicies(context.Background(), orgID)
+		require.NoError(t, err)
+
+		require.Len(t, result, 1)
+		require.Equal(t, "on-call", result[0].Name)
+		require.Equal(t, definitions.Provenance(models.ProvenanceAPI), result[0].Provenance)
+		require.Equal(t, calculateEscalationPolicyFingerprint(revision.Config.EscalationPolicies[0]), result[0].Version)
+	})
+
+	t.Run("service returns empty list when there are no policies", func(t *testing.T) {
+		sut, store, _ := createEscalationPolicySvcSut()
+		store.GetFn = func(ctx context.Context, orgID int64) (*legacy_storage.ConfigRevision, error) {
+			return &legacy_storage.ConfigRevision{Config: &definitions.PostableUserConfig{}}, nil
+		}
+
+		result, err := sut.GetEscalationPolicies(context.Background(), orgID)
+		require.NoError(t, err)
+		require.Empty(t, result)
+	})
+}
+
+func TestCreateEscalationPolicy(t *testing.T) {
+	orgID := int64(1)
+
+	t.Run("rejects invalid policies", func(t *testing.T) {
+		sut, _, _ := createEscalationPolicySvcSut()
+
+		_, err := sut.CreateEscalationPolicy(context.Background(), definitions.ProvisionedEscalationPolicy{
+			EscalationPolicy: definitions.EscalationPolicy{Name: "test", Receiver: "team-a"},
+		}, orgID)
+		require.ErrorIs(t, err, ErrEscalationPolicyInvalid)
+	})
+
+	t.Run("rejects policies that reference unknown receivers", func(t *testing.T) {
+		sut, store, _ := createEscalationPolicySvcSut()
+		store.GetFn = func(ctx context.Context, orgID int64) (*legacy_storage.ConfigRevision, error) {
+			return &legacy_storage.ConfigRevision{Config: escalationPolicyTestConfig()}, nil
+		}
+
+		_, err := sut.CreateEscalationPolicy(context.Background(), definitions.ProvisionedEscalationPolicy{
+			EscalationPolicy: definitions.EscalationPolicy{
+				Name:     "test",
+				Receiver: "unknown",
+				Steps:    []definitions.EscalationStep{{After: model.Duration(time.Minute), Receiver: "team-a-lead"}},
+			},
+		}, orgID)
+		require.ErrorIs(t, err, ErrEscalationPolicyInvalid)
+	})
+
+	t.Run("rejects policies that already exist", func(t *testing.T) {
+		sut, store, _ := createEscalationPolicySvcSut()
+		store.GetFn = func(ctx context.Context, orgID int64) (*legacy_storage.ConfigRevision, error) {
+			return &legacy_storage.ConfigRevision{Config: escalationPolicyTestConfig()}, nil
+		}
+
+		_, err := sut.CreateEscalationPolicy(context.Background(), definitions.ProvisionedEscalationPolicy{
+			EscalationPolicy: definitions.EscalationPolicy{
+				Name:     "on-call",
+				Receiver: "team-a-lead",
+				Steps:    []definitions.EscalationStep{{After: model.Duration(time.Minute), Receiver: "team-a"}},
+			},
+		}, orgID)
+		require.ErrorIs(t, err, ErrEscalationPolicyExists)
+	})
+
+	t.Run("saves the policy and its provenance", func(t *testing.T) {
+		sut, store, prov := createEscalationPolicySvcSut()
+		rev := &legacy_storage.ConfigRevision{Config: escalationPolicyTestConfig()}
+		rev.Config.EscalationPolicies = nil
+		store.GetFn = func(ctx context.Context, orgID int64) (*legacy_storage.ConfigRevision, error) {
+			return rev, nil
+		}
+		prov.EXPECT().SetProvenance(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		policy := escalationPolicyTestConfig().EscalationPolicies[0]
+		result, err := sut.CreateEscalationPolicy(context.Background(), definitions.ProvisionedEscalationPolicy{
+			EscalationPolicy: policy,
+			Provenance:       definitions.Provenance(models.ProvenanceAPI),
+		}, orgID)
+		require.NoError(t, err)
+
+		require.Equal(t, policy, result.EscalationPolicy)
+		require.Equal(t, calculateEscalationPolicyFingerprint(policy), result.Version)
+		require.Len(t, store.Calls, 2)
+		require.Equal(t, "Save", store.Calls[1].Method)
+		require.Equal(t, []definitions.EscalationPolicy{policy}, store.Calls[1].Args[1].(*legacy_storage.ConfigRevision).Config.EscalationPolicies)
+		prov.AssertCalled(t, "SetProvenance", mock.Anything, mock.MatchedBy(func(p *definitions.ProvisionedEscalationPolicy) bool {
+			return p.Name == policy.Name
+		}), orgID, models.ProvenanceAPI)
+	})
+}
+
+func TestUpdateEscalationPolicy(t *testing.T) {
+	orgID := int64(1)
+	updated := definitions.EscalationPolicy{
+		Name:     "on-call",
+		Receiver: "team-a",
+		Steps:    []definitions.EscalationStep{{After: model.Duration(time.Hour), Receiver: "team-a-lead"}},
+	}
+
+	t.Run("returns ErrEscalationPolicyNotFound if policy does not exist", func(t *testing.T) {
+		sut, store, _ := createEscalationPolicySvcSut()
+		store.GetFn = func(ctx context.Context, orgID int64) (*legacy_storage.ConfigRevision, error) {
+			return &legacy_storage.ConfigRevision{Config: escalationPolicyTestConfig()}, nil
+		}
+
+		_, err := sut.UpdateEscalationPolicy(context.Background(), "unknown", definitions.ProvisionedEscalationPolicy{EscalationPolicy: updated}, orgID)
+		require.ErrorIs(t, err, ErrEscalationPolicyNotFound)
+	})
+
+	t.Run("returns ErrVersionConflict if version does not match", func(t *testing.T) {
+		sut, store, _ := createEscalationPolicySvcSut()
+		store.GetFn = func(ctx context.Context, orgID int64) (*legacy_storage.ConfigRevision, error) {
+			return &legacy_storage.ConfigRevision{Config: escalationPolicyTestConfig()}, nil
+		}
+
+		_, err := sut.UpdateEscalationPolicy(context.Background(), "on-call", definitions.ProvisionedEscalationPolicy{EscalationPolicy: updated, Version: "wrong"}, orgID)
+		require.ErrorIs(t, err, ErrVersionConflict)
+	})
+
+	t.Run("moves provenance when policy is renamed", func(t *testing.T) {
+		sut, store, prov := createEscalationPolicySvcSut()
+		cfg := escalationPolicyTestConfig()
+		version := calculateEscalationPolicyFingerprint(cfg.EscalationPolicies[0])
+		store.GetFn = func(ctx context.Context, orgID int64) (*legacy_storage.ConfigRevision, error) {
+			return &legacy_storage.ConfigRevision{Config: cfg}, nil
+		}
+		prov.EXPECT().GetProvenance(mock.Anything, mock.Anything, mock.Anything).Return(models.ProvenanceAPI, nil)
+		prov.EXPECT().DeleteProvenance(mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		prov.EXPECT().SetProvenance(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		renamed := updated
+		renamed.Name = "renamed"
+		result, err := sut.UpdateEscalationPolicy(context.Background(), "on-call", definitions.ProvisionedEscalationPolicy{
+			EscalationPolicy: renamed,
+			Version:          version,
+			Provenance:       definitions.Provenance(models.ProvenanceAPI),
+		}, orgID)
+		require.NoError(t, err)
+
+		require.Equal(t, renamed, result.EscalationPolicy)
+		require.Equal(t, []definitions.EscalationPolicy{renamed}, cfg.EscalationPolicies)
+		prov.AssertCalled(t, "DeleteProvenance", mock.Anything, mock.MatchedBy(func(p *definitions.ProvisionedEscalationPolicy) bool {
+			return p.Name == "on-call"
+		}), orgID)
+		prov.AssertCalled(t, "SetProvenance", mock.Anything, mock.MatchedBy(func(p *definitions.ProvisionedEscalationPolicy) bool {
+			return p.Name == "renamed"
+		}), orgID, models.ProvenanceAPI)
+	})
+}
+
+func TestDeleteEscalationPolicy(t *testing.T) {
+	orgID := int64(1)
+
+	t.Run("does nothing if policy does not exist", func(t *testing.T) {
+		sut, store, _ := createEscalationPolicySvcSut()
+		store.GetFn = func(ctx context.Context, orgID int64) (*legacy_storage.ConfigRevision, error) {
+			return &legacy_storage.ConfigRevision{Config: escalationPolicyTestConfig()}, nil
+		}
+
+		err := sut.DeleteEscalationPolicy(context.Background(), "unknown", orgID, definitions.Provenance(models.ProvenanceAPI), "")
+		require.NoError(t, err)
+		require.Len(t, store.Calls, 1)
+	})
+
+	t.Run("deletes the policy and its provenance", func(t *testing.T) {
+		sut, store, prov := createEscalationPolicySvcSut()
+		cfg := escalationPolicyTestConfig()
+		version := calculateEscalationPolicyFingerprint(cfg.EscalationPolicies[0])
+		store.GetFn = func(ctx context.Context, orgID int64) (*legacy_storage.ConfigRevision, error) {
+			return &legacy_storage.ConfigRevision{Config: cfg}, nil
+		}
+		prov.EXPECT().GetProvenance(mock.Anything, mock.Anything, mock.Anything).Return(models.ProvenanceAPI, nil)
+		prov.EXPECT().DeleteProvenance(mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		err := sut.DeleteEscalationPolicy(context.Background(), "on-call", orgID, definitions.Provenance(models.ProvenanceAPI), version)
+		require.NoError(t, err)
+
+		require.Empty(t, cfg.EscalationPolicies)
+		require.Equal(t, "Save", store.Calls[1].Method)
+		prov.AssertCalled(t, "DeleteProvenance", mock.Anything, mock.Anything, orgID)
+	})
+}
+
+func escalationPolicyTestConfig() *definitions.PostableUserConfig {
+	return &definitions.PostableUserConfig{
+		AlertmanagerConfig: definitions.PostableApiAlertingConfig{
+			Receivers: []*definitions.PostableApiReceiver{
+				{Receiver: config.Receiver{Name: "team-a"}},
+				{Receiver: config.Receiver{Name: "team-a-lead"}},
+			},
+		},
+		EscalationPolicies: []definitions.EscalationPolicy{
+			{
+				Name:     "on-call",
+				Receiver: "team-a",
+				Steps: []definitions.EscalationStep{
+					{After: model.Duration(15 * time.Minute), Receiver: "team-a-lead"},
+				},
+			},
+		},
+	}
+}
+
+func createEscalationPolicySvcSut() (*EscalationPolicyService, *legacy_storage.AlertmanagerConfigStoreFake, *MockProvisioningStore) {
+	store := &legacy_storage.AlertmanagerConfigStoreFake{}
+	prov := &MockProvisioningStore{}
+	return &EscalationPolicyService{
+		configStore:     store,
+		provenanceStore: prov,
+		xact:            newNopTransactionManager(),
+		log:             log.NewNopLogger(),
+		validator: func(from, to models.Provenance) error {
+			return nil
+		},
+	}, store, prov
+}
@@ -37,6 +37,25 @@ type RuleStore interface {
 	UpdateAlertRules(ctx context.Context, user *models.UserUID, rule []models.UpdateRule) error
 	DeleteAlertRulesByUID(ctx context.Context, orgID int64, user *models.UserUID, permanently bool, ruleUID ...string) error
 	GetAlertRulesGroupByRuleUID(ctx context.Context, query *models.GetAlertRulesGroupByRuleUIDQuery) ([]*models.AlertRule, error)
+	ListAlertRuleTemplates(ctx context.Context, query *models.ListAlertRuleTemplatesQuery) ([]*models.AlertRuleTemplate, error)
+}
+
+// AlertRuleTemplateStore represents the ability to persist and query alert rule templates.
+type AlertRuleTemplateStore interface {
+	ListAlertRuleTemplates(ctx context.Context, query *models.ListAlertRuleTemplatesQuery) ([]*models.AlertRuleTemplate, error)
+	GetAlertRuleTemplate(ctx context.Context, orgID int64, uid string) (*models.AlertRuleTemplate, error)
+	InsertAlertRuleTemplate(ctx context.Context, user *models.UserUID, template models.AlertRuleTemplate) (models.AlertRuleTemplate, error)
+	UpdateAlertRuleTemplate(ctx context.Context, user *models.UserUID, template models.AlertRuleTemplate) (models.AlertRuleTemplate, error)
+	DeleteAlertRuleTemplate(ctx context.Context, orgID int64, uid string) error
+}
+
+// RecurringSilenceStore represents the ability to persist and query recurring silences.
+type RecurringSilenceStore interface {
+	ListRecurringSilences(ctx context.Context, query *models.ListRecurringSilencesQuery) ([]*models.RecurringSilence, error)
+	GetRecurringSilence(ctx context.Context, orgID int64, uid string) (*models.RecurringSilence, error)
+	InsertRecurringSilence(ctx context.Context, user *models.UserUID, silence models.RecurringSilence) (models.RecurringSilence, error)
+	UpdateRecurringSilence(ctx context.Context, user *models.UserUID, silence models.RecurringSilence) (models.RecurringSilence, error)
+	DeleteRecurringSilence(ctx context.Context, orgID int64, uid string) error
 }
 
 // QuotaChecker represents the ability to evaluate whether quotas are met.
@@ -0,0 +1,181 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning/validation"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// SilenceAccessControlService authorizes access to silences.
+type SilenceAccessControlService interface {
+	AuthorizeReadSilence(ctx context.Context, user identity.Requester, silence *models.Silence) error
+	AuthorizeCreateSilence(ctx context.Context, user identity.Requester, silence *models.Silence) error
+	AuthorizeUpdateSilence(ctx context.Context, user identity.Requester, silence *models.Silence) error
+}
+
+// RecurringSilenceService manages recurring silences. Recurring silences are authorized with the permissions of
+// silences: a user can manage a recurring silence if they can manage the silences that it creates.
+// The service changes only the definition of recurring silences, the silences are created by notifier.RecurringSilenceMaterializer.
+type RecurringSilenceService struct {
+	store           RecurringSilenceStore
+	authz           SilenceAccessControlService
+	provenanceStore ProvisioningStore
+	xact            TransactionManager
+	log             log.Logger
+}
+
+func NewRecurringSilenceService(store RecurringSilenceStore, authz SilenceAccessControlService, provenanceStore ProvisioningStore, xact TransactionManager, log log.Logger) *RecurringSilenceService {
+	return &RecurringSilenceService{
+		store:           store,
+		authz:           authz,
+		provenanceStore: provenanceStore,
+		xact:            xact,
+		log:             log,
+	}
+}
+
+// GetRecurringSilences returns the recurring silences that the user can read, and their provenances.
+func (service *RecurringSilenceService) GetRecurringSilences(ctx context.Context, user identity.Requester) ([]models.RecurringSilence, map[string]models.Provenance, error) {
+	silences, err := service.store.ListRecurringSilences(ctx, &models.ListRecurringSilencesQuery{OrgID: user.GetOrgID()})
+	if err != nil {
+		return nil, nil, err
+	}
+	provenances, err := service.provenanceStore.GetProvenances(ctx, user.GetOrgID(), (&models.RecurringSilence{}).ResourceType())
+	if err != nil {
+		return nil, nil, err
+	}
+	result := make([]models.RecurringSilence, 0, len(silences))
+	for _, s := range silences {
+		sil := s.Silence(models.TimeWindow{})
+		if err := service.authz.AuthorizeReadSilence(ctx, user, &sil); err != nil {
+			if errors.Is(err, accesscontrol.ErrAuthorizationBase) {
+				delete(provenances, s.ResourceID())
+				continue
+			}
+			return nil, nil, err
+		}
+		result = append(result, *s)
+	}
+	return result, provenances, nil
+}
+
+// GetRecurringSilence returns the recurring silence with the given UID and its provenance.
+func (service *RecurringSilenceService) GetRecurringSilence(ctx context.Context, user identity.Requester, uid string) (models.RecurringSilence, models.Provenance, error) {
+	s, err := service.store.GetRecurringSilence(ctx, user.GetOrgID(), uid)
+	if err != nil {
+		return models.RecurringSilence{}, models.ProvenanceNone, err
+	}
+	sil := s.Silence(models.TimeWindow{})
+	if err := service.authz.AuthorizeReadSilence(ctx, user, &sil); err != nil {
+		return models.RecurringSilence{}, models.ProvenanceNone, err
+	}
+	provenance, err := service.provenanceStore.GetProvenance(ctx, s, user.GetOrgID())
+	if err != nil {
+		return models.RecurringSilence{}, models.ProvenanceNone, err
+	}
+	return *s, provenance, nil
+}
+
+// CreateRecurringSilence creates a recurring silence. The user must be allowed to create the silences of the recurring silence.
+func (service *RecurringSilenceService) CreateRecurringSilence(ctx context.Context, user identity.Requester, silence models.RecurringSilence, provenance models.Provenance) (models.RecurringSilence, error) {
+	silence.OrgID = user.GetOrgID()
+	if silence.UID == "" {
+		silence.UID = util.GenerateShortUID()
+	} else if err := util.ValidateUID(silence.UID); err != nil {
+		return models.RecurringSilence{}, fmt.Errorf("%w: cannot create recurring silence with UID '%s': %w", models.ErrRecurringSilenceFailedValidation, silence.UID, err)
+	}
+	if err := silence.Validate(); err != nil {
+		return models.RecurringSilence{}, err
+	}
+	sil := silence.Silence(models.TimeWindow{})
+	if err := service.authz.AuthorizeCreateSilence(ctx, user, &sil); err != nil {
+		return models.RecurringSilence{}, err
+	}
+
+	var created models.RecurringSilence
+	err := service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		created, err = service.store.InsertRecurringSilence(ctx, userUidOrFallback(user), silence)
+		if err != nil {
+			return err
+		}
+		return service.provenanceStore.SetProvenance(ctx, &created, silence.OrgID, provenance)
+	})
+	if err != nil {
+		return models.RecurringSilence{}, err
+	}
+	return created, nil
+}
+
+// UpdateRecurringSilence updates a recurring silence. The user must be allowed to update the silences of the recurring silence
+// before and after the change. The silences created from the previous version are replaced by the materializer.
+func (service *RecurringSilenceService) UpdateRecurringSilence(ctx context.Context, user identity.Requester, silence models.RecurringSilence, provenance models.Provenance) (models.RecurringSilence, error) {
+	silence.OrgID = user.GetOrgID()
+	existing, err := service.store.GetRecurringSilence(ctx, silence.OrgID, silence.UID)
+	if err != nil {
+		return models.RecurringSilence{}, err
+	}
+	if err := service.checkProvenance(ctx, existing, provenance); err != nil {
+		return models.RecurringSilence{}, err
+	}
+	if err := silence.Validate(); err != nil {
+		return models.RecurringSilence{}, err
+	}
+	for _, s := range []*models.RecurringSilence{existing, &silence} {
+		sil := s.Silence(models.TimeWindow{})
+		if err := service.authz.AuthorizeUpdateSilence(ctx, user, &sil); err != nil {
+			return models.RecurringSilence{}, err
+		}
+	}
+	silence.ID = existing.ID
+	silence.Version = existing.Version
+
+	var updated models.RecurringSilence
+	err = service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		updated, err = service.store.UpdateRecurringSilence(ctx, userUidOrFallback(user), silence)
+		if err != nil {
+			return err
+		}
+		return service.provenanceStore.SetProvenance(ctx, &updated, silence.OrgID, provenance)
+	})
+	if err != nil {
+		return models.RecurringSilence{}, err
+	}
+	return updated, nil
+}
+
+// DeleteRecurringSilence deletes a recurring silence. The silences that it created are expired by the materializer.
+func (service *RecurringSilenceService) DeleteRecurringSilence(ctx context.Context, user identity.Requester, uid string, provenance models.Provenance) error {
+	existing, err := service.store.GetRecurringSilence(ctx, user.GetOrgID(), uid)
+	if err != nil {
+		return err
+	}
+	if err := service.checkProvenance(ctx, existing, provenance); err != nil {
+		return err
+	}
+	sil := existing.Silence(models.TimeWindow{})
+	if err := service.authz.AuthorizeUpdateSilence(ctx, user, &sil); err != nil {
+		return err
+	}
+	return service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := service.store.DeleteRecurringSilence(ctx, user.GetOrgID(), uid); err != nil {
+			return err
+		}
+		return service.provenanceStore.DeleteProvenance(ctx, existing, user.GetOrgID())
+	})
+}
+
+func (service *RecurringSilenceService) checkProvenance(ctx context.Context, silence *models.RecurringSilence, provenance models.Provenance) error {
+	storedProvenance, err := service.provenanceStore.GetProvenance(ctx, silence, silence.OrgID)
+	if err != nil {
+		return err
+	}
+	return validation.ValidateProvenanceRelaxed(storedProvenance, provenance)
+}
@@ -0,0 +1,183 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/stretchr/testify/require"
+
+	alertingModels "github.com/grafana/alerting/models"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+type fakeRecurringSilenceStore struct {
+	silences map[string]*models.RecurringSilence
+}
+
+func (f *fakeRecurringSilenceStore) ListRecurringSilences(_ context.Context, query *models.ListRecurringSilencesQuery) ([]*models.RecurringSilence, error) {
+	result := make([]*models.RecurringSilence, 0, len(f.silences))
+	for _, s := range f.silences {
+		if query.OrgID == 0 || s.OrgID == query.OrgID {
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeRecurringSilenceStore) GetRecurringSilence(_ context.Context, orgID int64, uid string) (*models.RecurringSilence, error) {
+	s, ok := f.silences[uid]
+	if !ok || s.OrgID != orgID {
+		return nil, models.ErrRecurringSilenceNotFound.Errorf("")
+	}
+	return s, nil
+}
+
+func (f *fakeRecurringSilenceStore) InsertRecurringSilence(_ context.Context, _ *models.UserUID, silence models.RecurringSilence) (models.RecurringSilence, error) {
+	silence.Version = 1
+	f.silences[silence.UID] = &silence
+	return silence, nil
+}
+
+func (f *fakeRecurringSilenceStore) UpdateRecurringSilence(_ context.Context, _ *models.UserUID, silence models.RecurringSilence) (models.RecurringSilence, error) {
+	silence.Version++
+	f.silences[silence.UID] = &silence
+	return silence, nil
+}
+
+func (f *fakeRecurringSilenceStore) DeleteRecurringSilence(_ context.Context, _ int64, uid string) error {
+	delete(f.silences, uid)
+	return nil
+}
+
+// fakeSilenceAccessControlService allows access only to silences without a rule UID matcher.
+type fakeSilenceAccessControlService struct{}
+
+func (fakeSilenceAccessControlService) authorize(silence *models.Silence) error {
+	if silence.GetRuleUID() != nil {
+		return accesscontrol.NewAuthorizationErrorGeneric("access rule silence")
+	}
+	return nil
+}
+
+func (f fakeSilenceAccessControlService) AuthorizeReadSilence(_ context.Context, _ identity.Requester, silence *models.Silence) error {
+	return f.authorize(silence)
+}
+
+func (f fakeSilenceAccessControlService) AuthorizeCreateSilence(_ context.Context, _ identity.Requester, silence *models.Silence) error {
+	return f.authorize(silence)
+}
+
+func (f fakeSilenceAccessControlService) AuthorizeUpdateSilence(_ context.Context, _ identity.Requester, silence *models.Silence) error {
+	return f.authorize(silence)
+}
+
+func testRecurringSilence(uid string) models.RecurringSilence {
+	return models.RecurringSilence{
+		UID:       uid,
+		Matchers:  amv2.Matchers{{Name: util.Pointer("cluster"), Value: util.Pointer("eu-1"), IsEqual: util.Pointer(true), IsRegex: util.Pointer(false)}},
+		Comment:   "Maintenance",
+		CreatedBy: "team-a",
+		Schedule:  models.RecurringSilenceSchedule{Cron: "0 2 * * 0", Duration: 2 * time.Hour, Timezone: "Europe/Berlin"},
+	}
+}
+
+func TestRecurringSilenceService(t *testing.T) {
+	orgID := int64(1)
+	u := &user.SignedInUser{OrgID: orgID}
+	ctx := context.Background()
+
+	newService := func() (*RecurringSilenceService, *fakeRecurringSilenceStore, *fakes.FakeProvisioningStore) {
+		store := &fakeRecurringSilenceStore{silences: map[string]*models.RecurringSilence{}}
+		provenanceStore := fakes.NewFakeProvisioningStore()
+		return NewRecurringSilenceService(store, fakeSilenceAccessControlService{}, provenanceStore, newNopTransactionManager(), log.NewNopLogger()), store, provenanceStore
+	}
+
+	t.Run("create should store the recurring silence with provenance", func(t *testing.T) {
+		service, store, provenanceStore := newService()
+
+		created, err := service.CreateRecurringSilence(ctx, u, testRecurringSilence(""), models.ProvenanceFile)
+		require.NoError(t, err)
+		require.NotEmpty(t, created.UID)
+		require.Equal(t, orgID, created.OrgID)
+		require.Contains(t, store.silences, created.UID)
+
+		provenance, err := provenanceStore.GetProvenance(ctx, &created, orgID)
+		require.NoError(t, err)
+		require.Equal(t, models.ProvenanceFile, provenance)
+	})
+
+	t.Run("create should fail if the recurring silence is invalid", func(t *testing.T) {
+		service, store, _ := newService()
+		s := testRecurringSilence("maintenance")
+		s.Schedule.Duration = 0
+
+		_, err := service.CreateRecurringSilence(ctx, u, s, models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrRecurringSilenceFailedValidation)
+		require.Empty(t, store.silences)
+	})
+
+	t.Run("create should fail if the user cannot create the silences", func(t *testing.T) {
+		service, store, _ := newService()
+		s := testRecurringSilence("maintenance")
+		s.Matchers = append(s.Matchers, &amv2.Matcher{Name: util.Pointer(alertingModels.RuleUIDLabel), Value: util.Pointer("rule"), IsEqual: util.Pointer(true), IsRegex: util.Pointer(false)})
+
+		_, err := service.CreateRecurringSilence(ctx, u, s, models.ProvenanceAPI)
+		require.ErrorIs(t, err, accesscontrol.ErrAuthorizationBase)
+		require.Empty(t, store.silences)
+	})
+
+	t.Run("list should return only the recurring silences that the user can read", func(t *testing.T) {
+		service, store, _ := newService()
+		_, err := service.CreateRecurringSilence(ctx, u, testRecurringSilence("maintenance"), models.ProvenanceAPI)
+		require.NoError(t, err)
+		hidden := testRecurringSilence("hidden")
+		hidden.OrgID = orgID
+		hidden.Matchers = append(hidden.Matchers, &amv2.Matcher{Name: util.Pointer(alertingModels.RuleUIDLabel), Value: util.Pointer("rule"), IsEqual: util.Pointer(true), IsRegex: util.Pointer(false)})
+		store.silences["hidden"] = &hidden
+
+		silences, provenances, err := service.GetRecurringSilences(ctx, u)
+		require.NoError(t, err)
+		require.Len(t, silences, 1)
+		require.Equal(t, "maintenance", silences[0].UID)
+		require.Equal(t, map[string]models.Provenance{"maintenance": models.ProvenanceAPI}, provenances)
+	})
+
+	t.Run("update should keep the identity and bump the version", func(t *testing.T) {
+		service, _, _ := newService()
+		created, err := service.CreateRecurringSilence(ctx, u, testRecurringSilence("maintenance"), models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		s := testRecurringSilence("maintenance")
+		s.Comment = "Changed"
+		updated, err := service.UpdateRecurringSilence(ctx, u, s, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.Equal(t, created.Version+1, updated.Version)
+		require.Equal(t, "Changed", updated.Comment)
+
+		_, err = service.UpdateRecurringSilence(ctx, u, testRecurringSilence("unknown"), models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrRecurringSilenceNotFound)
+	})
+
+	t.Run("update and delete should fail for provisioned recurring silences", func(t *testing.T) {
+		service, store, _ := newService()
+		_, err := service.CreateRecurringSilence(ctx, u, testRecurringSilence("maintenance"), models.ProvenanceFile)
+		require.NoError(t, err)
+
+		_, err = service.UpdateRecurringSilence(ctx, u, testRecurringSilence("maintenance"), models.ProvenanceNone)
+		require.Error(t, err)
+		require.Error(t, service.DeleteRecurringSilence(ctx, u, "maintenance", models.ProvenanceNone))
+		require.Contains(t, store.silences, "maintenance")
+
+		require.NoError(t, service.DeleteRecurringSilence(ctx, u, "maintenance", models.ProvenanceFile))
+		require.Empty(t, store.silences)
+	})
+}
@@ -365,6 +365,9 @@ func (a *alertRule) Run() error {
 				// the evaluation loop is that the rule was deleted.
 				stateTransitions := a.stateManager.DeleteStateByRuleUID(ngmodels.WithRuleKey(ctx, a.key.AlertRuleKey), a.key, ngmodels.StateReasonRuleDeleted)
 				a.expireAndSend(grafanaCtx, stateTransitions)
+			} else if errors.Is(reason, errRuleReassigned) {
+				// Save the state for the replica that takes over the rule.
+				a.stateManager.HandOffStateByRuleUID(ngmodels.WithRuleKey(ctx, a.key.AlertRuleKey), a.key)
 			} else {
 				// Otherwise, just clean up the cache.
 				a.stateManager.ForgetStateByRuleUID(ngmodels.WithRuleKey(ctx, a.key.AlertRuleKey), a.key)
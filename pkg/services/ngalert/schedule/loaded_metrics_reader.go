@@ -3,12 +3,14 @@ package schedule
 import (
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
+	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 )
 
 var _ eval.AlertingResultsReader = AlertingResultsFromRuleState{}
+var _ eval.HysteresisStreaksReader = AlertingResultsFromRuleState{}
 
 func (a *alertRule) newLoadedMetricsReader(rule *ngmodels.AlertRule) eval.AlertingResultsReader {
 	return &AlertingResultsFromRuleState{
@@ -42,3 +44,17 @@ func (n AlertingResultsFromRuleState) Read() map[data.Fingerprint]struct{} {
 	}
 	return active
 }
+
+// ReadHysteresisStreaks returns the streaks of the states whose latest result was held back by a recovery threshold.
+func (n AlertingResultsFromRuleState) ReadHysteresisStreaks() expr.HysteresisStreaks {
+	states := n.Manager.GetStatesForRuleUID(n.Rule.OrgID, n.Rule.UID)
+
+	streaks := expr.HysteresisStreaks{}
+	for _, st := range states {
+		if st.StateReason != "" || st.HysteresisStreak == nil {
+			continue
+		}
+		streaks[st.ResultFingerprint] = *st.HysteresisStreak
+	}
+	return streaks
+}
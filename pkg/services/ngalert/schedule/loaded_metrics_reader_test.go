@@ -2,11 +2,13 @@ package schedule
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
@@ -53,6 +55,29 @@ func TestLoadedResultsFromRuleState(t *testing.T) {
 	})
 }
 
+func TestHysteresisStreaksFromRuleState(t *testing.T) {
+	rule := ngmodels.RuleGen.GenerateRef()
+	streak := &expr.HysteresisStreak{Evaluations: 2, Since: time.Now()}
+	p := &FakeRuleStateProvider{
+		map[ngmodels.AlertRuleKey][]*state.State{
+			rule.GetKey(): {
+				{State: eval.Normal, ResultFingerprint: data.Fingerprint(1), HysteresisStreak: streak},
+				{State: eval.Alerting, ResultFingerprint: data.Fingerprint(2), HysteresisStreak: streak},
+				{State: eval.Alerting, ResultFingerprint: data.Fingerprint(3)},
+				{State: eval.Normal, ResultFingerprint: data.Fingerprint(4), HysteresisStreak: streak, StateReason: ngmodels.StateReasonMissingSeries},
+			},
+		},
+	}
+
+	reader := AlertingResultsFromRuleState{
+		Manager: p,
+		Rule:    rule,
+	}
+
+	streaks := reader.ReadHysteresisStreaks()
+	require.Equal(t, expr.HysteresisStreaks{1: *streak, 2: *streak}, streaks)
+}
+
 type FakeRuleStateProvider struct {
 	states map[ngmodels.AlertRuleKey][]*state.State
 }
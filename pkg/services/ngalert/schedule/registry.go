@@ -16,8 +16,9 @@ import (
 )
 
 var (
-	errRuleDeleted   = errors.New("rule deleted")
-	errRuleRestarted = errors.New("rule restarted")
+	errRuleDeleted    = errors.New("rule deleted")
+	errRuleRestarted  = errors.New("rule restarted")
+	errRuleReassigned = errors.New("rule reassigned to another replica")
 )
 
 type ruleFactory interface {
@@ -308,6 +309,13 @@ func (r ruleWithFolder) Fingerprint() fingerprint {
 		writeBytes(tmp)
 	}
 
+	for _, dep := range rule.Dependencies {
+		writeString(dep.RuleUID)
+		for _, m := range dep.Matchers {
+			writeString(m)
+		}
+	}
+
 	// fields that do not affect the state.
 	// TODO consider removing fields below from the fingerprint
 	writeInt(int64(rule.For))
@@ -214,6 +214,7 @@ func TestRuleWithFolderFingerprint(t *testing.T) {
 				},
 			},
 			MissingSeriesEvalsToResolve: util.Pointer(2),
+			Dependencies:                []models.AlertRuleDependency{{RuleUID: "parent-uid"}},
 		}
 		r2 := &models.AlertRule{
 			ID:        2,
@@ -260,6 +261,7 @@ func TestRuleWithFolderFingerprint(t *testing.T) {
 				},
 			},
 			MissingSeriesEvalsToResolve: util.Pointer(1),
+			Dependencies:                []models.AlertRuleDependency{{RuleUID: "parent-uid2", Matchers: []string{`dc="eu-1"`}}},
 		}
 
 		excludedFields := map[string]struct{}{
@@ -18,6 +18,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util/ticker"
@@ -110,6 +111,9 @@ type schedule struct {
 	tracer          tracing.Tracer
 	featureToggles  featuremgmt.FeatureToggles
 	recordingWriter RecordingWriter
+
+	// sharder partitions the alert rules across the replicas. If nil, this replica evaluates all rules.
+	sharder *ruleSharder
 }
 
 // SchedulerCfg is the scheduler configuration.
@@ -131,6 +135,10 @@ type SchedulerCfg struct {
 	RecordingWriter        RecordingWriter
 	RuleStopReasonProvider AlertRuleStopReasonProvider
 	FeatureToggles         featuremgmt.FeatureToggles
+	// Membership enables sharding of the alert rules across the replicas of the cluster. If nil, all rules are evaluated.
+	Membership notifier.ClusterMembership
+	// ShardingHandOffDelay is how long a replica waits before evaluating the rules it acquired after the replicas changed.
+	ShardingHandOffDelay time.Duration
 }
 
 // NewScheduler returns a new scheduler.
@@ -164,6 +172,10 @@ func NewScheduler(cfg SchedulerCfg, stateManager *state.Manager) *schedule {
 		featureToggles:         cfg.FeatureToggles,
 	}
 
+	if cfg.Membership != nil {
+		sch.sharder = newRuleSharder(cfg.Membership, cfg.ShardingHandOffDelay, cfg.Log, cfg.Metrics)
+	}
+
 	return &sch
 }
 
@@ -219,6 +231,17 @@ func (sch *schedule) deleteAlertRule(ctx context.Context, keys ...ngmodels.Alert
 	sch.updateRulesMetrics(alertRules)
 }
 
+// releaseAlertRule stops the evaluation of the rule that is reassigned to another replica. The routine hands off
+// the state of the rule to the new owner. If the rule is not running, its state is only removed from the cache.
+func (sch *schedule) releaseAlertRule(ctx context.Context, rule *ngmodels.AlertRule) {
+	ruleRoutine, ok := sch.registry.del(rule.GetKey())
+	if !ok {
+		sch.stateManager.ForgetStateByRuleUID(ctx, rule.GetKeyWithGroup())
+		return
+	}
+	ruleRoutine.Stop(errRuleReassigned)
+}
+
 func (sch *schedule) getRuleStopReason(ctx context.Context, key ngmodels.AlertRuleKeyWithGroup) error {
 	// If the ruleStopReasonProvider is defined, we will use it to get the reason why the
 	// alert rule was stopped. If it returns an error, we will use the default reason.
@@ -292,6 +315,13 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 
 	sch.updateRulesMetrics(alertRules)
 
+	// when the evaluation is sharded, keep only the rules that this replica owns.
+	var assignment shardAssignment
+	if sch.sharder != nil {
+		assignment = sch.sharder.filter(tick, alertRules)
+		alertRules = assignment.owned
+	}
+
 	readyToRun := make([]readyToRunItem, 0)
 	updatedRules := make([]ngmodels.AlertRuleKeyWithVersion, 0, len(updated)) // this is needed for tests only
 	restartedRules := make([]Rule, 0)
@@ -335,7 +365,12 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		}
 
 		if newRoutine && !invalidInterval {
+			_, acquired := assignment.acquired[key]
 			dispatcherGroup.Go(func() error {
+				if acquired {
+					// continue from the state that the previous owner of the rule handed off.
+					sch.stateManager.LoadStateByRuleUID(ngmodels.WithRuleKey(ctx, key), item)
+				}
 				return ruleRoutine.Run()
 			})
 		}
@@ -406,9 +441,19 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		oldRoutine.Stop(errRuleRestarted)
 	}
 
+	// stop routines of the rules that were reassigned to other replicas
+	released := make(map[ngmodels.AlertRuleKey]struct{}, len(assignment.released))
+	for _, rule := range assignment.released {
+		released[rule.GetKey()] = struct{}{}
+		sch.releaseAlertRule(ctx, rule)
+	}
+
 	// unregister and stop routines of the deleted alert rules
 	toDelete := make([]ngmodels.AlertRuleKey, 0, len(registeredDefinitions))
 	for key := range registeredDefinitions {
+		if _, ok := released[key]; ok {
+			continue
+		}
 		toDelete = append(toDelete, key)
 	}
 	sch.deleteAlertRule(ctx, toDelete...)
@@ -0,0 +1,237 @@
+package schedule
+
+import (
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+)
+
+// ringTokensPerMember is the number of virtual nodes of each member in the hash ring.
+// More tokens spread the rules more evenly across the members.
+const ringTokensPerMember = 128
+
+type ringToken struct {
+	hash   uint64
+	member string
+}
+
+// hashRing assigns alert rules to members with consistent hashing,
+// so that a change of the members only moves the rules of the members that joined or left.
+type hashRing struct {
+	members []string
+	tokens  []ringToken
+}
+
+func newHashRing(members []string) *hashRing {
+	members = slices.Clone(members)
+	slices.Sort(members)
+	members = slices.Compact(members)
+
+	tokens := make([]ringToken, 0, len(members)*ringTokensPerMember)
+	for _, m := range members {
+		for i := 0; i < ringTokensPerMember; i++ {
+			tokens = append(tokens, ringToken{hash: ringHash(fmt.Sprintf("%s#%d", m, i)), member: m})
+		}
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		if tokens[i].hash == tokens[j].hash {
+			return tokens[i].member < tokens[j].member
+		}
+		return tokens[i].hash < tokens[j].hash
+	})
+	return &hashRing{members: members, tokens: tokens}
+}
+
+// owner returns the member that evaluates the rule, or an empty string if the ring has no members.
+func (r *hashRing) owner(key ngmodels.AlertRuleKey) string {
+	if len(r.tokens) == 0 {
+		return ""
+	}
+	h := ringHash(fmt.Sprintf("%d/%s", key.OrgID, key.UID))
+	i := sort.Search(len(r.tokens), func(i int) bool {
+		return r.tokens[i].hash >= h
+	})
+	if i == len(r.tokens) {
+		i = 0
+	}
+	return r.tokens[i].member
+}
+
+func (r *hashRing) equal(members []string) bool {
+	members = slices.Clone(members)
+	slices.Sort(members)
+	return slices.Equal(r.members, slices.Compact(members))
+}
+
+func ringHash(s string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(s))
+	return h.Sum64()
+}
+
+// shardAssignment is the result of filtering the rules of a tick by the rules that this replica owns.
+type shardAssignment struct {
+	// owned are the rules that this replica evaluates.
+	owned []*ngmodels.AlertRule
+	// acquired are the owned rules that were evaluated by another replica in the previous tick.
+	acquired map[ngmodels.AlertRuleKey]struct{}
+	// released are the rules that this replica owned in the previous tick but are now evaluated by another replica.
+	// In the first tick, it contains all rules that this replica does not own.
+	released []*ngmodels.AlertRule
+}
+
+// ruleSharder partitions the alert rules across the replicas of the cluster.
+//
+// When the members change, the rules that move to another replica are released right away, while the rules that move
+// to this replica are acquired only after handOffDelay. This gives the previous owner time to notice the change
+// and to persist the state of the rules before this replica loads it.
+type ruleSharder struct {
+	membership   notifier.ClusterMembership
+	handOffDelay time.Duration
+	log          log.Logger
+	metrics      *metrics.Scheduler
+
+	ring      *hashRing
+	changedAt time.Time
+	// owned are the rules owned in the previous tick. It is nil before the first tick.
+	owned map[ngmodels.AlertRuleKey]struct{}
+}
+
+func newRuleSharder(membership notifier.ClusterMembership, handOffDelay time.Duration, logger log.Logger, m *metrics.Scheduler) *ruleSharder {
+	return &ruleSharder{
+		membership:   membership,
+		handOffDelay: handOffDelay,
+		log:          logger,
+		metrics:      m,
+	}
+}
+
+// members returns the members of the cluster. This replica is always a member, even if the cluster
+// does not know it yet: evaluating a rule twice is better than not evaluating it at all.
+func (s *ruleSharder) members() []string {
+	self := s.membership.Self()
+	members := s.membership.Members()
+	if !slices.Contains(members, self) {
+		members = append(slices.Clone(members), self)
+	}
+	return members
+}
+
+// updateRing rebuilds the ring if the members of the cluster changed since the previous tick.
+func (s *ruleSharder) updateRing(now time.Time) {
+	members := s.members()
+	if s.ring != nil && s.ring.equal(members) {
+		return
+	}
+	s.ring = newHashRing(members)
+	s.changedAt = now
+	s.log.Info("Members of the cluster changed, rebalancing alert rules", "members", s.ring.members, "handOffDelay", s.handOffDelay)
+	if s.metrics != nil {
+		s.metrics.ShardMembers.Set(float64(len(s.ring.members)))
+	}
+}
+
+// owns returns true if this replica evaluates the rule, which is assigned to a member of the ring by its shard key.
+func (s *ruleSharder) owns(key, shardKey ngmodels.AlertRuleKey, now time.Time) bool {
+	if s.ring.owner(shardKey) != s.membership.Self() {
+		return false
+	}
+	if now.Sub(s.changedAt) >= s.handOffDelay {
+		return true
+	}
+	// during the hand-off, keep only the rules that this replica already owned before the change
+	_, ok := s.owned[key]
+	return ok
+}
+
+// filter returns the rules that this replica owns at the time, and the changes of the assignment since the previous call.
+func (s *ruleSharder) filter(now time.Time, rules []*ngmodels.AlertRule) shardAssignment {
+	s.updateRing(now)
+
+	result := shardAssignment{
+		owned:    make([]*ngmodels.AlertRule, 0, len(rules)/len(s.ring.members)+1),
+		acquired: make(map[ngmodels.AlertRuleKey]struct{}),
+	}
+	first := s.owned == nil
+	owned := make(map[ngmodels.AlertRuleKey]struct{}, len(s.owned))
+	shardKeys := dependencyShardKeys(rules)
+	for _, rule := range rules {
+		key := rule.GetKey()
+		_, wasOwned := s.owned[key]
+		if !s.owns(key, shardKeys[key], now) {
+			if wasOwned || first {
+				result.released = append(result.released, rule)
+			}
+			continue
+		}
+		owned[key] = struct{}{}
+		result.owned = append(result.owned, rule)
+		if !wasOwned {
+			result.acquired[key] = struct{}{}
+		}
+	}
+	s.owned = owned
+
+	if s.metrics != nil {
+		s.metrics.ShardOwnedAlertRules.Set(float64(len(result.owned)))
+		s.metrics.ShardRuleReassignments.WithLabelValues("acquired").Add(float64(len(result.acquired)))
+		if !first {
+			s.metrics.ShardRuleReassignments.WithLabelValues("released").Add(float64(len(result.released)))
+		}
+	}
+	return result
+}
+
+// dependencyShardKeys returns the keys by which the rules are assigned to the members of the ring. The rules that are
+// connected by dependencies share the smallest key among them, so that a rule is evaluated by the same replica as its
+// parent rules, whose instances are only in the state cache of the replica that evaluates them.
+func dependencyShardKeys(rules []*ngmodels.AlertRule) map[ngmodels.AlertRuleKey]ngmodels.AlertRuleKey {
+	roots := make(map[ngmodels.AlertRuleKey]ngmodels.AlertRuleKey, len(rules))
+	for _, rule := range rules {
+		roots[rule.GetKey()] = rule.GetKey()
+	}
+	var find func(key ngmodels.AlertRuleKey) ngmodels.AlertRuleKey
+	find = func(key ngmodels.AlertRuleKey) ngmodels.AlertRuleKey {
+		root := roots[key]
+		if root != key {
+			root = find(root)
+			roots[key] = root
+		}
+		return root
+	}
+	for _, rule := range rules {
+		for _, dep := range rule.Dependencies {
+			parent := ngmodels.AlertRuleKey{OrgID: rule.OrgID, UID: dep.RuleUID}
+			// The parent might not exist anymore.
+			if _, ok := roots[parent]; !ok {
+				continue
+			}
+			a, b := find(rule.GetKey()), find(parent)
+			if a == b {
+				continue
+			}
+			if ruleKeyLess(b, a) {
+				a, b = b, a
+			}
+			roots[b] = a
+		}
+	}
+	for key := range roots {
+		find(key)
+	}
+	return roots
+}
+
+func ruleKeyLess(a, b ngmodels.AlertRuleKey) bool {
+	if a.OrgID != b.OrgID {
+		return a.OrgID < b.OrgID
+	}
+	return a.UID < b.UID
+}
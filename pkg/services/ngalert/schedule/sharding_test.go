@@ -0,0 +1,264 @@
+package schedule
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type fakeClusterMembership struct {
+	self    string
+	members []string
+}
+
+func (f *fakeClusterMembership) Self() string {
+	return f.self
+}
+
+func (f *fakeClusterMembership) Members() []string {
+	return f.members
+}
+
+func TestHashRing(t *testing.T) {
+	keys := make([]models.AlertRuleKey, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		keys = append(keys, models.GenerateRuleKey(1))
+	}
+
+	t.Run("spreads rules across members", func(t *testing.T) {
+		ring := newHashRing([]string{"a", "b", "c"})
+		counts := map[string]int{}
+		for _, key := range keys {
+			counts[ring.owner(key)]++
+		}
+		require.Len(t, counts, 3)
+		for member, count := range counts {
+			require.Greaterf(t, count, 200, "member %s owns too few rules", member)
+			require.Lessf(t, count, 470, "member %s owns too many rules", member)
+		}
+	})
+
+	t.Run("does not depend on the order of members", func(t *testing.T) {
+		r1 := newHashRing([]string{"a", "b", "c"})
+		r2 := newHashRing([]string{"c", "a", "b", "a"})
+		for _, key := range keys {
+			require.Equal(t, r1.owner(key), r2.owner(key))
+		}
+		require.True(t, r1.equal([]string{"b", "c", "a"}))
+		require.False(t, r1.equal([]string{"a", "b"}))
+	})
+
+	t.Run("moves only the rules of the member that left", func(t *testing.T) {
+		before := newHashRing([]string{"a", "b", "c"})
+		after := newHashRing([]string{"a", "b"})
+		for _, key := range keys {
+			if owner := before.owner(key); owner != "c" {
+				require.Equal(t, owner, after.owner(key))
+			}
+		}
+	})
+
+	t.Run("has no owner without members", func(t *testing.T) {
+		require.Empty(t, newHashRing(nil).owner(keys[0]))
+	})
+}
+
+func TestRuleSharder(t *testing.T) {
+	const handOffDelay = 10 * time.Second
+	rules := models.RuleGen.With(models.RuleGen.WithOrgID(1)).GenerateManyRef(100)
+	ownedBy := func(ring *hashRing, member string) map[models.AlertRuleKey]struct{} {
+		result := map[models.AlertRuleKey]struct{}{}
+		for _, rule := range rules {
+			if ring.owner(rule.GetKey()) == member {
+				result[rule.GetKey()] = struct{}{}
+			}
+		}
+		return result
+	}
+	keysOf := func(rules []*models.AlertRule) map[models.AlertRuleKey]struct{} {
+		result := map[models.AlertRuleKey]struct{}{}
+		for _, rule := range rules {
+			result[rule.GetKey()] = struct{}{}
+		}
+		return result
+	}
+
+	membership := &fakeClusterMembership{self: "a", members: []string{"a", "b"}}
+	sharder := newRuleSharder(membership, handOffDelay, log.NewNopLogger(), nil)
+	now := time.Unix(0, 0)
+
+	t.Run("waits for the hand-off delay on start", func(t *testing.T) {
+		assignment := sharder.filter(now, rules)
+		require.Empty(t, assignment.owned)
+		require.Empty(t, assignment.acquired)
+		require.Len(t, assignment.released, len(rules))
+
+		assignment = sharder.filter(now.Add(handOffDelay-time.Second), rules)
+		require.Empty(t, assignment.owned)
+		require.Empty(t, assignment.released)
+	})
+
+	now = now.Add(handOffDelay)
+	expected := ownedBy(newHashRing([]string{"a", "b"}), "a")
+	require.NotEmpty(t, expected)
+
+	t.Run("owns the rules assigned by the ring after the hand-off delay", func(t *testing.T) {
+		assignment := sharder.filter(now, rules)
+		require.Equal(t, expected, keysOf(assignment.owned))
+		require.Equal(t, expected, assignment.acquired)
+		require.Empty(t, assignment.released)
+
+		assignment = sharder.filter(now.Add(time.Second), rules)
+		require.Equal(t, expected, keysOf(assignment.owned))
+		require.Empty(t, assignment.acquired)
+		require.Empty(t, assignment.released)
+	})
+
+	t.Run("releases rules right away when a member joins", func(t *testing.T) {
+		membership.members = []string{"a", "b", "c"}
+		now = now.Add(time.Minute)
+		assignment := sharder.filter(now, rules)
+
+		remaining := ownedBy(newHashRing(membership.members), "a")
+		require.Equal(t, remaining, keysOf(assignment.owned))
+		require.Empty(t, assignment.acquired)
+		require.Len(t, assignment.released, len(expected)-len(remaining))
+		for _, rule := range assignment.released {
+			require.Contains(t, expected, rule.GetKey())
+			require.NotContains(t, remaining, rule.GetKey())
+		}
+		expected = remaining
+	})
+
+	t.Run("acquires rules after the hand-off delay when a member leaves", func(t *testing.T) {
+		membership.members = []string{"a", "c"}
+		now = now.Add(time.Minute)
+		assignment := sharder.filter(now, rules)
+		require.Equal(t, expected, keysOf(assignment.owned))
+		require.Empty(t, assignment.acquired)
+		require.Empty(t, assignment.released)
+
+		now = now.Add(handOffDelay)
+		assignment = sharder.filter(now, rules)
+		all := ownedBy(newHashRing(membership.members), "a")
+		require.Equal(t, all, keysOf(assignment.owned))
+		require.Len(t, assignment.acquired, len(all)-len(expected))
+		for key := range assignment.acquired {
+			require.NotContains(t, expected, key)
+		}
+		require.Empty(t, assignment.released)
+	})
+
+	t.Run("includes itself if the cluster does not know it yet", func(t *testing.T) {
+		membership.members = nil
+		now = now.Add(time.Minute)
+		sharder.filter(now, rules)
+		assignment := sharder.filter(now.Add(handOffDelay), rules)
+		require.Len(t, assignment.owned, len(rules))
+	})
+}
+
+func TestRuleSharderWithDependencies(t *testing.T) {
+	gen := models.RuleGen.With(models.RuleGen.WithOrgID(1), models.RuleGen.WithDependencies())
+	rules := gen.GenerateManyRef(100)
+	// chain the dependencies of the first rules, and make a rule depend on two chains
+	for i := 1; i < 10; i++ {
+		rules[i].Dependencies = []models.AlertRuleDependency{{RuleUID: rules[i-1].UID}}
+	}
+	for i := 11; i < 20; i++ {
+		rules[i].Dependencies = []models.AlertRuleDependency{{RuleUID: rules[10].UID}}
+	}
+	rules[20].Dependencies = []models.AlertRuleDependency{{RuleUID: rules[9].UID}, {RuleUID: rules[19].UID}}
+	// a dependency on a rule that does not exist
+	rules[21].Dependencies = []models.AlertRuleDependency{{RuleUID: "missing"}}
+
+	t.Run("assigns the rules connected by dependencies together", func(t *testing.T) {
+		keys := dependencyShardKeys(rules)
+		for i := 1; i <= 20; i++ {
+			require.Equal(t, keys[rules[0].GetKey()], keys[rules[i].GetKey()])
+		}
+		require.Equal(t, rules[21].GetKey(), keys[rules[21].GetKey()])
+
+		reversed := slices.Clone(rules)
+		slices.Reverse(reversed)
+		require.Equal(t, keys, dependencyShardKeys(reversed))
+	})
+
+	t.Run("evaluates dependent rules on the replica of their parents", func(t *testing.T) {
+		members := []string{"a", "b", "c"}
+		owners := map[models.AlertRuleKey]string{}
+		for _, member := range members {
+			sharder := newRuleSharder(&fakeClusterMembership{self: member, members: members}, 0, log.NewNopLogger(), nil)
+			for _, rule := range sharder.filter(time.Unix(0, 0), rules).owned {
+				require.NotContains(t, owners, rule.GetKey(), "rule is evaluated by more than one member")
+				owners[rule.GetKey()] = member
+			}
+		}
+		require.Len(t, owners, len(rules))
+		for _, rule := range rules {
+			for _, dep := range rule.Dependencies {
+				parent := models.AlertRuleKey{OrgID: rule.OrgID, UID: dep.RuleUID}
+				if owner, ok := owners[parent]; ok {
+					require.Equal(t, owner, owners[rule.GetKey()])
+				}
+			}
+		}
+	})
+}
+
+func TestProcessTicksWithSharding(t *testing.T) {
+	ctx := context.Background()
+	dispatcherGroup, ctx := errgroup.WithContext(ctx)
+	ruleStore := newFakeRulesStore()
+	sch := setupScheduler(t, ruleStore, nil, nil, nil, nil, nil)
+	membership := &fakeClusterMembership{self: "a", members: []string{"a", "b"}}
+	sch.sharder = newRuleSharder(membership, 0, log.NewNopLogger(), nil)
+
+	gen := models.RuleGen
+	rules := gen.With(gen.WithOrgID(1), gen.WithInterval(time.Second)).GenerateManyRef(20)
+	for _, rule := range rules {
+		ruleStore.PutRule(ctx, rule)
+	}
+	ring := newHashRing(membership.members)
+
+	tick := time.Unix(0, 0)
+	scheduled, stopped, _ := sch.processTick(ctx, dispatcherGroup, tick)
+	require.Empty(t, stopped)
+	require.NotEmpty(t, scheduled)
+	for _, item := range scheduled {
+		require.Equal(t, "a", ring.owner(item.rule.GetKey()))
+	}
+	for _, rule := range rules {
+		require.Equal(t, ring.owner(rule.GetKey()) == "a", sch.registry.exists(rule.GetKey()))
+	}
+
+	t.Run("stops the routines of rules that are reassigned", func(t *testing.T) {
+		routines := map[models.AlertRuleKey]Rule{}
+		for _, item := range scheduled {
+			routines[item.rule.GetKey()] = item.ruleRoutine
+		}
+
+		membership.self = "b"
+		tick = tick.Add(time.Second)
+		scheduled, stopped, _ = sch.processTick(ctx, dispatcherGroup, tick)
+		require.Len(t, stopped, len(routines))
+		for key, routine := range routines {
+			require.Contains(t, stopped, key)
+			require.ErrorIs(t, routine.(*alertRule).ctx.Err(), errRuleReassigned)
+			require.False(t, sch.registry.exists(key))
+		}
+		for _, item := range scheduled {
+			require.Equal(t, "b", ring.owner(item.rule.GetKey()))
+		}
+		// the rules are still known to the scheduler
+		all, _ := sch.Rules()
+		require.Len(t, all, len(rules))
+	})
+}
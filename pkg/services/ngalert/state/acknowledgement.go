@@ -0,0 +1,270 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngModels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	history_model "github.com/grafana/grafana/pkg/services/ngalert/state/historian/model"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+var (
+	ErrAlertInstanceNotFound        = errutil.NotFound("alerting.alert-instance.notFound", errutil.WithPublicMessage("alert instance not found"))
+	ErrAlertInstanceNotFiring       = errutil.BadRequest("alerting.alert-instance.notFiring", errutil.WithPublicMessage("only firing alert instances can be acknowledged"))
+	ErrAlertInstanceNotAcknowledged = errutil.NotFound("alerting.alert-instance.notAcknowledged", errutil.WithPublicMessage("alert instance is not acknowledged"))
+	ErrInvalidAcknowledgement       = errutil.ValidationFailed("alerting.alert-instance.invalidAcknowledgement")
+)
+
+// AlertInstanceSilencer creates and deletes the silences that suppress notifications of acknowledged alert instances.
+type AlertInstanceSilencer interface {
+	CreateSilence(ctx context.Context, orgID int64, ps ngModels.Silence) (string, error)
+	DeleteSilence(ctx context.Context, orgID int64, silenceID string) error
+}
+
+// AcknowledgeCommand describes an acknowledgement of a firing alert instance.
+type AcknowledgeCommand struct {
+	// By is the login of the user who acknowledges the instance.
+	By      string
+	Comment string
+	// ExpiresAt is the time when the acknowledgement expires. If nil, the acknowledgement lasts until the instance resolves.
+	ExpiresAt *time.Time
+	// Duration sets ExpiresAt relative to the time of the acknowledgement. It is mutually exclusive with ExpiresAt.
+	Duration time.Duration
+	// SuppressNotifications silences the notifications of the instance until the acknowledgement expires.
+	// It requires ExpiresAt or Duration to be set.
+	SuppressNotifications bool
+}
+
+// isAcknowledgeable returns true if the state is firing and therefore can be acknowledged.
+func isAcknowledgeable(s eval.State) bool {
+	return s == eval.Alerting || s == eval.Recovering || s == eval.NoData || s == eval.Error
+}
+
+// AcknowledgeState acknowledges the firing state of the rule identified by the fingerprint of its labels.
+// An existing acknowledgement is replaced. The acknowledgement is saved to the instance store immediately, recorded by
+// the historian and replicated to the other replicas.
+func (st *Manager) AcknowledgeState(ctx context.Context, rule *ngModels.AlertRule, fingerprint data.Fingerprint, cmd AcknowledgeCommand) (*State, error) {
+	logger := st.log.FromContext(ctx).New(append(rule.GetKey().LogContext(), "fingerprint", fingerprint.String())...)
+	now := st.clock.Now()
+
+	if cmd.By == "" {
+		return nil, ErrInvalidAcknowledgement.Errorf("user is required")
+	}
+	if cmd.Duration != 0 {
+		if cmd.ExpiresAt != nil {
+			return nil, ErrInvalidAcknowledgement.Errorf("expiry and duration are mutually exclusive")
+		}
+		expiresAt := now.Add(cmd.Duration)
+		cmd.ExpiresAt = &expiresAt
+	}
+	if cmd.ExpiresAt != nil && !cmd.ExpiresAt.After(now) {
+		return nil, ErrInvalidAcknowledgement.Errorf("expiry must be in the future")
+	}
+	if cmd.SuppressNotifications {
+		if cmd.ExpiresAt == nil {
+			return nil, ErrInvalidAcknowledgement.Errorf("expiry is required to suppress notifications")
+		}
+		if st.silencer == nil {
+			return nil, ErrInvalidAcknowledgement.Errorf("suppression of notifications is not supported")
+		}
+	}
+
+	existing := st.cache.get(rule.OrgID, rule.UID, fingerprint)
+	if existing == nil {
+		return nil, ErrAlertInstanceNotFound.Errorf("no alert instance with fingerprint %s", fingerprint)
+	}
+	if !isAcknowledgeable(existing.State) {
+		return nil, ErrAlertInstanceNotFiring.Errorf("alert instance is %s", existing.State)
+	}
+
+	ack := &ngModels.AlertInstanceAcknowledgement{
+		By:        cmd.By,
+		At:        now,
+		Comment:   cmd.Comment,
+		ExpiresAt: cmd.ExpiresAt,
+	}
+	if cmd.SuppressNotifications {
+		silenceID, err := st.silencer.CreateSilence(ctx, rule.OrgID, acknowledgementSilence(existing, ack, now))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create silence for the acknowledgement: %w", err)
+		}
+		ack.SilenceID = silenceID
+	}
+
+	// The state can change between the check above and the update, so it is checked again under the lock of the cache.
+	var previous *ngModels.AlertInstanceAcknowledgement
+	updated, err := st.cache.update(rule.OrgID, rule.UID, fingerprint, func(s *State) error {
+		if !isAcknowledgeable(s.State) {
+			return ErrAlertInstanceNotFiring.Errorf("alert instance is %s", s.State)
+		}
+		previous = s.Acknowledgement
+		s.Acknowledgement = ack
+		return nil
+	})
+	if err == nil && updated == nil {
+		err = ErrAlertInstanceNotFound.Errorf("no alert instance with fingerprint %s", fingerprint)
+	}
+	if err != nil {
+		st.deleteAcknowledgementSilence(ctx, logger, rule.OrgID, ack)
+		return nil, err
+	}
+	if previous != nil {
+		st.deleteAcknowledgementSilence(ctx, logger, rule.OrgID, previous)
+	}
+	logger.Info("Alert instance was acknowledged", "by", ack.By, "silenceID", ack.SilenceID)
+
+	st.acknowledgements.broadcast(logger, acknowledgementUpdate{
+		OrgID:           rule.OrgID,
+		RuleUID:         rule.UID,
+		CacheID:         fingerprint,
+		Acknowledgement: ack,
+	})
+	st.saveAcknowledgementChange(ctx, logger, rule, StateTransition{
+		State:                 updated,
+		PreviousState:         updated.State,
+		PreviousStateReason:   updated.StateReason,
+		AcknowledgementChange: &AcknowledgementChange{Time: now, Previous: previous},
+	})
+	return updated, nil
+}
+
+// UnacknowledgeState removes the acknowledgement of the state of the rule identified by the fingerprint of its labels.
+// The silence of the acknowledgement, if any, is deleted.
+func (st *Manager) UnacknowledgeState(ctx context.Context, rule *ngModels.AlertRule, fingerprint data.Fingerprint) (*State, error) {
+	logger := st.log.FromContext(ctx).New(append(rule.GetKey().LogContext(), "fingerprint", fingerprint.String())...)
+	now := st.clock.Now()
+
+	var previous *ngModels.AlertInstanceAcknowledgement
+	updated, err := st.cache.update(rule.OrgID, rule.UID, fingerprint, func(s *State) error {
+		if s.Acknowledgement == nil {
+			return ErrAlertInstanceNotAcknowledged.Errorf("alert instance with fingerprint %s is not acknowledged", fingerprint)
+		}
+		previous = s.Acknowledgement
+		s.Acknowledgement = nil
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if updated == nil {
+		return nil, ErrAlertInstanceNotFound.Errorf("no alert instance with fingerprint %s", fingerprint)
+	}
+	change := st.acknowledgementRemoved(ctx, logger, updated.OrgID, previous, now)
+	logger.Info("Alert instance acknowledgement was removed")
+
+	st.acknowledgements.broadcast(logger, acknowledgementUpdate{
+		OrgID:     rule.OrgID,
+		RuleUID:   rule.UID,
+		CacheID:   fingerprint,
+		RemovedAt: now,
+	})
+	st.saveAcknowledgementChange(ctx, logger, rule, StateTransition{
+		State:                 updated,
+		PreviousState:         updated.State,
+		PreviousStateReason:   updated.StateReason,
+		AcknowledgementChange: change,
+	})
+	return updated, nil
+}
+
+// updateAcknowledgements removes the acknowledgements of states that are not firing anymore or that have expired.
+// It must run before the states are sent so that the resolved notifications are not muted by the silence of the acknowledgement.
+func (st *Manager) updateAcknowledgements(ctx context.Context, logger log.Logger, transitions StateTransitions, evaluatedAt time.Time) {
+	for i := range transitions {
+		s := transitions[i].State
+		var previous *ngModels.AlertInstanceAcknowledgement
+		st.cache.updateState(s, func(s *State) {
+			if s.Acknowledgement == nil {
+				return
+			}
+			if isAcknowledgeable(s.State) && !s.Acknowledgement.IsExpired(evaluatedAt) {
+				return
+			}
+			previous = s.Acknowledgement
+			s.Acknowledgement = nil
+		})
+		if previous == nil {
+			continue
+		}
+		logger.Debug("Removing acknowledgement of alert instance", "cacheID", s.CacheID, "state", s.State, "expired", previous.IsExpired(evaluatedAt))
+		transitions[i].AcknowledgementChange = st.acknowledgementRemoved(ctx, logger, s.OrgID, previous, evaluatedAt)
+	}
+}
+
+// acknowledgementRemoved deletes the silence of the removed acknowledgement and returns the change of the state.
+func (st *Manager) acknowledgementRemoved(ctx context.Context, logger log.Logger, orgID int64, previous *ngModels.AlertInstanceAcknowledgement, now time.Time) *AcknowledgementChange {
+	if !previous.IsExpired(now) {
+		st.deleteAcknowledgementSilence(ctx, logger, orgID, previous)
+	}
+	return &AcknowledgementChange{Time: now, Previous: previous}
+}
+
+func (st *Manager) deleteAcknowledgementSilence(ctx context.Context, logger log.Logger, orgID int64, ack *ngModels.AlertInstanceAcknowledgement) {
+	if ack.SilenceID == "" || st.silencer == nil {
+		return
+	}
+	if err := st.silencer.DeleteSilence(ctx, orgID, ack.SilenceID); err != nil {
+		logger.Warn("Failed to delete the silence of the acknowledgement", "silenceID", ack.SilenceID, "error", err)
+	}
+}
+
+// saveAcknowledgementChange saves the states of the rule to the instance store and records the transition in the historian.
+func (st *Manager) saveAcknowledgementChange(ctx context.Context, logger log.Logger, rule *ngModels.AlertRule, transition StateTransition) {
+	if st.instanceStore != nil {
+		ruleKey := rule.GetKeyWithGroup()
+		instances := alertInstancesFromStates(logger, st.cache.getStatesForRuleUID(rule.OrgID, rule.UID))
+		if err := st.instanceStore.SaveAlertInstancesForRule(ctx, ruleKey, instances); err != nil {
+			logger.Error("Failed to save the acknowledgement of alert instance", "error", err)
+		}
+	}
+
+	if st.historian == nil {
+		return
+	}
+	errCh := st.historian.Record(ctx, history_model.NewRuleMeta(rule, logger), StateTransitions{transition})
+	go func() {
+		if err := <-errCh; err != nil {
+			logger.Error("Error recording the acknowledgement of alert instance", "error", err)
+		}
+	}()
+}
+
+// acknowledgementSilence returns a silence that mutes the notifications of the state until the acknowledgement expires.
+// The matchers are the labels of the alert that is sent to the Alertmanager for the state.
+func acknowledgementSilence(s *State, ack *ngModels.AlertInstanceAcknowledgement, now time.Time) ngModels.Silence {
+	alert := StateToPostableAlert(StateTransition{State: s, PreviousState: s.State}, nil, featuremgmt.WithFeatures())
+	matchers := make(amv2.Matchers, 0, len(alert.Labels))
+	for name, value := range alert.Labels {
+		matchers = append(matchers, &amv2.Matcher{
+			Name:    util.Pointer(name),
+			Value:   util.Pointer(value),
+			IsEqual: util.Pointer(true),
+			IsRegex: util.Pointer(false),
+		})
+	}
+	comment := "Acknowledged"
+	if ack.Comment != "" {
+		comment = fmt.Sprintf("Acknowledged: %s", ack.Comment)
+	}
+	startsAt := strfmt.DateTime(now)
+	endsAt := strfmt.DateTime(*ack.ExpiresAt)
+	return ngModels.Silence{
+		Silence: amv2.Silence{
+			Comment:   util.Pointer(comment),
+			CreatedBy: util.Pointer(ack.By),
+			StartsAt:  &startsAt,
+			EndsAt:    &endsAt,
+			Matchers:  matchers,
+		},
+	}
+}
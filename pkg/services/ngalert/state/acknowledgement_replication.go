@@ -0,0 +1,156 @@
+package state
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/grafana/alerting/cluster"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	ngModels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// acknowledgementsClusterKey is the key of the acknowledgements in the state that is shared by the replicas.
+const acknowledgementsClusterKey = "alert-acknowledgements"
+
+// ClusterPeer shares state with the other replicas of Grafana when high availability is configured.
+type ClusterPeer interface {
+	AddState(key string, s cluster.State, reg prometheus.Registerer) cluster.ClusterChannel
+}
+
+// acknowledgementUpdate is an acknowledgement of an alert instance, or the removal of one, that is replicated to the
+// other replicas.
+type acknowledgementUpdate struct {
+	OrgID   int64            `json:"orgId"`
+	RuleUID string           `json:"ruleUid"`
+	CacheID data.Fingerprint `json:"cacheId"`
+	// Acknowledgement is nil if the acknowledgement was removed.
+	Acknowledgement *ngModels.AlertInstanceAcknowledgement `json:"acknowledgement,omitempty"`
+	// RemovedAt is the time the acknowledgement was removed.
+	RemovedAt time.Time `json:"removedAt,omitempty"`
+}
+
+type acknowledgementKey struct {
+	orgID   int64
+	ruleUID string
+	cacheID data.Fingerprint
+}
+
+// acknowledgementReplicator broadcasts the acknowledgements that users make on this replica and applies those made on
+// the other replicas, so that all replicas that evaluate a rule have the same acknowledgements and do not overwrite
+// them when they save their states. Only changes made by users are replicated. Acknowledgements that expire or whose
+// instance resolves are removed by each replica when it evaluates the rule.
+//
+// It implements cluster.State. The most recent change of an acknowledgement wins.
+type acknowledgementReplicator struct {
+	cache   *cache
+	logger  log.Logger
+	channel cluster.ClusterChannel
+
+	mtx sync.Mutex
+	// removals are the times acknowledgements were removed by users, so that an acknowledgement received later from a
+	// replica that missed the removal is not applied again.
+	removals map[acknowledgementKey]time.Time
+}
+
+func newAcknowledgementReplicator(c *cache, peer ClusterPeer, reg prometheus.Registerer, logger log.Logger) *acknowledgementReplicator {
+	r := &acknowledgementReplicator{
+		cache:    c,
+		logger:   logger,
+		removals: make(map[acknowledgementKey]time.Time),
+	}
+	r.channel = peer.AddState(acknowledgementsClusterKey, r, reg)
+	return r
+}
+
+// broadcast sends the change of an acknowledgement made on this replica to the other replicas.
+func (r *acknowledgementReplicator) broadcast(logger log.Logger, u acknowledgementUpdate) {
+	if r == nil {
+		return
+	}
+	if u.Acknowledgement == nil {
+		r.recordRemoval(u)
+	}
+	b, err := json.Marshal([]acknowledgementUpdate{u})
+	if err != nil {
+		logger.Error("Failed to encode the acknowledgement to replicate it", "error", err)
+		return
+	}
+	r.channel.Broadcast(b)
+}
+
+// MarshalBinary returns the acknowledgements of the states in the cache and the removals of acknowledgements.
+func (r *acknowledgementReplicator) MarshalBinary() ([]byte, error) {
+	var updates []acknowledgementUpdate
+	r.cache.forEachAcknowledged(func(s *State) {
+		updates = append(updates, acknowledgementUpdate{OrgID: s.OrgID, RuleUID: s.AlertRuleUID, CacheID: s.CacheID, Acknowledgement: s.Acknowledgement})
+	})
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	for key, removedAt := range r.removals {
+		// Removals are only kept for as long as the state is in the cache.
+		if r.cache.get(key.orgID, key.ruleUID, key.cacheID) == nil {
+			delete(r.removals, key)
+			continue
+		}
+		updates = append(updates, acknowledgementUpdate{OrgID: key.orgID, RuleUID: key.ruleUID, CacheID: key.cacheID, RemovedAt: removedAt})
+	}
+	return json.Marshal(updates)
+}
+
+// Merge applies the changes of acknowledgements received from another replica.
+func (r *acknowledgementReplicator) Merge(b []byte) error {
+	var updates []acknowledgementUpdate
+	if err := json.Unmarshal(b, &updates); err != nil {
+		return err
+	}
+	for _, u := range updates {
+		r.apply(u)
+	}
+	return nil
+}
+
+func (r *acknowledgementReplicator) apply(u acknowledgementUpdate) {
+	if u.Acknowledgement == nil {
+		r.recordRemoval(u)
+		_, _ = r.cache.update(u.OrgID, u.RuleUID, u.CacheID, func(s *State) error {
+			if s.Acknowledgement != nil && !s.Acknowledgement.At.After(u.RemovedAt) {
+				s.Acknowledgement = nil
+			}
+			return nil
+		})
+		return
+	}
+
+	r.mtx.Lock()
+	removedAt, removed := r.removals[acknowledgementKey{orgID: u.OrgID, ruleUID: u.RuleUID, cacheID: u.CacheID}]
+	r.mtx.Unlock()
+	if removed && !u.Acknowledgement.At.After(removedAt) {
+		return
+	}
+	_, _ = r.cache.update(u.OrgID, u.RuleUID, u.CacheID, func(s *State) error {
+		// The instance might not be firing on this replica if the evaluations of the replicas differ, in which case the
+		// acknowledgement would be removed by the next evaluation anyway.
+		if !isAcknowledgeable(s.State) {
+			return nil
+		}
+		if s.Acknowledgement == nil || s.Acknowledgement.At.Before(u.Acknowledgement.At) {
+			s.Acknowledgement = u.Acknowledgement
+			r.logger.Debug("Applied acknowledgement from another replica", "org_id", u.OrgID, "rule_uid", u.RuleUID, "cacheID", u.CacheID, "by", u.Acknowledgement.By)
+		}
+		return nil
+	})
+}
+
+func (r *acknowledgementReplicator) recordRemoval(u acknowledgementUpdate) {
+	key := acknowledgementKey{orgID: u.OrgID, ruleUID: u.RuleUID, cacheID: u.CacheID}
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if current, ok := r.removals[key]; !ok || current.Before(u.RemovedAt) {
+		r.removals[key] = u.RemovedAt
+	}
+}
@@ -53,6 +53,7 @@ func (c *cache) RegisterMetrics(r prometheus.Registerer) {
 	r.MustRegister(newAlertCountByState(eval.Error))
 	r.MustRegister(newAlertCountByState(eval.NoData))
 	r.MustRegister(newAlertCountByState(eval.Recovering))
+	r.MustRegister(newAlertCountByState(eval.Suppressed))
 }
 
 func (c *cache) countAlertsBy(state eval.State) float64 {
@@ -188,12 +189,21 @@ func (c *cache) deleteRuleStates(ruleKey ngModels.AlertRuleKey, predicate func(s
 	return nil
 }
 
+// setRuleStates replaces the states of the rule with the states computed by an evaluation.
+// The acknowledgements of the cached states are kept, see setEvaluated.
 func (c *cache) setRuleStates(ruleKey ngModels.AlertRuleKey, s ruleStates) {
 	c.mtxStates.Lock()
 	defer c.mtxStates.Unlock()
 	if _, ok := c.states[ruleKey.OrgID]; !ok {
 		c.states[ruleKey.OrgID] = make(map[string]*ruleStates)
 	}
+	if current, ok := c.states[ruleKey.OrgID][ruleKey.UID]; ok {
+		for id, entry := range s.states {
+			if cached, ok := current.states[id]; ok {
+				entry.Acknowledgement = cached.Acknowledgement
+			}
+		}
+	}
 	c.states[ruleKey.OrgID][ruleKey.UID] = &s
 }
 
@@ -209,6 +219,68 @@ func (c *cache) set(entry *State) {
 	c.states[entry.OrgID][entry.AlertRuleUID].states[entry.CacheID] = entry
 }
 
+// setEvaluated replaces the cached state with the state computed by an evaluation. The acknowledgement of the
+// cached state is kept because users can change it while the rule is evaluated, and evaluations only change
+// acknowledgements of states that are already in the cache, see update.
+func (c *cache) setEvaluated(entry *State) {
+	c.mtxStates.Lock()
+	defer c.mtxStates.Unlock()
+	if _, ok := c.states[entry.OrgID]; !ok {
+		c.states[entry.OrgID] = make(map[string]*ruleStates)
+	}
+	rs, ok := c.states[entry.OrgID][entry.AlertRuleUID]
+	if !ok {
+		rs = &ruleStates{states: make(map[data.Fingerprint]*State)}
+		c.states[entry.OrgID][entry.AlertRuleUID] = rs
+	}
+	if cached, ok := rs.states[entry.CacheID]; ok {
+		entry.Acknowledgement = cached.Acknowledgement
+	}
+	rs.states[entry.CacheID] = entry
+}
+
+// update calls fn with the cached state under the lock of the cache, and returns a copy of the state after fn.
+// The state is changed in place so that the changes are not lost if the state is modified concurrently by an
+// evaluation. It returns nil if the state is not in the cache.
+func (c *cache) update(orgID int64, alertRuleUID string, stateId data.Fingerprint, fn func(s *State) error) (*State, error) {
+	c.mtxStates.Lock()
+	defer c.mtxStates.Unlock()
+	rs, ok := c.states[orgID][alertRuleUID]
+	if !ok {
+		return nil, nil
+	}
+	s, ok := rs.states[stateId]
+	if !ok {
+		return nil, nil
+	}
+	if err := fn(s); err != nil {
+		return nil, err
+	}
+	return s.Copy(), nil
+}
+
+// updateState is like update for a state that the caller already has, which may not be in the cache anymore.
+func (c *cache) updateState(s *State, fn func(s *State)) {
+	c.mtxStates.Lock()
+	defer c.mtxStates.Unlock()
+	fn(s)
+}
+
+// forEachAcknowledged calls fn with each acknowledged state under the read lock of the cache. fn must not modify the state.
+func (c *cache) forEachAcknowledged(fn func(s *State)) {
+	c.mtxStates.RLock()
+	defer c.mtxStates.RUnlock()
+	for _, orgStates := range c.states {
+		for _, rs := range orgStates {
+			for _, s := range rs.states {
+				if s.Acknowledgement != nil {
+					fn(s)
+				}
+			}
+		}
+	}
+}
+
 func (c *cache) get(orgID int64, alertRuleUID string, stateId data.Fingerprint) *State {
 	c.mtxStates.RLock()
 	defer c.mtxStates.RUnlock()
@@ -235,6 +307,22 @@ func (c *cache) getAll(orgID int64) []*State {
 	return states
 }
 
+func (c *cache) getAllOrgs() map[int64][]*State {
+	c.mtxStates.RLock()
+	defer c.mtxStates.RUnlock()
+	result := make(map[int64][]*State, len(c.states))
+	for orgID, orgStates := range c.states {
+		var states []*State
+		for _, v1 := range orgStates {
+			for _, v2 := range v1.states {
+				states = append(states, v2)
+			}
+		}
+		result[orgID] = states
+	}
+	return result
+}
+
 func (c *cache) getStatesForRuleUID(orgID int64, alertRuleUID string) []*State {
 	c.mtxStates.RLock()
 	defer c.mtxStates.RUnlock()
@@ -288,7 +376,7 @@ func (c *cache) GetAlertInstances() []ngModels.AlertInstance {
 				if err != nil {
 					continue
 				}
-				states = append(states, ngModels.AlertInstance{
+				instance := ngModels.AlertInstance{
 					AlertInstanceKey:  key,
 					Labels:            ngModels.InstanceLabels(v2.Labels),
 					CurrentState:      ngModels.InstanceStateType(v2.State.String()),
@@ -300,7 +388,9 @@ func (c *cache) GetAlertInstances() []ngModels.AlertInstance {
 					ResolvedAt:        v2.ResolvedAt,
 					LastSentAt:        v2.LastSentAt,
 					ResultFingerprint: v2.ResultFingerprint.String(),
-				})
+				}
+				instance.SetAcknowledgement(v2.Acknowledgement)
+				states = append(states, instance)
 			}
 		}
 	}
@@ -191,6 +191,12 @@ func TestCacheMetrics(t *testing.T) {
 				CacheID:      data.Fingerprint(rand.Int63()),
 				State:        eval.Recovering,
 			},
+			{
+				OrgID:        orgID,
+				AlertRuleUID: "rule1",
+				CacheID:      data.Fingerprint(rand.Int63()),
+				State:        eval.Suppressed,
+			},
 		}
 		expectedMetrics := `
 			# HELP grafana_alerting_alerts How many alerts by state are in the scheduler.
@@ -201,6 +207,7 @@ func TestCacheMetrics(t *testing.T) {
 			grafana_alerting_alerts{state="normal"} 1
 			grafana_alerting_alerts{state="pending"} 1
 			grafana_alerting_alerts{state="recovering"} 1
+			grafana_alerting_alerts{state="suppressed"} 1
 		`
 
 		reg := prometheus.NewPedanticRegistry()
@@ -251,3 +258,39 @@ func randomSate(ruleKey models.AlertRuleKey) State {
 		EvaluationDuration:   time.Duration(6000),
 	}
 }
+
+func TestCacheKeepsAcknowledgementOfEvaluatedStates(t *testing.T) {
+	c := newCache()
+	evaluated := func() *State {
+		return &State{OrgID: 1, AlertRuleUID: "rule1", CacheID: 1, State: eval.Alerting}
+	}
+	c.set(evaluated())
+
+	// The state is acknowledged while the rule is evaluated.
+	next := evaluated()
+	ack := &models.AlertInstanceAcknowledgement{By: "admin", At: time.Now()}
+	updated, err := c.update(1, "rule1", 1, func(s *State) error {
+		s.Acknowledgement = ack
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, ack, updated.Acknowledgement)
+
+	t.Run("setEvaluated", func(t *testing.T) {
+		c.setEvaluated(next)
+		require.Equal(t, ack, c.get(1, "rule1", 1).Acknowledgement)
+	})
+
+	t.Run("setRuleStates", func(t *testing.T) {
+		c.setRuleStates(models.AlertRuleKey{OrgID: 1, UID: "rule1"}, ruleStates{states: map[data.Fingerprint]*State{1: evaluated()}})
+		require.Equal(t, ack, c.get(1, "rule1", 1).Acknowledgement)
+	})
+
+	t.Run("update of a missing state", func(t *testing.T) {
+		updated, err := c.update(1, "rule1", 2, func(s *State) error {
+			return errors.New("unexpected call")
+		})
+		require.NoError(t, err)
+		require.Nil(t, updated)
+	})
+}
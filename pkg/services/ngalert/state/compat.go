@@ -6,6 +6,7 @@ import (
 	"net/url"
 	"path"
 	"strconv"
+	"time"
 
 	"github.com/benbjohnson/clock"
 	"github.com/go-openapi/strfmt"
@@ -34,6 +35,8 @@ const (
 // - if evaluation state is either NoData or Error, the resulting set of labels is changed:
 //   - original alert name (label: model.AlertNameLabel) is backed up to OriginalAlertName
 //   - label model.AlertNameLabel is overwritten to either NoDataAlertName or ErrorAlertName
+//
+// - if the state is acknowledged, the acknowledgement is added to the annotations
 func StateToPostableAlert(transition StateTransition, appURL *url.URL, featureToggles featuremgmt.FeatureToggles) *models.PostableAlert {
 	alertState := transition.State
 	nL := alertState.Labels.Copy()
@@ -62,6 +65,16 @@ func StateToPostableAlert(transition StateTransition, appURL *url.URL, featureTo
 		nA[alertingModels.OrgIDAnnotation] = strconv.FormatInt(alertState.OrgID, 10)
 	}
 
+	if ack := alertState.Acknowledgement; ack != nil {
+		nA[ngModels.AcknowledgedByAnnotation] = ack.By
+		if ack.Comment != "" {
+			nA[ngModels.AcknowledgementCommentAnnotation] = ack.Comment
+		}
+		if ack.ExpiresAt != nil {
+			nA[ngModels.AcknowledgedUntilAnnotation] = ack.ExpiresAt.UTC().Format(time.RFC3339)
+		}
+	}
+
 	var urlStr string
 	if uid := nL[alertingModels.RuleUIDLabel]; len(uid) > 0 && appURL != nil {
 		u := *appURL
@@ -181,6 +181,25 @@ func Test_StateToPostableAlert(t *testing.T) {
 				require.Equal(t, alertState.StateReason, result.Annotations[ngModels.StateReasonAnnotation])
 			})
 
+			t.Run("should add acknowledgement annotations if acknowledged", func(t *testing.T) {
+				alertState := randomTransition(eval.Normal, tc.state)
+				expiresAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+				alertState.Acknowledgement = &ngModels.AlertInstanceAcknowledgement{
+					By:        "admin",
+					At:        expiresAt.Add(-time.Hour),
+					Comment:   "looking into it",
+					ExpiresAt: &expiresAt,
+				}
+				result := StateToPostableAlert(alertState, appURL, featuremgmt.WithFeatures())
+				require.Equal(t, "admin", result.Annotations[ngModels.AcknowledgedByAnnotation])
+				require.Equal(t, "looking into it", result.Annotations[ngModels.AcknowledgementCommentAnnotation])
+				require.Equal(t, "2024-01-02T03:04:05Z", result.Annotations[ngModels.AcknowledgedUntilAnnotation])
+
+				alertState.Acknowledgement = nil
+				result = StateToPostableAlert(alertState, appURL, featuremgmt.WithFeatures())
+				require.NotContains(t, result.Annotations, ngModels.AcknowledgedByAnnotation)
+			})
+
 			switch tc.state {
 			case eval.NoData:
 				t.Run("should keep existing labels and change name", func(t *testing.T) {
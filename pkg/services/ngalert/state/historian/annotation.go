@@ -111,6 +111,9 @@ func (h *AnnotationBackend) Record(ctx context.Context, rule history_model.RuleM
 // Query filters state history annotations and formats them into a dataframe.
 func (h *AnnotationBackend) Query(ctx context.Context, query ngmodels.HistoryQuery) (*data.Frame, error) {
 	logger := h.log.FromContext(ctx)
+	if query.Aggregation != "" {
+		return nil, ngmodels.ErrHistoryAggregationNotSupported.Errorf("")
+	}
 	if query.RuleUID == "" {
 		return nil, fmt.Errorf("ruleUID is required to query annotations")
 	}
@@ -205,7 +208,7 @@ func buildAnnotations(rule history_model.RuleMeta, states []state.StateTransitio
 		}
 		logger.Debug("Alert state changed creating annotation", "newState", state.Formatted(), "oldState", state.PreviousFormatted())
 
-		annotationText, annotationData := BuildAnnotationTextAndData(rule, state.State)
+		annotationText, annotationData := BuildAnnotationTextAndData(rule, state)
 
 		item := annotations.Item{
 			AlertID:   rule.ID,
@@ -214,7 +217,7 @@ func buildAnnotations(rule history_model.RuleMeta, states []state.StateTransitio
 			NewState:  state.Formatted(),
 			Text:      annotationText,
 			Data:      annotationData,
-			Epoch:     state.LastEvaluationTime.UnixNano() / int64(time.Millisecond),
+			Epoch:     recordTime(state).UnixNano() / int64(time.Millisecond),
 		}
 
 		items = append(items, item)
@@ -222,7 +225,8 @@ func buildAnnotations(rule history_model.RuleMeta, states []state.StateTransitio
 	return items
 }
 
-func BuildAnnotationTextAndData(rule history_model.RuleMeta, currentState *state.State) (string, *simplejson.Json) {
+func BuildAnnotationTextAndData(rule history_model.RuleMeta, transition state.StateTransition) (string, *simplejson.Json) {
+	currentState := transition.State
 	jsonData := simplejson.New()
 	var value string
 
@@ -252,6 +256,16 @@ func BuildAnnotationTextAndData(rule history_model.RuleMeta, currentState *state
 		value = strings.Join(values, ", ")
 	}
 
+	if transition.AcknowledgementChange != nil {
+		ack := NewAcknowledgementRecord(transition)
+		jsonData.Set("acknowledgement", ack)
+		if ack.Acknowledged {
+			value = fmt.Sprintf("%s - acknowledged by %s", value, ack.By)
+		} else {
+			value = fmt.Sprintf("%s - acknowledgement removed", value)
+		}
+	}
+
 	labels := removePrivateLabels(currentState.Labels)
 	return fmt.Sprintf("%s {%s} - %s", rule.Title, labels.String(), value), jsonData
 }
@@ -250,6 +250,25 @@ func TestBuildAnnotations(t *testing.T) {
 		j := assertValidJSON(t, items[0].Data)
 		require.JSONEq(t, `{"values": {"nan": "NaN", "inf": "+Inf", "ninf": "-Inf"}}`, j)
 	})
+
+	t.Run("acknowledgement changes are annotated at the time of the change", func(t *testing.T) {
+		logger := log.NewNopLogger()
+		rule := history_model.RuleMeta{Title: "test"}
+		ackTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		states := []state.StateTransition{makeStateTransition()}
+		states[0].PreviousState = eval.Alerting
+		states[0].Values = nil
+		states[0].Acknowledgement = &models.AlertInstanceAcknowledgement{By: "admin", At: ackTime, Comment: "on it"}
+		states[0].AcknowledgementChange = &state.AcknowledgementChange{Time: ackTime}
+
+		items := buildAnnotations(rule, states, logger)
+
+		require.Len(t, items, 1)
+		require.Equal(t, ackTime.UnixMilli(), items[0].Epoch)
+		require.Contains(t, items[0].Text, "acknowledged by admin")
+		j := assertValidJSON(t, items[0].Data)
+		require.JSONEq(t, `{"values": null, "acknowledgement": {"acknowledged": true, "by": "admin", "comment": "on it"}}`, j)
+	})
 }
 
 func makeStateTransition() state.StateTransition {
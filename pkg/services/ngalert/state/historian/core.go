@@ -18,6 +18,10 @@ import (
 const StateHistoryWriteTimeout = time.Minute
 
 func shouldRecord(transition state.StateTransition) bool {
+	// Acknowledgements are recorded even if the state did not change.
+	if transition.AcknowledgementChange != nil {
+		return true
+	}
 	if !transition.Changed() {
 		return false
 	}
@@ -58,6 +62,15 @@ func ShouldRecordAnnotation(t state.StateTransition) bool {
 	return true
 }
 
+// recordTime returns the time of the history record of the transition. It is the time of the acknowledgement change
+// if the state itself did not change, otherwise the time of the evaluation.
+func recordTime(t state.StateTransition) time.Time {
+	if t.AcknowledgementChange != nil && !t.Changed() {
+		return t.AcknowledgementChange.Time
+	}
+	return t.LastEvaluationTime
+}
+
 func removePrivateLabels(labels data.Labels) data.Labels {
 	result := make(data.Labels)
 	for k, v := range labels {
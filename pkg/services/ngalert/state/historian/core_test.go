@@ -95,6 +95,15 @@ func TestShouldRecord(t *testing.T) {
 			require.Equal(t, !ok, shouldRecord(trans))
 		})
 	}
+
+	t.Run("acknowledgement change without transition should be recorded", func(t *testing.T) {
+		trans := state.StateTransition{
+			State:                 &state.State{State: eval.Alerting},
+			PreviousState:         eval.Alerting,
+			AcknowledgementChange: &state.AcknowledgementChange{},
+		}
+		require.True(t, shouldRecord(trans))
+	})
 }
 
 func TestShouldRecordAnnotation(t *testing.T) {
@@ -141,6 +141,9 @@ func (h *RemoteLokiBackend) Record(ctx context.Context, rule history_model.RuleM
 
 // Query retrieves state history entries from an external Loki instance and formats the results into a dataframe.
 func (h *RemoteLokiBackend) Query(ctx context.Context, query models.HistoryQuery) (*data.Frame, error) {
+	if query.Aggregation != "" {
+		return nil, models.ErrHistoryAggregationNotSupported.Errorf("")
+	}
 	uids, err := h.getFolderUIDsForFilter(ctx, query)
 	if err != nil {
 		return nil, err
@@ -283,25 +286,7 @@ func StatesToStream(rule history_model.RuleMeta, states []state.StateTransition,
 			continue
 		}
 
-		sanitizedLabels := removePrivateLabels(state.Labels)
-		entry := LokiEntry{
-			SchemaVersion:  1,
-			Previous:       state.PreviousFormatted(),
-			Current:        state.Formatted(),
-			Values:         valuesAsDataBlob(state.State),
-			Condition:      rule.Condition,
-			DashboardUID:   rule.DashboardUID,
-			PanelID:        rule.PanelID,
-			Fingerprint:    labelFingerprint(sanitizedLabels),
-			RuleTitle:      rule.Title,
-			RuleID:         rule.ID,
-			RuleUID:        rule.UID,
-			InstanceLabels: sanitizedLabels,
-		}
-		if state.State.State == eval.Error {
-			entry.Error = state.Error.Error()
-		}
-
+		entry := newLokiEntry(rule, state)
 		jsn, err := json.Marshal(entry)
 		if err != nil {
 			logger.Error("Failed to construct history record for state, skipping", "error", err)
@@ -310,7 +295,7 @@ func StatesToStream(rule history_model.RuleMeta, states []state.StateTransition,
 		line := string(jsn)
 
 		samples = append(samples, Sample{
-			T: state.LastEvaluationTime,
+			T: recordTime(state),
 			V: line,
 		})
 	}
@@ -321,6 +306,33 @@ func StatesToStream(rule history_model.RuleMeta, states []state.StateTransition,
 	}
 }
 
+// newLokiEntry returns the history record of the state transition.
+func newLokiEntry(rule history_model.RuleMeta, state state.StateTransition) LokiEntry {
+	sanitizedLabels := removePrivateLabels(state.Labels)
+	entry := LokiEntry{
+		SchemaVersion:  1,
+		Previous:       state.PreviousFormatted(),
+		Current:        state.Formatted(),
+		Values:         valuesAsDataBlob(state.State),
+		Condition:      rule.Condition,
+		DashboardUID:   rule.DashboardUID,
+		PanelID:        rule.PanelID,
+		Fingerprint:    labelFingerprint(sanitizedLabels),
+		RuleTitle:      rule.Title,
+		RuleID:         rule.ID,
+		RuleUID:        rule.UID,
+		InstanceLabels: sanitizedLabels,
+	}
+	// The error can be missing if the transition is an acknowledgement of a state restored from the database.
+	if state.State.State == eval.Error && state.Error != nil {
+		entry.Error = state.Error.Error()
+	}
+	if state.AcknowledgementChange != nil {
+		entry.Acknowledgement = NewAcknowledgementRecord(state)
+	}
+	return entry
+}
+
 func (h *RemoteLokiBackend) recordStreams(ctx context.Context, stream Stream, logger log.Logger) error {
 	if err := h.client.Push(ctx, []Stream{stream}); err != nil {
 		return err
@@ -346,6 +358,34 @@ type LokiEntry struct {
 	// InstanceLabels is exactly the set of labels associated with the alert instance in Alertmanager.
 	// These should not be conflated with labels associated with log streams.
 	InstanceLabels map[string]string `json:"labels"`
+	// Acknowledgement is set if the acknowledgement of the alert instance changed.
+	Acknowledgement *AcknowledgementRecord `json:"acknowledgement,omitempty"`
+}
+
+// AcknowledgementRecord describes a change of the acknowledgement of an alert instance in the state history.
+type AcknowledgementRecord struct {
+	// Acknowledged is false if the acknowledgement was removed or expired.
+	Acknowledged bool `json:"acknowledged"`
+	// By, Comment and ExpiresAt describe the new acknowledgement, or the removed one if Acknowledged is false.
+	By        string     `json:"by,omitempty"`
+	Comment   string     `json:"comment,omitempty"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// NewAcknowledgementRecord returns the record of the acknowledgement change of the transition.
+// The transition must have an acknowledgement change.
+func NewAcknowledgementRecord(t state.StateTransition) *AcknowledgementRecord {
+	ack := t.State.Acknowledgement
+	record := &AcknowledgementRecord{Acknowledged: ack != nil}
+	if ack == nil {
+		ack = t.AcknowledgementChange.Previous
+	}
+	if ack != nil {
+		record.By = ack.By
+		record.Comment = ack.Comment
+		record.ExpiresAt = ack.ExpiresAt
+	}
+	return record
 }
 
 func valuesAsDataBlob(state *state.State) *simplejson.Json {
@@ -493,7 +533,13 @@ func queryHasLogFilters(query models.HistoryQuery) bool {
 }
 
 func (h *RemoteLokiBackend) getFolderUIDsForFilter(ctx context.Context, query models.HistoryQuery) ([]string, error) {
-	bypass, err := h.ac.CanReadAllRules(ctx, query.SignedInUser)
+	return getFolderUIDsForFilter(ctx, h.ac, h.ruleStore, query)
+}
+
+// getFolderUIDsForFilter returns the UIDs of the folders whose state history the user can read.
+// It returns no UIDs if the user can read the history of all folders, or if the query filters by a rule the user can read.
+func getFolderUIDsForFilter(ctx context.Context, ac AccessControl, ruleStore RuleStore, query models.HistoryQuery) ([]string, error) {
+	bypass, err := ac.CanReadAllRules(ctx, query.SignedInUser)
 	if err != nil {
 		return nil, err
 	}
@@ -502,7 +548,7 @@ func (h *RemoteLokiBackend) getFolderUIDsForFilter(ctx context.Context, query mo
 	}
 	// if there is a filter by rule UID, find that rule UID and make sure that user has access to it.
 	if query.RuleUID != "" {
-		rule, err := h.ruleStore.GetAlertRuleByUID(ctx, &models.GetAlertRuleByUIDQuery{
+		rule, err := ruleStore.GetAlertRuleByUID(ctx, &models.GetAlertRuleByUIDQuery{
 			UID:   query.RuleUID,
 			OrgID: query.OrgID,
 		})
@@ -512,17 +558,17 @@ func (h *RemoteLokiBackend) getFolderUIDsForFilter(ctx context.Context, query mo
 		if rule == nil {
 			return nil, models.ErrAlertRuleNotFound
 		}
-		return nil, h.ac.AuthorizeAccessInFolder(ctx, query.SignedInUser, rule)
+		return nil, ac.AuthorizeAccessInFolder(ctx, query.SignedInUser, rule)
 	}
 	// if no filter, then we need to get all namespaces user has access to
-	folders, err := h.ruleStore.GetUserVisibleNamespaces(ctx, query.OrgID, query.SignedInUser)
+	folders, err := ruleStore.GetUserVisibleNamespaces(ctx, query.OrgID, query.SignedInUser)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch folders that user can access: %w", err)
 	}
 	uids := make([]string, 0, len(folders))
 	// now keep only UIDs of folder in which user can read rules.
 	for _, f := range folders {
-		hasAccess, err := h.ac.HasAccessInFolder(ctx, query.SignedInUser, models.Namespace(*f.ToFolderReference()))
+		hasAccess, err := ac.HasAccessInFolder(ctx, query.SignedInUser, models.Namespace(*f.ToFolderReference()))
 		if err != nil {
 			return nil, err
 		}
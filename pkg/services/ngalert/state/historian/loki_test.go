@@ -201,6 +201,38 @@ func TestRemoteLokiBackend(t *testing.T) {
 			exp := labelFingerprint(states[0].Labels)
 			require.Equal(t, exp, entry.Fingerprint)
 		})
+
+		t.Run("records acknowledgement changes", func(t *testing.T) {
+			rule := createTestRule()
+			l := log.NewNopLogger()
+			ackTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+			expiresAt := ackTime.Add(time.Hour)
+			ack := &models.AlertInstanceAcknowledgement{By: "admin", At: ackTime, Comment: "on it", ExpiresAt: &expiresAt}
+			states := []state.StateTransition{
+				{
+					PreviousState:         eval.Alerting,
+					State:                 &state.State{State: eval.Alerting, Acknowledgement: ack, LastEvaluationTime: ackTime.Add(-time.Minute)},
+					AcknowledgementChange: &state.AcknowledgementChange{Time: ackTime},
+				},
+				{
+					PreviousState:         eval.Alerting,
+					State:                 &state.State{State: eval.Alerting},
+					AcknowledgementChange: &state.AcknowledgementChange{Time: expiresAt, Previous: ack},
+				},
+			}
+
+			res := StatesToStream(rule, states, nil, l)
+
+			require.Len(t, res.Values, 2)
+			require.Equal(t, ackTime, res.Values[0].T)
+			entry := requireEntry(t, res.Values[0])
+			require.Equal(t, &AcknowledgementRecord{Acknowledged: true, By: "admin", Comment: "on it", ExpiresAt: &expiresAt}, entry.Acknowledgement)
+
+			require.Equal(t, expiresAt, res.Values[1].T)
+			entry = requireEntry(t, res.Values[1])
+			require.False(t, entry.Acknowledgement.Acknowledged)
+			require.Equal(t, "admin", entry.Acknowledgement.By)
+		})
 	})
 }
 
@@ -0,0 +1,792 @@
+package historian
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	history_model "github.com/grafana/grafana/pkg/services/ngalert/state/historian/model"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	// sqlCleanupInterval is how often the state history that is older than the retention is deleted.
+	sqlCleanupInterval = time.Hour
+	// sqlCleanupBatchSize is the maximum number of records that are deleted at once. It is below the limit of query parameters of SQLite.
+	sqlCleanupBatchSize = 900
+	// sqlDefaultQueryLimit is the maximum number of records returned by a query that does not define a limit.
+	sqlDefaultQueryLimit = 1000
+	// sqlDefaultFlappingLimit is the number of rules returned by a flapping aggregation that does not define a limit.
+	sqlDefaultFlappingLimit = 10
+	// sqlMaxQueryRecords is the maximum number of records that a query reads. Queries that return records return the
+	// most recent ones read, and aggregations that need more records fail.
+	sqlMaxQueryRecords = 100000
+	// sqlQueryPageSize is the number of records that are read at once when the records are filtered by labels.
+	sqlQueryPageSize = 1000
+	// sqlFolderBatchSize is the maximum number of folders that are filtered by one query. It is below the limit of query
+	// parameters of SQLite together with the other conditions of the query.
+	sqlFolderBatchSize = 900
+)
+
+var ErrStateHistoryQueryTooLarge = errutil.BadRequest("alerting.stateHistoryQueryTooLarge").MustTemplate(
+	"State history aggregation exceeded the maximum of {{.Public.MaxRecords}} records",
+	errutil.WithPublic("The state history aggregation exceeds the maximum of {{.Public.MaxRecords}} records. Narrow the time range or add filters and try again."),
+)
+
+type SQLConfig struct {
+	// BatchSize is the number of buffered state transitions that triggers a write before the flush interval.
+	BatchSize int
+	// FlushInterval is how often the buffered state transitions are written.
+	FlushInterval time.Duration
+	// Retention is how long the state history is kept. 0 keeps it forever.
+	Retention time.Duration
+}
+
+func NewSQLConfig(cfg setting.UnifiedAlertingStateHistorySettings) SQLConfig {
+	return SQLConfig{
+		BatchSize:     cfg.SQLBatchSize,
+		FlushInterval: cfg.SQLFlushInterval,
+		Retention:     cfg.SQLRetention,
+	}
+}
+
+// stateHistoryEntry represents a record in alert_state_history table.
+type stateHistoryEntry struct {
+	ID            int64  `xorm:"pk autoincr 'id'"`
+	OrgID         int64  `xorm:"org_id"`
+	RuleUID       string `xorm:"rule_uid"`
+	RuleTitle     string
+	NamespaceUID  string `xorm:"namespace_uid"`
+	RuleGroup     string
+	DashboardUID  string `xorm:"dashboard_uid"`
+	PanelID       int64  `xorm:"panel_id"`
+	Fingerprint   string
+	PreviousState string
+	CurrentState  string
+	// Labels are the labels of the alert instance, encoded as JSON.
+	Labels string
+	// Line is the history record of the state transition, in the same format as the lines written to Loki.
+	Line string
+	// Epoch is the time of the state transition, in milliseconds.
+	Epoch int64
+}
+
+func (e stateHistoryEntry) TableName() string {
+	return "alert_state_history"
+}
+
+// SQLBackend is a state.Historian that records state history to a table of the Grafana database.
+// State transitions are buffered and written in batches, either when the flush interval elapses or when the batch is full.
+type SQLBackend struct {
+	db        db.DB
+	cfg       SQLConfig
+	clock     clock.Clock
+	metrics   *metrics.Historian
+	log       log.Logger
+	ac        AccessControl
+	ruleStore RuleStore
+
+	mtx sync.Mutex
+	// pending are the state transitions that are not written yet, and waiting are the channels
+	// of the Record calls that they come from.
+	pending []stateHistoryEntry
+	waiting []chan<- error
+	// full is signalled when the number of pending state transitions reaches the batch size.
+	full chan struct{}
+}
+
+func NewSQLBackend(logger log.Logger, cfg SQLConfig, store db.DB, metrics *metrics.Historian, ruleStore RuleStore, ac AccessControl) *SQLBackend {
+	return &SQLBackend{
+		db:        store,
+		cfg:       cfg,
+		clock:     clock.New(),
+		metrics:   metrics,
+		log:       logger,
+		ac:        ac,
+		ruleStore: ruleStore,
+		full:      make(chan struct{}, 1),
+	}
+}
+
+// Run writes the buffered state transitions at every flush interval and deletes the expired state history
+// until the context is cancelled. The remaining state transitions are written before it returns.
+func (h *SQLBackend) Run(ctx context.Context) error {
+	h.log.Info("Starting SQL state history backend", "flushInterval", h.cfg.FlushInterval, "batchSize", h.cfg.BatchSize, "retention", h.cfg.Retention)
+	flushTicker := h.clock.Ticker(h.cfg.FlushInterval)
+	defer flushTicker.Stop()
+
+	var cleanup <-chan time.Time
+	if h.cfg.Retention > 0 {
+		cleanupTicker := h.clock.Ticker(sqlCleanupInterval)
+		defer cleanupTicker.Stop()
+		cleanup = cleanupTicker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			h.flush()
+			h.log.Info("Stopped SQL state history backend")
+			return nil
+		case <-flushTicker.C:
+			h.flush()
+		case <-h.full:
+			h.flush()
+		case <-cleanup:
+			deleted, err := h.deleteExpired(ctx)
+			if err != nil {
+				h.log.Error("Failed to delete expired state history", "deleted", deleted, "error", err)
+				continue
+			}
+			h.log.Debug("Deleted expired state history", "deleted", deleted)
+		}
+	}
+}
+
+// Record buffers a number of state transitions for a given rule. The returned channel is closed when they are written.
+func (h *SQLBackend) Record(ctx context.Context, rule history_model.RuleMeta, states []state.StateTransition) <-chan error {
+	logger := h.log.FromContext(ctx)
+	entries := statesToSQLEntries(rule, states, logger)
+
+	errCh := make(chan error, 1)
+	if len(entries) == 0 {
+		close(errCh)
+		return errCh
+	}
+
+	h.mtx.Lock()
+	h.pending = append(h.pending, entries...)
+	h.waiting = append(h.waiting, errCh)
+	full := len(h.pending) >= h.cfg.BatchSize
+	h.mtx.Unlock()
+
+	if full {
+		select {
+		case h.full <- struct{}{}:
+		default:
+		}
+	}
+	return errCh
+}
+
+// flush writes the buffered state transitions.
+func (h *SQLBackend) flush() {
+	h.mtx.Lock()
+	entries, waiting := h.pending, h.waiting
+	h.pending, h.waiting = nil, nil
+	h.mtx.Unlock()
+
+	if len(entries) == 0 {
+		return
+	}
+
+	// This is a new background job, so let's create a brand new context for it.
+	// We want it to be isolated, i.e. we don't want grafana shutdowns to interrupt this work
+	// immediately but rather try to flush writes.
+	ctx, cancel := context.WithTimeout(context.Background(), StateHistoryWriteTimeout)
+	defer cancel()
+
+	perOrg := make(map[int64]int)
+	for _, e := range entries {
+		perOrg[e.OrgID]++
+	}
+	for orgID, count := range perOrg {
+		org := fmt.Sprint(orgID)
+		h.metrics.WritesTotal.WithLabelValues(org, "sql").Inc()
+		h.metrics.TransitionsTotal.WithLabelValues(org).Add(float64(count))
+	}
+
+	h.log.Debug("Saving state history batch", "samples", len(entries))
+	err := h.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.BulkInsert(stateHistoryEntry{}.TableName(), entries, sqlstore.NativeSettingsForDialect(h.db.GetDialect()))
+		return err
+	})
+	if err != nil {
+		h.log.Error("Failed to save alert state history batch", "samples", len(entries), "error", err)
+		for orgID, count := range perOrg {
+			org := fmt.Sprint(orgID)
+			h.metrics.WritesFailed.WithLabelValues(org, "sql").Inc()
+			h.metrics.TransitionsFailed.WithLabelValues(org).Add(float64(count))
+		}
+		err = fmt.Errorf("failed to save alert state history batch: %w", err)
+	} else {
+		h.log.Debug("Done saving alert state history batch", "samples", len(entries))
+	}
+
+	for _, ch := range waiting {
+		if err != nil {
+			ch <- err
+		}
+		close(ch)
+	}
+}
+
+// deleteExpired deletes the state history that is older than the retention, in batches.
+// It returns the number of deleted records.
+func (h *SQLBackend) deleteExpired(ctx context.Context) (int64, error) {
+	cutoff := h.clock.Now().Add(-h.cfg.Retention).UnixMilli()
+	var total int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+		// Load the IDs first, because deleting with a limit is not supported by all databases.
+		var ids []int64
+		err := h.db.WithDbSession(ctx, func(sess *db.Session) error {
+			return sess.SQL(
+				fmt.Sprintf("SELECT id FROM %s WHERE epoch < ? ORDER BY id %s", stateHistoryEntry{}.TableName(), h.db.GetDialect().Limit(sqlCleanupBatchSize)),
+				cutoff,
+			).Find(&ids)
+		})
+		if err != nil {
+			return total, err
+		}
+		if len(ids) == 0 {
+			return total, nil
+		}
+
+		var deleted int64
+		err = h.db.WithDbSession(ctx, func(sess *db.Session) error {
+			var err error
+			deleted, err = sess.In("id", ids).Delete(&stateHistoryEntry{})
+			return err
+		})
+		total += deleted
+		if err != nil {
+			return total, err
+		}
+		if len(ids) < sqlCleanupBatchSize {
+			return total, nil
+		}
+	}
+}
+
+// Query retrieves state history from the database and formats it into a dataframe.
+// If the query has an aggregation, the dataframe contains the aggregation instead of the history records.
+func (h *SQLBackend) Query(ctx context.Context, query models.HistoryQuery) (*data.Frame, error) {
+	uids, err := getFolderUIDsForFilter(ctx, h.ac, h.ruleStore, query)
+	if err != nil {
+		return nil, err
+	}
+
+	now := h.clock.Now().UTC()
+	if query.To.IsZero() || query.To.After(now) {
+		query.To = now
+	}
+	if query.From.IsZero() {
+		query.From = query.To.Add(-defaultQueryRange)
+	}
+
+	switch query.Aggregation {
+	case models.HistoryAggregationFiringDuration:
+		entries, err := h.findForAggregation(ctx, query, uids)
+		if err != nil {
+			return nil, err
+		}
+		previous, err := h.findLastBefore(ctx, query, uids)
+		if err != nil {
+			return nil, err
+		}
+		return firingDurationFrame(previous, entries, query.From, query.To), nil
+	case models.HistoryAggregationFlapping:
+		entries, err := h.findForAggregation(ctx, query, uids)
+		if err != nil {
+			return nil, err
+		}
+		limit := query.Limit
+		if limit <= 0 {
+			limit = sqlDefaultFlappingLimit
+		}
+		return flappingFrame(entries, limit), nil
+	default:
+		limit := query.Limit
+		if limit <= 0 {
+			limit = sqlDefaultQueryLimit
+		}
+		entries, truncated, err := h.find(ctx, query, uids, min(limit, sqlMaxQueryRecords), false)
+		if err != nil {
+			return nil, err
+		}
+		if truncated {
+			h.log.FromContext(ctx).Warn("State history query read the maximum number of records, the result might be incomplete", "maxRecords", sqlMaxQueryRecords)
+		}
+		return entriesToFrame(entries)
+	}
+}
+
+// findForAggregation returns all the state history records that match the query, ordered by time, without the history
+// records themselves. It fails if there are more records than can be read by a query.
+func (h *SQLBackend) findForAggregation(ctx context.Context, query models.HistoryQuery, folderUIDs []string) ([]stateHistoryEntry, error) {
+	entries, truncated, err := h.find(ctx, query, folderUIDs, 0, true)
+	if err != nil {
+		return nil, err
+	}
+	if truncated {
+		return nil, ErrStateHistoryQueryTooLarge.Build(errutil.TemplateData{
+			Public: map[string]any{"MaxRecords": sqlMaxQueryRecords},
+		})
+	}
+	return entries, nil
+}
+
+// findLastBefore returns the last state history record of each alert instance that matches the query before the start
+// of the range, without the history records themselves. It fails if there are more alert instances than records that
+// can be read by a query.
+func (h *SQLBackend) findLastBefore(ctx context.Context, query models.HistoryQuery, folderUIDs []string) ([]stateHistoryEntry, error) {
+	batches := [][]string{nil}
+	if len(folderUIDs) > 0 {
+		batches = slices.Collect(slices.Chunk(folderUIDs, sqlFolderBatchSize))
+	}
+
+	type key struct {
+		ruleUID     string
+		fingerprint string
+	}
+	last := make(map[key]stateHistoryEntry)
+	for _, batch := range batches {
+		cond := "org_id = ? AND epoch < ?"
+		args := []any{query.OrgID, query.From.UnixMilli()}
+		if len(batch) > 0 {
+			cond += " AND namespace_uid IN (?" + strings.Repeat(", ?", len(batch)-1) + ")"
+			for _, uid := range batch {
+				args = append(args, uid)
+			}
+		}
+		if query.RuleUID != "" {
+			cond += " AND rule_uid = ?"
+			args = append(args, query.RuleUID)
+		}
+		if query.DashboardUID != "" {
+			cond += " AND dashboard_uid = ?"
+			args = append(args, query.DashboardUID)
+		}
+		if query.PanelID != 0 {
+			cond += " AND panel_id = ?"
+			args = append(args, query.PanelID)
+		}
+		args = append(args, query.OrgID)
+
+		var page []stateHistoryEntry
+		err := h.db.WithDbSession(ctx, func(sess *db.Session) error {
+			return sess.SQL(fmt.Sprintf(
+				"SELECT h.id, h.org_id, h.rule_uid, h.rule_title, h.namespace_uid, h.fingerprint, h.previous_state, h.current_state, h.labels, h.epoch "+
+					"FROM %[1]s h INNER JOIN (SELECT rule_uid, fingerprint, MAX(epoch) AS epoch FROM %[1]s WHERE %[2]s GROUP BY rule_uid, fingerprint) m "+
+					"ON h.rule_uid = m.rule_uid AND h.fingerprint = m.fingerprint AND h.epoch = m.epoch WHERE h.org_id = ?",
+				stateHistoryEntry{}.TableName(), cond,
+			), args...).Find(&page)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to query state history: %w", err)
+		}
+		for _, e := range page {
+			if !h.matchLabels(e, query.Labels) {
+				continue
+			}
+			// Several records of an alert instance can have the same time, the last one written is kept.
+			k := key{ruleUID: e.RuleUID, fingerprint: e.Fingerprint}
+			if prev, ok := last[k]; !ok || prev.ID < e.ID {
+				last[k] = e
+			}
+		}
+		if len(last) > sqlMaxQueryRecords {
+			return nil, ErrStateHistoryQueryTooLarge.Build(errutil.TemplateData{
+				Public: map[string]any{"MaxRecords": sqlMaxQueryRecords},
+			})
+		}
+	}
+	return sortEntries(slices.Collect(maps.Values(last)), 0), nil
+}
+
+// find returns the state history records that match the query, ordered by time. If limit is greater than 0, only
+// the most recent records are returned. If forAggregation is true, the history records themselves are not loaded.
+// At most sqlMaxQueryRecords records are read, and truncated is true if there might be more records that match.
+func (h *SQLBackend) find(ctx context.Context, query models.HistoryQuery, folderUIDs []string, limit int, forAggregation bool) ([]stateHistoryEntry, bool, error) {
+	// The list of folders can exceed the limit of query parameters, so the folders are filtered in batches.
+	batches := [][]string{nil}
+	if len(folderUIDs) > 0 {
+		batches = slices.Collect(slices.Chunk(folderUIDs, sqlFolderBatchSize))
+	}
+
+	var entries []stateHistoryEntry
+	read := 0
+	for _, batch := range batches {
+		found, n, truncated, err := h.findInFolders(ctx, query, batch, limit, forAggregation, sqlMaxQueryRecords-read)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to query state history: %w", err)
+		}
+		entries = append(entries, found...)
+		read += n
+		if truncated {
+			return sortEntries(entries, limit), true, nil
+		}
+	}
+	return sortEntries(entries, limit), false, nil
+}
+
+// findInFolders returns the most recent state history records that match the query in the folders, or in all folders
+// if folderUIDs is empty. Labels are stored as JSON, which cannot be filtered in the same way by all databases, so the
+// records are read page by page and filtered here until limit records match, all records are read, or maxRecords
+// records are read. It returns the records that match, the number of records read, and whether there might be more.
+func (h *SQLBackend) findInFolders(ctx context.Context, query models.HistoryQuery, folderUIDs []string, limit int, forAggregation bool, maxRecords int) ([]stateHistoryEntry, int, bool, error) {
+	var result []stateHistoryEntry
+	var last *stateHistoryEntry
+	read := 0
+	for {
+		pageSize := sqlQueryPageSize
+		if limit > 0 && len(query.Labels) == 0 {
+			pageSize = limit - len(result)
+		}
+		// Read one more record than the remaining ones to know whether there are more.
+		if remaining := maxRecords - read; pageSize > remaining {
+			pageSize = remaining + 1
+		}
+
+		var page []stateHistoryEntry
+		err := h.db.WithDbSession(ctx, func(sess *db.Session) error {
+			q := sess.Table(stateHistoryEntry{}).
+				Where("org_id = ?", query.OrgID).
+				And("epoch >= ?", query.From.UnixMilli()).
+				And("epoch <= ?", query.To.UnixMilli())
+			if len(folderUIDs) > 0 {
+				q = q.In("namespace_uid", folderUIDs)
+			}
+			if query.RuleUID != "" {
+				q = q.And("rule_uid = ?", query.RuleUID)
+			}
+			if query.DashboardUID != "" {
+				q = q.And("dashboard_uid = ?", query.DashboardUID)
+			}
+			if query.PanelID != 0 {
+				q = q.And("panel_id = ?", query.PanelID)
+			}
+			// Continue after the last record of the previous page.
+			if last != nil {
+				q = q.And("(epoch < ? OR (epoch = ? AND id < ?))", last.Epoch, last.Epoch, last.ID)
+			}
+			if forAggregation {
+				q = q.Cols("id", "org_id", "rule_uid", "rule_title", "namespace_uid", "fingerprint", "previous_state", "current_state", "labels", "epoch")
+			}
+			return q.Desc("epoch", "id").Limit(pageSize).Find(&page)
+		})
+		if err != nil {
+			return nil, read, false, err
+		}
+		read += len(page)
+		truncated := read > maxRecords
+		if truncated {
+			page = page[:len(page)-(read-maxRecords)]
+			read = maxRecords
+		}
+
+		for _, e := range page {
+			if h.matchLabels(e, query.Labels) {
+				result = append(result, e)
+			}
+		}
+		if limit > 0 && len(result) >= limit {
+			return result[:limit], read, false, nil
+		}
+		if truncated {
+			return result, read, true, nil
+		}
+		if len(page) < pageSize {
+			return result, read, false, nil
+		}
+		last = &page[len(page)-1]
+	}
+}
+
+// sortEntries orders the records by time and keeps the most recent limit records if limit is greater than 0.
+func sortEntries(entries []stateHistoryEntry, limit int) []stateHistoryEntry {
+	slices.SortFunc(entries, func(a, b stateHistoryEntry) int {
+		if a.Epoch != b.Epoch {
+			return cmp.Compare(a.Epoch, b.Epoch)
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+	if limit > 0 && len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+	return entries
+}
+
+func (h *SQLBackend) matchLabels(e stateHistoryEntry, matchers map[string]string) bool {
+	if len(matchers) == 0 {
+		return true
+	}
+	var lbls map[string]string
+	if err := json.Unmarshal([]byte(e.Labels), &lbls); err != nil {
+		h.log.Warn("Failed to parse labels of state history record, skipping", "id", e.ID, "error", err)
+		return false
+	}
+	for k, v := range matchers {
+		if lbls[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func statesToSQLEntries(rule history_model.RuleMeta, states []state.StateTransition, logger log.Logger) []stateHistoryEntry {
+	entries := make([]stateHistoryEntry, 0, len(states))
+	for _, state := range states {
+		if !shouldRecord(state) {
+			continue
+		}
+
+		entry := newLokiEntry(rule, state)
+		line, err := json.Marshal(entry)
+		if err != nil {
+			logger.Error("Failed to construct history record for state, skipping", "error", err)
+			continue
+		}
+		lbls, err := json.Marshal(entry.InstanceLabels)
+		if err != nil {
+			logger.Error("Failed to serialize labels of state, skipping", "error", err)
+			continue
+		}
+
+		entries = append(entries, stateHistoryEntry{
+			OrgID:         rule.OrgID,
+			RuleUID:       rule.UID,
+			RuleTitle:     rule.Title,
+			NamespaceUID:  rule.NamespaceUID,
+			RuleGroup:     rule.Group,
+			DashboardUID:  rule.DashboardUID,
+			PanelID:       rule.PanelID,
+			Fingerprint:   entry.Fingerprint,
+			PreviousState: state.PreviousState.String(),
+			CurrentState:  state.State.State.String(),
+			Labels:        string(lbls),
+			Line:          string(line),
+			Epoch:         recordTime(state).UnixMilli(),
+		})
+	}
+	return entries
+}
+
+// entriesToFrame formats the state history records into a dataframe with the same structure as the one returned by the Loki backend.
+func entriesToFrame(entries []stateHistoryEntry) (*data.Frame, error) {
+	frame := data.NewFrame("states")
+	lbls := data.Labels(map[string]string{})
+
+	times := make([]time.Time, 0, len(entries))
+	lines := make([]json.RawMessage, 0, len(entries))
+	labels := make([]json.RawMessage, 0, len(entries))
+	for _, e := range entries {
+		streamLbls, err := json.Marshal(map[string]string{
+			StateHistoryLabelKey: StateHistoryLabelValue,
+			OrgIDLabel:           fmt.Sprint(e.OrgID),
+			GroupLabel:           e.RuleGroup,
+			FolderUIDLabel:       e.NamespaceUID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize stream labels: %w", err)
+		}
+		times = append(times, time.UnixMilli(e.Epoch))
+		lines = append(lines, json.RawMessage(e.Line))
+		labels = append(labels, streamLbls)
+	}
+
+	frame.Fields = append(frame.Fields, data.NewField(dfTime, lbls, times))
+	frame.Fields = append(frame.Fields, data.NewField(dfLine, lbls, lines))
+	frame.Fields = append(frame.Fields, data.NewField(dfLabels, lbls, labels))
+	return frame, nil
+}
+
+func isFiringState(s string) bool {
+	return s == eval.Alerting.String() || s == eval.Recovering.String()
+}
+
+// firingDurationFrame aggregates the time that the alert instances of each rule spent firing between from and to, per day in UTC.
+// previous are the last records of the alert instances before from. The state of an alert instance at from is the
+// current state of its last record before from, or the previous state of its first record in the range if there is none.
+func firingDurationFrame(previous, entries []stateHistoryEntry, from, to time.Time) *data.Frame {
+	type key struct {
+		day     time.Time
+		ruleUID string
+	}
+	type instanceKey struct {
+		ruleUID     string
+		fingerprint string
+	}
+	durations := make(map[key]time.Duration)
+	titles := make(map[string]string)
+	addFiring := func(ruleUID string, since, until time.Time) {
+		for since.Before(until) {
+			day := since.Truncate(24 * time.Hour)
+			end := day.Add(24 * time.Hour)
+			if end.After(until) {
+				end = until
+			}
+			durations[key{day: day, ruleUID: ruleUID}] += end.Sub(since)
+			since = end
+		}
+	}
+
+	before := make(map[instanceKey]stateHistoryEntry, len(previous))
+	for _, e := range previous {
+		before[instanceKey{ruleUID: e.RuleUID, fingerprint: e.Fingerprint}] = e
+	}
+
+	for _, instance := range groupByInstance(entries) {
+		first := instance[0]
+		firing, since := isFiringState(first.PreviousState), from
+		k := instanceKey{ruleUID: first.RuleUID, fingerprint: first.Fingerprint}
+		if e, ok := before[k]; ok {
+			firing = isFiringState(e.CurrentState)
+			delete(before, k)
+		}
+		for _, e := range instance {
+			t := time.UnixMilli(e.Epoch).UTC()
+			if firing {
+				addFiring(e.RuleUID, since, t)
+			}
+			firing, since = isFiringState(e.CurrentState), t
+			titles[e.RuleUID] = e.RuleTitle
+		}
+		if firing {
+			addFiring(first.RuleUID, since, to.UTC())
+		}
+	}
+	// The alert instances without records in the range stay in their last state during the whole range.
+	for _, e := range previous {
+		if _, ok := before[instanceKey{ruleUID: e.RuleUID, fingerprint: e.Fingerprint}]; !ok || !isFiringState(e.CurrentState) {
+			continue
+		}
+		addFiring(e.RuleUID, from.UTC(), to.UTC())
+		titles[e.RuleUID] = e.RuleTitle
+	}
+
+	keys := make([]key, 0, len(durations))
+	for k := range durations {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if !keys[i].day.Equal(keys[j].day) {
+			return keys[i].day.Before(keys[j].day)
+		}
+		return keys[i].ruleUID < keys[j].ruleUID
+	})
+
+	days := make([]time.Time, 0, len(keys))
+	ruleUIDs := make([]string, 0, len(keys))
+	ruleTitles := make([]string, 0, len(keys))
+	seconds := make([]float64, 0, len(keys))
+	for _, k := range keys {
+		days = append(days, k.day)
+		ruleUIDs = append(ruleUIDs, k.ruleUID)
+		ruleTitles = append(ruleTitles, titles[k.ruleUID])
+		seconds = append(seconds, durations[k].Seconds())
+	}
+
+	return data.NewFrame(string(models.HistoryAggregationFiringDuration),
+		data.NewField("day", nil, days),
+		data.NewField("ruleUID", nil, ruleUIDs),
+		data.NewField("ruleTitle", nil, ruleTitles),
+		data.NewField("firingSeconds", nil, seconds),
+	)
+}
+
+// flappingFrame aggregates the number of times that the alert instances of each rule started or stopped firing,
+// and the number of alert instances that did, for the limit rules with the most changes.
+func flappingFrame(entries []stateHistoryEntry, limit int) *data.Frame {
+	type flapping struct {
+		ruleUID   string
+		ruleTitle string
+		changes   int64
+		instances int64
+	}
+	byRule := make(map[string]*flapping)
+	for _, instance := range groupByInstance(entries) {
+		var changes int64
+		for _, e := range instance {
+			if isFiringState(e.PreviousState) != isFiringState(e.CurrentState) {
+				changes++
+			}
+		}
+		if changes == 0 {
+			continue
+		}
+		last := instance[len(instance)-1]
+		f, ok := byRule[last.RuleUID]
+		if !ok {
+			f = &flapping{ruleUID: last.RuleUID}
+			byRule[last.RuleUID] = f
+		}
+		f.ruleTitle = last.RuleTitle
+		f.changes += changes
+		f.instances++
+	}
+
+	rules := make([]*flapping, 0, len(byRule))
+	for _, f := range byRule {
+		rules = append(rules, f)
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].changes != rules[j].changes {
+			return rules[i].changes > rules[j].changes
+		}
+		return rules[i].ruleUID < rules[j].ruleUID
+	})
+	if len(rules) > limit {
+		rules = rules[:limit]
+	}
+
+	ruleUIDs := make([]string, 0, len(rules))
+	ruleTitles := make([]string, 0, len(rules))
+	changes := make([]int64, 0, len(rules))
+	instances := make([]int64, 0, len(rules))
+	for _, f := range rules {
+		ruleUIDs = append(ruleUIDs, f.ruleUID)
+		ruleTitles = append(ruleTitles, f.ruleTitle)
+		changes = append(changes, f.changes)
+		instances = append(instances, f.instances)
+	}
+
+	return data.NewFrame(string(models.HistoryAggregationFlapping),
+		data.NewField("ruleUID", nil, ruleUIDs),
+		data.NewField("ruleTitle", nil, ruleTitles),
+		data.NewField("changes", nil, changes),
+		data.NewField("instances", nil, instances),
+	)
+}
+
+// groupByInstance groups the state history records by alert instance, keeping the order of the records.
+func groupByInstance(entries []stateHistoryEntry) [][]stateHistoryEntry {
+	type key struct {
+		ruleUID     string
+		fingerprint string
+	}
+	index := make(map[key]int)
+	var result [][]stateHistoryEntry
+	for _, e := range entries {
+		k := key{ruleUID: e.RuleUID, fingerprint: e.Fingerprint}
+		i, ok := index[k]
+		if !ok {
+			i = len(result)
+			index[k] = i
+			result = append(result, nil)
+		}
+		result[i] = append(result[i], e)
+	}
+	return result
+}
@@ -0,0 +1,346 @@
+package historian
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	acfakes "github.com/grafana/grafana/pkg/services/ngalert/accesscontrol/fakes"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+)
+
+func TestFiringDurationFrame(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(48 * time.Hour)
+	at := func(d time.Duration) int64 {
+		return from.Add(d).UnixMilli()
+	}
+
+	entries := []stateHistoryEntry{
+		// Instance a fires from 22:00 on the first day to 02:00 on the second day.
+		{RuleUID: "rule-1", RuleTitle: "Rule 1", Fingerprint: "a", PreviousState: "Pending", CurrentState: "Alerting", Epoch: at(22 * time.Hour)},
+		// Instance b was firing before the range and resolves at 01:00.
+		{RuleUID: "rule-1", RuleTitle: "Rule 1", Fingerprint: "b", PreviousState: "Alerting", CurrentState: "Normal", Epoch: at(time.Hour)},
+		{RuleUID: "rule-1", RuleTitle: "Rule 1", Fingerprint: "a", PreviousState: "Alerting", CurrentState: "Recovering", Epoch: at(25 * time.Hour)},
+		{RuleUID: "rule-1", RuleTitle: "Rule 1", Fingerprint: "a", PreviousState: "Recovering", CurrentState: "Normal", Epoch: at(26 * time.Hour)},
+		// Instance c starts firing at 12:00 on the second day and is still firing.
+		{RuleUID: "rule-2", RuleTitle: "Rule 2", Fingerprint: "c", PreviousState: "Pending", CurrentState: "Alerting", Epoch: at(36 * time.Hour)},
+		// Instance e starts firing at 23:00 on the second day.
+		{RuleUID: "rule-3", RuleTitle: "Rule 3", Fingerprint: "e", PreviousState: "Pending", CurrentState: "Alerting", Epoch: at(47 * time.Hour)},
+	}
+	previous := []stateHistoryEntry{
+		// Instance d started firing before the range and fires during the whole range without transitions.
+		{RuleUID: "rule-3", RuleTitle: "Rule 3", Fingerprint: "d", PreviousState: "Pending", CurrentState: "Alerting", Epoch: at(-time.Hour)},
+		// Instance f resolved before the range.
+		{RuleUID: "rule-3", RuleTitle: "Rule 3", Fingerprint: "f", PreviousState: "Alerting", CurrentState: "Normal", Epoch: at(-time.Hour)},
+		{RuleUID: "rule-3", RuleTitle: "Rule 3", Fingerprint: "e", PreviousState: "Normal", CurrentState: "Pending", Epoch: at(-time.Hour)},
+	}
+
+	frame := firingDurationFrame(previous, entries, from, to)
+
+	require.Equal(t, string(models.HistoryAggregationFiringDuration), frame.Name)
+	rows, err := frame.RowLen()
+	require.NoError(t, err)
+	require.Equal(t, 5, rows)
+
+	type row struct {
+		day     time.Time
+		ruleUID string
+		title   string
+		seconds float64
+	}
+	var result []row
+	for i := 0; i < rows; i++ {
+		result = append(result, row{
+			day:     frame.Fields[0].At(i).(time.Time),
+			ruleUID: frame.Fields[1].At(i).(string),
+			title:   frame.Fields[2].At(i).(string),
+			seconds: frame.Fields[3].At(i).(float64),
+		})
+	}
+	require.Equal(t, []row{
+		{day: from, ruleUID: "rule-1", title: "Rule 1", seconds: (3 * time.Hour).Seconds()},
+		{day: from, ruleUID: "rule-3", title: "Rule 3", seconds: (24 * time.Hour).Seconds()},
+		{day: from.Add(24 * time.Hour), ruleUID: "rule-1", title: "Rule 1", seconds: (2 * time.Hour).Seconds()},
+		{day: from.Add(24 * time.Hour), ruleUID: "rule-2", title: "Rule 2", seconds: (12 * time.Hour).Seconds()},
+		{day: from.Add(24 * time.Hour), ruleUID: "rule-3", title: "Rule 3", seconds: (25 * time.Hour).Seconds()},
+	}, result)
+}
+
+func TestFlappingFrame(t *testing.T) {
+	entries := []stateHistoryEntry{
+		{RuleUID: "rule-1", RuleTitle: "Rule 1", Fingerprint: "a", PreviousState: "Normal", CurrentState: "Alerting"},
+		{RuleUID: "rule-1", RuleTitle: "Rule 1", Fingerprint: "a", PreviousState: "Alerting", CurrentState: "Normal"},
+		{RuleUID: "rule-1", RuleTitle: "Rule 1", Fingerprint: "b", PreviousState: "Normal", CurrentState: "Alerting"},
+		{RuleUID: "rule-2", RuleTitle: "Rule 2", Fingerprint: "c", PreviousState: "Normal", CurrentState: "Alerting"},
+		{RuleUID: "rule-2", RuleTitle: "Rule 2", Fingerprint: "c", PreviousState: "Alerting", CurrentState: "Recovering"},
+		// Pending and acknowledgements do not change whether the instance is firing.
+		{RuleUID: "rule-3", RuleTitle: "Rule 3", Fingerprint: "d", PreviousState: "Normal", CurrentState: "Pending"},
+		{RuleUID: "rule-3", RuleTitle: "Rule 3", Fingerprint: "d", PreviousState: "Pending", CurrentState: "Pending"},
+	}
+
+	t.Run("returns the rules ordered by number of changes", func(t *testing.T) {
+		frame := flappingFrame(entries, 10)
+
+		require.Equal(t, string(models.HistoryAggregationFlapping), frame.Name)
+		rows, err := frame.RowLen()
+		require.NoError(t, err)
+		require.Equal(t, 2, rows)
+		require.Equal(t, "rule-1", frame.Fields[0].At(0))
+		require.Equal(t, "Rule 1", frame.Fields[1].At(0))
+		require.Equal(t, int64(3), frame.Fields[2].At(0))
+		require.Equal(t, int64(2), frame.Fields[3].At(0))
+		require.Equal(t, "rule-2", frame.Fields[0].At(1))
+		require.Equal(t, int64(1), frame.Fields[2].At(1))
+		require.Equal(t, int64(1), frame.Fields[3].At(1))
+	})
+
+	t.Run("returns at most limit rules", func(t *testing.T) {
+		frame := flappingFrame(entries, 1)
+
+		rows, err := frame.RowLen()
+		require.NoError(t, err)
+		require.Equal(t, 1, rows)
+		require.Equal(t, "rule-1", frame.Fields[0].At(0))
+	})
+}
+
+func TestIntegrationSQLBackend(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	sqlStore := db.InitTestDB(t)
+	rule := createTestRule()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	usr := &identity.StaticRequester{OrgID: rule.OrgID}
+
+	newBackend := func(t *testing.T) (*SQLBackend, *clock.Mock) {
+		ac := &acfakes.FakeRuleService{
+			CanReadAllRulesFunc: func(ctx context.Context, user identity.Requester) (bool, error) {
+				return true, nil
+			},
+		}
+		met := metrics.NewHistorianMetrics(prometheus.NewRegistry(), metrics.Subsystem)
+		h := NewSQLBackend(log.NewNopLogger(), SQLConfig{BatchSize: 10, FlushInterval: time.Second, Retention: 24 * time.Hour}, sqlStore, met, fakes.NewRuleStore(t), ac)
+		clk := clock.NewMock()
+		clk.Set(start.Add(time.Hour))
+		h.clock = clk
+		t.Cleanup(func() {
+			_, err := sqlStore.GetEngine().Exec("DELETE FROM alert_state_history")
+			require.NoError(t, err)
+		})
+		return h, clk
+	}
+	transition := func(labels data.Labels, prev, cur eval.State, at time.Time) state.StateTransition {
+		return state.StateTransition{
+			PreviousState: prev,
+			State: &state.State{
+				State:              cur,
+				Labels:             labels,
+				LastEvaluationTime: at,
+			},
+		}
+	}
+	record := func(t *testing.T, h *SQLBackend, transitions ...state.StateTransition) {
+		t.Helper()
+		errCh := h.Record(context.Background(), rule, transitions)
+		h.flush()
+		require.NoError(t, <-errCh)
+	}
+
+	t.Run("records are not written until flushed", func(t *testing.T) {
+		h, _ := newBackend(t)
+
+		errCh := h.Record(context.Background(), rule, []state.StateTransition{
+			transition(data.Labels{"a": "1"}, eval.Normal, eval.Alerting, start),
+		})
+		select {
+		case <-errCh:
+			t.Fatal("record should not be written before flush")
+		default:
+		}
+
+		h.flush()
+		require.NoError(t, <-errCh)
+		count, err := sqlStore.GetEngine().Table(stateHistoryEntry{}).Count()
+		require.NoError(t, err)
+		require.Equal(t, int64(1), count)
+	})
+
+	t.Run("query returns the records in the same format as Loki", func(t *testing.T) {
+		h, _ := newBackend(t)
+		record(t, h,
+			transition(data.Labels{"a": "1", "__private__": "x"}, eval.Normal, eval.Alerting, start),
+			transition(data.Labels{"a": "2"}, eval.Normal, eval.Pending, start.Add(time.Minute)),
+		)
+
+		frame, err := h.Query(context.Background(), models.HistoryQuery{OrgID: rule.OrgID, RuleUID: rule.UID, SignedInUser: usr, From: start})
+		require.NoError(t, err)
+
+		rows, err := frame.RowLen()
+		require.NoError(t, err)
+		require.Equal(t, 2, rows)
+		require.Equal(t, start, frame.Fields[0].At(0).(time.Time).UTC())
+
+		var entry LokiEntry
+		require.NoError(t, json.Unmarshal(frame.Fields[1].At(0).(json.RawMessage), &entry))
+		require.Equal(t, rule.UID, entry.RuleUID)
+		require.Equal(t, "Alerting", entry.Current)
+		require.Equal(t, map[string]string{"a": "1"}, entry.InstanceLabels)
+
+		var streamLabels map[string]string
+		require.NoError(t, json.Unmarshal(frame.Fields[2].At(0).(json.RawMessage), &streamLabels))
+		require.Equal(t, rule.NamespaceUID, streamLabels[FolderUIDLabel])
+		require.Equal(t, rule.Group, streamLabels[GroupLabel])
+	})
+
+	t.Run("query filters by labels and returns the most recent records", func(t *testing.T) {
+		h, _ := newBackend(t)
+		record(t, h,
+			transition(data.Labels{"a": "1"}, eval.Normal, eval.Alerting, start),
+			transition(data.Labels{"a": "2"}, eval.Normal, eval.Alerting, start.Add(time.Minute)),
+			transition(data.Labels{"a": "1"}, eval.Alerting, eval.Normal, start.Add(2*time.Minute)),
+		)
+
+		frame, err := h.Query(context.Background(), models.HistoryQuery{OrgID: rule.OrgID, SignedInUser: usr, From: start, Labels: map[string]string{"a": "1"}, Limit: 1})
+		require.NoError(t, err)
+
+		rows, err := frame.RowLen()
+		require.NoError(t, err)
+		require.Equal(t, 1, rows)
+		var entry LokiEntry
+		require.NoError(t, json.Unmarshal(frame.Fields[1].At(0).(json.RawMessage), &entry))
+		require.Equal(t, "Normal", entry.Current)
+		require.Equal(t, map[string]string{"a": "1"}, entry.InstanceLabels)
+	})
+
+	t.Run("query reads the records page by page when filtering by labels", func(t *testing.T) {
+		h, _ := newBackend(t)
+		transitions := []state.StateTransition{transition(data.Labels{"a": "1"}, eval.Normal, eval.Alerting, start)}
+		for i := 0; i < sqlQueryPageSize; i++ {
+			transitions = append(transitions, transition(data.Labels{"a": "2"}, eval.Normal, eval.Alerting, start.Add(time.Duration(i+1)*time.Millisecond)))
+		}
+		record(t, h, transitions...)
+
+		frame, err := h.Query(context.Background(), models.HistoryQuery{OrgID: rule.OrgID, SignedInUser: usr, From: start, Labels: map[string]string{"a": "1"}, Limit: 1})
+		require.NoError(t, err)
+
+		rows, err := frame.RowLen()
+		require.NoError(t, err)
+		require.Equal(t, 1, rows)
+		require.Equal(t, start, frame.Fields[0].At(0).(time.Time).UTC())
+	})
+
+	t.Run("query filters by folders in batches", func(t *testing.T) {
+		h, _ := newBackend(t)
+		other := rule
+		other.NamespaceUID = "other-folder"
+		record(t, h, transition(data.Labels{"a": "1"}, eval.Normal, eval.Alerting, start))
+		errCh := h.Record(context.Background(), other, []state.StateTransition{transition(data.Labels{"a": "1"}, eval.Normal, eval.Alerting, start.Add(time.Minute))})
+		h.flush()
+		require.NoError(t, <-errCh)
+
+		folderUIDs := make([]string, 0, sqlFolderBatchSize+1)
+		for i := 0; i < sqlFolderBatchSize; i++ {
+			folderUIDs = append(folderUIDs, fmt.Sprintf("folder-%d", i))
+		}
+		folderUIDs = append(folderUIDs, rule.NamespaceUID)
+
+		entries, truncated, err := h.find(context.Background(), models.HistoryQuery{OrgID: rule.OrgID, From: start, To: start.Add(time.Hour)}, folderUIDs, 10, false)
+		require.NoError(t, err)
+		require.False(t, truncated)
+		require.Len(t, entries, 1)
+		require.Equal(t, rule.NamespaceUID, entries[0].NamespaceUID)
+	})
+
+	t.Run("query reads at most the maximum number of records", func(t *testing.T) {
+		h, _ := newBackend(t)
+		record(t, h,
+			transition(data.Labels{"a": "1"}, eval.Normal, eval.Alerting, start),
+			transition(data.Labels{"a": "1"}, eval.Alerting, eval.Normal, start.Add(time.Minute)),
+			transition(data.Labels{"a": "1"}, eval.Normal, eval.Alerting, start.Add(2*time.Minute)),
+		)
+		query := models.HistoryQuery{OrgID: rule.OrgID, From: start, To: start.Add(time.Hour)}
+
+		entries, read, truncated, err := h.findInFolders(context.Background(), query, nil, 0, true, 2)
+		require.NoError(t, err)
+		require.True(t, truncated)
+		require.Equal(t, 2, read)
+		require.Len(t, entries, 2)
+
+		entries, _, truncated, err = h.findInFolders(context.Background(), query, nil, 0, true, 3)
+		require.NoError(t, err)
+		require.False(t, truncated)
+		require.Len(t, entries, 3)
+	})
+
+	t.Run("query returns aggregations", func(t *testing.T) {
+		h, _ := newBackend(t)
+		record(t, h,
+			transition(data.Labels{"a": "1"}, eval.Normal, eval.Alerting, start),
+			transition(data.Labels{"a": "1"}, eval.Alerting, eval.Normal, start.Add(10*time.Minute)),
+		)
+
+		frame, err := h.Query(context.Background(), models.HistoryQuery{OrgID: rule.OrgID, SignedInUser: usr, From: start, Aggregation: models.HistoryAggregationFiringDuration})
+		require.NoError(t, err)
+		require.Equal(t, string(models.HistoryAggregationFiringDuration), frame.Name)
+		require.Equal(t, (10 * time.Minute).Seconds(), frame.Fields[3].At(0))
+
+		frame, err = h.Query(context.Background(), models.HistoryQuery{OrgID: rule.OrgID, SignedInUser: usr, From: start, Aggregation: models.HistoryAggregationFlapping})
+		require.NoError(t, err)
+		require.Equal(t, string(models.HistoryAggregationFlapping), frame.Name)
+		require.Equal(t, int64(2), frame.Fields[2].At(0))
+	})
+
+	t.Run("firing duration accounts for alert instances firing all day without transitions", func(t *testing.T) {
+		h, clk := newBackend(t)
+		record(t, h,
+			transition(data.Labels{"a": "1"}, eval.Normal, eval.Alerting, start),
+			transition(data.Labels{"a": "2"}, eval.Normal, eval.Alerting, start),
+			transition(data.Labels{"a": "2"}, eval.Alerting, eval.Normal, start.Add(time.Hour)),
+		)
+		clk.Set(start.Add(48 * time.Hour))
+
+		day := start.Add(24 * time.Hour)
+		frame, err := h.Query(context.Background(), models.HistoryQuery{OrgID: rule.OrgID, SignedInUser: usr, From: day, To: day.Add(24 * time.Hour), Aggregation: models.HistoryAggregationFiringDuration})
+		require.NoError(t, err)
+
+		rows, err := frame.RowLen()
+		require.NoError(t, err)
+		require.Equal(t, 1, rows)
+		require.Equal(t, day, frame.Fields[0].At(0).(time.Time))
+		require.Equal(t, rule.UID, frame.Fields[1].At(0))
+		require.Equal(t, (24 * time.Hour).Seconds(), frame.Fields[3].At(0))
+	})
+
+	t.Run("deletes records older than the retention", func(t *testing.T) {
+		h, clk := newBackend(t)
+		record(t, h,
+			transition(data.Labels{"a": "1"}, eval.Normal, eval.Alerting, start),
+			transition(data.Labels{"a": "1"}, eval.Alerting, eval.Normal, start.Add(2*time.Hour)),
+		)
+
+		clk.Set(start.Add(25 * time.Hour))
+		deleted, err := h.deleteExpired(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, int64(1), deleted)
+
+		count, err := sqlStore.GetEngine().Table(stateHistoryEntry{}).Count()
+		require.NoError(t, err)
+		require.Equal(t, int64(1), count)
+	})
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/benbjohnson/clock"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 
@@ -24,7 +25,7 @@ var (
 	ResendDelay = 30 * time.Second
 )
 
-type takeImageFn func(reason string) *ngModels.Image
+type takeImageFn func(reason string, s *State) *ngModels.Image
 
 // AlertInstanceManager defines the interface for querying the current alert instances.
 type AlertInstanceManager interface {
@@ -53,8 +54,11 @@ type Manager struct {
 	instanceStore InstanceStore
 	images        ImageCapturer
 	historian     Historian
+	silencer      AlertInstanceSilencer
 	externalURL   *url.URL
 
+	acknowledgements *acknowledgementReplicator
+
 	rulesPerRuleGroupLimit int64
 
 	persister StatePersister
@@ -67,6 +71,12 @@ type ManagerCfg struct {
 	Images        ImageCapturer
 	Clock         clock.Clock
 	Historian     Historian
+	// Silencer is used to suppress the notifications of acknowledged alert instances. If nil, acknowledgements cannot
+	// suppress notifications.
+	Silencer AlertInstanceSilencer
+	// ClusterPeer is used to replicate the acknowledgements of alert instances to the other replicas. If nil,
+	// acknowledgements are not replicated.
+	ClusterPeer ClusterPeer
 	// MaxStateSaveConcurrency controls the number of goroutines (per rule) that can save alert state in parallel.
 	MaxStateSaveConcurrency int
 	// StatePeriodicSaveBatchSize controls the size of the alert instance batch that is saved periodically when the
@@ -101,12 +111,20 @@ func NewManager(cfg ManagerCfg, statePersister StatePersister) *Manager {
 		instanceStore:          cfg.InstanceStore,
 		images:                 cfg.Images,
 		historian:              cfg.Historian,
+		silencer:               cfg.Silencer,
 		clock:                  cfg.Clock,
 		externalURL:            cfg.ExternalURL,
 		rulesPerRuleGroupLimit: cfg.RulesPerRuleGroupLimit,
 		persister:              statePersister,
 		tracer:                 cfg.Tracer,
 	}
+	if cfg.ClusterPeer != nil {
+		var reg prometheus.Registerer = prometheus.NewRegistry()
+		if cfg.Metrics != nil {
+			reg = cfg.Metrics.Registerer()
+		}
+		m.acknowledgements = newAcknowledgementReplicator(c, cfg.ClusterPeer, reg, cfg.Log)
+	}
 
 	return m
 }
@@ -180,39 +198,7 @@ func (st *Manager) Warm(ctx context.Context, orgReader OrgReader, rulesReader Ru
 				continue
 			}
 
-			// nil safety.
-			annotations := ruleForEntry.Annotations
-			if annotations == nil {
-				annotations = make(map[string]string)
-			}
-
-			lbs := map[string]string(entry.Labels)
-			cacheID := entry.Labels.Fingerprint()
-			var resultFp data.Fingerprint
-			if entry.ResultFingerprint != "" {
-				fp, err := strconv.ParseUint(entry.ResultFingerprint, 16, 64)
-				if err != nil {
-					logger.Error("Failed to parse result fingerprint of alert instance", "error", err, "rule_uid", entry.RuleUID)
-				}
-				resultFp = data.Fingerprint(fp)
-			}
-			state := &State{
-				AlertRuleUID:         entry.RuleUID,
-				OrgID:                entry.RuleOrgID,
-				CacheID:              cacheID,
-				Labels:               lbs,
-				State:                translateInstanceState(entry.CurrentState),
-				StateReason:          entry.CurrentReason,
-				LastEvaluationString: "",
-				StartsAt:             entry.CurrentStateSince,
-				EndsAt:               entry.CurrentStateEnd,
-				FiredAt:              entry.FiredAt,
-				LastEvaluationTime:   entry.LastEvalTime,
-				Annotations:          annotations,
-				ResultFingerprint:    resultFp,
-				ResolvedAt:           entry.ResolvedAt,
-				LastSentAt:           entry.LastSentAt,
-			}
+			state := stateFromAlertInstance(entry, ruleForEntry, logger)
 			st.cache.set(state)
 			statesCount++
 		}
@@ -221,6 +207,44 @@ func (st *Manager) Warm(ctx context.Context, orgReader OrgReader, rulesReader Ru
 	logger.Info("State cache has been initialized", "states", statesCount, "duration", time.Since(startTime))
 }
 
+// stateFromAlertInstance restores the state of the rule from the alert instance persisted in the instance store.
+func stateFromAlertInstance(entry *ngModels.AlertInstance, rule *ngModels.AlertRule, logger log.Logger) *State {
+	// nil safety.
+	annotations := rule.Annotations
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+
+	lbs := map[string]string(entry.Labels)
+	cacheID := entry.Labels.Fingerprint()
+	var resultFp data.Fingerprint
+	if entry.ResultFingerprint != "" {
+		fp, err := strconv.ParseUint(entry.ResultFingerprint, 16, 64)
+		if err != nil {
+			logger.Error("Failed to parse result fingerprint of alert instance", "error", err, "rule_uid", entry.RuleUID)
+		}
+		resultFp = data.Fingerprint(fp)
+	}
+	return &State{
+		AlertRuleUID:         entry.RuleUID,
+		OrgID:                entry.RuleOrgID,
+		CacheID:              cacheID,
+		Labels:               lbs,
+		State:                translateInstanceState(entry.CurrentState),
+		StateReason:          entry.CurrentReason,
+		LastEvaluationString: "",
+		StartsAt:             entry.CurrentStateSince,
+		EndsAt:               entry.CurrentStateEnd,
+		FiredAt:              entry.FiredAt,
+		LastEvaluationTime:   entry.LastEvalTime,
+		Annotations:          annotations,
+		ResultFingerprint:    resultFp,
+		ResolvedAt:           entry.ResolvedAt,
+		LastSentAt:           entry.LastSentAt,
+		Acknowledgement:      entry.GetAcknowledgement(),
+	}
+}
+
 func (st *Manager) Get(orgID int64, alertRuleUID string, stateId data.Fingerprint) *State {
 	return st.cache.get(orgID, alertRuleUID, stateId)
 }
@@ -256,10 +280,16 @@ func (st *Manager) DeleteStateByRuleUID(ctx context.Context, ruleKey ngModels.Al
 		}
 		s.LastEvaluationTime = now
 		s.Values = map[string]float64{}
+		var ackChange *AcknowledgementChange
+		if previous := s.Acknowledgement; previous != nil {
+			s.Acknowledgement = nil
+			ackChange = st.acknowledgementRemoved(ctx, logger, s.OrgID, previous, now)
+		}
 		transitions = append(transitions, StateTransition{
-			State:               s,
-			PreviousState:       oldState,
-			PreviousStateReason: oldReason,
+			State:                 s,
+			PreviousState:         oldState,
+			PreviousStateReason:   oldReason,
+			AcknowledgementChange: ackChange,
 		})
 	}
 
@@ -282,6 +312,81 @@ func (st *Manager) ForgetStateByRuleUID(ctx context.Context, ruleKey ngModels.Al
 	return st.cache.removeByRuleUID(ruleKey.OrgID, ruleKey.UID)
 }
 
+// HandOffStateByRuleUID saves the current state of the rule to the instance store and removes it from the cache,
+// so that another replica can continue the evaluation of the rule from where this one stopped.
+// It is used when the rule is reassigned to another replica.
+func (st *Manager) HandOffStateByRuleUID(ctx context.Context, ruleKey ngModels.AlertRuleKeyWithGroup) []*State {
+	logger := st.log.FromContext(ctx)
+	states := st.ForgetStateByRuleUID(ctx, ruleKey)
+	if st.instanceStore == nil {
+		return states
+	}
+
+	instances := alertInstancesFromStates(logger, states)
+	if err := st.instanceStore.SaveAlertInstancesForRule(ctx, ruleKey, instances); err != nil {
+		logger.Error("Failed to save the state of the rule for hand-off", "error", err)
+		return states
+	}
+	logger.Debug("Rule state was handed off", "states", len(instances))
+	return states
+}
+
+// alertInstancesFromStates converts the states that are not stale to alert instances that can be saved to the instance store.
+func alertInstancesFromStates(logger log.Logger, states []*State) []ngModels.AlertInstance {
+	instances := make([]ngModels.AlertInstance, 0, len(states))
+	for _, s := range states {
+		if s.IsStale() {
+			continue
+		}
+		key, err := s.GetAlertInstanceKey()
+		if err != nil {
+			logger.Error("Failed to create a key for alert state to save it. The state will be ignored", "cacheID", s.CacheID, "error", err, "labels", s.Labels.String())
+			continue
+		}
+		instance := ngModels.AlertInstance{
+			AlertInstanceKey:  key,
+			Labels:            ngModels.InstanceLabels(s.Labels),
+			CurrentState:      ngModels.InstanceStateType(s.State.String()),
+			CurrentReason:     s.StateReason,
+			LastEvalTime:      s.LastEvaluationTime,
+			CurrentStateSince: s.StartsAt,
+			CurrentStateEnd:   s.EndsAt,
+			FiredAt:           s.FiredAt,
+			ResolvedAt:        s.ResolvedAt,
+			LastSentAt:        s.LastSentAt,
+			ResultFingerprint: s.ResultFingerprint.String(),
+		}
+		instance.SetAcknowledgement(s.Acknowledgement)
+		instances = append(instances, instance)
+	}
+	return instances
+}
+
+// LoadStateByRuleUID replaces the state of the rule in the cache with the one persisted in the instance store.
+// It is used when this replica takes over the evaluation of the rule from another replica.
+func (st *Manager) LoadStateByRuleUID(ctx context.Context, rule *ngModels.AlertRule) int {
+	logger := st.log.FromContext(ctx)
+	if st.instanceStore == nil {
+		return 0
+	}
+
+	instances, err := st.instanceStore.ListAlertInstances(ctx, &ngModels.ListAlertInstancesQuery{
+		RuleOrgID: rule.OrgID,
+		RuleUID:   rule.UID,
+	})
+	if err != nil {
+		logger.Error("Unable to fetch the state of the rule", "error", err)
+		return 0
+	}
+
+	st.cache.removeByRuleUID(rule.OrgID, rule.UID)
+	for _, entry := range instances {
+		st.cache.set(stateFromAlertInstance(entry, rule, logger))
+	}
+	logger.Debug("Rule state was loaded", "states", len(instances))
+	return len(instances)
+}
+
 // ResetStateByRuleUID removes the rule instances from cache and instanceStore and saves state history. If the state
 // history has to be saved, rule must not be nil.
 func (st *Manager) ResetStateByRuleUID(ctx context.Context, rule *ngModels.AlertRule, reason string) []StateTransition {
@@ -330,12 +435,12 @@ func (st *Manager) ProcessEvalResults(
 	{
 		var image *ngModels.Image
 		var imageTaken bool
-		fn = func(reason string) *ngModels.Image {
+		fn = func(reason string, s *State) *ngModels.Image {
 			if imageTaken {
 				return image
 			}
 			logger.Debug("Taking image", "dashboard", alertRule.GetDashboardUID(), "panel", alertRule.GetPanelID(), "reason", reason)
-			img, err := takeImage(ctx, st.images, alertRule)
+			img, err := takeImage(ctx, st.images, alertRule, results, s.firingWindow())
 			imageTaken = true
 			if err != nil {
 				logger.Warn("Failed to take an image",
@@ -359,6 +464,7 @@ func (st *Manager) ProcessEvalResults(
 	))
 
 	allChanges := StateTransitions(append(states, staleStates...))
+	st.updateAcknowledgements(ctx, logger, allChanges, evaluatedAt)
 
 	// It's important that this is done *before* we sync the states to the persister. Otherwise, we will not persist
 	// the LastSentAt field to the store.
@@ -395,6 +501,11 @@ func (st *Manager) updateLastSentAt(states StateTransitions, evaluatedAt time.Ti
 }
 
 func (st *Manager) setNextStateForRule(ctx context.Context, alertRule *ngModels.AlertRule, results eval.Results, extraLabels data.Labels, logger log.Logger, takeImageFn takeImageFn, now time.Time) []StateTransition {
+	suppressed := false
+	if parentUID := st.firingDependency(logger, alertRule); parentUID != "" {
+		logger.Debug("Alert rule is suppressed by a firing dependency", "parent_rule_uid", parentUID)
+		suppressed = true
+	}
 	if results.IsNoData() && (alertRule.NoDataState == ngModels.Alerting || alertRule.NoDataState == ngModels.OK || alertRule.NoDataState == ngModels.KeepLast) { // If it is no data, check the mapping and switch all results to the new state
 		// aggregate UID of datasources that returned NoData into one and provide as auxiliary info via annotationa. See: https://github.com/grafana/grafana/issues/88184
 		var refIds strings.Builder
@@ -430,14 +541,14 @@ func (st *Manager) setNextStateForRule(ctx context.Context, alertRule *ngModels.
 		if len(results) > 0 {
 			result = results[0]
 		}
-		transitions := st.setNextStateForAll(alertRule, result, logger, annotations, takeImageFn)
+		transitions := st.setNextStateForAll(alertRule, result, logger, annotations, takeImageFn, suppressed)
 		if len(transitions) > 0 {
 			return transitions // if there are no current states for the rule. Create ones for each result
 		}
 	}
 	if results.IsError() && (alertRule.ExecErrState == ngModels.AlertingErrState || alertRule.ExecErrState == ngModels.OkErrState || alertRule.ExecErrState == ngModels.KeepLastErrState) {
 		// TODO squash all errors into one, and provide as annotation
-		transitions := st.setNextStateForAll(alertRule, results[0], logger, nil, takeImageFn)
+		transitions := st.setNextStateForAll(alertRule, results[0], logger, nil, takeImageFn, suppressed)
 		if len(transitions) > 0 {
 			return transitions // if there are no current states for the rule. Create ones for each result
 		}
@@ -449,17 +560,17 @@ func (st *Manager) setNextStateForRule(ctx context.Context, alertRule *ngModels.
 			patch(newState, curState, result)
 		}
 		start := st.clock.Now()
-		s := newState.transition(alertRule, result, nil, logger, takeImageFn)
+		s := newState.transition(alertRule, result, nil, logger, takeImageFn, suppressed)
 		if st.metrics != nil {
 			st.metrics.StateUpdateDuration.Observe(st.clock.Now().Sub(start).Seconds())
 		}
-		st.cache.set(newState) // replace the existing state with the new one
+		st.cache.setEvaluated(newState) // replace the existing state with the new one
 		transitions = append(transitions, s)
 	}
 	return transitions
 }
 
-func (st *Manager) setNextStateForAll(alertRule *ngModels.AlertRule, result eval.Result, logger log.Logger, extraAnnotations data.Labels, takeImageFn takeImageFn) []StateTransition {
+func (st *Manager) setNextStateForAll(alertRule *ngModels.AlertRule, result eval.Result, logger log.Logger, extraAnnotations data.Labels, takeImageFn takeImageFn, suppressed bool) []StateTransition {
 	currentStates := st.cache.getStatesForRuleUID(alertRule.OrgID, alertRule.UID)
 	transitions := make([]StateTransition, 0, len(currentStates))
 	updated := ruleStates{
@@ -468,7 +579,7 @@ func (st *Manager) setNextStateForAll(alertRule *ngModels.AlertRule, result eval
 	for _, currentState := range currentStates {
 		start := st.clock.Now()
 		newState := currentState.Copy()
-		t := newState.transition(alertRule, result, extraAnnotations, logger, takeImageFn)
+		t := newState.transition(alertRule, result, extraAnnotations, logger, takeImageFn, suppressed)
 		if st.metrics != nil {
 			st.metrics.StateUpdateDuration.Observe(st.clock.Now().Sub(start).Seconds())
 		}
@@ -479,14 +590,69 @@ func (st *Manager) setNextStateForAll(alertRule *ngModels.AlertRule, result eval
 	return transitions
 }
 
+// firingDependency returns the UID of the first dependency of the alert rule that has a firing instance that matches
+// the matchers of the dependency, or an empty string if there is none. An instance is firing if it is sent to the
+// Alertmanager as a firing alert, so the NoData and Error states of the parent rule suppress the alert rule too. The instances of the parent rules are those
+// of their last evaluation, which can precede the evaluation of the alert rule by up to one interval. They are read
+// from the cache of this replica, which has them because the scheduler evaluates dependent rules on the same replica
+// when the evaluation is sharded.
+func (st *Manager) firingDependency(logger log.Logger, alertRule *ngModels.AlertRule) string {
+	for _, dep := range alertRule.Dependencies {
+		matchers, err := dep.LabelMatchers()
+		if err != nil {
+			logger.Warn("Ignoring dependency with invalid matchers", "parent_rule_uid", dep.RuleUID, "error", err)
+			continue
+		}
+		for _, s := range st.cache.getStatesForRuleUID(alertRule.OrgID, dep.RuleUID) {
+			if !isFiringState(s.State) {
+				continue
+			}
+			matches := true
+			for _, m := range matchers {
+				if !m.Matches(s.Labels[m.Name]) {
+					matches = false
+					break
+				}
+			}
+			if matches {
+				return dep.RuleUID
+			}
+		}
+	}
+	return ""
+}
+
 func (st *Manager) GetAll(orgID int64) []*State {
 	allStates := st.cache.getAll(orgID)
 	return allStates
 }
+
+// GetAllOrgStates returns the states of all organizations, by organization.
+func (st *Manager) GetAllOrgStates() map[int64][]*State {
+	return st.cache.getAllOrgs()
+}
+
 func (st *Manager) GetStatesForRuleUID(orgID int64, alertRuleUID string) []*State {
 	return st.cache.getStatesForRuleUID(orgID, alertRuleUID)
 }
 
+// ListAlertInstances returns the current states of the organization in the cache as alert instances. It implements
+// InstanceReader to read the state of rules that is not yet saved to the instance store. The rule group of the query is ignored.
+func (st *Manager) ListAlertInstances(ctx context.Context, cmd *ngModels.ListAlertInstancesQuery) ([]*ngModels.AlertInstance, error) {
+	var states []*State
+	if cmd.RuleUID != "" {
+		states = st.cache.getStatesForRuleUID(cmd.RuleOrgID, cmd.RuleUID)
+	} else {
+		states = st.cache.getAll(cmd.RuleOrgID)
+	}
+	instances := alertInstancesFromStates(st.log.FromContext(ctx), states)
+	result := make([]*ngModels.AlertInstance, 0, len(instances))
+	for i := range instances {
+		result = append(result, &instances[i])
+	}
+	return result, nil
+}
+
 func (st *Manager) GetStatusForRuleUID(orgID int64, alertRuleUID string) ngModels.RuleStatus {
 	states := st.GetStatesForRuleUID(orgID, alertRuleUID)
 	return StatesToRuleStatus(states)
@@ -512,6 +678,8 @@ func translateInstanceState(state ngModels.InstanceStateType) eval.State {
 		return eval.Pending
 	case ngModels.InstanceStateRecovering:
 		return eval.Recovering
+	case ngModels.InstanceStateSuppressed:
+		return eval.Suppressed
 	default:
 		return eval.Error
 	}
@@ -538,7 +706,7 @@ func (st *Manager) deleteStaleStatesFromCache(logger log.Logger, evaluatedAt tim
 		// By setting ResolvedAt we trigger the scheduler to send a resolved notification to the Alertmanager.
 		if s.ShouldBeResolved(oldState) {
 			s.ResolvedAt = &evaluatedAt
-			image := takeImageFn("stale state")
+			image := takeImageFn("stale state", s)
 			if image != nil {
 				s.Image = image
 			}
@@ -586,6 +754,7 @@ func StatesToRuleStatus(states []*State) ngModels.RuleStatus {
 		case eval.Pending:
 		case eval.Alerting:
 		case eval.Recovering:
+		case eval.Suppressed:
 		case eval.Error:
 			status.Health = "error"
 		case eval.NoData:
@@ -13,11 +13,14 @@ import (
 	"time"
 
 	"github.com/benbjohnson/clock"
+	"github.com/go-openapi/strfmt"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	alertingCluster "github.com/grafana/alerting/cluster"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/exp/slices"
@@ -1326,6 +1329,8 @@ func TestProcessEvalResults(t *testing.T) {
 					continue
 				}
 				delete(expectedStates, actual.CacheID)
+				// The time when the state became active is covered by the tests of the state setters.
+				expected.ActiveAt = actual.ActiveAt
 				if !assert.ObjectsAreEqual(expected, actual) {
 					assert.Failf(t, "expected and actual states are not equal", "Diff: %s", cmp.Diff(expected, actual, cmpopts.EquateErrors()))
 				}
@@ -2037,6 +2042,465 @@ func TestResetStateByRuleUID(t *testing.T) {
 	}
 }
 
+func TestHandOffAndLoadStateByRuleUID(t *testing.T) {
+	interval := time.Minute
+	ctx := context.Background()
+	ng, dbstore := tests.SetupTestEnv(t, 1)
+
+	orgService, err := alertTestUtil.SetupOrgService(t, dbstore.SQLStore, setting.NewCfg())
+	require.NoError(t, err)
+	mainOrg, err := orgService.CreateWithMember(ctx, &org.CreateOrgCommand{})
+	require.NoError(t, err)
+
+	rule := tests.CreateTestAlertRule(t, ctx, dbstore, int64(interval.Seconds()), mainOrg.ID)
+
+	labels1 := models.InstanceLabels{"test1": "testValue1"}
+	_, hash1, _ := labels1.StringAndHash()
+	require.NoError(t, ng.InstanceStore.SaveAlertInstance(ctx, models.AlertInstance{
+		AlertInstanceKey: models.AlertInstanceKey{
+			RuleOrgID:  rule.OrgID,
+			RuleUID:    rule.UID,
+			LabelsHash: hash1,
+		},
+		CurrentState: models.InstanceStateNormal,
+		Labels:       labels1,
+	}))
+
+	newManager := func() *state.Manager {
+		cfg := state.ManagerCfg{
+			Metrics:       metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+			InstanceStore: ng.InstanceStore,
+			Images:        &state.NoopImageService{},
+			Clock:         clock.NewMock(),
+			Historian:     &state.FakeHistorian{},
+			Tracer:        tracing.InitializeTracerForTest(),
+			Log:           log.New("ngalert.state.manager"),
+		}
+		return state.NewManager(cfg, state.NewNoopPersister())
+	}
+
+	previousOwner := newManager()
+	previousOwner.Warm(ctx, dbstore, dbstore, ng.InstanceStore)
+	require.Len(t, previousOwner.GetStatesForRuleUID(rule.OrgID, rule.UID), 1)
+
+	// the state changed since it was last saved
+	firedAt := time.Now().Truncate(time.Second).UTC()
+	firing := setCacheID(&state.State{
+		AlertRuleUID: rule.UID,
+		OrgID:        rule.OrgID,
+		Labels:       data.Labels{"test2": "testValue2"},
+		State:        eval.Alerting,
+		StartsAt:     firedAt,
+		FiredAt:      &firedAt,
+	})
+	previousOwner.Put([]*state.State{firing})
+
+	handedOff := previousOwner.HandOffStateByRuleUID(ctx, rule.GetKeyWithGroup())
+	require.Len(t, handedOff, 2)
+	require.Empty(t, previousOwner.GetStatesForRuleUID(rule.OrgID, rule.UID))
+
+	alertInstances, err := ng.InstanceStore.ListAlertInstances(ctx, &models.ListAlertInstancesQuery{RuleOrgID: rule.OrgID, RuleUID: rule.UID})
+	require.NoError(t, err)
+	require.Len(t, alertInstances, 2)
+
+	newOwner := newManager()
+	require.Equal(t, 2, newOwner.LoadStateByRuleUID(ctx, rule))
+	loaded := stateSliceToMap(newOwner.GetStatesForRuleUID(rule.OrgID, rule.UID))
+	require.Len(t, loaded, 2)
+	require.Contains(t, loaded, firing.CacheID)
+	require.Equal(t, eval.Alerting, loaded[firing.CacheID].State)
+	require.Equal(t, firedAt, loaded[firing.CacheID].StartsAt.UTC())
+	require.NotNil(t, loaded[firing.CacheID].FiredAt)
+}
+
+func TestAcknowledgeState(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewMock()
+	clk.Set(time.Now().Truncate(time.Second))
+	silencer := &fakeSilencer{}
+	historian := &state.FakeHistorian{}
+	instanceStore := &state.FakeInstanceStore{}
+	st := state.NewManager(state.ManagerCfg{
+		Metrics:       metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+		InstanceStore: instanceStore,
+		Images:        &state.NoopImageService{},
+		Clock:         clk,
+		Historian:     historian,
+		Silencer:      silencer,
+		Tracer:        tracing.InitializeTracerForTest(),
+		Log:           log.New("ngalert.state.manager"),
+	}, state.NewNoopPersister())
+
+	gen := models.RuleGen
+	rule := gen.With(gen.WithFor(0), gen.WithKeepFiringFor(0), gen.WithIntervalSeconds(60), gen.WithNoDataExecAs(models.NoData)).GenerateRef()
+	evaluate := func(s eval.State) state.StateTransitions {
+		return st.ProcessEvalResults(ctx, clk.Now(), rule, eval.Results{{
+			Instance:    data.Labels{"instance": "a"},
+			State:       s,
+			EvaluatedAt: clk.Now(),
+		}}, nil, nil)
+	}
+
+	transitions := evaluate(eval.Alerting)
+	require.Len(t, transitions, 1)
+	fp := transitions[0].CacheID
+
+	t.Run("fails if state does not exist", func(t *testing.T) {
+		_, err := st.AcknowledgeState(ctx, rule, fp+1, state.AcknowledgeCommand{By: "admin"})
+		require.ErrorIs(t, err, state.ErrAlertInstanceNotFound)
+	})
+
+	t.Run("fails if suppression has no expiry", func(t *testing.T) {
+		_, err := st.AcknowledgeState(ctx, rule, fp, state.AcknowledgeCommand{By: "admin", SuppressNotifications: true})
+		require.ErrorIs(t, err, state.ErrInvalidAcknowledgement)
+	})
+
+	t.Run("fails to unacknowledge if not acknowledged", func(t *testing.T) {
+		_, err := st.UnacknowledgeState(ctx, rule, fp)
+		require.ErrorIs(t, err, state.ErrAlertInstanceNotAcknowledged)
+	})
+
+	t.Run("acknowledges firing state and suppresses notifications", func(t *testing.T) {
+		expiresAt := clk.Now().Add(time.Hour)
+		s, err := st.AcknowledgeState(ctx, rule, fp, state.AcknowledgeCommand{
+			By:                    "admin",
+			Comment:               "looking into it",
+			ExpiresAt:             &expiresAt,
+			SuppressNotifications: true,
+		})
+		require.NoError(t, err)
+		require.NotNil(t, s.Acknowledgement)
+		require.Equal(t, "admin", s.Acknowledgement.By)
+		require.Equal(t, "silence-1", s.Acknowledgement.SilenceID)
+		require.Equal(t, s.Acknowledgement, st.Get(rule.OrgID, rule.UID, fp).Acknowledgement)
+
+		require.Len(t, silencer.created, 1)
+		require.Equal(t, "admin", *silencer.created[0].CreatedBy)
+		require.Equal(t, strfmt.DateTime(expiresAt), *silencer.created[0].EndsAt)
+		lbls := make(model.LabelSet, len(s.Labels))
+		for k, v := range s.Labels {
+			lbls[model.LabelName(k)] = model.LabelValue(v)
+		}
+		require.True(t, silencer.created[0].Mutes(lbls, clk.Now()))
+
+		require.NotEmpty(t, historian.StateTransitions)
+		last := historian.StateTransitions[len(historian.StateTransitions)-1]
+		require.NotNil(t, last.AcknowledgementChange)
+		require.Nil(t, last.AcknowledgementChange.Previous)
+
+		ops := instanceStore.RecordedOps()
+		require.NotEmpty(t, ops)
+		op, ok := ops[len(ops)-1].(state.FakeInstanceStoreOp)
+		require.True(t, ok)
+		require.Equal(t, "SaveAlertInstancesForRule", op.Name)
+		instances := op.Args[2].([]models.AlertInstance)
+		require.Len(t, instances, 1)
+		require.Equal(t, s.Acknowledgement, instances[0].GetAcknowledgement())
+	})
+
+	t.Run("keeps acknowledgement while firing", func(t *testing.T) {
+		clk.Add(time.Minute)
+		transitions := evaluate(eval.Alerting)
+		require.NotNil(t, transitions[0].Acknowledgement)
+		require.Nil(t, transitions[0].AcknowledgementChange)
+	})
+
+	t.Run("removes acknowledgement and silence when resolved", func(t *testing.T) {
+		clk.Add(time.Minute)
+		transitions := evaluate(eval.Normal)
+		require.Nil(t, transitions[0].Acknowledgement)
+		require.NotNil(t, transitions[0].AcknowledgementChange)
+		require.Equal(t, "admin", transitions[0].AcknowledgementChange.Previous.By)
+		require.Equal(t, []string{"silence-1"}, silencer.deleted)
+	})
+
+	t.Run("fails if state is not firing", func(t *testing.T) {
+		_, err := st.AcknowledgeState(ctx, rule, fp, state.AcknowledgeCommand{By: "admin"})
+		require.ErrorIs(t, err, state.ErrAlertInstanceNotFiring)
+	})
+
+	t.Run("removes acknowledgement when expired", func(t *testing.T) {
+		clk.Add(time.Minute)
+		evaluate(eval.Alerting)
+		expiresAt := clk.Now().Add(time.Minute)
+		_, err := st.AcknowledgeState(ctx, rule, fp, state.AcknowledgeCommand{By: "admin", ExpiresAt: &expiresAt})
+		require.NoError(t, err)
+
+		clk.Add(2 * time.Minute)
+		transitions := evaluate(eval.Alerting)
+		require.Nil(t, transitions[0].Acknowledgement)
+		require.NotNil(t, transitions[0].AcknowledgementChange)
+	})
+
+	t.Run("sets expiry from duration", func(t *testing.T) {
+		s, err := st.AcknowledgeState(ctx, rule, fp, state.AcknowledgeCommand{By: "admin", Duration: time.Hour})
+		require.NoError(t, err)
+		require.Equal(t, clk.Now().Add(time.Hour), *s.Acknowledgement.ExpiresAt)
+
+		expiresAt := clk.Now().Add(time.Hour)
+		_, err = st.AcknowledgeState(ctx, rule, fp, state.AcknowledgeCommand{By: "admin", Duration: time.Hour, ExpiresAt: &expiresAt})
+		require.ErrorIs(t, err, state.ErrInvalidAcknowledgement)
+	})
+
+	t.Run("unacknowledges state", func(t *testing.T) {
+		expiresAt := clk.Now().Add(time.Hour)
+		_, err := st.AcknowledgeState(ctx, rule, fp, state.AcknowledgeCommand{By: "admin", ExpiresAt: &expiresAt, SuppressNotifications: true})
+		require.NoError(t, err)
+
+		s, err := st.UnacknowledgeState(ctx, rule, fp)
+		require.NoError(t, err)
+		require.Nil(t, s.Acknowledgement)
+		require.Nil(t, st.Get(rule.OrgID, rule.UID, fp).Acknowledgement)
+		require.Equal(t, []string{"silence-1", "silence-2"}, silencer.deleted)
+	})
+}
+
+func TestRuleDependencies(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewMock()
+	clk.Set(time.Now().Truncate(time.Second))
+	st := state.NewManager(state.ManagerCfg{
+		Metrics:       metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+		InstanceStore: &state.FakeInstanceStore{},
+		Images:        &state.NoopImageService{},
+		Clock:         clk,
+		Historian:     &state.FakeHistorian{},
+		Tracer:        tracing.InitializeTracerForTest(),
+		Log:           log.New("ngalert.state.manager"),
+	}, state.NewNoopPersister())
+
+	gen := models.RuleGen
+	gen = gen.With(gen.WithOrgID(1), gen.WithFor(0), gen.WithKeepFiringFor(0), gen.WithIntervalSeconds(60))
+	parent := gen.GenerateRef()
+	child := gen.With(gen.WithDependencies(models.AlertRuleDependency{RuleUID: parent.UID, Matchers: []string{`dc="eu-1"`}})).GenerateRef()
+
+	evaluate := func(rule *models.AlertRule, s eval.State, dcs ...string) state.StateTransitions {
+		results := make(eval.Results, 0, len(dcs))
+		for _, dc := range dcs {
+			results = append(results, eval.Result{Instance: data.Labels{"dc": dc}, State: s, EvaluatedAt: clk.Now()})
+		}
+		return st.ProcessEvalResults(ctx, clk.Now(), rule, results, nil, nil)
+	}
+
+	sent := evaluate(child, eval.Alerting, "eu-1")
+	require.Len(t, sent, 1)
+	require.Equal(t, eval.Alerting, sent[0].State.State)
+	firedAt := sent[0].FiredAt
+
+	t.Run("is not suppressed by instances of the parent that do not match", func(t *testing.T) {
+		clk.Add(time.Minute)
+		evaluate(parent, eval.Alerting, "eu-2")
+		sent := evaluate(child, eval.Alerting, "eu-1")
+		require.Len(t, sent, 1)
+		require.Equal(t, eval.Alerting, sent[0].State.State)
+	})
+
+	t.Run("is suppressed and resolved while a matching instance of the parent is firing", func(t *testing.T) {
+		clk.Add(time.Minute)
+		evaluate(parent, eval.Alerting, "eu-1", "eu-2")
+		suppressedAt := clk.Now()
+		sent := evaluate(child, eval.Alerting, "eu-1")
+		require.Len(t, sent, 1)
+		require.Equal(t, eval.Suppressed, sent[0].State.State)
+		require.Equal(t, eval.Alerting, sent[0].PreviousState)
+		require.Equal(t, "Alerting", sent[0].StateReason)
+		require.Equal(t, suppressedAt, *sent[0].ResolvedAt)
+		require.Equal(t, suppressedAt, sent[0].EndsAt)
+		require.Equal(t, firedAt, sent[0].FiredAt)
+
+		clk.Add(time.Minute)
+		evaluate(child, eval.Alerting, "eu-1")
+		s := st.GetStatesForRuleUID(child.OrgID, child.UID)
+		require.Len(t, s, 1)
+		require.Equal(t, eval.Suppressed, s[0].State)
+		require.Equal(t, suppressedAt, s[0].StartsAt)
+	})
+
+	t.Run("fires again when the parent is resolved", func(t *testing.T) {
+		clk.Add(time.Minute)
+		evaluate(parent, eval.Normal, "eu-1", "eu-2")
+		sent := evaluate(child, eval.Alerting, "eu-1")
+		require.Len(t, sent, 1)
+		require.Equal(t, eval.Alerting, sent[0].State.State)
+		require.Equal(t, eval.Suppressed, sent[0].PreviousState)
+		require.Nil(t, sent[0].ResolvedAt)
+		require.Equal(t, clk.Now(), sent[0].StartsAt)
+		require.Equal(t, clk.Now(), *sent[0].FiredAt)
+	})
+
+	t.Run("is suppressed while a matching instance of the parent is in the Error state", func(t *testing.T) {
+		parent := gen.With(gen.WithErrorExecAs(models.ErrorErrState)).GenerateRef()
+		child := gen.With(gen.WithDependencies(models.AlertRuleDependency{RuleUID: parent.UID})).GenerateRef()
+		clk.Add(time.Minute)
+		st.ProcessEvalResults(ctx, clk.Now(), parent, eval.Results{
+			{Instance: data.Labels{"dc": "eu-1"}, State: eval.Error, Error: errors.New("failed to query"), EvaluatedAt: clk.Now()},
+		}, nil, nil)
+		require.Equal(t, eval.Error, st.GetStatesForRuleUID(parent.OrgID, parent.UID)[0].State)
+
+		sent := evaluate(child, eval.Alerting, "eu-1")
+		require.Len(t, sent, 1)
+		require.Equal(t, eval.Suppressed, sent[0].State.State)
+	})
+
+	t.Run("keeps the state it would have had while suppressed", func(t *testing.T) {
+		child := gen.With(gen.WithFor(2*time.Minute), gen.WithDependencies(models.AlertRuleDependency{RuleUID: parent.UID})).GenerateRef()
+		clk.Add(time.Minute)
+		evaluate(parent, eval.Alerting, "eu-1")
+		for _, expected := range []string{"Pending", "Pending", "Alerting", "Alerting"} {
+			sent := evaluate(child, eval.Alerting, "eu-1")
+			require.Len(t, sent, 1)
+			require.Equal(t, eval.Suppressed, sent[0].State.State)
+			require.Equal(t, expected, sent[0].StateReason)
+			clk.Add(time.Minute)
+		}
+	})
+}
+
+type fakeSilencer struct {
+	created []models.Silence
+	deleted []string
+}
+
+func (f *fakeSilencer) CreateSilence(_ context.Context, _ int64, ps models.Silence) (string, error) {
+	f.created = append(f.created, ps)
+	return fmt.Sprintf("silence-%d", len(f.created)), nil
+}
+
+func (f *fakeSilencer) DeleteSilence(_ context.Context, _ int64, silenceID string) error {
+	f.deleted = append(f.deleted, silenceID)
+	return nil
+}
+
+func TestAcknowledgementReplication(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewMock()
+	clk.Set(time.Now().Truncate(time.Second))
+	cluster := &fakeCluster{}
+	newReplica := func() *state.Manager {
+		return state.NewManager(state.ManagerCfg{
+			Metrics:       metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+			InstanceStore: &state.FakeInstanceStore{},
+			Images:        &state.NoopImageService{},
+			Clock:         clk,
+			Historian:     &state.FakeHistorian{},
+			ClusterPeer:   cluster.newPeer(),
+			Tracer:        tracing.InitializeTracerForTest(),
+			Log:           log.New("ngalert.state.manager"),
+		}, state.NewNoopPersister())
+	}
+	a, b := newReplica(), newReplica()
+
+	gen := models.RuleGen
+	rule := gen.With(gen.WithFor(0), gen.WithKeepFiringFor(0), gen.WithIntervalSeconds(60)).GenerateRef()
+	evaluate := func(st *state.Manager, s eval.State) state.StateTransitions {
+		return st.ProcessEvalResults(ctx, clk.Now(), rule, eval.Results{{
+			Instance:    data.Labels{"instance": "a"},
+			State:       s,
+			EvaluatedAt: clk.Now(),
+		}}, nil, nil)
+	}
+	fp := evaluate(a, eval.Alerting)[0].CacheID
+	evaluate(b, eval.Alerting)
+
+	t.Run("replicates acknowledgements to the other replicas", func(t *testing.T) {
+		s, err := a.AcknowledgeState(ctx, rule, fp, state.AcknowledgeCommand{By: "admin", Comment: "on it"})
+		require.NoError(t, err)
+		require.True(t, s.Acknowledgement.Equals(b.Get(rule.OrgID, rule.UID, fp).Acknowledgement))
+
+		clk.Add(time.Minute)
+		transitions := evaluate(b, eval.Alerting)
+		require.True(t, s.Acknowledgement.Equals(transitions[0].Acknowledgement))
+	})
+
+	t.Run("replicates removals of acknowledgements", func(t *testing.T) {
+		full, err := cluster.states[0].MarshalBinary()
+		require.NoError(t, err)
+
+		clk.Add(time.Minute)
+		_, err = b.UnacknowledgeState(ctx, rule, fp)
+		require.NoError(t, err)
+		require.Nil(t, a.Get(rule.OrgID, rule.UID, fp).Acknowledgement)
+
+		// A full state sync from a replica that missed the removal must not restore the acknowledgement.
+		require.NoError(t, cluster.states[1].Merge(full))
+		require.Nil(t, b.Get(rule.OrgID, rule.UID, fp).Acknowledgement)
+	})
+
+	t.Run("applies the most recent acknowledgement", func(t *testing.T) {
+		clk.Add(time.Minute)
+		_, err := a.AcknowledgeState(ctx, rule, fp, state.AcknowledgeCommand{By: "first"})
+		require.NoError(t, err)
+		full, err := cluster.states[0].MarshalBinary()
+		require.NoError(t, err)
+
+		clk.Add(time.Minute)
+		_, err = b.AcknowledgeState(ctx, rule, fp, state.AcknowledgeCommand{By: "second"})
+		require.NoError(t, err)
+		require.NoError(t, cluster.states[1].Merge(full))
+		require.Equal(t, "second", a.Get(rule.OrgID, rule.UID, fp).Acknowledgement.By)
+		require.Equal(t, "second", b.Get(rule.OrgID, rule.UID, fp).Acknowledgement.By)
+	})
+}
+
+// fakeCluster delivers the broadcasts of each peer to the states of the other peers.
+type fakeCluster struct {
+	states []alertingCluster.State
+}
+
+func (c *fakeCluster) newPeer() *fakeClusterPeer {
+	return &fakeClusterPeer{cluster: c, index: len(c.states)}
+}
+
+type fakeClusterPeer struct {
+	cluster *fakeCluster
+	index   int
+}
+
+func (p *fakeClusterPeer) AddState(_ string, s alertingCluster.State, _ prometheus.Registerer) alertingCluster.ClusterChannel {
+	p.cluster.states = append(p.cluster.states, s)
+	return p
+}
+
+func (p *fakeClusterPeer) Broadcast(b []byte) {
+	for i, s := range p.cluster.states {
+		if i != p.index {
+			_ = s.Merge(b)
+		}
+	}
+}
+
+func TestListAlertInstances(t *testing.T) {
+	ctx := context.Background()
+	st := state.NewManager(state.ManagerCfg{
+		Metrics:       metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+		InstanceStore: &state.FakeInstanceStore{},
+		Images:        &state.NoopImageService{},
+		Clock:         clock.NewMock(),
+		Historian:     &state.FakeHistorian{},
+		Tracer:        tracing.InitializeTracerForTest(),
+		Log:           log.New("ngalert.state.manager"),
+	}, state.NewNoopPersister())
+
+	firedAt := time.Now().Truncate(time.Second).UTC()
+	st.Put([]*state.State{
+		setCacheID(&state.State{OrgID: 1, AlertRuleUID: "rule-1", Labels: data.Labels{"a": "1"}, State: eval.Alerting, StartsAt: firedAt, FiredAt: &firedAt}),
+		setCacheID(&state.State{OrgID: 1, AlertRuleUID: "rule-2", Labels: data.Labels{"a": "2"}, State: eval.Normal}),
+		setCacheID(&state.State{OrgID: 2, AlertRuleUID: "rule-1", Labels: data.Labels{"a": "3"}, State: eval.Normal}),
+	})
+
+	instances, err := st.ListAlertInstances(ctx, &models.ListAlertInstancesQuery{RuleOrgID: 1})
+	require.NoError(t, err)
+	require.Len(t, instances, 2)
+
+	instances, err = st.ListAlertInstances(ctx, &models.ListAlertInstancesQuery{RuleOrgID: 1, RuleUID: "rule-1"})
+	require.NoError(t, err)
+	require.Len(t, instances, 1)
+	require.Equal(t, "rule-1", instances[0].RuleUID)
+	require.Equal(t, models.InstanceStateFiring, instances[0].CurrentState)
+	require.Equal(t, models.InstanceLabels{"a": "1"}, instances[0].Labels)
+	require.Equal(t, &firedAt, instances[0].FiredAt)
+}
+
 func setCacheID(s *state.State) *state.State {
 	if s.CacheID != 0 {
 		return s
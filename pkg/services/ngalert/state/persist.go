@@ -3,6 +3,7 @@ package state
 import (
 	"context"
 
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	history_model "github.com/grafana/grafana/pkg/services/ngalert/state/historian/model"
 )
@@ -51,3 +52,10 @@ type Historian interface {
 type ImageCapturer interface {
 	NewImage(ctx context.Context, r *models.AlertRule) (*models.Image, error)
 }
+
+// ResultsImageCapturer is an ImageCapturer that can also draw images from the results of an evaluation.
+// It is used instead of NewImage if the image capturer implements it.
+type ResultsImageCapturer interface {
+	ImageCapturer
+	NewImageFromResults(ctx context.Context, r *models.AlertRule, results eval.Results, firing models.TimeWindow) (*models.Image, error)
+}
@@ -57,6 +57,7 @@ func (a *SyncRuleStatePersister) Sync(ctx context.Context, span trace.Span, rule
 			LastSentAt:        s.LastSentAt,
 			ResultFingerprint: s.ResultFingerprint.String(),
 		}
+		instance.SetAcknowledgement(s.Acknowledgement)
 
 		instancesToSave = append(instancesToSave, instance)
 	}
@@ -0,0 +1,238 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/prometheus/model/value"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+)
+
+const (
+	// AlertsSeriesName is the name of the series that has the value 1 for each pending or firing alert instance,
+	// the same as the one that Prometheus writes for its alerting rules.
+	AlertsSeriesName = "ALERTS"
+	// AlertsForStateSeriesName is the name of the series whose value is the time, in seconds since the epoch,
+	// when each pending or firing alert instance became active, that is, when it became pending, or firing if it
+	// was not pending before.
+	AlertsForStateSeriesName = "ALERTS_FOR_STATE"
+	// AlertStateLabel is the label of the ALERTS series that contains the state of the alert instance.
+	AlertStateLabel = "alertstate"
+	// RuleUIDSeriesLabel is the label of the series that contains the UID of the alert rule. The private label with
+	// the UID cannot be written, and without it the alert instances of different rules with the same labels would
+	// write the same series.
+	RuleUIDSeriesLabel = "grafana_rule_uid"
+)
+
+// SeriesWriter writes series to a remote write target.
+type SeriesWriter interface {
+	WriteDatasource(ctx context.Context, dsUID string, name string, t time.Time, frames data.Frames, orgID int64, extraLabels map[string]string) error
+}
+
+// StatesReader returns the states of the alert instances of all organizations.
+type StatesReader interface {
+	GetAllOrgStates() map[int64][]*State
+}
+
+type SeriesExporterCfg struct {
+	// Interval is how often the series are written.
+	Interval time.Duration
+	// DatasourceUID is the data source that the series are written to. If empty, the default target of the writer is used.
+	DatasourceUID string
+	Writer        SeriesWriter
+	Clock         clock.Clock
+	Log           log.Logger
+}
+
+// SeriesExporter periodically writes the ALERTS and ALERTS_FOR_STATE series of the pending and firing alert instances,
+// so that the state of Grafana-managed alert rules can be queried like the state of Prometheus alerting rules.
+// When an alert instance is not pending or firing anymore, a stale marker is written for its series.
+type SeriesExporter struct {
+	interval time.Duration
+	dsUID    string
+	writer   SeriesWriter
+	states   StatesReader
+	clock    clock.Clock
+	log      log.Logger
+
+	// written are the label sets of the series that were written by the last export, by organization and series name.
+	written map[int64]map[string]map[data.Fingerprint]data.Labels
+}
+
+func NewSeriesExporter(cfg SeriesExporterCfg, states StatesReader) *SeriesExporter {
+	return &SeriesExporter{
+		interval: cfg.Interval,
+		dsUID:    cfg.DatasourceUID,
+		writer:   cfg.Writer,
+		states:   states,
+		clock:    cfg.Clock,
+		log:      cfg.Log,
+		written:  map[int64]map[string]map[data.Fingerprint]data.Labels{},
+	}
+}
+
+// Run writes the series at every interval until the context is cancelled.
+func (e *SeriesExporter) Run(ctx context.Context) error {
+	e.log.Info("Starting export of alert state series", "interval", e.interval)
+	ticker := e.clock.Ticker(e.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			e.log.Info("Stopping export of alert state series")
+			return nil
+		case now := <-ticker.C:
+			if err := e.export(ctx, now); err != nil {
+				e.log.Error("Failed to export alert state series", "error", err)
+			}
+		}
+	}
+}
+
+func (e *SeriesExporter) export(ctx context.Context, now time.Time) error {
+	series := alertStateSeries(e.states.GetAllOrgStates())
+
+	var errs []error
+	for orgID := range e.written {
+		if _, ok := series[orgID]; !ok {
+			series[orgID] = map[string]map[data.Fingerprint]seriesSample{}
+		}
+	}
+	for orgID, byName := range series {
+		for _, name := range []string{AlertsSeriesName, AlertsForStateSeriesName} {
+			samples := byName[name]
+			// Mark the series that are not written anymore as stale, so that queries stop returning them immediately.
+			for fp, lbls := range e.written[orgID][name] {
+				if _, ok := samples[fp]; !ok {
+					if samples == nil {
+						samples = map[data.Fingerprint]seriesSample{}
+					}
+					samples[fp] = seriesSample{labels: lbls, value: value.StaleNaN}
+				}
+			}
+			if len(samples) == 0 {
+				continue
+			}
+			if err := e.writer.WriteDatasource(ctx, e.dsUID, name, now, seriesFrames(samples), orgID, nil); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			e.setWritten(orgID, name, samples)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (e *SeriesExporter) setWritten(orgID int64, name string, samples map[data.Fingerprint]seriesSample) {
+	written := make(map[data.Fingerprint]data.Labels, len(samples))
+	for fp, s := range samples {
+		if value.IsStaleNaN(s.value) {
+			continue
+		}
+		written[fp] = s.labels
+	}
+	if len(written) == 0 {
+		delete(e.written[orgID], name)
+		if len(e.written[orgID]) == 0 {
+			delete(e.written, orgID)
+		}
+		return
+	}
+	if _, ok := e.written[orgID]; !ok {
+		e.written[orgID] = map[string]map[data.Fingerprint]data.Labels{}
+	}
+	e.written[orgID][name] = written
+}
+
+type seriesSample struct {
+	labels data.Labels
+	value  float64
+}
+
+// alertStateSeries returns the samples of the ALERTS and ALERTS_FOR_STATE series of the pending and firing alert
+// instances, by organization, series name and fingerprint of the labels.
+func alertStateSeries(states map[int64][]*State) map[int64]map[string]map[data.Fingerprint]seriesSample {
+	result := make(map[int64]map[string]map[data.Fingerprint]seriesSample, len(states))
+	for orgID, orgStates := range states {
+		alerts := map[data.Fingerprint]seriesSample{}
+		forState := map[data.Fingerprint]seriesSample{}
+		for _, s := range orgStates {
+			alertState, ok := alertStateLabelValue(s.State)
+			if !ok {
+				continue
+			}
+			lbls := seriesLabels(s.AlertRuleUID, s.Labels)
+			forState[lbls.Fingerprint()] = seriesSample{labels: lbls, value: float64(activeAt(s).Unix())}
+
+			lbls = lbls.Copy()
+			lbls[AlertStateLabel] = alertState
+			alerts[lbls.Fingerprint()] = seriesSample{labels: lbls, value: 1}
+		}
+		if len(alerts) == 0 {
+			continue
+		}
+		result[orgID] = map[string]map[data.Fingerprint]seriesSample{
+			AlertsSeriesName:         alerts,
+			AlertsForStateSeriesName: forState,
+		}
+	}
+	return result
+}
+
+// alertStateLabelValue returns the value of the alertstate label for the state, and false if the alert instance
+// of the state is neither pending nor firing.
+func alertStateLabelValue(s eval.State) (string, bool) {
+	switch s {
+	case eval.Pending:
+		return "pending", true
+	case eval.Alerting, eval.Recovering:
+		return "firing", true
+	default:
+		return "", false
+	}
+}
+
+// activeAt returns the time when the alert instance of the state became active. StartsAt is used for the states
+// restored from the database that have not changed since.
+func activeAt(s *State) time.Time {
+	if s.ActiveAt != nil {
+		return *s.ActiveAt
+	}
+	return s.StartsAt
+}
+
+// seriesLabels returns the labels of the alert instance without the private labels, whose names start with "__"
+// because they are reserved in Prometheus, and with the UID of the alert rule.
+func seriesLabels(ruleUID string, lbls data.Labels) data.Labels {
+	result := make(data.Labels, len(lbls)+1)
+	for k, v := range lbls {
+		if strings.HasPrefix(k, "__") {
+			continue
+		}
+		result[k] = v
+	}
+	if ruleUID != "" {
+		result[RuleUIDSeriesLabel] = ruleUID
+	}
+	return result
+}
+
+// seriesFrames converts the samples to numeric multi frames, one frame per series.
+func seriesFrames(samples map[data.Fingerprint]seriesSample) data.Frames {
+	frames := make(data.Frames, 0, len(samples))
+	for _, s := range samples {
+		frame := data.NewFrame("", data.NewField("", s.labels, []float64{s.value}))
+		frame.SetMeta(&data.FrameMeta{
+			Type:        data.FrameTypeNumericMulti,
+			TypeVersion: data.FrameTypeVersion{0, 1},
+		})
+		frames = append(frames, frame)
+	}
+	return frames
+}
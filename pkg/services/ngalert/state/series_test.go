@@ -0,0 +1,157 @@
+package state
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+)
+
+type fakeStatesReader struct {
+	states map[int64][]*State
+}
+
+func (f *fakeStatesReader) GetAllOrgStates() map[int64][]*State {
+	return f.states
+}
+
+type fakeSeriesWrite struct {
+	orgID  int64
+	name   string
+	frames data.Frames
+}
+
+type fakeSeriesWriter struct {
+	writes []fakeSeriesWrite
+}
+
+func (f *fakeSeriesWriter) WriteDatasource(_ context.Context, _ string, name string, _ time.Time, frames data.Frames, orgID int64, _ map[string]string) error {
+	f.writes = append(f.writes, fakeSeriesWrite{orgID: orgID, name: name, frames: frames})
+	return nil
+}
+
+// samples returns the values of the series written with the given name, by the string representation of their labels.
+func (f *fakeSeriesWriter) samples(name string) map[string]float64 {
+	result := map[string]float64{}
+	for _, w := range f.writes {
+		if w.name != name {
+			continue
+		}
+		for _, frame := range w.frames {
+			result[frame.Fields[0].Labels.String()] = frame.Fields[0].At(0).(float64)
+		}
+	}
+	return result
+}
+
+func TestSeriesExporter(t *testing.T) {
+	startsAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := startsAt.Add(time.Hour)
+
+	newExporter := func(states map[int64][]*State) (*SeriesExporter, *fakeStatesReader, *fakeSeriesWriter) {
+		reader := &fakeStatesReader{states: states}
+		writer := &fakeSeriesWriter{}
+		return NewSeriesExporter(SeriesExporterCfg{Interval: time.Minute, Writer: writer, Log: log.NewNopLogger()}, reader), reader, writer
+	}
+
+	t.Run("writes the series of pending and firing alert instances", func(t *testing.T) {
+		e, _, writer := newExporter(map[int64][]*State{
+			1: {
+				{State: eval.Pending, AlertRuleUID: "uid", Labels: data.Labels{"alertname": "a", "__alert_rule_uid__": "uid"}, StartsAt: startsAt},
+				{State: eval.Alerting, Labels: data.Labels{"alertname": "b"}, StartsAt: startsAt},
+				{State: eval.Recovering, Labels: data.Labels{"alertname": "c"}, StartsAt: startsAt},
+				{State: eval.Normal, Labels: data.Labels{"alertname": "d"}, StartsAt: startsAt},
+			},
+		})
+
+		require.NoError(t, e.export(context.Background(), now))
+
+		require.Equal(t, map[string]float64{
+			"alertname=a, alertstate=pending, grafana_rule_uid=uid": 1,
+			"alertname=b, alertstate=firing":                        1,
+			"alertname=c, alertstate=firing":                        1,
+		}, writer.samples(AlertsSeriesName))
+		require.Equal(t, map[string]float64{
+			"alertname=a, grafana_rule_uid=uid": float64(startsAt.Unix()),
+			"alertname=b":                       float64(startsAt.Unix()),
+			"alertname=c":                       float64(startsAt.Unix()),
+		}, writer.samples(AlertsForStateSeriesName))
+		for _, w := range writer.writes {
+			require.Equal(t, int64(1), w.orgID)
+		}
+	})
+
+	t.Run("writes the time when the alert instance became pending for firing alert instances", func(t *testing.T) {
+		s := &State{Labels: data.Labels{"alertname": "a"}}
+		s.SetPending("", startsAt, startsAt.Add(time.Minute))
+		s.SetAlerting("", startsAt.Add(5*time.Minute), startsAt.Add(6*time.Minute))
+		s.SetRecovering("", startsAt.Add(10*time.Minute), startsAt.Add(11*time.Minute))
+		e, _, writer := newExporter(map[int64][]*State{1: {s}})
+
+		require.NoError(t, e.export(context.Background(), now))
+
+		require.Equal(t, map[string]float64{
+			"alertname=a": float64(startsAt.Unix()),
+		}, writer.samples(AlertsForStateSeriesName))
+	})
+
+	t.Run("writes nothing if there are no pending or firing alert instances", func(t *testing.T) {
+		e, _, writer := newExporter(map[int64][]*State{
+			1: {{State: eval.Normal, Labels: data.Labels{"alertname": "a"}}},
+		})
+
+		require.NoError(t, e.export(context.Background(), now))
+		require.Empty(t, writer.writes)
+	})
+
+	t.Run("writes stale markers for series of alert instances that are not active anymore", func(t *testing.T) {
+		e, reader, writer := newExporter(map[int64][]*State{
+			1: {
+				{State: eval.Pending, Labels: data.Labels{"alertname": "a"}, StartsAt: startsAt},
+				{State: eval.Alerting, Labels: data.Labels{"alertname": "b"}, StartsAt: startsAt},
+			},
+		})
+		require.NoError(t, e.export(context.Background(), now))
+
+		// a became firing and b resolved.
+		reader.states = map[int64][]*State{
+			1: {
+				{State: eval.Alerting, Labels: data.Labels{"alertname": "a"}, StartsAt: startsAt},
+				{State: eval.Normal, Labels: data.Labels{"alertname": "b"}, StartsAt: startsAt},
+			},
+		}
+		writer.writes = nil
+		require.NoError(t, e.export(context.Background(), now.Add(time.Minute)))
+
+		alerts := writer.samples(AlertsSeriesName)
+		require.Len(t, alerts, 3)
+		require.Equal(t, float64(1), alerts["alertname=a, alertstate=firing"])
+		require.True(t, value.IsStaleNaN(alerts["alertname=a, alertstate=pending"]))
+		require.True(t, value.IsStaleNaN(alerts["alertname=b, alertstate=firing"]))
+
+		forState := writer.samples(AlertsForStateSeriesName)
+		require.Len(t, forState, 2)
+		require.Equal(t, float64(startsAt.Unix()), forState["alertname=a"])
+		require.True(t, value.IsStaleNaN(forState["alertname=b"]))
+
+		// Stale markers are written only once.
+		reader.states = map[int64][]*State{}
+		writer.writes = nil
+		require.NoError(t, e.export(context.Background(), now.Add(2*time.Minute)))
+		alerts = writer.samples(AlertsSeriesName)
+		require.Len(t, alerts, 1)
+		require.True(t, math.IsNaN(alerts["alertname=a, alertstate=firing"]))
+
+		writer.writes = nil
+		require.NoError(t, e.export(context.Background(), now.Add(3*time.Minute)))
+		require.Empty(t, writer.writes)
+		require.Empty(t, e.written)
+	})
+}
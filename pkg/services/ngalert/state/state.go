@@ -66,6 +66,11 @@ type State struct {
 	// FiredAt is the time the state first transitions to Alerting.
 	FiredAt *time.Time
 
+	// ActiveAt is the time the state transitions to Pending, or to Alerting if it was not Pending before.
+	// It is kept while the state is Pending, Alerting or Recovering, and is reset when the state transitions to any
+	// other state. It is not persisted.
+	ActiveAt *time.Time
+
 	StartsAt time.Time
 	// EndsAt is different from the Prometheus EndsAt as EndsAt is updated for both Normal states
 	// and states that have been resolved. It cannot be used to determine when a state was resolved.
@@ -78,6 +83,14 @@ type State struct {
 	LastEvaluationString string
 	LastEvaluationTime   time.Time
 	EvaluationDuration   time.Duration
+
+	// HysteresisStreak is the streak of the latest result if the condition of the rule is a recovery threshold
+	// that is holding back the transition of the state. It is not persisted.
+	HysteresisStreak *expr.HysteresisStreak
+
+	// Acknowledgement is set when a user acknowledged the firing state. It is cleared
+	// when the state resolves or when the acknowledgement expires.
+	Acknowledgement *models.AlertInstanceAcknowledgement
 }
 
 func newState(ctx context.Context, log log.Logger, alertRule *models.AlertRule, result eval.Result, extraLabels data.Labels, externalURL *url.URL) *State {
@@ -133,11 +146,14 @@ func (a *State) Copy() *State {
 		StartsAt:             a.StartsAt,
 		EndsAt:               a.EndsAt,
 		FiredAt:              a.FiredAt,
+		ActiveAt:             a.ActiveAt,
 		ResolvedAt:           a.ResolvedAt,
 		LastSentAt:           a.LastSentAt,
 		LastEvaluationString: a.LastEvaluationString,
 		LastEvaluationTime:   a.LastEvaluationTime,
 		EvaluationDuration:   a.EvaluationDuration,
+		HysteresisStreak:     a.HysteresisStreak,
+		Acknowledgement:      a.Acknowledgement,
 	}
 }
 
@@ -159,6 +175,14 @@ func (a *State) GetAlertInstanceKey() (models.AlertInstanceKey, error) {
 
 // SetAlerting sets the state to Alerting. It changes both the start and end time.
 func (a *State) SetAlerting(reason string, startsAt, endsAt time.Time) {
+	if a.ActiveAt == nil {
+		// A state restored from the database does not have ActiveAt, but a pending state started when it became active.
+		activeAt := startsAt
+		if a.State == eval.Pending {
+			activeAt = a.StartsAt
+		}
+		a.ActiveAt = &activeAt
+	}
 	a.State = eval.Alerting
 	a.StateReason = reason
 	a.StartsAt = startsAt
@@ -176,6 +200,7 @@ func (a *State) SetPending(reason string, startsAt, endsAt time.Time) {
 	a.StartsAt = startsAt
 	a.EndsAt = endsAt
 	a.Error = nil
+	a.ActiveAt = &startsAt
 }
 
 // SetRecovering sets the state to Recovering. It changes both the start and end time.
@@ -194,6 +219,7 @@ func (a *State) SetNoData(reason string, startsAt, endsAt time.Time) {
 	a.StartsAt = startsAt
 	a.EndsAt = endsAt
 	a.Error = nil
+	a.ActiveAt = nil
 }
 
 // SetError sets the state to Error. It changes both the start and end time.
@@ -203,6 +229,17 @@ func (a *State) SetError(err error, startsAt, endsAt time.Time) {
 	a.StartsAt = startsAt
 	a.EndsAt = endsAt
 	a.Error = err
+	a.ActiveAt = nil
+}
+
+// SetSuppressed sets the state to Suppressed. It changes both the start and end time.
+func (a *State) SetSuppressed(reason string, startsAt, endsAt time.Time) {
+	a.State = eval.Suppressed
+	a.StateReason = reason
+	a.StartsAt = startsAt
+	a.EndsAt = endsAt
+	a.Error = nil
+	a.ActiveAt = nil
 }
 
 // SetNormal sets the state to Normal. It changes both the start and end time.
@@ -212,6 +249,7 @@ func (a *State) SetNormal(reason string, startsAt, endsAt time.Time) {
 	a.StartsAt = startsAt
 	a.EndsAt = endsAt
 	a.Error = nil
+	a.ActiveAt = nil
 }
 
 // Maintain updates the end time using the most recent evaluation.
@@ -285,6 +323,15 @@ type StateTransition struct {
 	*State
 	PreviousState       eval.State
 	PreviousStateReason string
+	// AcknowledgementChange is set if the acknowledgement of the state was added, removed or expired.
+	AcknowledgementChange *AcknowledgementChange
+}
+
+// AcknowledgementChange describes a change of the acknowledgement of a state.
+// The current acknowledgement, if any, is State.Acknowledgement.
+type AcknowledgementChange struct {
+	Time     time.Time
+	Previous *models.AlertInstanceAcknowledgement
 }
 
 func (c StateTransition) Formatted() string {
@@ -592,9 +639,9 @@ func (a *State) NeedsSending(resendDelay time.Duration, resolvedRetention time.D
 		return true
 	}
 
-	// For normal states, we should only be sending if this is a resolved notification or a re-send of the resolved
-	// notification within the resolvedRetention period.
-	if a.State == eval.Normal && (a.ResolvedAt == nil || a.LastEvaluationTime.Sub(*a.ResolvedAt) > resolvedRetention) {
+	// For normal and suppressed states, we should only be sending if this is a resolved notification or a re-send of
+	// the resolved notification within the resolvedRetention period.
+	if (a.State == eval.Normal || a.State == eval.Suppressed) && (a.ResolvedAt == nil || a.LastEvaluationTime.Sub(*a.ResolvedAt) > resolvedRetention) {
 		return false
 	}
 
@@ -666,11 +713,12 @@ func (a *State) ShouldBeResolved(oldState eval.State) bool {
 		return false
 	}
 
-	if oldState != eval.Alerting && oldState != eval.Error && oldState != eval.NoData && oldState != eval.Recovering {
-		return false
-	}
+	return isFiringState(oldState)
+}
 
-	return true
+// isFiringState returns true if alerts in the state are sent to the Alertmanager as firing alerts.
+func isFiringState(s eval.State) bool {
+	return s == eval.Alerting || s == eval.Error || s == eval.NoData || s == eval.Recovering
 }
 
 // shouldTakeImage determines whether a new image should be taken for a given transition. This should return true when
@@ -694,9 +742,16 @@ func shouldTakeImage(state, previousState eval.State, previousImage *models.Imag
 }
 
 // takeImage takes an image for the alert rule. It returns nil if screenshots are disabled or
-// the rule is not associated with a dashboard panel.
-func takeImage(ctx context.Context, s ImageCapturer, r *models.AlertRule) (*models.Image, error) {
-	img, err := s.NewImage(ctx, r)
+// the rule is not associated with a dashboard panel. If the image capturer can draw images from
+// the results of the evaluation, the results and the firing window are passed to it.
+func takeImage(ctx context.Context, s ImageCapturer, r *models.AlertRule, results eval.Results, firing models.TimeWindow) (*models.Image, error) {
+	var img *models.Image
+	var err error
+	if rs, ok := s.(ResultsImageCapturer); ok {
+		img, err = rs.NewImageFromResults(ctx, r, results, firing)
+	} else {
+		img, err = s.NewImage(ctx, r)
+	}
 	if err != nil {
 		if errors.Is(err, screenshot.ErrScreenshotsUnavailable) ||
 			errors.Is(err, models.ErrNoDashboard) ||
@@ -708,6 +763,19 @@ func takeImage(ctx context.Context, s ImageCapturer, r *models.AlertRule) (*mode
 	return img, nil
 }
 
+// firingWindow returns the window in which the state was firing. The end of the window is zero if the state
+// has not been resolved.
+func (a *State) firingWindow() models.TimeWindow {
+	var w models.TimeWindow
+	if a.FiredAt != nil {
+		w.Start = *a.FiredAt
+	}
+	if a.ResolvedAt != nil {
+		w.End = *a.ResolvedAt
+	}
+	return w
+}
+
 func FormatStateAndReason(state eval.State, reason string) string {
 	s := fmt.Sprintf("%v", state)
 	if len(reason) > 0 {
@@ -781,8 +849,10 @@ func patch(newState, existingState *State, result eval.Result) {
 	newState.StartsAt = existingState.StartsAt
 	newState.EndsAt = existingState.EndsAt
 	newState.FiredAt = existingState.FiredAt
+	newState.ActiveAt = existingState.ActiveAt
 	newState.ResolvedAt = existingState.ResolvedAt
 	newState.LastSentAt = existingState.LastSentAt
+	newState.Acknowledgement = existingState.Acknowledgement
 	// Annotations can change over time, however we also want to maintain
 	// certain annotations across evaluations
 	for key := range models.InternalAnnotationNameSet { // Changing in
@@ -812,7 +882,9 @@ func patch(newState, existingState *State, result eval.Result) {
 	}
 }
 
-func (a *State) transition(alertRule *models.AlertRule, result eval.Result, extraAnnotations data.Labels, logger log.Logger, takeImageFn takeImageFn) StateTransition {
+// transition sets the next state from the result. If suppressed is true, because a dependency of the rule is firing,
+// a state that is not Normal is set to Suppressed with the state it would have had as the reason.
+func (a *State) transition(alertRule *models.AlertRule, result eval.Result, extraAnnotations data.Labels, logger log.Logger, takeImageFn takeImageFn, suppressed bool) StateTransition {
 	a.LastEvaluationTime = result.EvaluatedAt
 	a.EvaluationDuration = result.EvaluationDuration
 	a.SetNextValues(result)
@@ -823,8 +895,11 @@ func (a *State) transition(alertRule *models.AlertRule, result eval.Result, extr
 		Condition:       alertRule.Condition,
 	}
 	a.LastEvaluationString = result.EvaluationString
+	a.HysteresisStreak = result.HysteresisStreak
 	oldState := a.State
 	oldReason := a.StateReason
+	oldStartsAt := a.StartsAt
+	oldFiredAt := a.FiredAt
 
 	// Add the instance to the log context to help correlate log lines for a state
 	logger = logger.New("instance", result.Instance)
@@ -850,6 +925,15 @@ func (a *State) transition(alertRule *models.AlertRule, result eval.Result, extr
 		}
 	}
 
+	if a.State == eval.Suppressed {
+		// A suppressed state evolves as the state it would have had, which is kept as the reason.
+		underlying, err := eval.ParseStateString(a.StateReason)
+		if err != nil {
+			underlying = eval.Normal
+		}
+		a.State = underlying
+	}
+
 	switch result.State {
 	case eval.Normal:
 		logger.Debug("Setting next state", "handler", "resultNormal")
@@ -864,7 +948,8 @@ func (a *State) transition(alertRule *models.AlertRule, result eval.Result, extr
 		logger.Debug("Setting next state", "handler", "resultNoData")
 		resultNoData(a, alertRule, result, logger)
 	case eval.Pending,
-		eval.Recovering: // we do not emit results with these states
+		eval.Recovering,
+		eval.Suppressed: // we do not emit results with these states
 		logger.Debug("Ignoring set next state", "state", result.State)
 	}
 
@@ -877,18 +962,40 @@ func (a *State) transition(alertRule *models.AlertRule, result eval.Result, extr
 		a.StateReason = resultStateReason(result, alertRule)
 	}
 
+	if suppressed && a.State != eval.Normal {
+		// The suppression starts when the state is first suppressed, and the state has not fired while it was suppressed.
+		startsAt := result.EvaluatedAt
+		if oldState == eval.Suppressed {
+			startsAt = oldStartsAt
+		}
+		logger.Debug("Suppressing state", "state", a.State)
+		a.SetSuppressed(a.State.String(), startsAt, result.EvaluatedAt)
+		a.FiredAt = oldFiredAt
+	} else if oldState == eval.Suppressed && a.State != eval.Normal {
+		// The state was resolved when it was suppressed, so it starts again when the suppression ends.
+		startsAt := result.EvaluatedAt
+		a.StartsAt = startsAt
+		if a.State == eval.Alerting {
+			a.FiredAt = &startsAt
+		}
+	}
+
 	// Set Resolved property so the scheduler knows to send a postable alert
 	// to Alertmanager.
 	newlyResolved := false
 	if oldState == eval.Alerting && a.State == eval.Normal || oldState == eval.Recovering && a.State == eval.Normal {
 		a.ResolvedAt = &result.EvaluatedAt
 		newlyResolved = true
-	} else if a.State != eval.Normal && a.State != eval.Pending { // Retain the last resolved time for Normal->Normal and Normal->Pending.
+	} else if a.State == eval.Suppressed && isFiringState(oldState) {
+		// Alerts that are suppressed are resolved in the Alertmanager so that no more notifications are sent for them.
+		a.ResolvedAt = &result.EvaluatedAt
+		newlyResolved = true
+	} else if a.State != eval.Normal && a.State != eval.Pending && a.State != eval.Suppressed { // Retain the last resolved time for Normal->Normal, Normal->Pending and Suppressed.
 		a.ResolvedAt = nil
 	}
 
 	if reason := shouldTakeImage(a.State, oldState, a.Image, newlyResolved); reason != "" {
-		image := takeImageFn(reason)
+		image := takeImageFn(reason, a)
 		if image != nil {
 			a.Image = image
 		}
@@ -47,6 +47,7 @@ func TestSetAlerting(t *testing.T) {
 			StartsAt:    mock.Now(),
 			EndsAt:      mock.Now().Add(time.Minute),
 			FiredAt:     util.Pointer(mock.Now()),
+			ActiveAt:    util.Pointer(mock.Now()),
 		},
 	}, {
 		name: "previous state is removed",
@@ -62,6 +63,38 @@ func TestSetAlerting(t *testing.T) {
 			StartsAt: mock.Now(),
 			EndsAt:   mock.Now().Add(time.Minute),
 			FiredAt:  util.Pointer(mock.Now()),
+			ActiveAt: util.Pointer(mock.Now()),
+		},
+	}, {
+		name: "active time of pending state is kept",
+		state: State{
+			State:    eval.Pending,
+			StartsAt: mock.Now(),
+			ActiveAt: util.Pointer(mock.Now()),
+		},
+		startsAt: mock.Now().Add(time.Minute),
+		endsAt:   mock.Now().Add(2 * time.Minute),
+		expected: State{
+			State:    eval.Alerting,
+			StartsAt: mock.Now().Add(time.Minute),
+			EndsAt:   mock.Now().Add(2 * time.Minute),
+			FiredAt:  util.Pointer(mock.Now().Add(time.Minute)),
+			ActiveAt: util.Pointer(mock.Now()),
+		},
+	}, {
+		name: "active time of restored pending state is its start time",
+		state: State{
+			State:    eval.Pending,
+			StartsAt: mock.Now(),
+		},
+		startsAt: mock.Now().Add(time.Minute),
+		endsAt:   mock.Now().Add(2 * time.Minute),
+		expected: State{
+			State:    eval.Alerting,
+			StartsAt: mock.Now().Add(time.Minute),
+			EndsAt:   mock.Now().Add(2 * time.Minute),
+			FiredAt:  util.Pointer(mock.Now().Add(time.Minute)),
+			ActiveAt: util.Pointer(mock.Now()),
 		},
 	}}
 
@@ -93,6 +126,7 @@ func TestSetPending(t *testing.T) {
 			StateReason: "this is a reason",
 			StartsAt:    mock.Now(),
 			EndsAt:      mock.Now().Add(time.Minute),
+			ActiveAt:    util.Pointer(mock.Now()),
 		},
 	}, {
 		name: "previous state is removed",
@@ -107,6 +141,7 @@ func TestSetPending(t *testing.T) {
 			State:    eval.Pending,
 			StartsAt: mock.Now(),
 			EndsAt:   mock.Now().Add(time.Minute),
+			ActiveAt: util.Pointer(mock.Now()),
 		},
 	}}
 
@@ -636,7 +671,7 @@ func TestTakeImage(t *testing.T) {
 		s := NewMockImageCapturer(ctrl)
 
 		s.EXPECT().NewImage(ctx, &r).Return(nil, ngmodels.ErrNoDashboard)
-		image, err := takeImage(ctx, s, &r)
+		image, err := takeImage(ctx, s, &r, nil, ngmodels.TimeWindow{})
 		assert.NoError(t, err)
 		assert.Nil(t, image)
 	})
@@ -650,7 +685,7 @@ func TestTakeImage(t *testing.T) {
 		s := NewMockImageCapturer(ctrl)
 
 		s.EXPECT().NewImage(ctx, &r).Return(nil, ngmodels.ErrNoPanel)
-		image, err := takeImage(ctx, s, &r)
+		image, err := takeImage(ctx, s, &r, nil, ngmodels.TimeWindow{})
 		assert.NoError(t, err)
 		assert.Nil(t, image)
 	})
@@ -664,7 +699,7 @@ func TestTakeImage(t *testing.T) {
 		s := NewMockImageCapturer(ctrl)
 
 		s.EXPECT().NewImage(ctx, &r).Return(nil, screenshot.ErrScreenshotsUnavailable)
-		image, err := takeImage(ctx, s, &r)
+		image, err := takeImage(ctx, s, &r, nil, ngmodels.TimeWindow{})
 		assert.NoError(t, err)
 		assert.Nil(t, image)
 	})
@@ -678,7 +713,7 @@ func TestTakeImage(t *testing.T) {
 		s := NewMockImageCapturer(ctrl)
 
 		s.EXPECT().NewImage(ctx, &r).Return(nil, errors.New("unknown error"))
-		image, err := takeImage(ctx, s, &r)
+		image, err := takeImage(ctx, s, &r, nil, ngmodels.TimeWindow{})
 		assert.EqualError(t, err, "unknown error")
 		assert.Nil(t, image)
 	})
@@ -692,11 +727,42 @@ func TestTakeImage(t *testing.T) {
 		s := NewMockImageCapturer(ctrl)
 
 		s.EXPECT().NewImage(ctx, &r).Return(&ngmodels.Image{Path: "foo.png"}, nil)
-		image, err := takeImage(ctx, s, &r)
+		image, err := takeImage(ctx, s, &r, nil, ngmodels.TimeWindow{})
 		assert.NoError(t, err)
 		require.NotNil(t, image)
 		assert.Equal(t, ngmodels.Image{Path: "foo.png"}, *image)
 	})
+
+	t.Run("image should be drawn from the results if supported", func(t *testing.T) {
+		ctx := context.Background()
+		r := ngmodels.AlertRule{}
+		results := eval.Results{{State: eval.Alerting}}
+		firing := ngmodels.TimeWindow{Start: time.Now()}
+		s := &fakeResultsImageCapturer{image: &ngmodels.Image{Path: "chart.png"}}
+
+		image, err := takeImage(ctx, s, &r, results, firing)
+		assert.NoError(t, err)
+		require.NotNil(t, image)
+		assert.Equal(t, ngmodels.Image{Path: "chart.png"}, *image)
+		assert.Equal(t, results, s.results)
+		assert.Equal(t, firing, s.firing)
+	})
+}
+
+type fakeResultsImageCapturer struct {
+	image   *ngmodels.Image
+	results eval.Results
+	firing  ngmodels.TimeWindow
+}
+
+func (f *fakeResultsImageCapturer) NewImage(_ context.Context, _ *ngmodels.AlertRule) (*ngmodels.Image, error) {
+	return nil, errors.New("unexpected call")
+}
+
+func (f *fakeResultsImageCapturer) NewImageFromResults(_ context.Context, _ *ngmodels.AlertRule, results eval.Results, firing ngmodels.TimeWindow) (*ngmodels.Image, error) {
+	f.results = results
+	f.firing = firing
+	return f.image, nil
 }
 
 func TestParseFormattedState(t *testing.T) {
@@ -0,0 +1,200 @@
+package statearchive
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/matttproud/golang_protobuf_extensions/pbutil"
+	"github.com/prometheus/alertmanager/nflog/nflogpb"
+	prometheusModel "github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+// labelsChange is the change of the labels of an alert instance made by the import.
+type labelsChange struct {
+	from models.InstanceLabels
+	to   models.InstanceLabels
+}
+
+type labelValue struct {
+	name  prometheusModel.LabelName
+	value prometheusModel.LabelValue
+}
+
+// remapNotificationLog returns the notification log in the binary format of the Alertmanager with the alerts whose
+// labels are changed by the import replaced by their new labels. The Alertmanager identifies an alert in the
+// notification log by the hash of its labels, and a group by the values of its group labels, so the alerts of the
+// alert rules mapped to other UIDs would be notified again otherwise. The notification log is returned unchanged if
+// no labels are changed.
+func remapNotificationLog(content []byte, changes []labelsChange) ([]byte, error) {
+	if len(content) == 0 || len(changes) == 0 {
+		return content, nil
+	}
+
+	hashes := make(map[uint64]uint64)
+	// values maps the old value of a label to the new one. A value that is changed to different values by
+	// different instances is not replaced in group keys.
+	values := make(map[labelValue]prometheusModel.LabelValue)
+	ambiguous := make(map[labelValue]struct{})
+	for _, c := range changes {
+		from, to := alertLabels(c.from), alertLabels(c.to)
+		for i := range from {
+			hashes[alertHash(from[i])] = alertHash(to[i])
+		}
+		for name, value := range c.from {
+			newValue, ok := c.to[name]
+			if !ok || newValue == value {
+				continue
+			}
+			key := labelValue{name: prometheusModel.LabelName(name), value: prometheusModel.LabelValue(value)}
+			if v, ok := values[key]; ok && v != prometheusModel.LabelValue(newValue) {
+				ambiguous[key] = struct{}{}
+			}
+			values[key] = prometheusModel.LabelValue(newValue)
+		}
+	}
+	for key := range ambiguous {
+		delete(values, key)
+	}
+
+	var out bytes.Buffer
+	r := bytes.NewReader(content)
+	for {
+		var e nflogpb.MeshEntry
+		_, err := pbutil.ReadDelimited(r, &e)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, ErrInvalidArchive.Errorf("invalid notification log: %w", err)
+		}
+		if e.Entry == nil {
+			return nil, ErrInvalidArchive.Errorf("invalid notification log: entry is missing")
+		}
+		e.Entry.FiringAlerts = remapHashes(e.Entry.FiringAlerts, hashes)
+		e.Entry.ResolvedAlerts = remapHashes(e.Entry.ResolvedAlerts, hashes)
+		e.Entry.GroupKey = []byte(remapGroupKey(string(e.Entry.GroupKey), values))
+		if _, err := pbutil.WriteDelimited(&out, &e); err != nil {
+			return nil, err
+		}
+	}
+	return out.Bytes(), nil
+}
+
+// alertLabels returns the labels of the alerts that the Alertmanager can receive for an alert instance with the labels:
+// the labels of the instance, and the labels of the DatasourceNoData and DatasourceError alerts of the instance.
+func alertLabels(labels models.InstanceLabels) []prometheusModel.LabelSet {
+	result := make([]prometheusModel.LabelSet, 0, 3)
+	result = append(result, labelSet(labels))
+	for _, alertName := range []string{state.NoDataAlertName, state.ErrorAlertName} {
+		ls := labelSet(labels)
+		if name, ok := labels[prometheusModel.AlertNameLabel]; ok {
+			ls[state.Rulename] = prometheusModel.LabelValue(name)
+		}
+		ls[prometheusModel.AlertNameLabel] = prometheusModel.LabelValue(alertName)
+		result = append(result, ls)
+	}
+	return result
+}
+
+func labelSet(labels models.InstanceLabels) prometheusModel.LabelSet {
+	result := make(prometheusModel.LabelSet, len(labels)+1)
+	for k, v := range labels {
+		result[prometheusModel.LabelName(k)] = prometheusModel.LabelValue(v)
+	}
+	return result
+}
+
+// alertHash returns the hash of the labels of an alert that identifies the alert in the notification log.
+// It is computed the same way as in the notification pipeline of the Alertmanager.
+func alertHash(labels prometheusModel.LabelSet) uint64 {
+	const sep = '\xff'
+	names := make(prometheusModel.LabelNames, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Sort(names)
+
+	b := make([]byte, 0, 1024)
+	for _, name := range names {
+		b = append(b, string(name)...)
+		b = append(b, sep)
+		b = append(b, string(labels[name])...)
+		b = append(b, sep)
+	}
+	return xxhash.Sum64(b)
+}
+
+func remapHashes(hashes []uint64, mapping map[uint64]uint64) []uint64 {
+	for i, h := range hashes {
+		if mapped, ok := mapping[h]; ok {
+			hashes[i] = mapped
+		}
+	}
+	return hashes
+}
+
+// remapGroupKey returns the group key with the values of the group labels replaced. A group key is the key of the
+// route, a colon, and the group labels in the format of model.LabelSet. The key is returned unchanged if the group
+// labels cannot be parsed.
+func remapGroupKey(key string, values map[labelValue]prometheusModel.LabelValue) string {
+	if len(values) == 0 {
+		return key
+	}
+	i := strings.LastIndex(key, "}:{")
+	if i < 0 {
+		return key
+	}
+	labels, ok := parseLabelSet(key[i+2:])
+	if !ok {
+		return key
+	}
+	changed := false
+	for name, value := range labels {
+		if v, ok := values[labelValue{name: name, value: value}]; ok {
+			labels[name] = v
+			changed = true
+		}
+	}
+	if !changed {
+		return key
+	}
+	return key[:i+2] + labels.String()
+}
+
+// parseLabelSet parses labels in the format of model.LabelSet.String.
+func parseLabelSet(s string) (prometheusModel.LabelSet, bool) {
+	if !strings.HasPrefix(s, "{") || !strings.HasSuffix(s, "}") {
+		return nil, false
+	}
+	s = s[1 : len(s)-1]
+	result := prometheusModel.LabelSet{}
+	for s != "" {
+		name, rest, ok := strings.Cut(s, "=")
+		if !ok {
+			return nil, false
+		}
+		quoted, err := strconv.QuotedPrefix(rest)
+		if err != nil {
+			return nil, false
+		}
+		value, err := strconv.Unquote(quoted)
+		if err != nil {
+			return nil, false
+		}
+		result[prometheusModel.LabelName(name)] = prometheusModel.LabelValue(value)
+		rest = rest[len(quoted):]
+		if rest != "" && !strings.HasPrefix(rest, ", ") {
+			return nil, false
+		}
+		s = strings.TrimPrefix(rest, ", ")
+	}
+	return result, true
+}
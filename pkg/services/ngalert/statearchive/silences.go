@@ -0,0 +1,48 @@
+package statearchive
+
+import (
+	"bytes"
+	"errors"
+	"io"
+
+	alertingModels "github.com/grafana/alerting/models"
+	"github.com/matttproud/golang_protobuf_extensions/pbutil"
+	"github.com/prometheus/alertmanager/silence/silencepb"
+)
+
+// remapSilences returns the silences in the binary format of the Alertmanager with the matchers of the alert rule UID
+// label mapped to the UIDs of the alert rules in this instance. The silences are returned unchanged if no rule UIDs
+// are mapped.
+func remapSilences(content []byte, ruleUIDs map[string]string) ([]byte, error) {
+	if len(content) == 0 || len(ruleUIDs) == 0 {
+		return content, nil
+	}
+
+	var out bytes.Buffer
+	r := bytes.NewReader(content)
+	for {
+		var s silencepb.MeshSilence
+		_, err := pbutil.ReadDelimited(r, &s)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, ErrInvalidArchive.Errorf("invalid silences: %w", err)
+		}
+		if s.Silence == nil {
+			return nil, ErrInvalidArchive.Errorf("invalid silences: silence is missing")
+		}
+		for _, m := range s.Silence.Matchers {
+			if m.Name != alertingModels.RuleUIDLabel || m.Type != silencepb.Matcher_EQUAL {
+				continue
+			}
+			if uid, ok := ruleUIDs[m.Pattern]; ok {
+				m.Pattern = uid
+			}
+		}
+		if _, err := pbutil.WriteDelimited(&out, &s); err != nil {
+			return nil, err
+		}
+	}
+	return out.Bytes(), nil
+}
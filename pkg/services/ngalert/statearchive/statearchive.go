@@ -0,0 +1,450 @@
+// Package statearchive exports the state of alerting of a Grafana instance to a portable archive and imports it into
+// another one, so that alerts keep their state and are not notified again when Grafana is moved to another cluster or
+// database. The archive contains the state of the alert instances, and the silences and notification log of the
+// Grafana Alertmanager of each organization.
+package statearchive
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sort"
+
+	"github.com/benbjohnson/clock"
+	alertingModels "github.com/grafana/alerting/models"
+	prometheusModel "github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/folder"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/org"
+)
+
+var ErrInvalidArchive = errutil.ValidationFailed("alerting.state-archive.invalid")
+
+// InstanceWriter overwrites the state of a rule in the instance store.
+type InstanceWriter interface {
+	SaveAlertInstancesForRule(ctx context.Context, key models.AlertRuleKeyWithGroup, instances []models.AlertInstance) error
+}
+
+// FolderReader returns the folders of an organization with their full path, by UID.
+type FolderReader interface {
+	GetUserVisibleNamespaces(ctx context.Context, orgID int64, user identity.Requester) (map[string]*folder.Folder, error)
+}
+
+// StateLoader loads the state of a rule from the instance store into the state cache.
+type StateLoader interface {
+	LoadStateByRuleUID(ctx context.Context, rule *models.AlertRule) int
+}
+
+// Alertmanagers reads and replaces the state of the running Alertmanagers of the organizations.
+type Alertmanagers interface {
+	// AlertmanagerState returns the current silences and notification log of the Alertmanager of an organization.
+	AlertmanagerState(ctx context.Context, orgID int64) ([]byte, []byte, error)
+	// ReplaceAlertmanagerState replaces the state of the Alertmanager of an organization while it is stopped.
+	ReplaceAlertmanagerState(ctx context.Context, orgID int64, replace func(ctx context.Context) error) error
+}
+
+// ImportOptions maps the organizations and alert rules in the archive to the ones in this instance.
+type ImportOptions struct {
+	// OrgIDs maps the IDs of the organizations in the archive to the IDs of the organizations in this instance.
+	OrgIDs map[int64]int64
+	// RuleUIDs maps the UIDs of the alert rules in the archive to the UIDs of the alert rules in this instance.
+	RuleUIDs map[string]string
+}
+
+func (o ImportOptions) orgID(id int64) int64 {
+	if mapped, ok := o.OrgIDs[id]; ok {
+		return mapped
+	}
+	return id
+}
+
+func (o ImportOptions) ruleUID(uid string) string {
+	if mapped, ok := o.RuleUIDs[uid]; ok {
+		return mapped
+	}
+	return uid
+}
+
+// Service exports and imports the state of alerting.
+type Service struct {
+	orgs      state.OrgReader
+	rules     state.RuleReader
+	folders   FolderReader
+	instances state.InstanceReader
+	writer    InstanceWriter
+	kv        kvstore.KVStore
+	clock     clock.Clock
+	logger    log.Logger
+
+	// states and alertmanagers are nil if Grafana is not running, for example when the state is imported from the CLI.
+	states        StateLoader
+	alertmanagers Alertmanagers
+}
+
+type Option func(*Service)
+
+// WithStateLoader loads the imported state of the rules into the state cache of the running Grafana instance.
+func WithStateLoader(loader StateLoader) Option {
+	return func(s *Service) {
+		s.states = loader
+	}
+}
+
+// WithAlertmanagers exports the state of the running Alertmanagers, and restarts the running Alertmanagers of the
+// organizations whose state is imported.
+func WithAlertmanagers(alertmanagers Alertmanagers) Option {
+	return func(s *Service) {
+		s.alertmanagers = alertmanagers
+	}
+}
+
+// NewService returns a new Service. The state of the alert instances is exported from instances and imported with writer.
+func NewService(orgs state.OrgReader, rules state.RuleReader, folders FolderReader, instances state.InstanceReader, writer InstanceWriter, kv kvstore.KVStore, logger log.Logger, opts ...Option) *Service {
+	s := &Service{
+		orgs:      orgs,
+		rules:     rules,
+		folders:   folders,
+		instances: instances,
+		writer:    writer,
+		kv:        kv,
+		clock:     clock.New(),
+		logger:    logger,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Export returns the state of alerting of the organizations. If orgIDs is empty, all organizations are exported.
+func (s *Service) Export(ctx context.Context, orgIDs []int64) (*apimodels.AlertStateArchive, error) {
+	if len(orgIDs) == 0 {
+		ids, err := s.orgs.FetchOrgIds(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch organizations: %w", err)
+		}
+		orgIDs = ids
+	}
+
+	archive := &apimodels.AlertStateArchive{
+		Version:    apimodels.AlertStateArchiveVersion,
+		ExportedAt: s.clock.Now().UTC(),
+		Orgs:       make([]apimodels.AlertStateArchiveOrg, 0, len(orgIDs)),
+	}
+	for _, orgID := range orgIDs {
+		org, err := s.exportOrg(ctx, orgID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to export organization %d: %w", orgID, err)
+		}
+		archive.Orgs = append(archive.Orgs, org)
+	}
+	return archive, nil
+}
+
+func (s *Service) exportOrg(ctx context.Context, orgID int64) (apimodels.AlertStateArchiveOrg, error) {
+	org := apimodels.AlertStateArchiveOrg{OrgID: orgID}
+
+	instances, err := s.instances.ListAlertInstances(ctx, &models.ListAlertInstancesQuery{RuleOrgID: orgID})
+	if err != nil {
+		return org, fmt.Errorf("failed to list alert instances: %w", err)
+	}
+	// Sort the instances so that archives of the same state are equal.
+	sort.Slice(instances, func(i, j int) bool {
+		if instances[i].RuleUID != instances[j].RuleUID {
+			return instances[i].RuleUID < instances[j].RuleUID
+		}
+		return instances[i].LabelsHash < instances[j].LabelsHash
+	})
+	org.Instances = make([]apimodels.AlertStateArchiveInstance, 0, len(instances))
+	for _, instance := range instances {
+		org.Instances = append(org.Instances, instanceToArchive(instance))
+	}
+
+	silences, nflog, err := s.alertmanagerState(ctx, orgID)
+	if err != nil {
+		return org, fmt.Errorf("failed to get the Alertmanager state: %w", err)
+	}
+	if len(silences) > 0 {
+		org.Silences = silences
+	}
+	if len(nflog) > 0 {
+		org.NotificationLog = nflog
+	}
+	return org, nil
+}
+
+// alertmanagerState returns the silences and the notification log of the organization. The kvstore is only updated
+// by the maintenance of the Alertmanager, so the state is read from the running Alertmanager if Grafana is running.
+func (s *Service) alertmanagerState(ctx context.Context, orgID int64) ([]byte, []byte, error) {
+	if s.alertmanagers != nil {
+		return s.alertmanagers.AlertmanagerState(ctx, orgID)
+	}
+	fileStore := notifier.NewFileStore(orgID, s.kv)
+	silences, err := fileStore.GetSilences(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	nflog, err := fileStore.GetNotificationLog(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return []byte(silences), []byte(nflog), nil
+}
+
+// Import imports the state of alerting in the archive. The state of the alert instances of every alert rule in the
+// archive replaces the current state of the rule, and the silences and the notification log of every organization in
+// the archive replace the current ones. Alert instances of alert rules that do not exist are skipped.
+func (s *Service) Import(ctx context.Context, archive apimodels.AlertStateArchive, opts ImportOptions) (apimodels.AlertStateImportResult, error) {
+	result := apimodels.AlertStateImportResult{}
+	if err := validateArchive(archive, opts); err != nil {
+		return result, err
+	}
+	orgIDs, err := s.orgs.FetchOrgIds(ctx)
+	if err != nil {
+		return result, fmt.Errorf("failed to fetch organizations: %w", err)
+	}
+	existing := make(map[int64]struct{}, len(orgIDs))
+	for _, id := range orgIDs {
+		existing[id] = struct{}{}
+	}
+
+	for _, org := range archive.Orgs {
+		orgID := opts.orgID(org.OrgID)
+		logger := s.logger.FromContext(ctx).New("org", orgID, "archive_org", org.OrgID)
+		if _, ok := existing[orgID]; !ok {
+			logger.Warn("Skipped organization that does not exist", "skipped_instances", len(org.Instances))
+			result.SkippedInstances += len(org.Instances)
+			continue
+		}
+
+		imported, changes, err := s.importInstances(ctx, logger, orgID, org.Instances, opts)
+		if err != nil {
+			return result, fmt.Errorf("failed to import the state of the alert instances of organization %d: %w", org.OrgID, err)
+		}
+		if err := s.importAlertmanagerState(ctx, orgID, org, opts, changes); err != nil {
+			return result, fmt.Errorf("failed to import the Alertmanager state of organization %d: %w", org.OrgID, err)
+		}
+		result.Orgs++
+		result.Rules += imported.Rules
+		result.Instances += imported.Instances
+		result.SkippedInstances += imported.SkippedInstances
+		logger.Info("Imported alerting state", "rules", imported.Rules, "instances", imported.Instances, "skipped_instances", imported.SkippedInstances)
+	}
+	return result, nil
+}
+
+// importInstances replaces the state of the alert rules of the organization with the archived instances. It returns
+// the number of imported rules and instances, and the changes of the labels of the imported instances.
+func (s *Service) importInstances(ctx context.Context, logger log.Logger, orgID int64, archived []apimodels.AlertStateArchiveInstance, opts ImportOptions) (apimodels.AlertStateImportResult, []labelsChange, error) {
+	result := apimodels.AlertStateImportResult{}
+	if len(archived) == 0 {
+		return result, nil, nil
+	}
+	rules, err := s.rules.ListAlertRules(ctx, &models.ListAlertRulesQuery{OrgID: orgID})
+	if err != nil {
+		return result, nil, fmt.Errorf("failed to list alert rules: %w", err)
+	}
+	ruleByUID := make(map[string]*models.AlertRule, len(rules))
+	for _, rule := range rules {
+		ruleByUID[rule.UID] = rule
+	}
+	user := accesscontrol.BackgroundUser("grafana_state_archive", orgID, org.RoleAdmin, []accesscontrol.Permission{
+		{Action: dashboards.ActionFoldersRead, Scope: dashboards.ScopeFoldersAll},
+	})
+	folders, err := s.folders.GetUserVisibleNamespaces(ctx, orgID, user)
+	if err != nil {
+		return result, nil, fmt.Errorf("failed to get folders: %w", err)
+	}
+
+	byRule := make(map[string][]models.AlertInstance)
+	var changes []labelsChange
+	for _, a := range archived {
+		rule, ok := ruleByUID[opts.ruleUID(a.RuleUID)]
+		if !ok {
+			logger.Debug("Skipped alert instance of alert rule that does not exist", "rule_uid", opts.ruleUID(a.RuleUID))
+			result.SkippedInstances++
+			continue
+		}
+		folderTitle := ""
+		if f, ok := folders[rule.NamespaceUID]; ok {
+			folderTitle = f.Fullpath
+		} else {
+			logger.Warn("Failed to find the folder of alert rule, the folder label of its alert instances is not replaced", "rule_uid", rule.UID, "folder_uid", rule.NamespaceUID)
+		}
+		instance, err := instanceFromArchive(a, rule, folderTitle)
+		if err != nil {
+			return result, nil, err
+		}
+		if !maps.Equal(a.Labels, map[string]string(instance.Labels)) {
+			changes = append(changes, labelsChange{from: a.Labels, to: instance.Labels})
+		}
+		byRule[rule.UID] = append(byRule[rule.UID], instance)
+	}
+
+	for uid, instances := range byRule {
+		rule := ruleByUID[uid]
+		if err := s.writer.SaveAlertInstancesForRule(ctx, rule.GetKeyWithGroup(), instances); err != nil {
+			return result, nil, fmt.Errorf("failed to save the state of alert rule %s: %w", uid, err)
+		}
+		if s.states != nil {
+			s.states.LoadStateByRuleUID(ctx, rule)
+		}
+		result.Rules++
+		result.Instances += len(instances)
+	}
+	return result, changes, nil
+}
+
+// importAlertmanagerState replaces the silences and the notification log of the organization with the archived ones.
+// The rule UIDs in the silences and the labels of the alerts in the notification log are mapped to the imported ones.
+func (s *Service) importAlertmanagerState(ctx context.Context, orgID int64, org apimodels.AlertStateArchiveOrg, opts ImportOptions, changes []labelsChange) error {
+	if len(org.Silences) == 0 && len(org.NotificationLog) == 0 {
+		return nil
+	}
+	silences, err := remapSilences(org.Silences, opts.RuleUIDs)
+	if err != nil {
+		return err
+	}
+	nflog, err := remapNotificationLog(org.NotificationLog, changes)
+	if err != nil {
+		return err
+	}
+
+	replace := func(ctx context.Context) error {
+		fileStore := notifier.NewFileStore(orgID, s.kv)
+		if len(silences) > 0 {
+			if err := fileStore.SaveContent(ctx, notifier.SilencesFilename, silences); err != nil {
+				return fmt.Errorf("failed to save silences: %w", err)
+			}
+		}
+		if len(nflog) > 0 {
+			if err := fileStore.SaveContent(ctx, notifier.NotificationLogFilename, nflog); err != nil {
+				return fmt.Errorf("failed to save notification log: %w", err)
+			}
+		}
+		return nil
+	}
+	if s.alertmanagers != nil {
+		return s.alertmanagers.ReplaceAlertmanagerState(ctx, orgID, replace)
+	}
+	return replace(ctx)
+}
+
+func validateArchive(archive apimodels.AlertStateArchive, opts ImportOptions) error {
+	if archive.Version != apimodels.AlertStateArchiveVersion {
+		return ErrInvalidArchive.Errorf("unsupported archive version %d, expected %d", archive.Version, apimodels.AlertStateArchiveVersion)
+	}
+	// The state of two organizations in the archive cannot be imported into the same organization.
+	targets := make(map[int64]int64, len(archive.Orgs))
+	for _, org := range archive.Orgs {
+		if org.OrgID <= 0 {
+			return ErrInvalidArchive.Errorf("invalid organization ID %d", org.OrgID)
+		}
+		target := opts.orgID(org.OrgID)
+		if other, ok := targets[target]; ok {
+			return ErrInvalidArchive.Errorf("organizations %d and %d of the archive are both imported into organization %d", other, org.OrgID, target)
+		}
+		targets[target] = org.OrgID
+		for _, instance := range org.Instances {
+			if instance.RuleUID == "" {
+				return ErrInvalidArchive.Errorf("alert instance of organization %d has no alert rule UID", org.OrgID)
+			}
+			if !models.InstanceStateType(instance.State).IsValid() {
+				return ErrInvalidArchive.Errorf("alert instance of alert rule %s has invalid state %q", instance.RuleUID, instance.State)
+			}
+		}
+	}
+	return nil
+}
+
+func instanceToArchive(instance *models.AlertInstance) apimodels.AlertStateArchiveInstance {
+	result := apimodels.AlertStateArchiveInstance{
+		RuleUID:           instance.RuleUID,
+		Labels:            instance.Labels,
+		State:             string(instance.CurrentState),
+		StateReason:       instance.CurrentReason,
+		StartsAt:          instance.CurrentStateSince,
+		EndsAt:            instance.CurrentStateEnd,
+		LastEvaluatedAt:   instance.LastEvalTime,
+		LastSentAt:        instance.LastSentAt,
+		FiredAt:           instance.FiredAt,
+		ResolvedAt:        instance.ResolvedAt,
+		ResultFingerprint: instance.ResultFingerprint,
+	}
+	if ack := instance.GetAcknowledgement(); ack != nil {
+		result.Acknowledgement = &apimodels.GettableAlertInstanceAcknowledgement{
+			AcknowledgedBy: ack.By,
+			AcknowledgedAt: ack.At,
+			Comment:        ack.Comment,
+			ExpiresAt:      ack.ExpiresAt,
+			SilenceID:      ack.SilenceID,
+		}
+	}
+	return result
+}
+
+// instanceFromArchive returns the alert instance of the rule for the archived instance. The labels that are derived
+// from the rule are replaced with those of the rule and the full path of its folder, so that they match the labels of
+// the next evaluations of the rule if it has a different UID, folder or title. The folder title label is kept if
+// folderTitle is empty.
+func instanceFromArchive(a apimodels.AlertStateArchiveInstance, rule *models.AlertRule, folderTitle string) (models.AlertInstance, error) {
+	labels := make(models.InstanceLabels, len(a.Labels))
+	for k, v := range a.Labels {
+		labels[k] = v
+	}
+	derived := map[string]string{
+		alertingModels.RuleUIDLabel:      rule.UID,
+		alertingModels.NamespaceUIDLabel: rule.NamespaceUID,
+		prometheusModel.AlertNameLabel:   rule.Title,
+	}
+	if folderTitle != "" {
+		derived[models.FolderTitleLabel] = folderTitle
+	}
+	for k, v := range derived {
+		if _, ok := labels[k]; ok {
+			labels[k] = v
+		}
+	}
+	_, hash, err := labels.StringAndHash()
+	if err != nil {
+		return models.AlertInstance{}, ErrInvalidArchive.Errorf("invalid labels of alert instance of alert rule %s: %w", a.RuleUID, err)
+	}
+
+	instance := models.AlertInstance{
+		AlertInstanceKey: models.AlertInstanceKey{
+			RuleOrgID:  rule.OrgID,
+			RuleUID:    rule.UID,
+			LabelsHash: hash,
+		},
+		Labels:            labels,
+		CurrentState:      models.InstanceStateType(a.State),
+		CurrentReason:     a.StateReason,
+		CurrentStateSince: a.StartsAt,
+		CurrentStateEnd:   a.EndsAt,
+		LastEvalTime:      a.LastEvaluatedAt,
+		LastSentAt:        a.LastSentAt,
+		FiredAt:           a.FiredAt,
+		ResolvedAt:        a.ResolvedAt,
+		ResultFingerprint: a.ResultFingerprint,
+	}
+	if ack := a.Acknowledgement; ack != nil {
+		instance.SetAcknowledgement(&models.AlertInstanceAcknowledgement{
+			By:        ack.AcknowledgedBy,
+			At:        ack.AcknowledgedAt,
+			Comment:   ack.Comment,
+			ExpiresAt: ack.ExpiresAt,
+			SilenceID: ack.SilenceID,
+		})
+	}
+	return instance, nil
+}
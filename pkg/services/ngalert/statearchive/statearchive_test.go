@@ -0,0 +1,308 @@
+package statearchive
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	alertingModels "github.com/grafana/alerting/models"
+	"github.com/matttproud/golang_protobuf_extensions/pbutil"
+	"github.com/prometheus/alertmanager/nflog/nflogpb"
+	"github.com/prometheus/alertmanager/silence/silencepb"
+	prometheusModel "github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+)
+
+func TestExportImport(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second).UTC()
+
+	gen := models.RuleGen
+	source := gen.With(gen.WithOrgID(1), gen.WithUID("source-uid"), gen.WithTitle("High CPU"), gen.WithNamespaceUID("source-folder")).GenerateRef()
+	target := gen.With(gen.WithOrgID(2), gen.WithUID("target-uid"), gen.WithTitle("CPU usage"), gen.WithNamespaceUID("target-folder")).GenerateRef()
+
+	firing := models.AlertInstance{
+		AlertInstanceKey: models.AlertInstanceKey{RuleOrgID: 1, RuleUID: source.UID},
+		Labels: models.InstanceLabels{
+			"instance":                       "server-1",
+			alertingModels.RuleUIDLabel:      source.UID,
+			alertingModels.NamespaceUIDLabel: source.NamespaceUID,
+			prometheusModel.AlertNameLabel:   source.Title,
+			models.FolderTitleLabel:          "Infrastructure",
+		},
+		CurrentState:      models.InstanceStateFiring,
+		CurrentStateSince: now.Add(-time.Hour),
+		CurrentStateEnd:   now.Add(4 * time.Minute),
+		LastEvalTime:      now,
+		LastSentAt:        &now,
+		FiredAt:           &now,
+		ResultFingerprint: "abc",
+	}
+	firing.SetAcknowledgement(&models.AlertInstanceAcknowledgement{By: "admin", At: now, Comment: "on it"})
+	orphan := models.AlertInstance{
+		AlertInstanceKey: models.AlertInstanceKey{RuleOrgID: 1, RuleUID: "deleted"},
+		Labels:           models.InstanceLabels{"instance": "server-2"},
+		CurrentState:     models.InstanceStateNormal,
+	}
+	sourceInstances := newFakeInstanceStore(firing, orphan)
+
+	sourceKV := fakes.NewFakeKVStore(t)
+	silences := marshalSilences(t, silenceWithRuleUID("s1", source.UID), silenceWithRuleUID("s2", "other-uid"))
+	nflog := marshalNotificationLog(t, &nflogpb.MeshEntry{
+		Entry: &nflogpb.Entry{
+			GroupKey:     []byte(`{}/{__grafana_autogenerated__="true"}:{__alert_rule_uid__="source-uid", alertname="High CPU", team="infra"}`),
+			Receiver:     &nflogpb.Receiver{GroupName: "team", Integration: "email"},
+			FiringAlerts: []uint64{alertHash(labelSet(firing.Labels)), 42},
+		},
+	})
+	require.NoError(t, notifier.NewFileStore(1, sourceKV).SaveContent(ctx, notifier.SilencesFilename, silences))
+	require.NoError(t, notifier.NewFileStore(1, sourceKV).SaveContent(ctx, notifier.NotificationLogFilename, nflog))
+
+	sourceRules := fakes.NewRuleStore(t)
+	exporter := NewService(notifier.NewFakeOrgStore(t, []int64{1}), sourceRules, sourceRules, sourceInstances, sourceInstances, sourceKV, log.NewNopLogger())
+	archive, err := exporter.Export(ctx, nil)
+	require.NoError(t, err)
+	require.Equal(t, apimodels.AlertStateArchiveVersion, archive.Version)
+	require.Len(t, archive.Orgs, 1)
+	require.Len(t, archive.Orgs[0].Instances, 2)
+	require.Equal(t, silences, archive.Orgs[0].Silences)
+	require.Equal(t, nflog, archive.Orgs[0].NotificationLog)
+
+	rules := fakes.NewRuleStore(t)
+	rules.PutRule(ctx, target)
+	targetInstances := newFakeInstanceStore()
+	targetKV := fakes.NewFakeKVStore(t)
+	loader := &fakeStateLoader{}
+	alertmanagers := &fakeAlertmanagers{}
+	importer := NewService(notifier.NewFakeOrgStore(t, []int64{2}), rules, rules, targetInstances, targetInstances, targetKV, log.NewNopLogger(),
+		WithStateLoader(loader), WithAlertmanagers(alertmanagers))
+
+	result, err := importer.Import(ctx, *archive, ImportOptions{
+		OrgIDs:   map[int64]int64{1: 2},
+		RuleUIDs: map[string]string{source.UID: target.UID},
+	})
+	require.NoError(t, err)
+	require.Equal(t, apimodels.AlertStateImportResult{Orgs: 1, Rules: 1, Instances: 1, SkippedInstances: 1}, result)
+
+	expectedLabels := models.InstanceLabels{
+		"instance":                       "server-1",
+		alertingModels.RuleUIDLabel:      target.UID,
+		alertingModels.NamespaceUIDLabel: target.NamespaceUID,
+		prometheusModel.AlertNameLabel:   target.Title,
+		models.FolderTitleLabel:          rules.Folders[2][0].Fullpath,
+	}
+
+	t.Run("should map the instances to the alert rule", func(t *testing.T) {
+		imported := targetInstances.instances[target.GetKey()]
+		require.Len(t, imported, 1)
+		instance := imported[0]
+		_, hash, err := expectedLabels.StringAndHash()
+		require.NoError(t, err)
+		assert.Equal(t, models.AlertInstanceKey{RuleOrgID: 2, RuleUID: target.UID, LabelsHash: hash}, instance.AlertInstanceKey)
+		assert.Equal(t, expectedLabels, instance.Labels)
+		assert.Equal(t, firing.CurrentState, instance.CurrentState)
+		assert.Equal(t, firing.CurrentStateSince, instance.CurrentStateSince)
+		assert.Equal(t, firing.CurrentStateEnd, instance.CurrentStateEnd)
+		assert.Equal(t, firing.FiredAt, instance.FiredAt)
+		assert.Equal(t, firing.LastSentAt, instance.LastSentAt)
+		assert.Equal(t, firing.ResultFingerprint, instance.ResultFingerprint)
+		assert.True(t, firing.GetAcknowledgement().Equals(instance.GetAcknowledgement()))
+		assert.Equal(t, []string{target.UID}, loader.loaded)
+	})
+
+	t.Run("should replace the Alertmanager state with the rule UIDs mapped in silences", func(t *testing.T) {
+		assert.Equal(t, []int64{2}, alertmanagers.replaced)
+		fileStore := notifier.NewFileStore(2, targetKV)
+		content, err := fileStore.GetSilences(ctx)
+		require.NoError(t, err)
+		patterns := map[string]string{}
+		for _, s := range unmarshalSilences(t, []byte(content)) {
+			patterns[s.Silence.Id] = s.Silence.Matchers[0].Pattern
+		}
+		assert.Equal(t, map[string]string{"s1": target.UID, "s2": "other-uid"}, patterns)
+
+	})
+
+	t.Run("should replace the labels of the imported alerts in the notification log", func(t *testing.T) {
+		content, err := notifier.NewFileStore(2, targetKV).GetNotificationLog(ctx)
+		require.NoError(t, err)
+		entries := unmarshalNotificationLog(t, []byte(content))
+		require.Len(t, entries, 1)
+		assert.Equal(t, `{}/{__grafana_autogenerated__="true"}:{__alert_rule_uid__="target-uid", alertname="CPU usage", team="infra"}`, string(entries[0].Entry.GroupKey))
+		assert.Equal(t, "team", entries[0].Entry.Receiver.GroupName)
+		assert.Equal(t, []uint64{alertHash(labelSet(expectedLabels)), 42}, entries[0].Entry.FiringAlerts)
+	})
+
+	t.Run("should export the state of the running Alertmanagers", func(t *testing.T) {
+		live := &fakeAlertmanagers{silences: []byte("silences"), nflog: []byte("notification log")}
+		exporter := NewService(notifier.NewFakeOrgStore(t, []int64{1}), sourceRules, sourceRules, sourceInstances, sourceInstances, sourceKV, log.NewNopLogger(),
+			WithAlertmanagers(live))
+		archive, err := exporter.Export(ctx, nil)
+		require.NoError(t, err)
+		require.Len(t, archive.Orgs, 1)
+		assert.Equal(t, live.silences, archive.Orgs[0].Silences)
+		assert.Equal(t, live.nflog, archive.Orgs[0].NotificationLog)
+	})
+
+	t.Run("should skip organizations that do not exist", func(t *testing.T) {
+		result, err := importer.Import(ctx, *archive, ImportOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, apimodels.AlertStateImportResult{SkippedInstances: 2}, result)
+	})
+
+	t.Run("should reject invalid archives", func(t *testing.T) {
+		testCases := map[string]func(a *apimodels.AlertStateArchive) ImportOptions{
+			"unsupported version": func(a *apimodels.AlertStateArchive) ImportOptions {
+				a.Version = 2
+				return ImportOptions{}
+			},
+			"invalid state": func(a *apimodels.AlertStateArchive) ImportOptions {
+				a.Orgs[0].Instances = []apimodels.AlertStateArchiveInstance{{RuleUID: "uid", State: "Unknown"}}
+				return ImportOptions{}
+			},
+			"two organizations imported into the same one": func(a *apimodels.AlertStateArchive) ImportOptions {
+				a.Orgs = append(a.Orgs, apimodels.AlertStateArchiveOrg{OrgID: 2})
+				return ImportOptions{OrgIDs: map[int64]int64{1: 2}}
+			},
+		}
+		for name, mutate := range testCases {
+			t.Run(name, func(t *testing.T) {
+				invalid := apimodels.AlertStateArchive{
+					Version: apimodels.AlertStateArchiveVersion,
+					Orgs:    []apimodels.AlertStateArchiveOrg{{OrgID: 1}},
+				}
+				opts := mutate(&invalid)
+				_, err := importer.Import(ctx, invalid, opts)
+				require.ErrorIs(t, err, ErrInvalidArchive)
+			})
+		}
+	})
+}
+
+type fakeInstanceStore struct {
+	instances map[models.AlertRuleKey][]models.AlertInstance
+}
+
+func newFakeInstanceStore(instances ...models.AlertInstance) *fakeInstanceStore {
+	f := &fakeInstanceStore{instances: make(map[models.AlertRuleKey][]models.AlertInstance)}
+	for _, instance := range instances {
+		key := models.AlertRuleKey{OrgID: instance.RuleOrgID, UID: instance.RuleUID}
+		f.instances[key] = append(f.instances[key], instance)
+	}
+	return f
+}
+
+func (f *fakeInstanceStore) ListAlertInstances(_ context.Context, q *models.ListAlertInstancesQuery) ([]*models.AlertInstance, error) {
+	var result []*models.AlertInstance
+	for key, instances := range f.instances {
+		if key.OrgID != q.RuleOrgID {
+			continue
+		}
+		for i := range instances {
+			result = append(result, &instances[i])
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeInstanceStore) SaveAlertInstancesForRule(_ context.Context, key models.AlertRuleKeyWithGroup, instances []models.AlertInstance) error {
+	f.instances[key.AlertRuleKey] = instances
+	return nil
+}
+
+type fakeStateLoader struct {
+	loaded []string
+}
+
+func (f *fakeStateLoader) LoadStateByRuleUID(_ context.Context, rule *models.AlertRule) int {
+	f.loaded = append(f.loaded, rule.UID)
+	return 0
+}
+
+type fakeAlertmanagers struct {
+	silences []byte
+	nflog    []byte
+	replaced []int64
+}
+
+func (f *fakeAlertmanagers) AlertmanagerState(_ context.Context, _ int64) ([]byte, []byte, error) {
+	return f.silences, f.nflog, nil
+}
+
+func (f *fakeAlertmanagers) ReplaceAlertmanagerState(ctx context.Context, orgID int64, replace func(ctx context.Context) error) error {
+	f.replaced = append(f.replaced, orgID)
+	return replace(ctx)
+}
+
+func silenceWithRuleUID(id, ruleUID string) *silencepb.MeshSilence {
+	return &silencepb.MeshSilence{
+		Silence: &silencepb.Silence{
+			Id: id,
+			Matchers: []*silencepb.Matcher{
+				{Type: silencepb.Matcher_EQUAL, Name: alertingModels.RuleUIDLabel, Pattern: ruleUID},
+			},
+		},
+	}
+}
+
+func marshalSilences(t *testing.T, silences ...*silencepb.MeshSilence) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	for _, s := range silences {
+		_, err := pbutil.WriteDelimited(&buf, s)
+		require.NoError(t, err)
+	}
+	return buf.Bytes()
+}
+
+func unmarshalSilences(t *testing.T, content []byte) []*silencepb.MeshSilence {
+	t.Helper()
+	var result []*silencepb.MeshSilence
+	r := bytes.NewReader(content)
+	for {
+		var s silencepb.MeshSilence
+		_, err := pbutil.ReadDelimited(r, &s)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		result = append(result, &s)
+	}
+	return result
+}
+
+func marshalNotificationLog(t *testing.T, entries ...*nflogpb.MeshEntry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	for _, e := range entries {
+		_, err := pbutil.WriteDelimited(&buf, e)
+		require.NoError(t, err)
+	}
+	return buf.Bytes()
+}
+
+func unmarshalNotificationLog(t *testing.T, content []byte) []*nflogpb.MeshEntry {
+	t.Helper()
+	var result []*nflogpb.MeshEntry
+	r := bytes.NewReader(content)
+	for {
+		var e nflogpb.MeshEntry
+		_, err := pbutil.ReadDelimited(r, &e)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		result = append(result, &e)
+	}
+	return result
+}
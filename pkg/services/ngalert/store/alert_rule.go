@@ -895,6 +895,14 @@ func (st DBstore) DeleteInFolders(ctx context.Context, orgID int64, folderUIDs [
 		if err := st.DeleteAlertRulesByUID(ctx, orgID, ngmodels.NewUserUID(user), false, uids...); err != nil {
 			return err
 		}
+
+		err = st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+			_, err := sess.Where("org_id = ? AND namespace_uid = ?", orgID, folderUID).Delete(alertRuleTemplate{})
+			return err
+		})
+		if err != nil {
+			return err
+		}
 	}
 	return nil
 }
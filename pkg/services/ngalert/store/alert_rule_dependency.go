@@ -0,0 +1,138 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// AlertRuleLister is the part of the store that lists alert rules.
+type AlertRuleLister interface {
+	ListAlertRules(ctx context.Context, query *ngmodels.ListAlertRulesQuery) (ngmodels.RulesGroup, error)
+}
+
+// dependencyChange is a rule that is created or updated and the UIDs of the alert rules that it newly depends on.
+type dependencyChange struct {
+	rule  *ngmodels.AlertRule
+	added []string
+}
+
+// VerifyRuleDependencies returns ngmodels.ErrAlertRuleFailedValidation if the rule newly depends on an alert rule that
+// does not exist in its organization, or if its dependencies form a cycle. existing is the stored version of the rule,
+// or nil if the rule is created. The dependencies that the stored version already has are not verified again, so that
+// a rule whose parent was deleted can still be changed.
+func VerifyRuleDependencies(ctx context.Context, lister AlertRuleLister, rule *ngmodels.AlertRule, existing *ngmodels.AlertRule) error {
+	var changes []dependencyChange
+	if added := addedDependencies(rule, existing); len(added) > 0 {
+		changes = append(changes, dependencyChange{rule: rule, added: added})
+	}
+	return verifyDependencies(ctx, lister, changes, nil)
+}
+
+// VerifyGroupDeltaDependencies verifies the dependencies of the rules that are created or updated by the delta
+// the same way as VerifyRuleDependencies. The rules that the delta deletes are not valid dependencies.
+func VerifyGroupDeltaDependencies(ctx context.Context, lister AlertRuleLister, delta *GroupDelta) error {
+	var changes []dependencyChange
+	for _, r := range delta.New {
+		if added := addedDependencies(r, nil); len(added) > 0 {
+			changes = append(changes, dependencyChange{rule: r, added: added})
+		}
+	}
+	for _, upd := range delta.Update {
+		if added := addedDependencies(upd.New, upd.Existing); len(added) > 0 {
+			changes = append(changes, dependencyChange{rule: upd.New, added: added})
+		}
+	}
+	return verifyDependencies(ctx, lister, changes, delta.Delete)
+}
+
+// addedDependencies returns the UIDs of the alert rules that the rule depends on and its existing version does not.
+func addedDependencies(rule *ngmodels.AlertRule, existing *ngmodels.AlertRule) []string {
+	var result []string
+	for _, d := range rule.Dependencies {
+		if existing != nil && slices.ContainsFunc(existing.Dependencies, func(e ngmodels.AlertRuleDependency) bool {
+			return e.RuleUID == d.RuleUID
+		}) {
+			continue
+		}
+		result = append(result, d.RuleUID)
+	}
+	return result
+}
+
+func verifyDependencies(ctx context.Context, lister AlertRuleLister, changes []dependencyChange, deleted []*ngmodels.AlertRule) error {
+	byOrg := make(map[int64][]dependencyChange)
+	for _, c := range changes {
+		byOrg[c.rule.OrgID] = append(byOrg[c.rule.OrgID], c)
+	}
+	for orgID, orgChanges := range byOrg {
+		rules, err := lister.ListAlertRules(ctx, &ngmodels.ListAlertRulesQuery{OrgID: orgID})
+		if err != nil {
+			return fmt.Errorf("failed to list alert rules: %w", err)
+		}
+		deps := make(map[string][]string, len(rules)+len(orgChanges))
+		for _, r := range rules {
+			deps[r.UID] = dependencyUIDs(r)
+		}
+		for _, r := range deleted {
+			if r.OrgID == orgID {
+				delete(deps, r.UID)
+			}
+		}
+		for _, c := range orgChanges {
+			// The UID of a new rule can be generated when it is inserted. No other rule can depend on it yet.
+			if c.rule.UID != "" {
+				deps[c.rule.UID] = dependencyUIDs(c.rule)
+			}
+		}
+
+		for _, c := range orgChanges {
+			for _, uid := range c.added {
+				if _, ok := deps[uid]; !ok {
+					return fmt.Errorf("%w '%s' (UID: %s): dependency on alert rule %s that does not exist", ngmodels.ErrAlertRuleFailedValidation, c.rule.Title, c.rule.UID, uid)
+				}
+			}
+			if c.rule.UID == "" {
+				continue
+			}
+			if cycle := dependencyCycle(deps, c.rule.UID); cycle != nil {
+				return fmt.Errorf("%w '%s' (UID: %s): dependencies form a cycle %s", ngmodels.ErrAlertRuleFailedValidation, c.rule.Title, c.rule.UID, strings.Join(cycle, " -> "))
+			}
+		}
+	}
+	return nil
+}
+
+func dependencyUIDs(r *ngmodels.AlertRule) []string {
+	result := make([]string, 0, len(r.Dependencies))
+	for _, d := range r.Dependencies {
+		result = append(result, d.RuleUID)
+	}
+	return result
+}
+
+// dependencyCycle returns the UIDs of the alert rules on a path of dependencies that starts and ends at the rule with
+// the UID, or nil if there is none. deps are the UIDs of the dependencies of each alert rule by its UID.
+func dependencyCycle(deps map[string][]string, uid string) []string {
+	visited := map[string]struct{}{uid: {}}
+	var visit func(path []string) []string
+	visit = func(path []string) []string {
+		for _, next := range deps[path[len(path)-1]] {
+			if next == uid {
+				return append(path, next)
+			}
+			if _, ok := visited[next]; ok {
+				continue
+			}
+			visited[next] = struct{}{}
+			if cycle := visit(append(slices.Clip(path), next)); cycle != nil {
+				return cycle
+			}
+		}
+		return nil
+	}
+	return visit([]string{uid})
+}
@@ -0,0 +1,66 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+)
+
+func TestVerifyRuleDependencies(t *testing.T) {
+	orgID := int64(1)
+	gen := models.RuleGen
+	gen = gen.With(gen.WithOrgID(orgID), gen.WithNoDependencies())
+	dependsOn := func(parents ...*models.AlertRule) models.AlertRuleMutator {
+		deps := make([]models.AlertRuleDependency, 0, len(parents))
+		for _, p := range parents {
+			deps = append(deps, models.AlertRuleDependency{RuleUID: p.UID})
+		}
+		return gen.WithDependencies(deps...)
+	}
+
+	a := gen.GenerateRef()
+	b := gen.With(dependsOn(a)).GenerateRef()
+	c := gen.With(dependsOn(b)).GenerateRef()
+	ruleStore := fakes.NewRuleStore(t)
+	ruleStore.PutRule(context.Background(), a, b, c)
+
+	t.Run("accepts dependencies on existing rules", func(t *testing.T) {
+		rule := gen.With(dependsOn(a, c)).GenerateRef()
+		require.NoError(t, VerifyRuleDependencies(context.Background(), ruleStore, rule, nil))
+	})
+
+	t.Run("rejects dependencies on rules that do not exist", func(t *testing.T) {
+		rule := gen.With(gen.WithDependencies(models.AlertRuleDependency{RuleUID: "missing"})).GenerateRef()
+		err := VerifyRuleDependencies(context.Background(), ruleStore, rule, nil)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.ErrorContains(t, err, "missing")
+	})
+
+	t.Run("does not verify dependencies that the stored rule already has", func(t *testing.T) {
+		existing := gen.With(gen.WithDependencies(models.AlertRuleDependency{RuleUID: "deleted"})).GenerateRef()
+		rule := models.CopyRule(existing)
+		rule.Title = "updated"
+		require.NoError(t, VerifyRuleDependencies(context.Background(), ruleStore, rule, existing))
+	})
+
+	t.Run("rejects cycles", func(t *testing.T) {
+		rule := models.CopyRule(a)
+		rule.Dependencies = []models.AlertRuleDependency{{RuleUID: c.UID}}
+		err := VerifyRuleDependencies(context.Background(), ruleStore, rule, a)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.ErrorContains(t, err, a.UID+" -> "+c.UID+" -> "+b.UID+" -> "+a.UID)
+	})
+
+	t.Run("rejects dependencies on rules deleted by the same change", func(t *testing.T) {
+		rule := gen.With(dependsOn(c)).GenerateRef()
+		err := VerifyGroupDeltaDependencies(context.Background(), ruleStore, &GroupDelta{
+			New:    []*models.AlertRule{rule},
+			Delete: []*models.AlertRule{c},
+		})
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+	})
+}
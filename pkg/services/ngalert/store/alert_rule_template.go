@@ -0,0 +1,262 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+type alertRuleTemplateInstance struct {
+	UID    string            `json:"uid"`
+	Values map[string]string `json:"values"`
+}
+
+// ListAlertRuleTemplates returns the alert rule templates of the organization that match the query.
+func (st DBstore) ListAlertRuleTemplates(ctx context.Context, query *ngmodels.ListAlertRuleTemplatesQuery) ([]*ngmodels.AlertRuleTemplate, error) {
+	var result []*ngmodels.AlertRuleTemplate
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		q := sess.Table(alertRuleTemplate{}).Where("org_id = ?", query.OrgID)
+		if len(query.NamespaceUIDs) > 0 {
+			args, in := getINSubQueryArgs(query.NamespaceUIDs)
+			q = q.Where(fmt.Sprintf("namespace_uid IN (%s)", strings.Join(in, ",")), args...)
+		}
+		if len(query.RuleGroups) > 0 {
+			args, in := getINSubQueryArgs(query.RuleGroups)
+			q = q.Where(fmt.Sprintf("rule_group IN (%s)", strings.Join(in, ",")), args...)
+		}
+		var templates []alertRuleTemplate
+		if err := q.Asc("namespace_uid", "rule_group").Find(&templates); err != nil {
+			return err
+		}
+		result = make([]*ngmodels.AlertRuleTemplate, 0, len(templates))
+		for _, t := range templates {
+			converted, err := alertRuleTemplateToModelsAlertRuleTemplate(t, st.Logger)
+			if err != nil {
+				st.Logger.Error("Invalid alert rule template found in DB store, ignoring it", "org", t.OrgID, "uid", t.UID, "error", err)
+				continue
+			}
+			result = append(result, &converted)
+		}
+		return nil
+	})
+	return result, err
+}
+
+// AlertRuleTemplateReader is the part of the store that lists alert rule templates.
+type AlertRuleTemplateReader interface {
+	ListAlertRuleTemplates(ctx context.Context, query *ngmodels.ListAlertRuleTemplatesQuery) ([]*ngmodels.AlertRuleTemplate, error)
+}
+
+// VerifyRuleGroupsNotOwnedByTemplates returns ngmodels.ErrAlertRuleTemplateConflict if one of the rule groups belongs to
+// an alert rule template. The rules of such a group are changed only through the template, which replaces them on every update.
+func VerifyRuleGroupsNotOwnedByTemplates(ctx context.Context, reader AlertRuleTemplateReader, keys ...ngmodels.AlertRuleGroupKey) error {
+	byOrg := make(map[int64][]ngmodels.AlertRuleGroupKey)
+	for _, key := range keys {
+		byOrg[key.OrgID] = append(byOrg[key.OrgID], key)
+	}
+	for orgID, orgKeys := range byOrg {
+		query := &ngmodels.ListAlertRuleTemplatesQuery{OrgID: orgID}
+		for _, key := range orgKeys {
+			query.NamespaceUIDs = append(query.NamespaceUIDs, key.NamespaceUID)
+			query.RuleGroups = append(query.RuleGroups, key.RuleGroup)
+		}
+		templates, err := reader.ListAlertRuleTemplates(ctx, query)
+		if err != nil {
+			return fmt.Errorf("failed to list alert rule templates: %w", err)
+		}
+		for _, t := range templates {
+			if slices.Contains(orgKeys, t.GetGroupKey()) {
+				return ngmodels.ErrAlertRuleTemplateConflict(*t, ngmodels.ErrAlertRuleGroupOwnedByTemplate)
+			}
+		}
+	}
+	return nil
+}
+
+// VerifyGroupDeltaNotOwnedByTemplates verifies that neither the group of the delta nor the groups the delta moves rules from
+// belong to an alert rule template.
+func VerifyGroupDeltaNotOwnedByTemplates(ctx context.Context, reader AlertRuleTemplateReader, delta *GroupDelta) error {
+	keys := []ngmodels.AlertRuleGroupKey{delta.GroupKey}
+	for key := range delta.AffectedGroups {
+		if key != delta.GroupKey {
+			keys = append(keys, key)
+		}
+	}
+	return VerifyRuleGroupsNotOwnedByTemplates(ctx, reader, keys...)
+}
+
+// GetAlertRuleTemplate returns the alert rule template with the given UID.
+// It returns ngmodels.ErrAlertRuleTemplateNotFound if there is no such template.
+func (st DBstore) GetAlertRuleTemplate(ctx context.Context, orgID int64, uid string) (*ngmodels.AlertRuleTemplate, error) {
+	var result *ngmodels.AlertRuleTemplate
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		t := alertRuleTemplate{OrgID: orgID, UID: uid}
+		has, err := sess.Get(&t)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return ngmodels.ErrAlertRuleTemplateNotFound.Errorf("")
+		}
+		converted, err := alertRuleTemplateToModelsAlertRuleTemplate(t, st.Logger)
+		if err != nil {
+			return fmt.Errorf("failed to convert alert rule template: %w", err)
+		}
+		result = &converted
+		return nil
+	})
+	return result, err
+}
+
+// InsertAlertRuleTemplate inserts a new alert rule template and returns it with the generated ID and UID.
+func (st DBstore) InsertAlertRuleTemplate(ctx context.Context, user *ngmodels.UserUID, template ngmodels.AlertRuleTemplate) (ngmodels.AlertRuleTemplate, error) {
+	err := st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if template.UID == "" {
+			template.UID = util.GenerateShortUID()
+		}
+		template.Version = 1
+		template.Updated = TimeNow()
+		template.UpdatedBy = user
+		converted, err := alertRuleTemplateFromModelsAlertRuleTemplate(template)
+		if err != nil {
+			return fmt.Errorf("failed to convert alert rule template to storage model: %w", err)
+		}
+		if _, err := sess.Insert(&converted); err != nil {
+			if st.SQLStore.GetDialect().IsUniqueConstraintViolation(err) {
+				return ngmodels.ErrAlertRuleTemplateConflict(template, errors.New("the UID and the rule group of a template must be unique"))
+			}
+			return fmt.Errorf("failed to insert alert rule template: %w", err)
+		}
+		template.ID = converted.ID
+		return nil
+	})
+	return template, err
+}
+
+// UpdateAlertRuleTemplate updates the alert rule template. The version of the template must be the version of
+// the stored template, otherwise ErrOptimisticLock is returned.
+func (st DBstore) UpdateAlertRuleTemplate(ctx context.Context, user *ngmodels.UserUID, template ngmodels.AlertRuleTemplate) (ngmodels.AlertRuleTemplate, error) {
+	err := st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		template.Updated = TimeNow()
+		template.UpdatedBy = user
+		converted, err := alertRuleTemplateFromModelsAlertRuleTemplate(template)
+		if err != nil {
+			return fmt.Errorf("failed to convert alert rule template to storage model: %w", err)
+		}
+		updated, err := sess.ID(template.ID).AllCols().Update(&converted)
+		if err != nil {
+			return fmt.Errorf("failed to update alert rule template %s: %w", template.UID, err)
+		}
+		if updated == 0 {
+			return fmt.Errorf("%w: alert rule template UID %s version %d", ErrOptimisticLock, template.UID, template.Version)
+		}
+		template.Version++
+		return nil
+	})
+	return template, err
+}
+
+// DeleteAlertRuleTemplate deletes the alert rule template with the given UID. It does not delete the rules of the template.
+func (st DBstore) DeleteAlertRuleTemplate(ctx context.Context, orgID int64, uid string) error {
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Delete(alertRuleTemplate{})
+		return err
+	})
+}
+
+func alertRuleTemplateToModelsAlertRuleTemplate(t alertRuleTemplate, l log.Logger) (ngmodels.AlertRuleTemplate, error) {
+	result := ngmodels.AlertRuleTemplate{
+		ID:              t.ID,
+		OrgID:           t.OrgID,
+		UID:             t.UID,
+		NamespaceUID:    t.NamespaceUID,
+		RuleGroup:       t.RuleGroup,
+		IntervalSeconds: t.IntervalSeconds,
+		Version:         t.Version,
+		Updated:         t.Updated,
+	}
+	if t.UpdatedBy != nil {
+		result.UpdatedBy = util.Pointer(ngmodels.UserUID(*t.UpdatedBy))
+	}
+	if err := json.Unmarshal([]byte(t.Parameters), &result.Parameters); err != nil {
+		return ngmodels.AlertRuleTemplate{}, fmt.Errorf("failed to parse parameters: %w", err)
+	}
+
+	var instances []alertRuleTemplateInstance
+	if err := json.Unmarshal([]byte(t.Instances), &instances); err != nil {
+		return ngmodels.AlertRuleTemplate{}, fmt.Errorf("failed to parse instances: %w", err)
+	}
+	result.Instances = make([]ngmodels.AlertRuleTemplateInstance, 0, len(instances))
+	for _, i := range instances {
+		result.Instances = append(result.Instances, ngmodels.AlertRuleTemplateInstance{UID: i.UID, Values: i.Values})
+	}
+
+	// The rule of the template is stored in the same format as the alert_rule table.
+	var rule alertRule
+	if err := json.Unmarshal([]byte(t.Rule), &rule); err != nil {
+		return ngmodels.AlertRuleTemplate{}, fmt.Errorf("failed to parse rule: %w", err)
+	}
+	r, err := alertRuleToModelsAlertRule(rule, l)
+	if err != nil {
+		return ngmodels.AlertRuleTemplate{}, err
+	}
+	result.Rule = r
+	return result, nil
+}
+
+func alertRuleTemplateFromModelsAlertRuleTemplate(t ngmodels.AlertRuleTemplate) (alertRuleTemplate, error) {
+	result := alertRuleTemplate{
+		ID:              t.ID,
+		OrgID:           t.OrgID,
+		UID:             t.UID,
+		NamespaceUID:    t.NamespaceUID,
+		RuleGroup:       t.RuleGroup,
+		IntervalSeconds: t.IntervalSeconds,
+		Version:         t.Version,
+		Updated:         t.Updated,
+	}
+	if t.UpdatedBy != nil {
+		result.UpdatedBy = util.Pointer(string(*t.UpdatedBy))
+	}
+
+	params := t.Parameters
+	if params == nil {
+		params = []string{}
+	}
+	b, err := json.Marshal(params)
+	if err != nil {
+		return alertRuleTemplate{}, fmt.Errorf("failed to marshal parameters: %w", err)
+	}
+	result.Parameters = string(b)
+
+	instances := make([]alertRuleTemplateInstance, 0, len(t.Instances))
+	for _, i := range t.Instances {
+		instances = append(instances, alertRuleTemplateInstance{UID: i.UID, Values: i.Values})
+	}
+	b, err = json.Marshal(instances)
+	if err != nil {
+		return alertRuleTemplate{}, fmt.Errorf("failed to marshal instances: %w", err)
+	}
+	result.Instances = string(b)
+
+	rule := t.Rule
+	rule.ID, rule.GUID, rule.UID, rule.Version = 0, "", "", 0
+	converted, err := alertRuleFromModelsAlertRule(rule)
+	if err != nil {
+		return alertRuleTemplate{}, err
+	}
+	b, err = json.Marshal(converted)
+	if err != nil {
+		return alertRuleTemplate{}, fmt.Errorf("failed to marshal rule: %w", err)
+	}
+	result.Rule = string(b)
+	return result, nil
+}
@@ -0,0 +1,115 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log/logtest"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestIntegrationAlertRuleTemplates(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	sqlStore := db.InitTestDB(t)
+	cfg := setting.NewCfg()
+	cfg.UnifiedAlerting = setting.UnifiedAlertingSettings{BaseInterval: 10 * time.Second}
+	folderService := setupFolderService(t, sqlStore, cfg, featuremgmt.WithFeatures())
+	store := createTestStore(sqlStore, folderService, &logtest.Fake{}, cfg.UnifiedAlerting, &fakeBus{})
+	usr := models.UserUID("1234")
+	orgID := int64(1)
+
+	rule := models.RuleGen.With(models.RuleMuts.WithOrgID(orgID)).GenerateRef()
+	newTemplate := func(folder, group string) models.AlertRuleTemplate {
+		return models.AlertRuleTemplate{
+			OrgID:           orgID,
+			NamespaceUID:    folder,
+			RuleGroup:       group,
+			IntervalSeconds: 60,
+			Parameters:      []string{"service"},
+			Rule:            *rule,
+			Instances: []models.AlertRuleTemplateInstance{
+				{UID: "api", Values: map[string]string{"service": "api"}},
+			},
+		}
+	}
+
+	created, err := store.InsertAlertRuleTemplate(context.Background(), &usr, newTemplate("folder-1", "group-1"))
+	require.NoError(t, err)
+	require.NotEmpty(t, created.UID)
+	require.NotZero(t, created.ID)
+	require.Equal(t, int64(1), created.Version)
+
+	t.Run("should get template by UID", func(t *testing.T) {
+		stored, err := store.GetAlertRuleTemplate(context.Background(), orgID, created.UID)
+		require.NoError(t, err)
+		require.Equal(t, created.UID, stored.UID)
+		require.Equal(t, "folder-1", stored.NamespaceUID)
+		require.Equal(t, "group-1", stored.RuleGroup)
+		require.Equal(t, int64(60), stored.IntervalSeconds)
+		require.Equal(t, []string{"service"}, stored.Parameters)
+		require.Equal(t, created.Instances, stored.Instances)
+		require.Equal(t, rule.Title, stored.Rule.Title)
+		require.Equal(t, rule.Labels, stored.Rule.Labels)
+		require.Equal(t, rule.Data, stored.Rule.Data)
+		require.Equal(t, &usr, stored.UpdatedBy)
+
+		_, err = store.GetAlertRuleTemplate(context.Background(), orgID, "unknown")
+		require.ErrorIs(t, err, models.ErrAlertRuleTemplateNotFound)
+	})
+
+	t.Run("should not allow two templates for the same group", func(t *testing.T) {
+		_, err := store.InsertAlertRuleTemplate(context.Background(), &usr, newTemplate("folder-1", "group-1"))
+		require.ErrorIs(t, err, models.ErrAlertRuleTemplateConflictBase)
+	})
+
+	t.Run("should update template and increase version", func(t *testing.T) {
+		stored, err := store.GetAlertRuleTemplate(context.Background(), orgID, created.UID)
+		require.NoError(t, err)
+		stored.Instances = append(stored.Instances, models.AlertRuleTemplateInstance{UID: "web", Values: map[string]string{"service": "web"}})
+		updated, err := store.UpdateAlertRuleTemplate(context.Background(), &usr, *stored)
+		require.NoError(t, err)
+		require.Equal(t, stored.Version+1, updated.Version)
+
+		stored, err = store.GetAlertRuleTemplate(context.Background(), orgID, created.UID)
+		require.NoError(t, err)
+		require.Len(t, stored.Instances, 2)
+
+		t.Run("should fail if version is not the latest", func(t *testing.T) {
+			_, err := store.UpdateAlertRuleTemplate(context.Background(), &usr, created)
+			require.Truef(t, errors.Is(err, ErrOptimisticLock), "expected optimistic lock error, got %v", err)
+		})
+	})
+
+	t.Run("should list templates by folder and group", func(t *testing.T) {
+		other, err := store.InsertAlertRuleTemplate(context.Background(), &usr, newTemplate("folder-2", "group-1"))
+		require.NoError(t, err)
+
+		result, err := store.ListAlertRuleTemplates(context.Background(), &models.ListAlertRuleTemplatesQuery{OrgID: orgID})
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+
+		result, err = store.ListAlertRuleTemplates(context.Background(), &models.ListAlertRuleTemplatesQuery{OrgID: orgID, NamespaceUIDs: []string{"folder-2"}})
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		require.Equal(t, other.UID, result[0].UID)
+
+		result, err = store.ListAlertRuleTemplates(context.Background(), &models.ListAlertRuleTemplatesQuery{OrgID: orgID, RuleGroups: []string{"group-2"}})
+		require.NoError(t, err)
+		require.Empty(t, result)
+	})
+
+	t.Run("should delete template", func(t *testing.T) {
+		require.NoError(t, store.DeleteAlertRuleTemplate(context.Background(), orgID, created.UID))
+		_, err := store.GetAlertRuleTemplate(context.Background(), orgID, created.UID)
+		require.ErrorIs(t, err, models.ErrAlertRuleTemplateNotFound)
+	})
+}
@@ -96,6 +96,13 @@ func alertRuleToModelsAlertRule(ar alertRule, l log.Logger) (models.AlertRule, e
 		}
 	}
 
+	if ar.Dependencies != "" {
+		err = json.Unmarshal([]byte(ar.Dependencies), &result.Dependencies)
+		if err != nil {
+			return models.AlertRule{}, fmt.Errorf("failed to parse dependencies: %w", err)
+		}
+	}
+
 	return result, nil
 }
 
@@ -180,6 +187,14 @@ func alertRuleFromModelsAlertRule(ar models.AlertRule) (alertRule, error) {
 	}
 	result.Metadata = string(metadata)
 
+	if len(ar.Dependencies) > 0 {
+		dependenciesData, err := json.Marshal(ar.Dependencies)
+		if err != nil {
+			return alertRule{}, fmt.Errorf("failed to marshal dependencies: %w", err)
+		}
+		result.Dependencies = string(dependenciesData)
+	}
+
 	return result, nil
 }
 
@@ -211,6 +226,7 @@ func alertRuleToAlertRuleVersion(rule alertRule) alertRuleVersion {
 		NotificationSettings:        rule.NotificationSettings,
 		Metadata:                    rule.Metadata,
 		MissingSeriesEvalsToResolve: rule.MissingSeriesEvalsToResolve,
+		Dependencies:                rule.Dependencies,
 	}
 }
 
@@ -245,5 +261,6 @@ func alertRuleVersionToAlertRule(version alertRuleVersion) alertRule {
 		NotificationSettings:        version.NotificationSettings,
 		Metadata:                    version.Metadata,
 		MissingSeriesEvalsToResolve: version.MissingSeriesEvalsToResolve,
+		Dependencies:                version.Dependencies,
 	}
 }
@@ -55,41 +55,47 @@ func (st InstanceDBStore) ListAlertInstances(ctx context.Context, cmd *models.Li
 // SaveAlertInstance is a handler for saving a new alert instance.
 func (st InstanceDBStore) SaveAlertInstance(ctx context.Context, alertInstance models.AlertInstance) error {
 	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
-		if err := models.ValidateAlertInstance(alertInstance); err != nil {
-			return err
-		}
-
-		labelTupleJSON, err := alertInstance.Labels.StringKey()
-		if err != nil {
-			return err
-		}
-		params := append(make([]any, 0),
-			alertInstance.RuleOrgID,
-			alertInstance.RuleUID,
-			labelTupleJSON,
-			alertInstance.LabelsHash,
-			alertInstance.CurrentState,
-			alertInstance.CurrentReason,
-			alertInstance.CurrentStateSince.Unix(),
-			alertInstance.CurrentStateEnd.Unix(),
-			alertInstance.LastEvalTime.Unix(),
-			nullableTimeToUnix(alertInstance.FiredAt),
-			nullableTimeToUnix(alertInstance.ResolvedAt),
-			nullableTimeToUnix(alertInstance.LastSentAt),
-			alertInstance.ResultFingerprint,
-		)
+		return st.saveAlertInstance(sess, alertInstance)
+	})
+}
 
-		upsertSQL := st.SQLStore.GetDialect().UpsertSQL(
-			"alert_instance",
-			[]string{"rule_org_id", "rule_uid", "labels_hash"},
-			[]string{"rule_org_id", "rule_uid", "labels", "labels_hash", "current_state", "current_reason", "current_state_since", "current_state_end", "last_eval_time", "fired_at", "resolved_at", "last_sent_at", "result_fingerprint"})
-		_, err = sess.SQL(upsertSQL, params...).Query()
-		if err != nil {
-			return err
-		}
+func (st InstanceDBStore) saveAlertInstance(sess *db.Session, alertInstance models.AlertInstance) error {
+	if err := models.ValidateAlertInstance(alertInstance); err != nil {
+		return err
+	}
 
-		return nil
-	})
+	labelTupleJSON, err := alertInstance.Labels.StringKey()
+	if err != nil {
+		return err
+	}
+	params := append(make([]any, 0),
+		alertInstance.RuleOrgID,
+		alertInstance.RuleUID,
+		labelTupleJSON,
+		alertInstance.LabelsHash,
+		alertInstance.CurrentState,
+		alertInstance.CurrentReason,
+		alertInstance.CurrentStateSince.Unix(),
+		alertInstance.CurrentStateEnd.Unix(),
+		alertInstance.LastEvalTime.Unix(),
+		nullableTimeToUnix(alertInstance.FiredAt),
+		nullableTimeToUnix(alertInstance.ResolvedAt),
+		nullableTimeToUnix(alertInstance.LastSentAt),
+		alertInstance.ResultFingerprint,
+		alertInstance.AcknowledgedBy,
+		nullableTimeToUnix(alertInstance.AcknowledgedAt),
+		alertInstance.AcknowledgementComment,
+		nullableTimeToUnix(alertInstance.AcknowledgementExpiresAt),
+		alertInstance.AcknowledgementSilenceID,
+	)
+
+	upsertSQL := st.SQLStore.GetDialect().UpsertSQL(
+		"alert_instance",
+		[]string{"rule_org_id", "rule_uid", "labels_hash"},
+		[]string{"rule_org_id", "rule_uid", "labels", "labels_hash", "current_state", "current_reason", "current_state_since", "current_state_end", "last_eval_time", "fired_at", "resolved_at", "last_sent_at", "result_fingerprint",
+			"acknowledged_by", "acknowledged_at", "acknowledgement_comment", "acknowledgement_expires_at", "acknowledgement_silence_id"})
+	_, err = sess.SQL(upsertSQL, params...).Query()
+	return err
 }
 
 // DeleteAlertInstances deletes instances with the provided keys in a single transaction.
@@ -191,10 +197,19 @@ func (st InstanceDBStore) DeleteAlertInstances(ctx context.Context, keys ...mode
 	return err
 }
 
-// SaveAlertInstancesForRule is not implemented for instance database store.
+// SaveAlertInstancesForRule replaces the alert instances of the rule with the given instances in a single transaction.
 func (st InstanceDBStore) SaveAlertInstancesForRule(ctx context.Context, key models.AlertRuleKeyWithGroup, instances []models.AlertInstance) error {
-	st.Logger.Error("SaveAlertInstancesForRule is not implemented for instance database store.")
-	return errors.New("method SaveAlertInstancesForRule is not implemented for instance database store")
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Exec("DELETE FROM alert_instance WHERE rule_org_id = ? AND rule_uid = ?", key.OrgID, key.UID); err != nil {
+			return fmt.Errorf("failed to delete alert instances of the rule: %w", err)
+		}
+		for _, instance := range instances {
+			if err := st.saveAlertInstance(sess, instance); err != nil {
+				return fmt.Errorf("failed to save alert instance: %w", err)
+			}
+		}
+		return nil
+	})
 }
 
 // DeleteAlertInstancesByRule deletes all instances for a given rule.
@@ -256,10 +271,11 @@ func (st InstanceDBStore) insertInstancesBatch(sess *sqlstore.DBSession, batch [
 
 	query := strings.Builder{}
 	placeholders := make([]string, 0, len(batch))
-	args := make([]any, 0, len(batch)*12)
+	args := make([]any, 0, len(batch)*17)
 
 	query.WriteString("INSERT INTO alert_instance ")
-	query.WriteString("(rule_org_id, rule_uid, labels, labels_hash, current_state, current_reason, current_state_since, current_state_end, last_eval_time, fired_at, resolved_at, last_sent_at) VALUES ")
+	query.WriteString("(rule_org_id, rule_uid, labels, labels_hash, current_state, current_reason, current_state_since, current_state_end, last_eval_time, fired_at, resolved_at, last_sent_at, ")
+	query.WriteString("acknowledged_by, acknowledged_at, acknowledgement_comment, acknowledgement_expires_at, acknowledgement_silence_id) VALUES ")
 
 	for _, instance := range batch {
 		if err := models.ValidateAlertInstance(instance); err != nil {
@@ -273,7 +289,7 @@ func (st InstanceDBStore) insertInstancesBatch(sess *sqlstore.DBSession, batch [
 			continue
 		}
 
-		placeholders = append(placeholders, "(?,?,?,?,?,?,?,?,?,?,?,?)")
+		placeholders = append(placeholders, "(?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)")
 		args = append(args,
 			instance.RuleOrgID,
 			instance.RuleUID,
@@ -287,6 +303,11 @@ func (st InstanceDBStore) insertInstancesBatch(sess *sqlstore.DBSession, batch [
 			nullableTimeToUnix(instance.FiredAt),
 			nullableTimeToUnix(instance.ResolvedAt),
 			nullableTimeToUnix(instance.LastSentAt),
+			instance.AcknowledgedBy,
+			nullableTimeToUnix(instance.AcknowledgedAt),
+			instance.AcknowledgementComment,
+			nullableTimeToUnix(instance.AcknowledgementExpiresAt),
+			instance.AcknowledgementSilenceID,
 		)
 	}
 
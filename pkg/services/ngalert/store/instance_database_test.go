@@ -82,6 +82,23 @@ func TestIntegration_CompressedAlertRuleStateOperations(t *testing.T) {
 				containsHash(t, alerts, "hash2")
 			},
 		},
+		{
+			name: "can save and read acknowledgement of alert instance",
+			setupInstances: func() []models.AlertInstance {
+				instance := createAlertInstance(alertRule1.OrgID, alertRule1.UID, "hash1", "", models.InstanceStateFiring)
+				instance.SetAcknowledgement(testAcknowledgement())
+				return []models.AlertInstance{instance}
+			},
+			listQuery: &models.ListAlertInstancesQuery{
+				RuleOrgID: alertRule1.OrgID,
+				RuleUID:   alertRule1.UID,
+			},
+			validate: func(t *testing.T, alerts []*models.AlertInstance) {
+				require.Len(t, alerts, 1)
+				ack := testAcknowledgement()
+				require.Truef(t, ack.Equals(alerts[0].GetAcknowledgement()), "expected %v, got %v", ack, alerts[0].GetAcknowledgement())
+			},
+		},
 	}
 
 	for _, tc := range tests {
@@ -110,6 +127,18 @@ func containsHash(t *testing.T, instances []*models.AlertInstance, hash string)
 	require.Fail(t, fmt.Sprintf("%v does not contain an instance with hash %s", instances, hash))
 }
 
+func testAcknowledgement() *models.AlertInstanceAcknowledgement {
+	at := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	expiresAt := at.Add(time.Hour)
+	return &models.AlertInstanceAcknowledgement{
+		By:        "admin",
+		At:        at,
+		Comment:   "looking into it",
+		ExpiresAt: &expiresAt,
+		SilenceID: "silence-id",
+	}
+}
+
 func createAlertInstance(orgID int64, ruleUID, labelsHash, reason string, state models.InstanceStateType) models.AlertInstance {
 	return models.AlertInstance{
 		AlertInstanceKey: models.AlertInstanceKey{
@@ -298,6 +327,65 @@ func TestIntegrationAlertInstanceOperations(t *testing.T) {
 		require.Equal(t, instance2.Labels, alerts[0].Labels)
 		require.Equal(t, instance2.CurrentState, alerts[0].CurrentState)
 	})
+
+	t.Run("can replace the alert instances of a rule", func(t *testing.T) {
+		alertRule5 := tests.CreateTestAlertRule(t, ctx, dbstore, 60, mainOrgID)
+		err := ng.InstanceStore.SaveAlertInstance(ctx, createAlertInstance(alertRule5.OrgID, alertRule5.UID, "hash1", "", models.InstanceStateFiring))
+		require.NoError(t, err)
+
+		instances := []models.AlertInstance{
+			createAlertInstance(alertRule5.OrgID, alertRule5.UID, "hash2", "", models.InstanceStatePending),
+			createAlertInstance(alertRule5.OrgID, alertRule5.UID, "hash3", "", models.InstanceStateNormal),
+		}
+		err = ng.InstanceStore.SaveAlertInstancesForRule(ctx, alertRule5.GetKeyWithGroup(), instances)
+		require.NoError(t, err)
+
+		alerts, err := ng.InstanceStore.ListAlertInstances(ctx, &models.ListAlertInstancesQuery{
+			RuleOrgID: alertRule5.OrgID,
+			RuleUID:   alertRule5.UID,
+		})
+		require.NoError(t, err)
+		require.Len(t, alerts, 2)
+		containsHash(t, alerts, "hash2")
+		containsHash(t, alerts, "hash3")
+
+		// other rules are not affected
+		alerts, err = ng.InstanceStore.ListAlertInstances(ctx, &models.ListAlertInstancesQuery{
+			RuleOrgID: alertRule4.OrgID,
+			RuleUID:   alertRule4.UID,
+		})
+		require.NoError(t, err)
+		require.Len(t, alerts, 1)
+	})
+
+	t.Run("can save and read acknowledgement of alert instance", func(t *testing.T) {
+		alertRule6 := tests.CreateTestAlertRule(t, ctx, dbstore, 60, mainOrgID)
+		instance := createAlertInstance(alertRule6.OrgID, alertRule6.UID, "hash1", "", models.InstanceStateFiring)
+		ack := testAcknowledgement()
+		instance.SetAcknowledgement(ack)
+		err := ng.InstanceStore.SaveAlertInstance(ctx, instance)
+		require.NoError(t, err)
+
+		alerts, err := ng.InstanceStore.ListAlertInstances(ctx, &models.ListAlertInstancesQuery{
+			RuleOrgID: alertRule6.OrgID,
+			RuleUID:   alertRule6.UID,
+		})
+		require.NoError(t, err)
+		require.Len(t, alerts, 1)
+		require.Truef(t, ack.Equals(alerts[0].GetAcknowledgement()), "expected %v, got %v", ack, alerts[0].GetAcknowledgement())
+
+		instance.SetAcknowledgement(nil)
+		err = ng.InstanceStore.SaveAlertInstance(ctx, instance)
+		require.NoError(t, err)
+
+		alerts, err = ng.InstanceStore.ListAlertInstances(ctx, &models.ListAlertInstancesQuery{
+			RuleOrgID: alertRule6.OrgID,
+			RuleUID:   alertRule6.UID,
+		})
+		require.NoError(t, err)
+		require.Len(t, alerts, 1)
+		require.Nil(t, alerts[0].GetAcknowledgement())
+	})
 }
 
 func TestIntegrationFullSync(t *testing.T) {
@@ -31,12 +31,66 @@ type alertRule struct {
 	NotificationSettings        string `xorm:"notification_settings"`
 	Metadata                    string `xorm:"metadata"`
 	MissingSeriesEvalsToResolve *int   `xorm:"missing_series_evals_to_resolve"`
+	Dependencies                string `xorm:"dependencies"`
 }
 
 func (a alertRule) TableName() string {
 	return "alert_rule"
 }
 
+// alertRuleTemplate represents a record in alert_rule_template table
+type alertRuleTemplate struct {
+	ID              int64  `xorm:"pk autoincr 'id'"`
+	OrgID           int64  `xorm:"org_id"`
+	UID             string `xorm:"uid"`
+	NamespaceUID    string `xorm:"namespace_uid"`
+	RuleGroup       string
+	IntervalSeconds int64
+	Parameters      string
+	Rule            string
+	Instances       string
+	Version         int64 `xorm:"version"` // this tag makes xorm add optimistic lock (see https://xorm.io/docs/chapter-06/1.lock/)
+	Updated         time.Time
+	UpdatedBy       *string `xorm:"updated_by"`
+}
+
+func (a alertRuleTemplate) TableName() string {
+	return "alert_rule_template"
+}
+
+// recurringSilence represents a record in alert_recurring_silence table
+type recurringSilence struct {
+	ID        int64  `xorm:"pk autoincr 'id'"`
+	OrgID     int64  `xorm:"org_id"`
+	UID       string `xorm:"uid"`
+	Matchers  string
+	Comment   string
+	CreatedBy string
+	Schedule  string
+	Version   int64 `xorm:"version"` // this tag makes xorm add optimistic lock (see https://xorm.io/docs/chapter-06/1.lock/)
+	Updated   time.Time
+	UpdatedBy *string `xorm:"updated_by"`
+}
+
+func (a recurringSilence) TableName() string {
+	return "alert_recurring_silence"
+}
+
+// recurringSilenceState represents a record in alert_recurring_silence_state table
+type recurringSilenceState struct {
+	ID                      int64  `xorm:"pk autoincr 'id'"`
+	OrgID                   int64  `xorm:"org_id"`
+	RecurringSilenceUID     string `xorm:"recurring_silence_uid"`
+	RecurringSilenceVersion int64
+	MaterializedUntil       int64
+	Silences                string
+	Version                 int64 `xorm:"version"` // this tag makes xorm add optimistic lock (see https://xorm.io/docs/chapter-06/1.lock/)
+}
+
+func (a recurringSilenceState) TableName() string {
+	return "alert_recurring_silence_state"
+}
+
 // alertRuleVersion represents a record in alert_rule_version table
 type alertRuleVersion struct {
 	ID               int64  `xorm:"pk autoincr 'id'"`
@@ -69,6 +123,7 @@ type alertRuleVersion struct {
 	NotificationSettings        string `xorm:"notification_settings"`
 	Metadata                    string `xorm:"metadata"`
 	MissingSeriesEvalsToResolve *int   `xorm:"missing_series_evals_to_resolve"`
+	Dependencies                string `xorm:"dependencies"`
 }
 
 // EqualSpec compares two alertRuleVersion objects for equality based on their specifications and returns true if they match.
@@ -93,7 +148,8 @@ func (a alertRuleVersion) EqualSpec(b alertRuleVersion) bool {
 		a.IsPaused == b.IsPaused &&
 		a.NotificationSettings == b.NotificationSettings &&
 		a.Metadata == b.Metadata &&
-		a.MissingSeriesEvalsToResolve == b.MissingSeriesEvalsToResolve
+		a.MissingSeriesEvalsToResolve == b.MissingSeriesEvalsToResolve &&
+		a.Dependencies == b.Dependencies
 }
 
 func (a alertRuleVersion) TableName() string {
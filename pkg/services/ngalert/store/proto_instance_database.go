@@ -141,17 +141,22 @@ func (st ProtoInstanceDBStore) FullSync(ctx context.Context, instances []models.
 
 func alertInstanceModelToProto(modelInstance models.AlertInstance) *pb.AlertInstance {
 	return &pb.AlertInstance{
-		Labels:            modelInstance.Labels,
-		LabelsHash:        modelInstance.LabelsHash,
-		CurrentState:      string(modelInstance.CurrentState),
-		CurrentStateSince: timestamppb.New(modelInstance.CurrentStateSince),
-		CurrentStateEnd:   timestamppb.New(modelInstance.CurrentStateEnd),
-		CurrentReason:     modelInstance.CurrentReason,
-		LastEvalTime:      timestamppb.New(modelInstance.LastEvalTime),
-		LastSentAt:        nullableTimeToTimestamp(modelInstance.LastSentAt),
-		FiredAt:           nullableTimeToTimestamp(modelInstance.FiredAt),
-		ResolvedAt:        nullableTimeToTimestamp(modelInstance.ResolvedAt),
-		ResultFingerprint: modelInstance.ResultFingerprint,
+		Labels:                   modelInstance.Labels,
+		LabelsHash:               modelInstance.LabelsHash,
+		CurrentState:             string(modelInstance.CurrentState),
+		CurrentStateSince:        timestamppb.New(modelInstance.CurrentStateSince),
+		CurrentStateEnd:          timestamppb.New(modelInstance.CurrentStateEnd),
+		CurrentReason:            modelInstance.CurrentReason,
+		LastEvalTime:             timestamppb.New(modelInstance.LastEvalTime),
+		LastSentAt:               nullableTimeToTimestamp(modelInstance.LastSentAt),
+		FiredAt:                  nullableTimeToTimestamp(modelInstance.FiredAt),
+		ResolvedAt:               nullableTimeToTimestamp(modelInstance.ResolvedAt),
+		ResultFingerprint:        modelInstance.ResultFingerprint,
+		AcknowledgedBy:           modelInstance.AcknowledgedBy,
+		AcknowledgedAt:           nullableTimeToTimestamp(modelInstance.AcknowledgedAt),
+		AcknowledgementComment:   modelInstance.AcknowledgementComment,
+		AcknowledgementExpiresAt: nullableTimeToTimestamp(modelInstance.AcknowledgementExpiresAt),
+		AcknowledgementSilenceId: modelInstance.AcknowledgementSilenceID,
 	}
 }
 
@@ -185,16 +190,21 @@ func alertInstanceProtoToModel(ruleUID string, ruleOrgID int64, protoInstance *p
 			RuleUID:    ruleUID,
 			LabelsHash: protoInstance.LabelsHash,
 		},
-		Labels:            protoInstance.Labels,
-		CurrentState:      models.InstanceStateType(protoInstance.CurrentState),
-		CurrentStateSince: protoInstance.CurrentStateSince.AsTime(),
-		CurrentStateEnd:   protoInstance.CurrentStateEnd.AsTime(),
-		CurrentReason:     protoInstance.CurrentReason,
-		LastEvalTime:      protoInstance.LastEvalTime.AsTime(),
-		LastSentAt:        nullableTimestampToTime(protoInstance.LastSentAt),
-		FiredAt:           nullableTimestampToTime(protoInstance.FiredAt),
-		ResolvedAt:        nullableTimestampToTime(protoInstance.ResolvedAt),
-		ResultFingerprint: protoInstance.ResultFingerprint,
+		Labels:                   protoInstance.Labels,
+		CurrentState:             models.InstanceStateType(protoInstance.CurrentState),
+		CurrentStateSince:        protoInstance.CurrentStateSince.AsTime(),
+		CurrentStateEnd:          protoInstance.CurrentStateEnd.AsTime(),
+		CurrentReason:            protoInstance.CurrentReason,
+		LastEvalTime:             protoInstance.LastEvalTime.AsTime(),
+		LastSentAt:               nullableTimestampToTime(protoInstance.LastSentAt),
+		FiredAt:                  nullableTimestampToTime(protoInstance.FiredAt),
+		ResolvedAt:               nullableTimestampToTime(protoInstance.ResolvedAt),
+		ResultFingerprint:        protoInstance.ResultFingerprint,
+		AcknowledgedBy:           protoInstance.AcknowledgedBy,
+		AcknowledgedAt:           nullableTimestampToTime(protoInstance.AcknowledgedAt),
+		AcknowledgementComment:   protoInstance.AcknowledgementComment,
+		AcknowledgementExpiresAt: nullableTimestampToTime(protoInstance.AcknowledgementExpiresAt),
+		AcknowledgementSilenceID: protoInstance.AcknowledgementSilenceId,
 	}
 }
 
@@ -0,0 +1,294 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/timeinterval"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+type recurringSilenceSchedule struct {
+	Cron          string                      `json:"cron,omitempty"`
+	Duration      time.Duration               `json:"duration,omitempty"`
+	TimeIntervals []timeinterval.TimeInterval `json:"time_intervals,omitempty"`
+	Timezone      string                      `json:"timezone,omitempty"`
+}
+
+type materializedSilence struct {
+	ID     string `json:"id"`
+	EndsAt int64  `json:"ends_at"`
+}
+
+// ListRecurringSilences returns the recurring silences that match the query ordered by organization and UID.
+func (st DBstore) ListRecurringSilences(ctx context.Context, query *ngmodels.ListRecurringSilencesQuery) ([]*ngmodels.RecurringSilence, error) {
+	var result []*ngmodels.RecurringSilence
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		q := sess.Table(recurringSilence{})
+		if query.OrgID > 0 {
+			q = q.Where("org_id = ?", query.OrgID)
+		}
+		var silences []recurringSilence
+		if err := q.Asc("org_id", "uid").Find(&silences); err != nil {
+			return err
+		}
+		result = make([]*ngmodels.RecurringSilence, 0, len(silences))
+		for _, s := range silences {
+			converted, err := recurringSilenceToModelsRecurringSilence(s)
+			if err != nil {
+				st.Logger.Error("Invalid recurring silence found in DB store, ignoring it", "org", s.OrgID, "uid", s.UID, "error", err)
+				continue
+			}
+			result = append(result, &converted)
+		}
+		return nil
+	})
+	return result, err
+}
+
+// GetRecurringSilence returns the recurring silence with the given UID.
+// It returns ngmodels.ErrRecurringSilenceNotFound if there is no such recurring silence.
+func (st DBstore) GetRecurringSilence(ctx context.Context, orgID int64, uid string) (*ngmodels.RecurringSilence, error) {
+	var result *ngmodels.RecurringSilence
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		s := recurringSilence{OrgID: orgID, UID: uid}
+		has, err := sess.Get(&s)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return ngmodels.ErrRecurringSilenceNotFound.Errorf("")
+		}
+		converted, err := recurringSilenceToModelsRecurringSilence(s)
+		if err != nil {
+			return fmt.Errorf("failed to convert recurring silence: %w", err)
+		}
+		result = &converted
+		return nil
+	})
+	return result, err
+}
+
+// InsertRecurringSilence inserts a new recurring silence and returns it with the generated ID and UID.
+func (st DBstore) InsertRecurringSilence(ctx context.Context, user *ngmodels.UserUID, silence ngmodels.RecurringSilence) (ngmodels.RecurringSilence, error) {
+	err := st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if silence.UID == "" {
+			silence.UID = util.GenerateShortUID()
+		}
+		silence.Version = 1
+		silence.Updated = TimeNow()
+		silence.UpdatedBy = user
+		converted, err := recurringSilenceFromModelsRecurringSilence(silence)
+		if err != nil {
+			return fmt.Errorf("failed to convert recurring silence to storage model: %w", err)
+		}
+		if _, err := sess.Insert(&converted); err != nil {
+			if st.SQLStore.GetDialect().IsUniqueConstraintViolation(err) {
+				return ngmodels.ErrRecurringSilenceExists.Errorf("")
+			}
+			return fmt.Errorf("failed to insert recurring silence: %w", err)
+		}
+		silence.ID = converted.ID
+		return nil
+	})
+	return silence, err
+}
+
+// UpdateRecurringSilence updates the recurring silence. The version of the recurring silence must be the version of
+// the stored recurring silence, otherwise ErrOptimisticLock is returned.
+func (st DBstore) UpdateRecurringSilence(ctx context.Context, user *ngmodels.UserUID, silence ngmodels.RecurringSilence) (ngmodels.RecurringSilence, error) {
+	err := st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		silence.Updated = TimeNow()
+		silence.UpdatedBy = user
+		converted, err := recurringSilenceFromModelsRecurringSilence(silence)
+		if err != nil {
+			return fmt.Errorf("failed to convert recurring silence to storage model: %w", err)
+		}
+		updated, err := sess.ID(silence.ID).AllCols().Update(&converted)
+		if err != nil {
+			return fmt.Errorf("failed to update recurring silence %s: %w", silence.UID, err)
+		}
+		if updated == 0 {
+			return fmt.Errorf("%w: recurring silence UID %s version %d", ErrOptimisticLock, silence.UID, silence.Version)
+		}
+		silence.Version++
+		return nil
+	})
+	return silence, err
+}
+
+// DeleteRecurringSilence deletes the recurring silence with the given UID. The silences created for it are expired
+// when the state of the recurring silence is reconciled.
+func (st DBstore) DeleteRecurringSilence(ctx context.Context, orgID int64, uid string) error {
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Delete(recurringSilence{})
+		return err
+	})
+}
+
+// ListRecurringSilenceStates returns the materialization states of the recurring silences of all organizations.
+func (st DBstore) ListRecurringSilenceStates(ctx context.Context) ([]*ngmodels.RecurringSilenceState, error) {
+	var result []*ngmodels.RecurringSilenceState
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var states []recurringSilenceState
+		if err := sess.Table(recurringSilenceState{}).Asc("org_id", "recurring_silence_uid").Find(&states); err != nil {
+			return err
+		}
+		result = make([]*ngmodels.RecurringSilenceState, 0, len(states))
+		for _, s := range states {
+			converted, err := recurringSilenceStateToModelsRecurringSilenceState(s)
+			if err != nil {
+				st.Logger.Error("Invalid recurring silence state found in DB store, ignoring it", "org", s.OrgID, "uid", s.RecurringSilenceUID, "error", err)
+				continue
+			}
+			result = append(result, &converted)
+		}
+		return nil
+	})
+	return result, err
+}
+
+// SaveRecurringSilenceState inserts the state if its version is 0 and updates it otherwise. ErrOptimisticLock is returned
+// if the state was changed or inserted by someone else in the meantime.
+func (st DBstore) SaveRecurringSilenceState(ctx context.Context, state ngmodels.RecurringSilenceState) (ngmodels.RecurringSilenceState, error) {
+	err := st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		converted, err := recurringSilenceStateFromModelsRecurringSilenceState(state)
+		if err != nil {
+			return fmt.Errorf("failed to convert recurring silence state to storage model: %w", err)
+		}
+		if state.Version == 0 {
+			converted.Version = 1
+			if _, err := sess.Insert(&converted); err != nil {
+				if st.SQLStore.GetDialect().IsUniqueConstraintViolation(err) {
+					return fmt.Errorf("%w: state of recurring silence UID %s was inserted concurrently", ErrOptimisticLock, state.UID)
+				}
+				return fmt.Errorf("failed to insert recurring silence state: %w", err)
+			}
+			state.Version = 1
+			return nil
+		}
+		updated, err := sess.Where("org_id = ? AND recurring_silence_uid = ?", state.OrgID, state.UID).AllCols().Omit("id").Update(&converted)
+		if err != nil {
+			return fmt.Errorf("failed to update recurring silence state %s: %w", state.UID, err)
+		}
+		if updated == 0 {
+			return fmt.Errorf("%w: state of recurring silence UID %s version %d", ErrOptimisticLock, state.UID, state.Version)
+		}
+		state.Version++
+		return nil
+	})
+	return state, err
+}
+
+// DeleteRecurringSilenceState deletes the state if its version is the stored version, otherwise ErrOptimisticLock is returned.
+func (st DBstore) DeleteRecurringSilenceState(ctx context.Context, state ngmodels.RecurringSilenceState) error {
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		deleted, err := sess.Where("org_id = ? AND recurring_silence_uid = ? AND version = ?", state.OrgID, state.UID, state.Version).Delete(recurringSilenceState{})
+		if err != nil {
+			return err
+		}
+		if deleted == 0 {
+			return fmt.Errorf("%w: state of recurring silence UID %s version %d", ErrOptimisticLock, state.UID, state.Version)
+		}
+		return nil
+	})
+}
+
+func recurringSilenceToModelsRecurringSilence(s recurringSilence) (ngmodels.RecurringSilence, error) {
+	result := ngmodels.RecurringSilence{
+		ID:        s.ID,
+		OrgID:     s.OrgID,
+		UID:       s.UID,
+		Comment:   s.Comment,
+		CreatedBy: s.CreatedBy,
+		Version:   s.Version,
+		Updated:   s.Updated,
+	}
+	if s.UpdatedBy != nil {
+		result.UpdatedBy = util.Pointer(ngmodels.UserUID(*s.UpdatedBy))
+	}
+	if err := json.Unmarshal([]byte(s.Matchers), &result.Matchers); err != nil {
+		return ngmodels.RecurringSilence{}, fmt.Errorf("failed to parse matchers: %w", err)
+	}
+	var schedule recurringSilenceSchedule
+	if err := json.Unmarshal([]byte(s.Schedule), &schedule); err != nil {
+		return ngmodels.RecurringSilence{}, fmt.Errorf("failed to parse schedule: %w", err)
+	}
+	result.Schedule = ngmodels.RecurringSilenceSchedule(schedule)
+	return result, nil
+}
+
+func recurringSilenceFromModelsRecurringSilence(s ngmodels.RecurringSilence) (recurringSilence, error) {
+	result := recurringSilence{
+		ID:        s.ID,
+		OrgID:     s.OrgID,
+		UID:       s.UID,
+		Comment:   s.Comment,
+		CreatedBy: s.CreatedBy,
+		Version:   s.Version,
+		Updated:   s.Updated,
+	}
+	if s.UpdatedBy != nil {
+		result.UpdatedBy = util.Pointer(string(*s.UpdatedBy))
+	}
+	matchers := s.Matchers
+	if matchers == nil {
+		matchers = amv2.Matchers{}
+	}
+	b, err := json.Marshal(matchers)
+	if err != nil {
+		return recurringSilence{}, fmt.Errorf("failed to marshal matchers: %w", err)
+	}
+	result.Matchers = string(b)
+	b, err = json.Marshal(recurringSilenceSchedule(s.Schedule))
+	if err != nil {
+		return recurringSilence{}, fmt.Errorf("failed to marshal schedule: %w", err)
+	}
+	result.Schedule = string(b)
+	return result, nil
+}
+
+func recurringSilenceStateToModelsRecurringSilenceState(s recurringSilenceState) (ngmodels.RecurringSilenceState, error) {
+	result := ngmodels.RecurringSilenceState{
+		OrgID:                   s.OrgID,
+		UID:                     s.RecurringSilenceUID,
+		RecurringSilenceVersion: s.RecurringSilenceVersion,
+		MaterializedUntil:       time.UnixMilli(s.MaterializedUntil),
+		Version:                 s.Version,
+	}
+	var silences []materializedSilence
+	if err := json.Unmarshal([]byte(s.Silences), &silences); err != nil {
+		return ngmodels.RecurringSilenceState{}, fmt.Errorf("failed to parse silences: %w", err)
+	}
+	result.Silences = make([]ngmodels.MaterializedSilence, 0, len(silences))
+	for _, sil := range silences {
+		result.Silences = append(result.Silences, ngmodels.MaterializedSilence{ID: sil.ID, EndsAt: time.UnixMilli(sil.EndsAt)})
+	}
+	return result, nil
+}
+
+func recurringSilenceStateFromModelsRecurringSilenceState(s ngmodels.RecurringSilenceState) (recurringSilenceState, error) {
+	result := recurringSilenceState{
+		OrgID:                   s.OrgID,
+		RecurringSilenceUID:     s.UID,
+		RecurringSilenceVersion: s.RecurringSilenceVersion,
+		MaterializedUntil:       s.MaterializedUntil.UnixMilli(),
+		Version:                 s.Version,
+	}
+	silences := make([]materializedSilence, 0, len(s.Silences))
+	for _, sil := range s.Silences {
+		silences = append(silences, materializedSilence{ID: sil.ID, EndsAt: sil.EndsAt.UnixMilli()})
+	}
+	b, err := json.Marshal(silences)
+	if err != nil {
+		return recurringSilenceState{}, fmt.Errorf("failed to marshal silences: %w", err)
+	}
+	result.Silences = string(b)
+	return result, nil
+}
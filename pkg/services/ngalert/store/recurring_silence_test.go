@@ -0,0 +1,127 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log/logtest"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestIntegrationRecurringSilences(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	sqlStore := db.InitTestDB(t)
+	cfg := setting.NewCfg()
+	cfg.UnifiedAlerting = setting.UnifiedAlertingSettings{BaseInterval: 10 * time.Second}
+	folderService := setupFolderService(t, sqlStore, cfg, featuremgmt.WithFeatures())
+	store := createTestStore(sqlStore, folderService, &logtest.Fake{}, cfg.UnifiedAlerting, &fakeBus{})
+	usr := models.UserUID("1234")
+	ctx := context.Background()
+
+	newSilence := func(orgID int64, uid string) models.RecurringSilence {
+		return models.RecurringSilence{
+			OrgID:     orgID,
+			UID:       uid,
+			Matchers:  amv2.Matchers{{Name: util.Pointer("cluster"), Value: util.Pointer("eu-1"), IsEqual: util.Pointer(true), IsRegex: util.Pointer(false)}},
+			Comment:   "Maintenance",
+			CreatedBy: "team-a",
+			Schedule:  models.RecurringSilenceSchedule{Cron: "0 2 * * 0", Duration: 2 * time.Hour, Timezone: "Europe/Berlin"},
+		}
+	}
+
+	created, err := store.InsertRecurringSilence(ctx, &usr, newSilence(1, "maintenance"))
+	require.NoError(t, err)
+	require.NotZero(t, created.ID)
+	require.Equal(t, int64(1), created.Version)
+	_, err = store.InsertRecurringSilence(ctx, &usr, newSilence(2, ""))
+	require.NoError(t, err)
+
+	t.Run("should get recurring silence by UID", func(t *testing.T) {
+		stored, err := store.GetRecurringSilence(ctx, 1, "maintenance")
+		require.NoError(t, err)
+		require.Equal(t, created.Matchers, stored.Matchers)
+		require.Equal(t, created.Schedule, stored.Schedule)
+		require.Equal(t, "team-a", stored.CreatedBy)
+		require.Equal(t, usr, *stored.UpdatedBy)
+
+		_, err = store.GetRecurringSilence(ctx, 2, "maintenance")
+		require.ErrorIs(t, err, models.ErrRecurringSilenceNotFound)
+	})
+
+	t.Run("should fail to insert a recurring silence with the same UID", func(t *testing.T) {
+		_, err := store.InsertRecurringSilence(ctx, &usr, newSilence(1, "maintenance"))
+		require.ErrorIs(t, err, models.ErrRecurringSilenceExists)
+	})
+
+	t.Run("should list recurring silences of one or all organizations", func(t *testing.T) {
+		silences, err := store.ListRecurringSilences(ctx, &models.ListRecurringSilencesQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, silences, 1)
+
+		silences, err = store.ListRecurringSilences(ctx, &models.ListRecurringSilencesQuery{})
+		require.NoError(t, err)
+		require.Len(t, silences, 2)
+	})
+
+	t.Run("should update recurring silence with optimistic lock", func(t *testing.T) {
+		s := created
+		s.Comment = "Changed"
+		updated, err := store.UpdateRecurringSilence(ctx, &usr, s)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), updated.Version)
+
+		_, err = store.UpdateRecurringSilence(ctx, &usr, s)
+		require.ErrorIs(t, err, ErrOptimisticLock)
+	})
+
+	t.Run("should save recurring silence state with optimistic lock", func(t *testing.T) {
+		state := models.RecurringSilenceState{
+			OrgID:                   1,
+			UID:                     "maintenance",
+			RecurringSilenceVersion: 2,
+			MaterializedUntil:       time.UnixMilli(1717200000000),
+			Silences:                []models.MaterializedSilence{{ID: "silence-1", EndsAt: time.UnixMilli(1717207200000)}},
+		}
+		saved, err := store.SaveRecurringSilenceState(ctx, state)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), saved.Version)
+
+		_, err = store.SaveRecurringSilenceState(ctx, state)
+		require.ErrorIs(t, err, ErrOptimisticLock)
+
+		saved.Silences = []models.MaterializedSilence{}
+		saved, err = store.SaveRecurringSilenceState(ctx, saved)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), saved.Version)
+
+		states, err := store.ListRecurringSilenceStates(ctx)
+		require.NoError(t, err)
+		require.Len(t, states, 1)
+		require.Equal(t, saved, *states[0])
+
+		stale := saved
+		stale.Version = 1
+		require.ErrorIs(t, store.DeleteRecurringSilenceState(ctx, stale), ErrOptimisticLock)
+		require.NoError(t, store.DeleteRecurringSilenceState(ctx, saved))
+
+		states, err = store.ListRecurringSilenceStates(ctx)
+		require.NoError(t, err)
+		require.Empty(t, states)
+	})
+
+	t.Run("should delete recurring silence", func(t *testing.T) {
+		require.NoError(t, store.DeleteRecurringSilence(ctx, 1, "maintenance"))
+		_, err := store.GetRecurringSilence(ctx, 1, "maintenance")
+		require.ErrorIs(t, err, models.ErrRecurringSilenceNotFound)
+	})
+}
@@ -24,6 +24,7 @@ type RuleStore struct {
 	Rules       map[int64][]*models.AlertRule
 	History     map[string][]*models.AlertRule
 	Deleted     map[int64][]*models.AlertRule
+	Templates   map[int64][]*models.AlertRuleTemplate
 	Hook        func(cmd any) error // use Hook if you need to intercept some query and return an error
 	RecordedOps []any
 	Folders     map[int64][]*folder.Folder
@@ -41,8 +42,9 @@ func NewRuleStore(t *testing.T) *RuleStore {
 		Hook: func(any) error {
 			return nil
 		},
-		Folders: map[int64][]*folder.Folder{},
-		History: map[string][]*models.AlertRule{},
+		Folders:   map[int64][]*folder.Folder{},
+		History:   map[string][]*models.AlertRule{},
+		Templates: map[int64][]*models.AlertRuleTemplate{},
 	}
 }
 
@@ -480,3 +482,22 @@ func (f *RuleStore) ListDeletedRules(_ context.Context, orgID int64) ([]*models.
 	}
 	return f.Deleted[orgID], nil
 }
+
+func (f *RuleStore) ListAlertRuleTemplates(_ context.Context, q *models.ListAlertRuleTemplatesQuery) ([]*models.AlertRuleTemplate, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	if err := f.Hook(*q); err != nil {
+		return nil, err
+	}
+	result := make([]*models.AlertRuleTemplate, 0)
+	for _, t := range f.Templates[q.OrgID] {
+		if len(q.NamespaceUIDs) > 0 && !slices.Contains(q.NamespaceUIDs, t.NamespaceUID) {
+			continue
+		}
+		if len(q.RuleGroups) > 0 && !slices.Contains(q.RuleGroups, t.RuleGroup) {
+			continue
+		}
+		result = append(result, t)
+	}
+	return result, nil
+}
@@ -3,7 +3,9 @@ package alerting
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -34,6 +36,8 @@ const (
 	testFileCorrectProperties_t         = "./testdata/templates/correct-properties"
 	testFileCorrectPropertiesWithOrg_t  = "./testdata/templates/correct-properties-with-org"
 	testFileMultipleTs                  = "./testdata/templates/multiple-templates"
+	testFileCorrectProperties_ep        = "./testdata/escalation_policies/correct-properties"
+	testFileCorrectProperties_rs        = "./testdata/recurring_silences/correct-properties"
 )
 
 func TestConfigReader(t *testing.T) {
@@ -160,6 +164,41 @@ func TestConfigReader(t *testing.T) {
 		require.NoError(t, err)
 		require.Len(t, file[0].Templates, 2)
 	})
+	t.Run("an escalation policies file with correct properties should not error", func(t *testing.T) {
+		file, err := configReader.readConfig(ctx, testFileCorrectProperties_ep)
+		require.NoError(t, err)
+		require.Len(t, file[0].EscalationPolicies, 1)
+		policy := file[0].EscalationPolicies[0]
+		require.Equal(t, int64(1337), policy.OrgID)
+		require.Equal(t, "on-call", policy.Policy.Name)
+		require.Len(t, policy.Policy.Steps, 2)
+		require.Equal(t, model.Duration(15*time.Minute), policy.Policy.Steps[0].After)
+		require.Equal(t, []DeleteEscalationPolicy{{OrgID: 1, Name: "old-on-call"}}, file[0].DeleteEscalationPolicies)
+	})
+	t.Run("a recurring silences file with correct properties should not error", func(t *testing.T) {
+		file, err := configReader.readConfig(ctx, testFileCorrectProperties_rs)
+		require.NoError(t, err)
+		require.Len(t, file[0].RecurringSilences, 2)
+
+		cron := file[0].RecurringSilences[0]
+		require.Equal(t, int64(1337), cron.OrgID)
+		require.Equal(t, "eu-1-maintenance", cron.UID)
+		require.Equal(t, "team-a", cron.CreatedBy)
+		require.Len(t, cron.Matchers, 2)
+		require.True(t, *cron.Matchers[1].IsRegex)
+		require.Equal(t, 2*time.Hour, cron.Schedule.Duration)
+		require.Equal(t, "Europe/Berlin", cron.Schedule.Timezone)
+		require.NoError(t, cron.Validate())
+
+		intervals := file[0].RecurringSilences[1]
+		require.Equal(t, int64(1), intervals.OrgID)
+		require.Equal(t, "provisioning", intervals.CreatedBy)
+		require.False(t, *intervals.Matchers[0].IsEqual)
+		require.Len(t, intervals.Schedule.TimeIntervals, 1)
+		require.NoError(t, intervals.Validate())
+
+		require.Equal(t, []DeleteRecurringSilence{{OrgID: 1, UID: "old-maintenance"}}, file[0].DeleteRecurringSilences)
+	})
 	t.Run("a rule file with dasboard typo", func(t *testing.T) {
 		ruleFiles, err := configReader.readConfig(ctx, testFileDasboardTypoSupport)
 		require.NoError(t, err)
@@ -0,0 +1,77 @@
+package alerting
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+)
+
+type EscalationPoliciesProvisioner interface {
+	Provision(ctx context.Context, files []*AlertingFile) error
+	Unprovision(ctx context.Context, files []*AlertingFile) error
+}
+
+type defaultEscalationPoliciesProvisioner struct {
+	logger                  log.Logger
+	escalationPolicyService provisioning.EscalationPolicyService
+}
+
+func NewEscalationPoliciesProvisioner(logger log.Logger,
+	escalationPolicyService provisioning.EscalationPolicyService) EscalationPoliciesProvisioner {
+	return &defaultEscalationPoliciesProvisioner{
+		logger:                  logger,
+		escalationPolicyService: escalationPolicyService,
+	}
+}
+
+func (c *defaultEscalationPoliciesProvisioner) Provision(ctx context.Context,
+	files []*AlertingFile) error {
+	cache := map[int64]map[string]struct{}{}
+	for _, file := range files {
+		for _, policy := range file.EscalationPolicies {
+			if _, exists := cache[policy.OrgID]; !exists {
+				policies, err := c.escalationPolicyService.GetEscalationPolicies(ctx, policy.OrgID)
+				if err != nil {
+					return err
+				}
+				cache[policy.OrgID] = make(map[string]struct{}, len(policies))
+				for _, p := range policies {
+					cache[policy.OrgID][p.Name] = struct{}{}
+				}
+			}
+			p := definitions.ProvisionedEscalationPolicy{
+				EscalationPolicy: policy.Policy,
+				Provenance:       definitions.Provenance(models.ProvenanceFile),
+			}
+			if _, exists := cache[policy.OrgID][p.Name]; exists {
+				_, err := c.escalationPolicyService.UpdateEscalationPolicy(ctx, p.Name, p, policy.OrgID)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			_, err := c.escalationPolicyService.CreateEscalationPolicy(ctx, p, policy.OrgID)
+			if err != nil {
+				return err
+			}
+			cache[policy.OrgID][p.Name] = struct{}{}
+		}
+	}
+	return nil
+}
+
+func (c *defaultEscalationPoliciesProvisioner) Unprovision(ctx context.Context,
+	files []*AlertingFile) error {
+	for _, file := range files {
+		for _, deletePolicy := range file.DeleteEscalationPolicies {
+			err := c.escalationPolicyService.DeleteEscalationPolicy(ctx, deletePolicy.Name, deletePolicy.OrgID, definitions.Provenance(models.ProvenanceFile), "")
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package alerting
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+type EscalationPolicyV1 struct {
+	OrgID  values.Int64Value            `json:"orgId" yaml:"orgId"`
+	Policy definitions.EscalationPolicy `json:",inline" yaml:",inline"`
+}
+
+func (v1 *EscalationPolicyV1) mapToModel() EscalationPolicy {
+	orgID := v1.OrgID.Value()
+	if orgID < 1 {
+		orgID = 1
+	}
+	return EscalationPolicy{
+		OrgID:  orgID,
+		Policy: v1.Policy,
+	}
+}
+
+type EscalationPolicy struct {
+	OrgID  int64
+	Policy definitions.EscalationPolicy
+}
+
+type DeleteEscalationPolicyV1 struct {
+	OrgID values.Int64Value  `json:"orgId" yaml:"orgId"`
+	Name  values.StringValue `json:"name" yaml:"name"`
+}
+
+func (v1 *DeleteEscalationPolicyV1) mapToModel() (DeleteEscalationPolicy, error) {
+	name := strings.TrimSpace(v1.Name.Value())
+	if name == "" {
+		return DeleteEscalationPolicy{}, errors.New("delete escalation policy missing name")
+	}
+	orgID := v1.OrgID.Value()
+	if orgID < 1 {
+		orgID = 1
+	}
+	return DeleteEscalationPolicy{
+		OrgID: orgID,
+		Name:  name,
+	}, nil
+}
+
+type DeleteEscalationPolicy struct {
+	OrgID int64
+	Name  string
+}
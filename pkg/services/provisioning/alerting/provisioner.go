@@ -15,10 +15,13 @@ type ProvisionerConfig struct {
 	FolderService              folder.Service
 	DashboardProvService       dashboards.DashboardProvisioningService
 	RuleService                provisioning.AlertRuleService
+	RuleTemplateService        provisioning.AlertRuleTemplateService
 	ContactPointService        provisioning.ContactPointService
 	NotificiationPolicyService provisioning.NotificationPolicyService
 	MuteTimingService          provisioning.MuteTimingService
+	EscalationPolicyService    provisioning.EscalationPolicyService
 	TemplateService            provisioning.TemplateService
+	RecurringSilenceService    provisioning.RecurringSilenceService
 }
 
 func Provision(ctx context.Context, cfg ProvisionerConfig) error {
@@ -40,6 +43,11 @@ func Provision(ctx context.Context, cfg ProvisionerConfig) error {
 	if err != nil {
 		return fmt.Errorf("mute times: %w", err)
 	}
+	epProvisioner := NewEscalationPoliciesProvisioner(logger, cfg.EscalationPolicyService)
+	err = epProvisioner.Provision(ctx, files)
+	if err != nil {
+		return fmt.Errorf("escalation policies: %w", err)
+	}
 	ttProvsioner := NewTextTemplateProvisioner(logger, cfg.TemplateService)
 	err = ttProvsioner.Provision(ctx, files)
 	if err != nil {
@@ -58,6 +66,10 @@ func Provision(ctx context.Context, cfg ProvisionerConfig) error {
 	if err != nil {
 		return fmt.Errorf("mute times: %w", err)
 	}
+	err = epProvisioner.Unprovision(ctx, files)
+	if err != nil {
+		return fmt.Errorf("escalation policies: %w", err)
+	}
 	err = ttProvsioner.Unprovision(ctx, files)
 	if err != nil {
 		return fmt.Errorf("text templates: %w", err)
@@ -66,11 +78,21 @@ func Provision(ctx context.Context, cfg ProvisionerConfig) error {
 		logger,
 		cfg.FolderService,
 		cfg.DashboardProvService,
-		cfg.RuleService)
+		cfg.RuleService,
+		cfg.RuleTemplateService)
 	err = ruleProvisioner.Provision(ctx, files)
 	if err != nil {
 		return fmt.Errorf("alert rules: %w", err)
 	}
+	rsProvisioner := NewRecurringSilencesProvisioner(logger, cfg.RecurringSilenceService)
+	err = rsProvisioner.Provision(ctx, files)
+	if err != nil {
+		return fmt.Errorf("recurring silences: %w", err)
+	}
+	err = rsProvisioner.Unprovision(ctx, files)
+	if err != nil {
+		return fmt.Errorf("recurring silences: %w", err)
+	}
 	err = cpProvisioner.Unprovision(ctx, files) // Unprovision contact points after rules to make sure all references in rules are updated
 	if err != nil {
 		return fmt.Errorf("contact points: %w", err)
@@ -0,0 +1,64 @@
+package alerting
+
+import (
+	"context"
+	"errors"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+)
+
+type RecurringSilencesProvisioner interface {
+	Provision(ctx context.Context, files []*AlertingFile) error
+	Unprovision(ctx context.Context, files []*AlertingFile) error
+}
+
+type defaultRecurringSilencesProvisioner struct {
+	logger                  log.Logger
+	recurringSilenceService provisioning.RecurringSilenceService
+}
+
+func NewRecurringSilencesProvisioner(logger log.Logger,
+	recurringSilenceService provisioning.RecurringSilenceService) RecurringSilencesProvisioner {
+	return &defaultRecurringSilencesProvisioner{
+		logger:                  logger,
+		recurringSilenceService: recurringSilenceService,
+	}
+}
+
+func (c *defaultRecurringSilencesProvisioner) Provision(ctx context.Context,
+	files []*AlertingFile) error {
+	for _, file := range files {
+		for _, silence := range file.RecurringSilences {
+			u := provisionerUser(silence.OrgID)
+			_, _, err := c.recurringSilenceService.GetRecurringSilence(ctx, u, silence.UID)
+			if err != nil && !errors.Is(err, models.ErrRecurringSilenceNotFound) {
+				return err
+			} else if err != nil {
+				c.logger.Debug("creating recurring silence", "uid", silence.UID, "org", silence.OrgID)
+				_, err = c.recurringSilenceService.CreateRecurringSilence(ctx, u, silence, models.ProvenanceFile)
+			} else {
+				c.logger.Debug("updating recurring silence", "uid", silence.UID, "org", silence.OrgID)
+				_, err = c.recurringSilenceService.UpdateRecurringSilence(ctx, u, silence, models.ProvenanceFile)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *defaultRecurringSilencesProvisioner) Unprovision(ctx context.Context,
+	files []*AlertingFile) error {
+	for _, file := range files {
+		for _, deleteSilence := range file.DeleteRecurringSilences {
+			err := c.recurringSilenceService.DeleteRecurringSilence(ctx, provisionerUser(deleteSilence.OrgID), deleteSilence.UID, models.ProvenanceFile)
+			if err != nil && !errors.Is(err, models.ErrRecurringSilenceNotFound) {
+				return err
+			}
+		}
+	}
+	return nil
+}
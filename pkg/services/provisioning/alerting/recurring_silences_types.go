@@ -0,0 +1,92 @@
+package alerting
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/pkg/labels"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+type RecurringSilenceV1 struct {
+	OrgID     values.Int64Value  `json:"orgId" yaml:"orgId"`
+	UID       values.StringValue `json:"uid" yaml:"uid"`
+	Comment   values.StringValue `json:"comment" yaml:"comment"`
+	CreatedBy values.StringValue `json:"createdBy" yaml:"createdBy"`
+	// Matchers are in the Alertmanager format, for example cluster="eu-1" or severity=~"warning|critical".
+	Matchers []values.StringValue                 `json:"matchers" yaml:"matchers"`
+	Schedule definitions.RecurringSilenceSchedule `json:"schedule" yaml:"schedule"`
+}
+
+func (v1 *RecurringSilenceV1) mapToModel() (models.RecurringSilence, error) {
+	uid := strings.TrimSpace(v1.UID.Value())
+	if uid == "" {
+		return models.RecurringSilence{}, errors.New("recurring silence missing uid")
+	}
+	orgID := v1.OrgID.Value()
+	if orgID < 1 {
+		orgID = 1
+	}
+	matchers := make(amv2.Matchers, 0, len(v1.Matchers))
+	for _, m := range v1.Matchers {
+		matcher, err := labels.ParseMatcher(m.Value())
+		if err != nil {
+			return models.RecurringSilence{}, fmt.Errorf("recurring silence %s has an invalid matcher: %w", uid, err)
+		}
+		matchers = append(matchers, &amv2.Matcher{
+			Name:    util.Pointer(matcher.Name),
+			Value:   util.Pointer(matcher.Value),
+			IsEqual: util.Pointer(matcher.Type == labels.MatchEqual || matcher.Type == labels.MatchRegexp),
+			IsRegex: util.Pointer(matcher.Type == labels.MatchRegexp || matcher.Type == labels.MatchNotRegexp),
+		})
+	}
+	createdBy := v1.CreatedBy.Value()
+	if createdBy == "" {
+		createdBy = "provisioning"
+	}
+	return models.RecurringSilence{
+		OrgID:     orgID,
+		UID:       uid,
+		Matchers:  matchers,
+		Comment:   v1.Comment.Value(),
+		CreatedBy: createdBy,
+		Schedule: models.RecurringSilenceSchedule{
+			Cron:          v1.Schedule.Cron,
+			Duration:      time.Duration(v1.Schedule.Duration),
+			TimeIntervals: v1.Schedule.TimeIntervals,
+			Timezone:      v1.Schedule.Timezone,
+		},
+	}, nil
+}
+
+type DeleteRecurringSilenceV1 struct {
+	OrgID values.Int64Value  `json:"orgId" yaml:"orgId"`
+	UID   values.StringValue `json:"uid" yaml:"uid"`
+}
+
+func (v1 *DeleteRecurringSilenceV1) mapToModel() (DeleteRecurringSilence, error) {
+	uid := strings.TrimSpace(v1.UID.Value())
+	if uid == "" {
+		return DeleteRecurringSilence{}, errors.New("delete recurring silence missing uid")
+	}
+	orgID := v1.OrgID.Value()
+	if orgID < 1 {
+		orgID = 1
+	}
+	return DeleteRecurringSilence{
+		OrgID: orgID,
+		UID:   uid,
+	}, nil
+}
+
+type DeleteRecurringSilence struct {
+	OrgID int64
+	UID   string
+}
@@ -25,12 +25,14 @@ func NewAlertRuleProvisioner(
 	logger log.Logger,
 	folderService folder.Service,
 	dashboardProvService dashboards.DashboardProvisioningService,
-	ruleService provisioning.AlertRuleService) AlertRuleProvisioner {
+	ruleService provisioning.AlertRuleService,
+	templateService provisioning.AlertRuleTemplateService) AlertRuleProvisioner {
 	return &defaultAlertRuleProvisioner{
 		logger:               logger,
 		folderService:        folderService,
 		dashboardProvService: dashboardProvService,
 		ruleService:          ruleService,
+		templateService:      templateService,
 	}
 }
 
@@ -39,6 +41,7 @@ type defaultAlertRuleProvisioner struct {
 	folderService        folder.Service
 	dashboardProvService dashboards.DashboardProvisioningService
 	ruleService          provisioning.AlertRuleService
+	templateService      provisioning.AlertRuleTemplateService
 }
 
 func (prov *defaultAlertRuleProvisioner) Provision(ctx context.Context,
@@ -76,10 +79,46 @@ func (prov *defaultAlertRuleProvisioner) Provision(ctx context.Context,
 				return err
 			}
 		}
+		for _, template := range file.RuleTemplates {
+			err := prov.provisionTemplate(ctx, template)
+			if err != nil {
+				return err
+			}
+		}
+		for _, deleteTemplate := range file.DeleteRuleTemplates {
+			err := prov.templateService.DeleteTemplate(ctx, provisionerUser(deleteTemplate.OrgID), deleteTemplate.UID, alert_models.ProvenanceFile)
+			if err != nil && !errors.Is(err, alert_models.ErrAlertRuleTemplateNotFound) {
+				return err
+			}
+		}
 	}
 	return nil
 }
 
+func (prov *defaultAlertRuleProvisioner) provisionTemplate(
+	ctx context.Context,
+	template alert_models.AlertRuleTemplateWithFolderFullpath) error {
+	ctx, u := identity.WithServiceIdentity(ctx, template.OrgID)
+	folderUID, err := prov.getOrCreateFolderFullpath(ctx, template.FolderFullpath, template.OrgID)
+	if err != nil {
+		prov.logger.Error("failed to get or create folder", "folder", template.FolderFullpath, "org", template.OrgID, "err", err)
+		return err
+	}
+	template.NamespaceUID = folderUID
+	prov.logger.Debug("provisioning alert rule template", "uid", template.UID, "org", template.OrgID, "folderUID", folderUID, "group", template.RuleGroup)
+	_, _, err = prov.templateService.GetTemplate(ctx, u, template.UID)
+	if err != nil && !errors.Is(err, alert_models.ErrAlertRuleTemplateNotFound) {
+		return err
+	} else if err != nil {
+		prov.logger.Debug("creating rule template", "uid", template.UID, "org", template.OrgID)
+		_, err = prov.templateService.CreateTemplate(ctx, u, *template.AlertRuleTemplate, alert_models.ProvenanceFile)
+	} else {
+		prov.logger.Debug("updating rule template", "uid", template.UID, "org", template.OrgID)
+		_, err = prov.templateService.UpdateTemplate(ctx, u, *template.AlertRuleTemplate, alert_models.ProvenanceFile)
+	}
+	return err
+}
+
 func (prov *defaultAlertRuleProvisioner) provisionRule(
 	ctx context.Context,
 	user identity.Requester,
@@ -168,6 +207,10 @@ var provisionerUser = func(orgID int64) identity.Requester {
 			{Action: dashboards.ActionFoldersRead, Scope: dashboards.ScopeFoldersAll},
 			{Action: accesscontrol.ActionAlertingProvisioningReadSecrets, Scope: dashboards.ScopeFoldersAll},
 			{Action: accesscontrol.ActionAlertingProvisioningWrite, Scope: dashboards.ScopeFoldersAll},
+			// Recurring silences are authorized like the silences that they create.
+			{Action: accesscontrol.ActionAlertingInstanceRead},
+			{Action: accesscontrol.ActionAlertingInstanceCreate},
+			{Action: accesscontrol.ActionAlertingInstanceUpdate},
 		},
 	)
 }
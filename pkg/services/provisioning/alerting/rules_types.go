@@ -87,15 +87,20 @@ func withFallback(value, fallback string) *string {
 }
 
 func (rule *AlertRuleV1) mapToModel(orgID int64) (models.AlertRule, error) {
+	if rule.Title.Value() != "" && rule.UID.Value() == "" {
+		return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: no UID set", rule.Title.Value())
+	}
+	return rule.mapSpecToModel(orgID)
+}
+
+// mapSpecToModel maps the rule without requiring the UID, which the rule of a template does not have.
+func (rule *AlertRuleV1) mapSpecToModel(orgID int64) (models.AlertRule, error) {
 	alertRule := models.AlertRule{}
 	alertRule.Title = rule.Title.Value()
 	if alertRule.Title == "" {
 		return models.AlertRule{}, fmt.Errorf("rule has no title set")
 	}
 	alertRule.UID = rule.UID.Value()
-	if alertRule.UID == "" {
-		return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: no UID set", alertRule.Title)
-	}
 	alertRule.OrgID = orgID
 
 	duration := model.Duration(0)
@@ -165,6 +170,68 @@ func (rule *AlertRuleV1) mapToModel(orgID int64) (models.AlertRule, error) {
 	return alertRule, nil
 }
 
+type AlertRuleTemplateV1 struct {
+	OrgID      values.Int64Value             `json:"orgId" yaml:"orgId"`
+	UID        values.StringValue            `json:"uid" yaml:"uid"`
+	Folder     values.StringValue            `json:"folder" yaml:"folder"`
+	Group      values.StringValue            `json:"group" yaml:"group"`
+	Interval   values.StringValue            `json:"interval" yaml:"interval"`
+	Parameters []values.StringValue          `json:"parameters" yaml:"parameters"`
+	Rule       AlertRuleV1                   `json:"rule" yaml:"rule"`
+	Instances  []AlertRuleTemplateInstanceV1 `json:"instances" yaml:"instances"`
+}
+
+type AlertRuleTemplateInstanceV1 struct {
+	UID    values.StringValue    `json:"uid" yaml:"uid"`
+	Values values.StringMapValue `json:"values" yaml:"values"`
+}
+
+func (templateV1 *AlertRuleTemplateV1) MapToModel() (models.AlertRuleTemplateWithFolderFullpath, error) {
+	template := models.AlertRuleTemplateWithFolderFullpath{AlertRuleTemplate: &models.AlertRuleTemplate{}}
+	template.UID = templateV1.UID.Value()
+	if template.UID == "" {
+		return models.AlertRuleTemplateWithFolderFullpath{}, errors.New("rule template has no UID set")
+	}
+	template.OrgID = templateV1.OrgID.Value()
+	if template.OrgID < 1 {
+		template.OrgID = 1
+	}
+	template.RuleGroup = templateV1.Group.Value()
+	if strings.TrimSpace(template.RuleGroup) == "" {
+		return models.AlertRuleTemplateWithFolderFullpath{}, fmt.Errorf("rule template '%s' has no group set", template.UID)
+	}
+	template.FolderFullpath = templateV1.Folder.Value()
+	if strings.TrimSpace(template.FolderFullpath) == "" {
+		return models.AlertRuleTemplateWithFolderFullpath{}, fmt.Errorf("rule template '%s' has no folder set", template.UID)
+	}
+	if templateV1.Interval.Value() != "" {
+		interval, err := model.ParseDuration(templateV1.Interval.Value())
+		if err != nil {
+			return models.AlertRuleTemplateWithFolderFullpath{}, fmt.Errorf("rule template '%s' failed to parse interval: %w", template.UID, err)
+		}
+		template.IntervalSeconds = int64(time.Duration(interval).Seconds())
+	}
+	for _, p := range templateV1.Parameters {
+		template.Parameters = append(template.Parameters, p.Value())
+	}
+	rule, err := templateV1.Rule.mapSpecToModel(template.OrgID)
+	if err != nil {
+		return models.AlertRuleTemplateWithFolderFullpath{}, fmt.Errorf("rule template '%s' failed to parse: %w", template.UID, err)
+	}
+	template.Rule = rule
+	for idx, instanceV1 := range templateV1.Instances {
+		instance := models.AlertRuleTemplateInstance{
+			UID:    instanceV1.UID.Value(),
+			Values: instanceV1.Values.Value(),
+		}
+		if instance.UID == "" {
+			return models.AlertRuleTemplateWithFolderFullpath{}, fmt.Errorf("rule template '%s' failed to parse: instance %d has no UID set", template.UID, idx)
+		}
+		template.Instances = append(template.Instances, instance)
+	}
+	return template, nil
+}
+
 type QueryV1 struct {
 	RefID             values.StringValue       `json:"refId" yaml:"refId"`
 	QueryType         values.StringValue       `json:"queryType" yaml:"queryType"`
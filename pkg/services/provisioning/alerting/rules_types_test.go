@@ -202,6 +202,75 @@ func TestRules(t *testing.T) {
 	})
 }
 
+func TestRuleTemplate(t *testing.T) {
+	parse := func(t *testing.T, raw string) AlertRuleTemplateV1 {
+		t.Helper()
+		var template AlertRuleTemplateV1
+		require.NoError(t, yaml.Unmarshal([]byte(raw), &template))
+		return template
+	}
+	valid := `
+uid: latency
+folder: Services
+group: latency
+interval: 1m
+parameters: [service]
+rule:
+  title: High latency of $${service}
+  condition: A
+  data:
+    - refId: A
+      model:
+        expr: latency{service="${service}"}
+  annotations:
+    summary: Latency of ${service} is high
+instances:
+  - uid: latency-api
+    values:
+      service: api
+`
+
+	t.Run("a valid rule template should not error", func(t *testing.T) {
+		template := parse(t, valid)
+		mapped, err := template.MapToModel()
+		require.NoError(t, err)
+		require.Equal(t, "latency", mapped.UID)
+		require.Equal(t, int64(1), mapped.OrgID)
+		require.Equal(t, "Services", mapped.FolderFullpath)
+		require.Equal(t, "latency", mapped.RuleGroup)
+		require.Equal(t, int64(60), mapped.IntervalSeconds)
+		require.Equal(t, []string{"service"}, mapped.Parameters)
+		require.Equal(t, "High latency of ${service}", mapped.Rule.Title)
+		require.Equal(t, "Latency of ${service} is high", mapped.Rule.Annotations["summary"])
+		require.JSONEq(t, `{"expr":"latency{service=\"${service}\"}"}`, string(mapped.Rule.Data[0].Model))
+		require.Equal(t, []models.AlertRuleTemplateInstance{{UID: "latency-api", Values: map[string]string{"service": "api"}}}, mapped.Instances)
+	})
+	t.Run("a rule template without a uid should error", func(t *testing.T) {
+		template := parse(t, valid)
+		template.UID = values.StringValue{}
+		_, err := template.MapToModel()
+		require.Error(t, err)
+	})
+	t.Run("a rule template without a folder should error", func(t *testing.T) {
+		template := parse(t, valid)
+		template.Folder = values.StringValue{}
+		_, err := template.MapToModel()
+		require.Error(t, err)
+	})
+	t.Run("a rule template with an instance without a uid should error", func(t *testing.T) {
+		template := parse(t, valid)
+		template.Instances[0].UID = values.StringValue{}
+		_, err := template.MapToModel()
+		require.Error(t, err)
+	})
+	t.Run("a rule template with an invalid rule should error", func(t *testing.T) {
+		template := parse(t, valid)
+		template.Rule.Condition = values.StringValue{}
+		_, err := template.MapToModel()
+		require.Error(t, err)
+	})
+}
+
 func TestNotificationsSettingsV1MapToModel(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -18,6 +18,8 @@ type AlertingFile struct {
 	Filename            string
 	Groups              []models.AlertRuleGroupWithFolderFullpath
 	DeleteRules         []RuleDelete
+	RuleTemplates       []models.AlertRuleTemplateWithFolderFullpath
+	DeleteRuleTemplates []RuleDelete
 	ContactPoints       []ContactPoint
 	DeleteContactPoints []DeleteContactPoint
 	Policies            []NotificiationPolicy
@@ -26,6 +28,12 @@ type AlertingFile struct {
 	DeleteMuteTimes     []DeleteMuteTime
 	Templates           []Template
 	DeleteTemplates     []DeleteTemplate
+
+	EscalationPolicies       []EscalationPolicy
+	DeleteEscalationPolicies []DeleteEscalationPolicy
+
+	RecurringSilences       []models.RecurringSilence
+	DeleteRecurringSilences []DeleteRecurringSilence
 }
 
 type AlertingFileV1 struct {
@@ -33,6 +41,8 @@ type AlertingFileV1 struct {
 	Filename            string
 	Groups              []AlertRuleGroupV1      `json:"groups" yaml:"groups"`
 	DeleteRules         []RuleDeleteV1          `json:"deleteRules" yaml:"deleteRules"`
+	RuleTemplates       []AlertRuleTemplateV1   `json:"ruleTemplates" yaml:"ruleTemplates"`
+	DeleteRuleTemplates []RuleDeleteV1          `json:"deleteRuleTemplates" yaml:"deleteRuleTemplates"`
 	ContactPoints       []ContactPointV1        `json:"contactPoints" yaml:"contactPoints"`
 	DeleteContactPoints []DeleteContactPointV1  `json:"deleteContactPoints" yaml:"deleteContactPoints"`
 	Policies            []NotificiationPolicyV1 `json:"policies" yaml:"policies"`
@@ -41,6 +51,12 @@ type AlertingFileV1 struct {
 	DeleteMuteTimes     []DeleteMuteTimeV1      `json:"deleteMuteTimes" yaml:"deleteMuteTimes"`
 	Templates           []TemplateV1            `json:"templates" yaml:"templates"`
 	DeleteTemplates     []DeleteTemplateV1      `json:"deleteTemplates" yaml:"deleteTemplates"`
+
+	EscalationPolicies       []EscalationPolicyV1       `json:"escalationPolicies" yaml:"escalationPolicies"`
+	DeleteEscalationPolicies []DeleteEscalationPolicyV1 `json:"deleteEscalationPolicies" yaml:"deleteEscalationPolicies"`
+
+	RecurringSilences       []RecurringSilenceV1       `json:"recurringSilences" yaml:"recurringSilences"`
+	DeleteRecurringSilences []DeleteRecurringSilenceV1 `json:"deleteRecurringSilences" yaml:"deleteRecurringSilences"`
 }
 
 func (fileV1 *AlertingFileV1) MapToModel() (AlertingFile, error) {
@@ -61,6 +77,12 @@ func (fileV1 *AlertingFileV1) MapToModel() (AlertingFile, error) {
 	if err := fileV1.mapTemplates(&alertingFile); err != nil {
 		return AlertingFile{}, fmt.Errorf("failure parsing templates: %w", err)
 	}
+	if err := fileV1.mapEscalationPolicies(&alertingFile); err != nil {
+		return AlertingFile{}, fmt.Errorf("failure parsing escalation policies: %w", err)
+	}
+	if err := fileV1.mapRecurringSilences(&alertingFile); err != nil {
+		return AlertingFile{}, fmt.Errorf("failure parsing recurring silences: %w", err)
+	}
 	return alertingFile, nil
 }
 
@@ -92,6 +114,38 @@ func (fileV1 *AlertingFileV1) mapMuteTimes(alertingFile *AlertingFile) error {
 	return nil
 }
 
+func (fileV1 *AlertingFileV1) mapEscalationPolicies(alertingFile *AlertingFile) error {
+	for _, epV1 := range fileV1.EscalationPolicies {
+		alertingFile.EscalationPolicies = append(alertingFile.EscalationPolicies, epV1.mapToModel())
+	}
+	for _, deleteV1 := range fileV1.DeleteEscalationPolicies {
+		delReq, err := deleteV1.mapToModel()
+		if err != nil {
+			return err
+		}
+		alertingFile.DeleteEscalationPolicies = append(alertingFile.DeleteEscalationPolicies, delReq)
+	}
+	return nil
+}
+
+func (fileV1 *AlertingFileV1) mapRecurringSilences(alertingFile *AlertingFile) error {
+	for _, rsV1 := range fileV1.RecurringSilences {
+		silence, err := rsV1.mapToModel()
+		if err != nil {
+			return err
+		}
+		alertingFile.RecurringSilences = append(alertingFile.RecurringSilences, silence)
+	}
+	for _, deleteV1 := range fileV1.DeleteRecurringSilences {
+		delReq, err := deleteV1.mapToModel()
+		if err != nil {
+			return err
+		}
+		alertingFile.DeleteRecurringSilences = append(alertingFile.DeleteRecurringSilences, delReq)
+	}
+	return nil
+}
+
 func (fileV1 *AlertingFileV1) mapPolicies(alertingFile *AlertingFile) error {
 	for _, npV1 := range fileV1.Policies {
 		np, err := npV1.mapToModel()
@@ -139,5 +193,22 @@ func (fileV1 *AlertingFileV1) mapRules(alertingFile *AlertingFile) error {
 		}
 		alertingFile.DeleteRules = append(alertingFile.DeleteRules, ruleDelete)
 	}
+	for _, templateV1 := range fileV1.RuleTemplates {
+		template, err := templateV1.MapToModel()
+		if err != nil {
+			return err
+		}
+		alertingFile.RuleTemplates = append(alertingFile.RuleTemplates, template)
+	}
+	for _, templateDeleteV1 := range fileV1.DeleteRuleTemplates {
+		orgID := templateDeleteV1.OrgID.Value()
+		if orgID < 1 {
+			orgID = 1
+		}
+		alertingFile.DeleteRuleTemplates = append(alertingFile.DeleteRuleTemplates, RuleDelete{
+			UID:   templateDeleteV1.UID.Value(),
+			OrgID: orgID,
+		})
+	}
 	return nil
 }
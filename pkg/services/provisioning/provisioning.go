@@ -300,6 +300,7 @@ func (ps *ProvisioningServiceImpl) ProvisionAlerting(ctx context.Context) error
 		notifier.NewCachedNotificationSettingsValidationService(ps.alertingStore),
 		alertingauthz.NewRuleService(ps.ac),
 	)
+	ruleTemplateService := provisioning.NewAlertRuleTemplateService(ps.alertingStore, ruleService, ps.alertingStore, ps.SQLStore, ps.log)
 	configStore := legacy_storage.NewAlertmanagerConfigStore(ps.alertingStore)
 	receiverSvc := notifier.NewReceiverService(
 		alertingauthz.NewReceiverAccess[*ngmodels.Receiver](ps.ac, true),
@@ -317,16 +318,22 @@ func (ps *ProvisioningServiceImpl) ProvisionAlerting(ctx context.Context) error
 	notificationPolicyService := provisioning.NewNotificationPolicyService(configStore,
 		ps.alertingStore, ps.SQLStore, ps.Cfg.UnifiedAlerting, ps.log)
 	mutetimingsService := provisioning.NewMuteTimingService(configStore, ps.alertingStore, ps.alertingStore, ps.log, ps.alertingStore)
+	escalationPolicyService := provisioning.NewEscalationPolicyService(configStore, ps.alertingStore, ps.alertingStore, ps.log)
 	templateService := provisioning.NewTemplateService(configStore, ps.alertingStore, ps.alertingStore, ps.log)
+	recurringSilenceService := provisioning.NewRecurringSilenceService(ps.alertingStore,
+		alertingauthz.NewSilenceService(ps.ac, ps.alertingStore), ps.alertingStore, ps.SQLStore, ps.log)
 	cfg := prov_alerting.ProvisionerConfig{
 		Path:                       alertingPath,
 		RuleService:                *ruleService,
+		RuleTemplateService:        *ruleTemplateService,
 		FolderService:              ps.folderService,
 		DashboardProvService:       ps.dashboardProvisioningService,
 		ContactPointService:        *contactPointService,
 		NotificiationPolicyService: *notificationPolicyService,
 		MuteTimingService:          *mutetimingsService,
+		EscalationPolicyService:    *escalationPolicyService,
 		TemplateService:            *templateService,
+		RecurringSilenceService:    *recurringSilenceService,
 	}
 	return ps.provisionAlerting(ctx, cfg)
 }
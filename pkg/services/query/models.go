@@ -21,6 +21,8 @@ type parsedRequest struct {
 	hasExpression bool
 	parsedQueries map[string][]parsedQuery
 	dsTypes       map[string]bool
+	// debug asks the expression service to return a trace of the pipeline.
+	debug bool
 }
 
 func (pr parsedRequest) getFlattenedQueries() []parsedQuery {
@@ -203,6 +203,7 @@ func buildErrorResponses(err error, queries []*simplejson.Json) splitResponse {
 func (s *ServiceImpl) handleExpressions(ctx context.Context, user identity.Requester, parsedReq *parsedRequest) (*backend.QueryDataResponse, error) {
 	exprReq := expr.Request{
 		Queries: []expr.Query{},
+		Debug:   parsedReq.debug,
 	}
 
 	if user != nil { // for passthrough authentication, SSE does not authenticate
@@ -283,6 +284,7 @@ func (s *ServiceImpl) parseMetricRequest(ctx context.Context, user identity.Requ
 		hasExpression: false,
 		parsedQueries: make(map[string][]parsedQuery),
 		dsTypes:       make(map[string]bool),
+		debug:         reqDTO.Debug,
 	}
 
 	// Parse the queries and store them by datasource
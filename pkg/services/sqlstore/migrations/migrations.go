@@ -155,4 +155,16 @@ func (oss *OSSMigrations) AddMigration(mg *Migrator) {
 	ualert.DropTitleUniqueIndexMigration(mg)
 
 	ualert.AddStateFiredAtColumn(mg)
+
+	ualert.AddAlertRuleTemplateTable(mg)
+
+	ualert.AddStateAcknowledgementColumns(mg)
+
+	ualert.AddStateHistoryTable(mg)
+
+	ualert.AddRecurringSilenceTables(mg)
+
+	ualert.AddNotificationDeliveryTables(mg)
+
+	ualert.AddAlertRuleDependencies(mg)
 }
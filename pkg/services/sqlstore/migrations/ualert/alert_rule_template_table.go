@@ -0,0 +1,32 @@
+package ualert
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+// AddAlertRuleTemplateTable adds a table to store alert rule templates.
+func AddAlertRuleTemplateTable(mg *migrator.Migrator) {
+	alertRuleTemplate := migrator.Table{
+		Name: "alert_rule_template",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			{Name: "namespace_uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			{Name: "rule_group", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "interval_seconds", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "parameters", Type: migrator.DB_Text, Nullable: false},
+			{Name: "rule", Type: migrator.DB_MediumText, Nullable: false},
+			{Name: "instances", Type: migrator.DB_MediumText, Nullable: false},
+			{Name: "version", Type: migrator.DB_Int, Nullable: false},
+			{Name: "updated", Type: migrator.DB_DateTime, Nullable: false},
+			{Name: "updated_by", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: true},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "uid"}, Type: migrator.UniqueIndex},
+			{Cols: []string{"org_id", "namespace_uid", "rule_group"}, Type: migrator.UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create alert_rule_template table", migrator.NewAddTableMigration(alertRuleTemplate))
+	mg.AddMigration("add unique index in alert_rule_template on org_id and uid columns", migrator.NewAddIndexMigration(alertRuleTemplate, alertRuleTemplate.Indices[0]))
+	mg.AddMigration("add unique index in alert_rule_template on org_id, namespace_uid and rule_group columns", migrator.NewAddIndexMigration(alertRuleTemplate, alertRuleTemplate.Indices[1]))
+}
@@ -0,0 +1,59 @@
+package ualert
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+// AddNotificationDeliveryTables adds tables to store the delivery log of the notifications of the Grafana Alertmanager.
+func AddNotificationDeliveryTables(mg *migrator.Migrator) {
+	delivery := migrator.Table{
+		Name: "alert_notification_delivery",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "receiver", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "integration", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "integration_index", Type: migrator.DB_Int, Nullable: false},
+			{Name: "group_key", Type: migrator.DB_Text, Nullable: false},
+			{Name: "attempt", Type: migrator.DB_Int, Nullable: false},
+			{Name: "status", Type: migrator.DB_NVarchar, Length: 32, Nullable: false},
+			{Name: "error", Type: migrator.DB_Text, Nullable: true},
+			{Name: "duration_ms", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "epoch", Type: migrator.DB_BigInt, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "epoch"}},
+			{Cols: []string{"org_id", "receiver", "epoch"}},
+			{Cols: []string{"epoch"}},
+		},
+	}
+
+	mg.AddMigration("create alert_notification_delivery table", migrator.NewAddTableMigration(delivery))
+	mg.AddMigration("add index in alert_notification_delivery on org_id and epoch columns", migrator.NewAddIndexMigration(delivery, delivery.Indices[0]))
+	mg.AddMigration("add index in alert_notification_delivery on org_id, receiver and epoch columns", migrator.NewAddIndexMigration(delivery, delivery.Indices[1]))
+	mg.AddMigration("add index in alert_notification_delivery on epoch column", migrator.NewAddIndexMigration(delivery, delivery.Indices[2]))
+
+	deliveryAlert := migrator.Table{
+		Name: "alert_notification_delivery_alert",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "delivery_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "rule_uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			{Name: "fingerprint", Type: migrator.DB_NVarchar, Length: 16, Nullable: false},
+			{Name: "status", Type: migrator.DB_NVarchar, Length: 32, Nullable: false},
+			{Name: "starts_at", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "epoch", Type: migrator.DB_BigInt, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"delivery_id"}},
+			{Cols: []string{"org_id", "rule_uid", "epoch"}},
+			{Cols: []string{"org_id", "fingerprint", "epoch"}},
+			{Cols: []string{"epoch"}},
+		},
+	}
+
+	mg.AddMigration("create alert_notification_delivery_alert table", migrator.NewAddTableMigration(deliveryAlert))
+	mg.AddMigration("add index in alert_notification_delivery_alert on delivery_id column", migrator.NewAddIndexMigration(deliveryAlert, deliveryAlert.Indices[0]))
+	mg.AddMigration("add index in alert_notification_delivery_alert on org_id, rule_uid and epoch columns", migrator.NewAddIndexMigration(deliveryAlert, deliveryAlert.Indices[1]))
+	mg.AddMigration("add index in alert_notification_delivery_alert on org_id, fingerprint and epoch columns", migrator.NewAddIndexMigration(deliveryAlert, deliveryAlert.Indices[2]))
+	mg.AddMigration("add index in alert_notification_delivery_alert on epoch column", migrator.NewAddIndexMigration(deliveryAlert, deliveryAlert.Indices[3]))
+}
@@ -0,0 +1,47 @@
+package ualert
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+// AddRecurringSilenceTables adds tables to store recurring silences and the state of their materialization.
+func AddRecurringSilenceTables(mg *migrator.Migrator) {
+	recurringSilence := migrator.Table{
+		Name: "alert_recurring_silence",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			{Name: "matchers", Type: migrator.DB_Text, Nullable: false},
+			{Name: "comment", Type: migrator.DB_Text, Nullable: false},
+			{Name: "created_by", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "schedule", Type: migrator.DB_Text, Nullable: false},
+			{Name: "version", Type: migrator.DB_Int, Nullable: false},
+			{Name: "updated", Type: migrator.DB_DateTime, Nullable: false},
+			{Name: "updated_by", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: true},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "uid"}, Type: migrator.UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create alert_recurring_silence table", migrator.NewAddTableMigration(recurringSilence))
+	mg.AddMigration("add unique index in alert_recurring_silence on org_id and uid columns", migrator.NewAddIndexMigration(recurringSilence, recurringSilence.Indices[0]))
+
+	recurringSilenceState := migrator.Table{
+		Name: "alert_recurring_silence_state",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "recurring_silence_uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			{Name: "recurring_silence_version", Type: migrator.DB_Int, Nullable: false},
+			{Name: "materialized_until", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "silences", Type: migrator.DB_Text, Nullable: false},
+			{Name: "version", Type: migrator.DB_Int, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "recurring_silence_uid"}, Type: migrator.UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create alert_recurring_silence_state table", migrator.NewAddTableMigration(recurringSilenceState))
+	mg.AddMigration("add unique index in alert_recurring_silence_state on org_id and recurring_silence_uid columns", migrator.NewAddIndexMigration(recurringSilenceState, recurringSilenceState.Indices[0]))
+}
@@ -0,0 +1,38 @@
+package ualert
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+// AddStateAcknowledgementColumns adds columns to alert_instance to represent the acknowledgement of the instance.
+func AddStateAcknowledgementColumns(mg *migrator.Migrator) {
+	mg.AddMigration("add acknowledged_by column to alert_instance table", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_instance"}, &migrator.Column{
+		Name:     "acknowledged_by",
+		Type:     migrator.DB_NVarchar,
+		Length:   190,
+		Nullable: true,
+	}))
+
+	mg.AddMigration("add acknowledged_at column to alert_instance table", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_instance"}, &migrator.Column{
+		Name:     "acknowledged_at",
+		Type:     migrator.DB_BigInt, // BigInt, to match existing time fields.
+		Nullable: true,
+	}))
+
+	mg.AddMigration("add acknowledgement_comment column to alert_instance table", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_instance"}, &migrator.Column{
+		Name:     "acknowledgement_comment",
+		Type:     migrator.DB_Text,
+		Nullable: true,
+	}))
+
+	mg.AddMigration("add acknowledgement_expires_at column to alert_instance table", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_instance"}, &migrator.Column{
+		Name:     "acknowledgement_expires_at",
+		Type:     migrator.DB_BigInt,
+		Nullable: true,
+	}))
+
+	mg.AddMigration("add acknowledgement_silence_id column to alert_instance table", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_instance"}, &migrator.Column{
+		Name:     "acknowledgement_silence_id",
+		Type:     migrator.DB_NVarchar,
+		Length:   40,
+		Nullable: true,
+	}))
+}
@@ -434,6 +434,15 @@ type Cfg struct {
 	// SQLExpressionTimeoutSeconds is the duration a SQL expression will run before timing out
 	SQLExpressionTimeout time.Duration
 
+	// SQLExpressionInputRowLimit is the maximum number of rows (across all frames) that can be accepted by a SQL expression.
+	SQLExpressionInputRowLimit int64
+
+	// SQLExpressionOutputRowLimit is the maximum number of rows that a SQL expression can return before it fails.
+	SQLExpressionOutputRowLimit int64
+
+	// SQLExpressionMemoryLimit is the maximum estimated memory in bytes of the input and output of a SQL expression.
+	SQLExpressionMemoryLimit int64
+
 	ImageUploadProvider string
 
 	// LiveMaxConnections is a maximum number of WebSocket connections to
@@ -808,6 +817,9 @@ func (cfg *Cfg) readExpressionsSettings() {
 	cfg.SQLExpressionCellLimit = expressions.Key("sql_expression_cell_limit").MustInt64(100000)
 	cfg.SQLExpressionOutputCellLimit = expressions.Key("sql_expression_output_cell_limit").MustInt64(100000)
 	cfg.SQLExpressionTimeout = expressions.Key("sql_expression_timeout").MustDuration(time.Second * 10)
+	cfg.SQLExpressionInputRowLimit = expressions.Key("sql_expression_input_row_limit").MustInt64(0)
+	cfg.SQLExpressionOutputRowLimit = expressions.Key("sql_expression_output_row_limit").MustInt64(0)
+	cfg.SQLExpressionMemoryLimit = expressions.Key("sql_expression_memory_limit").MustInt64(0)
 }
 
 type AnnotationCleanupSettings struct {
@@ -23,6 +23,7 @@ const (
 	alertmanagerDefaultPushPullInterval   = alertingCluster.DefaultPushPullInterval
 	alertmanagerDefaultConfigPollInterval = time.Minute
 	alertmanagerRedisDefaultMaxConns      = 5
+	alertmanagerDefaultShardHandOffDelay  = 30 * time.Second
 	// To start, the alertmanager needs at least one route defined.
 	// TODO: we should move this to Grafana settings and define this as the default.
 	alertmanagerDefaultConfiguration = `{
@@ -57,16 +58,25 @@ const (
 	screenshotsMaxCaptureTimeout            = 30 * time.Second
 	screenshotsDefaultMaxConcurrent         = 5
 	screenshotsDefaultUploadImageStorage    = false
+	screenshotsDefaultRenderCharts          = false
 	// SchedulerBaseInterval base interval of the scheduler. Controls how often the scheduler fetches database for new changes as well as schedules evaluation of a rule
 	// changing this value is discouraged because this could cause existing alert definition
 	// with intervals that are not exactly divided by this number not to be evaluated
 	SchedulerBaseInterval = 10 * time.Second
 	// DefaultRuleEvaluationInterval indicates a default interval of for how long a rule should be evaluated to change state from Pending to Alerting
-	DefaultRuleEvaluationInterval  = SchedulerBaseInterval * 6 // == 60 seconds
-	stateHistoryDefaultEnabled     = true
-	lokiDefaultMaxQueryLength      = 721 * time.Hour // 30d1h, matches the default value in Loki
-	defaultRecordingRequestTimeout = 10 * time.Second
-	lokiDefaultMaxQuerySize        = 65536 // 64kb
+	DefaultRuleEvaluationInterval   = SchedulerBaseInterval * 6 // == 60 seconds
+	stateHistoryDefaultEnabled      = true
+	lokiDefaultMaxQueryLength       = 721 * time.Hour // 30d1h, matches the default value in Loki
+	defaultRecordingRequestTimeout  = 10 * time.Second
+	lokiDefaultMaxQuerySize         = 65536 // 64kb
+	defaultStateSeriesInterval      = time.Minute
+	sqlHistoryDefaultBatchSize      = 1000
+	sqlHistoryDefaultFlushInterval  = 5 * time.Second
+	sqlHistoryDefaultRetention      = 30 * 24 * time.Hour
+	recurringSilencesSyncInterval   = time.Minute
+	recurringSilencesLookahead      = 24 * time.Hour
+	deliveryLogDefaultFlushInterval = 10 * time.Second
+	deliveryLogDefaultRetention     = 7 * 24 * time.Hour
 )
 
 type UnifiedAlertingSettings struct {
@@ -92,6 +102,8 @@ type UnifiedAlertingSettings struct {
 	HARedisMaxConns                 int
 	HARedisTLSEnabled               bool
 	HARedisTLSConfig                dstls.ClientConfig
+	HAShardRuleEvaluation           bool
+	HAShardHandOffDelay             time.Duration
 	InitializationTimeout           time.Duration
 	MaxAttempts                     int64
 	MinInterval                     time.Duration
@@ -113,6 +125,9 @@ type UnifiedAlertingSettings struct {
 	StateHistory                  UnifiedAlertingStateHistorySettings
 	RemoteAlertmanager            RemoteAlertmanagerSettings
 	RecordingRules                RecordingRuleSettings
+	StateSeries                   UnifiedAlertingStateSeriesSettings
+	RecurringSilences             UnifiedAlertingRecurringSilencesSettings
+	NotificationDeliveryLog       UnifiedAlertingNotificationDeliveryLogSettings
 	PrometheusConversion          UnifiedAlertingPrometheusConversionSettings
 
 	// MaxStateSaveConcurrency controls the number of goroutines (per rule) that can save alert state in parallel.
@@ -146,6 +161,34 @@ type RecordingRuleSettings struct {
 	DefaultDatasourceUID string
 }
 
+// UnifiedAlertingStateSeriesSettings configures the remote write of the ALERTS and ALERTS_FOR_STATE series
+// of Grafana-managed alert rules. The series are written to the remote write target of recording rules.
+type UnifiedAlertingStateSeriesSettings struct {
+	Enabled  bool
+	Interval time.Duration
+	// DatasourceUID is the data source to write to when recording rules write to data sources.
+	// If empty, the default data source of recording rules is used.
+	DatasourceUID string
+}
+
+// UnifiedAlertingRecurringSilencesSettings configures how the silences of recurring silences are created.
+type UnifiedAlertingRecurringSilencesSettings struct {
+	// SyncInterval is how often the silences of recurring silences are created, replaced and expired.
+	SyncInterval time.Duration
+	// Lookahead is how far ahead of time the silences of recurring silences are created.
+	Lookahead time.Duration
+}
+
+// UnifiedAlertingNotificationDeliveryLogSettings configures the log of the attempts of the Grafana Alertmanager
+// to send notifications.
+type UnifiedAlertingNotificationDeliveryLogSettings struct {
+	Enabled bool
+	// FlushInterval is how often the recorded attempts are written to the database.
+	FlushInterval time.Duration
+	// Retention is how long the deliveries are kept. 0 keeps them forever.
+	Retention time.Duration
+}
+
 // RemoteAlertmanagerSettings contains the configuration needed
 // to disable the internal Alertmanager and use an external one instead.
 type RemoteAlertmanagerSettings struct {
@@ -161,6 +204,7 @@ type UnifiedAlertingScreenshotSettings struct {
 	CaptureTimeout             time.Duration
 	MaxConcurrentScreenshots   int64
 	UploadExternalImageStorage bool
+	RenderCharts               bool
 }
 
 type UnifiedAlertingReservedLabelSettings struct {
@@ -189,6 +233,12 @@ type UnifiedAlertingStateHistorySettings struct {
 	MultiPrimary          string
 	MultiSecondaries      []string
 	ExternalLabels        map[string]string
+	// SQLBatchSize is the maximum number of state transitions that the SQL backend buffers before writing them.
+	SQLBatchSize int
+	// SQLFlushInterval is how often the SQL backend writes the buffered state transitions.
+	SQLFlushInterval time.Duration
+	// SQLRetention is how long the SQL backend keeps state history. 0 keeps it forever.
+	SQLRetention time.Duration
 }
 
 // IsEnabled returns true if UnifiedAlertingSettings.Enabled is either nil or true.
@@ -302,6 +352,11 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 	uaCfg.HARedisTLSConfig.InsecureSkipVerify = ua.Key("ha_redis_tls_insecure_skip_verify").MustBool(false)
 	uaCfg.HARedisTLSConfig.CipherSuites = ua.Key("ha_redis_tls_cipher_suites").MustString("")
 	uaCfg.HARedisTLSConfig.MinVersion = ua.Key("ha_redis_tls_min_version").MustString("")
+	uaCfg.HAShardRuleEvaluation = ua.Key("ha_shard_rule_evaluation").MustBool(false)
+	uaCfg.HAShardHandOffDelay, err = gtime.ParseDuration(valueAsString(ua, "ha_shard_hand_off_delay", (alertmanagerDefaultShardHandOffDelay).String()))
+	if err != nil {
+		return err
+	}
 
 	// TODO load from ini file
 	uaCfg.DefaultConfiguration = alertmanagerDefaultConfiguration
@@ -418,6 +473,7 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 
 	uaCfgScreenshots.MaxConcurrentScreenshots = screenshots.Key("max_concurrent_screenshots").MustInt64(screenshotsDefaultMaxConcurrent)
 	uaCfgScreenshots.UploadExternalImageStorage = screenshots.Key("upload_external_image_storage").MustBool(screenshotsDefaultUploadImageStorage)
+	uaCfgScreenshots.RenderCharts = screenshots.Key("render_charts").MustBool(screenshotsDefaultRenderCharts)
 	uaCfg.Screenshots = uaCfgScreenshots
 
 	reservedLabels := iniFile.Section("unified_alerting.reserved_labels")
@@ -445,6 +501,21 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 		MultiPrimary:          stateHistory.Key("primary").MustString(""),
 		MultiSecondaries:      splitTrim(stateHistory.Key("secondaries").MustString(""), ","),
 		ExternalLabels:        stateHistoryLabels.KeysHash(),
+		SQLBatchSize:          stateHistory.Key("sql_batch_size").MustInt(sqlHistoryDefaultBatchSize),
+		SQLFlushInterval:      stateHistory.Key("sql_flush_interval").MustDuration(sqlHistoryDefaultFlushInterval),
+	}
+	uaCfgStateHistory.SQLRetention, err = gtime.ParseDuration(valueAsString(stateHistory, "sql_retention", sqlHistoryDefaultRetention.String()))
+	if err != nil {
+		return err
+	}
+	if uaCfgStateHistory.SQLBatchSize <= 0 {
+		return fmt.Errorf("setting 'sql_batch_size' in section 'unified_alerting.state_history' is invalid, only positive numbers are allowed")
+	}
+	if uaCfgStateHistory.SQLFlushInterval <= 0 {
+		return fmt.Errorf("setting 'sql_flush_interval' in section 'unified_alerting.state_history' is invalid, only positive durations are allowed")
+	}
+	if uaCfgStateHistory.SQLRetention < 0 {
+		return fmt.Errorf("setting 'sql_retention' in section 'unified_alerting.state_history' is invalid, negative durations are not allowed")
 	}
 	uaCfg.StateHistory = uaCfgStateHistory
 
@@ -472,6 +543,44 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 
 	uaCfg.RecordingRules = uaCfgRecordingRules
 
+	stateSeries := iniFile.Section("unified_alerting.state_series")
+	uaCfg.StateSeries = UnifiedAlertingStateSeriesSettings{
+		Enabled:       stateSeries.Key("enabled").MustBool(false),
+		Interval:      stateSeries.Key("interval").MustDuration(defaultStateSeriesInterval),
+		DatasourceUID: stateSeries.Key("datasource_uid").MustString(""),
+	}
+	if uaCfg.StateSeries.Interval <= 0 {
+		return fmt.Errorf("setting 'interval' in section 'unified_alerting.state_series' is invalid, only positive durations are allowed")
+	}
+
+	recurringSilences := iniFile.Section("unified_alerting.recurring_silences")
+	uaCfg.RecurringSilences = UnifiedAlertingRecurringSilencesSettings{
+		SyncInterval: recurringSilences.Key("sync_interval").MustDuration(recurringSilencesSyncInterval),
+		Lookahead:    recurringSilences.Key("lookahead").MustDuration(recurringSilencesLookahead),
+	}
+	if uaCfg.RecurringSilences.SyncInterval <= 0 {
+		return fmt.Errorf("setting 'sync_interval' in section 'unified_alerting.recurring_silences' is invalid, only positive durations are allowed")
+	}
+	if uaCfg.RecurringSilences.Lookahead < uaCfg.RecurringSilences.SyncInterval {
+		return fmt.Errorf("setting 'lookahead' in section 'unified_alerting.recurring_silences' is invalid, it must be at least 'sync_interval'")
+	}
+
+	deliveryLog := iniFile.Section("unified_alerting.notification_delivery_log")
+	uaCfg.NotificationDeliveryLog = UnifiedAlertingNotificationDeliveryLogSettings{
+		Enabled:       deliveryLog.Key("enabled").MustBool(false),
+		FlushInterval: deliveryLog.Key("flush_interval").MustDuration(deliveryLogDefaultFlushInterval),
+	}
+	uaCfg.NotificationDeliveryLog.Retention, err = gtime.ParseDuration(valueAsString(deliveryLog, "retention", deliveryLogDefaultRetention.String()))
+	if err != nil {
+		return err
+	}
+	if uaCfg.NotificationDeliveryLog.FlushInterval <= 0 {
+		return fmt.Errorf("setting 'flush_interval' in section 'unified_alerting.notification_delivery_log' is invalid, only positive durations are allowed")
+	}
+	if uaCfg.NotificationDeliveryLog.Retention < 0 {
+		return fmt.Errorf("setting 'retention' in section 'unified_alerting.notification_delivery_log' is invalid, only non-negative durations are allowed")
+	}
+
 	uaCfg.MaxStateSaveConcurrency = ua.Key("max_state_save_concurrency").MustInt(1)
 
 	uaCfg.StatePeriodicSaveInterval, err = gtime.ParseDuration(valueAsString(ua, "state_periodic_save_interval", (time.Minute * 5).String()))